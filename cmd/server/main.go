@@ -15,6 +15,7 @@ import (
 	"github.com/flexprice/flexprice/internal/httpclient"
 	"github.com/flexprice/flexprice/internal/kafka"
 	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/metrics"
 	"github.com/flexprice/flexprice/internal/pdf"
 	"github.com/flexprice/flexprice/internal/postgres"
 	"github.com/flexprice/flexprice/internal/publisher"
@@ -115,6 +116,7 @@ func main() {
 
 			// Event Publisher
 			publisher.NewEventPublisher,
+			publisher.NewIngestEventPublisher,
 
 			// HTTP Client
 			httpclient.NewDefaultClient,
@@ -126,6 +128,9 @@ func main() {
 			repository.NewEventRepository,
 			repository.NewProcessedEventRepository,
 			repository.NewFeatureUsageRepository,
+			repository.NewUsageCounterRepository,
+			repository.NewEventDedupRepository,
+			repository.NewAuditLogRepository,
 			repository.NewMeterRepository,
 			repository.NewUserRepository,
 			repository.NewAuthRepository,
@@ -190,7 +195,7 @@ func main() {
 			service.NewEnvAccessService,
 			service.NewEnvironmentService,
 			service.NewMeterService,
-			service.NewEventService,
+			service.NewIngestEventService,
 			service.NewEventPostProcessingService,
 			service.NewEventConsumptionService,
 			service.NewFeatureUsageTrackingService,
@@ -242,6 +247,8 @@ func main() {
 		fx.Invoke(
 			sentry.RegisterHooks,
 			pyroscope.RegisterHooks,
+			publisher.RegisterHooks,
+			metrics.RegisterHooks,
 			startServer,
 		),
 	)
@@ -340,6 +347,7 @@ func provideHandlers(
 		AlertLogsHandler:         v1.NewAlertLogsHandler(alertLogsService, customerService, walletService, featureService, logger),
 		RBAC:                     v1.NewRBACHandler(rbacService, userService, logger),
 		CronKafkaLagMonitoring:   cron.NewKafkaLagMonitoringHandler(logger, eventService),
+		CronEventDedup:           cron.NewEventDedupHandler(logger, featureUsageTrackingService),
 	}
 }
 
@@ -4310,6 +4310,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4367,6 +4373,57 @@ const docTemplate = `{
                 }
             }
         },
+        "/events/analytics/forecast": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Forecast the usage of a customer's feature in the next period from its usage in the previous periods",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Forecast feature usage",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ForecastFeatureUsageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ForecastFeatureUsageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/events/bulk": {
             "post": {
                 "security": [
@@ -4412,6 +4469,87 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/clock-skew": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Report the delay between the timestamp and the ingestion of the events of each customer and source, flagging the producers whose clock is consistently skewed (last 24 hours by default)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get clock skew",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the ingestion time range (RFC3339) - defaults to 24 hours ago",
+                        "name": "start_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the ingestion time range (RFC3339) - defaults to now",
+                        "name": "end_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only report the producers of this customer",
+                        "name": "external_customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only report the producers of this source",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skew beyond which a producer is flagged - defaults to 300",
+                        "name": "threshold_seconds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skip the producers with fewer events - defaults to 10",
+                        "name": "min_events",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetClockSkewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -12454,6 +12592,50 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.ClockSkewItem": {
+            "type": "object",
+            "properties": {
+                "avg_skew_seconds": {
+                    "type": "number"
+                },
+                "direction": {
+                    "description": "Direction is the direction of the clock of a skewed producer",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.ClockSkewDirection"
+                        }
+                    ]
+                },
+                "event_count": {
+                    "type": "integer"
+                },
+                "external_customer_id": {
+                    "type": "string"
+                },
+                "max_skew_seconds": {
+                    "type": "number"
+                },
+                "median_skew_seconds": {
+                    "type": "number"
+                },
+                "min_skew_seconds": {
+                    "type": "number"
+                },
+                "p10_skew_seconds": {
+                    "type": "number"
+                },
+                "p90_skew_seconds": {
+                    "type": "number"
+                },
+                "skewed": {
+                    "description": "Skewed flags a producer whose events are consistently skewed beyond the threshold",
+                    "type": "boolean"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.ConnectionResponse": {
             "type": "object",
             "properties": {
@@ -13757,16 +13939,39 @@ const docTemplate = `{
                 "aggregation": {
                     "$ref": "#/definitions/meter.Aggregation"
                 },
+                "billing_mode": {
+                    "description": "BillingMode is bill by default, usage of shadow meters shows in analytics but is never billed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MeterBillingMode"
+                        }
+                    ]
+                },
                 "event_name": {
                     "type": "string",
                     "example": "api_request"
                 },
+                "event_name_match": {
+                    "description": "EventNameMatch is exact by default, with prefix the meter tracks every event whose\nname starts with event_name",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MeterEventNameMatch"
+                        }
+                    ]
+                },
                 "filters": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/meter.Filter"
                     }
                 },
+                "indexed_properties": {
+                    "description": "IndexedProperties are the event properties usage is frequently filtered or grouped by,\nthey are indexed in ClickHouse to speed up analytics once operators enable property indexes\nfor the tenant",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "name": {
                     "type": "string",
                     "example": "API Usage Meter"
@@ -15197,6 +15402,10 @@ const docTemplate = `{
         "dto.Event": {
             "type": "object",
             "properties": {
+                "correlation_id": {
+                    "description": "CorrelationID is the trace or correlation id read from the event, set on analytics event samples",
+                    "type": "string"
+                },
                 "customer_id": {
                     "type": "string"
                 },
@@ -15318,6 +15527,85 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.ForecastFeatureUsageRequest": {
+            "type": "object",
+            "required": [
+                "end_time",
+                "external_customer_id",
+                "feature_id",
+                "start_time",
+                "window_size"
+            ],
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "external_customer_id": {
+                    "type": "string"
+                },
+                "feature_id": {
+                    "type": "string"
+                },
+                "model": {
+                    "description": "Model is the forecast model, defaults to moving_average",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.UsageForecastModel"
+                        }
+                    ]
+                },
+                "periods": {
+                    "description": "Periods is the number of latest periods the model looks at, defaults to 3 for\nmoving_average and every period for linear_trend",
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "window_size": {
+                    "$ref": "#/definitions/types.WindowSize"
+                }
+            }
+        },
+        "dto.ForecastFeatureUsageResponse": {
+            "type": "object",
+            "properties": {
+                "feature_id": {
+                    "type": "string"
+                },
+                "forecast_usage": {
+                    "type": "number"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UsageForecastPoint"
+                    }
+                },
+                "model": {
+                    "$ref": "#/definitions/types.UsageForecastModel"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "window_size": {
+                    "$ref": "#/definitions/types.WindowSize"
+                }
+            }
+        },
+        "dto.GetClockSkewResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ClockSkewItem"
+                    }
+                },
+                "threshold_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
         "dto.GetCostAnalyticsRequest": {
             "type": "object",
             "properties": {
@@ -15540,9 +15828,28 @@ const docTemplate = `{
                 "external_customer_id"
             ],
             "properties": {
+                "billable_only": {
+                    "description": "BillableOnly limits the analytics to the usage that is invoiced, leaving out the usage of\nshadow meters and the usage of line items outside their active dates, so costs match invoices",
+                    "type": "boolean"
+                },
+                "calendar": {
+                    "description": "Calendar is an accounting calendar whose periods replace calendar months in the MONTH\nwindows, e.g. the periods of a 4-4-5 fiscal calendar",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.AccountingCalendar"
+                        }
+                    ]
+                },
+                "cursor": {
+                    "description": "Cursor is the next_cursor of the previous page, the first page is returned when unset",
+                    "type": "string"
+                },
                 "end_time": {
                     "type": "string"
                 },
+                "entity_type": {
+                    "$ref": "#/definitions/types.PriceEntityType"
+                },
                 "expand": {
                     "description": "allowed values: \"price\", \"meter\", \"feature\", \"subscription_line_item\",\"plan\",\"addon\"",
                     "type": "array",
@@ -15553,6 +15860,17 @@ const docTemplate = `{
                 "external_customer_id": {
                     "type": "string"
                 },
+                "external_customer_ids": {
+                    "description": "ExternalCustomerIDs is a segment of customers whose analytics are combined, only supported\nby the multi customer analytics and exclusive with external_customer_id",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "fail_on_missing_price": {
+                    "description": "FailOnMissingPrice fails the request when usage was recorded against a price that can't be\nfetched instead of reporting it with zero cost and a warning",
+                    "type": "boolean"
+                },
                 "feature_ids": {
                     "type": "array",
                     "items": {
@@ -15566,6 +15884,17 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "limit": {
+                    "description": "Limit is the number of items returned per page, sorted by feature name. All items are\nreturned when unset, it is capped at MaxAnalyticsPageSize.",
+                    "type": "integer"
+                },
+                "price_ids": {
+                    "description": "PriceIDs limits the analytics to the usage billed under these prices, e.g. to isolate the\nusage of an overridden price",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "property_filters": {
                     "description": "Property filters to filter the events by the keys in ` + "`" + `properties` + "`" + ` field of the event",
                     "type": "object",
@@ -15585,6 +15914,9 @@ const docTemplate = `{
                 "start_time": {
                     "type": "string"
                 },
+                "weight_property": {
+                    "type": "string"
+                },
                 "window_size": {
                     "$ref": "#/definitions/types.WindowSize"
                 }
@@ -15596,14 +15928,41 @@ const docTemplate = `{
                 "currency": {
                     "type": "string"
                 },
+                "has_more": {
+                    "description": "HasMore is true when there are items after the page",
+                    "type": "boolean"
+                },
                 "items": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/dto.UsageAnalyticItem"
                     }
                 },
+                "next_cursor": {
+                    "description": "NextCursor is the cursor of the next page, empty on the last page",
+                    "type": "string"
+                },
+                "page_total_cost": {
+                    "description": "PageTotalCost is the cost of the items of the page, only set when the items are paged.\nTotalCost remains the cost of all items.",
+                    "type": "number"
+                },
+                "settled_total_cost": {
+                    "description": "SettledTotalCost is TotalCost in the settlement currency, costs without a conversion rate\nare left out and flagged by a warning",
+                    "type": "number"
+                },
+                "settlement_currency": {
+                    "description": "SettlementCurrency is the currency the costs are settled in, only set when the environment\nhas a settlement currency configured",
+                    "type": "string"
+                },
                 "total_cost": {
                     "type": "number"
+                },
+                "warnings": {
+                    "description": "Warnings flags usage whose cost couldn't be fully calculated",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UsageAnalyticsWarning"
+                    }
                 }
             }
         },
@@ -15856,8 +16215,7 @@ const docTemplate = `{
         "dto.IngestEventRequest": {
             "type": "object",
             "required": [
-                "event_name",
-                "external_customer_id"
+                "event_name"
             ],
             "properties": {
                 "customer_id": {
@@ -16809,6 +17167,9 @@ const docTemplate = `{
                 "aggregation": {
                     "$ref": "#/definitions/meter.Aggregation"
                 },
+                "billing_mode": {
+                    "$ref": "#/definitions/types.MeterBillingMode"
+                },
                 "created_at": {
                     "type": "string",
                     "example": "2024-03-20T15:04:05Z"
@@ -16817,6 +17178,9 @@ const docTemplate = `{
                     "type": "string",
                     "example": "api_request"
                 },
+                "event_name_match": {
+                    "$ref": "#/definitions/types.MeterEventNameMatch"
+                },
                 "filters": {
                     "type": "array",
                     "items": {
@@ -16827,6 +17191,12 @@ const docTemplate = `{
                     "type": "string",
                     "example": "550e8400-e29b-41d4-a716-446655440000"
                 },
+                "indexed_properties": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "name": {
                     "type": "string",
                     "example": "API Usage Meter"
@@ -16885,6 +17255,10 @@ const docTemplate = `{
                 "billing_model": {
                     "$ref": "#/definitions/types.BillingModel"
                 },
+                "discount_percent": {
+                    "description": "DiscountPercent keeps the tiers of a tiered price and charges this percentage less than\ntheir output, e.g. 10 for a 10% discount, instead of redefining the price (optional)",
+                    "type": "number"
+                },
                 "price_id": {
                     "description": "PriceID references the plan price to override",
                     "type": "string"
@@ -18814,6 +19188,35 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.TierCostBreakup": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "description": "Cost is the cost of the quantity billed in the tier including its flat amount",
+                    "type": "number"
+                },
+                "flat_amount": {
+                    "description": "FlatAmount is the flat amount of the tier, if any",
+                    "type": "number"
+                },
+                "quantity": {
+                    "description": "Quantity is the quantity billed in the tier, the whole quantity for volume tiers",
+                    "type": "number"
+                },
+                "tier_index": {
+                    "description": "TierIndex is the index of the tier in the tiers of the price sorted by up_to",
+                    "type": "integer"
+                },
+                "unit_amount": {
+                    "description": "UnitAmount is the amount per unit of the tier",
+                    "type": "number"
+                },
+                "up_to": {
+                    "description": "UpTo is the inclusive upper bound of the tier, null for the last tier",
+                    "type": "integer"
+                }
+            }
+        },
         "dto.TopUpWalletRequest": {
             "type": "object",
             "required": [
@@ -19655,9 +20058,24 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "aggregation_field": {
+                    "description": "Event property the meter aggregates (only if expand includes \"aggregation_field\")",
+                    "type": "string"
+                },
+                "aggregation_field_values": {
+                    "description": "Sample of the distinct values counted by a count unique meter (only if expand includes \"aggregation_field\")",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "aggregation_type": {
                     "$ref": "#/definitions/types.AggregationType"
                 },
+                "commitment_adjusted_cost": {
+                    "description": "Cost after the subscription commitment, only if the subscription has a commitment and the range is within one of its billing periods",
+                    "type": "number"
+                },
                 "currency": {
                     "type": "string"
                 },
@@ -19679,6 +20097,13 @@ const docTemplate = `{
                 "feature_id": {
                     "type": "string"
                 },
+                "matched_filters": {
+                    "description": "Meter filters with the values of the contributing events that matched them (only if expand includes \"matched_filters\")",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/meter.Filter"
+                    }
+                },
                 "meter": {
                     "description": "Full meter object (only if expand includes \"meter\")",
                     "allOf": [
@@ -19730,9 +20155,24 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "settled_cost": {
+                    "description": "Total cost in the settlement currency (only if a settlement currency is configured)",
+                    "type": "number"
+                },
+                "settlement_rate": {
+                    "description": "Rate the total cost was converted to the settlement currency at",
+                    "type": "number"
+                },
                 "source": {
                     "type": "string"
                 },
+                "sources": {
+                    "description": "Distinct sources of the contributing events (only if expand includes \"sources\")",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "sub_line_item_id": {
                     "description": "Subscription line item ID",
                     "type": "string"
@@ -19749,6 +20189,13 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "tiers": {
+                    "description": "Usage and cost of each tier of a tiered price, adding up to total_cost (only if expand includes \"tiers\")",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TierCostBreakup"
+                    }
+                },
                 "total_cost": {
                     "type": "number"
                 },
@@ -19760,6 +20207,9 @@ const docTemplate = `{
                 },
                 "unit_plural": {
                     "type": "string"
+                },
+                "weighted_usage": {
+                    "type": "number"
                 }
             }
         },
@@ -19781,6 +20231,23 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UsageAnalyticsWarning": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/types.UsageAnalyticsWarningCode"
+                },
+                "feature_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "price_id": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.UsageBreakdownItem": {
             "type": "object",
             "properties": {
@@ -19809,6 +20276,17 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UsageForecastPoint": {
+            "type": "object",
+            "properties": {
+                "timestamp": {
+                    "type": "string"
+                },
+                "usage": {
+                    "type": "number"
+                }
+            }
+        },
         "dto.UsageResult": {
             "type": "object",
             "properties": {
@@ -20184,12 +20662,51 @@ const docTemplate = `{
                     "description": "Field is the key in $event.properties on which the aggregation is to be applied\nFor ex if the aggregation type is sum for API usage, the field could be \"duration_ms\"",
                     "type": "string"
                 },
+                "fill_gaps": {
+                    "description": "FillGaps is used only for MAX and LATEST gauges to report a point for analytics windows\nwithout events, either zero or the usage of the last window with events carried over.\nIf not provided, windows without events have no point.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MeterGapFillMode"
+                        }
+                    ]
+                },
+                "heartbeat_interval_seconds": {
+                    "description": "HeartbeatIntervalSeconds is used only for UPTIME aggregation, it is the interval the\nheartbeat events are sent at. Every interval with a heartbeat counts as active for the\nwhole interval, so usage is the active duration in seconds.",
+                    "type": "integer"
+                },
+                "max_event_value": {
+                    "description": "MaxEventValue is the highest value of the field expected for an event, it guards billing\nagainst anomalous values sent by producer bugs. Higher values are rejected or lowered to it\ndepending on the configured max event value policy. If not provided, values are not capped.",
+                    "type": "number"
+                },
+                "min_event_value": {
+                    "description": "MinEventValue is the lowest value of the field counted for an event, lower values are\nraised to it before aggregation. For ex a minimum of 60 with a field in seconds bills\ncalls shorter than a minute as one minute. If not provided, values are counted as is.",
+                    "type": "number"
+                },
                 "multiplier": {
                     "description": "Multiplier is the multiplier for the aggregation\nFor ex if the aggregation type is sum_with_multiplier for API usage, the multiplier could be 1000\nto scale up by a factor of 1000. If not provided, it will be null.",
                     "type": "number"
                 },
+                "rate_property": {
+                    "description": "RateProperty is the key in $event.properties the multiplier of sum_with_multiplier is looked\nup by in RateTable, e.g. \"model\" for per model rates. Events whose value has no rate in the\ntable fall back to the static multiplier.",
+                    "type": "string"
+                },
+                "rate_table": {
+                    "description": "RateTable holds the multiplier per value of the rate property",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "rolling_window_hours": {
+                    "description": "RollingWindowHours is used only for MAX aggregation to report the highest usage summed over\na rolling window of this many hours ending at each event, e.g. max concurrent usage in the\nlast hour. It is computed at analytics time and can't be combined with bucket_size.",
+                    "type": "integer"
+                },
                 "type": {
                     "$ref": "#/definitions/types.AggregationType"
+                },
+                "unit": {
+                    "description": "Unit is the unit the field is aggregated in. String values with a unit suffix, e.g. \"1.5GB\"\nor \"500ms\", are converted to this unit when the event is ingested. If not provided, values\nmust be plain numbers.",
+                    "type": "string"
                 }
             }
         },
@@ -20220,6 +20737,14 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "billing_mode": {
+                    "description": "BillingMode defines whether the usage of the meter is billed. Usage of shadow meters is\nrecorded and shows in analytics but is never charged.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MeterBillingMode"
+                        }
+                    ]
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -20234,6 +20759,14 @@ const docTemplate = `{
                     "description": "EventName is the unique identifier for the event that this meter is tracking\nIt is a mandatory field in the events table and hence being used as the primary matching field\nWe can have multiple meters tracking the same event but with different filters and aggregation",
                     "type": "string"
                 },
+                "event_name_match": {
+                    "description": "EventNameMatch defines how EventName is matched against the name of events. With prefix\nmatching a meter tracks a family of events, for ex \"api.users.\" tracks \"api.users.create\".",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MeterEventNameMatch"
+                        }
+                    ]
+                },
                 "filters": {
                     "description": "Filters define the criteria for the meter to be applied on the events before aggregation\nIt also defines the possible values on which later the charges will be applied",
                     "type": "array",
@@ -20245,6 +20778,13 @@ const docTemplate = `{
                     "description": "ID is the unique identifier for the meter",
                     "type": "string"
                 },
+                "indexed_properties": {
+                    "description": "IndexedProperties are the event properties usage of the meter is frequently filtered or\ngrouped by. They are indexed in ClickHouse by the apply-property-indexes command.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "name": {
                     "description": "Name is the display name of the meter",
                     "type": "string"
@@ -20396,6 +20936,10 @@ const docTemplate = `{
                     "description": "Description of the price",
                     "type": "string"
                 },
+                "discount_percent": {
+                    "description": "DiscountPercent is set on subscription override prices that keep the tiers of their parent\nprice and charge this percentage less than the tier output, e.g. 10 for a 10% discount",
+                    "type": "number"
+                },
                 "display_amount": {
                     "description": "DisplayAmount is the formatted amount with currency symbol\nFor USD: $12.50",
                     "type": "string"
@@ -20815,6 +21359,52 @@ const docTemplate = `{
                 }
             }
         },
+        "types.AccountingCalendar": {
+            "type": "object",
+            "properties": {
+                "period_weeks": {
+                    "description": "PeriodWeeks are the weeks of each period of the fiscal year, only set for custom calendars",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/types.AccountingCalendarType"
+                },
+                "year_start": {
+                    "description": "YearStart is the first day of a fiscal year, earlier and later years repeat from it",
+                    "type": "string"
+                }
+            }
+        },
+        "types.AccountingCalendarType": {
+            "type": "string",
+            "enum": [
+                "4-4-5",
+                "4-5-4",
+                "5-4-4",
+                "custom"
+            ],
+            "x-enum-comments": {
+                "AccountingCalendar445": "splits every quarter into periods of 4, 4 and 5 weeks",
+                "AccountingCalendar454": "splits every quarter into periods of 4, 5 and 4 weeks",
+                "AccountingCalendar544": "splits every quarter into periods of 5, 4 and 4 weeks",
+                "AccountingCalendarCustom": "uses the periods given in period_weeks"
+            },
+            "x-enum-descriptions": [
+                "splits every quarter into periods of 4, 4 and 5 weeks",
+                "splits every quarter into periods of 4, 5 and 4 weeks",
+                "splits every quarter into periods of 5, 4 and 4 weeks",
+                "uses the periods given in period_weeks"
+            ],
+            "x-enum-varnames": [
+                "AccountingCalendar445",
+                "AccountingCalendar454",
+                "AccountingCalendar544",
+                "AccountingCalendarCustom"
+            ]
+        },
         "types.AddonAssociationEntityType": {
             "type": "string",
             "enum": [
@@ -20921,10 +21511,16 @@ const docTemplate = `{
                 "LATEST",
                 "SUM_WITH_MULTIPLIER",
                 "MAX",
-                "WEIGHTED_SUM"
+                "WEIGHTED_SUM",
+                "COUNT_ONCE_PER_PERIOD",
+                "UPTIME",
+                "COUNTER_DELTA"
             ],
             "x-enum-comments": {
-                "AggregationSumWithMultiplier": "Sum with a multiplier - [sum(value) * multiplier]"
+                "AggregationCountOncePerPeriod": "At most one event per customer per billing period",
+                "AggregationCounterDelta": "Increase of a cumulative counter between consecutive readings of a customer",
+                "AggregationSumWithMultiplier": "Sum with a multiplier - [sum(value) * multiplier]",
+                "AggregationUptime": "Active seconds from heartbeat events, one heartbeat interval per interval with a heartbeat"
             },
             "x-enum-descriptions": [
                 "",
@@ -20934,7 +21530,10 @@ const docTemplate = `{
                 "",
                 "Sum with a multiplier - [sum(value) * multiplier]",
                 "",
-                ""
+                "",
+                "At most one event per customer per billing period",
+                "Active seconds from heartbeat events, one heartbeat interval per interval with a heartbeat",
+                "Increase of a cumulative counter between consecutive readings of a customer"
             ],
             "x-enum-varnames": [
                 "AggregationCount",
@@ -20944,7 +21543,10 @@ const docTemplate = `{
                 "AggregationLatest",
                 "AggregationSumWithMultiplier",
                 "AggregationMax",
-                "AggregationWeightedSum"
+                "AggregationWeightedSum",
+                "AggregationCountOncePerPeriod",
+                "AggregationUptime",
+                "AggregationCounterDelta"
             ]
         },
         "types.AlertCondition": {
@@ -21204,6 +21806,25 @@ const docTemplate = `{
                 "CancellationTypeEndOfPeriod"
             ]
         },
+        "types.ClockSkewDirection": {
+            "type": "string",
+            "enum": [
+                "ahead",
+                "behind"
+            ],
+            "x-enum-comments": {
+                "ClockSkewDirectionAhead": "is a producer timestamping its events in the future",
+                "ClockSkewDirectionBehind": "is a producer timestamping its events in the past"
+            },
+            "x-enum-descriptions": [
+                "is a producer timestamping its events in the future",
+                "is a producer timestamping its events in the past"
+            ],
+            "x-enum-varnames": [
+                "ClockSkewDirectionAhead",
+                "ClockSkewDirectionBehind"
+            ]
+        },
         "types.CollectionMethod": {
             "type": "string",
             "enum": [
@@ -21986,6 +22607,63 @@ const docTemplate = `{
                 "type": "string"
             }
         },
+        "types.MeterBillingMode": {
+            "type": "string",
+            "enum": [
+                "bill",
+                "shadow"
+            ],
+            "x-enum-comments": {
+                "MeterBillingModeBill": "bills the usage of the meter",
+                "MeterBillingModeShadow": "records and reports the usage of the meter without ever billing it,\nfor ex while onboarding a customer before their usage is charged"
+            },
+            "x-enum-descriptions": [
+                "bills the usage of the meter",
+                "records and reports the usage of the meter without ever billing it,\nfor ex while onboarding a customer before their usage is charged"
+            ],
+            "x-enum-varnames": [
+                "MeterBillingModeBill",
+                "MeterBillingModeShadow"
+            ]
+        },
+        "types.MeterEventNameMatch": {
+            "type": "string",
+            "enum": [
+                "exact",
+                "prefix"
+            ],
+            "x-enum-comments": {
+                "MeterEventNameMatchExact": "matches the events named exactly as the meter's event name",
+                "MeterEventNameMatchPrefix": "matches the events whose name starts with the meter's event name,\nfor ex \"api.users.\" matches \"api.users.create\" and \"api.users.delete\""
+            },
+            "x-enum-descriptions": [
+                "matches the events named exactly as the meter's event name",
+                "matches the events whose name starts with the meter's event name,\nfor ex \"api.users.\" matches \"api.users.create\" and \"api.users.delete\""
+            ],
+            "x-enum-varnames": [
+                "MeterEventNameMatchExact",
+                "MeterEventNameMatchPrefix"
+            ]
+        },
+        "types.MeterGapFillMode": {
+            "type": "string",
+            "enum": [
+                "zero",
+                "carry_over"
+            ],
+            "x-enum-comments": {
+                "MeterGapFillModeCarryOver": "repeats the usage of the last window with events, for gauges\nwhose value holds until the next reading",
+                "MeterGapFillModeZero": "reports a zero usage point for windows without events"
+            },
+            "x-enum-descriptions": [
+                "reports a zero usage point for windows without events",
+                "repeats the usage of the last window with events, for gauges\nwhose value holds until the next reading"
+            ],
+            "x-enum-varnames": [
+                "MeterGapFillModeZero",
+                "MeterGapFillModeCarryOver"
+            ]
+        },
         "types.PaginationResponse": {
             "type": "object",
             "properties": {
@@ -22824,6 +23502,36 @@ const docTemplate = `{
                 "TransactionTypeDebit"
             ]
         },
+        "types.UsageAnalyticsWarningCode": {
+            "type": "string",
+            "enum": [
+                "missing_price",
+                "missing_conversion_rate"
+            ],
+            "x-enum-comments": {
+                "UsageAnalyticsWarningMissingConversionRate": "means costs are priced in a currency without a\nconversion rate to the settlement currency, they are left out of the settled total cost",
+                "UsageAnalyticsWarningMissingPrice": "means usage was recorded against a price that couldn't be\nfetched, its cost is reported as zero"
+            },
+            "x-enum-descriptions": [
+                "means usage was recorded against a price that couldn't be\nfetched, its cost is reported as zero",
+                "means costs are priced in a currency without a\nconversion rate to the settlement currency, they are left out of the settled total cost"
+            ],
+            "x-enum-varnames": [
+                "UsageAnalyticsWarningMissingPrice",
+                "UsageAnalyticsWarningMissingConversionRate"
+            ]
+        },
+        "types.UsageForecastModel": {
+            "type": "string",
+            "enum": [
+                "moving_average",
+                "linear_trend"
+            ],
+            "x-enum-varnames": [
+                "UsageForecastModelMovingAverage",
+                "UsageForecastModelLinearTrend"
+            ]
+        },
         "types.UserFilter": {
             "type": "object",
             "properties": {
@@ -22986,6 +23694,9 @@ const docTemplate = `{
         "types.WindowSize": {
             "type": "string",
             "enum": [
+                "SECOND",
+                "10SEC",
+                "30SEC",
                 "MINUTE",
                 "15MIN",
                 "30MIN",
@@ -22998,6 +23709,9 @@ const docTemplate = `{
                 "MONTH"
             ],
             "x-enum-varnames": [
+                "WindowSizeSecond",
+                "WindowSize10Sec",
+                "WindowSize30Sec",
                 "WindowSizeMinute",
                 "WindowSize15Min",
                 "WindowSize30Min",
@@ -15,6 +15,7 @@ import (
 	"github.com/flexprice/flexprice/ent/addon"
 	"github.com/flexprice/flexprice/ent/addonassociation"
 	"github.com/flexprice/flexprice/ent/alertlogs"
+	"github.com/flexprice/flexprice/ent/auditlog"
 	"github.com/flexprice/flexprice/ent/auth"
 	"github.com/flexprice/flexprice/ent/billingsequence"
 	"github.com/flexprice/flexprice/ent/connection"
@@ -30,6 +31,7 @@ import (
 	"github.com/flexprice/flexprice/ent/entitlement"
 	"github.com/flexprice/flexprice/ent/entityintegrationmapping"
 	"github.com/flexprice/flexprice/ent/environment"
+	"github.com/flexprice/flexprice/ent/eventdedupkey"
 	"github.com/flexprice/flexprice/ent/feature"
 	"github.com/flexprice/flexprice/ent/group"
 	"github.com/flexprice/flexprice/ent/invoice"
@@ -53,6 +55,8 @@ import (
 	"github.com/flexprice/flexprice/ent/taxassociation"
 	"github.com/flexprice/flexprice/ent/taxrate"
 	"github.com/flexprice/flexprice/ent/tenant"
+	"github.com/flexprice/flexprice/ent/usagecounter"
+	"github.com/flexprice/flexprice/ent/usagecounterentry"
 	"github.com/flexprice/flexprice/ent/user"
 	"github.com/flexprice/flexprice/ent/wallet"
 	"github.com/flexprice/flexprice/ent/wallettransaction"
@@ -119,6 +123,7 @@ func checkColumn(table, column string) error {
 			addon.Table:                    addon.ValidColumn,
 			addonassociation.Table:         addonassociation.ValidColumn,
 			alertlogs.Table:                alertlogs.ValidColumn,
+			auditlog.Table:                 auditlog.ValidColumn,
 			auth.Table:                     auth.ValidColumn,
 			billingsequence.Table:          billingsequence.ValidColumn,
 			connection.Table:               connection.ValidColumn,
@@ -134,6 +139,7 @@ func checkColumn(table, column string) error {
 			entitlement.Table:              entitlement.ValidColumn,
 			entityintegrationmapping.Table: entityintegrationmapping.ValidColumn,
 			environment.Table:              environment.ValidColumn,
+			eventdedupkey.Table:            eventdedupkey.ValidColumn,
 			feature.Table:                  feature.ValidColumn,
 			group.Table:                    group.ValidColumn,
 			invoice.Table:                  invoice.ValidColumn,
@@ -157,6 +163,8 @@ func checkColumn(table, column string) error {
 			taxassociation.Table:           taxassociation.ValidColumn,
 			taxrate.Table:                  taxrate.ValidColumn,
 			tenant.Table:                   tenant.ValidColumn,
+			usagecounter.Table:             usagecounter.ValidColumn,
+			usagecounterentry.Table:        usagecounterentry.ValidColumn,
 			user.Table:                     user.ValidColumn,
 			wallet.Table:                   wallet.ValidColumn,
 			wallettransaction.Table:        wallettransaction.ValidColumn,
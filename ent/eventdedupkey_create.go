@@ -0,0 +1,276 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/flexprice/flexprice/ent/eventdedupkey"
+)
+
+// EventDedupKeyCreate is the builder for creating a EventDedupKey entity.
+type EventDedupKeyCreate struct {
+	config
+	mutation *EventDedupKeyMutation
+	hooks    []Hook
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (edkc *EventDedupKeyCreate) SetTenantID(s string) *EventDedupKeyCreate {
+	edkc.mutation.SetTenantID(s)
+	return edkc
+}
+
+// SetEnvironmentID sets the "environment_id" field.
+func (edkc *EventDedupKeyCreate) SetEnvironmentID(s string) *EventDedupKeyCreate {
+	edkc.mutation.SetEnvironmentID(s)
+	return edkc
+}
+
+// SetNillableEnvironmentID sets the "environment_id" field if the given value is not nil.
+func (edkc *EventDedupKeyCreate) SetNillableEnvironmentID(s *string) *EventDedupKeyCreate {
+	if s != nil {
+		edkc.SetEnvironmentID(*s)
+	}
+	return edkc
+}
+
+// SetEventID sets the "event_id" field.
+func (edkc *EventDedupKeyCreate) SetEventID(s string) *EventDedupKeyCreate {
+	edkc.mutation.SetEventID(s)
+	return edkc
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (edkc *EventDedupKeyCreate) SetExpiresAt(t time.Time) *EventDedupKeyCreate {
+	edkc.mutation.SetExpiresAt(t)
+	return edkc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (edkc *EventDedupKeyCreate) SetCreatedAt(t time.Time) *EventDedupKeyCreate {
+	edkc.mutation.SetCreatedAt(t)
+	return edkc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (edkc *EventDedupKeyCreate) SetNillableCreatedAt(t *time.Time) *EventDedupKeyCreate {
+	if t != nil {
+		edkc.SetCreatedAt(*t)
+	}
+	return edkc
+}
+
+// Mutation returns the EventDedupKeyMutation object of the builder.
+func (edkc *EventDedupKeyCreate) Mutation() *EventDedupKeyMutation {
+	return edkc.mutation
+}
+
+// Save creates the EventDedupKey in the database.
+func (edkc *EventDedupKeyCreate) Save(ctx context.Context) (*EventDedupKey, error) {
+	edkc.defaults()
+	return withHooks(ctx, edkc.sqlSave, edkc.mutation, edkc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (edkc *EventDedupKeyCreate) SaveX(ctx context.Context) *EventDedupKey {
+	v, err := edkc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (edkc *EventDedupKeyCreate) Exec(ctx context.Context) error {
+	_, err := edkc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (edkc *EventDedupKeyCreate) ExecX(ctx context.Context) {
+	if err := edkc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (edkc *EventDedupKeyCreate) defaults() {
+	if _, ok := edkc.mutation.EnvironmentID(); !ok {
+		v := eventdedupkey.DefaultEnvironmentID
+		edkc.mutation.SetEnvironmentID(v)
+	}
+	if _, ok := edkc.mutation.CreatedAt(); !ok {
+		v := eventdedupkey.DefaultCreatedAt()
+		edkc.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (edkc *EventDedupKeyCreate) check() error {
+	if _, ok := edkc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant_id", err: errors.New(`ent: missing required field "EventDedupKey.tenant_id"`)}
+	}
+	if v, ok := edkc.mutation.TenantID(); ok {
+		if err := eventdedupkey.TenantIDValidator(v); err != nil {
+			return &ValidationError{Name: "tenant_id", err: fmt.Errorf(`ent: validator failed for field "EventDedupKey.tenant_id": %w`, err)}
+		}
+	}
+	if _, ok := edkc.mutation.EnvironmentID(); !ok {
+		return &ValidationError{Name: "environment_id", err: errors.New(`ent: missing required field "EventDedupKey.environment_id"`)}
+	}
+	if _, ok := edkc.mutation.EventID(); !ok {
+		return &ValidationError{Name: "event_id", err: errors.New(`ent: missing required field "EventDedupKey.event_id"`)}
+	}
+	if v, ok := edkc.mutation.EventID(); ok {
+		if err := eventdedupkey.EventIDValidator(v); err != nil {
+			return &ValidationError{Name: "event_id", err: fmt.Errorf(`ent: validator failed for field "EventDedupKey.event_id": %w`, err)}
+		}
+	}
+	if _, ok := edkc.mutation.ExpiresAt(); !ok {
+		return &ValidationError{Name: "expires_at", err: errors.New(`ent: missing required field "EventDedupKey.expires_at"`)}
+	}
+	if _, ok := edkc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "EventDedupKey.created_at"`)}
+	}
+	return nil
+}
+
+func (edkc *EventDedupKeyCreate) sqlSave(ctx context.Context) (*EventDedupKey, error) {
+	if err := edkc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := edkc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, edkc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	edkc.mutation.id = &_node.ID
+	edkc.mutation.done = true
+	return _node, nil
+}
+
+func (edkc *EventDedupKeyCreate) createSpec() (*EventDedupKey, *sqlgraph.CreateSpec) {
+	var (
+		_node = &EventDedupKey{config: edkc.config}
+		_spec = sqlgraph.NewCreateSpec(eventdedupkey.Table, sqlgraph.NewFieldSpec(eventdedupkey.FieldID, field.TypeInt))
+	)
+	if value, ok := edkc.mutation.TenantID(); ok {
+		_spec.SetField(eventdedupkey.FieldTenantID, field.TypeString, value)
+		_node.TenantID = value
+	}
+	if value, ok := edkc.mutation.EnvironmentID(); ok {
+		_spec.SetField(eventdedupkey.FieldEnvironmentID, field.TypeString, value)
+		_node.EnvironmentID = value
+	}
+	if value, ok := edkc.mutation.EventID(); ok {
+		_spec.SetField(eventdedupkey.FieldEventID, field.TypeString, value)
+		_node.EventID = value
+	}
+	if value, ok := edkc.mutation.ExpiresAt(); ok {
+		_spec.SetField(eventdedupkey.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = value
+	}
+	if value, ok := edkc.mutation.CreatedAt(); ok {
+		_spec.SetField(eventdedupkey.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	return _node, _spec
+}
+
+// EventDedupKeyCreateBulk is the builder for creating many EventDedupKey entities in bulk.
+type EventDedupKeyCreateBulk struct {
+	config
+	err      error
+	builders []*EventDedupKeyCreate
+}
+
+// Save creates the EventDedupKey entities in the database.
+func (edkcb *EventDedupKeyCreateBulk) Save(ctx context.Context) ([]*EventDedupKey, error) {
+	if edkcb.err != nil {
+		return nil, edkcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(edkcb.builders))
+	nodes := make([]*EventDedupKey, len(edkcb.builders))
+	mutators := make([]Mutator, len(edkcb.builders))
+	for i := range edkcb.builders {
+		func(i int, root context.Context) {
+			builder := edkcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*EventDedupKeyMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, edkcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, edkcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, edkcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (edkcb *EventDedupKeyCreateBulk) SaveX(ctx context.Context) []*EventDedupKey {
+	v, err := edkcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (edkcb *EventDedupKeyCreateBulk) Exec(ctx context.Context) error {
+	_, err := edkcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (edkcb *EventDedupKeyCreateBulk) ExecX(ctx context.Context) {
+	if err := edkcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
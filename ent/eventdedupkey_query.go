@@ -0,0 +1,527 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/flexprice/flexprice/ent/eventdedupkey"
+	"github.com/flexprice/flexprice/ent/predicate"
+)
+
+// EventDedupKeyQuery is the builder for querying EventDedupKey entities.
+type EventDedupKeyQuery struct {
+	config
+	ctx        *QueryContext
+	order      []eventdedupkey.OrderOption
+	inters     []Interceptor
+	predicates []predicate.EventDedupKey
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the EventDedupKeyQuery builder.
+func (edkq *EventDedupKeyQuery) Where(ps ...predicate.EventDedupKey) *EventDedupKeyQuery {
+	edkq.predicates = append(edkq.predicates, ps...)
+	return edkq
+}
+
+// Limit the number of records to be returned by this query.
+func (edkq *EventDedupKeyQuery) Limit(limit int) *EventDedupKeyQuery {
+	edkq.ctx.Limit = &limit
+	return edkq
+}
+
+// Offset to start from.
+func (edkq *EventDedupKeyQuery) Offset(offset int) *EventDedupKeyQuery {
+	edkq.ctx.Offset = &offset
+	return edkq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (edkq *EventDedupKeyQuery) Unique(unique bool) *EventDedupKeyQuery {
+	edkq.ctx.Unique = &unique
+	return edkq
+}
+
+// Order specifies how the records should be ordered.
+func (edkq *EventDedupKeyQuery) Order(o ...eventdedupkey.OrderOption) *EventDedupKeyQuery {
+	edkq.order = append(edkq.order, o...)
+	return edkq
+}
+
+// First returns the first EventDedupKey entity from the query.
+// Returns a *NotFoundError when no EventDedupKey was found.
+func (edkq *EventDedupKeyQuery) First(ctx context.Context) (*EventDedupKey, error) {
+	nodes, err := edkq.Limit(1).All(setContextOp(ctx, edkq.ctx, ent.OpQueryFirst))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{eventdedupkey.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (edkq *EventDedupKeyQuery) FirstX(ctx context.Context) *EventDedupKey {
+	node, err := edkq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first EventDedupKey ID from the query.
+// Returns a *NotFoundError when no EventDedupKey ID was found.
+func (edkq *EventDedupKeyQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = edkq.Limit(1).IDs(setContextOp(ctx, edkq.ctx, ent.OpQueryFirstID)); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{eventdedupkey.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (edkq *EventDedupKeyQuery) FirstIDX(ctx context.Context) int {
+	id, err := edkq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single EventDedupKey entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one EventDedupKey entity is found.
+// Returns a *NotFoundError when no EventDedupKey entities are found.
+func (edkq *EventDedupKeyQuery) Only(ctx context.Context) (*EventDedupKey, error) {
+	nodes, err := edkq.Limit(2).All(setContextOp(ctx, edkq.ctx, ent.OpQueryOnly))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{eventdedupkey.Label}
+	default:
+		return nil, &NotSingularError{eventdedupkey.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (edkq *EventDedupKeyQuery) OnlyX(ctx context.Context) *EventDedupKey {
+	node, err := edkq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only EventDedupKey ID in the query.
+// Returns a *NotSingularError when more than one EventDedupKey ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (edkq *EventDedupKeyQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = edkq.Limit(2).IDs(setContextOp(ctx, edkq.ctx, ent.OpQueryOnlyID)); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{eventdedupkey.Label}
+	default:
+		err = &NotSingularError{eventdedupkey.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (edkq *EventDedupKeyQuery) OnlyIDX(ctx context.Context) int {
+	id, err := edkq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of EventDedupKeys.
+func (edkq *EventDedupKeyQuery) All(ctx context.Context) ([]*EventDedupKey, error) {
+	ctx = setContextOp(ctx, edkq.ctx, ent.OpQueryAll)
+	if err := edkq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*EventDedupKey, *EventDedupKeyQuery]()
+	return withInterceptors[[]*EventDedupKey](ctx, edkq, qr, edkq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (edkq *EventDedupKeyQuery) AllX(ctx context.Context) []*EventDedupKey {
+	nodes, err := edkq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of EventDedupKey IDs.
+func (edkq *EventDedupKeyQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if edkq.ctx.Unique == nil && edkq.path != nil {
+		edkq.Unique(true)
+	}
+	ctx = setContextOp(ctx, edkq.ctx, ent.OpQueryIDs)
+	if err = edkq.Select(eventdedupkey.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (edkq *EventDedupKeyQuery) IDsX(ctx context.Context) []int {
+	ids, err := edkq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (edkq *EventDedupKeyQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, edkq.ctx, ent.OpQueryCount)
+	if err := edkq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, edkq, querierCount[*EventDedupKeyQuery](), edkq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (edkq *EventDedupKeyQuery) CountX(ctx context.Context) int {
+	count, err := edkq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (edkq *EventDedupKeyQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, edkq.ctx, ent.OpQueryExist)
+	switch _, err := edkq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (edkq *EventDedupKeyQuery) ExistX(ctx context.Context) bool {
+	exist, err := edkq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the EventDedupKeyQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (edkq *EventDedupKeyQuery) Clone() *EventDedupKeyQuery {
+	if edkq == nil {
+		return nil
+	}
+	return &EventDedupKeyQuery{
+		config:     edkq.config,
+		ctx:        edkq.ctx.Clone(),
+		order:      append([]eventdedupkey.OrderOption{}, edkq.order...),
+		inters:     append([]Interceptor{}, edkq.inters...),
+		predicates: append([]predicate.EventDedupKey{}, edkq.predicates...),
+		// clone intermediate query.
+		sql:  edkq.sql.Clone(),
+		path: edkq.path,
+	}
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		TenantID string `json:"tenant_id,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.EventDedupKey.Query().
+//		GroupBy(eventdedupkey.FieldTenantID).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (edkq *EventDedupKeyQuery) GroupBy(field string, fields ...string) *EventDedupKeyGroupBy {
+	edkq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &EventDedupKeyGroupBy{build: edkq}
+	grbuild.flds = &edkq.ctx.Fields
+	grbuild.label = eventdedupkey.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		TenantID string `json:"tenant_id,omitempty"`
+//	}
+//
+//	client.EventDedupKey.Query().
+//		Select(eventdedupkey.FieldTenantID).
+//		Scan(ctx, &v)
+func (edkq *EventDedupKeyQuery) Select(fields ...string) *EventDedupKeySelect {
+	edkq.ctx.Fields = append(edkq.ctx.Fields, fields...)
+	sbuild := &EventDedupKeySelect{EventDedupKeyQuery: edkq}
+	sbuild.label = eventdedupkey.Label
+	sbuild.flds, sbuild.scan = &edkq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a EventDedupKeySelect configured with the given aggregations.
+func (edkq *EventDedupKeyQuery) Aggregate(fns ...AggregateFunc) *EventDedupKeySelect {
+	return edkq.Select().Aggregate(fns...)
+}
+
+func (edkq *EventDedupKeyQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range edkq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, edkq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range edkq.ctx.Fields {
+		if !eventdedupkey.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if edkq.path != nil {
+		prev, err := edkq.path(ctx)
+		if err != nil {
+			return err
+		}
+		edkq.sql = prev
+	}
+	return nil
+}
+
+func (edkq *EventDedupKeyQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*EventDedupKey, error) {
+	var (
+		nodes = []*EventDedupKey{}
+		_spec = edkq.querySpec()
+	)
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*EventDedupKey).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &EventDedupKey{config: edkq.config}
+		nodes = append(nodes, node)
+		return node.assignValues(columns, values)
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, edkq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	return nodes, nil
+}
+
+func (edkq *EventDedupKeyQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := edkq.querySpec()
+	_spec.Node.Columns = edkq.ctx.Fields
+	if len(edkq.ctx.Fields) > 0 {
+		_spec.Unique = edkq.ctx.Unique != nil && *edkq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, edkq.driver, _spec)
+}
+
+func (edkq *EventDedupKeyQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(eventdedupkey.Table, eventdedupkey.Columns, sqlgraph.NewFieldSpec(eventdedupkey.FieldID, field.TypeInt))
+	_spec.From = edkq.sql
+	if unique := edkq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if edkq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := edkq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, eventdedupkey.FieldID)
+		for i := range fields {
+			if fields[i] != eventdedupkey.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := edkq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := edkq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := edkq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := edkq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (edkq *EventDedupKeyQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(edkq.driver.Dialect())
+	t1 := builder.Table(eventdedupkey.Table)
+	columns := edkq.ctx.Fields
+	if len(columns) == 0 {
+		columns = eventdedupkey.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if edkq.sql != nil {
+		selector = edkq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if edkq.ctx.Unique != nil && *edkq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, p := range edkq.predicates {
+		p(selector)
+	}
+	for _, p := range edkq.order {
+		p(selector)
+	}
+	if offset := edkq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := edkq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// EventDedupKeyGroupBy is the group-by builder for EventDedupKey entities.
+type EventDedupKeyGroupBy struct {
+	selector
+	build *EventDedupKeyQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (edkgb *EventDedupKeyGroupBy) Aggregate(fns ...AggregateFunc) *EventDedupKeyGroupBy {
+	edkgb.fns = append(edkgb.fns, fns...)
+	return edkgb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (edkgb *EventDedupKeyGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, edkgb.build.ctx, ent.OpQueryGroupBy)
+	if err := edkgb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*EventDedupKeyQuery, *EventDedupKeyGroupBy](ctx, edkgb.build, edkgb, edkgb.build.inters, v)
+}
+
+func (edkgb *EventDedupKeyGroupBy) sqlScan(ctx context.Context, root *EventDedupKeyQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(edkgb.fns))
+	for _, fn := range edkgb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*edkgb.flds)+len(edkgb.fns))
+		for _, f := range *edkgb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*edkgb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := edkgb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// EventDedupKeySelect is the builder for selecting fields of EventDedupKey entities.
+type EventDedupKeySelect struct {
+	*EventDedupKeyQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (edks *EventDedupKeySelect) Aggregate(fns ...AggregateFunc) *EventDedupKeySelect {
+	edks.fns = append(edks.fns, fns...)
+	return edks
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (edks *EventDedupKeySelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, edks.ctx, ent.OpQuerySelect)
+	if err := edks.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*EventDedupKeyQuery, *EventDedupKeySelect](ctx, edks.EventDedupKeyQuery, edks, edks.inters, v)
+}
+
+func (edks *EventDedupKeySelect) sqlScan(ctx context.Context, root *EventDedupKeyQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(edks.fns))
+	for _, fn := range edks.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*edks.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := edks.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
@@ -0,0 +1,244 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/flexprice/flexprice/ent/eventdedupkey"
+	"github.com/flexprice/flexprice/ent/predicate"
+)
+
+// EventDedupKeyUpdate is the builder for updating EventDedupKey entities.
+type EventDedupKeyUpdate struct {
+	config
+	hooks    []Hook
+	mutation *EventDedupKeyMutation
+}
+
+// Where appends a list predicates to the EventDedupKeyUpdate builder.
+func (edku *EventDedupKeyUpdate) Where(ps ...predicate.EventDedupKey) *EventDedupKeyUpdate {
+	edku.mutation.Where(ps...)
+	return edku
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (edku *EventDedupKeyUpdate) SetExpiresAt(t time.Time) *EventDedupKeyUpdate {
+	edku.mutation.SetExpiresAt(t)
+	return edku
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (edku *EventDedupKeyUpdate) SetNillableExpiresAt(t *time.Time) *EventDedupKeyUpdate {
+	if t != nil {
+		edku.SetExpiresAt(*t)
+	}
+	return edku
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (edku *EventDedupKeyUpdate) SetCreatedAt(t time.Time) *EventDedupKeyUpdate {
+	edku.mutation.SetCreatedAt(t)
+	return edku
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (edku *EventDedupKeyUpdate) SetNillableCreatedAt(t *time.Time) *EventDedupKeyUpdate {
+	if t != nil {
+		edku.SetCreatedAt(*t)
+	}
+	return edku
+}
+
+// Mutation returns the EventDedupKeyMutation object of the builder.
+func (edku *EventDedupKeyUpdate) Mutation() *EventDedupKeyMutation {
+	return edku.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (edku *EventDedupKeyUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, edku.sqlSave, edku.mutation, edku.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (edku *EventDedupKeyUpdate) SaveX(ctx context.Context) int {
+	affected, err := edku.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (edku *EventDedupKeyUpdate) Exec(ctx context.Context) error {
+	_, err := edku.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (edku *EventDedupKeyUpdate) ExecX(ctx context.Context) {
+	if err := edku.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (edku *EventDedupKeyUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(eventdedupkey.Table, eventdedupkey.Columns, sqlgraph.NewFieldSpec(eventdedupkey.FieldID, field.TypeInt))
+	if ps := edku.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := edku.mutation.ExpiresAt(); ok {
+		_spec.SetField(eventdedupkey.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := edku.mutation.CreatedAt(); ok {
+		_spec.SetField(eventdedupkey.FieldCreatedAt, field.TypeTime, value)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, edku.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{eventdedupkey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	edku.mutation.done = true
+	return n, nil
+}
+
+// EventDedupKeyUpdateOne is the builder for updating a single EventDedupKey entity.
+type EventDedupKeyUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *EventDedupKeyMutation
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (edkuo *EventDedupKeyUpdateOne) SetExpiresAt(t time.Time) *EventDedupKeyUpdateOne {
+	edkuo.mutation.SetExpiresAt(t)
+	return edkuo
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (edkuo *EventDedupKeyUpdateOne) SetNillableExpiresAt(t *time.Time) *EventDedupKeyUpdateOne {
+	if t != nil {
+		edkuo.SetExpiresAt(*t)
+	}
+	return edkuo
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (edkuo *EventDedupKeyUpdateOne) SetCreatedAt(t time.Time) *EventDedupKeyUpdateOne {
+	edkuo.mutation.SetCreatedAt(t)
+	return edkuo
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (edkuo *EventDedupKeyUpdateOne) SetNillableCreatedAt(t *time.Time) *EventDedupKeyUpdateOne {
+	if t != nil {
+		edkuo.SetCreatedAt(*t)
+	}
+	return edkuo
+}
+
+// Mutation returns the EventDedupKeyMutation object of the builder.
+func (edkuo *EventDedupKeyUpdateOne) Mutation() *EventDedupKeyMutation {
+	return edkuo.mutation
+}
+
+// Where appends a list predicates to the EventDedupKeyUpdate builder.
+func (edkuo *EventDedupKeyUpdateOne) Where(ps ...predicate.EventDedupKey) *EventDedupKeyUpdateOne {
+	edkuo.mutation.Where(ps...)
+	return edkuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (edkuo *EventDedupKeyUpdateOne) Select(field string, fields ...string) *EventDedupKeyUpdateOne {
+	edkuo.fields = append([]string{field}, fields...)
+	return edkuo
+}
+
+// Save executes the query and returns the updated EventDedupKey entity.
+func (edkuo *EventDedupKeyUpdateOne) Save(ctx context.Context) (*EventDedupKey, error) {
+	return withHooks(ctx, edkuo.sqlSave, edkuo.mutation, edkuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (edkuo *EventDedupKeyUpdateOne) SaveX(ctx context.Context) *EventDedupKey {
+	node, err := edkuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (edkuo *EventDedupKeyUpdateOne) Exec(ctx context.Context) error {
+	_, err := edkuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (edkuo *EventDedupKeyUpdateOne) ExecX(ctx context.Context) {
+	if err := edkuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (edkuo *EventDedupKeyUpdateOne) sqlSave(ctx context.Context) (_node *EventDedupKey, err error) {
+	_spec := sqlgraph.NewUpdateSpec(eventdedupkey.Table, eventdedupkey.Columns, sqlgraph.NewFieldSpec(eventdedupkey.FieldID, field.TypeInt))
+	id, ok := edkuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "EventDedupKey.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := edkuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, eventdedupkey.FieldID)
+		for _, f := range fields {
+			if !eventdedupkey.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != eventdedupkey.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := edkuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := edkuo.mutation.ExpiresAt(); ok {
+		_spec.SetField(eventdedupkey.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := edkuo.mutation.CreatedAt(); ok {
+		_spec.SetField(eventdedupkey.FieldCreatedAt, field.TypeTime, value)
+	}
+	_node = &EventDedupKey{config: edkuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, edkuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{eventdedupkey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	edkuo.mutation.done = true
+	return _node, nil
+}
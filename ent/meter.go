@@ -42,8 +42,16 @@ type Meter struct {
 	// Filters holds the value of the "filters" field.
 	Filters []schema.MeterFilter `json:"filters,omitempty"`
 	// ResetUsage holds the value of the "reset_usage" field.
-	ResetUsage   string `json:"reset_usage,omitempty"`
-	selectValues sql.SelectValues
+	ResetUsage string `json:"reset_usage,omitempty"`
+	// SubscriptionStatuses holds the value of the "subscription_statuses" field.
+	SubscriptionStatuses []string `json:"subscription_statuses,omitempty"`
+	// BillingMode holds the value of the "billing_mode" field.
+	BillingMode string `json:"billing_mode,omitempty"`
+	// EventNameMatch holds the value of the "event_name_match" field.
+	EventNameMatch string `json:"event_name_match,omitempty"`
+	// IndexedProperties holds the value of the "indexed_properties" field.
+	IndexedProperties []string `json:"indexed_properties,omitempty"`
+	selectValues      sql.SelectValues
 }
 
 // scanValues returns the types for scanning values from sql.Rows.
@@ -51,9 +59,9 @@ func (*Meter) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case meter.FieldAggregation, meter.FieldFilters:
+		case meter.FieldAggregation, meter.FieldFilters, meter.FieldSubscriptionStatuses, meter.FieldIndexedProperties:
 			values[i] = new([]byte)
-		case meter.FieldID, meter.FieldTenantID, meter.FieldStatus, meter.FieldCreatedBy, meter.FieldUpdatedBy, meter.FieldEnvironmentID, meter.FieldEventName, meter.FieldName, meter.FieldResetUsage:
+		case meter.FieldID, meter.FieldTenantID, meter.FieldStatus, meter.FieldCreatedBy, meter.FieldUpdatedBy, meter.FieldEnvironmentID, meter.FieldEventName, meter.FieldName, meter.FieldResetUsage, meter.FieldBillingMode, meter.FieldEventNameMatch:
 			values[i] = new(sql.NullString)
 		case meter.FieldCreatedAt, meter.FieldUpdatedAt:
 			values[i] = new(sql.NullTime)
@@ -154,6 +162,34 @@ func (m *Meter) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				m.ResetUsage = value.String
 			}
+		case meter.FieldSubscriptionStatuses:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field subscription_statuses", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &m.SubscriptionStatuses); err != nil {
+					return fmt.Errorf("unmarshal field subscription_statuses: %w", err)
+				}
+			}
+		case meter.FieldBillingMode:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field billing_mode", values[i])
+			} else if value.Valid {
+				m.BillingMode = value.String
+			}
+		case meter.FieldEventNameMatch:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field event_name_match", values[i])
+			} else if value.Valid {
+				m.EventNameMatch = value.String
+			}
+		case meter.FieldIndexedProperties:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field indexed_properties", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &m.IndexedProperties); err != nil {
+					return fmt.Errorf("unmarshal field indexed_properties: %w", err)
+				}
+			}
 		default:
 			m.selectValues.Set(columns[i], values[i])
 		}
@@ -225,6 +261,18 @@ func (m *Meter) String() string {
 	builder.WriteString(", ")
 	builder.WriteString("reset_usage=")
 	builder.WriteString(m.ResetUsage)
+	builder.WriteString(", ")
+	builder.WriteString("subscription_statuses=")
+	builder.WriteString(fmt.Sprintf("%v", m.SubscriptionStatuses))
+	builder.WriteString(", ")
+	builder.WriteString("billing_mode=")
+	builder.WriteString(m.BillingMode)
+	builder.WriteString(", ")
+	builder.WriteString("event_name_match=")
+	builder.WriteString(m.EventNameMatch)
+	builder.WriteString(", ")
+	builder.WriteString("indexed_properties=")
+	builder.WriteString(fmt.Sprintf("%v", m.IndexedProperties))
 	builder.WriteByte(')')
 	return builder.String()
 }
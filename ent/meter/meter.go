@@ -38,6 +38,14 @@ const (
 	FieldFilters = "filters"
 	// FieldResetUsage holds the string denoting the reset_usage field in the database.
 	FieldResetUsage = "reset_usage"
+	// FieldSubscriptionStatuses holds the string denoting the subscription_statuses field in the database.
+	FieldSubscriptionStatuses = "subscription_statuses"
+	// FieldBillingMode holds the string denoting the billing_mode field in the database.
+	FieldBillingMode = "billing_mode"
+	// FieldEventNameMatch holds the string denoting the event_name_match field in the database.
+	FieldEventNameMatch = "event_name_match"
+	// FieldIndexedProperties holds the string denoting the indexed_properties field in the database.
+	FieldIndexedProperties = "indexed_properties"
 	// Table holds the table name of the meter in the database.
 	Table = "meters"
 )
@@ -57,6 +65,10 @@ var Columns = []string{
 	FieldAggregation,
 	FieldFilters,
 	FieldResetUsage,
+	FieldSubscriptionStatuses,
+	FieldBillingMode,
+	FieldEventNameMatch,
+	FieldIndexedProperties,
 }
 
 // ValidColumn reports if the column name is valid (part of the table columns).
@@ -92,6 +104,10 @@ var (
 	DefaultFilters []schema.MeterFilter
 	// DefaultResetUsage holds the default value on creation for the "reset_usage" field.
 	DefaultResetUsage string
+	// DefaultBillingMode holds the default value on creation for the "billing_mode" field.
+	DefaultBillingMode string
+	// DefaultEventNameMatch holds the default value on creation for the "event_name_match" field.
+	DefaultEventNameMatch string
 )
 
 // OrderOption defines the ordering options for the Meter queries.
@@ -151,3 +167,13 @@ func ByName(opts ...sql.OrderTermOption) OrderOption {
 func ByResetUsage(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldResetUsage, opts...).ToFunc()
 }
+
+// ByBillingMode orders the results by the billing_mode field.
+func ByBillingMode(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldBillingMode, opts...).ToFunc()
+}
+
+// ByEventNameMatch orders the results by the event_name_match field.
+func ByEventNameMatch(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldEventNameMatch, opts...).ToFunc()
+}
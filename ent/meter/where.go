@@ -114,6 +114,16 @@ func ResetUsage(v string) predicate.Meter {
 	return predicate.Meter(sql.FieldEQ(FieldResetUsage, v))
 }
 
+// BillingMode applies equality check predicate on the "billing_mode" field. It's identical to BillingModeEQ.
+func BillingMode(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldEQ(FieldBillingMode, v))
+}
+
+// EventNameMatch applies equality check predicate on the "event_name_match" field. It's identical to EventNameMatchEQ.
+func EventNameMatch(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldEQ(FieldEventNameMatch, v))
+}
+
 // TenantIDEQ applies the EQ predicate on the "tenant_id" field.
 func TenantIDEQ(v string) predicate.Meter {
 	return predicate.Meter(sql.FieldEQ(FieldTenantID, v))
@@ -744,6 +754,156 @@ func ResetUsageContainsFold(v string) predicate.Meter {
 	return predicate.Meter(sql.FieldContainsFold(FieldResetUsage, v))
 }
 
+// SubscriptionStatusesIsNil applies the IsNil predicate on the "subscription_statuses" field.
+func SubscriptionStatusesIsNil() predicate.Meter {
+	return predicate.Meter(sql.FieldIsNull(FieldSubscriptionStatuses))
+}
+
+// SubscriptionStatusesNotNil applies the NotNil predicate on the "subscription_statuses" field.
+func SubscriptionStatusesNotNil() predicate.Meter {
+	return predicate.Meter(sql.FieldNotNull(FieldSubscriptionStatuses))
+}
+
+// BillingModeEQ applies the EQ predicate on the "billing_mode" field.
+func BillingModeEQ(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldEQ(FieldBillingMode, v))
+}
+
+// BillingModeNEQ applies the NEQ predicate on the "billing_mode" field.
+func BillingModeNEQ(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldNEQ(FieldBillingMode, v))
+}
+
+// BillingModeIn applies the In predicate on the "billing_mode" field.
+func BillingModeIn(vs ...string) predicate.Meter {
+	return predicate.Meter(sql.FieldIn(FieldBillingMode, vs...))
+}
+
+// BillingModeNotIn applies the NotIn predicate on the "billing_mode" field.
+func BillingModeNotIn(vs ...string) predicate.Meter {
+	return predicate.Meter(sql.FieldNotIn(FieldBillingMode, vs...))
+}
+
+// BillingModeGT applies the GT predicate on the "billing_mode" field.
+func BillingModeGT(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldGT(FieldBillingMode, v))
+}
+
+// BillingModeGTE applies the GTE predicate on the "billing_mode" field.
+func BillingModeGTE(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldGTE(FieldBillingMode, v))
+}
+
+// BillingModeLT applies the LT predicate on the "billing_mode" field.
+func BillingModeLT(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldLT(FieldBillingMode, v))
+}
+
+// BillingModeLTE applies the LTE predicate on the "billing_mode" field.
+func BillingModeLTE(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldLTE(FieldBillingMode, v))
+}
+
+// BillingModeContains applies the Contains predicate on the "billing_mode" field.
+func BillingModeContains(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldContains(FieldBillingMode, v))
+}
+
+// BillingModeHasPrefix applies the HasPrefix predicate on the "billing_mode" field.
+func BillingModeHasPrefix(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldHasPrefix(FieldBillingMode, v))
+}
+
+// BillingModeHasSuffix applies the HasSuffix predicate on the "billing_mode" field.
+func BillingModeHasSuffix(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldHasSuffix(FieldBillingMode, v))
+}
+
+// BillingModeEqualFold applies the EqualFold predicate on the "billing_mode" field.
+func BillingModeEqualFold(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldEqualFold(FieldBillingMode, v))
+}
+
+// BillingModeContainsFold applies the ContainsFold predicate on the "billing_mode" field.
+func BillingModeContainsFold(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldContainsFold(FieldBillingMode, v))
+}
+
+// EventNameMatchEQ applies the EQ predicate on the "event_name_match" field.
+func EventNameMatchEQ(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldEQ(FieldEventNameMatch, v))
+}
+
+// EventNameMatchNEQ applies the NEQ predicate on the "event_name_match" field.
+func EventNameMatchNEQ(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldNEQ(FieldEventNameMatch, v))
+}
+
+// EventNameMatchIn applies the In predicate on the "event_name_match" field.
+func EventNameMatchIn(vs ...string) predicate.Meter {
+	return predicate.Meter(sql.FieldIn(FieldEventNameMatch, vs...))
+}
+
+// EventNameMatchNotIn applies the NotIn predicate on the "event_name_match" field.
+func EventNameMatchNotIn(vs ...string) predicate.Meter {
+	return predicate.Meter(sql.FieldNotIn(FieldEventNameMatch, vs...))
+}
+
+// EventNameMatchGT applies the GT predicate on the "event_name_match" field.
+func EventNameMatchGT(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldGT(FieldEventNameMatch, v))
+}
+
+// EventNameMatchGTE applies the GTE predicate on the "event_name_match" field.
+func EventNameMatchGTE(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldGTE(FieldEventNameMatch, v))
+}
+
+// EventNameMatchLT applies the LT predicate on the "event_name_match" field.
+func EventNameMatchLT(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldLT(FieldEventNameMatch, v))
+}
+
+// EventNameMatchLTE applies the LTE predicate on the "event_name_match" field.
+func EventNameMatchLTE(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldLTE(FieldEventNameMatch, v))
+}
+
+// EventNameMatchContains applies the Contains predicate on the "event_name_match" field.
+func EventNameMatchContains(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldContains(FieldEventNameMatch, v))
+}
+
+// EventNameMatchHasPrefix applies the HasPrefix predicate on the "event_name_match" field.
+func EventNameMatchHasPrefix(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldHasPrefix(FieldEventNameMatch, v))
+}
+
+// EventNameMatchHasSuffix applies the HasSuffix predicate on the "event_name_match" field.
+func EventNameMatchHasSuffix(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldHasSuffix(FieldEventNameMatch, v))
+}
+
+// EventNameMatchEqualFold applies the EqualFold predicate on the "event_name_match" field.
+func EventNameMatchEqualFold(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldEqualFold(FieldEventNameMatch, v))
+}
+
+// EventNameMatchContainsFold applies the ContainsFold predicate on the "event_name_match" field.
+func EventNameMatchContainsFold(v string) predicate.Meter {
+	return predicate.Meter(sql.FieldContainsFold(FieldEventNameMatch, v))
+}
+
+// IndexedPropertiesIsNil applies the IsNil predicate on the "indexed_properties" field.
+func IndexedPropertiesIsNil() predicate.Meter {
+	return predicate.Meter(sql.FieldIsNull(FieldIndexedProperties))
+}
+
+// IndexedPropertiesNotNil applies the NotNil predicate on the "indexed_properties" field.
+func IndexedPropertiesNotNil() predicate.Meter {
+	return predicate.Meter(sql.FieldNotNull(FieldIndexedProperties))
+}
+
 // And groups predicates with the AND operator between them.
 func And(predicates ...predicate.Meter) predicate.Meter {
 	return predicate.Meter(sql.AndPredicates(predicates...))
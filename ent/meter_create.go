@@ -157,6 +157,46 @@ func (mc *MeterCreate) SetNillableResetUsage(s *string) *MeterCreate {
 	return mc
 }
 
+// SetSubscriptionStatuses sets the "subscription_statuses" field.
+func (mc *MeterCreate) SetSubscriptionStatuses(s []string) *MeterCreate {
+	mc.mutation.SetSubscriptionStatuses(s)
+	return mc
+}
+
+// SetBillingMode sets the "billing_mode" field.
+func (mc *MeterCreate) SetBillingMode(s string) *MeterCreate {
+	mc.mutation.SetBillingMode(s)
+	return mc
+}
+
+// SetNillableBillingMode sets the "billing_mode" field if the given value is not nil.
+func (mc *MeterCreate) SetNillableBillingMode(s *string) *MeterCreate {
+	if s != nil {
+		mc.SetBillingMode(*s)
+	}
+	return mc
+}
+
+// SetEventNameMatch sets the "event_name_match" field.
+func (mc *MeterCreate) SetEventNameMatch(s string) *MeterCreate {
+	mc.mutation.SetEventNameMatch(s)
+	return mc
+}
+
+// SetNillableEventNameMatch sets the "event_name_match" field if the given value is not nil.
+func (mc *MeterCreate) SetNillableEventNameMatch(s *string) *MeterCreate {
+	if s != nil {
+		mc.SetEventNameMatch(*s)
+	}
+	return mc
+}
+
+// SetIndexedProperties sets the "indexed_properties" field.
+func (mc *MeterCreate) SetIndexedProperties(s []string) *MeterCreate {
+	mc.mutation.SetIndexedProperties(s)
+	return mc
+}
+
 // SetID sets the "id" field.
 func (mc *MeterCreate) SetID(s string) *MeterCreate {
 	mc.mutation.SetID(s)
@@ -226,6 +266,14 @@ func (mc *MeterCreate) defaults() {
 		v := meter.DefaultResetUsage
 		mc.mutation.SetResetUsage(v)
 	}
+	if _, ok := mc.mutation.BillingMode(); !ok {
+		v := meter.DefaultBillingMode
+		mc.mutation.SetBillingMode(v)
+	}
+	if _, ok := mc.mutation.EventNameMatch(); !ok {
+		v := meter.DefaultEventNameMatch
+		mc.mutation.SetEventNameMatch(v)
+	}
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -272,6 +320,12 @@ func (mc *MeterCreate) check() error {
 	if _, ok := mc.mutation.ResetUsage(); !ok {
 		return &ValidationError{Name: "reset_usage", err: errors.New(`ent: missing required field "Meter.reset_usage"`)}
 	}
+	if _, ok := mc.mutation.BillingMode(); !ok {
+		return &ValidationError{Name: "billing_mode", err: errors.New(`ent: missing required field "Meter.billing_mode"`)}
+	}
+	if _, ok := mc.mutation.EventNameMatch(); !ok {
+		return &ValidationError{Name: "event_name_match", err: errors.New(`ent: missing required field "Meter.event_name_match"`)}
+	}
 	return nil
 }
 
@@ -355,6 +409,22 @@ func (mc *MeterCreate) createSpec() (*Meter, *sqlgraph.CreateSpec) {
 		_spec.SetField(meter.FieldResetUsage, field.TypeString, value)
 		_node.ResetUsage = value
 	}
+	if value, ok := mc.mutation.SubscriptionStatuses(); ok {
+		_spec.SetField(meter.FieldSubscriptionStatuses, field.TypeJSON, value)
+		_node.SubscriptionStatuses = value
+	}
+	if value, ok := mc.mutation.BillingMode(); ok {
+		_spec.SetField(meter.FieldBillingMode, field.TypeString, value)
+		_node.BillingMode = value
+	}
+	if value, ok := mc.mutation.EventNameMatch(); ok {
+		_spec.SetField(meter.FieldEventNameMatch, field.TypeString, value)
+		_node.EventNameMatch = value
+	}
+	if value, ok := mc.mutation.IndexedProperties(); ok {
+		_spec.SetField(meter.FieldIndexedProperties, field.TypeJSON, value)
+		_node.IndexedProperties = value
+	}
 	return _node, _spec
 }
 
@@ -138,6 +138,70 @@ func (mu *MeterUpdate) SetNillableResetUsage(s *string) *MeterUpdate {
 	return mu
 }
 
+// SetSubscriptionStatuses sets the "subscription_statuses" field.
+func (mu *MeterUpdate) SetSubscriptionStatuses(s []string) *MeterUpdate {
+	mu.mutation.SetSubscriptionStatuses(s)
+	return mu
+}
+
+// AppendSubscriptionStatuses appends s to the "subscription_statuses" field.
+func (mu *MeterUpdate) AppendSubscriptionStatuses(s []string) *MeterUpdate {
+	mu.mutation.AppendSubscriptionStatuses(s)
+	return mu
+}
+
+// ClearSubscriptionStatuses clears the value of the "subscription_statuses" field.
+func (mu *MeterUpdate) ClearSubscriptionStatuses() *MeterUpdate {
+	mu.mutation.ClearSubscriptionStatuses()
+	return mu
+}
+
+// SetBillingMode sets the "billing_mode" field.
+func (mu *MeterUpdate) SetBillingMode(s string) *MeterUpdate {
+	mu.mutation.SetBillingMode(s)
+	return mu
+}
+
+// SetNillableBillingMode sets the "billing_mode" field if the given value is not nil.
+func (mu *MeterUpdate) SetNillableBillingMode(s *string) *MeterUpdate {
+	if s != nil {
+		mu.SetBillingMode(*s)
+	}
+	return mu
+}
+
+// SetEventNameMatch sets the "event_name_match" field.
+func (mu *MeterUpdate) SetEventNameMatch(s string) *MeterUpdate {
+	mu.mutation.SetEventNameMatch(s)
+	return mu
+}
+
+// SetNillableEventNameMatch sets the "event_name_match" field if the given value is not nil.
+func (mu *MeterUpdate) SetNillableEventNameMatch(s *string) *MeterUpdate {
+	if s != nil {
+		mu.SetEventNameMatch(*s)
+	}
+	return mu
+}
+
+// SetIndexedProperties sets the "indexed_properties" field.
+func (mu *MeterUpdate) SetIndexedProperties(s []string) *MeterUpdate {
+	mu.mutation.SetIndexedProperties(s)
+	return mu
+}
+
+// AppendIndexedProperties appends s to the "indexed_properties" field.
+func (mu *MeterUpdate) AppendIndexedProperties(s []string) *MeterUpdate {
+	mu.mutation.AppendIndexedProperties(s)
+	return mu
+}
+
+// ClearIndexedProperties clears the value of the "indexed_properties" field.
+func (mu *MeterUpdate) ClearIndexedProperties() *MeterUpdate {
+	mu.mutation.ClearIndexedProperties()
+	return mu
+}
+
 // Mutation returns the MeterMutation object of the builder.
 func (mu *MeterUpdate) Mutation() *MeterMutation {
 	return mu.mutation
@@ -244,6 +308,34 @@ func (mu *MeterUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := mu.mutation.ResetUsage(); ok {
 		_spec.SetField(meter.FieldResetUsage, field.TypeString, value)
 	}
+	if value, ok := mu.mutation.SubscriptionStatuses(); ok {
+		_spec.SetField(meter.FieldSubscriptionStatuses, field.TypeJSON, value)
+	}
+	if value, ok := mu.mutation.AppendedSubscriptionStatuses(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, meter.FieldSubscriptionStatuses, value)
+		})
+	}
+	if mu.mutation.SubscriptionStatusesCleared() {
+		_spec.ClearField(meter.FieldSubscriptionStatuses, field.TypeJSON)
+	}
+	if value, ok := mu.mutation.BillingMode(); ok {
+		_spec.SetField(meter.FieldBillingMode, field.TypeString, value)
+	}
+	if value, ok := mu.mutation.EventNameMatch(); ok {
+		_spec.SetField(meter.FieldEventNameMatch, field.TypeString, value)
+	}
+	if value, ok := mu.mutation.IndexedProperties(); ok {
+		_spec.SetField(meter.FieldIndexedProperties, field.TypeJSON, value)
+	}
+	if value, ok := mu.mutation.AppendedIndexedProperties(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, meter.FieldIndexedProperties, value)
+		})
+	}
+	if mu.mutation.IndexedPropertiesCleared() {
+		_spec.ClearField(meter.FieldIndexedProperties, field.TypeJSON)
+	}
 	if n, err = sqlgraph.UpdateNodes(ctx, mu.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{meter.Label}
@@ -372,6 +464,70 @@ func (muo *MeterUpdateOne) SetNillableResetUsage(s *string) *MeterUpdateOne {
 	return muo
 }
 
+// SetSubscriptionStatuses sets the "subscription_statuses" field.
+func (muo *MeterUpdateOne) SetSubscriptionStatuses(s []string) *MeterUpdateOne {
+	muo.mutation.SetSubscriptionStatuses(s)
+	return muo
+}
+
+// AppendSubscriptionStatuses appends s to the "subscription_statuses" field.
+func (muo *MeterUpdateOne) AppendSubscriptionStatuses(s []string) *MeterUpdateOne {
+	muo.mutation.AppendSubscriptionStatuses(s)
+	return muo
+}
+
+// ClearSubscriptionStatuses clears the value of the "subscription_statuses" field.
+func (muo *MeterUpdateOne) ClearSubscriptionStatuses() *MeterUpdateOne {
+	muo.mutation.ClearSubscriptionStatuses()
+	return muo
+}
+
+// SetBillingMode sets the "billing_mode" field.
+func (muo *MeterUpdateOne) SetBillingMode(s string) *MeterUpdateOne {
+	muo.mutation.SetBillingMode(s)
+	return muo
+}
+
+// SetNillableBillingMode sets the "billing_mode" field if the given value is not nil.
+func (muo *MeterUpdateOne) SetNillableBillingMode(s *string) *MeterUpdateOne {
+	if s != nil {
+		muo.SetBillingMode(*s)
+	}
+	return muo
+}
+
+// SetEventNameMatch sets the "event_name_match" field.
+func (muo *MeterUpdateOne) SetEventNameMatch(s string) *MeterUpdateOne {
+	muo.mutation.SetEventNameMatch(s)
+	return muo
+}
+
+// SetNillableEventNameMatch sets the "event_name_match" field if the given value is not nil.
+func (muo *MeterUpdateOne) SetNillableEventNameMatch(s *string) *MeterUpdateOne {
+	if s != nil {
+		muo.SetEventNameMatch(*s)
+	}
+	return muo
+}
+
+// SetIndexedProperties sets the "indexed_properties" field.
+func (muo *MeterUpdateOne) SetIndexedProperties(s []string) *MeterUpdateOne {
+	muo.mutation.SetIndexedProperties(s)
+	return muo
+}
+
+// AppendIndexedProperties appends s to the "indexed_properties" field.
+func (muo *MeterUpdateOne) AppendIndexedProperties(s []string) *MeterUpdateOne {
+	muo.mutation.AppendIndexedProperties(s)
+	return muo
+}
+
+// ClearIndexedProperties clears the value of the "indexed_properties" field.
+func (muo *MeterUpdateOne) ClearIndexedProperties() *MeterUpdateOne {
+	muo.mutation.ClearIndexedProperties()
+	return muo
+}
+
 // Mutation returns the MeterMutation object of the builder.
 func (muo *MeterUpdateOne) Mutation() *MeterMutation {
 	return muo.mutation
@@ -508,6 +664,34 @@ func (muo *MeterUpdateOne) sqlSave(ctx context.Context) (_node *Meter, err error
 	if value, ok := muo.mutation.ResetUsage(); ok {
 		_spec.SetField(meter.FieldResetUsage, field.TypeString, value)
 	}
+	if value, ok := muo.mutation.SubscriptionStatuses(); ok {
+		_spec.SetField(meter.FieldSubscriptionStatuses, field.TypeJSON, value)
+	}
+	if value, ok := muo.mutation.AppendedSubscriptionStatuses(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, meter.FieldSubscriptionStatuses, value)
+		})
+	}
+	if muo.mutation.SubscriptionStatusesCleared() {
+		_spec.ClearField(meter.FieldSubscriptionStatuses, field.TypeJSON)
+	}
+	if value, ok := muo.mutation.BillingMode(); ok {
+		_spec.SetField(meter.FieldBillingMode, field.TypeString, value)
+	}
+	if value, ok := muo.mutation.EventNameMatch(); ok {
+		_spec.SetField(meter.FieldEventNameMatch, field.TypeString, value)
+	}
+	if value, ok := muo.mutation.IndexedProperties(); ok {
+		_spec.SetField(meter.FieldIndexedProperties, field.TypeJSON, value)
+	}
+	if value, ok := muo.mutation.AppendedIndexedProperties(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, meter.FieldIndexedProperties, value)
+		})
+	}
+	if muo.mutation.IndexedPropertiesCleared() {
+		_spec.ClearField(meter.FieldIndexedProperties, field.TypeJSON)
+	}
 	_node = &Meter{config: muo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
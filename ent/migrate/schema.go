@@ -126,6 +126,33 @@ var (
 			},
 		},
 	}
+	// AuditLogsColumns holds the columns for the "audit_logs" table.
+	AuditLogsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString, Unique: true, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "tenant_id", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "environment_id", Type: field.TypeString, Default: "", SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "entity_type", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "entity_id", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "action", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(20)"}},
+		{Name: "actor", Type: field.TypeString, Default: "", SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "before", Type: field.TypeJSON, Nullable: true, SchemaType: map[string]string{"postgres": "jsonb"}},
+		{Name: "after", Type: field.TypeJSON, Nullable: true, SchemaType: map[string]string{"postgres": "jsonb"}},
+		{Name: "changes", Type: field.TypeJSON, SchemaType: map[string]string{"postgres": "jsonb"}},
+		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "timestamp"}},
+	}
+	// AuditLogsTable holds the schema information for the "audit_logs" table.
+	AuditLogsTable = &schema.Table{
+		Name:       "audit_logs",
+		Columns:    AuditLogsColumns,
+		PrimaryKey: []*schema.Column{AuditLogsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "idx_audit_logs_entity",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogsColumns[1], AuditLogsColumns[2], AuditLogsColumns[3], AuditLogsColumns[4], AuditLogsColumns[10]},
+			},
+		},
+	}
 	// AuthsColumns holds the columns for the "auths" table.
 	AuthsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
@@ -835,6 +862,33 @@ var (
 			},
 		},
 	}
+	// EventDedupKeysColumns holds the columns for the "event_dedup_keys" table.
+	EventDedupKeysColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "tenant_id", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "environment_id", Type: field.TypeString, Default: "", SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "event_id", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(255)"}},
+		{Name: "expires_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "timestamp"}},
+		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "timestamp"}},
+	}
+	// EventDedupKeysTable holds the schema information for the "event_dedup_keys" table.
+	EventDedupKeysTable = &schema.Table{
+		Name:       "event_dedup_keys",
+		Columns:    EventDedupKeysColumns,
+		PrimaryKey: []*schema.Column{EventDedupKeysColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "idx_event_dedup_keys_event",
+				Unique:  true,
+				Columns: []*schema.Column{EventDedupKeysColumns[1], EventDedupKeysColumns[2], EventDedupKeysColumns[3]},
+			},
+			{
+				Name:    "idx_event_dedup_keys_expires_at",
+				Unique:  false,
+				Columns: []*schema.Column{EventDedupKeysColumns[4]},
+			},
+		},
+	}
 	// FeaturesColumns holds the columns for the "features" table.
 	FeaturesColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeString, Unique: true, SchemaType: map[string]string{"postgres": "varchar(50)"}},
@@ -1141,6 +1195,10 @@ var (
 		{Name: "aggregation", Type: field.TypeJSON},
 		{Name: "filters", Type: field.TypeJSON},
 		{Name: "reset_usage", Type: field.TypeString, Default: "BILLING_PERIOD", SchemaType: map[string]string{"postgres": "varchar(20)"}},
+		{Name: "subscription_statuses", Type: field.TypeJSON, Nullable: true},
+		{Name: "billing_mode", Type: field.TypeString, Default: "bill", SchemaType: map[string]string{"postgres": "varchar(20)"}},
+		{Name: "event_name_match", Type: field.TypeString, Default: "exact", SchemaType: map[string]string{"postgres": "varchar(20)"}},
+		{Name: "indexed_properties", Type: field.TypeJSON, Nullable: true},
 	}
 	// MetersTable holds the schema information for the "meters" table.
 	MetersTable = &schema.Table{
@@ -1340,6 +1398,7 @@ var (
 		{Name: "start_date", Type: field.TypeTime, Nullable: true},
 		{Name: "end_date", Type: field.TypeTime, Nullable: true},
 		{Name: "group_id", Type: field.TypeString, Nullable: true, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "discount_percent", Type: field.TypeFloat64, Nullable: true, SchemaType: map[string]string{"postgres": "numeric(5,2)"}},
 	}
 	// PricesTable holds the schema information for the "prices" table.
 	PricesTable = &schema.Table{
@@ -1567,6 +1626,7 @@ var (
 		{Name: "proration_behavior", Type: field.TypeString, Default: "none"},
 		{Name: "enable_true_up", Type: field.TypeBool, Default: false},
 		{Name: "invoicing_customer_id", Type: field.TypeString, Nullable: true, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "usage_frozen", Type: field.TypeBool, Default: false},
 	}
 	// SubscriptionsTable holds the schema information for the "subscriptions" table.
 	SubscriptionsTable = &schema.Table{
@@ -1964,6 +2024,57 @@ var (
 			},
 		},
 	}
+	// UsageCountersColumns holds the columns for the "usage_counters" table.
+	UsageCountersColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "tenant_id", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "environment_id", Type: field.TypeString, Default: "", SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "customer_id", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "feature_id", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "period_id", Type: field.TypeInt64},
+		{Name: "value", Type: field.TypeOther, SchemaType: map[string]string{"postgres": "numeric(25,15)"}},
+		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "timestamp"}},
+		{Name: "updated_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "timestamp"}},
+	}
+	// UsageCountersTable holds the schema information for the "usage_counters" table.
+	UsageCountersTable = &schema.Table{
+		Name:       "usage_counters",
+		Columns:    UsageCountersColumns,
+		PrimaryKey: []*schema.Column{UsageCountersColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "idx_usage_counters_customer_feature_period",
+				Unique:  true,
+				Columns: []*schema.Column{UsageCountersColumns[1], UsageCountersColumns[2], UsageCountersColumns[3], UsageCountersColumns[4], UsageCountersColumns[5]},
+			},
+		},
+	}
+	// UsageCounterEntriesColumns holds the columns for the "usage_counter_entries" table.
+	UsageCounterEntriesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "tenant_id", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "environment_id", Type: field.TypeString, Default: "", SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "event_id", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(255)"}},
+		{Name: "sub_line_item_id", Type: field.TypeString, Default: "", SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "customer_id", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "feature_id", Type: field.TypeString, SchemaType: map[string]string{"postgres": "varchar(50)"}},
+		{Name: "period_id", Type: field.TypeInt64},
+		{Name: "value", Type: field.TypeOther, SchemaType: map[string]string{"postgres": "numeric(25,15)"}},
+		{Name: "created_at", Type: field.TypeTime, SchemaType: map[string]string{"postgres": "timestamp"}},
+	}
+	// UsageCounterEntriesTable holds the schema information for the "usage_counter_entries" table.
+	UsageCounterEntriesTable = &schema.Table{
+		Name:       "usage_counter_entries",
+		Columns:    UsageCounterEntriesColumns,
+		PrimaryKey: []*schema.Column{UsageCounterEntriesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "idx_usage_counter_entries_event",
+				Unique:  true,
+				Columns: []*schema.Column{UsageCounterEntriesColumns[1], UsageCounterEntriesColumns[2], UsageCounterEntriesColumns[3], UsageCounterEntriesColumns[4]},
+			},
+		},
+	}
 	// UsersColumns holds the columns for the "users" table.
 	UsersColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeString, Unique: true, SchemaType: map[string]string{"postgres": "varchar(50)"}},
@@ -2145,6 +2256,7 @@ var (
 		AddonsTable,
 		AddonAssociationsTable,
 		AlertLogsTable,
+		AuditLogsTable,
 		AuthsTable,
 		BillingSequencesTable,
 		ConnectionsTable,
@@ -2160,6 +2272,7 @@ var (
 		EntitlementsTable,
 		EntityIntegrationMappingsTable,
 		EnvironmentsTable,
+		EventDedupKeysTable,
 		FeaturesTable,
 		GroupsTable,
 		InvoicesTable,
@@ -2183,6 +2296,8 @@ var (
 		TaxAssociationsTable,
 		TaxRatesTable,
 		TenantsTable,
+		UsageCountersTable,
+		UsageCounterEntriesTable,
 		UsersTable,
 		WalletsTable,
 		WalletTransactionsTable,
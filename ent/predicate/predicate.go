@@ -15,6 +15,9 @@ type AddonAssociation func(*sql.Selector)
 // AlertLogs is the predicate function for alertlogs builders.
 type AlertLogs func(*sql.Selector)
 
+// AuditLog is the predicate function for auditlog builders.
+type AuditLog func(*sql.Selector)
+
 // Auth is the predicate function for auth builders.
 type Auth func(*sql.Selector)
 
@@ -60,6 +63,9 @@ type EntityIntegrationMapping func(*sql.Selector)
 // Environment is the predicate function for environment builders.
 type Environment func(*sql.Selector)
 
+// EventDedupKey is the predicate function for eventdedupkey builders.
+type EventDedupKey func(*sql.Selector)
+
 // Feature is the predicate function for feature builders.
 type Feature func(*sql.Selector)
 
@@ -129,6 +135,12 @@ type TaxRate func(*sql.Selector)
 // Tenant is the predicate function for tenant builders.
 type Tenant func(*sql.Selector)
 
+// UsageCounter is the predicate function for usagecounter builders.
+type UsageCounter func(*sql.Selector)
+
+// UsageCounterEntry is the predicate function for usagecounterentry builders.
+type UsageCounterEntry func(*sql.Selector)
+
 // User is the predicate function for user builders.
 type User func(*sql.Selector)
 
@@ -94,8 +94,10 @@ type Price struct {
 	// EndDate holds the value of the "end_date" field.
 	EndDate *time.Time `json:"end_date,omitempty"`
 	// GroupID holds the value of the "group_id" field.
-	GroupID      *string `json:"group_id,omitempty"`
-	selectValues sql.SelectValues
+	GroupID *string `json:"group_id,omitempty"`
+	// DiscountPercent holds the value of the "discount_percent" field.
+	DiscountPercent *float64 `json:"discount_percent,omitempty"`
+	selectValues    sql.SelectValues
 }
 
 // scanValues returns the types for scanning values from sql.Rows.
@@ -105,7 +107,7 @@ func (*Price) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case price.FieldFilterValues, price.FieldTiers, price.FieldPriceUnitTiers, price.FieldTransformQuantity, price.FieldMetadata:
 			values[i] = new([]byte)
-		case price.FieldAmount, price.FieldPriceUnitAmount, price.FieldConversionRate:
+		case price.FieldAmount, price.FieldPriceUnitAmount, price.FieldConversionRate, price.FieldDiscountPercent:
 			values[i] = new(sql.NullFloat64)
 		case price.FieldBillingPeriodCount, price.FieldTrialPeriod:
 			values[i] = new(sql.NullInt64)
@@ -381,6 +383,13 @@ func (pr *Price) assignValues(columns []string, values []any) error {
 				pr.GroupID = new(string)
 				*pr.GroupID = value.String
 			}
+		case price.FieldDiscountPercent:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field discount_percent", values[i])
+			} else if value.Valid {
+				pr.DiscountPercent = new(float64)
+				*pr.DiscountPercent = value.Float64
+			}
 		default:
 			pr.selectValues.Set(columns[i], values[i])
 		}
@@ -548,6 +557,11 @@ func (pr *Price) String() string {
 		builder.WriteString("group_id=")
 		builder.WriteString(*v)
 	}
+	builder.WriteString(", ")
+	if v := pr.DiscountPercent; v != nil {
+		builder.WriteString("discount_percent=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -89,6 +89,8 @@ const (
 	FieldEndDate = "end_date"
 	// FieldGroupID holds the string denoting the group_id field in the database.
 	FieldGroupID = "group_id"
+	// FieldDiscountPercent holds the string denoting the discount_percent field in the database.
+	FieldDiscountPercent = "discount_percent"
 	// Table holds the table name of the price in the database.
 	Table = "prices"
 )
@@ -134,6 +136,7 @@ var Columns = []string{
 	FieldStartDate,
 	FieldEndDate,
 	FieldGroupID,
+	FieldDiscountPercent,
 }
 
 // ValidColumn reports if the column name is valid (part of the table columns).
@@ -357,3 +360,8 @@ func ByEndDate(opts ...sql.OrderTermOption) OrderOption {
 func ByGroupID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldGroupID, opts...).ToFunc()
 }
+
+// ByDiscountPercent orders the results by the discount_percent field.
+func ByDiscountPercent(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDiscountPercent, opts...).ToFunc()
+}
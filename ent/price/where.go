@@ -229,6 +229,11 @@ func GroupID(v string) predicate.Price {
 	return predicate.Price(sql.FieldEQ(FieldGroupID, v))
 }
 
+// DiscountPercent applies equality check predicate on the "discount_percent" field. It's identical to DiscountPercentEQ.
+func DiscountPercent(v float64) predicate.Price {
+	return predicate.Price(sql.FieldEQ(FieldDiscountPercent, v))
+}
+
 // TenantIDEQ applies the EQ predicate on the "tenant_id" field.
 func TenantIDEQ(v string) predicate.Price {
 	return predicate.Price(sql.FieldEQ(FieldTenantID, v))
@@ -2389,6 +2394,56 @@ func GroupIDContainsFold(v string) predicate.Price {
 	return predicate.Price(sql.FieldContainsFold(FieldGroupID, v))
 }
 
+// DiscountPercentEQ applies the EQ predicate on the "discount_percent" field.
+func DiscountPercentEQ(v float64) predicate.Price {
+	return predicate.Price(sql.FieldEQ(FieldDiscountPercent, v))
+}
+
+// DiscountPercentNEQ applies the NEQ predicate on the "discount_percent" field.
+func DiscountPercentNEQ(v float64) predicate.Price {
+	return predicate.Price(sql.FieldNEQ(FieldDiscountPercent, v))
+}
+
+// DiscountPercentIn applies the In predicate on the "discount_percent" field.
+func DiscountPercentIn(vs ...float64) predicate.Price {
+	return predicate.Price(sql.FieldIn(FieldDiscountPercent, vs...))
+}
+
+// DiscountPercentNotIn applies the NotIn predicate on the "discount_percent" field.
+func DiscountPercentNotIn(vs ...float64) predicate.Price {
+	return predicate.Price(sql.FieldNotIn(FieldDiscountPercent, vs...))
+}
+
+// DiscountPercentGT applies the GT predicate on the "discount_percent" field.
+func DiscountPercentGT(v float64) predicate.Price {
+	return predicate.Price(sql.FieldGT(FieldDiscountPercent, v))
+}
+
+// DiscountPercentGTE applies the GTE predicate on the "discount_percent" field.
+func DiscountPercentGTE(v float64) predicate.Price {
+	return predicate.Price(sql.FieldGTE(FieldDiscountPercent, v))
+}
+
+// DiscountPercentLT applies the LT predicate on the "discount_percent" field.
+func DiscountPercentLT(v float64) predicate.Price {
+	return predicate.Price(sql.FieldLT(FieldDiscountPercent, v))
+}
+
+// DiscountPercentLTE applies the LTE predicate on the "discount_percent" field.
+func DiscountPercentLTE(v float64) predicate.Price {
+	return predicate.Price(sql.FieldLTE(FieldDiscountPercent, v))
+}
+
+// DiscountPercentIsNil applies the IsNil predicate on the "discount_percent" field.
+func DiscountPercentIsNil() predicate.Price {
+	return predicate.Price(sql.FieldIsNull(FieldDiscountPercent))
+}
+
+// DiscountPercentNotNil applies the NotNil predicate on the "discount_percent" field.
+func DiscountPercentNotNil() predicate.Price {
+	return predicate.Price(sql.FieldNotNull(FieldDiscountPercent))
+}
+
 // And groups predicates with the AND operator between them.
 func And(predicates ...predicate.Price) predicate.Price {
 	return predicate.Price(sql.AndPredicates(predicates...))
@@ -449,6 +449,20 @@ func (pc *PriceCreate) SetNillableGroupID(s *string) *PriceCreate {
 	return pc
 }
 
+// SetDiscountPercent sets the "discount_percent" field.
+func (pc *PriceCreate) SetDiscountPercent(f float64) *PriceCreate {
+	pc.mutation.SetDiscountPercent(f)
+	return pc
+}
+
+// SetNillableDiscountPercent sets the "discount_percent" field if the given value is not nil.
+func (pc *PriceCreate) SetNillableDiscountPercent(f *float64) *PriceCreate {
+	if f != nil {
+		pc.SetDiscountPercent(*f)
+	}
+	return pc
+}
+
 // SetID sets the "id" field.
 func (pc *PriceCreate) SetID(s string) *PriceCreate {
 	pc.mutation.SetID(s)
@@ -800,6 +814,10 @@ func (pc *PriceCreate) createSpec() (*Price, *sqlgraph.CreateSpec) {
 		_spec.SetField(price.FieldGroupID, field.TypeString, value)
 		_node.GroupID = &value
 	}
+	if value, ok := pc.mutation.DiscountPercent(); ok {
+		_spec.SetField(price.FieldDiscountPercent, field.TypeFloat64, value)
+		_node.DiscountPercent = &value
+	}
 	return _node, _spec
 }
 
@@ -544,6 +544,33 @@ func (pu *PriceUpdate) ClearGroupID() *PriceUpdate {
 	return pu
 }
 
+// SetDiscountPercent sets the "discount_percent" field.
+func (pu *PriceUpdate) SetDiscountPercent(f float64) *PriceUpdate {
+	pu.mutation.ResetDiscountPercent()
+	pu.mutation.SetDiscountPercent(f)
+	return pu
+}
+
+// SetNillableDiscountPercent sets the "discount_percent" field if the given value is not nil.
+func (pu *PriceUpdate) SetNillableDiscountPercent(f *float64) *PriceUpdate {
+	if f != nil {
+		pu.SetDiscountPercent(*f)
+	}
+	return pu
+}
+
+// AddDiscountPercent adds f to the "discount_percent" field.
+func (pu *PriceUpdate) AddDiscountPercent(f float64) *PriceUpdate {
+	pu.mutation.AddDiscountPercent(f)
+	return pu
+}
+
+// ClearDiscountPercent clears the value of the "discount_percent" field.
+func (pu *PriceUpdate) ClearDiscountPercent() *PriceUpdate {
+	pu.mutation.ClearDiscountPercent()
+	return pu
+}
+
 // Mutation returns the PriceMutation object of the builder.
 func (pu *PriceUpdate) Mutation() *PriceMutation {
 	return pu.mutation
@@ -823,6 +850,15 @@ func (pu *PriceUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if pu.mutation.GroupIDCleared() {
 		_spec.ClearField(price.FieldGroupID, field.TypeString)
 	}
+	if value, ok := pu.mutation.DiscountPercent(); ok {
+		_spec.SetField(price.FieldDiscountPercent, field.TypeFloat64, value)
+	}
+	if value, ok := pu.mutation.AddedDiscountPercent(); ok {
+		_spec.AddField(price.FieldDiscountPercent, field.TypeFloat64, value)
+	}
+	if pu.mutation.DiscountPercentCleared() {
+		_spec.ClearField(price.FieldDiscountPercent, field.TypeFloat64)
+	}
 	if n, err = sqlgraph.UpdateNodes(ctx, pu.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{price.Label}
@@ -1357,6 +1393,33 @@ func (puo *PriceUpdateOne) ClearGroupID() *PriceUpdateOne {
 	return puo
 }
 
+// SetDiscountPercent sets the "discount_percent" field.
+func (puo *PriceUpdateOne) SetDiscountPercent(f float64) *PriceUpdateOne {
+	puo.mutation.ResetDiscountPercent()
+	puo.mutation.SetDiscountPercent(f)
+	return puo
+}
+
+// SetNillableDiscountPercent sets the "discount_percent" field if the given value is not nil.
+func (puo *PriceUpdateOne) SetNillableDiscountPercent(f *float64) *PriceUpdateOne {
+	if f != nil {
+		puo.SetDiscountPercent(*f)
+	}
+	return puo
+}
+
+// AddDiscountPercent adds f to the "discount_percent" field.
+func (puo *PriceUpdateOne) AddDiscountPercent(f float64) *PriceUpdateOne {
+	puo.mutation.AddDiscountPercent(f)
+	return puo
+}
+
+// ClearDiscountPercent clears the value of the "discount_percent" field.
+func (puo *PriceUpdateOne) ClearDiscountPercent() *PriceUpdateOne {
+	puo.mutation.ClearDiscountPercent()
+	return puo
+}
+
 // Mutation returns the PriceMutation object of the builder.
 func (puo *PriceUpdateOne) Mutation() *PriceMutation {
 	return puo.mutation
@@ -1666,6 +1729,15 @@ func (puo *PriceUpdateOne) sqlSave(ctx context.Context) (_node *Price, err error
 	if puo.mutation.GroupIDCleared() {
 		_spec.ClearField(price.FieldGroupID, field.TypeString)
 	}
+	if value, ok := puo.mutation.DiscountPercent(); ok {
+		_spec.SetField(price.FieldDiscountPercent, field.TypeFloat64, value)
+	}
+	if value, ok := puo.mutation.AddedDiscountPercent(); ok {
+		_spec.AddField(price.FieldDiscountPercent, field.TypeFloat64, value)
+	}
+	if puo.mutation.DiscountPercentCleared() {
+		_spec.ClearField(price.FieldDiscountPercent, field.TypeFloat64)
+	}
 	_node = &Price{config: puo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
@@ -8,6 +8,7 @@ import (
 	"github.com/flexprice/flexprice/ent/addon"
 	"github.com/flexprice/flexprice/ent/addonassociation"
 	"github.com/flexprice/flexprice/ent/alertlogs"
+	"github.com/flexprice/flexprice/ent/auditlog"
 	"github.com/flexprice/flexprice/ent/auth"
 	"github.com/flexprice/flexprice/ent/billingsequence"
 	"github.com/flexprice/flexprice/ent/connection"
@@ -23,6 +24,7 @@ import (
 	"github.com/flexprice/flexprice/ent/entitlement"
 	"github.com/flexprice/flexprice/ent/entityintegrationmapping"
 	"github.com/flexprice/flexprice/ent/environment"
+	"github.com/flexprice/flexprice/ent/eventdedupkey"
 	"github.com/flexprice/flexprice/ent/feature"
 	"github.com/flexprice/flexprice/ent/group"
 	"github.com/flexprice/flexprice/ent/invoice"
@@ -47,6 +49,8 @@ import (
 	"github.com/flexprice/flexprice/ent/taxassociation"
 	"github.com/flexprice/flexprice/ent/taxrate"
 	"github.com/flexprice/flexprice/ent/tenant"
+	"github.com/flexprice/flexprice/ent/usagecounter"
+	"github.com/flexprice/flexprice/ent/usagecounterentry"
 	"github.com/flexprice/flexprice/ent/user"
 	"github.com/flexprice/flexprice/ent/wallet"
 	"github.com/flexprice/flexprice/ent/wallettransaction"
@@ -195,6 +199,36 @@ func init() {
 	alertlogsDescAlertStatus := alertlogsFields[7].Descriptor()
 	// alertlogs.AlertStatusValidator is a validator for the "alert_status" field. It is called by the builders before save.
 	alertlogs.AlertStatusValidator = alertlogsDescAlertStatus.Validators[0].(func(string) error)
+	auditlogFields := schema.AuditLog{}.Fields()
+	_ = auditlogFields
+	// auditlogDescTenantID is the schema descriptor for tenant_id field.
+	auditlogDescTenantID := auditlogFields[1].Descriptor()
+	// auditlog.TenantIDValidator is a validator for the "tenant_id" field. It is called by the builders before save.
+	auditlog.TenantIDValidator = auditlogDescTenantID.Validators[0].(func(string) error)
+	// auditlogDescEnvironmentID is the schema descriptor for environment_id field.
+	auditlogDescEnvironmentID := auditlogFields[2].Descriptor()
+	// auditlog.DefaultEnvironmentID holds the default value on creation for the environment_id field.
+	auditlog.DefaultEnvironmentID = auditlogDescEnvironmentID.Default.(string)
+	// auditlogDescEntityType is the schema descriptor for entity_type field.
+	auditlogDescEntityType := auditlogFields[3].Descriptor()
+	// auditlog.EntityTypeValidator is a validator for the "entity_type" field. It is called by the builders before save.
+	auditlog.EntityTypeValidator = auditlogDescEntityType.Validators[0].(func(string) error)
+	// auditlogDescEntityID is the schema descriptor for entity_id field.
+	auditlogDescEntityID := auditlogFields[4].Descriptor()
+	// auditlog.EntityIDValidator is a validator for the "entity_id" field. It is called by the builders before save.
+	auditlog.EntityIDValidator = auditlogDescEntityID.Validators[0].(func(string) error)
+	// auditlogDescAction is the schema descriptor for action field.
+	auditlogDescAction := auditlogFields[5].Descriptor()
+	// auditlog.ActionValidator is a validator for the "action" field. It is called by the builders before save.
+	auditlog.ActionValidator = auditlogDescAction.Validators[0].(func(string) error)
+	// auditlogDescActor is the schema descriptor for actor field.
+	auditlogDescActor := auditlogFields[6].Descriptor()
+	// auditlog.DefaultActor holds the default value on creation for the actor field.
+	auditlog.DefaultActor = auditlogDescActor.Default.(string)
+	// auditlogDescCreatedAt is the schema descriptor for created_at field.
+	auditlogDescCreatedAt := auditlogFields[10].Descriptor()
+	// auditlog.DefaultCreatedAt holds the default value on creation for the created_at field.
+	auditlog.DefaultCreatedAt = auditlogDescCreatedAt.Default.(func() time.Time)
 	authFields := schema.Auth{}.Fields()
 	_ = authFields
 	// authDescProvider is the schema descriptor for provider field.
@@ -840,6 +874,24 @@ func init() {
 	environmentDescType := environmentFields[2].Descriptor()
 	// environment.TypeValidator is a validator for the "type" field. It is called by the builders before save.
 	environment.TypeValidator = environmentDescType.Validators[0].(func(string) error)
+	eventdedupkeyFields := schema.EventDedupKey{}.Fields()
+	_ = eventdedupkeyFields
+	// eventdedupkeyDescTenantID is the schema descriptor for tenant_id field.
+	eventdedupkeyDescTenantID := eventdedupkeyFields[0].Descriptor()
+	// eventdedupkey.TenantIDValidator is a validator for the "tenant_id" field. It is called by the builders before save.
+	eventdedupkey.TenantIDValidator = eventdedupkeyDescTenantID.Validators[0].(func(string) error)
+	// eventdedupkeyDescEnvironmentID is the schema descriptor for environment_id field.
+	eventdedupkeyDescEnvironmentID := eventdedupkeyFields[1].Descriptor()
+	// eventdedupkey.DefaultEnvironmentID holds the default value on creation for the environment_id field.
+	eventdedupkey.DefaultEnvironmentID = eventdedupkeyDescEnvironmentID.Default.(string)
+	// eventdedupkeyDescEventID is the schema descriptor for event_id field.
+	eventdedupkeyDescEventID := eventdedupkeyFields[2].Descriptor()
+	// eventdedupkey.EventIDValidator is a validator for the "event_id" field. It is called by the builders before save.
+	eventdedupkey.EventIDValidator = eventdedupkeyDescEventID.Validators[0].(func(string) error)
+	// eventdedupkeyDescCreatedAt is the schema descriptor for created_at field.
+	eventdedupkeyDescCreatedAt := eventdedupkeyFields[4].Descriptor()
+	// eventdedupkey.DefaultCreatedAt holds the default value on creation for the created_at field.
+	eventdedupkey.DefaultCreatedAt = eventdedupkeyDescCreatedAt.Default.(func() time.Time)
 	featureMixin := schema.Feature{}.Mixin()
 	featureMixinFields0 := featureMixin[0].Fields()
 	_ = featureMixinFields0
@@ -1125,6 +1177,14 @@ func init() {
 	meterDescResetUsage := meterFields[5].Descriptor()
 	// meter.DefaultResetUsage holds the default value on creation for the reset_usage field.
 	meter.DefaultResetUsage = meterDescResetUsage.Default.(string)
+	// meterDescBillingMode is the schema descriptor for billing_mode field.
+	meterDescBillingMode := meterFields[7].Descriptor()
+	// meter.DefaultBillingMode holds the default value on creation for the billing_mode field.
+	meter.DefaultBillingMode = meterDescBillingMode.Default.(string)
+	// meterDescEventNameMatch is the schema descriptor for event_name_match field.
+	meterDescEventNameMatch := meterFields[8].Descriptor()
+	// meter.DefaultEventNameMatch holds the default value on creation for the event_name_match field.
+	meter.DefaultEventNameMatch = meterDescEventNameMatch.Default.(string)
 	paymentMixin := schema.Payment{}.Mixin()
 	paymentMixinFields0 := paymentMixin[0].Fields()
 	_ = paymentMixinFields0
@@ -1646,6 +1706,10 @@ func init() {
 	subscriptionDescEnableTrueUp := subscriptionFields[31].Descriptor()
 	// subscription.DefaultEnableTrueUp holds the default value on creation for the enable_true_up field.
 	subscription.DefaultEnableTrueUp = subscriptionDescEnableTrueUp.Default.(bool)
+	// subscriptionDescUsageFrozen is the schema descriptor for usage_frozen field.
+	subscriptionDescUsageFrozen := subscriptionFields[33].Descriptor()
+	// subscription.DefaultUsageFrozen holds the default value on creation for the usage_frozen field.
+	subscription.DefaultUsageFrozen = subscriptionDescUsageFrozen.Default.(bool)
 	subscriptionlineitemMixin := schema.SubscriptionLineItem{}.Mixin()
 	subscriptionlineitemMixinFields0 := subscriptionlineitemMixin[0].Fields()
 	_ = subscriptionlineitemMixinFields0
@@ -2045,6 +2109,72 @@ func init() {
 	tenantDescBillingDetails := tenantFields[5].Descriptor()
 	// tenant.DefaultBillingDetails holds the default value on creation for the billing_details field.
 	tenant.DefaultBillingDetails = tenantDescBillingDetails.Default.(schema.TenantBillingDetails)
+	usagecounterFields := schema.UsageCounter{}.Fields()
+	_ = usagecounterFields
+	// usagecounterDescTenantID is the schema descriptor for tenant_id field.
+	usagecounterDescTenantID := usagecounterFields[0].Descriptor()
+	// usagecounter.TenantIDValidator is a validator for the "tenant_id" field. It is called by the builders before save.
+	usagecounter.TenantIDValidator = usagecounterDescTenantID.Validators[0].(func(string) error)
+	// usagecounterDescEnvironmentID is the schema descriptor for environment_id field.
+	usagecounterDescEnvironmentID := usagecounterFields[1].Descriptor()
+	// usagecounter.DefaultEnvironmentID holds the default value on creation for the environment_id field.
+	usagecounter.DefaultEnvironmentID = usagecounterDescEnvironmentID.Default.(string)
+	// usagecounterDescCustomerID is the schema descriptor for customer_id field.
+	usagecounterDescCustomerID := usagecounterFields[2].Descriptor()
+	// usagecounter.CustomerIDValidator is a validator for the "customer_id" field. It is called by the builders before save.
+	usagecounter.CustomerIDValidator = usagecounterDescCustomerID.Validators[0].(func(string) error)
+	// usagecounterDescFeatureID is the schema descriptor for feature_id field.
+	usagecounterDescFeatureID := usagecounterFields[3].Descriptor()
+	// usagecounter.FeatureIDValidator is a validator for the "feature_id" field. It is called by the builders before save.
+	usagecounter.FeatureIDValidator = usagecounterDescFeatureID.Validators[0].(func(string) error)
+	// usagecounterDescValue is the schema descriptor for value field.
+	usagecounterDescValue := usagecounterFields[5].Descriptor()
+	// usagecounter.DefaultValue holds the default value on creation for the value field.
+	usagecounter.DefaultValue = usagecounterDescValue.Default.(decimal.Decimal)
+	// usagecounterDescCreatedAt is the schema descriptor for created_at field.
+	usagecounterDescCreatedAt := usagecounterFields[6].Descriptor()
+	// usagecounter.DefaultCreatedAt holds the default value on creation for the created_at field.
+	usagecounter.DefaultCreatedAt = usagecounterDescCreatedAt.Default.(func() time.Time)
+	// usagecounterDescUpdatedAt is the schema descriptor for updated_at field.
+	usagecounterDescUpdatedAt := usagecounterFields[7].Descriptor()
+	// usagecounter.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	usagecounter.DefaultUpdatedAt = usagecounterDescUpdatedAt.Default.(func() time.Time)
+	// usagecounter.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	usagecounter.UpdateDefaultUpdatedAt = usagecounterDescUpdatedAt.UpdateDefault.(func() time.Time)
+	usagecounterentryFields := schema.UsageCounterEntry{}.Fields()
+	_ = usagecounterentryFields
+	// usagecounterentryDescTenantID is the schema descriptor for tenant_id field.
+	usagecounterentryDescTenantID := usagecounterentryFields[0].Descriptor()
+	// usagecounterentry.TenantIDValidator is a validator for the "tenant_id" field. It is called by the builders before save.
+	usagecounterentry.TenantIDValidator = usagecounterentryDescTenantID.Validators[0].(func(string) error)
+	// usagecounterentryDescEnvironmentID is the schema descriptor for environment_id field.
+	usagecounterentryDescEnvironmentID := usagecounterentryFields[1].Descriptor()
+	// usagecounterentry.DefaultEnvironmentID holds the default value on creation for the environment_id field.
+	usagecounterentry.DefaultEnvironmentID = usagecounterentryDescEnvironmentID.Default.(string)
+	// usagecounterentryDescEventID is the schema descriptor for event_id field.
+	usagecounterentryDescEventID := usagecounterentryFields[2].Descriptor()
+	// usagecounterentry.EventIDValidator is a validator for the "event_id" field. It is called by the builders before save.
+	usagecounterentry.EventIDValidator = usagecounterentryDescEventID.Validators[0].(func(string) error)
+	// usagecounterentryDescSubLineItemID is the schema descriptor for sub_line_item_id field.
+	usagecounterentryDescSubLineItemID := usagecounterentryFields[3].Descriptor()
+	// usagecounterentry.DefaultSubLineItemID holds the default value on creation for the sub_line_item_id field.
+	usagecounterentry.DefaultSubLineItemID = usagecounterentryDescSubLineItemID.Default.(string)
+	// usagecounterentryDescCustomerID is the schema descriptor for customer_id field.
+	usagecounterentryDescCustomerID := usagecounterentryFields[4].Descriptor()
+	// usagecounterentry.CustomerIDValidator is a validator for the "customer_id" field. It is called by the builders before save.
+	usagecounterentry.CustomerIDValidator = usagecounterentryDescCustomerID.Validators[0].(func(string) error)
+	// usagecounterentryDescFeatureID is the schema descriptor for feature_id field.
+	usagecounterentryDescFeatureID := usagecounterentryFields[5].Descriptor()
+	// usagecounterentry.FeatureIDValidator is a validator for the "feature_id" field. It is called by the builders before save.
+	usagecounterentry.FeatureIDValidator = usagecounterentryDescFeatureID.Validators[0].(func(string) error)
+	// usagecounterentryDescValue is the schema descriptor for value field.
+	usagecounterentryDescValue := usagecounterentryFields[7].Descriptor()
+	// usagecounterentry.DefaultValue holds the default value on creation for the value field.
+	usagecounterentry.DefaultValue = usagecounterentryDescValue.Default.(decimal.Decimal)
+	// usagecounterentryDescCreatedAt is the schema descriptor for created_at field.
+	usagecounterentryDescCreatedAt := usagecounterentryFields[8].Descriptor()
+	// usagecounterentry.DefaultCreatedAt holds the default value on creation for the created_at field.
+	usagecounterentry.DefaultCreatedAt = usagecounterentryDescCreatedAt.Default.(func() time.Time)
 	userMixin := schema.User{}.Mixin()
 	userMixinFields0 := userMixin[0].Fields()
 	_ = userMixinFields0
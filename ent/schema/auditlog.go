@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// AuditLog holds the schema definition for the AuditLog entity, the before and after of each
+// change to meters, prices and features. Audit logs are point-in-time records and never updated.
+type AuditLog struct {
+	ent.Schema
+}
+
+// Fields of the AuditLog.
+func (AuditLog) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			Unique().
+			Immutable(),
+		field.String("tenant_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			NotEmpty().
+			Immutable(),
+		field.String("environment_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			Default("").
+			Immutable(),
+		// Type of the entity changed (meter, price or feature)
+		field.String("entity_type").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			NotEmpty().
+			Immutable(),
+		field.String("entity_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			NotEmpty().
+			Immutable(),
+		// Change made to the entity (create, update or delete)
+		field.String("action").
+			SchemaType(map[string]string{
+				"postgres": "varchar(20)",
+			}).
+			NotEmpty().
+			Immutable(),
+		// ID of the user who made the change, empty for changes made by the system
+		field.String("actor").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			Default("").
+			Immutable(),
+		// Entity before the change, null when it was created
+		field.JSON("before", map[string]interface{}{}).
+			SchemaType(map[string]string{
+				"postgres": "jsonb",
+			}).
+			Optional().
+			Immutable(),
+		// Entity after the change, null when it was deleted
+		field.JSON("after", map[string]interface{}{}).
+			SchemaType(map[string]string{
+				"postgres": "jsonb",
+			}).
+			Optional().
+			Immutable(),
+		// Fields that differ between before and after, keyed by field name
+		field.JSON("changes", map[string]types.AuditLogChange{}).
+			SchemaType(map[string]string{
+				"postgres": "jsonb",
+			}).
+			Immutable(),
+		field.Time("created_at").
+			SchemaType(map[string]string{
+				"postgres": "timestamp",
+			}).
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the AuditLog.
+func (AuditLog) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the AuditLog.
+func (AuditLog) Indexes() []ent.Index {
+	return []ent.Index{
+		// Index for listing the audit logs of an entity, the latest first
+		index.Fields("tenant_id", "environment_id", "entity_type", "entity_id", "created_at").
+			StorageKey("idx_audit_logs_entity"),
+	}
+}
@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// EventDedupKey holds the schema definition for the EventDedupKey entity, the id of an event
+// processed for feature usage within the dedup window. Keys are shared by all consumers so a
+// redelivered event is skipped whichever consumer receives it.
+type EventDedupKey struct {
+	ent.Schema
+}
+
+// Fields of the EventDedupKey.
+func (EventDedupKey) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("tenant_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			NotEmpty().
+			Immutable(),
+		field.String("environment_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			Default("").
+			Immutable(),
+		field.String("event_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(255)",
+			}).
+			NotEmpty().
+			Immutable(),
+		// The key is free to be claimed again once it expires
+		field.Time("expires_at").
+			SchemaType(map[string]string{
+				"postgres": "timestamp",
+			}),
+		field.Time("created_at").
+			SchemaType(map[string]string{
+				"postgres": "timestamp",
+			}).
+			Default(time.Now),
+	}
+}
+
+// Edges of the EventDedupKey.
+func (EventDedupKey) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the EventDedupKey.
+func (EventDedupKey) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("tenant_id", "environment_id", "event_id").
+			Unique().
+			StorageKey("idx_event_dedup_keys_event"),
+		// Index for deleting the expired keys
+		index.Fields("expires_at").
+			StorageKey("idx_event_dedup_keys_expires_at"),
+	}
+}
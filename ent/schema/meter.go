@@ -53,6 +53,20 @@ func (Meter) Fields() []ent.Field {
 				"postgres": "varchar(20)",
 			}).
 			Default(string(types.ResetUsageBillingPeriod)),
+		field.Strings("subscription_statuses").
+			Optional(),
+		field.String("billing_mode").
+			SchemaType(map[string]string{
+				"postgres": "varchar(20)",
+			}).
+			Default(string(types.MeterBillingModeBill)),
+		field.String("event_name_match").
+			SchemaType(map[string]string{
+				"postgres": "varchar(20)",
+			}).
+			Default(string(types.MeterEventNameMatchExact)),
+		field.Strings("indexed_properties").
+			Optional(),
 	}
 }
 
@@ -71,8 +85,17 @@ type MeterFilter struct {
 
 // MeterAggregation defines the aggregation configuration for a meter
 type MeterAggregation struct {
-	Type       types.AggregationType `json:"type"`
-	Field      string                `json:"field,omitempty"`
-	Multiplier *decimal.Decimal      `json:"multiplier,omitempty"`
-	BucketSize types.WindowSize      `json:"bucket_size,omitempty"`
+	Type               types.AggregationType      `json:"type"`
+	Field              string                     `json:"field,omitempty"`
+	Multiplier         *decimal.Decimal           `json:"multiplier,omitempty"`
+	RateProperty       string                     `json:"rate_property,omitempty"`
+	RateTable          map[string]decimal.Decimal `json:"rate_table,omitempty"`
+	BucketSize         types.WindowSize           `json:"bucket_size,omitempty"`
+	Unit               types.ValueUnit            `json:"unit,omitempty"`
+	RollingWindowHours int                        `json:"rolling_window_hours,omitempty"`
+	FillGaps           types.MeterGapFillMode     `json:"fill_gaps,omitempty"`
+	MinEventValue      *decimal.Decimal           `json:"min_event_value,omitempty"`
+	MaxEventValue      *decimal.Decimal           `json:"max_event_value,omitempty"`
+	// HeartbeatIntervalSeconds is the interval heartbeats of UPTIME meters are sent at
+	HeartbeatIntervalSeconds int64 `json:"heartbeat_interval_seconds,omitempty"`
 }
@@ -210,6 +210,15 @@ func (Price) Fields() []ent.Field {
 			}).
 			Optional().
 			Nillable(),
+
+		// discount_percent is set on subscription override prices that inherit the tiers of their
+		// parent price and charge a percentage less than the parent's tier output
+		field.Float("discount_percent").
+			SchemaType(map[string]string{
+				"postgres": "numeric(5,2)",
+			}).
+			Optional().
+			Nillable(),
 	}
 }
 
@@ -160,6 +160,9 @@ func (Subscription) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			Comment("Customer ID to use for invoicing (can differ from the subscription customer)"),
+		field.Bool("usage_frozen").
+			Default(false).
+			Comment("Stop metering usage for the subscription without cancelling it"),
 	}
 }
 
@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/shopspring/decimal"
+)
+
+// UsageCounter holds the schema definition for the UsageCounter entity, the running usage of a
+// feature by a customer in a billing period.
+type UsageCounter struct {
+	ent.Schema
+}
+
+// Fields of the UsageCounter.
+func (UsageCounter) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("tenant_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			NotEmpty().
+			Immutable(),
+		field.String("environment_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			Default("").
+			Immutable(),
+		field.String("customer_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			NotEmpty().
+			Immutable(),
+		field.String("feature_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			NotEmpty().
+			Immutable(),
+		field.Int64("period_id").
+			Immutable(),
+		field.Other("value", decimal.Decimal{}).
+			SchemaType(map[string]string{
+				"postgres": "numeric(25,15)",
+			}).
+			Default(decimal.Zero),
+		field.Time("created_at").
+			SchemaType(map[string]string{
+				"postgres": "timestamp",
+			}).
+			Default(time.Now).
+			Immutable(),
+		field.Time("updated_at").
+			SchemaType(map[string]string{
+				"postgres": "timestamp",
+			}).
+			Default(time.Now).
+			UpdateDefault(time.Now),
+	}
+}
+
+// Edges of the UsageCounter.
+func (UsageCounter) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the UsageCounter.
+func (UsageCounter) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("tenant_id", "environment_id", "customer_id", "feature_id", "period_id").
+			Unique().
+			StorageKey("idx_usage_counters_customer_feature_period"),
+	}
+}
@@ -0,0 +1,88 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/shopspring/decimal"
+)
+
+// UsageCounterEntry holds the schema definition for the UsageCounterEntry entity, a feature usage
+// row added to a usage counter. Entries make the counters idempotent: a row is added once, and
+// cancelling it subtracts the value it added from the counter it was added to.
+type UsageCounterEntry struct {
+	ent.Schema
+}
+
+// Fields of the UsageCounterEntry.
+func (UsageCounterEntry) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("tenant_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			NotEmpty().
+			Immutable(),
+		field.String("environment_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			Default("").
+			Immutable(),
+		// ID of the event the feature usage row was tracked from
+		field.String("event_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(255)",
+			}).
+			NotEmpty().
+			Immutable(),
+		field.String("sub_line_item_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			Default("").
+			Immutable(),
+		field.String("customer_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			NotEmpty().
+			Immutable(),
+		field.String("feature_id").
+			SchemaType(map[string]string{
+				"postgres": "varchar(50)",
+			}).
+			NotEmpty().
+			Immutable(),
+		field.Int64("period_id").
+			Immutable(),
+		field.Other("value", decimal.Decimal{}).
+			SchemaType(map[string]string{
+				"postgres": "numeric(25,15)",
+			}).
+			Default(decimal.Zero).
+			Immutable(),
+		field.Time("created_at").
+			SchemaType(map[string]string{
+				"postgres": "timestamp",
+			}).
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the UsageCounterEntry.
+func (UsageCounterEntry) Edges() []ent.Edge {
+	return nil
+}
+
+// Indexes of the UsageCounterEntry.
+func (UsageCounterEntry) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("tenant_id", "environment_id", "event_id", "sub_line_item_id").
+			Unique().
+			StorageKey("idx_usage_counter_entries_event"),
+	}
+}
@@ -98,6 +98,8 @@ type Subscription struct {
 	EnableTrueUp bool `json:"enable_true_up,omitempty"`
 	// Customer ID to use for invoicing (can differ from the subscription customer)
 	InvoicingCustomerID *string `json:"invoicing_customer_id,omitempty"`
+	// Stop metering usage for the subscription without cancelling it
+	UsageFrozen bool `json:"usage_frozen,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the SubscriptionQuery when eager-loading is set.
 	Edges        SubscriptionEdges `json:"edges"`
@@ -199,7 +201,7 @@ func (*Subscription) scanValues(columns []string) ([]any, error) {
 			values[i] = &sql.NullScanner{S: new(decimal.Decimal)}
 		case subscription.FieldMetadata:
 			values[i] = new([]byte)
-		case subscription.FieldCancelAtPeriodEnd, subscription.FieldEnableTrueUp:
+		case subscription.FieldCancelAtPeriodEnd, subscription.FieldEnableTrueUp, subscription.FieldUsageFrozen:
 			values[i] = new(sql.NullBool)
 		case subscription.FieldBillingPeriodCount, subscription.FieldVersion:
 			values[i] = new(sql.NullInt64)
@@ -473,6 +475,12 @@ func (s *Subscription) assignValues(columns []string, values []any) error {
 				s.InvoicingCustomerID = new(string)
 				*s.InvoicingCustomerID = value.String
 			}
+		case subscription.FieldUsageFrozen:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field usage_frozen", values[i])
+			} else if value.Valid {
+				s.UsageFrozen = value.Bool
+			}
 		default:
 			s.selectValues.Set(columns[i], values[i])
 		}
@@ -678,6 +686,9 @@ func (s *Subscription) String() string {
 		builder.WriteString("invoicing_customer_id=")
 		builder.WriteString(*v)
 	}
+	builder.WriteString(", ")
+	builder.WriteString("usage_frozen=")
+	builder.WriteString(fmt.Sprintf("%v", s.UsageFrozen))
 	builder.WriteByte(')')
 	return builder.String()
 }
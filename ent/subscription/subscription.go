@@ -94,6 +94,8 @@ const (
 	FieldEnableTrueUp = "enable_true_up"
 	// FieldInvoicingCustomerID holds the string denoting the invoicing_customer_id field in the database.
 	FieldInvoicingCustomerID = "invoicing_customer_id"
+	// FieldUsageFrozen holds the string denoting the usage_frozen field in the database.
+	FieldUsageFrozen = "usage_frozen"
 	// EdgeLineItems holds the string denoting the line_items edge name in mutations.
 	EdgeLineItems = "line_items"
 	// EdgePauses holds the string denoting the pauses edge name in mutations.
@@ -203,6 +205,7 @@ var Columns = []string{
 	FieldProrationBehavior,
 	FieldEnableTrueUp,
 	FieldInvoicingCustomerID,
+	FieldUsageFrozen,
 }
 
 // ValidColumn reports if the column name is valid (part of the table columns).
@@ -270,6 +273,8 @@ var (
 	ProrationBehaviorValidator func(string) error
 	// DefaultEnableTrueUp holds the default value on creation for the "enable_true_up" field.
 	DefaultEnableTrueUp bool
+	// DefaultUsageFrozen holds the default value on creation for the "usage_frozen" field.
+	DefaultUsageFrozen bool
 )
 
 // PaymentBehavior defines the type for the "payment_behavior" enum field.
@@ -524,6 +529,11 @@ func ByInvoicingCustomerID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldInvoicingCustomerID, opts...).ToFunc()
 }
 
+// ByUsageFrozen orders the results by the usage_frozen field.
+func ByUsageFrozen(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldUsageFrozen, opts...).ToFunc()
+}
+
 // ByLineItemsCount orders the results by line_items count.
 func ByLineItemsCount(opts ...sql.OrderTermOption) OrderOption {
 	return func(s *sql.Selector) {
@@ -241,6 +241,11 @@ func EnableTrueUp(v bool) predicate.Subscription {
 	return predicate.Subscription(sql.FieldEQ(FieldEnableTrueUp, v))
 }
 
+// UsageFrozen applies equality check predicate on the "usage_frozen" field. It's identical to UsageFrozenEQ.
+func UsageFrozen(v bool) predicate.Subscription {
+	return predicate.Subscription(sql.FieldEQ(FieldUsageFrozen, v))
+}
+
 // InvoicingCustomerID applies equality check predicate on the "invoicing_customer_id" field. It's identical to InvoicingCustomerIDEQ.
 func InvoicingCustomerID(v string) predicate.Subscription {
 	return predicate.Subscription(sql.FieldEQ(FieldInvoicingCustomerID, v))
@@ -2291,6 +2296,16 @@ func InvoicingCustomerIDContainsFold(v string) predicate.Subscription {
 	return predicate.Subscription(sql.FieldContainsFold(FieldInvoicingCustomerID, v))
 }
 
+// UsageFrozenEQ applies the EQ predicate on the "usage_frozen" field.
+func UsageFrozenEQ(v bool) predicate.Subscription {
+	return predicate.Subscription(sql.FieldEQ(FieldUsageFrozen, v))
+}
+
+// UsageFrozenNEQ applies the NEQ predicate on the "usage_frozen" field.
+func UsageFrozenNEQ(v bool) predicate.Subscription {
+	return predicate.Subscription(sql.FieldNEQ(FieldUsageFrozen, v))
+}
+
 // HasLineItems applies the HasEdge predicate on the "line_items" edge.
 func HasLineItems() predicate.Subscription {
 	return predicate.Subscription(func(s *sql.Selector) {
@@ -518,6 +518,20 @@ func (sc *SubscriptionCreate) SetNillableInvoicingCustomerID(s *string) *Subscri
 	return sc
 }
 
+// SetUsageFrozen sets the "usage_frozen" field.
+func (sc *SubscriptionCreate) SetUsageFrozen(b bool) *SubscriptionCreate {
+	sc.mutation.SetUsageFrozen(b)
+	return sc
+}
+
+// SetNillableUsageFrozen sets the "usage_frozen" field if the given value is not nil.
+func (sc *SubscriptionCreate) SetNillableUsageFrozen(b *bool) *SubscriptionCreate {
+	if b != nil {
+		sc.SetUsageFrozen(*b)
+	}
+	return sc
+}
+
 // SetID sets the "id" field.
 func (sc *SubscriptionCreate) SetID(s string) *SubscriptionCreate {
 	sc.mutation.SetID(s)
@@ -734,6 +748,10 @@ func (sc *SubscriptionCreate) defaults() {
 		v := subscription.DefaultEnableTrueUp
 		sc.mutation.SetEnableTrueUp(v)
 	}
+	if _, ok := sc.mutation.UsageFrozen(); !ok {
+		v := subscription.DefaultUsageFrozen
+		sc.mutation.SetUsageFrozen(v)
+	}
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -860,6 +878,9 @@ func (sc *SubscriptionCreate) check() error {
 	if _, ok := sc.mutation.EnableTrueUp(); !ok {
 		return &ValidationError{Name: "enable_true_up", err: errors.New(`ent: missing required field "Subscription.enable_true_up"`)}
 	}
+	if _, ok := sc.mutation.UsageFrozen(); !ok {
+		return &ValidationError{Name: "usage_frozen", err: errors.New(`ent: missing required field "Subscription.usage_frozen"`)}
+	}
 	return nil
 }
 
@@ -1047,6 +1068,10 @@ func (sc *SubscriptionCreate) createSpec() (*Subscription, *sqlgraph.CreateSpec)
 		_spec.SetField(subscription.FieldEnableTrueUp, field.TypeBool, value)
 		_node.EnableTrueUp = value
 	}
+	if value, ok := sc.mutation.UsageFrozen(); ok {
+		_spec.SetField(subscription.FieldUsageFrozen, field.TypeBool, value)
+		_node.UsageFrozen = value
+	}
 	if nodes := sc.mutation.LineItemsIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -483,6 +483,20 @@ func (su *SubscriptionUpdate) ClearInvoicingCustomerID() *SubscriptionUpdate {
 	return su
 }
 
+// SetUsageFrozen sets the "usage_frozen" field.
+func (su *SubscriptionUpdate) SetUsageFrozen(b bool) *SubscriptionUpdate {
+	su.mutation.SetUsageFrozen(b)
+	return su
+}
+
+// SetNillableUsageFrozen sets the "usage_frozen" field if the given value is not nil.
+func (su *SubscriptionUpdate) SetNillableUsageFrozen(b *bool) *SubscriptionUpdate {
+	if b != nil {
+		su.SetUsageFrozen(*b)
+	}
+	return su
+}
+
 // AddLineItemIDs adds the "line_items" edge to the SubscriptionLineItem entity by IDs.
 func (su *SubscriptionUpdate) AddLineItemIDs(ids ...string) *SubscriptionUpdate {
 	su.mutation.AddLineItemIDs(ids...)
@@ -901,6 +915,9 @@ func (su *SubscriptionUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := su.mutation.EnableTrueUp(); ok {
 		_spec.SetField(subscription.FieldEnableTrueUp, field.TypeBool, value)
 	}
+	if value, ok := su.mutation.UsageFrozen(); ok {
+		_spec.SetField(subscription.FieldUsageFrozen, field.TypeBool, value)
+	}
 	if su.mutation.LineItemsCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -1667,6 +1684,20 @@ func (suo *SubscriptionUpdateOne) ClearInvoicingCustomerID() *SubscriptionUpdate
 	return suo
 }
 
+// SetUsageFrozen sets the "usage_frozen" field.
+func (suo *SubscriptionUpdateOne) SetUsageFrozen(b bool) *SubscriptionUpdateOne {
+	suo.mutation.SetUsageFrozen(b)
+	return suo
+}
+
+// SetNillableUsageFrozen sets the "usage_frozen" field if the given value is not nil.
+func (suo *SubscriptionUpdateOne) SetNillableUsageFrozen(b *bool) *SubscriptionUpdateOne {
+	if b != nil {
+		suo.SetUsageFrozen(*b)
+	}
+	return suo
+}
+
 // AddLineItemIDs adds the "line_items" edge to the SubscriptionLineItem entity by IDs.
 func (suo *SubscriptionUpdateOne) AddLineItemIDs(ids ...string) *SubscriptionUpdateOne {
 	suo.mutation.AddLineItemIDs(ids...)
@@ -2115,6 +2146,9 @@ func (suo *SubscriptionUpdateOne) sqlSave(ctx context.Context) (_node *Subscript
 	if value, ok := suo.mutation.EnableTrueUp(); ok {
 		_spec.SetField(subscription.FieldEnableTrueUp, field.TypeBool, value)
 	}
+	if value, ok := suo.mutation.UsageFrozen(); ok {
+		_spec.SetField(subscription.FieldUsageFrozen, field.TypeBool, value)
+	}
 	if suo.mutation.LineItemsCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -0,0 +1,186 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/flexprice/flexprice/ent/usagecounter"
+	"github.com/shopspring/decimal"
+)
+
+// UsageCounter is the model entity for the UsageCounter schema.
+type UsageCounter struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// TenantID holds the value of the "tenant_id" field.
+	TenantID string `json:"tenant_id,omitempty"`
+	// EnvironmentID holds the value of the "environment_id" field.
+	EnvironmentID string `json:"environment_id,omitempty"`
+	// CustomerID holds the value of the "customer_id" field.
+	CustomerID string `json:"customer_id,omitempty"`
+	// FeatureID holds the value of the "feature_id" field.
+	FeatureID string `json:"feature_id,omitempty"`
+	// PeriodID holds the value of the "period_id" field.
+	PeriodID int64 `json:"period_id,omitempty"`
+	// Value holds the value of the "value" field.
+	Value decimal.Decimal `json:"value,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*UsageCounter) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case usagecounter.FieldValue:
+			values[i] = new(decimal.Decimal)
+		case usagecounter.FieldID, usagecounter.FieldPeriodID:
+			values[i] = new(sql.NullInt64)
+		case usagecounter.FieldTenantID, usagecounter.FieldEnvironmentID, usagecounter.FieldCustomerID, usagecounter.FieldFeatureID:
+			values[i] = new(sql.NullString)
+		case usagecounter.FieldCreatedAt, usagecounter.FieldUpdatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the UsageCounter fields.
+func (uc *UsageCounter) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case usagecounter.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			uc.ID = int(value.Int64)
+		case usagecounter.FieldTenantID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field tenant_id", values[i])
+			} else if value.Valid {
+				uc.TenantID = value.String
+			}
+		case usagecounter.FieldEnvironmentID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field environment_id", values[i])
+			} else if value.Valid {
+				uc.EnvironmentID = value.String
+			}
+		case usagecounter.FieldCustomerID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field customer_id", values[i])
+			} else if value.Valid {
+				uc.CustomerID = value.String
+			}
+		case usagecounter.FieldFeatureID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field feature_id", values[i])
+			} else if value.Valid {
+				uc.FeatureID = value.String
+			}
+		case usagecounter.FieldPeriodID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field period_id", values[i])
+			} else if value.Valid {
+				uc.PeriodID = value.Int64
+			}
+		case usagecounter.FieldValue:
+			if value, ok := values[i].(*decimal.Decimal); !ok {
+				return fmt.Errorf("unexpected type %T for field value", values[i])
+			} else if value != nil {
+				uc.Value = *value
+			}
+		case usagecounter.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				uc.CreatedAt = value.Time
+			}
+		case usagecounter.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				uc.UpdatedAt = value.Time
+			}
+		default:
+			uc.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// GetValue returns the ent.Value that was dynamically selected and assigned to the UsageCounter.
+// This includes values selected through modifiers, order, etc.
+func (uc *UsageCounter) GetValue(name string) (ent.Value, error) {
+	return uc.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this UsageCounter.
+// Note that you need to call UsageCounter.Unwrap() before calling this method if this UsageCounter
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (uc *UsageCounter) Update() *UsageCounterUpdateOne {
+	return NewUsageCounterClient(uc.config).UpdateOne(uc)
+}
+
+// Unwrap unwraps the UsageCounter entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (uc *UsageCounter) Unwrap() *UsageCounter {
+	_tx, ok := uc.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: UsageCounter is not a transactional entity")
+	}
+	uc.config.driver = _tx.drv
+	return uc
+}
+
+// String implements the fmt.Stringer.
+func (uc *UsageCounter) String() string {
+	var builder strings.Builder
+	builder.WriteString("UsageCounter(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", uc.ID))
+	builder.WriteString("tenant_id=")
+	builder.WriteString(uc.TenantID)
+	builder.WriteString(", ")
+	builder.WriteString("environment_id=")
+	builder.WriteString(uc.EnvironmentID)
+	builder.WriteString(", ")
+	builder.WriteString("customer_id=")
+	builder.WriteString(uc.CustomerID)
+	builder.WriteString(", ")
+	builder.WriteString("feature_id=")
+	builder.WriteString(uc.FeatureID)
+	builder.WriteString(", ")
+	builder.WriteString("period_id=")
+	builder.WriteString(fmt.Sprintf("%v", uc.PeriodID))
+	builder.WriteString(", ")
+	builder.WriteString("value=")
+	builder.WriteString(fmt.Sprintf("%v", uc.Value))
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(uc.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(uc.UpdatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// UsageCounters is a parsable slice of UsageCounter.
+type UsageCounters []*UsageCounter
@@ -0,0 +1,345 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/flexprice/flexprice/ent/usagecounter"
+	"github.com/shopspring/decimal"
+)
+
+// UsageCounterCreate is the builder for creating a UsageCounter entity.
+type UsageCounterCreate struct {
+	config
+	mutation *UsageCounterMutation
+	hooks    []Hook
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (ucc *UsageCounterCreate) SetTenantID(s string) *UsageCounterCreate {
+	ucc.mutation.SetTenantID(s)
+	return ucc
+}
+
+// SetEnvironmentID sets the "environment_id" field.
+func (ucc *UsageCounterCreate) SetEnvironmentID(s string) *UsageCounterCreate {
+	ucc.mutation.SetEnvironmentID(s)
+	return ucc
+}
+
+// SetNillableEnvironmentID sets the "environment_id" field if the given value is not nil.
+func (ucc *UsageCounterCreate) SetNillableEnvironmentID(s *string) *UsageCounterCreate {
+	if s != nil {
+		ucc.SetEnvironmentID(*s)
+	}
+	return ucc
+}
+
+// SetCustomerID sets the "customer_id" field.
+func (ucc *UsageCounterCreate) SetCustomerID(s string) *UsageCounterCreate {
+	ucc.mutation.SetCustomerID(s)
+	return ucc
+}
+
+// SetFeatureID sets the "feature_id" field.
+func (ucc *UsageCounterCreate) SetFeatureID(s string) *UsageCounterCreate {
+	ucc.mutation.SetFeatureID(s)
+	return ucc
+}
+
+// SetPeriodID sets the "period_id" field.
+func (ucc *UsageCounterCreate) SetPeriodID(i int64) *UsageCounterCreate {
+	ucc.mutation.SetPeriodID(i)
+	return ucc
+}
+
+// SetValue sets the "value" field.
+func (ucc *UsageCounterCreate) SetValue(d decimal.Decimal) *UsageCounterCreate {
+	ucc.mutation.SetValue(d)
+	return ucc
+}
+
+// SetNillableValue sets the "value" field if the given value is not nil.
+func (ucc *UsageCounterCreate) SetNillableValue(d *decimal.Decimal) *UsageCounterCreate {
+	if d != nil {
+		ucc.SetValue(*d)
+	}
+	return ucc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (ucc *UsageCounterCreate) SetCreatedAt(t time.Time) *UsageCounterCreate {
+	ucc.mutation.SetCreatedAt(t)
+	return ucc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (ucc *UsageCounterCreate) SetNillableCreatedAt(t *time.Time) *UsageCounterCreate {
+	if t != nil {
+		ucc.SetCreatedAt(*t)
+	}
+	return ucc
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (ucc *UsageCounterCreate) SetUpdatedAt(t time.Time) *UsageCounterCreate {
+	ucc.mutation.SetUpdatedAt(t)
+	return ucc
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (ucc *UsageCounterCreate) SetNillableUpdatedAt(t *time.Time) *UsageCounterCreate {
+	if t != nil {
+		ucc.SetUpdatedAt(*t)
+	}
+	return ucc
+}
+
+// Mutation returns the UsageCounterMutation object of the builder.
+func (ucc *UsageCounterCreate) Mutation() *UsageCounterMutation {
+	return ucc.mutation
+}
+
+// Save creates the UsageCounter in the database.
+func (ucc *UsageCounterCreate) Save(ctx context.Context) (*UsageCounter, error) {
+	ucc.defaults()
+	return withHooks(ctx, ucc.sqlSave, ucc.mutation, ucc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (ucc *UsageCounterCreate) SaveX(ctx context.Context) *UsageCounter {
+	v, err := ucc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ucc *UsageCounterCreate) Exec(ctx context.Context) error {
+	_, err := ucc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ucc *UsageCounterCreate) ExecX(ctx context.Context) {
+	if err := ucc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ucc *UsageCounterCreate) defaults() {
+	if _, ok := ucc.mutation.EnvironmentID(); !ok {
+		v := usagecounter.DefaultEnvironmentID
+		ucc.mutation.SetEnvironmentID(v)
+	}
+	if _, ok := ucc.mutation.Value(); !ok {
+		v := usagecounter.DefaultValue
+		ucc.mutation.SetValue(v)
+	}
+	if _, ok := ucc.mutation.CreatedAt(); !ok {
+		v := usagecounter.DefaultCreatedAt()
+		ucc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := ucc.mutation.UpdatedAt(); !ok {
+		v := usagecounter.DefaultUpdatedAt()
+		ucc.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ucc *UsageCounterCreate) check() error {
+	if _, ok := ucc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant_id", err: errors.New(`ent: missing required field "UsageCounter.tenant_id"`)}
+	}
+	if v, ok := ucc.mutation.TenantID(); ok {
+		if err := usagecounter.TenantIDValidator(v); err != nil {
+			return &ValidationError{Name: "tenant_id", err: fmt.Errorf(`ent: validator failed for field "UsageCounter.tenant_id": %w`, err)}
+		}
+	}
+	if _, ok := ucc.mutation.EnvironmentID(); !ok {
+		return &ValidationError{Name: "environment_id", err: errors.New(`ent: missing required field "UsageCounter.environment_id"`)}
+	}
+	if _, ok := ucc.mutation.CustomerID(); !ok {
+		return &ValidationError{Name: "customer_id", err: errors.New(`ent: missing required field "UsageCounter.customer_id"`)}
+	}
+	if v, ok := ucc.mutation.CustomerID(); ok {
+		if err := usagecounter.CustomerIDValidator(v); err != nil {
+			return &ValidationError{Name: "customer_id", err: fmt.Errorf(`ent: validator failed for field "UsageCounter.customer_id": %w`, err)}
+		}
+	}
+	if _, ok := ucc.mutation.FeatureID(); !ok {
+		return &ValidationError{Name: "feature_id", err: errors.New(`ent: missing required field "UsageCounter.feature_id"`)}
+	}
+	if v, ok := ucc.mutation.FeatureID(); ok {
+		if err := usagecounter.FeatureIDValidator(v); err != nil {
+			return &ValidationError{Name: "feature_id", err: fmt.Errorf(`ent: validator failed for field "UsageCounter.feature_id": %w`, err)}
+		}
+	}
+	if _, ok := ucc.mutation.PeriodID(); !ok {
+		return &ValidationError{Name: "period_id", err: errors.New(`ent: missing required field "UsageCounter.period_id"`)}
+	}
+	if _, ok := ucc.mutation.Value(); !ok {
+		return &ValidationError{Name: "value", err: errors.New(`ent: missing required field "UsageCounter.value"`)}
+	}
+	if _, ok := ucc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "UsageCounter.created_at"`)}
+	}
+	if _, ok := ucc.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "UsageCounter.updated_at"`)}
+	}
+	return nil
+}
+
+func (ucc *UsageCounterCreate) sqlSave(ctx context.Context) (*UsageCounter, error) {
+	if err := ucc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := ucc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, ucc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	ucc.mutation.id = &_node.ID
+	ucc.mutation.done = true
+	return _node, nil
+}
+
+func (ucc *UsageCounterCreate) createSpec() (*UsageCounter, *sqlgraph.CreateSpec) {
+	var (
+		_node = &UsageCounter{config: ucc.config}
+		_spec = sqlgraph.NewCreateSpec(usagecounter.Table, sqlgraph.NewFieldSpec(usagecounter.FieldID, field.TypeInt))
+	)
+	if value, ok := ucc.mutation.TenantID(); ok {
+		_spec.SetField(usagecounter.FieldTenantID, field.TypeString, value)
+		_node.TenantID = value
+	}
+	if value, ok := ucc.mutation.EnvironmentID(); ok {
+		_spec.SetField(usagecounter.FieldEnvironmentID, field.TypeString, value)
+		_node.EnvironmentID = value
+	}
+	if value, ok := ucc.mutation.CustomerID(); ok {
+		_spec.SetField(usagecounter.FieldCustomerID, field.TypeString, value)
+		_node.CustomerID = value
+	}
+	if value, ok := ucc.mutation.FeatureID(); ok {
+		_spec.SetField(usagecounter.FieldFeatureID, field.TypeString, value)
+		_node.FeatureID = value
+	}
+	if value, ok := ucc.mutation.PeriodID(); ok {
+		_spec.SetField(usagecounter.FieldPeriodID, field.TypeInt64, value)
+		_node.PeriodID = value
+	}
+	if value, ok := ucc.mutation.Value(); ok {
+		_spec.SetField(usagecounter.FieldValue, field.TypeOther, value)
+		_node.Value = value
+	}
+	if value, ok := ucc.mutation.CreatedAt(); ok {
+		_spec.SetField(usagecounter.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := ucc.mutation.UpdatedAt(); ok {
+		_spec.SetField(usagecounter.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	return _node, _spec
+}
+
+// UsageCounterCreateBulk is the builder for creating many UsageCounter entities in bulk.
+type UsageCounterCreateBulk struct {
+	config
+	err      error
+	builders []*UsageCounterCreate
+}
+
+// Save creates the UsageCounter entities in the database.
+func (uccb *UsageCounterCreateBulk) Save(ctx context.Context) ([]*UsageCounter, error) {
+	if uccb.err != nil {
+		return nil, uccb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(uccb.builders))
+	nodes := make([]*UsageCounter, len(uccb.builders))
+	mutators := make([]Mutator, len(uccb.builders))
+	for i := range uccb.builders {
+		func(i int, root context.Context) {
+			builder := uccb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*UsageCounterMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, uccb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, uccb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, uccb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (uccb *UsageCounterCreateBulk) SaveX(ctx context.Context) []*UsageCounter {
+	v, err := uccb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (uccb *UsageCounterCreateBulk) Exec(ctx context.Context) error {
+	_, err := uccb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (uccb *UsageCounterCreateBulk) ExecX(ctx context.Context) {
+	if err := uccb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,247 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/flexprice/flexprice/ent/predicate"
+	"github.com/flexprice/flexprice/ent/usagecounter"
+	"github.com/shopspring/decimal"
+)
+
+// UsageCounterUpdate is the builder for updating UsageCounter entities.
+type UsageCounterUpdate struct {
+	config
+	hooks    []Hook
+	mutation *UsageCounterMutation
+}
+
+// Where appends a list predicates to the UsageCounterUpdate builder.
+func (ucu *UsageCounterUpdate) Where(ps ...predicate.UsageCounter) *UsageCounterUpdate {
+	ucu.mutation.Where(ps...)
+	return ucu
+}
+
+// SetValue sets the "value" field.
+func (ucu *UsageCounterUpdate) SetValue(d decimal.Decimal) *UsageCounterUpdate {
+	ucu.mutation.SetValue(d)
+	return ucu
+}
+
+// SetNillableValue sets the "value" field if the given value is not nil.
+func (ucu *UsageCounterUpdate) SetNillableValue(d *decimal.Decimal) *UsageCounterUpdate {
+	if d != nil {
+		ucu.SetValue(*d)
+	}
+	return ucu
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (ucu *UsageCounterUpdate) SetUpdatedAt(t time.Time) *UsageCounterUpdate {
+	ucu.mutation.SetUpdatedAt(t)
+	return ucu
+}
+
+// Mutation returns the UsageCounterMutation object of the builder.
+func (ucu *UsageCounterUpdate) Mutation() *UsageCounterMutation {
+	return ucu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (ucu *UsageCounterUpdate) Save(ctx context.Context) (int, error) {
+	ucu.defaults()
+	return withHooks(ctx, ucu.sqlSave, ucu.mutation, ucu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ucu *UsageCounterUpdate) SaveX(ctx context.Context) int {
+	affected, err := ucu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (ucu *UsageCounterUpdate) Exec(ctx context.Context) error {
+	_, err := ucu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ucu *UsageCounterUpdate) ExecX(ctx context.Context) {
+	if err := ucu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ucu *UsageCounterUpdate) defaults() {
+	if _, ok := ucu.mutation.UpdatedAt(); !ok {
+		v := usagecounter.UpdateDefaultUpdatedAt()
+		ucu.mutation.SetUpdatedAt(v)
+	}
+}
+
+func (ucu *UsageCounterUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(usagecounter.Table, usagecounter.Columns, sqlgraph.NewFieldSpec(usagecounter.FieldID, field.TypeInt))
+	if ps := ucu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ucu.mutation.Value(); ok {
+		_spec.SetField(usagecounter.FieldValue, field.TypeOther, value)
+	}
+	if value, ok := ucu.mutation.UpdatedAt(); ok {
+		_spec.SetField(usagecounter.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, ucu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{usagecounter.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	ucu.mutation.done = true
+	return n, nil
+}
+
+// UsageCounterUpdateOne is the builder for updating a single UsageCounter entity.
+type UsageCounterUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *UsageCounterMutation
+}
+
+// SetValue sets the "value" field.
+func (ucuo *UsageCounterUpdateOne) SetValue(d decimal.Decimal) *UsageCounterUpdateOne {
+	ucuo.mutation.SetValue(d)
+	return ucuo
+}
+
+// SetNillableValue sets the "value" field if the given value is not nil.
+func (ucuo *UsageCounterUpdateOne) SetNillableValue(d *decimal.Decimal) *UsageCounterUpdateOne {
+	if d != nil {
+		ucuo.SetValue(*d)
+	}
+	return ucuo
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (ucuo *UsageCounterUpdateOne) SetUpdatedAt(t time.Time) *UsageCounterUpdateOne {
+	ucuo.mutation.SetUpdatedAt(t)
+	return ucuo
+}
+
+// Mutation returns the UsageCounterMutation object of the builder.
+func (ucuo *UsageCounterUpdateOne) Mutation() *UsageCounterMutation {
+	return ucuo.mutation
+}
+
+// Where appends a list predicates to the UsageCounterUpdate builder.
+func (ucuo *UsageCounterUpdateOne) Where(ps ...predicate.UsageCounter) *UsageCounterUpdateOne {
+	ucuo.mutation.Where(ps...)
+	return ucuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (ucuo *UsageCounterUpdateOne) Select(field string, fields ...string) *UsageCounterUpdateOne {
+	ucuo.fields = append([]string{field}, fields...)
+	return ucuo
+}
+
+// Save executes the query and returns the updated UsageCounter entity.
+func (ucuo *UsageCounterUpdateOne) Save(ctx context.Context) (*UsageCounter, error) {
+	ucuo.defaults()
+	return withHooks(ctx, ucuo.sqlSave, ucuo.mutation, ucuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ucuo *UsageCounterUpdateOne) SaveX(ctx context.Context) *UsageCounter {
+	node, err := ucuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (ucuo *UsageCounterUpdateOne) Exec(ctx context.Context) error {
+	_, err := ucuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ucuo *UsageCounterUpdateOne) ExecX(ctx context.Context) {
+	if err := ucuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ucuo *UsageCounterUpdateOne) defaults() {
+	if _, ok := ucuo.mutation.UpdatedAt(); !ok {
+		v := usagecounter.UpdateDefaultUpdatedAt()
+		ucuo.mutation.SetUpdatedAt(v)
+	}
+}
+
+func (ucuo *UsageCounterUpdateOne) sqlSave(ctx context.Context) (_node *UsageCounter, err error) {
+	_spec := sqlgraph.NewUpdateSpec(usagecounter.Table, usagecounter.Columns, sqlgraph.NewFieldSpec(usagecounter.FieldID, field.TypeInt))
+	id, ok := ucuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "UsageCounter.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := ucuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, usagecounter.FieldID)
+		for _, f := range fields {
+			if !usagecounter.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != usagecounter.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := ucuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ucuo.mutation.Value(); ok {
+		_spec.SetField(usagecounter.FieldValue, field.TypeOther, value)
+	}
+	if value, ok := ucuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(usagecounter.FieldUpdatedAt, field.TypeTime, value)
+	}
+	_node = &UsageCounter{config: ucuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, ucuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{usagecounter.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	ucuo.mutation.done = true
+	return _node, nil
+}
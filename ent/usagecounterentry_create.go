@@ -0,0 +1,363 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/flexprice/flexprice/ent/usagecounterentry"
+	"github.com/shopspring/decimal"
+)
+
+// UsageCounterEntryCreate is the builder for creating a UsageCounterEntry entity.
+type UsageCounterEntryCreate struct {
+	config
+	mutation *UsageCounterEntryMutation
+	hooks    []Hook
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (ucec *UsageCounterEntryCreate) SetTenantID(s string) *UsageCounterEntryCreate {
+	ucec.mutation.SetTenantID(s)
+	return ucec
+}
+
+// SetEnvironmentID sets the "environment_id" field.
+func (ucec *UsageCounterEntryCreate) SetEnvironmentID(s string) *UsageCounterEntryCreate {
+	ucec.mutation.SetEnvironmentID(s)
+	return ucec
+}
+
+// SetNillableEnvironmentID sets the "environment_id" field if the given value is not nil.
+func (ucec *UsageCounterEntryCreate) SetNillableEnvironmentID(s *string) *UsageCounterEntryCreate {
+	if s != nil {
+		ucec.SetEnvironmentID(*s)
+	}
+	return ucec
+}
+
+// SetEventID sets the "event_id" field.
+func (ucec *UsageCounterEntryCreate) SetEventID(s string) *UsageCounterEntryCreate {
+	ucec.mutation.SetEventID(s)
+	return ucec
+}
+
+// SetSubLineItemID sets the "sub_line_item_id" field.
+func (ucec *UsageCounterEntryCreate) SetSubLineItemID(s string) *UsageCounterEntryCreate {
+	ucec.mutation.SetSubLineItemID(s)
+	return ucec
+}
+
+// SetNillableSubLineItemID sets the "sub_line_item_id" field if the given value is not nil.
+func (ucec *UsageCounterEntryCreate) SetNillableSubLineItemID(s *string) *UsageCounterEntryCreate {
+	if s != nil {
+		ucec.SetSubLineItemID(*s)
+	}
+	return ucec
+}
+
+// SetCustomerID sets the "customer_id" field.
+func (ucec *UsageCounterEntryCreate) SetCustomerID(s string) *UsageCounterEntryCreate {
+	ucec.mutation.SetCustomerID(s)
+	return ucec
+}
+
+// SetFeatureID sets the "feature_id" field.
+func (ucec *UsageCounterEntryCreate) SetFeatureID(s string) *UsageCounterEntryCreate {
+	ucec.mutation.SetFeatureID(s)
+	return ucec
+}
+
+// SetPeriodID sets the "period_id" field.
+func (ucec *UsageCounterEntryCreate) SetPeriodID(i int64) *UsageCounterEntryCreate {
+	ucec.mutation.SetPeriodID(i)
+	return ucec
+}
+
+// SetValue sets the "value" field.
+func (ucec *UsageCounterEntryCreate) SetValue(d decimal.Decimal) *UsageCounterEntryCreate {
+	ucec.mutation.SetValue(d)
+	return ucec
+}
+
+// SetNillableValue sets the "value" field if the given value is not nil.
+func (ucec *UsageCounterEntryCreate) SetNillableValue(d *decimal.Decimal) *UsageCounterEntryCreate {
+	if d != nil {
+		ucec.SetValue(*d)
+	}
+	return ucec
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (ucec *UsageCounterEntryCreate) SetCreatedAt(t time.Time) *UsageCounterEntryCreate {
+	ucec.mutation.SetCreatedAt(t)
+	return ucec
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (ucec *UsageCounterEntryCreate) SetNillableCreatedAt(t *time.Time) *UsageCounterEntryCreate {
+	if t != nil {
+		ucec.SetCreatedAt(*t)
+	}
+	return ucec
+}
+
+// Mutation returns the UsageCounterEntryMutation object of the builder.
+func (ucec *UsageCounterEntryCreate) Mutation() *UsageCounterEntryMutation {
+	return ucec.mutation
+}
+
+// Save creates the UsageCounterEntry in the database.
+func (ucec *UsageCounterEntryCreate) Save(ctx context.Context) (*UsageCounterEntry, error) {
+	ucec.defaults()
+	return withHooks(ctx, ucec.sqlSave, ucec.mutation, ucec.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (ucec *UsageCounterEntryCreate) SaveX(ctx context.Context) *UsageCounterEntry {
+	v, err := ucec.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ucec *UsageCounterEntryCreate) Exec(ctx context.Context) error {
+	_, err := ucec.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ucec *UsageCounterEntryCreate) ExecX(ctx context.Context) {
+	if err := ucec.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ucec *UsageCounterEntryCreate) defaults() {
+	if _, ok := ucec.mutation.EnvironmentID(); !ok {
+		v := usagecounterentry.DefaultEnvironmentID
+		ucec.mutation.SetEnvironmentID(v)
+	}
+	if _, ok := ucec.mutation.SubLineItemID(); !ok {
+		v := usagecounterentry.DefaultSubLineItemID
+		ucec.mutation.SetSubLineItemID(v)
+	}
+	if _, ok := ucec.mutation.Value(); !ok {
+		v := usagecounterentry.DefaultValue
+		ucec.mutation.SetValue(v)
+	}
+	if _, ok := ucec.mutation.CreatedAt(); !ok {
+		v := usagecounterentry.DefaultCreatedAt()
+		ucec.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ucec *UsageCounterEntryCreate) check() error {
+	if _, ok := ucec.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant_id", err: errors.New(`ent: missing required field "UsageCounterEntry.tenant_id"`)}
+	}
+	if v, ok := ucec.mutation.TenantID(); ok {
+		if err := usagecounterentry.TenantIDValidator(v); err != nil {
+			return &ValidationError{Name: "tenant_id", err: fmt.Errorf(`ent: validator failed for field "UsageCounterEntry.tenant_id": %w`, err)}
+		}
+	}
+	if _, ok := ucec.mutation.EnvironmentID(); !ok {
+		return &ValidationError{Name: "environment_id", err: errors.New(`ent: missing required field "UsageCounterEntry.environment_id"`)}
+	}
+	if _, ok := ucec.mutation.EventID(); !ok {
+		return &ValidationError{Name: "event_id", err: errors.New(`ent: missing required field "UsageCounterEntry.event_id"`)}
+	}
+	if v, ok := ucec.mutation.EventID(); ok {
+		if err := usagecounterentry.EventIDValidator(v); err != nil {
+			return &ValidationError{Name: "event_id", err: fmt.Errorf(`ent: validator failed for field "UsageCounterEntry.event_id": %w`, err)}
+		}
+	}
+	if _, ok := ucec.mutation.SubLineItemID(); !ok {
+		return &ValidationError{Name: "sub_line_item_id", err: errors.New(`ent: missing required field "UsageCounterEntry.sub_line_item_id"`)}
+	}
+	if _, ok := ucec.mutation.CustomerID(); !ok {
+		return &ValidationError{Name: "customer_id", err: errors.New(`ent: missing required field "UsageCounterEntry.customer_id"`)}
+	}
+	if v, ok := ucec.mutation.CustomerID(); ok {
+		if err := usagecounterentry.CustomerIDValidator(v); err != nil {
+			return &ValidationError{Name: "customer_id", err: fmt.Errorf(`ent: validator failed for field "UsageCounterEntry.customer_id": %w`, err)}
+		}
+	}
+	if _, ok := ucec.mutation.FeatureID(); !ok {
+		return &ValidationError{Name: "feature_id", err: errors.New(`ent: missing required field "UsageCounterEntry.feature_id"`)}
+	}
+	if v, ok := ucec.mutation.FeatureID(); ok {
+		if err := usagecounterentry.FeatureIDValidator(v); err != nil {
+			return &ValidationError{Name: "feature_id", err: fmt.Errorf(`ent: validator failed for field "UsageCounterEntry.feature_id": %w`, err)}
+		}
+	}
+	if _, ok := ucec.mutation.PeriodID(); !ok {
+		return &ValidationError{Name: "period_id", err: errors.New(`ent: missing required field "UsageCounterEntry.period_id"`)}
+	}
+	if _, ok := ucec.mutation.Value(); !ok {
+		return &ValidationError{Name: "value", err: errors.New(`ent: missing required field "UsageCounterEntry.value"`)}
+	}
+	if _, ok := ucec.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "UsageCounterEntry.created_at"`)}
+	}
+	return nil
+}
+
+func (ucec *UsageCounterEntryCreate) sqlSave(ctx context.Context) (*UsageCounterEntry, error) {
+	if err := ucec.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := ucec.createSpec()
+	if err := sqlgraph.CreateNode(ctx, ucec.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	ucec.mutation.id = &_node.ID
+	ucec.mutation.done = true
+	return _node, nil
+}
+
+func (ucec *UsageCounterEntryCreate) createSpec() (*UsageCounterEntry, *sqlgraph.CreateSpec) {
+	var (
+		_node = &UsageCounterEntry{config: ucec.config}
+		_spec = sqlgraph.NewCreateSpec(usagecounterentry.Table, sqlgraph.NewFieldSpec(usagecounterentry.FieldID, field.TypeInt))
+	)
+	if value, ok := ucec.mutation.TenantID(); ok {
+		_spec.SetField(usagecounterentry.FieldTenantID, field.TypeString, value)
+		_node.TenantID = value
+	}
+	if value, ok := ucec.mutation.EnvironmentID(); ok {
+		_spec.SetField(usagecounterentry.FieldEnvironmentID, field.TypeString, value)
+		_node.EnvironmentID = value
+	}
+	if value, ok := ucec.mutation.EventID(); ok {
+		_spec.SetField(usagecounterentry.FieldEventID, field.TypeString, value)
+		_node.EventID = value
+	}
+	if value, ok := ucec.mutation.SubLineItemID(); ok {
+		_spec.SetField(usagecounterentry.FieldSubLineItemID, field.TypeString, value)
+		_node.SubLineItemID = value
+	}
+	if value, ok := ucec.mutation.CustomerID(); ok {
+		_spec.SetField(usagecounterentry.FieldCustomerID, field.TypeString, value)
+		_node.CustomerID = value
+	}
+	if value, ok := ucec.mutation.FeatureID(); ok {
+		_spec.SetField(usagecounterentry.FieldFeatureID, field.TypeString, value)
+		_node.FeatureID = value
+	}
+	if value, ok := ucec.mutation.PeriodID(); ok {
+		_spec.SetField(usagecounterentry.FieldPeriodID, field.TypeInt64, value)
+		_node.PeriodID = value
+	}
+	if value, ok := ucec.mutation.Value(); ok {
+		_spec.SetField(usagecounterentry.FieldValue, field.TypeOther, value)
+		_node.Value = value
+	}
+	if value, ok := ucec.mutation.CreatedAt(); ok {
+		_spec.SetField(usagecounterentry.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	return _node, _spec
+}
+
+// UsageCounterEntryCreateBulk is the builder for creating many UsageCounterEntry entities in bulk.
+type UsageCounterEntryCreateBulk struct {
+	config
+	err      error
+	builders []*UsageCounterEntryCreate
+}
+
+// Save creates the UsageCounterEntry entities in the database.
+func (ucecb *UsageCounterEntryCreateBulk) Save(ctx context.Context) ([]*UsageCounterEntry, error) {
+	if ucecb.err != nil {
+		return nil, ucecb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(ucecb.builders))
+	nodes := make([]*UsageCounterEntry, len(ucecb.builders))
+	mutators := make([]Mutator, len(ucecb.builders))
+	for i := range ucecb.builders {
+		func(i int, root context.Context) {
+			builder := ucecb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*UsageCounterEntryMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, ucecb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, ucecb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, ucecb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ucecb *UsageCounterEntryCreateBulk) SaveX(ctx context.Context) []*UsageCounterEntry {
+	v, err := ucecb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ucecb *UsageCounterEntryCreateBulk) Exec(ctx context.Context) error {
+	_, err := ucecb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ucecb *UsageCounterEntryCreateBulk) ExecX(ctx context.Context) {
+	if err := ucecb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,527 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/flexprice/flexprice/ent/predicate"
+	"github.com/flexprice/flexprice/ent/usagecounterentry"
+)
+
+// UsageCounterEntryQuery is the builder for querying UsageCounterEntry entities.
+type UsageCounterEntryQuery struct {
+	config
+	ctx        *QueryContext
+	order      []usagecounterentry.OrderOption
+	inters     []Interceptor
+	predicates []predicate.UsageCounterEntry
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the UsageCounterEntryQuery builder.
+func (uceq *UsageCounterEntryQuery) Where(ps ...predicate.UsageCounterEntry) *UsageCounterEntryQuery {
+	uceq.predicates = append(uceq.predicates, ps...)
+	return uceq
+}
+
+// Limit the number of records to be returned by this query.
+func (uceq *UsageCounterEntryQuery) Limit(limit int) *UsageCounterEntryQuery {
+	uceq.ctx.Limit = &limit
+	return uceq
+}
+
+// Offset to start from.
+func (uceq *UsageCounterEntryQuery) Offset(offset int) *UsageCounterEntryQuery {
+	uceq.ctx.Offset = &offset
+	return uceq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (uceq *UsageCounterEntryQuery) Unique(unique bool) *UsageCounterEntryQuery {
+	uceq.ctx.Unique = &unique
+	return uceq
+}
+
+// Order specifies how the records should be ordered.
+func (uceq *UsageCounterEntryQuery) Order(o ...usagecounterentry.OrderOption) *UsageCounterEntryQuery {
+	uceq.order = append(uceq.order, o...)
+	return uceq
+}
+
+// First returns the first UsageCounterEntry entity from the query.
+// Returns a *NotFoundError when no UsageCounterEntry was found.
+func (uceq *UsageCounterEntryQuery) First(ctx context.Context) (*UsageCounterEntry, error) {
+	nodes, err := uceq.Limit(1).All(setContextOp(ctx, uceq.ctx, ent.OpQueryFirst))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{usagecounterentry.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (uceq *UsageCounterEntryQuery) FirstX(ctx context.Context) *UsageCounterEntry {
+	node, err := uceq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first UsageCounterEntry ID from the query.
+// Returns a *NotFoundError when no UsageCounterEntry ID was found.
+func (uceq *UsageCounterEntryQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = uceq.Limit(1).IDs(setContextOp(ctx, uceq.ctx, ent.OpQueryFirstID)); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{usagecounterentry.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (uceq *UsageCounterEntryQuery) FirstIDX(ctx context.Context) int {
+	id, err := uceq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single UsageCounterEntry entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one UsageCounterEntry entity is found.
+// Returns a *NotFoundError when no UsageCounterEntry entities are found.
+func (uceq *UsageCounterEntryQuery) Only(ctx context.Context) (*UsageCounterEntry, error) {
+	nodes, err := uceq.Limit(2).All(setContextOp(ctx, uceq.ctx, ent.OpQueryOnly))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{usagecounterentry.Label}
+	default:
+		return nil, &NotSingularError{usagecounterentry.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (uceq *UsageCounterEntryQuery) OnlyX(ctx context.Context) *UsageCounterEntry {
+	node, err := uceq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only UsageCounterEntry ID in the query.
+// Returns a *NotSingularError when more than one UsageCounterEntry ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (uceq *UsageCounterEntryQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = uceq.Limit(2).IDs(setContextOp(ctx, uceq.ctx, ent.OpQueryOnlyID)); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{usagecounterentry.Label}
+	default:
+		err = &NotSingularError{usagecounterentry.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (uceq *UsageCounterEntryQuery) OnlyIDX(ctx context.Context) int {
+	id, err := uceq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of UsageCounterEntries.
+func (uceq *UsageCounterEntryQuery) All(ctx context.Context) ([]*UsageCounterEntry, error) {
+	ctx = setContextOp(ctx, uceq.ctx, ent.OpQueryAll)
+	if err := uceq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*UsageCounterEntry, *UsageCounterEntryQuery]()
+	return withInterceptors[[]*UsageCounterEntry](ctx, uceq, qr, uceq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (uceq *UsageCounterEntryQuery) AllX(ctx context.Context) []*UsageCounterEntry {
+	nodes, err := uceq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of UsageCounterEntry IDs.
+func (uceq *UsageCounterEntryQuery) IDs(ctx context.Context) (ids []int, err error) {
+	if uceq.ctx.Unique == nil && uceq.path != nil {
+		uceq.Unique(true)
+	}
+	ctx = setContextOp(ctx, uceq.ctx, ent.OpQueryIDs)
+	if err = uceq.Select(usagecounterentry.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (uceq *UsageCounterEntryQuery) IDsX(ctx context.Context) []int {
+	ids, err := uceq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (uceq *UsageCounterEntryQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, uceq.ctx, ent.OpQueryCount)
+	if err := uceq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, uceq, querierCount[*UsageCounterEntryQuery](), uceq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (uceq *UsageCounterEntryQuery) CountX(ctx context.Context) int {
+	count, err := uceq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (uceq *UsageCounterEntryQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, uceq.ctx, ent.OpQueryExist)
+	switch _, err := uceq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("ent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (uceq *UsageCounterEntryQuery) ExistX(ctx context.Context) bool {
+	exist, err := uceq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the UsageCounterEntryQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (uceq *UsageCounterEntryQuery) Clone() *UsageCounterEntryQuery {
+	if uceq == nil {
+		return nil
+	}
+	return &UsageCounterEntryQuery{
+		config:     uceq.config,
+		ctx:        uceq.ctx.Clone(),
+		order:      append([]usagecounterentry.OrderOption{}, uceq.order...),
+		inters:     append([]Interceptor{}, uceq.inters...),
+		predicates: append([]predicate.UsageCounterEntry{}, uceq.predicates...),
+		// clone intermediate query.
+		sql:  uceq.sql.Clone(),
+		path: uceq.path,
+	}
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		TenantID string `json:"tenant_id,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.UsageCounterEntry.Query().
+//		GroupBy(usagecounterentry.FieldTenantID).
+//		Aggregate(ent.Count()).
+//		Scan(ctx, &v)
+func (uceq *UsageCounterEntryQuery) GroupBy(field string, fields ...string) *UsageCounterEntryGroupBy {
+	uceq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &UsageCounterEntryGroupBy{build: uceq}
+	grbuild.flds = &uceq.ctx.Fields
+	grbuild.label = usagecounterentry.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		TenantID string `json:"tenant_id,omitempty"`
+//	}
+//
+//	client.UsageCounterEntry.Query().
+//		Select(usagecounterentry.FieldTenantID).
+//		Scan(ctx, &v)
+func (uceq *UsageCounterEntryQuery) Select(fields ...string) *UsageCounterEntrySelect {
+	uceq.ctx.Fields = append(uceq.ctx.Fields, fields...)
+	sbuild := &UsageCounterEntrySelect{UsageCounterEntryQuery: uceq}
+	sbuild.label = usagecounterentry.Label
+	sbuild.flds, sbuild.scan = &uceq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a UsageCounterEntrySelect configured with the given aggregations.
+func (uceq *UsageCounterEntryQuery) Aggregate(fns ...AggregateFunc) *UsageCounterEntrySelect {
+	return uceq.Select().Aggregate(fns...)
+}
+
+func (uceq *UsageCounterEntryQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range uceq.inters {
+		if inter == nil {
+			return fmt.Errorf("ent: uninitialized interceptor (forgotten import ent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, uceq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range uceq.ctx.Fields {
+		if !usagecounterentry.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+		}
+	}
+	if uceq.path != nil {
+		prev, err := uceq.path(ctx)
+		if err != nil {
+			return err
+		}
+		uceq.sql = prev
+	}
+	return nil
+}
+
+func (uceq *UsageCounterEntryQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*UsageCounterEntry, error) {
+	var (
+		nodes = []*UsageCounterEntry{}
+		_spec = uceq.querySpec()
+	)
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*UsageCounterEntry).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &UsageCounterEntry{config: uceq.config}
+		nodes = append(nodes, node)
+		return node.assignValues(columns, values)
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, uceq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	return nodes, nil
+}
+
+func (uceq *UsageCounterEntryQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := uceq.querySpec()
+	_spec.Node.Columns = uceq.ctx.Fields
+	if len(uceq.ctx.Fields) > 0 {
+		_spec.Unique = uceq.ctx.Unique != nil && *uceq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, uceq.driver, _spec)
+}
+
+func (uceq *UsageCounterEntryQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := sqlgraph.NewQuerySpec(usagecounterentry.Table, usagecounterentry.Columns, sqlgraph.NewFieldSpec(usagecounterentry.FieldID, field.TypeInt))
+	_spec.From = uceq.sql
+	if unique := uceq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	} else if uceq.path != nil {
+		_spec.Unique = true
+	}
+	if fields := uceq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, usagecounterentry.FieldID)
+		for i := range fields {
+			if fields[i] != usagecounterentry.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := uceq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := uceq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := uceq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := uceq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (uceq *UsageCounterEntryQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(uceq.driver.Dialect())
+	t1 := builder.Table(usagecounterentry.Table)
+	columns := uceq.ctx.Fields
+	if len(columns) == 0 {
+		columns = usagecounterentry.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if uceq.sql != nil {
+		selector = uceq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if uceq.ctx.Unique != nil && *uceq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, p := range uceq.predicates {
+		p(selector)
+	}
+	for _, p := range uceq.order {
+		p(selector)
+	}
+	if offset := uceq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := uceq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// UsageCounterEntryGroupBy is the group-by builder for UsageCounterEntry entities.
+type UsageCounterEntryGroupBy struct {
+	selector
+	build *UsageCounterEntryQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (ucegb *UsageCounterEntryGroupBy) Aggregate(fns ...AggregateFunc) *UsageCounterEntryGroupBy {
+	ucegb.fns = append(ucegb.fns, fns...)
+	return ucegb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (ucegb *UsageCounterEntryGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, ucegb.build.ctx, ent.OpQueryGroupBy)
+	if err := ucegb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*UsageCounterEntryQuery, *UsageCounterEntryGroupBy](ctx, ucegb.build, ucegb, ucegb.build.inters, v)
+}
+
+func (ucegb *UsageCounterEntryGroupBy) sqlScan(ctx context.Context, root *UsageCounterEntryQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(ucegb.fns))
+	for _, fn := range ucegb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*ucegb.flds)+len(ucegb.fns))
+		for _, f := range *ucegb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*ucegb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := ucegb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// UsageCounterEntrySelect is the builder for selecting fields of UsageCounterEntry entities.
+type UsageCounterEntrySelect struct {
+	*UsageCounterEntryQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (uces *UsageCounterEntrySelect) Aggregate(fns ...AggregateFunc) *UsageCounterEntrySelect {
+	uces.fns = append(uces.fns, fns...)
+	return uces
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (uces *UsageCounterEntrySelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, uces.ctx, ent.OpQuerySelect)
+	if err := uces.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*UsageCounterEntryQuery, *UsageCounterEntrySelect](ctx, uces.UsageCounterEntryQuery, uces, uces.inters, v)
+}
+
+func (uces *UsageCounterEntrySelect) sqlScan(ctx context.Context, root *UsageCounterEntryQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(uces.fns))
+	for _, fn := range uces.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*uces.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := uces.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
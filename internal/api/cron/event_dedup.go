@@ -0,0 +1,44 @@
+package cron
+
+import (
+	"net/http"
+
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// EventDedupHandler handles the cleanup of the event ids recorded for the feature usage dedup window
+type EventDedupHandler struct {
+	logger                      *logger.Logger
+	featureUsageTrackingService service.FeatureUsageTrackingService
+}
+
+// NewEventDedupHandler creates a new handler for the event dedup cron jobs
+func NewEventDedupHandler(log *logger.Logger, featureUsageTrackingService service.FeatureUsageTrackingService) *EventDedupHandler {
+	return &EventDedupHandler{
+		logger:                      log,
+		featureUsageTrackingService: featureUsageTrackingService,
+	}
+}
+
+// DeleteExpiredKeys deletes the event ids whose dedup window ended
+func (h *EventDedupHandler) DeleteExpiredKeys(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	h.logger.Infow("event dedup cleanup job started")
+
+	deleted, err := h.featureUsageTrackingService.DeleteExpiredEventDedupKeys(ctx)
+	if err != nil {
+		h.logger.Errorw("event dedup cleanup job failed", "error", err)
+		c.Error(err)
+		return
+	}
+
+	h.logger.Infow("event dedup cleanup job completed successfully", "deleted", deleted)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "expired event dedup keys deleted",
+		"deleted": deleted,
+	})
+}
@@ -30,7 +30,8 @@ func (h *KafkaLagMonitoringHandler) HandleKafkaLagMonitoring(c *gin.Context) {
 
 	h.logger.Infow("kafka lag monitoring job started")
 
-	if err := h.eventService.MonitorKafkaLag(ctx); err != nil {
+	response, err := h.eventService.MonitorKafkaLag(ctx)
+	if err != nil {
 		h.logger.Errorw("kafka lag monitoring job failed", "error", err)
 		c.Error(err)
 		return
@@ -38,7 +39,8 @@ func (h *KafkaLagMonitoringHandler) HandleKafkaLagMonitoring(c *gin.Context) {
 
 	h.logger.Infow("kafka lag monitoring job completed successfully")
 	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message": "kafka lag monitoring completed",
+		"status":          "success",
+		"message":         "kafka lag monitoring completed",
+		"consumer_groups": response.ConsumerGroups,
 	})
 }
@@ -22,17 +22,36 @@ type IngestEventRequest struct {
 	EventName          string                 `json:"event_name" validate:"required" binding:"required" example:"api_request" csv:"event_name"`
 	EventID            string                 `json:"event_id" example:"event123" csv:"event_id"`
 	CustomerID         string                 `json:"customer_id" example:"customer456" csv:"customer_id"`
-	ExternalCustomerID string                 `json:"external_customer_id" validate:"required" binding:"required" example:"customer456" csv:"external_customer_id"`
+	ExternalCustomerID string                 `json:"external_customer_id" example:"customer456" csv:"external_customer_id"`
 	Timestamp          time.Time              `json:"timestamp" example:"2024-03-20T15:04:05Z" csv:"-"` // Handled separately due to parsing
 	TimestampStr       string                 `json:"-" csv:"timestamp"`                                // Used for CSV parsing
 	Source             string                 `json:"source" example:"api" csv:"source"`
 	Properties         map[string]interface{} `json:"properties" swaggertype:"object,string,number" example:"{\"request_size\":100,\"response_status\":200}" csv:"-"` // Handled separately for dynamic columns
 }
 
+// Validate validates the request. Either the customer id or the external customer id is required,
+// the external customer id may be resolved from the configured property before validating.
 func (r *IngestEventRequest) Validate() error {
+	if r.CustomerID == "" && r.ExternalCustomerID == "" {
+		return ierr.NewError("customer_id or external_customer_id is required").
+			WithHint("Customer ID or external customer ID is required").
+			Mark(ierr.ErrValidation)
+	}
+
 	return validator.ValidateRequest(r)
 }
 
+// ResolveExternalCustomerID sets the external customer id of a request that has none from the
+// property at the dot separated path, empty disables the fallback
+func (r *IngestEventRequest) ResolveExternalCustomerID(property string) {
+	if r.ExternalCustomerID != "" || property == "" {
+		return
+	}
+	if externalCustomerID, ok := events.ExternalCustomerIDFromProperty(r.Properties, property); ok {
+		r.ExternalCustomerID = externalCustomerID
+	}
+}
+
 type BulkIngestEventRequest struct {
 	Events []*IngestEventRequest `json:"events" validate:"required,min=1,max=1000"`
 }
@@ -55,20 +74,99 @@ func (r *IngestEventRequest) ToEvent(ctx context.Context) *events.Event {
 	)
 }
 
+// IngestUsageRecordRequest is usage that was already aggregated over a period by the sender,
+// ingested as is instead of being derived from raw events. Either MeterID or FeatureID identifies
+// what the usage was recorded for. Re-sending a record with the same UsageRecordID replaces it.
+type IngestUsageRecordRequest struct {
+	UsageRecordID      string          `json:"usage_record_id" example:"usage_record_123"`
+	ExternalCustomerID string          `json:"external_customer_id" validate:"required" binding:"required" example:"customer456"`
+	MeterID            string          `json:"meter_id,omitempty" example:"meter_123"`
+	FeatureID          string          `json:"feature_id,omitempty" example:"feat_123"`
+	PeriodStart        time.Time       `json:"period_start" validate:"required" binding:"required" example:"2024-03-01T00:00:00Z"`
+	PeriodEnd          time.Time       `json:"period_end" validate:"required" binding:"required" example:"2024-03-02T00:00:00Z"`
+	Quantity           decimal.Decimal `json:"quantity" swaggertype:"string" example:"1500"`
+	Source             string          `json:"source,omitempty" example:"partner"`
+	// PeriodID places the usage into the billing period starting at this epoch in milliseconds
+	// instead of the period of period_start, e.g. to correct a closed period
+	PeriodID uint64 `json:"period_id,omitempty" example:"1709251200000"`
+}
+
+func (r *IngestUsageRecordRequest) Validate() error {
+	if err := validator.ValidateRequest(r); err != nil {
+		return err
+	}
+
+	if r.MeterID == "" && r.FeatureID == "" {
+		return ierr.NewError("meter_id or feature_id is required").
+			WithHint("Please provide the meter or feature the usage was recorded for").
+			Mark(ierr.ErrValidation)
+	}
+
+	if !r.PeriodEnd.After(r.PeriodStart) {
+		return ierr.NewError("period_end must be after period_start").
+			WithHint("Please provide a valid usage period").
+			WithReportableDetails(map[string]interface{}{
+				"period_start": r.PeriodStart,
+				"period_end":   r.PeriodEnd,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	if r.Quantity.IsNegative() {
+		return ierr.NewError("quantity must not be negative").
+			WithHint("Please provide a quantity of zero or more").
+			WithReportableDetails(map[string]interface{}{
+				"quantity": r.Quantity.String(),
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// IngestUsageRecordResponse is the result of ingesting a usage record
+type IngestUsageRecordResponse struct {
+	UsageRecordID string `json:"usage_record_id"`
+	// SubscriptionIDs are the subscriptions the usage was attributed to
+	SubscriptionIDs []string `json:"subscription_ids"`
+}
+
 type GetUsageRequest struct {
-	ExternalCustomerID string                `form:"external_customer_id" json:"external_customer_id" example:"customer456"`
-	CustomerID         string                `form:"customer_id" json:"customer_id" example:"customer456"`
-	EventName          string                `form:"event_name" json:"event_name" binding:"required" required:"true" example:"api_request"`
-	PropertyName       string                `form:"property_name" json:"property_name" example:"request_size"` // will be empty/ignored in case of COUNT
-	AggregationType    types.AggregationType `form:"aggregation_type" json:"aggregation_type" binding:"required"`
-	StartTime          time.Time             `form:"start_time" json:"start_time" example:"2024-03-13T00:00:00Z"`
-	EndTime            time.Time             `form:"end_time" json:"end_time" example:"2024-03-20T00:00:00Z"`
-	WindowSize         types.WindowSize      `form:"window_size" json:"window_size"`
-	BucketSize         types.WindowSize      `form:"bucket_size" json:"bucket_size,omitempty" example:"HOUR"` // Optional, only used for MAX aggregation with windowing
-	Filters            map[string][]string   `form:"filters,omitempty" json:"filters,omitempty"`
-	PriceID            string                `form:"-" json:"-"` // this is just for internal use to store the price id
-	MeterID            string                `form:"-" json:"-"` // this is just for internal use to store the meter id
-	Multiplier         *decimal.Decimal      `form:"multiplier" json:"multiplier,omitempty"`
+	ExternalCustomerID string                    `form:"external_customer_id" json:"external_customer_id" example:"customer456"`
+	CustomerID         string                    `form:"customer_id" json:"customer_id" example:"customer456"`
+	EventName          string                    `form:"event_name" json:"event_name" binding:"required" required:"true" example:"api_request"`
+	PropertyName       string                    `form:"property_name" json:"property_name" example:"request_size"` // will be empty/ignored in case of COUNT
+	AggregationType    types.AggregationType     `form:"aggregation_type" json:"aggregation_type" binding:"required"`
+	StartTime          time.Time                 `form:"start_time" json:"start_time" example:"2024-03-13T00:00:00Z"`
+	EndTime            time.Time                 `form:"end_time" json:"end_time" example:"2024-03-20T00:00:00Z"`
+	WindowSize         types.WindowSize          `form:"window_size" json:"window_size"`
+	BucketSize         types.WindowSize          `form:"bucket_size" json:"bucket_size,omitempty" example:"HOUR"` // Optional, only used for MAX aggregation with windowing
+	Filters            map[string][]string       `form:"filters,omitempty" json:"filters,omitempty"`
+	PriceID            string                    `form:"-" json:"-"` // this is just for internal use to store the price id
+	MeterID            string                    `form:"-" json:"-"` // this is just for internal use to store the meter id
+	EventNameMatch     types.MeterEventNameMatch `form:"-" json:"-"` // this is just for internal use to match the meter's events by prefix
+	Multiplier         *decimal.Decimal          `form:"multiplier" json:"multiplier,omitempty"`
+	HeartbeatInterval  int64                     `form:"-" json:"-"` // this is just for internal use to pass the heartbeat interval of UPTIME meters in seconds
+	// SubscriptionID and SubscriptionIDProperty are just for internal use to skip the events
+	// routed to another subscription of the customer
+	SubscriptionID         string `form:"-" json:"-"`
+	SubscriptionIDProperty string `form:"-" json:"-"`
+	// CaseInsensitiveEventName is just for internal use to match the meter's events regardless of case
+	CaseInsensitiveEventName bool `form:"-" json:"-"`
+	// RateProperty and RateTable are just for internal use to pass the rate table of
+	// SUM_WITH_MULTIPLIER meters
+	RateProperty string                     `form:"-" json:"-"`
+	RateTable    map[string]decimal.Decimal `form:"-" json:"-"`
+	// MinEventValue, MaxEventValue and MaxEventValuePolicy are just for internal use to bound the
+	// values billed with the meter's minimum and maximum event value
+	MinEventValue       *decimal.Decimal          `form:"-" json:"-"`
+	MaxEventValue       *decimal.Decimal          `form:"-" json:"-"`
+	MaxEventValuePolicy types.MaxEventValuePolicy `form:"-" json:"-"`
+	// CustomerCreatedAt, PreCustomerCreationPolicy and PreCustomerCreationClampedAt are just for
+	// internal use to bill the events timestamped before the customer was created as feature usage does
+	CustomerCreatedAt            *time.Time                           `form:"-" json:"-"`
+	PreCustomerCreationPolicy    types.PreCustomerCreationEventPolicy `form:"-" json:"-"`
+	PreCustomerCreationClampedAt *time.Time                           `form:"-" json:"-"`
 	// BillingAnchor enables custom monthly billing periods for usage aggregation.
 	//
 	// When to use:
@@ -93,11 +191,16 @@ type GetUsageByMeterRequest struct {
 	Meter              *meter.Meter        `form:"-" json:"-"` // caller can set this in case already fetched from db to avoid extra db call
 	ExternalCustomerID string              `form:"external_customer_id" json:"external_customer_id" example:"user_5"`
 	CustomerID         string              `form:"customer_id" json:"customer_id" example:"customer456"`
+	SubscriptionID     string              `form:"-" json:"-"` // this is just for internal use to bill only the events routed to the subscription
 	StartTime          time.Time           `form:"start_time" json:"start_time" example:"2024-11-09T00:00:00Z"`
 	EndTime            time.Time           `form:"end_time" json:"end_time" example:"2024-12-09T00:00:00Z"`
 	WindowSize         types.WindowSize    `form:"window_size" json:"window_size"`
 	BucketSize         types.WindowSize    `form:"bucket_size" json:"bucket_size,omitempty" example:"HOUR"` // Optional, only used for MAX aggregation with windowing
 	Filters            map[string][]string `form:"filters,omitempty" json:"filters,omitempty"`
+	// CustomerCreatedAt and SubscriptionStartDate are just for internal use to bill the events
+	// timestamped before the customer was created as feature usage does
+	CustomerCreatedAt     time.Time `form:"-" json:"-"`
+	SubscriptionStartDate time.Time `form:"-" json:"-"`
 	// BillingAnchor enables custom monthly billing periods for meter usage aggregation.
 	//
 	// Usage guidelines:
@@ -168,6 +271,9 @@ type Event struct {
 	Properties         map[string]interface{} `json:"properties"`
 	Source             string                 `json:"source"`
 	EnvironmentID      string                 `json:"environment_id"`
+
+	// CorrelationID is the trace or correlation id read from the event, set on analytics event samples
+	CorrelationID string `json:"correlation_id,omitempty"`
 }
 
 type GetUsageResponse struct {
@@ -211,7 +317,9 @@ func (r *GetUsageRequest) Validate() error {
 }
 
 func (r *GetUsageRequest) ToUsageParams() *events.UsageParams {
-	if r.AggregationType == "" || r.PropertyName == "" {
+	// Aggregations without a field, e.g. COUNT_ONCE_PER_PERIOD, keep their type
+	aggregationType := types.AggregationType(strings.ToUpper(string(r.AggregationType)))
+	if aggregationType == "" || (r.PropertyName == "" && aggregationType.RequiresField()) {
 		r.AggregationType = types.AggregationCount
 	}
 
@@ -219,6 +327,7 @@ func (r *GetUsageRequest) ToUsageParams() *events.UsageParams {
 		ExternalCustomerID: r.ExternalCustomerID,
 		CustomerID:         r.CustomerID,
 		EventName:          r.EventName,
+		EventNameMatch:     r.EventNameMatch,
 		PropertyName:       r.PropertyName,
 		AggregationType:    types.AggregationType(strings.ToUpper(string(r.AggregationType))),
 		StartTime:          r.StartTime,
@@ -227,7 +336,21 @@ func (r *GetUsageRequest) ToUsageParams() *events.UsageParams {
 		BucketSize:         r.BucketSize,
 		Filters:            r.Filters,
 		Multiplier:         r.Multiplier,
+		RateProperty:       r.RateProperty,
+		RateTable:          r.RateTable,
 		BillingAnchor:      r.BillingAnchor,
+
+		SubscriptionID:           r.SubscriptionID,
+		SubscriptionIDProperty:   r.SubscriptionIDProperty,
+		CaseInsensitiveEventName: r.CaseInsensitiveEventName,
+		HeartbeatIntervalSeconds: r.HeartbeatInterval,
+		MinEventValue:            r.MinEventValue,
+		MaxEventValue:            r.MaxEventValue,
+		MaxEventValuePolicy:      r.MaxEventValuePolicy,
+
+		CustomerCreatedAt:            r.CustomerCreatedAt,
+		PreCustomerCreationPolicy:    r.PreCustomerCreationPolicy,
+		PreCustomerCreationClampedAt: r.PreCustomerCreationClampedAt,
 	}
 }
 
@@ -241,6 +364,10 @@ func (r *GetUsageByMeterRequest) Validate() error {
 		return err
 	}
 
+	if err := r.WindowSize.ValidateRange(r.StartTime, r.EndTime); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -283,53 +410,298 @@ type GetUsageAnalyticsRequest struct {
 	EndTime            time.Time        `json:"end_time,omitempty"`
 	GroupBy            []string         `json:"group_by,omitempty"` // allowed values: "source", "feature_id", "properties.<field_name>"
 	WindowSize         types.WindowSize `json:"window_size,omitempty"`
-	Expand             []string         `json:"expand,omitempty"` // allowed values: "price", "meter", "feature", "subscription_line_item","plan","addon"
+	Expand             []string         `json:"expand,omitempty"` // allowed values: "price", "meter", "feature", "subscription_line_item","subscription","plan","addon","sources","matched_filters","aggregation_field","tiers"
+	// WindowSizes requests points for several window sizes in one call, returned in
+	// points_by_window keyed by window size
+	WindowSizes []types.WindowSize `json:"window_sizes,omitempty"`
 	// Property filters to filter the events by the keys in `properties` field of the event
 	PropertyFilters map[string][]string `json:"property_filters,omitempty"`
+	// PriceIDs limits the analytics to the usage billed under these prices, e.g. to isolate the
+	// usage of an overridden price
+	PriceIDs []string `json:"price_ids,omitempty"`
+	// EventSampleSize is the number of contributing raw events to return per feature.
+	// Sampling is disabled when unset and capped at MaxAnalyticsEventSampleSize.
+	EventSampleSize int `json:"event_sample_size,omitempty"`
+	// FailOnMissingPrice fails the request when usage was recorded against a price that can't be
+	// fetched instead of reporting it with zero cost and a warning
+	FailOnMissingPrice bool `json:"fail_on_missing_price,omitempty"`
+	// ExternalCustomerIDs is a segment of customers whose analytics are combined, only supported
+	// by the multi customer analytics and exclusive with external_customer_id
+	ExternalCustomerIDs []string `json:"external_customer_ids,omitempty"`
+	// Limit is the number of items returned per page, sorted by feature name. All items are
+	// returned when unset, it is capped at MaxAnalyticsPageSize.
+	Limit int `json:"limit,omitempty"`
+	// Cursor is the next_cursor of the previous page, the first page is returned when unset
+	Cursor string `json:"cursor,omitempty"`
+	// Calendar is an accounting calendar whose periods replace calendar months in the MONTH
+	// windows, e.g. the periods of a 4-4-5 fiscal calendar
+	Calendar *types.AccountingCalendar `json:"calendar,omitempty"`
+	// BillableOnly limits the analytics to the usage that is invoiced, leaving out the usage of
+	// shadow meters and the usage of line items outside their active dates, so costs match invoices
+	BillableOnly bool `json:"billable_only,omitempty"`
+	// WeightProperty weights the usage of each event by the numeric value of this event property,
+	// e.g. the request size, reported as weighted_usage next to the raw total usage. Events
+	// without a numeric value weigh 0, bucketed MAX features report no weighted usage.
+	WeightProperty string `json:"weight_property,omitempty"`
+	// EntityType restricts the analytics to the usage of PLAN or ADDON prices, e.g. ADDON to see
+	// the usage driven by addons apart from the plan. Subscription override prices count as the
+	// usage of the plan they override.
+	EntityType types.PriceEntityType `json:"entity_type,omitempty"`
 }
 
+// MaxAnalyticsPageSize caps the items of a page of analytics
+const MaxAnalyticsPageSize = 1000
+
+// MaxAnalyticsEventSampleSize caps the raw event samples returned per feature in analytics
+const MaxAnalyticsEventSampleSize = 10
+
+// MaxAnalyticsAggregationValues caps the sampled values of the aggregation field of count unique
+// meters returned per item in analytics
+const MaxAnalyticsAggregationValues = 10
+
+// MaxAnalyticsSegmentSize caps the customers of an analytics segment, each is queried separately
+const MaxAnalyticsSegmentSize = 100
+
 // GetUsageAnalyticsResponse represents the response for the usage analytics API
 type GetUsageAnalyticsResponse struct {
 	TotalCost decimal.Decimal     `json:"total_cost"`
 	Currency  string              `json:"currency"`
 	Items     []UsageAnalyticItem `json:"items"`
+	// Warnings flags usage whose cost couldn't be fully calculated
+	Warnings []UsageAnalyticsWarning `json:"warnings,omitempty"`
+	// PageTotalCost is the cost of the items of the page, only set when the items are paged.
+	// TotalCost remains the cost of all items.
+	PageTotalCost *decimal.Decimal `json:"page_total_cost,omitempty"`
+	// SettlementCurrency is the currency the costs are settled in, only set when the environment
+	// has a settlement currency configured
+	SettlementCurrency string `json:"settlement_currency,omitempty"`
+	// SettledTotalCost is TotalCost in the settlement currency, costs without a conversion rate
+	// are left out and flagged by a warning
+	SettledTotalCost *decimal.Decimal `json:"settled_total_cost,omitempty"`
+	// HasMore is true when there are items after the page
+	HasMore bool `json:"has_more,omitempty"`
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// UsageAnalyticsWarning flags usage of the analytics response that is incomplete
+type UsageAnalyticsWarning struct {
+	Code      types.UsageAnalyticsWarningCode `json:"code"`
+	Message   string                          `json:"message"`
+	FeatureID string                          `json:"feature_id,omitempty"`
+	PriceID   string                          `json:"price_id,omitempty"`
 }
 
 // UsageAnalyticItem represents a single analytic item in the response
 type UsageAnalyticItem struct {
-	FeatureID            string                             `json:"feature_id"`
-	PriceID              string                             `json:"price_id,omitempty"`               // Price ID used for this usage
-	MeterID              string                             `json:"meter_id,omitempty"`               // Meter ID
-	SubLineItemID        string                             `json:"sub_line_item_id,omitempty"`       // Subscription line item ID
-	SubscriptionID       string                             `json:"subscription_id,omitempty"`        // Subscription ID
-	Price                *PriceResponse                     `json:"price,omitempty"`                  // Full price object (only if expand includes "price")
-	Meter                *meter.Meter                       `json:"meter,omitempty"`                  // Full meter object (only if expand includes "meter")
-	Feature              *feature.Feature                   `json:"feature,omitempty"`                // Full feature object (only if expand includes "feature")
-	SubscriptionLineItem *subscription.SubscriptionLineItem `json:"subscription_line_item,omitempty"` // Full line item (only if expand includes "subscription_line_item")
-	Plan                 *plan.Plan                         `json:"plan,omitempty"`                   // Full plan object (only if expand includes "plan")
-	Addon                *addon.Addon                       `json:"addon,omitempty"`                  // Full addon object (only if expand includes "addon")
-	FeatureName          string                             `json:"name,omitempty"`
-	EventName            string                             `json:"event_name,omitempty"`
-	Source               string                             `json:"source,omitempty"`
-	Unit                 string                             `json:"unit,omitempty"`
-	UnitPlural           string                             `json:"unit_plural,omitempty"`
-	AggregationType      types.AggregationType              `json:"aggregation_type,omitempty"`
-	TotalUsage           decimal.Decimal                    `json:"total_usage"`
-	TotalCost            decimal.Decimal                    `json:"total_cost"`
-	Currency             string                             `json:"currency,omitempty"`
-	EventCount           uint64                             `json:"event_count"`          // Number of events that contributed to this aggregation
-	Properties           map[string]string                  `json:"properties,omitempty"` // Stores property values for flexible grouping (e.g., org_id -> "org123")
-	Points               []UsageAnalyticPoint               `json:"points,omitempty"`
-	AddOnID              string                             `json:"add_on_id,omitempty"`
-	PlanID               string                             `json:"plan_id,omitempty"`
+	FeatureID              string                             `json:"feature_id"`
+	PriceID                string                             `json:"price_id,omitempty"`               // Price ID used for this usage
+	MeterID                string                             `json:"meter_id,omitempty"`               // Meter ID
+	SubLineItemID          string                             `json:"sub_line_item_id,omitempty"`       // Subscription line item ID
+	SubscriptionID         string                             `json:"subscription_id,omitempty"`        // Subscription ID
+	Price                  *PriceResponse                     `json:"price,omitempty"`                  // Full price object (only if expand includes "price")
+	Meter                  *meter.Meter                       `json:"meter,omitempty"`                  // Full meter object (only if expand includes "meter")
+	Feature                *feature.Feature                   `json:"feature,omitempty"`                // Full feature object (only if expand includes "feature")
+	SubscriptionLineItem   *subscription.SubscriptionLineItem `json:"subscription_line_item,omitempty"` // Full line item (only if expand includes "subscription_line_item")
+	Subscription           *subscription.Subscription         `json:"subscription,omitempty"`           // Full subscription (only if expand includes "subscription")
+	Plan                   *plan.Plan                         `json:"plan,omitempty"`                   // Full plan object (only if expand includes "plan")
+	Addon                  *addon.Addon                       `json:"addon,omitempty"`                  // Full addon object (only if expand includes "addon")
+	FeatureName            string                             `json:"name,omitempty"`
+	EventName              string                             `json:"event_name,omitempty"`
+	Source                 string                             `json:"source,omitempty"`
+	Sources                []string                           `json:"sources,omitempty"`                  // Distinct sources of the contributing events (only if expand includes "sources")
+	MatchedFilters         []meter.Filter                     `json:"matched_filters,omitempty"`          // Meter filters with the values of the contributing events that matched them (only if expand includes "matched_filters")
+	AggregationField       string                             `json:"aggregation_field,omitempty"`        // Event property the meter aggregates (only if expand includes "aggregation_field")
+	AggregationFieldValues []string                           `json:"aggregation_field_values,omitempty"` // Sample of the distinct values counted by a count unique meter (only if expand includes "aggregation_field")
+	Tiers                  []TierCostBreakup                  `json:"tiers,omitempty"`                    // Usage and cost of each tier of a tiered price, adding up to total_cost (only if expand includes "tiers")
+	Unit                   string                             `json:"unit,omitempty"`
+	UnitPlural             string                             `json:"unit_plural,omitempty"`
+	AggregationType        types.AggregationType              `json:"aggregation_type,omitempty"`
+	TotalUsage             decimal.Decimal                    `json:"total_usage"`
+	WeightedUsage          *decimal.Decimal                   `json:"weighted_usage,omitempty"` // Usage weighted by the weight property of the events (only if weight_property is set)
+	TotalCost              decimal.Decimal                    `json:"total_cost"`
+	CommitmentAdjustedCost *decimal.Decimal                   `json:"commitment_adjusted_cost,omitempty"` // Cost after the subscription commitment, only if the subscription has a commitment and the range is within one of its billing periods
+	EffectiveRate          decimal.Decimal                    `json:"effective_rate"`                     // Blended cost per unit (total_cost / total_usage), zero when there is no usage
+	Currency               string                             `json:"currency,omitempty"`
+	SettledCost            *decimal.Decimal                   `json:"settled_cost,omitempty"`    // Total cost in the settlement currency (only if a settlement currency is configured)
+	SettlementRate         *decimal.Decimal                   `json:"settlement_rate,omitempty"` // Rate the total cost was converted to the settlement currency at
+	EventCount             uint64                             `json:"event_count"`               // Number of events that contributed to this aggregation
+	Properties             map[string]string                  `json:"properties,omitempty"`      // Stores property values for flexible grouping (e.g., org_id -> "org123")
+	Points                 []UsageAnalyticPoint               `json:"points,omitempty"`
+	AddOnID                string                             `json:"add_on_id,omitempty"`
+	PlanID                 string                             `json:"plan_id,omitempty"`
+	EventSamples           []Event                            `json:"event_samples,omitempty"` // Sample of raw events that contributed to this feature's usage (only if event_sample_size is set)
+
+	// PointsByWindow holds the time series per window size (only if window_sizes is set)
+	PointsByWindow map[types.WindowSize][]UsageAnalyticPoint `json:"points_by_window,omitempty"`
 }
 
 // UsageAnalyticPoint represents a point in the time series data
 type UsageAnalyticPoint struct {
-	Timestamp  time.Time       `json:"timestamp"`
-	Usage      decimal.Decimal `json:"usage"`
-	Cost       decimal.Decimal `json:"cost"`
-	EventCount uint64          `json:"event_count"` // Number of events in this time window
+	Timestamp     time.Time       `json:"timestamp"`
+	Usage         decimal.Decimal `json:"usage"`
+	Cost          decimal.Decimal `json:"cost"`
+	EffectiveRate decimal.Decimal `json:"effective_rate"` // Blended cost per unit in this time window
+	EventCount    uint64          `json:"event_count"`    // Number of events in this time window
+}
+
+// CompareUsageAnalyticsRequest compares the usage analytics of a customer between two time
+// ranges, e.g. this month against last month
+type CompareUsageAnalyticsRequest struct {
+	ExternalCustomerID string    `json:"external_customer_id" binding:"required" validate:"required"`
+	FeatureIDs         []string  `json:"feature_ids,omitempty"`
+	Sources            []string  `json:"sources,omitempty"`
+	CurrentStartTime   time.Time `json:"current_start_time" binding:"required" validate:"required"`
+	CurrentEndTime     time.Time `json:"current_end_time" binding:"required" validate:"required"`
+	PreviousStartTime  time.Time `json:"previous_start_time" binding:"required" validate:"required"`
+	PreviousEndTime    time.Time `json:"previous_end_time" binding:"required" validate:"required"`
+}
+
+func (r *CompareUsageAnalyticsRequest) Validate() error {
+	if err := validator.ValidateRequest(r); err != nil {
+		return err
+	}
+
+	if !r.CurrentEndTime.After(r.CurrentStartTime) || !r.PreviousEndTime.After(r.PreviousStartTime) {
+		return ierr.NewError("end time must be after start time").
+			WithHint("Please provide an end time after the start time for both ranges").
+			WithReportableDetails(map[string]interface{}{
+				"current_start_time":  r.CurrentStartTime,
+				"current_end_time":    r.CurrentEndTime,
+				"previous_start_time": r.PreviousStartTime,
+				"previous_end_time":   r.PreviousEndTime,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// analyticsRequest returns the analytics request of one of the compared ranges
+func (r *CompareUsageAnalyticsRequest) analyticsRequest(startTime, endTime time.Time) *GetUsageAnalyticsRequest {
+	return &GetUsageAnalyticsRequest{
+		ExternalCustomerID: r.ExternalCustomerID,
+		FeatureIDs:         r.FeatureIDs,
+		Sources:            r.Sources,
+		StartTime:          startTime,
+		EndTime:            endTime,
+	}
+}
+
+// CurrentAnalyticsRequest returns the analytics request of the current range
+func (r *CompareUsageAnalyticsRequest) CurrentAnalyticsRequest() *GetUsageAnalyticsRequest {
+	return r.analyticsRequest(r.CurrentStartTime, r.CurrentEndTime)
+}
+
+// PreviousAnalyticsRequest returns the analytics request of the previous range
+func (r *CompareUsageAnalyticsRequest) PreviousAnalyticsRequest() *GetUsageAnalyticsRequest {
+	return r.analyticsRequest(r.PreviousStartTime, r.PreviousEndTime)
+}
+
+// CompareUsageAnalyticsResponse holds the usage and cost deltas per feature between two ranges
+type CompareUsageAnalyticsResponse struct {
+	Currency          string                `json:"currency"`
+	CurrentTotalCost  decimal.Decimal       `json:"current_total_cost"`
+	PreviousTotalCost decimal.Decimal       `json:"previous_total_cost"`
+	TotalCostDelta    decimal.Decimal       `json:"total_cost_delta"`
+	Items             []UsageAnalyticsDelta `json:"items"`
+}
+
+// UsageAnalyticsDelta is the change of a feature's usage and cost from the previous range to the
+// current one. The percentages are nil when the previous value is zero, e.g. for a new feature.
+type UsageAnalyticsDelta struct {
+	FeatureID         string           `json:"feature_id"`
+	FeatureName       string           `json:"name,omitempty"`
+	CurrentUsage      decimal.Decimal  `json:"current_usage"`
+	PreviousUsage     decimal.Decimal  `json:"previous_usage"`
+	UsageDelta        decimal.Decimal  `json:"usage_delta"`
+	UsageDeltaPercent *decimal.Decimal `json:"usage_delta_percent"`
+	CurrentCost       decimal.Decimal  `json:"current_cost"`
+	PreviousCost      decimal.Decimal  `json:"previous_cost"`
+	CostDelta         decimal.Decimal  `json:"cost_delta"`
+	CostDeltaPercent  *decimal.Decimal `json:"cost_delta_percent"`
+}
+
+// ForecastFeatureUsageRequest forecasts the usage of a customer's feature in the period after the
+// time range from the usage of each period of the range. The range should end at a period
+// boundary, a partial last period lowers the forecast.
+type ForecastFeatureUsageRequest struct {
+	ExternalCustomerID string           `json:"external_customer_id" binding:"required" validate:"required"`
+	FeatureID          string           `json:"feature_id" binding:"required" validate:"required"`
+	StartTime          time.Time        `json:"start_time" binding:"required" validate:"required"`
+	EndTime            time.Time        `json:"end_time" binding:"required" validate:"required"`
+	WindowSize         types.WindowSize `json:"window_size" binding:"required" validate:"required"`
+	// Model is the forecast model, defaults to moving_average
+	Model types.UsageForecastModel `json:"model,omitempty"`
+	// Periods is the number of latest periods the model looks at, defaults to 3 for
+	// moving_average and every period for linear_trend
+	Periods int `json:"periods,omitempty"`
+}
+
+func (r *ForecastFeatureUsageRequest) Validate() error {
+	if err := validator.ValidateRequest(r); err != nil {
+		return err
+	}
+
+	if !r.EndTime.After(r.StartTime) {
+		return ierr.NewError("end time must be after start time").
+			WithHint("Please provide an end time after the start time").
+			WithReportableDetails(map[string]interface{}{
+				"start_time": r.StartTime,
+				"end_time":   r.EndTime,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	if err := r.WindowSize.Validate(); err != nil {
+		return err
+	}
+
+	if r.Model == "" {
+		r.Model = types.UsageForecastModelMovingAverage
+	}
+	if err := r.Model.Validate(); err != nil {
+		return err
+	}
+
+	if r.Periods < 0 {
+		return ierr.NewError("periods must not be negative").
+			WithHint("Please provide a positive number of periods or leave it empty for the default").
+			WithReportableDetails(map[string]interface{}{
+				"periods": r.Periods,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// AnalyticsRequest returns the analytics request of the feature's usage per period
+func (r *ForecastFeatureUsageRequest) AnalyticsRequest() *GetUsageAnalyticsRequest {
+	return &GetUsageAnalyticsRequest{
+		ExternalCustomerID: r.ExternalCustomerID,
+		FeatureIDs:         []string{r.FeatureID},
+		StartTime:          r.StartTime,
+		EndTime:            r.EndTime,
+		WindowSize:         r.WindowSize,
+	}
+}
+
+// ForecastFeatureUsageResponse is the usage of each period of the range and the usage forecast
+// for the next period
+type ForecastFeatureUsageResponse struct {
+	FeatureID     string                   `json:"feature_id"`
+	FeatureName   string                   `json:"name,omitempty"`
+	Model         types.UsageForecastModel `json:"model"`
+	WindowSize    types.WindowSize         `json:"window_size"`
+	History       []UsageForecastPoint     `json:"history"`
+	ForecastUsage decimal.Decimal          `json:"forecast_usage"`
+}
+
+// UsageForecastPoint is the usage of a period the forecast is based on
+type UsageForecastPoint struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Usage     decimal.Decimal `json:"usage"`
 }
 
 type GetMonitoringDataRequest struct {
@@ -368,15 +740,231 @@ type GetMonitoringDataResponse struct {
 	Points            []EventCountPoint `json:"points,omitempty"`
 }
 
+// KafkaLagMonitoringResponse is the lag of the configured Kafka consumer groups
+type KafkaLagMonitoringResponse struct {
+	ConsumerGroups []ConsumerGroupLag `json:"consumer_groups"`
+}
+
+// ConsumerGroupLag is the lag of a Kafka consumer group, Error is set when it couldn't be queried
+type ConsumerGroupLag struct {
+	Pipeline      string                  `json:"pipeline"`
+	Kind          types.ConsumerGroupKind `json:"kind"`
+	Topic         string                  `json:"topic"`
+	ConsumerGroup string                  `json:"consumer_group"`
+	TotalLag      int64                   `json:"total_lag"`
+	PartitionLags map[int32]int64         `json:"partition_lags,omitempty"`
+	Error         string                  `json:"error,omitempty"`
+}
+
+// Defaults of the clock skew diagnostic
+const (
+	DefaultClockSkewThresholdSeconds = 300
+	DefaultClockSkewMinEvents        = 10
+)
+
+// GetClockSkewRequest requests the clock skew of the event producers, each customer and source,
+// over the events ingested in a time range, the last 24 hours by default
+type GetClockSkewRequest struct {
+	StartTime          time.Time `json:"start_time,omitempty" form:"start_time"`
+	EndTime            time.Time `json:"end_time,omitempty" form:"end_time"`
+	ExternalCustomerID string    `json:"external_customer_id,omitempty" form:"external_customer_id"`
+	Source             string    `json:"source,omitempty" form:"source"`
+	// ThresholdSeconds is the skew beyond which a producer is flagged, 5 minutes by default
+	ThresholdSeconds int `json:"threshold_seconds,omitempty" form:"threshold_seconds" validate:"omitempty,gt=0"`
+	// MinEvents skips the producers with fewer events in the time range, 10 by default
+	MinEvents int `json:"min_events,omitempty" form:"min_events" validate:"omitempty,gt=0"`
+}
+
+func (r *GetClockSkewRequest) Validate() error {
+	if err := validator.ValidateRequest(r); err != nil {
+		return err
+	}
+
+	// Default to last 24 hours if start_time and end_time are not provided
+	if r.StartTime.IsZero() && r.EndTime.IsZero() {
+		r.EndTime = time.Now().UTC()
+		r.StartTime = r.EndTime.Add(-24 * time.Hour)
+	} else if r.StartTime.IsZero() || r.EndTime.IsZero() {
+		return ierr.NewError("both start_time and end_time must be provided, or neither").
+			WithHint("Please provide both start_time and end_time, or leave both empty for default 24 hour window").
+			Mark(ierr.ErrValidation)
+	}
+
+	if !r.EndTime.After(r.StartTime) {
+		return ierr.NewError("end_time must be after start_time").
+			WithHint("End time must be after start time").
+			WithReportableDetails(map[string]interface{}{
+				"start_time": r.StartTime,
+				"end_time":   r.EndTime,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	if r.ThresholdSeconds == 0 {
+		r.ThresholdSeconds = DefaultClockSkewThresholdSeconds
+	}
+	if r.MinEvents == 0 {
+		r.MinEvents = DefaultClockSkewMinEvents
+	}
+	return nil
+}
+
+// GetClockSkewResponse is the clock skew of the event producers, the most skewed first
+type GetClockSkewResponse struct {
+	ThresholdSeconds int              `json:"threshold_seconds"`
+	Items            []*ClockSkewItem `json:"items"`
+}
+
+// ClockSkewItem is the distribution of the delay between the timestamp and the ingestion of the
+// events of a customer and source in seconds, negative when the events are timestamped in the future
+type ClockSkewItem struct {
+	ExternalCustomerID string  `json:"external_customer_id"`
+	Source             string  `json:"source"`
+	EventCount         uint64  `json:"event_count"`
+	AvgSkewSeconds     float64 `json:"avg_skew_seconds"`
+	MinSkewSeconds     float64 `json:"min_skew_seconds"`
+	P10SkewSeconds     float64 `json:"p10_skew_seconds"`
+	MedianSkewSeconds  float64 `json:"median_skew_seconds"`
+	P90SkewSeconds     float64 `json:"p90_skew_seconds"`
+	MaxSkewSeconds     float64 `json:"max_skew_seconds"`
+	// Skewed flags a producer whose events are consistently skewed beyond the threshold
+	Skewed bool `json:"skewed"`
+	// Direction is the direction of the clock of a skewed producer
+	Direction types.ClockSkewDirection `json:"direction,omitempty"`
+}
+
+// GetPlatformUsageRequest requests usage totals across all tenants for capacity planning
+type GetPlatformUsageRequest struct {
+	StartTime time.Time `json:"start_time,omitempty" form:"start_time"`
+	EndTime   time.Time `json:"end_time,omitempty" form:"end_time"`
+}
+
+func (r *GetPlatformUsageRequest) Validate() error {
+	if err := validator.ValidateRequest(r); err != nil {
+		return err
+	}
+
+	// Default to last 24 hours if start_time and end_time are not provided
+	if r.StartTime.IsZero() && r.EndTime.IsZero() {
+		r.EndTime = time.Now().UTC()
+		r.StartTime = r.EndTime.Add(-24 * time.Hour)
+	} else if r.StartTime.IsZero() || r.EndTime.IsZero() {
+		return ierr.NewError("both start_time and end_time must be provided, or neither").
+			WithHint("Please provide both start_time and end_time, or leave both empty for default 24 hour window").
+			Mark(ierr.ErrValidation)
+	}
+
+	if !r.EndTime.After(r.StartTime) {
+		return ierr.NewError("end_time must be after start_time").
+			WithHint("Please provide an end_time after the start_time").
+			WithReportableDetails(map[string]interface{}{
+				"start_time": r.StartTime,
+				"end_time":   r.EndTime,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// GetPlatformUsageResponse holds the usage totals per meter across all tenants
+type GetPlatformUsageResponse struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	// TotalEvents counts an event once for every meter it was recorded for
+	TotalEvents uint64                       `json:"total_events"`
+	Meters      []*events.PlatformMeterUsage `json:"meters"`
+}
+
+// GetEventsStatusRequest requests the processing status of many events at once
+type GetEventsStatusRequest struct {
+	EventIDs []string `json:"event_ids" binding:"required,min=1,max=1000" validate:"required,min=1,max=1000"`
+}
+
+func (r *GetEventsStatusRequest) Validate() error {
+	return validator.ValidateRequest(r)
+}
+
+// EventStatus is the processing status of a single event
+type EventStatus struct {
+	EventID string                      `json:"event_id"`
+	Status  types.EventProcessingStatus `json:"status"`
+}
+
+// GetEventsStatusResponse holds the processing status of each requested event, in request order
+type GetEventsStatusResponse struct {
+	Events []EventStatus `json:"events"`
+}
+
+// GetUnbilledEventsRequest requests the events of a customer in a window that produced no feature usage
+type GetUnbilledEventsRequest struct {
+	ExternalCustomerID string    `json:"external_customer_id" binding:"required" validate:"required"`
+	StartTime          time.Time `json:"start_time" binding:"required" validate:"required"`
+	EndTime            time.Time `json:"end_time" binding:"required" validate:"required"`
+	// Limit caps the number of unbilled events returned, defaults to 100
+	Limit int `json:"limit,omitempty" validate:"omitempty,min=1,max=1000"`
+	// IterLastKey continues from the iter_last_key of the previous response
+	IterLastKey string `json:"iter_last_key,omitempty"`
+}
+
+func (r *GetUnbilledEventsRequest) Validate() error {
+	if err := validator.ValidateRequest(r); err != nil {
+		return err
+	}
+
+	if !r.EndTime.After(r.StartTime) {
+		return ierr.NewError("end time must be after start time").
+			WithHint("Please provide an end time after the start time").
+			WithReportableDetails(map[string]interface{}{
+				"start_time": r.StartTime,
+				"end_time":   r.EndTime,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// UnbilledEvent is an event that produced no feature usage and the reason why
+type UnbilledEvent struct {
+	EventID   string                    `json:"event_id"`
+	EventName string                    `json:"event_name"`
+	Timestamp time.Time                 `json:"timestamp"`
+	Reason    types.UnbilledEventReason `json:"reason"`
+}
+
+// GetUnbilledEventsResponse holds the unbilled events of the window, most recent first
+type GetUnbilledEventsResponse struct {
+	Events []UnbilledEvent `json:"events"`
+	// HasMore is set when the window has events left to check, they are listed by passing
+	// iter_last_key in the next request
+	HasMore bool `json:"has_more"`
+	// IterLastKey is the key of the last event checked
+	IterLastKey string `json:"iter_last_key,omitempty"`
+}
+
 type GetHuggingFaceBillingDataRequest struct {
 	EventIDs []string `json:"requestIds" binding:"required,min=1"`
 }
 
 type EventCostInfo struct {
-	EventID       string          `json:"requestId"`
+	EventID string `json:"requestId"`
+	// CostInNanoUSD is the cost in billionths of a dollar. Costs of prices in other currencies are
+	// converted at the conversion rates of a USD settlement config, zero when there is no rate.
 	CostInNanoUSD decimal.Decimal `json:"costNanoUsd"`
+	// MissingConversionRate is set when the price isn't in USD and has no conversion rate to it,
+	// CostInNanoUSD is zero and only CostInNano holds the cost
+	MissingConversionRate bool `json:"missingConversionRate,omitempty"`
+	// Currency is the currency of the price the cost was calculated with, empty when the price
+	// wasn't found
+	Currency string `json:"currency,omitempty"`
+	// CostInNano is the cost in billionths of the currency
+	CostInNano decimal.Decimal `json:"costNano"`
 }
 
 type GetHuggingFaceBillingDataResponse struct {
 	Data []EventCostInfo `json:"requests"`
+	// Truncated is set when an event has more usage records than the lookup limit, the records
+	// past the limit are left out
+	Truncated bool `json:"truncated"`
 }
@@ -1041,6 +1041,64 @@ type GetPreviewInvoiceRequest struct {
 	PeriodEnd *time.Time `json:"period_end,omitempty"`
 }
 
+// ProjectedInvoiceLineItem is a charge on the projected invoice of a subscription's current period
+type ProjectedInvoiceLineItem struct {
+	// sub_line_item_id is the subscription line item the charge is for
+	SubLineItemID string `json:"sub_line_item_id"`
+
+	// price_id is the price the charge is calculated with
+	PriceID string `json:"price_id"`
+
+	// price_type is FIXED for recurring fees and USAGE for metered charges
+	PriceType types.PriceType `json:"price_type"`
+
+	// feature_id is the metered feature of usage charges
+	FeatureID string `json:"feature_id,omitempty"`
+
+	// display_name is the name of the charge
+	DisplayName string `json:"display_name,omitempty"`
+
+	// quantity is the quantity billed so far in the period
+	Quantity decimal.Decimal `json:"quantity"`
+
+	// amount is the amount for the quantity billed so far in the period
+	Amount decimal.Decimal `json:"amount"`
+
+	// projected_quantity is the quantity expected by the end of the period at the current usage rate
+	ProjectedQuantity decimal.Decimal `json:"projected_quantity"`
+
+	// projected_amount is the amount for the projected quantity
+	ProjectedAmount decimal.Decimal `json:"projected_amount"`
+}
+
+// ProjectedInvoiceResponse previews the invoice of a subscription's current period, projecting
+// usage so far to the end of the period
+type ProjectedInvoiceResponse struct {
+	// subscription_id is the subscription the invoice is projected for
+	SubscriptionID string `json:"subscription_id"`
+
+	// currency is the three-letter ISO currency code of the invoice
+	Currency string `json:"currency"`
+
+	// period_start is the start of the billing period being projected
+	PeriodStart time.Time `json:"period_start"`
+
+	// period_end is the end of the billing period being projected
+	PeriodEnd time.Time `json:"period_end"`
+
+	// as_of is the point in the period usage is known up to
+	AsOf time.Time `json:"as_of"`
+
+	// line_items are the fixed and usage charges of the invoice
+	LineItems []ProjectedInvoiceLineItem `json:"line_items"`
+
+	// amount is the total of the charges so far in the period
+	Amount decimal.Decimal `json:"amount"`
+
+	// projected_total is the expected total of the invoice at the end of the period
+	ProjectedTotal decimal.Decimal `json:"projected_total"`
+}
+
 // CustomerInvoiceSummary represents a summary of customer's invoice status for a specific currency
 type CustomerInvoiceSummary struct {
 	// customer_id is the unique identifier of the customer
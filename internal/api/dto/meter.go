@@ -4,8 +4,10 @@ import (
 	"time"
 
 	"github.com/flexprice/flexprice/internal/domain/meter"
+	ierr "github.com/flexprice/flexprice/internal/errors"
 	"github.com/flexprice/flexprice/internal/types"
 	"github.com/flexprice/flexprice/internal/validator"
+	"github.com/samber/lo"
 )
 
 // CreateMeterRequest represents the request payload for creating a meter
@@ -15,6 +17,18 @@ type CreateMeterRequest struct {
 	Aggregation meter.Aggregation `json:"aggregation" binding:"required"`
 	Filters     []meter.Filter    `json:"filters"`
 	ResetUsage  types.ResetUsage  `json:"reset_usage" binding:"required"`
+	// SubscriptionStatuses restricts metering to subscriptions in these statuses,
+	// leave empty to count usage in every status
+	SubscriptionStatuses []types.SubscriptionStatus `json:"subscription_statuses,omitempty"`
+	// BillingMode is bill by default, usage of shadow meters shows in analytics but is never billed
+	BillingMode types.MeterBillingMode `json:"billing_mode,omitempty"`
+	// EventNameMatch is exact by default, with prefix the meter tracks every event whose
+	// name starts with event_name
+	EventNameMatch types.MeterEventNameMatch `json:"event_name_match,omitempty"`
+	// IndexedProperties are the event properties usage is frequently filtered or grouped by,
+	// they are indexed in ClickHouse to speed up analytics once operators enable property indexes
+	// for the tenant
+	IndexedProperties []string `json:"indexed_properties,omitempty"`
 }
 
 // UpdateMeterRequest represents the request payload for updating a meter
@@ -24,26 +38,34 @@ type UpdateMeterRequest struct {
 
 // MeterResponse represents the meter response structure
 type MeterResponse struct {
-	ID          string            `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Name        string            `json:"name" example:"API Usage Meter"`
-	TenantID    string            `json:"tenant_id" example:"tenant123"`
-	EventName   string            `json:"event_name" example:"api_request"`
-	Aggregation meter.Aggregation `json:"aggregation"`
-	Filters     []meter.Filter    `json:"filters"`
-	ResetUsage  types.ResetUsage  `json:"reset_usage"`
-	CreatedAt   time.Time         `json:"created_at" example:"2024-03-20T15:04:05Z"`
-	UpdatedAt   time.Time         `json:"updated_at" example:"2024-03-20T15:04:05Z"`
-	Status      string            `json:"status" example:"published"`
+	ID                   string                     `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name                 string                     `json:"name" example:"API Usage Meter"`
+	TenantID             string                     `json:"tenant_id" example:"tenant123"`
+	EventName            string                     `json:"event_name" example:"api_request"`
+	Aggregation          meter.Aggregation          `json:"aggregation"`
+	Filters              []meter.Filter             `json:"filters"`
+	ResetUsage           types.ResetUsage           `json:"reset_usage"`
+	SubscriptionStatuses []types.SubscriptionStatus `json:"subscription_statuses,omitempty"`
+	BillingMode          types.MeterBillingMode     `json:"billing_mode"`
+	EventNameMatch       types.MeterEventNameMatch  `json:"event_name_match"`
+	IndexedProperties    []string                   `json:"indexed_properties,omitempty"`
+	CreatedAt            time.Time                  `json:"created_at" example:"2024-03-20T15:04:05Z"`
+	UpdatedAt            time.Time                  `json:"updated_at" example:"2024-03-20T15:04:05Z"`
+	Status               string                     `json:"status" example:"published"`
 }
 
 func (r *MeterResponse) ToMeter() *meter.Meter {
 	return &meter.Meter{
-		ID:          r.ID,
-		Name:        r.Name,
-		EventName:   r.EventName,
-		Aggregation: r.Aggregation,
-		Filters:     r.Filters,
-		ResetUsage:  r.ResetUsage,
+		ID:                   r.ID,
+		Name:                 r.Name,
+		EventName:            r.EventName,
+		Aggregation:          r.Aggregation,
+		Filters:              r.Filters,
+		ResetUsage:           r.ResetUsage,
+		SubscriptionStatuses: r.SubscriptionStatuses,
+		BillingMode:          r.BillingMode,
+		EventNameMatch:       r.EventNameMatch,
+		IndexedProperties:    r.IndexedProperties,
 		BaseModel: types.BaseModel{
 			Status:    types.Status(r.Status),
 			CreatedAt: r.CreatedAt,
@@ -56,16 +78,20 @@ func (r *MeterResponse) ToMeter() *meter.Meter {
 // Convert domain Meter to MeterResponse
 func ToMeterResponse(m *meter.Meter) *MeterResponse {
 	return &MeterResponse{
-		ID:          m.ID,
-		Name:        m.Name,
-		TenantID:    m.TenantID,
-		EventName:   m.EventName,
-		Aggregation: m.Aggregation,
-		Filters:     m.Filters,
-		ResetUsage:  m.ResetUsage,
-		CreatedAt:   m.CreatedAt,
-		UpdatedAt:   m.UpdatedAt,
-		Status:      string(m.Status),
+		ID:                   m.ID,
+		Name:                 m.Name,
+		TenantID:             m.TenantID,
+		EventName:            m.EventName,
+		Aggregation:          m.Aggregation,
+		Filters:              m.Filters,
+		ResetUsage:           m.ResetUsage,
+		SubscriptionStatuses: m.SubscriptionStatuses,
+		BillingMode:          m.BillingMode,
+		EventNameMatch:       m.EventNameMatch,
+		IndexedProperties:    m.IndexedProperties,
+		CreatedAt:            m.CreatedAt,
+		UpdatedAt:            m.UpdatedAt,
+		Status:               string(m.Status),
 	}
 }
 
@@ -76,6 +102,16 @@ func (r *CreateMeterRequest) ToMeter(tenantID, createdBy string) *meter.Meter {
 	m.Aggregation = r.Aggregation
 	m.Filters = r.Filters
 	m.ResetUsage = r.ResetUsage
+	m.SubscriptionStatuses = r.SubscriptionStatuses
+	if r.BillingMode != "" {
+		m.BillingMode = r.BillingMode
+	}
+	if r.EventNameMatch != "" {
+		m.EventNameMatch = r.EventNameMatch
+	}
+	if len(r.IndexedProperties) > 0 {
+		m.IndexedProperties = lo.Uniq(r.IndexedProperties)
+	}
 	m.Status = types.StatusPublished
 	return m
 }
@@ -91,8 +127,73 @@ func (r *CreateMeterRequest) Validate() error {
 		return err
 	}
 
+	for _, status := range r.SubscriptionStatuses {
+		if err := status.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := r.BillingMode.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.EventNameMatch.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // ListMetersResponse represents a paginated list of meters
 type ListMetersResponse = types.ListResponse[*MeterResponse]
+
+// DuplicateMeterGroup is a set of meters that track the same event with the same aggregation.
+// The first meter is the oldest one and the suggested target of a merge.
+type DuplicateMeterGroup struct {
+	EventName   string            `json:"event_name"`
+	Aggregation meter.Aggregation `json:"aggregation"`
+	Meters      []*MeterResponse  `json:"meters"`
+}
+
+// ListDuplicateMetersResponse lists the groups of duplicate meters of an environment
+type ListDuplicateMetersResponse struct {
+	Groups []*DuplicateMeterGroup `json:"groups"`
+}
+
+// MergeMetersRequest merges duplicate source meters into the target meter
+type MergeMetersRequest struct {
+	TargetMeterID  string   `json:"target_meter_id" validate:"required"`
+	SourceMeterIDs []string `json:"source_meter_ids" validate:"required,min=1,dive,required"`
+}
+
+func (r *MergeMetersRequest) Validate() error {
+	if err := validator.ValidateRequest(r); err != nil {
+		return err
+	}
+
+	if lo.Contains(r.SourceMeterIDs, r.TargetMeterID) {
+		return ierr.NewError("target meter cannot be merged into itself").
+			WithHint("Please remove the target meter from the source meters").
+			WithReportableDetails(map[string]interface{}{
+				"target_meter_id": r.TargetMeterID,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	if len(lo.Uniq(r.SourceMeterIDs)) != len(r.SourceMeterIDs) {
+		return ierr.NewError("source meters must be unique").
+			WithHint("Please provide each source meter once").
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// MergeMetersResponse summarizes what was re-pointed to the target meter
+type MergeMetersResponse struct {
+	TargetMeterID    string   `json:"target_meter_id"`
+	MergedMeterIDs   []string `json:"merged_meter_ids"`
+	FeatureIDs       []string `json:"feature_ids"`
+	PriceIDs         []string `json:"price_ids"`
+	LineItemsUpdated int      `json:"line_items_updated"`
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/flexprice/flexprice/internal/domain/price"
 	priceDomain "github.com/flexprice/flexprice/internal/domain/price"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
 	ierr "github.com/flexprice/flexprice/internal/errors"
 	"github.com/flexprice/flexprice/internal/types"
 	"github.com/flexprice/flexprice/internal/validator"
@@ -48,6 +49,10 @@ type CreatePriceRequest struct {
 	// ParentPriceID is the id of the parent price for this price
 	ParentPriceID string `json:"-"`
 
+	// DiscountPercent is set on subscription override prices that keep the tiers of their parent
+	// NOTE: This is not a public field and is used internally by the override price workflow
+	DiscountPercent *decimal.Decimal `json:"-"`
+
 	// GroupID is the id of the group to add the price to
 	GroupID string `json:"group_id,omitempty"`
 }
@@ -637,6 +642,7 @@ func (r *CreatePriceRequest) ToPrice(ctx context.Context) (*priceDomain.Price, e
 		EntityID:           r.EntityID,
 		StartDate:          startDate,
 		ParentPriceID:      r.ParentPriceID,
+		DiscountPercent:    r.DiscountPercent,
 		EndDate:            r.EndDate,
 		EnvironmentID:      types.GetEnvironmentID(ctx),
 		BaseModel:          types.GetDefaultBaseModel(ctx),
@@ -874,6 +880,86 @@ type CostBreakup struct {
 	TierUnitAmount decimal.Decimal
 	// FinalCost is the total cost for the quantity
 	FinalCost decimal.Decimal
+	// Tiers is the quantity billed in each tier of a tiered price and its cost, adding up to
+	// the unrounded final cost
+	Tiers []TierCostBreakup
+}
+
+// TierCostBreakup is the quantity billed in a single tier of a tiered price and its cost
+type TierCostBreakup struct {
+	// TierIndex is the index of the tier in the tiers of the price sorted by up_to
+	TierIndex int `json:"tier_index"`
+	// UpTo is the inclusive upper bound of the tier, null for the last tier
+	UpTo *uint64 `json:"up_to"`
+	// Quantity is the quantity billed in the tier, the whole quantity for volume tiers
+	Quantity decimal.Decimal `json:"quantity"`
+	// UnitAmount is the amount per unit of the tier
+	UnitAmount decimal.Decimal `json:"unit_amount"`
+	// FlatAmount is the flat amount of the tier, if any
+	FlatAmount *decimal.Decimal `json:"flat_amount,omitempty"`
+	// Cost is the cost of the quantity billed in the tier including its flat amount
+	Cost decimal.Decimal `json:"cost"`
+}
+
+// EffectiveCostCommitment carries the subscription commitment state a charge is evaluated against
+type EffectiveCostCommitment struct {
+	// Remaining is the commitment amount still available for this charge
+	Remaining decimal.Decimal
+	// OverageFactor is the multiplier applied to usage beyond the commitment
+	OverageFactor decimal.Decimal
+	// ApplyTrueUp charges the commitment left unused after this charge when there is no overage.
+	// It should only be set on the last charge of a billing period.
+	ApplyTrueUp bool
+}
+
+// EffectiveCostRequest is the input for computing the effective cost of a price for a quantity
+type EffectiveCostRequest struct {
+	Price *priceDomain.Price
+	// LineItem is the subscription line item the charge belongs to. Its quantity is
+	// used for fixed prices when no quantity is given.
+	LineItem *subscription.SubscriptionLineItem
+	Quantity decimal.Decimal
+	// BaseCost overrides the cost computed from the price for the quantity, for
+	// usage that is priced outside the standard calculation (e.g. bucketed meters)
+	BaseCost *decimal.Decimal
+	// Commitment is nil when the subscription has no commitment configured
+	Commitment *EffectiveCostCommitment
+	// MinimumCharge is the floor applied to the charge, if any
+	MinimumCharge *decimal.Decimal
+	// MaximumCharge caps the charge, if any
+	MaximumCharge *decimal.Decimal
+}
+
+// EffectiveCostBreakdown itemizes the cost of a charge. StandardCost, CommitmentUtilized,
+// OverageCost, TrueUp, MinimumChargeAdjustment and CapAdjustment always sum to Total.
+type EffectiveCostBreakdown struct {
+	Quantity decimal.Decimal
+	// BaseCost is the cost of the quantity at list price before any modifier
+	BaseCost decimal.Decimal
+	// UnitCost is the per-unit list price used to split the charge across the commitment
+	UnitCost decimal.Decimal
+	// StandardCost is the cost billed at list price outside of any commitment
+	StandardCost decimal.Decimal
+	// CommittedQuantity is the quantity covered by the commitment
+	CommittedQuantity decimal.Decimal
+	// CommitmentUtilized is the commitment amount consumed by this charge
+	CommitmentUtilized decimal.Decimal
+	// OverageQuantity is the quantity billed beyond the commitment
+	OverageQuantity decimal.Decimal
+	// OverageCost is the cost of the overage quantity including the overage factor
+	OverageCost decimal.Decimal
+	// TrueUp is the unused commitment charged after this charge
+	TrueUp decimal.Decimal
+	// MinimumChargeAdjustment is the amount added to reach the minimum charge
+	MinimumChargeAdjustment decimal.Decimal
+	// CapAdjustment is the (non-positive) amount removed to respect the maximum charge
+	CapAdjustment decimal.Decimal
+	// Total is the effective cost of the charge
+	Total decimal.Decimal
+	// RemainingCommitment is the commitment left for subsequent charges
+	RemainingCommitment decimal.Decimal
+	// HasOverage is true when part of the charge was billed beyond the commitment
+	HasOverage bool
 }
 
 type DeletePriceRequest struct {
@@ -225,6 +225,8 @@ type UpdateSubscriptionRequest struct {
 	Status            types.SubscriptionStatus `json:"status"`
 	CancelAt          *time.Time               `json:"cancel_at,omitempty"`
 	CancelAtPeriodEnd bool                     `json:"cancel_at_period_end,omitempty"`
+	// UsageFrozen stops metering usage for the subscription without cancelling it
+	UsageFrozen *bool `json:"usage_frozen,omitempty"`
 }
 
 // CancelSubscriptionRequest represents the enhanced cancellation request
@@ -879,6 +881,10 @@ type OverrideLineItemRequest struct {
 
 	// TransformQuantity determines how to transform the quantity for this line item
 	TransformQuantity *price.TransformQuantity `json:"transform_quantity,omitempty"`
+
+	// DiscountPercent keeps the tiers of a tiered price and charges this percentage less than
+	// their output, e.g. 10 for a 10% discount, instead of redefining the price (optional)
+	DiscountPercent *decimal.Decimal `json:"discount_percent,omitempty"`
 }
 
 // OverrideEntitlementRequest allows overriding entitlement values for a subscription
@@ -921,13 +927,32 @@ func (r *OverrideLineItemRequest) Validate(
 			Mark(ierr.ErrValidation)
 	}
 
-	// At least one override field (quantity, amount, billing_model, tier_mode, tiers, or transform_quantity) must be provided
-	if r.Quantity == nil && r.Amount == nil && r.BillingModel == "" && r.TierMode == "" && len(r.Tiers) == 0 && r.TransformQuantity == nil {
+	// At least one override field (quantity, amount, billing_model, tier_mode, tiers, transform_quantity, or discount_percent) must be provided
+	if r.Quantity == nil && r.Amount == nil && r.BillingModel == "" && r.TierMode == "" && len(r.Tiers) == 0 && r.TransformQuantity == nil && r.DiscountPercent == nil {
 		return ierr.NewError("at least one override field must be provided").
-			WithHint("Specify at least one of: quantity, amount, billing_model, tier_mode, tiers, or transform_quantity for price override").
+			WithHint("Specify at least one of: quantity, amount, billing_model, tier_mode, tiers, transform_quantity, or discount_percent for price override").
 			Mark(ierr.ErrValidation)
 	}
 
+	// Validate discount percent if provided, it applies to the parent's tiers so it can't be
+	// combined with a redefinition of the price
+	if r.DiscountPercent != nil {
+		if !r.DiscountPercent.IsPositive() || r.DiscountPercent.GreaterThan(decimal.NewFromInt(100)) {
+			return ierr.NewError("discount_percent must be greater than 0 and at most 100").
+				WithHint("Override discount percent must be between 0 and 100").
+				WithReportableDetails(map[string]interface{}{
+					"discount_percent": r.DiscountPercent.String(),
+				}).
+				Mark(ierr.ErrValidation)
+		}
+
+		if r.Amount != nil || r.BillingModel != "" || r.TierMode != "" || len(r.Tiers) > 0 || r.TransformQuantity != nil {
+			return ierr.NewError("discount_percent cannot be combined with a price redefinition").
+				WithHint("Provide either discount_percent or amount, billing_model, tier_mode, tiers and transform_quantity for price override").
+				Mark(ierr.ErrValidation)
+		}
+	}
+
 	// Validate amount if provided
 	if r.Amount != nil && r.Amount.IsNegative() {
 		return ierr.NewError("amount must be non-negative").
@@ -1100,7 +1125,7 @@ func (r *OverrideLineItemRequest) Validate(
 	// If context is provided, do additional validation
 	if priceMap != nil && lineItemsByPriceID != nil && EntityId != "" {
 		// Validate that the price exists in the plan
-		_, exists := priceMap[r.PriceID]
+		originalPrice, exists := priceMap[r.PriceID]
 		if !exists {
 			return ierr.NewError("price not found in plan").
 				WithHint("Override price must be a valid price from the selected plan").
@@ -1111,6 +1136,17 @@ func (r *OverrideLineItemRequest) Validate(
 				Mark(ierr.ErrValidation)
 		}
 
+		// A discount override inherits the tier structure of the price it overrides
+		if r.DiscountPercent != nil && originalPrice.BillingModel != types.BILLING_MODEL_TIERED {
+			return ierr.NewError("discount_percent can only override tiered prices").
+				WithHint("Override discount percent requires a price with billing model TIERED").
+				WithReportableDetails(map[string]interface{}{
+					"price_id":      r.PriceID,
+					"billing_model": originalPrice.BillingModel,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+
 		// Validate that the line item exists for this price
 		_, exists = lineItemsByPriceID[r.PriceID]
 		if !exists {
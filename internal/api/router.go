@@ -61,6 +61,7 @@ type Handlers struct {
 	CronCreditGrant        *cron.CreditGrantCronHandler
 	CronInvoice            *cron.InvoiceHandler
 	CronKafkaLagMonitoring *cron.KafkaLagMonitoringHandler
+	CronEventDedup         *cron.EventDedupHandler
 }
 
 func NewRouter(handlers Handlers, cfg *config.Configuration, logger *logger.Logger, secretService service.SecretService, envAccessService service.EnvAccessService, rbacService *rbac.RBACService) *gin.Engine {
@@ -129,14 +130,19 @@ func NewRouter(handlers Handlers, cfg *config.Configuration, logger *logger.Logg
 		{
 			events.POST("", permissionMW.RequirePermission("event", "write"), handlers.Events.IngestEvent)
 			events.POST("/bulk", permissionMW.RequirePermission("event", "write"), handlers.Events.BulkIngestEvent)
+			events.POST("/usage-records", permissionMW.RequirePermission("event", "write"), handlers.Events.IngestUsageRecord)
 			events.GET("", handlers.Events.GetEvents)
 			events.POST("/query", handlers.Events.QueryEvents)
 			events.POST("/usage", handlers.Events.GetUsage)
 			events.POST("/usage/meter", handlers.Events.GetUsageByMeter)
 			events.POST("/analytics", handlers.Events.GetUsageAnalytics)
 			events.POST("/analytics-v2", handlers.Events.GetUsageAnalyticsV2)
+			events.POST("/analytics/forecast", handlers.Events.ForecastFeatureUsage)
 			events.POST("/huggingface-billing", handlers.Events.GetHuggingFaceBillingData)
 			events.GET("/monitoring", handlers.Events.GetMonitoringData)
+			events.GET("/clock-skew", handlers.Events.GetClockSkew)
+			events.POST("/status", handlers.Events.GetEventsStatus)
+			events.POST("/unbilled", handlers.Events.GetUnbilledEvents)
 		}
 
 		meters := v1Private.Group("/meters")
@@ -534,6 +540,7 @@ func NewRouter(handlers Handlers, cfg *config.Configuration, logger *logger.Logg
 	kafkaLagMonitoringGroup := cron.Group("/events")
 	{
 		kafkaLagMonitoringGroup.POST("/monitoring", handlers.CronKafkaLagMonitoring.HandleKafkaLagMonitoring)
+		kafkaLagMonitoringGroup.POST("/dedup/delete-expired", handlers.CronEventDedup.DeleteExpiredKeys)
 	}
 
 	// Settings routes
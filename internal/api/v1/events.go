@@ -44,6 +44,7 @@ func NewEventsHandler(eventService service.EventService, eventPostProcessingServ
 // @Param event body dto.IngestEventRequest true "Event data"
 // @Success 202 {object} map[string]string "message:Event accepted for processing"
 // @Failure 400 {object} ierr.ErrorResponse
+// @Failure 429 {object} ierr.ErrorResponse
 // @Failure 500 {object} ierr.ErrorResponse
 // @Router /events [post]
 func (h *EventsHandler) IngestEvent(c *gin.Context) {
@@ -57,11 +58,7 @@ func (h *EventsHandler) IngestEvent(c *gin.Context) {
 		return
 	}
 
-	if err := req.Validate(); err != nil {
-		c.Error(err)
-		return
-	}
-
+	// The request is validated by the service once the external customer id is resolved
 	err := h.eventService.CreateEvent(ctx, &req)
 	if err != nil {
 		h.log.Error("Failed to ingest event", "error", err)
@@ -81,6 +78,7 @@ func (h *EventsHandler) IngestEvent(c *gin.Context) {
 // @Param event body dto.BulkIngestEventRequest true "Event data"
 // @Success 202 {object} map[string]string "message:Event accepted for processing"
 // @Failure 400 {object} ierr.ErrorResponse
+// @Failure 429 {object} ierr.ErrorResponse
 // @Failure 500 {object} ierr.ErrorResponse
 // @Router /events/bulk [post]
 func (h *EventsHandler) BulkIngestEvent(c *gin.Context) {
@@ -104,6 +102,39 @@ func (h *EventsHandler) BulkIngestEvent(c *gin.Context) {
 	c.JSON(http.StatusAccepted, gin.H{"message": "Events accepted for processing"})
 }
 
+// @Summary Ingest usage record
+// @Description Ingest usage that was already aggregated over a period, instead of raw events
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param usage_record body dto.IngestUsageRecordRequest true "Usage record"
+// @Success 201 {object} dto.IngestUsageRecordResponse
+// @Failure 400 {object} ierr.ErrorResponse
+// @Failure 404 {object} ierr.ErrorResponse
+// @Failure 500 {object} ierr.ErrorResponse
+// @Router /events/usage-records [post]
+func (h *EventsHandler) IngestUsageRecord(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.IngestUsageRecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Failed to bind JSON", "error", err)
+		c.Error(ierr.WithError(err).
+			WithHint("Invalid request payload").
+			Mark(ierr.ErrValidation))
+		return
+	}
+
+	resp, err := h.featureUsageTrackingService.IngestUsageRecord(ctx, &req)
+	if err != nil {
+		h.log.Error("Failed to ingest usage record", "error", err)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
 // @Summary Get usage by meter
 // @Description Retrieve aggregated usage statistics using meter configuration
 // @Tags Events
@@ -389,6 +420,35 @@ func (h *EventsHandler) GetUsageAnalyticsV2(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// @Summary Forecast feature usage
+// @Description Forecast the usage of a customer's feature in the next period from its usage in the previous periods
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.ForecastFeatureUsageRequest true "Request body"
+// @Success 200 {object} dto.ForecastFeatureUsageResponse
+// @Failure 400 {object} ierr.ErrorResponse
+// @Failure 500 {object} ierr.ErrorResponse
+// @Router /events/analytics/forecast [post]
+func (h *EventsHandler) ForecastFeatureUsage(c *gin.Context) {
+	var req dto.ForecastFeatureUsageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(ierr.WithError(err).
+			WithHint("Please check the request payload").
+			Mark(ierr.ErrValidation))
+		return
+	}
+
+	response, err := h.featureUsageTrackingService.ForecastFeatureUsage(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func parseStartAndEndTime(startTimeStr, endTimeStr string) (time.Time, time.Time, error) {
 	var startTime time.Time
 	var endTime time.Time
@@ -464,6 +524,108 @@ func (h *EventsHandler) GetMonitoringData(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// @Summary Get clock skew
+// @Description Report the delay between the timestamp and the ingestion of the events of each customer and source, flagging the producers whose clock is consistently skewed (last 24 hours by default)
+// @Tags Events
+// @Produce json
+// @Security ApiKeyAuth
+// @Param start_time query string false "Start of the ingestion time range (RFC3339) - defaults to 24 hours ago"
+// @Param end_time query string false "End of the ingestion time range (RFC3339) - defaults to now"
+// @Param external_customer_id query string false "Only report the producers of this customer"
+// @Param source query string false "Only report the producers of this source"
+// @Param threshold_seconds query int false "Skew beyond which a producer is flagged - defaults to 300"
+// @Param min_events query int false "Skip the producers with fewer events - defaults to 10"
+// @Success 200 {object} dto.GetClockSkewResponse
+// @Failure 400 {object} ierr.ErrorResponse
+// @Failure 500 {object} ierr.ErrorResponse
+// @Router /events/clock-skew [get]
+func (h *EventsHandler) GetClockSkew(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.GetClockSkewRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(ierr.WithError(err).
+			WithHint("Please check the query parameters").
+			Mark(ierr.ErrValidation))
+		return
+	}
+
+	response, err := h.eventService.GetClockSkew(ctx, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get events processing status
+// @Description Retrieve the processing status of up to 1000 events at once
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.GetEventsStatusRequest true "Event IDs"
+// @Success 200 {object} dto.GetEventsStatusResponse
+// @Failure 400 {object} ierr.ErrorResponse
+// @Failure 500 {object} ierr.ErrorResponse
+// @Router /events/status [post]
+func (h *EventsHandler) GetEventsStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.GetEventsStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(ierr.WithError(err).
+			WithHint("Please check the request payload").
+			Mark(ierr.ErrValidation))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.featureUsageTrackingService.GetEventsStatus(ctx, req.EventIDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary List unbilled events
+// @Description List the events of a customer in a time window that produced no feature usage and the reason why
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.GetUnbilledEventsRequest true "Customer and time window"
+// @Success 200 {object} dto.GetUnbilledEventsResponse
+// @Failure 400 {object} ierr.ErrorResponse
+// @Failure 500 {object} ierr.ErrorResponse
+// @Router /events/unbilled [post]
+func (h *EventsHandler) GetUnbilledEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.GetUnbilledEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(ierr.WithError(err).
+			WithHint("Please check the request payload").
+			Mark(ierr.ErrValidation))
+		return
+	}
+
+	response, err := h.featureUsageTrackingService.GetUnbilledEvents(ctx, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // @Summary Get hugging face inference data
 // @Description Retrieve hugging face inference data for events
 // @Tags Events
@@ -3,17 +3,32 @@ package clickhouse
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
 
 	clickhouse_go "github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/flexprice/flexprice/internal/config"
 	"github.com/flexprice/flexprice/internal/sentry"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
 )
 
+// SharedFeatureUsageTable is the feature usage table of the tenants without a dedicated one
+const SharedFeatureUsageTable = "feature_usage"
+
 type ClickHouseStore struct {
 	conn   driver.Conn
 	sentry *sentry.Service
+
+	// replicas holds regional read-only connections keyed by region
+	replicas map[string]driver.Conn
+	// region is the read region served by this deployment
+	region string
+
+	// featureUsageTables holds the dedicated feature usage tables keyed by tenant
+	featureUsageTables map[string]string
 }
 
 func NewClickHouseStore(config *config.Configuration, sentryService *sentry.Service) (*ClickHouseStore, error) {
@@ -23,12 +38,86 @@ func NewClickHouseStore(config *config.Configuration, sentryService *sentry.Serv
 		return nil, fmt.Errorf("init clickhouse client: %w", err)
 	}
 
+	replicas := make(map[string]driver.Conn, len(config.ClickHouse.ReadReplicas))
+	closeAll := func() {
+		for _, replicaConn := range replicas {
+			_ = replicaConn.Close()
+		}
+		_ = conn.Close()
+	}
+	for _, replica := range config.ClickHouse.ReadReplicas {
+		replicaConn, err := clickhouse_go.Open(config.ClickHouse.GetReplicaClientOptions(replica))
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("init clickhouse replica client for region %s: %w", replica.Region, err)
+		}
+		replicas[replica.Region] = replicaConn
+	}
+
+	featureUsageTables, err := newFeatureUsageTables(config.ClickHouse.TenantTables)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+
 	return &ClickHouseStore{
-		conn:   conn,
-		sentry: sentryService,
+		conn:               conn,
+		sentry:             sentryService,
+		replicas:           replicas,
+		region:             config.ClickHouse.Region,
+		featureUsageTables: featureUsageTables,
 	}, nil
 }
 
+// NewClickHouseStoreWithConn creates a store over an existing connection, without read replicas
+func NewClickHouseStoreWithConn(conn driver.Conn, tenantTables []config.ClickHouseTenantTableConfig) (*ClickHouseStore, error) {
+	featureUsageTables, err := newFeatureUsageTables(tenantTables)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClickHouseStore{
+		conn:               conn,
+		featureUsageTables: featureUsageTables,
+	}, nil
+}
+
+// tableNamePattern matches a table name, optionally qualified with its database
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// newFeatureUsageTables indexes the dedicated feature usage tables by tenant. The names are
+// interpolated into queries so anything but a plain, optionally qualified, table name is rejected.
+func newFeatureUsageTables(tenantTables []config.ClickHouseTenantTableConfig) (map[string]string, error) {
+	tables := make(map[string]string, len(tenantTables))
+	for _, tenantTable := range tenantTables {
+		if !tableNamePattern.MatchString(tenantTable.FeatureUsageTable) {
+			return nil, fmt.Errorf("invalid feature usage table %q for tenant %s", tenantTable.FeatureUsageTable, tenantTable.TenantID)
+		}
+		if _, ok := tables[tenantTable.TenantID]; ok {
+			return nil, fmt.Errorf("duplicate feature usage table for tenant %s", tenantTable.TenantID)
+		}
+		tables[tenantTable.TenantID] = tenantTable.FeatureUsageTable
+	}
+	return tables, nil
+}
+
+// FeatureUsageTable returns the feature usage table of the tenant, the shared table unless the
+// tenant has a dedicated one
+func (s *ClickHouseStore) FeatureUsageTable(tenantID string) string {
+	if table, ok := s.featureUsageTables[tenantID]; ok {
+		return table
+	}
+	return SharedFeatureUsageTable
+}
+
+// FeatureUsageTables returns the shared feature usage table followed by the dedicated tables in
+// name order, for queries across tenants
+func (s *ClickHouseStore) FeatureUsageTables() []string {
+	dedicated := lo.Without(lo.Uniq(lo.Values(s.featureUsageTables)), SharedFeatureUsageTable)
+	sort.Strings(dedicated)
+	return append([]string{SharedFeatureUsageTable}, dedicated...)
+}
+
 // TracedConn returns a connection that automatically traces all database operations
 func (s *ClickHouseStore) GetConn() driver.Conn {
 	return &tracedConn{
@@ -42,7 +131,31 @@ func (s *ClickHouseStore) GetRawConn() driver.Conn {
 	return s.conn
 }
 
+// GetReadConn returns a traced connection for read-only queries. It prefers the
+// replica for the configured region and falls back to the primary when no matching
+// replica exists or a writer is forced.
+func (s *ClickHouseStore) GetReadConn(ctx context.Context) driver.Conn {
+	return &tracedConn{
+		conn:   s.selectReadConn(ctx),
+		sentry: s.sentry,
+	}
+}
+
+func (s *ClickHouseStore) selectReadConn(ctx context.Context) driver.Conn {
+	if len(s.replicas) == 0 || types.ShouldForceWriter(ctx) {
+		return s.conn
+	}
+
+	if replica, ok := s.replicas[s.region]; ok {
+		return replica
+	}
+	return s.conn
+}
+
 func (s *ClickHouseStore) Close() error {
+	for _, replica := range s.replicas {
+		_ = replica.Close()
+	}
 	return s.conn.Close()
 }
 
@@ -0,0 +1,191 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	clickhouse_go "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/flexprice/flexprice/internal/config"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConn is a named driver.Conn used to assert which connection was selected
+type fakeConn struct {
+	driver.Conn
+	name string
+}
+
+func TestSelectReadConn(t *testing.T) {
+	primary := &fakeConn{name: "primary"}
+	euReplica := &fakeConn{name: "eu-west-1"}
+	usReplica := &fakeConn{name: "us-east-1"}
+
+	replicas := map[string]driver.Conn{
+		"eu-west-1": euReplica,
+		"us-east-1": usReplica,
+	}
+
+	tests := []struct {
+		name          string
+		defaultRegion string
+		replicas      map[string]driver.Conn
+		ctx           context.Context
+		want          *fakeConn
+	}{
+		{
+			name:          "no replicas configured uses primary",
+			defaultRegion: "eu-west-1",
+			replicas:      map[string]driver.Conn{},
+			ctx:           context.Background(),
+			want:          primary,
+		},
+		{
+			name:          "configured region uses its replica",
+			defaultRegion: "us-east-1",
+			replicas:      replicas,
+			ctx:           context.Background(),
+			want:          usReplica,
+		},
+		{
+			name:          "unknown region falls back to primary",
+			defaultRegion: "ap-south-1",
+			replicas:      replicas,
+			ctx:           context.Background(),
+			want:          primary,
+		},
+		{
+			name:     "no region falls back to primary",
+			replicas: replicas,
+			ctx:      context.Background(),
+			want:     primary,
+		},
+		{
+			name:          "forced writer always uses primary",
+			defaultRegion: "eu-west-1",
+			replicas:      replicas,
+			ctx:           types.WithForceWriter(context.Background()),
+			want:          primary,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &ClickHouseStore{
+				conn:     primary,
+				replicas: tt.replicas,
+				region:   tt.defaultRegion,
+			}
+
+			got, ok := store.selectReadConn(tt.ctx).(*fakeConn)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want.name, got.name)
+		})
+	}
+}
+
+func TestGetConnAlwaysUsesPrimary(t *testing.T) {
+	primary := &fakeConn{name: "primary"}
+	store := &ClickHouseStore{
+		conn:     primary,
+		replicas: map[string]driver.Conn{"eu-west-1": &fakeConn{name: "eu-west-1"}},
+		region:   "eu-west-1",
+	}
+
+	traced, ok := store.GetConn().(*tracedConn)
+	assert.True(t, ok)
+	assert.Equal(t, primary, traced.conn)
+}
+
+func TestFeatureUsageTable(t *testing.T) {
+	store, err := NewClickHouseStoreWithConn(&fakeConn{name: "primary"}, []config.ClickHouseTenantTableConfig{
+		{TenantID: "tenant_large", FeatureUsageTable: "tenant_large.feature_usage"},
+		{TenantID: "tenant_other", FeatureUsageTable: "feature_usage_other"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "tenant_large.feature_usage", store.FeatureUsageTable("tenant_large"))
+	assert.Equal(t, "feature_usage_other", store.FeatureUsageTable("tenant_other"))
+	assert.Equal(t, SharedFeatureUsageTable, store.FeatureUsageTable("tenant_small"))
+	assert.Equal(t, []string{SharedFeatureUsageTable, "feature_usage_other", "tenant_large.feature_usage"}, store.FeatureUsageTables())
+
+	t.Run("no dedicated tables", func(t *testing.T) {
+		store, err := NewClickHouseStoreWithConn(&fakeConn{name: "primary"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, SharedFeatureUsageTable, store.FeatureUsageTable("tenant_large"))
+		assert.Equal(t, []string{SharedFeatureUsageTable}, store.FeatureUsageTables())
+	})
+
+	t.Run("rejects invalid table names", func(t *testing.T) {
+		for _, table := range []string{"", "feature_usage; DROP TABLE events", "db.schema.table", "1table", "db.`table`"} {
+			_, err := NewClickHouseStoreWithConn(&fakeConn{name: "primary"}, []config.ClickHouseTenantTableConfig{
+				{TenantID: "tenant_large", FeatureUsageTable: table},
+			})
+			assert.Error(t, err, table)
+		}
+	})
+
+	t.Run("rejects duplicate tenants", func(t *testing.T) {
+		_, err := NewClickHouseStoreWithConn(&fakeConn{name: "primary"}, []config.ClickHouseTenantTableConfig{
+			{TenantID: "tenant_large", FeatureUsageTable: "feature_usage_a"},
+			{TenantID: "tenant_large", FeatureUsageTable: "feature_usage_b"},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestIsBackPressureError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "too many parts",
+			err:  &clickhouse_go.Exception{Code: 252, Name: "DB::Exception", Message: "Too many parts (300) in table"},
+			want: true,
+		},
+		{
+			name: "too many parts wrapped as a database error",
+			err: ierr.WithError(&clickhouse_go.Exception{Code: 252, Message: "Too many parts (300) in table"}).
+				WithHint("Failed to execute batch insert for feature usage").
+				Mark(ierr.ErrDatabase),
+			want: true,
+		},
+		{
+			name: "too many simultaneous queries",
+			err:  &clickhouse_go.Exception{Code: 202, Message: "Too many simultaneous queries"},
+			want: true,
+		},
+		{
+			name: "other exception",
+			err:  &clickhouse_go.Exception{Code: 60, Message: "Table feature_usage doesn't exist"},
+			want: false,
+		},
+		{
+			name: "too many parts as text",
+			err:  errors.New("code: 252, message: Too many parts (300). Merges are processing significantly slower than inserts"),
+			want: true,
+		},
+		{
+			name: "unrelated error",
+			err:  errors.New("connection refused"),
+			want: false,
+		},
+		{
+			name: "nil",
+			err:  nil,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsBackPressureError(tt.err))
+		})
+	}
+}
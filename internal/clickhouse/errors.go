@@ -0,0 +1,45 @@
+package clickhouse
+
+import (
+	"errors"
+	"strings"
+
+	clickhouse_go "github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// ClickHouse error codes returned when the server sheds load rather than fails the query
+const (
+	errCodeTooManySimultaneousQueries int32 = 202
+	errCodeTooManyParts               int32 = 252
+)
+
+// backPressureCodes are the error codes telling the client to slow down, retrying the insert
+// right away only adds to the load
+var backPressureCodes = []int32{
+	errCodeTooManySimultaneousQueries,
+	errCodeTooManyParts,
+}
+
+// IsBackPressureError reports whether err, or an error it wraps, is ClickHouse rejecting work
+// because it is overloaded, e.g. too many parts waiting to be merged
+func IsBackPressureError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var exception *clickhouse_go.Exception
+	if errors.As(err, &exception) {
+		for _, code := range backPressureCodes {
+			if exception.Code == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Errors passed through as text, e.g. from the HTTP interface, only keep the message
+	message := err.Error()
+	return strings.Contains(message, "TOO_MANY_PARTS") ||
+		strings.Contains(message, "Too many parts") ||
+		strings.Contains(message, "TOO_MANY_SIMULTANEOUS_QUERIES")
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/Shopify/sarama"
@@ -42,6 +43,7 @@ type Configuration struct {
 	FeatureFlag              FeatureFlagConfig              `mapstructure:"feature_flag" validate:"required"`
 	Email                    EmailConfig                    `mapstructure:"email" validate:"required"`
 	RBAC                     RBACConfig                     `mapstructure:"rbac" validate:"omitempty"`
+	Customer                 CustomerConfig                 `mapstructure:"customer" validate:"omitempty"`
 }
 
 type CacheConfig struct {
@@ -92,6 +94,13 @@ type KafkaConfig struct {
 	SASLPassword           string               `mapstructure:"sasl_password"`
 	ClientID               string               `mapstructure:"client_id" validate:"required"`
 	RouteTenantsOnLazyMode []string             `mapstructure:"route_tenants_on_lazy_mode" validate:"omitempty"`
+	// ReplayTopic and ReplayConsumerGroup are the topic and consumer group replaying events, the
+	// lag of the replay consumer group is only reported when both are set
+	ReplayTopic         string `mapstructure:"replay_topic" default:""`
+	ReplayConsumerGroup string `mapstructure:"replay_consumer_group" default:""`
+	// LagReportingKinds limits the consumer groups whose lag is reported to the kinds, one of
+	// normal, backfill, lazy, replay or priority, empty reports every configured consumer group
+	LagReportingKinds []types.ConsumerGroupKind `mapstructure:"lag_reporting_kinds" validate:"omitempty"`
 }
 
 type ClickHouseConfig struct {
@@ -100,6 +109,39 @@ type ClickHouseConfig struct {
 	Username string `mapstructure:"username" validate:"required"`
 	Password string `mapstructure:"password" validate:"required"`
 	Database string `mapstructure:"database" validate:"required"`
+	// Region is the region of this deployment, used to pick its read replica.
+	// Empty means reads go to the primary.
+	Region       string                    `mapstructure:"region" validate:"omitempty"`
+	ReadReplicas []ClickHouseReplicaConfig `mapstructure:"read_replicas" validate:"omitempty,dive"`
+	// TenantTables routes the feature usage of tenants to dedicated tables, the other tenants use
+	// the shared feature_usage table
+	TenantTables []ClickHouseTenantTableConfig `mapstructure:"tenant_tables" validate:"omitempty,dive"`
+	// PropertyIndexes gates the property skip indexes of the meters to the tenants operators
+	// enable them for
+	PropertyIndexes ClickHousePropertyIndexesConfig `mapstructure:"property_indexes"`
+}
+
+// ClickHousePropertyIndexesConfig limits the property skip indexes the apply-property-indexes
+// command adds for the indexed properties of meters
+type ClickHousePropertyIndexesConfig struct {
+	// TenantIDs are the tenants whose meters get their indexed properties indexed, empty indexes none
+	TenantIDs []string `mapstructure:"tenant_ids" validate:"omitempty"`
+	// MaxIndexes caps the property indexes of a table across tenants, the properties indexed by
+	// the most meters are kept. Zero indexes none.
+	MaxIndexes int `mapstructure:"max_indexes" default:"16" validate:"gte=0"`
+}
+
+// ClickHouseReplicaConfig describes a regional read-only ClickHouse replica
+type ClickHouseReplicaConfig struct {
+	Region  string `mapstructure:"region" validate:"required"`
+	Address string `mapstructure:"address" validate:"required"`
+}
+
+// ClickHouseTenantTableConfig describes the dedicated feature usage table of a tenant. The table
+// may be qualified with its database and must have the schema of the shared table.
+type ClickHouseTenantTableConfig struct {
+	TenantID          string `mapstructure:"tenant_id" validate:"required"`
+	FeatureUsageTable string `mapstructure:"feature_usage_table" validate:"required"`
 }
 
 type LoggingConfig struct {
@@ -169,6 +211,17 @@ type SecretsConfig struct {
 type BillingConfig struct {
 	TenantID      string `mapstructure:"tenant_id" validate:"omitempty"`
 	EnvironmentID string `mapstructure:"environment_id" validate:"omitempty"`
+	// CostRoundingMode is how costs are rounded to the currency precision
+	CostRoundingMode types.CostRoundingMode `mapstructure:"cost_rounding_mode" default:"half_up"`
+	// CostRoundingGranularity is whether costs are rounded per line item or left for the invoice total
+	CostRoundingGranularity types.CostRoundingGranularity `mapstructure:"cost_rounding_granularity" default:"invoice"`
+	// CancellationBoundary is whether an event exactly at the cancellation of a subscription is billed to it
+	CancellationBoundary types.CancellationBoundary `mapstructure:"cancellation_boundary" default:"inclusive"`
+	// TrialBoundaryPhase is whether events at the end of a subscription's trial are metered as
+	// trial or paid usage, TrialBoundaryWindow widens the boundary to the events within the window
+	// either side of the trial end
+	TrialBoundaryPhase  types.TrialBoundaryPhase `mapstructure:"trial_boundary_phase" default:"paid"`
+	TrialBoundaryWindow time.Duration            `mapstructure:"trial_boundary_window" default:"0"`
 }
 
 type EventProcessingConfig struct {
@@ -207,6 +260,90 @@ type FeatureUsageTrackingConfig struct {
 	TopicBackfill         string `mapstructure:"topic_backfill" default:"v1_feature_tracking_service_backfill"`
 	RateLimitBackfill     int64  `mapstructure:"rate_limit_backfill" default:"1"`
 	ConsumerGroupBackfill string `mapstructure:"consumer_group_backfill" default:"v1_feature_tracking_service_backfill"`
+	// Max messages processed concurrently, 0 disables the cap
+	MaxInFlight         int `mapstructure:"max_in_flight" default:"0"`
+	MaxInFlightBackfill int `mapstructure:"max_in_flight_backfill" default:"0"`
+	// PausedSubscriptionPolicy controls how usage received during a subscription pause is processed
+	PausedSubscriptionPolicy types.PausedSubscriptionUsagePolicy `mapstructure:"paused_subscription_policy" default:"skip"`
+	// PreCustomerCreationPolicy controls how events timestamped before the customer was created are processed
+	PreCustomerCreationPolicy types.PreCustomerCreationEventPolicy `mapstructure:"pre_customer_creation_policy" default:"match"`
+	// PeriodCalculationFailurePolicy controls whether an event is skipped or retried when its billing period can't be calculated
+	PeriodCalculationFailurePolicy types.PeriodCalculationFailurePolicy `mapstructure:"period_calculation_failure_policy" default:"skip"`
+	// MaxEventValuePolicy controls whether events above the maximum event value of a meter are rejected or clamped
+	MaxEventValuePolicy types.MaxEventValuePolicy `mapstructure:"max_event_value_policy" default:"reject"`
+	// UnknownAggregationPolicy controls whether meters with an aggregation type that has no usage
+	// handling are treated as SUM or fail processing and analytics
+	UnknownAggregationPolicy types.UnknownAggregationPolicy `mapstructure:"unknown_aggregation_policy" default:"sum"`
+	// ReprocessMaxRange is the longest range a single reprocess run may cover, 0 disables the limit
+	ReprocessMaxRange time.Duration `mapstructure:"reprocess_max_range" default:"8760h"`
+	// ExternalCustomerIDProperty is the dot separated path of the property the external customer
+	// id is read from when an event has none, e.g. "customer.id", empty disables the fallback
+	ExternalCustomerIDProperty string `mapstructure:"external_customer_id_property" default:""`
+	// SubscriptionIDProperty is the dot separated path of the property naming the subscription an
+	// event is billed to, e.g. "subscription_id", empty matches all of the customer's subscriptions.
+	// Both feature usage and the invoices of the customer's other subscriptions honor it.
+	SubscriptionIDProperty string `mapstructure:"subscription_id_property" default:""`
+	// PeriodIDProperty is the dot separated path of the property naming the billing period an event
+	// is billed to by its period id, e.g. "period_id", so corrections can be placed into closed
+	// periods, the usage is timestamped in the period. Empty derives the period from the event timestamp.
+	PeriodIDProperty string `mapstructure:"period_id_property" default:""`
+	// CorrelationIDProperty is the dot separated path of the property holding the trace or correlation
+	// id stored with the event's feature usage, empty disables it
+	CorrelationIDProperty string `mapstructure:"correlation_id_property" default:"correlation_id"`
+	// UsageCounterSinkEnabled also adds tracked usage to the per period usage counters in postgres
+	UsageCounterSinkEnabled bool `mapstructure:"usage_counter_sink_enabled" default:"false"`
+	// EnforceHardUsageCaps stops billing the usage of a feature beyond the hard usage limit of the
+	// subscription's entitlement, the usage is still recorded and the limit itself stays included
+	EnforceHardUsageCaps bool `mapstructure:"enforce_hard_usage_caps" default:"false"`
+	// EventUsageLookupLimit caps the feature usage rows read per event when looking up usage by
+	// event ids, 0 disables the cap
+	EventUsageLookupLimit int `mapstructure:"event_usage_lookup_limit" default:"0"`
+	// DedupWindow skips an event whose id was already processed within this window, on top of the
+	// unique hash of the feature usage, as at-least-once delivery can redeliver events long after
+	// the first one. Backfilled events bypass it. 0 disables the window. The processed event ids
+	// are shared by all consumers in postgres, the expired ones are deleted by the
+	// /cron/events/dedup/delete-expired job.
+	DedupWindow time.Duration `mapstructure:"dedup_window" default:"0"`
+	// BackPressurePause pauses consumption for this long when clickhouse rejects an insert because
+	// it is overloaded, e.g. too many parts, instead of retrying the message right away. Consecutive
+	// rejections double the pause up to BackPressureMaxPause. 0 disables the pause.
+	BackPressurePause    time.Duration `mapstructure:"back_pressure_pause" default:"5s"`
+	BackPressureMaxPause time.Duration `mapstructure:"back_pressure_max_pause" default:"1m"`
+	// PriorityProperty is the dot separated path of the property tagging an event with a processing
+	// priority, e.g. "priority". Events tagged with a priority of PriorityTopics are published to
+	// its topic, the others to the regular topic. Backfilled events always use the backfill topic.
+	PriorityProperty string `mapstructure:"priority_property" default:""`
+	// PriorityTopics are the additional topics events are routed to by their priority tag, each
+	// consumed by its own handler, e.g. a high priority topic for real-time dashboards
+	PriorityTopics []FeatureUsagePriorityTopicConfig `mapstructure:"priority_topics" validate:"omitempty,dive"`
+	// EventStatusTimeout is how long after its ingestion an event without feature usage is reported
+	// as processing, it is reported as unprocessed afterwards. 0 reports it as processing forever.
+	EventStatusTimeout time.Duration `mapstructure:"event_status_timeout" default:"1h"`
+}
+
+// FeatureUsagePriorityTopicConfig describes the topic and consumer of a processing priority
+type FeatureUsagePriorityTopicConfig struct {
+	// Priority is the value of the priority property routed to the topic, matched ignoring case
+	Priority      string `mapstructure:"priority" validate:"required"`
+	Topic         string `mapstructure:"topic" validate:"required"`
+	ConsumerGroup string `mapstructure:"consumer_group" validate:"required"`
+	RateLimit     int64  `mapstructure:"rate_limit" validate:"required,gt=0"`
+	// Max messages processed concurrently, 0 disables the cap
+	MaxInFlight int `mapstructure:"max_in_flight"`
+}
+
+// PriorityTopic returns the priority topic the priority is routed to, false when the priority
+// has none
+func (c FeatureUsageTrackingConfig) PriorityTopic(priority string) (FeatureUsagePriorityTopicConfig, bool) {
+	if priority == "" {
+		return FeatureUsagePriorityTopicConfig{}, false
+	}
+	for _, topic := range c.PriorityTopics {
+		if strings.EqualFold(topic.Priority, priority) {
+			return topic, true
+		}
+	}
+	return FeatureUsagePriorityTopicConfig{}, false
 }
 
 type FeatureUsageTrackingLazyConfig struct {
@@ -216,12 +353,20 @@ type FeatureUsageTrackingLazyConfig struct {
 	TopicBackfill         string `mapstructure:"topic_backfill" default:"v1_feature_tracking_service_lazy_backfill"`
 	RateLimitBackfill     int64  `mapstructure:"rate_limit_backfill" default:"1"`
 	ConsumerGroupBackfill string `mapstructure:"consumer_group_backfill" default:"v1_feature_tracking_service_lazy_backfill"`
+	// Max messages processed concurrently, 0 disables the cap
+	MaxInFlight int `mapstructure:"max_in_flight" default:"0"`
 }
 
 type EnvAccessConfig struct {
 	UserEnvMapping map[string]map[string][]string `mapstructure:"user_env_mapping" json:"user_env_mapping" validate:"omitempty"`
 }
 
+type CustomerConfig struct {
+	// LookupKeyConflictPolicy controls how a lookup by external id that matches several customers
+	// is resolved, failing it or picking the oldest or newest customer
+	LookupKeyConflictPolicy types.CustomerLookupKeyConflictPolicy `mapstructure:"lookup_key_conflict_policy" default:"error"`
+}
+
 type FeatureFlagConfig struct {
 	EnableFeatureUsageForAnalytics bool   `mapstructure:"enable_feature_usage_for_analytics" validate:"required"`
 	ForceV1ForTenant               string `mapstructure:"force_v1_for_tenant" validate:"omitempty"`
@@ -305,6 +450,13 @@ func NewConfig() (*Configuration, error) {
 		cfg.EnvAccess.UserEnvMapping = userEnvMapping
 	}
 
+	// An unset lookup key conflict policy fails conflicting lookups, any other value must be known
+	if cfg.Customer.LookupKeyConflictPolicy != "" {
+		if err := cfg.Customer.LookupKeyConflictPolicy.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid customer.lookup_key_conflict_policy %q: %w", cfg.Customer.LookupKeyConflictPolicy, err)
+		}
+	}
+
 	return &cfg, nil
 }
 
@@ -337,6 +489,14 @@ func (c ClickHouseConfig) GetClientOptions() *clickhouse.Options {
 	return options
 }
 
+// GetReplicaClientOptions returns the client options for a read replica.
+// Replicas share credentials, database and TLS settings with the primary.
+func (c ClickHouseConfig) GetReplicaClientOptions(replica ClickHouseReplicaConfig) *clickhouse.Options {
+	options := c.GetClientOptions()
+	options.Addr = []string{replica.Address}
+	return options
+}
+
 func (c PostgresConfig) GetDSN() string {
 	return fmt.Sprintf(
 		"user=%s password=%s dbname=%s host=%s port=%d sslmode=%s",
@@ -1,10 +1,99 @@
 package config
 
 import (
+	"math"
+	"time"
+
 	"github.com/flexprice/flexprice/internal/types"
 )
 
 // EventConfig holds configuration for event processing
 type EventConfig struct {
 	PublishDestination types.PublishDestination `mapstructure:"publish_destination" default:"kafka"`
+	// NameNormalization is applied to event names at ingestion and when matching events to meters,
+	// one of none, trim or lowercase
+	NameNormalization types.EventNameNormalization `mapstructure:"name_normalization" default:"none"`
+	// Buffer batches the ingested events before they are published
+	Buffer EventBufferConfig `mapstructure:"buffer"`
+	// RateLimit limits the rate at which each customer ingests events
+	RateLimit EventRateLimitConfig `mapstructure:"rate_limit"`
+}
+
+// EventBufferConfig configures the buffering of the events ingested through the API. Buffered
+// events are published in batches once FlushSize events are buffered or FlushInterval elapses,
+// and ingestion is rejected with a too many requests error while MaxBuffered events are waiting.
+type EventBufferConfig struct {
+	// Enabled buffers the ingested events instead of publishing them one by one
+	Enabled bool `mapstructure:"enabled" default:"false"`
+	// FlushSize is the number of buffered events that triggers a flush and the largest batch published
+	FlushSize int `mapstructure:"flush_size" default:"500" validate:"gte=0"`
+	// FlushInterval is the longest an event stays buffered before it is published
+	FlushInterval time.Duration `mapstructure:"flush_interval" default:"100ms" validate:"gte=0"`
+	// MaxBuffered is the number of buffered events beyond which ingestion is rejected until the
+	// buffer drains, it is raised to FlushSize when lower
+	MaxBuffered int `mapstructure:"max_buffered" default:"10000" validate:"gte=0"`
+	// MaxRetries is the number of times an event failing to publish is retried with the next
+	// flushes before it is dropped
+	MaxRetries int `mapstructure:"max_retries" default:"3" validate:"gte=0"`
+}
+
+// EventRateLimitConfig configures the per customer rate limit of event ingestion. Each customer
+// of a tenant environment ingests up to EventsPerSecond events with bursts of up to Burst events,
+// the events beyond it are rejected with a too many requests error.
+//
+// The limits are enforced by each replica of the server on its own, they aren't shared between
+// replicas. The configured limits are divided by Replicas, so with requests balanced between the
+// replicas a customer ingests up to the configured limits across all of them.
+type EventRateLimitConfig struct {
+	// Enabled rate limits the ingestion of each customer
+	Enabled bool `mapstructure:"enabled" default:"false"`
+	// EventsPerSecond is the default sustained rate of a customer, 0 leaves customers unlimited
+	EventsPerSecond float64 `mapstructure:"events_per_second" default:"1000" validate:"gte=0"`
+	// Burst is the default number of events a customer ingests at once, raised to EventsPerSecond
+	// when lower
+	Burst int `mapstructure:"burst" default:"2000" validate:"gte=0"`
+	// Overrides replace the default limit of specific customers
+	Overrides []EventRateLimitOverride `mapstructure:"overrides" validate:"omitempty,dive"`
+	// Replicas is the number of server replicas ingesting events, the limits of each replica are
+	// the configured limits divided by it
+	Replicas int `mapstructure:"replicas" default:"1" validate:"gte=0"`
+}
+
+// EventRateLimitOverride is the ingestion rate limit of a customer
+type EventRateLimitOverride struct {
+	// TenantID restricts the override to the customer of a tenant, empty matches every tenant
+	TenantID           string  `mapstructure:"tenant_id"`
+	ExternalCustomerID string  `mapstructure:"external_customer_id" validate:"required"`
+	EventsPerSecond    float64 `mapstructure:"events_per_second" validate:"gte=0"`
+	Burst              int     `mapstructure:"burst" validate:"gte=0"`
+}
+
+// Limit returns the events per second and burst of the customer enforced by a replica, the events
+// per second are 0 when the customer is unlimited
+func (c EventRateLimitConfig) Limit(tenantID, externalCustomerID string) (float64, int) {
+	eventsPerSecond, burst := c.EventsPerSecond, c.Burst
+	for _, override := range c.Overrides {
+		if override.ExternalCustomerID != externalCustomerID {
+			continue
+		}
+		if override.TenantID != "" && override.TenantID != tenantID {
+			continue
+		}
+		eventsPerSecond, burst = override.EventsPerSecond, override.Burst
+		// an override of the tenant's customer wins over one matching every tenant
+		if override.TenantID != "" {
+			break
+		}
+	}
+
+	// each replica enforces its share of the limits
+	if c.Replicas > 1 {
+		eventsPerSecond /= float64(c.Replicas)
+		burst = int(math.Ceil(float64(burst) / float64(c.Replicas)))
+	}
+
+	if minBurst := int(math.Ceil(eventsPerSecond)); burst < minBurst {
+		burst = minBurst
+	}
+	return eventsPerSecond, burst
 }
@@ -19,6 +19,9 @@ type Webhook struct {
 	MaxElapsedTime  time.Duration                  `mapstructure:"max_elapsed_time" default:"2m"`
 	Tenants         map[string]TenantWebhookConfig `mapstructure:"tenants"`
 	Svix            Svix                           `mapstructure:"svix_config"`
+	// AlertDedupWindow is the window in which repeated balance alerts for the same customer share
+	// an idempotency key, zero disables the key
+	AlertDedupWindow time.Duration `mapstructure:"alert_dedup_window" default:"5m"`
 }
 
 // TenantWebhookConfig represents webhook configuration for a specific tenant
@@ -0,0 +1,137 @@
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/flexprice/flexprice/ent"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// AuditLog records a change to a billing configuration entity, with the entity as it was before
+// and after the change and the user who made it
+type AuditLog struct {
+	ID            string                   `json:"id"`
+	TenantID      string                   `json:"tenant_id"`
+	EnvironmentID string                   `json:"environment_id"`
+	EntityType    types.AuditLogEntityType `json:"entity_type"`
+	EntityID      string                   `json:"entity_id"`
+	Action        types.AuditLogAction     `json:"action"`
+	// Actor is the ID of the user who made the change, empty for changes made by the system
+	Actor string `json:"actor,omitempty"`
+	// Before is the entity before the change, nil when it was created
+	Before map[string]interface{} `json:"before,omitempty"`
+	// After is the entity after the change, nil when it was deleted
+	After map[string]interface{} `json:"after,omitempty"`
+	// Changes are the fields that differ between before and after, keyed by field name
+	Changes   map[string]types.AuditLogChange `json:"changes"`
+	CreatedAt time.Time                       `json:"created_at"`
+}
+
+// FromEnt converts an ent audit log to a domain audit log
+func FromEnt(e *ent.AuditLog) *AuditLog {
+	if e == nil {
+		return nil
+	}
+	return &AuditLog{
+		ID:            e.ID,
+		TenantID:      e.TenantID,
+		EnvironmentID: e.EnvironmentID,
+		EntityType:    types.AuditLogEntityType(e.EntityType),
+		EntityID:      e.EntityID,
+		Action:        types.AuditLogAction(e.Action),
+		Actor:         e.Actor,
+		Before:        e.Before,
+		After:         e.After,
+		Changes:       e.Changes,
+		CreatedAt:     e.CreatedAt,
+	}
+}
+
+// FromEntList converts a list of ent audit logs to domain audit logs
+func FromEntList(list []*ent.AuditLog) []*AuditLog {
+	auditLogs := make([]*AuditLog, len(list))
+	for i, item := range list {
+		auditLogs[i] = FromEnt(item)
+	}
+	return auditLogs
+}
+
+// ignoredFields change on every update and don't describe the change itself
+var ignoredFields = []string{"updated_at", "updated_by"}
+
+// New creates the audit log of a change from the entity before and after it. Either may be nil,
+// for a creation or a deletion. The actor is taken from the context.
+func New(ctx context.Context, entityType types.AuditLogEntityType, entityID string, action types.AuditLogAction, before, after interface{}) (*AuditLog, error) {
+	beforeSnapshot, err := snapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	afterSnapshot, err := snapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditLog{
+		ID:            types.GenerateUUIDWithPrefix(types.UUID_PREFIX_AUDIT_LOG),
+		TenantID:      types.GetTenantID(ctx),
+		EnvironmentID: types.GetEnvironmentID(ctx),
+		EntityType:    entityType,
+		EntityID:      entityID,
+		Action:        action,
+		Actor:         types.GetUserID(ctx),
+		Before:        beforeSnapshot,
+		After:         afterSnapshot,
+		Changes:       Diff(beforeSnapshot, afterSnapshot),
+		CreatedAt:     time.Now().UTC(),
+	}, nil
+}
+
+// snapshot captures the entity as its JSON fields, so later changes to it don't alter the log
+func snapshot(entity interface{}) (map[string]interface{}, error) {
+	if entity == nil || (reflect.ValueOf(entity).Kind() == reflect.Ptr && reflect.ValueOf(entity).IsNil()) {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, ierr.WithError(err).
+			WithHint("Failed to capture the entity for the audit log").
+			Mark(ierr.ErrInternal)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, ierr.WithError(err).
+			WithHint("Failed to capture the entity for the audit log").
+			Mark(ierr.ErrInternal)
+	}
+	return fields, nil
+}
+
+// Diff returns the top level fields whose value differs between before and after. A field
+// missing on one side is reported with a nil value on that side.
+func Diff(before, after map[string]interface{}) map[string]types.AuditLogChange {
+	changes := make(map[string]types.AuditLogChange)
+	for field, value := range before {
+		if !reflect.DeepEqual(value, after[field]) {
+			changes[field] = types.AuditLogChange{Before: value, After: after[field]}
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = types.AuditLogChange{Before: nil, After: value}
+		}
+	}
+
+	// Only ignored on updates, a creation or deletion reports every field
+	if before != nil && after != nil {
+		for _, field := range ignoredFields {
+			delete(changes, field)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,15 @@
+package auditlog
+
+import (
+	"context"
+
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// Repository defines the persistence operations of audit logs
+type Repository interface {
+	Create(ctx context.Context, auditLog *AuditLog) error
+
+	// ListByEntity returns the audit logs of an entity, the latest first
+	ListByEntity(ctx context.Context, entityType types.AuditLogEntityType, entityID string, limit int) ([]*AuditLog, error)
+}
@@ -1,6 +1,8 @@
 package customer
 
 import (
+	"sort"
+
 	"github.com/flexprice/flexprice/ent"
 	ierr "github.com/flexprice/flexprice/internal/errors"
 	"github.com/flexprice/flexprice/internal/types"
@@ -146,3 +148,49 @@ func ValidateAddress(c *Customer) error {
 	}
 	return nil
 }
+
+// ResolveLookupKeyMatches returns the customer a lookup by external id resolves to. Several
+// matches fail the lookup under the error policy, otherwise the oldest or newest customer is
+// picked, ties on creation time broken by id so the same customer is picked every time. An unset
+// policy is treated as error, the configured policy is validated when the config is loaded.
+func ResolveLookupKeyMatches(lookupKey string, matches []*Customer, policy types.CustomerLookupKeyConflictPolicy) (*Customer, error) {
+	if len(matches) == 0 {
+		return nil, ierr.NewError("customer not found").
+			WithHintf("Customer with lookup key %s was not found", lookupKey).
+			WithReportableDetails(map[string]any{
+				"lookup_key": lookupKey,
+			}).
+			Mark(ierr.ErrNotFound)
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+
+	ordered := make([]*Customer, len(matches))
+	copy(ordered, matches)
+	sort.Slice(ordered, func(i, j int) bool {
+		if !ordered[i].CreatedAt.Equal(ordered[j].CreatedAt) {
+			return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	switch policy {
+	case types.CustomerLookupKeyConflictPolicyOldest:
+		return ordered[0], nil
+	case types.CustomerLookupKeyConflictPolicyNewest:
+		return ordered[len(ordered)-1], nil
+	default:
+		customerIDs := make([]string, len(ordered))
+		for i, c := range ordered {
+			customerIDs[i] = c.ID
+		}
+		return nil, ierr.NewErrorf("%d customers have lookup key %s", len(ordered), lookupKey).
+			WithHintf("Multiple customers have the lookup key %s, the customer can't be determined", lookupKey).
+			WithReportableDetails(map[string]any{
+				"lookup_key":   lookupKey,
+				"customer_ids": customerIDs,
+			}).
+			Mark(ierr.ErrInvalidOperation)
+	}
+}
@@ -0,0 +1,22 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// EventDedupRepository records the ids of the events processed within the dedup window. The
+// records are shared by all consumers, so a redelivered event is recognized whichever consumer
+// receives it.
+type EventDedupRepository interface {
+	// Claim records the event id until expiresAt and returns true, or returns false when the id
+	// is already recorded and its record hasn't expired
+	Claim(ctx context.Context, eventID string, expiresAt time.Time) (bool, error)
+
+	// Release removes the record of the event id, so the event is processed again when redelivered
+	Release(ctx context.Context, eventID string) error
+
+	// DeleteExpired removes the records of all tenants that expired before the time and returns
+	// how many were removed
+	DeleteExpired(ctx context.Context, before time.Time) (int, error)
+}
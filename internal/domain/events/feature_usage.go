@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/flexprice/flexprice/internal/types"
+	"github.com/shopspring/decimal"
 )
 
 // FeatureUsageRepository defines operations for feature usage tracking
@@ -15,12 +16,20 @@ type FeatureUsageRepository interface {
 	// Bulk insert events into events_processed table
 	BulkInsertProcessedEvents(ctx context.Context, events []*FeatureUsage) error
 
+	// ReplaceProcessedEvents replaces the cancelled feature usage rows with the replacements at the
+	// same index, atomically per pair
+	ReplaceProcessedEvents(ctx context.Context, cancelled, replacements []*FeatureUsage) error
+
 	// Get processed events with filtering options
 	GetProcessedEvents(ctx context.Context, params *GetProcessedEventsParams) ([]*FeatureUsage, uint64, error)
 
 	// Check for duplicate event using unique_hash
 	IsDuplicate(ctx context.Context, subscriptionID, meterID string, periodID uint64, uniqueHash string) (bool, error)
 
+	// GetLatestProcessedEventBefore returns the latest feature usage of the customer's meter with a
+	// timestamp before the given time, nil when there is none
+	GetLatestProcessedEventBefore(ctx context.Context, customerID, meterID string, before time.Time) (*FeatureUsage, error)
+
 	// GetDetailedUsageAnalytics provides comprehensive usage analytics with filtering, grouping, and time-series data
 	GetDetailedUsageAnalytics(ctx context.Context, params *UsageAnalyticsParams, maxBucketFeatures map[string]*MaxBucketFeatureInfo) ([]*DetailedUsageAnalytic, error)
 
@@ -32,15 +41,53 @@ type FeatureUsageRepository interface {
 
 	GetUsageForMaxMetersWithBuckets(ctx context.Context, params *FeatureUsageParams) (*AggregationResult, error)
 
-	// GetFeatureUsageByEventIDs gets feature usage records by event IDs
-	GetFeatureUsageByEventIDs(ctx context.Context, eventIDs []string) ([]*FeatureUsage, error)
+	// GetFeatureUsageByEventIDs gets feature usage records by event IDs ordered by event and line item.
+	// A positive limit caps the records returned per event, so an event matched by many line items
+	// can't crowd out the records of the other events.
+	GetFeatureUsageByEventIDs(ctx context.Context, eventIDs []string, limit int) ([]*FeatureUsage, error)
+
+	// GetProcessedEventIDs returns the given event IDs that have feature usage recorded
+	GetProcessedEventIDs(ctx context.Context, eventIDs []string) ([]string, error)
+
+	// GetEventSampleIDs returns up to limit contributing event IDs per feature for the analytics window
+	GetEventSampleIDs(ctx context.Context, params *UsageAnalyticsParams, limit int) (map[string][]string, error)
+
+	// GetPropertyValuesByPrice returns the distinct values of the given event properties per price
+	// for the analytics window, keyed by price ID and then property. Up to limit values are returned
+	// per property, 0 returns them all.
+	GetPropertyValuesByPrice(ctx context.Context, params *UsageAnalyticsParams, properties []string, limit int) (map[string]map[string][]string, error)
+
+	// GetUsageSubLineItemIDs returns the distinct subscription line items with usage in the window
+	GetUsageSubLineItemIDs(ctx context.Context, startTime, endTime time.Time) ([]string, error)
+
+	// FindOrphanedUsage returns feature usage in the window of the given subscription line items,
+	// the ones that no longer exist
+	FindOrphanedUsage(ctx context.Context, params *FindOrphanedUsageParams) ([]*FeatureUsage, error)
+
+	// FindUsageWithoutCustomer returns feature usage in the window that has no customer id
+	FindUsageWithoutCustomer(ctx context.Context, params *FindUsageWithoutCustomerParams) ([]*FeatureUsage, error)
+
+	// GetPlatformUsageTotals returns usage totals per meter across all tenants and environments
+	GetPlatformUsageTotals(ctx context.Context, params *PlatformUsageParams) ([]*PlatformMeterUsage, error)
+}
+
+// PlatformMeterUsage is the usage recorded for a meter in a window across all customers
+type PlatformMeterUsage struct {
+	TenantID      string          `json:"tenant_id"`
+	EnvironmentID string          `json:"environment_id"`
+	MeterID       string          `json:"meter_id"`
+	TotalUsage    decimal.Decimal `json:"total_usage"`
+	EventCount    uint64          `json:"event_count"`
+	CustomerCount uint64          `json:"customer_count"`
 }
 
-// MaxBucketFeatureInfo contains information about a feature that uses MAX with bucket aggregation
+// MaxBucketFeatureInfo contains information about a feature that uses MAX with bucket or rolling window aggregation
 type MaxBucketFeatureInfo struct {
 	FeatureID    string
 	MeterID      string
 	BucketSize   types.WindowSize
 	EventName    string
 	PropertyName string
+	// RollingWindowHours is set instead of BucketSize for MAX over a rolling window
+	RollingWindowHours int
 }
@@ -1,6 +1,9 @@
 package events
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	ierr "github.com/flexprice/flexprice/internal/errors"
@@ -44,6 +47,14 @@ type Event struct {
 	ExternalCustomerID string `json:"external_customer_id" ch:"external_customer_id"`
 }
 
+// OriginalEventNameProperty is the property recording the name an event was sent with when
+// ingestion normalized it
+const OriginalEventNameProperty = "original_event_name"
+
+// OriginalValuesProperty is the property recording the values sent with a unit suffix, by field,
+// when ingestion converted them to the unit of their meter
+const OriginalValuesProperty = "original_values"
+
 // ProcessedEvent represents an event that has been processed for billing
 type ProcessedEvent struct {
 	// Original event fields
@@ -139,6 +150,52 @@ func (e *Event) Validate() error {
 	return validator.ValidateRequest(e)
 }
 
+// GetNestedProperty returns the property at a dot separated path, e.g. "customer.id" reads
+// the "id" key of the "customer" object property
+func (e *Event) GetNestedProperty(path string) (interface{}, bool) {
+	return NestedProperty(e.Properties, path)
+}
+
+// NestedProperty returns the property at a dot separated path of the properties
+func NestedProperty(properties map[string]interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return nil, false
+	}
+
+	var value interface{} = properties
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// ExternalCustomerIDFromProperty returns the external customer id held by the property at a dot
+// separated path of the properties. Only string and number properties hold an id.
+func ExternalCustomerIDFromProperty(properties map[string]interface{}, path string) (string, bool) {
+	value, ok := NestedProperty(properties, path)
+	if !ok {
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case float64:
+		// large ids decode as floats, format them without an exponent
+		return decimal.NewFromFloat(v).String(), true
+	case int, int64, json.Number:
+		return fmt.Sprint(v), true
+	default:
+		return "", false
+	}
+}
+
 // ToProcessedEvent creates a new ProcessedEvent from this Event with pending status
 func (e *Event) ToProcessedEvent() *ProcessedEvent {
 	return &ProcessedEvent{
@@ -169,6 +226,8 @@ type FeatureUsage struct {
 	FeatureID      string `json:"feature_id" ch:"feature_id"`
 	MeterID        string `json:"meter_id" ch:"meter_id"`
 	PeriodID       uint64 `json:"period_id" ch:"period_id"`
+	// CorrelationID traces the usage back to the request that produced the event
+	CorrelationID string `json:"correlation_id,omitempty" ch:"correlation_id"`
 	// Deduplication and metrics
 	UniqueHash string          `json:"unique_hash" ch:"unique_hash"`
 	QtyTotal   decimal.Decimal `json:"qty_total" ch:"qty_total"`
@@ -15,6 +15,7 @@ type UsageAnalyticsParams struct {
 	ExternalCustomerID string
 	FeatureIDs         []string
 	Sources            []string
+	PriceIDs           []string // Limits the usage to the usage billed under these prices
 	StartTime          time.Time
 	EndTime            time.Time
 	GroupBy            []string // Allowed values: "source", "feature_id", "properties.<field_name>"
@@ -39,6 +40,35 @@ type UsageAnalyticsParams struct {
 	// - Subscription billing periods (e.g., customer signed up on 15th)
 	// - Custom business cycles (e.g., fiscal months starting on 5th)
 	BillingAnchor *time.Time
+	// BillableOnly limits the usage to BillableWindows, the usage that is invoiced
+	BillableOnly    bool
+	BillableWindows []BillableWindow
+	// WeightProperty weights the usage of each event by the numeric value of this event property
+	// in WeightedUsage, empty leaves the usage unweighted
+	WeightProperty string
+}
+
+// BillableWindow is the window during which the usage of a subscription line item is billed
+type BillableWindow struct {
+	SubLineItemID string
+	StartTime     time.Time
+	// EndTime is zero while the line item is billed
+	EndTime time.Time
+}
+
+// IsBillable reports whether usage of the line item at t is billed, all usage is when only
+// billable usage isn't requested
+func (p *UsageAnalyticsParams) IsBillable(subLineItemID string, t time.Time) bool {
+	if !p.BillableOnly {
+		return true
+	}
+	for _, window := range p.BillableWindows {
+		if window.SubLineItemID == subLineItemID && !t.Before(window.StartTime) &&
+			(window.EndTime.IsZero() || t.Before(window.EndTime)) {
+			return true
+		}
+	}
+	return false
 }
 
 // DetailedUsageAnalytic represents detailed usage and cost data for analytics
@@ -47,24 +77,34 @@ type DetailedUsageAnalytic struct {
 	FeatureName     string
 	EventName       string
 	Source          string
+	Sources         []string // Distinct sources of the contributing events, sorted
 	MeterID         string
 	PriceID         string // Price ID used for this usage - allows tracking different prices per subscription
 	SubLineItemID   string // Subscription line item ID
 	SubscriptionID  string // Subscription ID
 	AggregationType types.AggregationType
-	Unit            string
-	UnitPlural      string
-	TotalUsage      decimal.Decimal
-	TotalCost       decimal.Decimal
-	Currency        string
-	EventCount      uint64            // Number of events that contributed to this aggregation
-	Properties      map[string]string // Stores property values for flexible grouping (e.g., org_id -> "org123")
-	Points          []UsageAnalyticPoint
+	// HeartbeatInterval is the heartbeat interval of UPTIME meters, usage is the number of
+	// intervals with a heartbeat times it
+	HeartbeatInterval time.Duration
+	Unit              string
+	UnitPlural        string
+	TotalUsage        decimal.Decimal
+	WeightedUsage     decimal.Decimal // SUM(qty_total * sign * weight property), only with a weight property
+	TotalCost         decimal.Decimal
+	Currency          string
+	EventCount        uint64            // Number of events that contributed to this aggregation
+	Properties        map[string]string // Stores property values for flexible grouping (e.g., org_id -> "org123")
+	Points            []UsageAnalyticPoint
+	PointsByWindow    map[types.WindowSize][]UsageAnalyticPoint // Points per window size when several window sizes are requested
 
 	// All aggregation values - we fetch all and use the appropriate one based on meter type
 	MaxUsage         decimal.Decimal // MAX(qty_total * sign)
 	LatestUsage      decimal.Decimal // argMax(qty_total, timestamp)
 	CountUniqueUsage uint64          // COUNT(DISTINCT unique_hash)
+
+	// CommitmentAdjustedCost is the cost after the subscription commitment is applied, nil when
+	// the subscription has no commitment or the range spans more than one billing period
+	CommitmentAdjustedCost *decimal.Decimal
 }
 
 // UsageAnalyticPoint represents a data point in a time series
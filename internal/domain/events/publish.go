@@ -0,0 +1,18 @@
+package events
+
+import "fmt"
+
+// PublishBatchError is returned when some of the events of a batch fail to publish. The other
+// events of the batch are published, so only the failed ones should be published again.
+type PublishBatchError struct {
+	Failed []*Event
+	Err    error
+}
+
+func (e *PublishBatchError) Error() string {
+	return fmt.Sprintf("failed to publish %d events: %v", len(e.Failed), e.Err)
+}
+
+func (e *PublishBatchError) Unwrap() error {
+	return e.Err
+}
@@ -2,6 +2,7 @@ package events
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/flexprice/flexprice/internal/types"
@@ -17,9 +18,13 @@ type Repository interface {
 	FindUnprocessedEvents(ctx context.Context, params *FindUnprocessedEventsParams) ([]*Event, error)
 	FindUnprocessedEventsFromFeatureUsage(ctx context.Context, params *FindUnprocessedEventsParams) ([]*Event, error)
 	GetDistinctEventNames(ctx context.Context, externalCustomerID string, startTime, endTime time.Time) ([]string, error)
+	GetEventsByIDs(ctx context.Context, eventIDs []string) ([]*Event, error)
 
 	// Monitoring methods
 	GetTotalEventCount(ctx context.Context, startTime, endTime time.Time, windowSize types.WindowSize) (*EventCountResult, error)
+	// GetClockSkewStats summarizes the delay between the timestamp and the ingestion of the events
+	// per customer and source
+	GetClockSkewStats(ctx context.Context, params *ClockSkewParams) ([]*ClockSkewStats, error)
 }
 
 // ProcessedEventRepository defines operations for processed events
@@ -71,17 +76,43 @@ type UsageAnalytic struct {
 }
 
 type UsageParams struct {
-	ExternalCustomerID string                `json:"external_customer_id"`
-	CustomerID         string                `json:"customer_id"`
-	EventName          string                `json:"event_name" validate:"required"`
-	PropertyName       string                `json:"property_name" validate:"required"`
-	AggregationType    types.AggregationType `json:"aggregation_type" validate:"required"`
-	WindowSize         types.WindowSize      `json:"window_size"`
-	BucketSize         types.WindowSize      `json:"bucket_size,omitempty"` // For windowed MAX aggregation
-	StartTime          time.Time             `json:"start_time" validate:"required"`
-	EndTime            time.Time             `json:"end_time" validate:"required"`
-	Filters            map[string][]string   `json:"filters"`
-	Multiplier         *decimal.Decimal      `json:"multiplier,omitempty" validate:"omitempty,gt=0"`
+	ExternalCustomerID string `json:"external_customer_id"`
+	CustomerID         string `json:"customer_id"`
+	EventName          string `json:"event_name" validate:"required"`
+	// EventNameMatch is exact by default, with prefix EventName matches every event name it starts
+	EventNameMatch  types.MeterEventNameMatch `json:"event_name_match,omitempty"`
+	PropertyName    string                    `json:"property_name" validate:"required"`
+	AggregationType types.AggregationType     `json:"aggregation_type" validate:"required"`
+	WindowSize      types.WindowSize          `json:"window_size"`
+	BucketSize      types.WindowSize          `json:"bucket_size,omitempty"` // For windowed MAX aggregation
+	StartTime       time.Time                 `json:"start_time" validate:"required"`
+	EndTime         time.Time                 `json:"end_time" validate:"required"`
+	Filters         map[string][]string       `json:"filters"`
+	Multiplier      *decimal.Decimal          `json:"multiplier,omitempty" validate:"omitempty,gt=0"`
+	// SubscriptionID skips the events whose SubscriptionIDProperty names another subscription, the
+	// events routed to a subscription are only billed to it
+	SubscriptionID         string `json:"subscription_id,omitempty"`
+	SubscriptionIDProperty string `json:"subscription_id_property,omitempty"`
+	// CaseInsensitiveEventName matches EventName regardless of case, as the tenant's event config sets
+	CaseInsensitiveEventName bool `json:"case_insensitive_event_name,omitempty"`
+	// RateProperty and RateTable look up the multiplier of SUM_WITH_MULTIPLIER per event by the
+	// value of the property, the events without a rate fall back to Multiplier
+	RateProperty string                     `json:"rate_property,omitempty"`
+	RateTable    map[string]decimal.Decimal `json:"rate_table,omitempty"`
+	// HeartbeatIntervalSeconds is the heartbeat interval of UPTIME aggregations
+	HeartbeatIntervalSeconds int64 `json:"heartbeat_interval_seconds,omitempty"`
+	// MinEventValue is the minimum event value of the meter, lower values are raised to it
+	MinEventValue *decimal.Decimal `json:"min_event_value,omitempty"`
+	// MaxEventValue is the maximum event value of the meter, the events above it are rejected or
+	// clamped according to MaxEventValuePolicy
+	MaxEventValue       *decimal.Decimal          `json:"max_event_value,omitempty"`
+	MaxEventValuePolicy types.MaxEventValuePolicy `json:"max_event_value_policy,omitempty"`
+	// CustomerCreatedAt and PreCustomerCreationPolicy handle the events timestamped before the
+	// customer was created as feature usage does, they're skipped or billed in the period containing
+	// PreCustomerCreationClampedAt, the time they're clamped to
+	CustomerCreatedAt            *time.Time                           `json:"customer_created_at,omitempty"`
+	PreCustomerCreationPolicy    types.PreCustomerCreationEventPolicy `json:"pre_customer_creation_policy,omitempty"`
+	PreCustomerCreationClampedAt *time.Time                           `json:"pre_customer_creation_clamped_at,omitempty"`
 	// BillingAnchor enables custom monthly billing periods for usage aggregation.
 	//
 	// Behavior by WindowSize:
@@ -100,6 +131,44 @@ type UsageParams struct {
 	BillingAnchor *time.Time `json:"billing_anchor,omitempty"`
 }
 
+// MatchesEventName reports whether an event with the name belongs to the usage
+func (p *UsageParams) MatchesEventName(eventName string) bool {
+	if p.CaseInsensitiveEventName {
+		return p.EventNameMatch.Matches(strings.ToLower(p.EventName), strings.ToLower(eventName))
+	}
+	return p.EventNameMatch.Matches(p.EventName, eventName)
+}
+
+// ExcludesPreCustomerCreationEvents reports whether the events timestamped before the customer was
+// created aren't billed in the period, they're skipped or clamped into another period
+func (p *UsageParams) ExcludesPreCustomerCreationEvents() bool {
+	if p.CustomerCreatedAt == nil {
+		return false
+	}
+
+	switch p.PreCustomerCreationPolicy {
+	case types.PreCustomerCreationEventPolicySkip:
+		return true
+	case types.PreCustomerCreationEventPolicyClamp:
+		return !p.IncludesPreCustomerCreationEvents()
+	default:
+		return false
+	}
+}
+
+// IncludesPreCustomerCreationEvents reports whether the events timestamped before the customer was
+// created are clamped into the period, they're billed in it whatever their timestamp
+func (p *UsageParams) IncludesPreCustomerCreationEvents() bool {
+	if p.CustomerCreatedAt == nil || p.PreCustomerCreationClampedAt == nil ||
+		p.PreCustomerCreationPolicy != types.PreCustomerCreationEventPolicyClamp {
+		return false
+	}
+
+	clampedAt := *p.PreCustomerCreationClampedAt
+	return (p.StartTime.IsZero() || !clampedAt.Before(p.StartTime)) &&
+		(p.EndTime.IsZero() || clampedAt.Before(p.EndTime))
+}
+
 // UsageSummaryParams defines parameters for querying pre-computed usage
 type UsageSummaryParams struct {
 	StartTime      time.Time `json:"start_time" validate:"required"`
@@ -125,6 +194,32 @@ type GetProcessedEventsParams struct {
 	CountTotal     bool      `json:"count_total"`
 }
 
+// PlatformUsageParams defines the window of a platform-wide usage query. The query covers
+// every tenant and environment, it is not scoped by the request context.
+type PlatformUsageParams struct {
+	StartTime time.Time `json:"start_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" validate:"required"`
+}
+
+// FindOrphanedUsageParams defines parameters for finding feature usage attributed to
+// subscription line items that no longer exist
+type FindOrphanedUsageParams struct {
+	StartTime time.Time `json:"start_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" validate:"required"`
+	// SubLineItemIDs are the line items that no longer exist, whose usage is returned
+	SubLineItemIDs []string `json:"sub_line_item_ids"`
+	Limit          int      `json:"limit"`
+}
+
+// FindUsageWithoutCustomerParams defines parameters for finding feature usage recorded
+// without a customer id, e.g. because the customer of its event was resolved late
+type FindUsageWithoutCustomerParams struct {
+	StartTime time.Time `json:"start_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" validate:"required"`
+	Limit     int       `json:"limit"`
+	Offset    int       `json:"offset"`
+}
+
 type GetEventsParams struct {
 	ExternalCustomerID string              `json:"external_customer_id"`
 	EventName          string              `json:"event_name" validate:"required"`
@@ -174,6 +269,35 @@ type EventCountResult struct {
 	Points     []EventCountPoint `json:"points,omitempty"`
 }
 
+// ClockSkewParams filters the events ingested in a time range whose clock skew is summarized
+type ClockSkewParams struct {
+	StartTime          time.Time `json:"start_time" validate:"required"`
+	EndTime            time.Time `json:"end_time" validate:"required"`
+	ExternalCustomerID string    `json:"external_customer_id"`
+	Source             string    `json:"source"`
+	// MaxSkew skips the events timestamped further than it from the time range, which bounds
+	// the partitions scanned since the events are ordered by timestamp and not ingestion
+	MaxSkew time.Duration `json:"max_skew"`
+	// MinEvents skips the producers with fewer events, too few to tell a skew
+	MinEvents int `json:"min_events"`
+	// Limit caps the number of producers, the most skewed first
+	Limit int `json:"limit"`
+}
+
+// ClockSkewStats is the distribution of the delay between the timestamp and the ingestion of the
+// events of a customer and source, negative when the events are timestamped in the future
+type ClockSkewStats struct {
+	ExternalCustomerID string        `json:"external_customer_id"`
+	Source             string        `json:"source"`
+	EventCount         uint64        `json:"event_count"`
+	Avg                time.Duration `json:"avg"`
+	Min                time.Duration `json:"min"`
+	P10                time.Duration `json:"p10"`
+	P50                time.Duration `json:"p50"`
+	P90                time.Duration `json:"p90"`
+	Max                time.Duration `json:"max"`
+}
+
 // FilterGroup represents a group of filters with priority
 type FilterGroup struct {
 	// ID is the identifier for the filter group. We are using the price ID
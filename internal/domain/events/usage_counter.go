@@ -0,0 +1,41 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// UsageCounter is the running total of the usage of a feature by a customer in a billing period.
+// It is kept in Postgres next to feature usage for reads that can't wait for ClickHouse merges.
+type UsageCounter struct {
+	TenantID      string          `json:"tenant_id"`
+	EnvironmentID string          `json:"environment_id"`
+	CustomerID    string          `json:"customer_id"`
+	FeatureID     string          `json:"feature_id"`
+	PeriodID      uint64          `json:"period_id"`
+	Value         decimal.Decimal `json:"value"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// UsageCounterRepository defines operations for the per period usage counters. Counters are
+// kept per feature usage row, by event id and subscription line item, so a row is counted once
+// however often its event is processed.
+type UsageCounterRepository interface {
+	// Add adds each feature usage row to the counter of its customer, feature and period, rows
+	// already added and rows without a customer or feature are skipped
+	Add(ctx context.Context, featureUsage []*FeatureUsage) error
+
+	// Cancel subtracts each feature usage row from the counter it was added to, rows never
+	// added are skipped
+	Cancel(ctx context.Context, featureUsage []*FeatureUsage) error
+
+	// Get returns the counter of a customer feature period, nil when nothing was counted
+	Get(ctx context.Context, customerID, featureID string, periodID uint64) (*UsageCounter, error)
+}
+
+// IsCountable returns true if the feature usage row can be added to a usage counter
+func (u *FeatureUsage) IsCountable() bool {
+	return u.Sign != 0 && u.CustomerID != "" && u.FeatureID != ""
+}
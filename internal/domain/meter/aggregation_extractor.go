@@ -0,0 +1,80 @@
+package meter
+
+import (
+	"sync"
+
+	"github.com/flexprice/flexprice/internal/domain/events"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/shopspring/decimal"
+)
+
+// AggregationExtractor extracts the quantity of a single event for a custom aggregation type.
+// It returns the quantity to record and the string value of the tracked field, if any.
+// Quantities recorded by custom extractors are summed when usage is aggregated.
+type AggregationExtractor func(event *events.Event, meter *Meter) (decimal.Decimal, string, error)
+
+var (
+	aggregationExtractorsMu sync.RWMutex
+	aggregationExtractors   = make(map[types.AggregationType]AggregationExtractor)
+)
+
+// RegisterAggregationExtractor registers an extractor for a custom aggregation type.
+// Deployments should register their extractors at startup, before events are processed.
+func RegisterAggregationExtractor(aggregationType types.AggregationType, extractor AggregationExtractor) error {
+	if aggregationType == "" {
+		return ierr.NewError("aggregation type is required").
+			WithHint("Please provide a custom aggregation type to register").
+			Mark(ierr.ErrValidation)
+	}
+
+	if extractor == nil {
+		return ierr.NewError("aggregation extractor is required").
+			WithHint("Please provide an extractor function for the custom aggregation type").
+			WithReportableDetails(map[string]interface{}{
+				"aggregation_type": aggregationType,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	if aggregationType.Validate() {
+		return ierr.NewError("cannot register extractor for built-in aggregation type").
+			WithHint("Custom aggregation types must not reuse a built-in aggregation type").
+			WithReportableDetails(map[string]interface{}{
+				"aggregation_type": aggregationType,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	aggregationExtractorsMu.Lock()
+	defer aggregationExtractorsMu.Unlock()
+
+	if _, ok := aggregationExtractors[aggregationType]; ok {
+		return ierr.NewError("aggregation extractor already registered").
+			WithHint("An extractor is already registered for this custom aggregation type").
+			WithReportableDetails(map[string]interface{}{
+				"aggregation_type": aggregationType,
+			}).
+			Mark(ierr.ErrAlreadyExists)
+	}
+
+	aggregationExtractors[aggregationType] = extractor
+	return nil
+}
+
+// GetAggregationExtractor returns the extractor registered for a custom aggregation type
+func GetAggregationExtractor(aggregationType types.AggregationType) (AggregationExtractor, bool) {
+	aggregationExtractorsMu.RLock()
+	defer aggregationExtractorsMu.RUnlock()
+
+	extractor, ok := aggregationExtractors[aggregationType]
+	return extractor, ok
+}
+
+// UnregisterAggregationExtractor removes the extractor registered for a custom aggregation type
+func UnregisterAggregationExtractor(aggregationType types.AggregationType) {
+	aggregationExtractorsMu.Lock()
+	defer aggregationExtractorsMu.Unlock()
+
+	delete(aggregationExtractors, aggregationType)
+}
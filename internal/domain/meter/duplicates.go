@@ -0,0 +1,123 @@
+package meter
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// duplicateKey identifies what a meter measures. Meters with the same key match the same events
+// and aggregate them identically, so they always report the same usage.
+func (m *Meter) duplicateKey() string {
+	var b strings.Builder
+	writePart := func(part string) {
+		b.WriteString(strconv.Itoa(len(part)))
+		b.WriteByte(':')
+		b.WriteString(part)
+		b.WriteByte('|')
+	}
+
+	writePart(m.EnvironmentID)
+	writePart(m.EventName)
+	writePart(string(m.Aggregation.Type))
+	writePart(m.Aggregation.Field)
+	if m.Aggregation.Multiplier != nil {
+		writePart(m.Aggregation.Multiplier.String())
+	} else {
+		writePart("")
+	}
+	writePart(m.Aggregation.RateProperty)
+	rateValues := make([]string, 0, len(m.Aggregation.RateTable))
+	for value := range m.Aggregation.RateTable {
+		rateValues = append(rateValues, value)
+	}
+	sort.Strings(rateValues)
+	writePart(strconv.Itoa(len(rateValues)))
+	for _, value := range rateValues {
+		writePart(value)
+		writePart(m.Aggregation.RateTable[value].String())
+	}
+	if m.Aggregation.MinEventValue != nil {
+		writePart(m.Aggregation.MinEventValue.String())
+	} else {
+		writePart("")
+	}
+	if m.Aggregation.MaxEventValue != nil {
+		writePart(m.Aggregation.MaxEventValue.String())
+	} else {
+		writePart("")
+	}
+	writePart(string(m.Aggregation.BucketSize))
+	writePart(string(m.ResetUsage))
+
+	// Filter and value order doesn't change which events match
+	filters := make(map[string][]string)
+	for _, f := range m.Filters {
+		filters[f.Key] = append(filters[f.Key], f.Values...)
+	}
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		values := append([]string(nil), filters[key]...)
+		sort.Strings(values)
+		writePart(key)
+		writePart(strconv.Itoa(len(values)))
+		for _, value := range values {
+			writePart(value)
+		}
+	}
+
+	return b.String()
+}
+
+// IsDuplicateOf returns true when both meters track the same event with the same aggregation,
+// filters and reset behaviour in the same environment
+func (m *Meter) IsDuplicateOf(other *Meter) bool {
+	if m == nil || other == nil || m.ID == other.ID {
+		return false
+	}
+	return m.duplicateKey() == other.duplicateKey()
+}
+
+// FindDuplicates groups the meters that are duplicates of each other. Each group is ordered
+// oldest first, so the first meter is the one to keep when merging. Meters without
+// duplicates are left out.
+func FindDuplicates(meters []*Meter) [][]*Meter {
+	groups := make(map[string][]*Meter)
+	keys := make([]string, 0)
+	for _, m := range meters {
+		if m == nil {
+			continue
+		}
+
+		key := m.duplicateKey()
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], m)
+	}
+
+	duplicates := make([][]*Meter, 0)
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+
+		sort.SliceStable(group, func(i, j int) bool {
+			if !group[i].CreatedAt.Equal(group[j].CreatedAt) {
+				return group[i].CreatedAt.Before(group[j].CreatedAt)
+			}
+			return group[i].ID < group[j].ID
+		})
+		duplicates = append(duplicates, group)
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i][0].CreatedAt.Before(duplicates[j][0].CreatedAt)
+	})
+	return duplicates
+}
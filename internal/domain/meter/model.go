@@ -1,12 +1,16 @@
 package meter
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/flexprice/flexprice/ent"
 	"github.com/flexprice/flexprice/ent/schema"
 	ierr "github.com/flexprice/flexprice/internal/errors"
 	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
 )
 
@@ -35,6 +39,24 @@ type Meter struct {
 	// total API requests do.
 	ResetUsage types.ResetUsage `db:"reset_usage" json:"reset_usage"`
 
+	// SubscriptionStatuses restricts metering to subscriptions in these statuses at the time
+	// of the event, for ex only "active" to stop counting usage while a subscription is trialing.
+	// When empty the meter counts in every status usage is tracked for.
+	SubscriptionStatuses []types.SubscriptionStatus `db:"subscription_statuses" json:"subscription_statuses,omitempty"`
+
+	// BillingMode defines whether the usage of the meter is billed. Usage of shadow meters is
+	// recorded and shows in analytics but is never charged.
+	BillingMode types.MeterBillingMode `db:"billing_mode" json:"billing_mode"`
+
+	// EventNameMatch defines how EventName is matched against the name of events. With prefix
+	// matching a meter tracks a family of events, for ex "api.users." tracks "api.users.create".
+	EventNameMatch types.MeterEventNameMatch `db:"event_name_match" json:"event_name_match"`
+
+	// IndexedProperties are the event properties usage of the meter is frequently filtered or
+	// grouped by. They are indexed in ClickHouse by the apply-property-indexes command for the
+	// tenants operators enable property indexes for.
+	IndexedProperties []string `db:"indexed_properties" json:"indexed_properties,omitempty"`
+
 	// EnvironmentID is the environment identifier for the meter
 	EnvironmentID string `db:"environment_id" json:"environment_id"`
 
@@ -64,9 +86,67 @@ type Aggregation struct {
 	// to scale up by a factor of 1000. If not provided, it will be null.
 	Multiplier *decimal.Decimal `json:"multiplier,omitempty"`
 
+	// RateProperty is the key in $event.properties the multiplier of sum_with_multiplier is looked
+	// up by in RateTable, e.g. "model" for per model rates. Events whose value has no rate in the
+	// table fall back to the static multiplier.
+	RateProperty string `json:"rate_property,omitempty"`
+
+	// RateTable holds the multiplier per value of the rate property
+	RateTable map[string]decimal.Decimal `json:"rate_table,omitempty"`
+
+	// MinEventValue is the lowest value of the field counted for an event, lower values are
+	// raised to it before aggregation. For ex a minimum of 60 with a field in seconds bills
+	// calls shorter than a minute as one minute. If not provided, values are counted as is.
+	MinEventValue *decimal.Decimal `json:"min_event_value,omitempty"`
+
+	// MaxEventValue is the highest value of the field expected for an event, it guards billing
+	// against anomalous values sent by producer bugs. Higher values are rejected or lowered to it
+	// depending on the configured max event value policy. If not provided, values are not capped.
+	MaxEventValue *decimal.Decimal `json:"max_event_value,omitempty"`
+
 	// BucketSize is used only for MAX aggregation when windowed aggregation is needed
 	// It defines the size of time windows to calculate max values within
 	BucketSize types.WindowSize `json:"bucket_size,omitempty"`
+
+	// Unit is the unit the field is aggregated in. String values with a unit suffix, e.g. "1.5GB"
+	// or "500ms", are converted to this unit when the event is ingested. If not provided, values
+	// must be plain numbers.
+	Unit types.ValueUnit `json:"unit,omitempty"`
+
+	// RollingWindowHours is used only for MAX aggregation to report the highest usage summed over
+	// a rolling window of this many hours ending at each event, e.g. max concurrent usage in the
+	// last hour. It is computed at analytics time and can't be combined with bucket_size.
+	RollingWindowHours int `json:"rolling_window_hours,omitempty"`
+
+	// FillGaps is used only for MAX and LATEST gauges to report a point for analytics windows
+	// without events, either zero or the usage of the last window with events carried over.
+	// If not provided, windows without events have no point. Filled points of bucketed meters
+	// are not buckets and have no cost.
+	FillGaps types.MeterGapFillMode `json:"fill_gaps,omitempty"`
+
+	// HeartbeatIntervalSeconds is used only for UPTIME aggregation, it is the interval the
+	// heartbeat events are sent at. Every interval with a heartbeat counts as active for the
+	// whole interval, so usage is the active duration in seconds.
+	HeartbeatIntervalSeconds int64 `json:"heartbeat_interval_seconds,omitempty"`
+}
+
+// MaxIndexedProperties is the maximum number of properties a meter can index, every index adds
+// to the storage and the insert cost of feature usage
+const MaxIndexedProperties = 5
+
+// indexedPropertyPattern matches the property names that can be indexed, they are used as is in
+// the ClickHouse index expressions
+var indexedPropertyPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+// eventValueBoundAggregationTypes are the aggregations of a numeric field a minimum or maximum
+// event value applies to
+var eventValueBoundAggregationTypes = []types.AggregationType{
+	types.AggregationSum,
+	types.AggregationSumWithMultiplier,
+	types.AggregationAvg,
+	types.AggregationMax,
+	types.AggregationLatest,
+	types.AggregationWeightedSum,
 }
 
 // FromEnt converts an Ent Meter to a domain Meter
@@ -89,14 +169,29 @@ func FromEnt(e *ent.Meter) *Meter {
 		EventName: e.EventName,
 		Name:      e.Name,
 		Aggregation: Aggregation{
-			Type:       e.Aggregation.Type,
-			Field:      e.Aggregation.Field,
-			Multiplier: e.Aggregation.Multiplier,
-			BucketSize: e.Aggregation.BucketSize,
+			Type:               e.Aggregation.Type,
+			Field:              e.Aggregation.Field,
+			Multiplier:         e.Aggregation.Multiplier,
+			RateProperty:       e.Aggregation.RateProperty,
+			RateTable:          e.Aggregation.RateTable,
+			BucketSize:         e.Aggregation.BucketSize,
+			Unit:               e.Aggregation.Unit,
+			RollingWindowHours: e.Aggregation.RollingWindowHours,
+			FillGaps:           e.Aggregation.FillGaps,
+			MinEventValue:      e.Aggregation.MinEventValue,
+			MaxEventValue:      e.Aggregation.MaxEventValue,
+
+			HeartbeatIntervalSeconds: e.Aggregation.HeartbeatIntervalSeconds,
 		},
-		Filters:       filters,
-		ResetUsage:    types.ResetUsage(e.ResetUsage),
-		EnvironmentID: e.EnvironmentID,
+		Filters:    filters,
+		ResetUsage: types.ResetUsage(e.ResetUsage),
+		SubscriptionStatuses: lo.Map(e.SubscriptionStatuses, func(status string, _ int) types.SubscriptionStatus {
+			return types.SubscriptionStatus(status)
+		}),
+		BillingMode:       types.MeterBillingMode(e.BillingMode),
+		EventNameMatch:    types.MeterEventNameMatch(e.EventNameMatch),
+		IndexedProperties: e.IndexedProperties,
+		EnvironmentID:     e.EnvironmentID,
 		BaseModel: types.BaseModel{
 			TenantID:  e.TenantID,
 			Status:    types.Status(e.Status),
@@ -135,13 +230,98 @@ func (m *Meter) ToEntFilters() []schema.MeterFilter {
 	return filters
 }
 
+// ToEntSubscriptionStatuses converts the subscription statuses to their ent representation
+func (m *Meter) ToEntSubscriptionStatuses() []string {
+	if len(m.SubscriptionStatuses) == 0 {
+		return nil
+	}
+	return lo.Map(m.SubscriptionStatuses, func(status types.SubscriptionStatus, _ int) string {
+		return string(status)
+	})
+}
+
+// IsShadow reports whether the usage of the meter is recorded without being billed
+func (m *Meter) IsShadow() bool {
+	return m.BillingMode == types.MeterBillingModeShadow
+}
+
+// MultiplierFor returns the multiplier of the event properties, the rate of the rate property
+// value when the rate table has one and the static multiplier otherwise
+func (a Aggregation) MultiplierFor(properties map[string]interface{}) *decimal.Decimal {
+	if a.RateProperty != "" {
+		if value, ok := properties[a.RateProperty]; ok && value != nil {
+			if rate, ok := a.RateTable[fmt.Sprint(value)]; ok {
+				return &rate
+			}
+		}
+	}
+	return a.Multiplier
+}
+
+// FloorEventValue raises a value extracted from an event field to the minimum event value
+func (a Aggregation) FloorEventValue(value decimal.Decimal) decimal.Decimal {
+	if a.MinEventValue == nil {
+		return value
+	}
+	return decimal.Max(value, *a.MinEventValue)
+}
+
+// BoundEventValue raises a value extracted from an event field to the minimum event value and
+// lowers it to the maximum event value
+func (a Aggregation) BoundEventValue(value decimal.Decimal) decimal.Decimal {
+	value = a.FloorEventValue(value)
+	if a.MaxEventValue == nil {
+		return value
+	}
+	return decimal.Min(value, *a.MaxEventValue)
+}
+
+// ExceedsMaxEventValue returns true if a value extracted from an event field is above the
+// maximum event value
+func (a Aggregation) ExceedsMaxEventValue(value decimal.Decimal) bool {
+	return a.MaxEventValue != nil && a.FloorEventValue(value).GreaterThan(*a.MaxEventValue)
+}
+
+// HeartbeatInterval returns the interval heartbeats of an UPTIME meter are sent at, zero for
+// other aggregations
+func (a Aggregation) HeartbeatInterval() time.Duration {
+	if a.Type != types.AggregationUptime {
+		return 0
+	}
+	return time.Duration(a.HeartbeatIntervalSeconds) * time.Second
+}
+
+// UptimeUsage returns the active duration in seconds of an UPTIME meter with heartbeats in the
+// given number of heartbeat intervals, capped at the period length when it is positive
+func UptimeUsage(heartbeats uint64, interval time.Duration, period time.Duration) decimal.Decimal {
+	active := time.Duration(heartbeats) * interval
+	if period > 0 && active > period {
+		active = period
+	}
+	return decimal.NewFromFloat(active.Seconds())
+}
+
+// CountsInSubscriptionStatus reports whether the meter records usage for a subscription in the status
+func (m *Meter) CountsInSubscriptionStatus(status types.SubscriptionStatus) bool {
+	return len(m.SubscriptionStatuses) == 0 || lo.Contains(m.SubscriptionStatuses, status)
+}
+
 // ToEntAggregation converts domain Aggregation to Ent Aggregation
 func (m *Meter) ToEntAggregation() schema.MeterAggregation {
 	return schema.MeterAggregation{
-		Type:       m.Aggregation.Type,
-		Field:      m.Aggregation.Field,
-		Multiplier: m.Aggregation.Multiplier,
-		BucketSize: m.Aggregation.BucketSize,
+		Type:               m.Aggregation.Type,
+		Field:              m.Aggregation.Field,
+		Multiplier:         m.Aggregation.Multiplier,
+		RateProperty:       m.Aggregation.RateProperty,
+		RateTable:          m.Aggregation.RateTable,
+		BucketSize:         m.Aggregation.BucketSize,
+		Unit:               m.Aggregation.Unit,
+		RollingWindowHours: m.Aggregation.RollingWindowHours,
+		FillGaps:           m.Aggregation.FillGaps,
+		MinEventValue:      m.Aggregation.MinEventValue,
+		MaxEventValue:      m.Aggregation.MaxEventValue,
+
+		HeartbeatIntervalSeconds: m.Aggregation.HeartbeatIntervalSeconds,
 	}
 }
 
@@ -162,15 +342,17 @@ func (m *Meter) Validate() error {
 			WithHint("Please specify the event name to track").
 			Mark(ierr.ErrValidation)
 	}
-	if !m.Aggregation.Type.Validate() {
+	// Custom aggregation types are valid only when an extractor is registered for them
+	_, isCustomAggregation := GetAggregationExtractor(m.Aggregation.Type)
+	if !m.Aggregation.Type.Validate() && !isCustomAggregation {
 		return ierr.NewError("invalid aggregation type").
-			WithHint("Please provide a valid aggregation type").
+			WithHint("Please provide a valid aggregation type or register an extractor for the custom aggregation type").
 			WithReportableDetails(map[string]interface{}{
 				"aggregation_type": m.Aggregation.Type,
 			}).
 			Mark(ierr.ErrValidation)
 	}
-	if m.Aggregation.Type.RequiresField() && m.Aggregation.Field == "" {
+	if !isCustomAggregation && m.Aggregation.Type.RequiresField() && m.Aggregation.Field == "" {
 		return ierr.NewError("field is required for aggregation type").
 			WithHint("Please specify a field for this aggregation type").
 			WithReportableDetails(map[string]interface{}{
@@ -192,6 +374,31 @@ func (m *Meter) Validate() error {
 				}).
 				Mark(ierr.ErrValidation)
 		}
+		if (m.Aggregation.RateProperty == "") != (len(m.Aggregation.RateTable) == 0) {
+			return ierr.NewError("rate_property and rate_table must be provided together").
+				WithHint("Please provide the property rates are looked up by along with the rate table").
+				Mark(ierr.ErrValidation)
+		}
+		for value, rate := range m.Aggregation.RateTable {
+			if rate.LessThanOrEqual(decimal.Zero) {
+				return ierr.NewError("invalid rate table multiplier").
+					WithHint("Rate table multipliers must be greater than zero").
+					WithReportableDetails(map[string]interface{}{
+						"rate_property": m.Aggregation.RateProperty,
+						"value":         value,
+						"multiplier":    rate,
+					}).
+					Mark(ierr.ErrValidation)
+			}
+		}
+	}
+	if m.Aggregation.Type != types.AggregationSumWithMultiplier && (m.Aggregation.RateProperty != "" || len(m.Aggregation.RateTable) > 0) {
+		return ierr.NewError("rate_table can only be used with SUM_WITH_MULTIPLIER aggregation").
+			WithHint("RateProperty and RateTable are only valid for SUM_WITH_MULTIPLIER aggregation type").
+			WithReportableDetails(map[string]interface{}{
+				"aggregation_type": m.Aggregation.Type,
+			}).
+			Mark(ierr.ErrValidation)
 	}
 	// Validate bucket_size is only used with MAX aggregation
 	if m.Aggregation.BucketSize != "" && m.Aggregation.Type != types.AggregationMax {
@@ -203,6 +410,110 @@ func (m *Meter) Validate() error {
 			}).
 			Mark(ierr.ErrValidation)
 	}
+	if m.Aggregation.RollingWindowHours != 0 {
+		if m.Aggregation.Type != types.AggregationMax {
+			return ierr.NewError("rolling_window_hours can only be used with MAX aggregation").
+				WithHint("RollingWindowHours is only valid for MAX aggregation type").
+				WithReportableDetails(map[string]interface{}{
+					"aggregation_type":     m.Aggregation.Type,
+					"rolling_window_hours": m.Aggregation.RollingWindowHours,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+		if m.Aggregation.RollingWindowHours < 0 {
+			return ierr.NewError("invalid rolling_window_hours").
+				WithHint("RollingWindowHours must be greater than zero").
+				WithReportableDetails(map[string]interface{}{
+					"rolling_window_hours": m.Aggregation.RollingWindowHours,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+		if m.Aggregation.BucketSize != "" {
+			return ierr.NewError("rolling_window_hours can't be combined with bucket_size").
+				WithHint("Please provide either a bucket_size or a rolling_window_hours").
+				WithReportableDetails(map[string]interface{}{
+					"bucket_size":          m.Aggregation.BucketSize,
+					"rolling_window_hours": m.Aggregation.RollingWindowHours,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+	}
+	if m.Aggregation.Type == types.AggregationUptime && m.Aggregation.HeartbeatIntervalSeconds <= 0 {
+		return ierr.NewError("heartbeat_interval_seconds is required for UPTIME aggregation").
+			WithHint("Please provide the interval in seconds the heartbeat events are sent at").
+			WithReportableDetails(map[string]interface{}{
+				"heartbeat_interval_seconds": m.Aggregation.HeartbeatIntervalSeconds,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+	if m.Aggregation.Type != types.AggregationUptime && m.Aggregation.HeartbeatIntervalSeconds != 0 {
+		return ierr.NewError("heartbeat_interval_seconds can only be used with UPTIME aggregation").
+			WithHint("HeartbeatIntervalSeconds is only valid for UPTIME aggregation type").
+			WithReportableDetails(map[string]interface{}{
+				"aggregation_type":           m.Aggregation.Type,
+				"heartbeat_interval_seconds": m.Aggregation.HeartbeatIntervalSeconds,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+	if m.Aggregation.MinEventValue != nil {
+		if !lo.Contains(eventValueBoundAggregationTypes, m.Aggregation.Type) {
+			return ierr.NewError("min_event_value can't be used with this aggregation").
+				WithHint("MinEventValue is only valid for aggregations of a numeric field").
+				WithReportableDetails(map[string]interface{}{
+					"aggregation_type": m.Aggregation.Type,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+		if !m.Aggregation.MinEventValue.IsPositive() {
+			return ierr.NewError("invalid min_event_value").
+				WithHint("MinEventValue must be greater than zero").
+				WithReportableDetails(map[string]interface{}{
+					"min_event_value": m.Aggregation.MinEventValue.String(),
+				}).
+				Mark(ierr.ErrValidation)
+		}
+	}
+	if m.Aggregation.MaxEventValue != nil {
+		if !lo.Contains(eventValueBoundAggregationTypes, m.Aggregation.Type) {
+			return ierr.NewError("max_event_value can't be used with this aggregation").
+				WithHint("MaxEventValue is only valid for aggregations of a numeric field").
+				WithReportableDetails(map[string]interface{}{
+					"aggregation_type": m.Aggregation.Type,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+		if !m.Aggregation.MaxEventValue.IsPositive() {
+			return ierr.NewError("invalid max_event_value").
+				WithHint("MaxEventValue must be greater than zero").
+				WithReportableDetails(map[string]interface{}{
+					"max_event_value": m.Aggregation.MaxEventValue.String(),
+				}).
+				Mark(ierr.ErrValidation)
+		}
+		if m.Aggregation.MinEventValue != nil && m.Aggregation.MaxEventValue.LessThan(*m.Aggregation.MinEventValue) {
+			return ierr.NewError("max_event_value is below min_event_value").
+				WithHint("MaxEventValue must be greater than or equal to MinEventValue").
+				WithReportableDetails(map[string]interface{}{
+					"min_event_value": m.Aggregation.MinEventValue.String(),
+					"max_event_value": m.Aggregation.MaxEventValue.String(),
+				}).
+				Mark(ierr.ErrValidation)
+		}
+	}
+	if m.Aggregation.FillGaps != "" {
+		if err := m.Aggregation.FillGaps.Validate(); err != nil {
+			return err
+		}
+		if m.Aggregation.Type != types.AggregationMax && m.Aggregation.Type != types.AggregationLatest {
+			return ierr.NewError("fill_gaps can only be used with MAX or LATEST aggregation").
+				WithHint("FillGaps is only valid for MAX and LATEST aggregation types").
+				WithReportableDetails(map[string]interface{}{
+					"aggregation_type": m.Aggregation.Type,
+					"fill_gaps":        m.Aggregation.FillGaps,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+	}
 	// If bucket_size is provided for MAX aggregation, validate it's a valid window size
 	if m.IsBucketedMaxMeter() {
 		if err := m.Aggregation.BucketSize.Validate(); err != nil {
@@ -230,14 +541,70 @@ func (m *Meter) Validate() error {
 				Mark(ierr.ErrValidation)
 		}
 	}
+
+	if m.Aggregation.Unit != "" {
+		if err := m.Aggregation.Unit.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, status := range m.SubscriptionStatuses {
+		if err := status.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := m.BillingMode.Validate(); err != nil {
+		return err
+	}
+
+	if err := m.EventNameMatch.Validate(); err != nil {
+		return err
+	}
+
+	if len(m.IndexedProperties) > MaxIndexedProperties {
+		return ierr.NewError("too many indexed properties").
+			WithHintf("A meter can index at most %d properties", MaxIndexedProperties).
+			WithReportableDetails(map[string]interface{}{
+				"indexed_properties": m.IndexedProperties,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+	for _, property := range m.IndexedProperties {
+		if !indexedPropertyPattern.MatchString(property) {
+			return ierr.NewError("invalid indexed property").
+				WithHint("Indexed properties must be first level property names of letters, digits, '_', '-' and '.'").
+				WithReportableDetails(map[string]interface{}{
+					"property": property,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+	}
 	return nil
 }
 
+// MatchesEventName returns true if the event name is tracked by this meter, exactly or by
+// prefix, optionally ignoring case. Both names are normalized the way event names are at ingestion.
+func (m *Meter) MatchesEventName(eventName string, caseInsensitive bool, normalization types.EventNameNormalization) bool {
+	meterEventName := normalization.Normalize(m.EventName)
+	eventName = normalization.Normalize(eventName)
+	if caseInsensitive {
+		meterEventName = strings.ToLower(meterEventName)
+		eventName = strings.ToLower(eventName)
+	}
+	return m.EventNameMatch.Matches(meterEventName, eventName)
+}
+
 // IsBucketedMaxMeter returns true if this is a max aggregation meter with bucket size
 func (m *Meter) IsBucketedMaxMeter() bool {
 	return m.Aggregation.Type == types.AggregationMax && m.Aggregation.BucketSize != ""
 }
 
+// IsRollingWindowMaxMeter returns true if this is a max aggregation meter over a rolling window
+func (m *Meter) IsRollingWindowMaxMeter() bool {
+	return m.Aggregation.Type == types.AggregationMax && m.Aggregation.RollingWindowHours > 0
+}
+
 // HasBucketSize returns true if this meter has a bucket size configured
 func (m *Meter) HasBucketSize() bool {
 	return m.Aggregation.BucketSize != ""
@@ -257,7 +624,9 @@ func NewMeter(name string, tenantID, createdBy string) *Meter {
 			UpdatedBy: createdBy,
 			Status:    types.StatusPublished,
 		},
-		Filters:    []Filter{},
-		ResetUsage: types.ResetUsageBillingPeriod,
+		Filters:        []Filter{},
+		ResetUsage:     types.ResetUsageBillingPeriod,
+		BillingMode:    types.MeterBillingModeBill,
+		EventNameMatch: types.MeterEventNameMatchExact,
 	}
 }
@@ -117,6 +117,10 @@ type Price struct {
 	// GroupID references the group this price belongs to
 	GroupID string `db:"group_id" json:"group_id,omitempty"`
 
+	// DiscountPercent is set on subscription override prices that keep the tiers of their parent
+	// price and charge this percentage less than the tier output, e.g. 10 for a 10% discount
+	DiscountPercent *decimal.Decimal `db:"discount_percent" json:"discount_percent,omitempty"`
+
 	// StartDate is the start date of the price
 	StartDate *time.Time `db:"start_date" json:"start_date,omitempty"`
 
@@ -179,6 +183,16 @@ func (p *Price) GetDisplayAmount() string {
 	return fmt.Sprintf("%s%s", p.GetCurrencySymbol(), amount)
 }
 
+// ApplyDiscountPercent reduces an amount computed from the tiers of the price by its discount
+// percent, if any
+func (p *Price) ApplyDiscountPercent(amount decimal.Decimal) decimal.Decimal {
+	if p.DiscountPercent == nil {
+		return amount
+	}
+	hundred := decimal.NewFromInt(100)
+	return amount.Mul(hundred.Sub(*p.DiscountPercent)).Div(hundred)
+}
+
 // CalculateAmount performs calculation
 func (p *Price) CalculateAmount(quantity decimal.Decimal) decimal.Decimal {
 	// Calculate with full precision
@@ -405,6 +419,7 @@ func FromEnt(e *ent.Price) *Price {
 		EntityID:               lo.FromPtr(e.EntityID),
 		ParentPriceID:          lo.FromPtr(e.ParentPriceID),
 		GroupID:                lo.FromPtr(e.GroupID),
+		DiscountPercent:        discountPercentFromEnt(e.DiscountPercent),
 		StartDate:              e.StartDate,
 		EndDate:                e.EndDate,
 		BaseModel: types.BaseModel{
@@ -418,6 +433,13 @@ func FromEnt(e *ent.Price) *Price {
 	}
 }
 
+func discountPercentFromEnt(v *float64) *decimal.Decimal {
+	if v == nil {
+		return nil
+	}
+	return lo.ToPtr(decimal.NewFromFloat(*v))
+}
+
 // FromEntList converts a list of Ent Prices to domain Prices
 func FromEntList(list []*ent.Price) []*Price {
 	if list == nil {
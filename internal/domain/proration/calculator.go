@@ -45,6 +45,11 @@ func (c *calculatorImpl) Calculate(ctx context.Context, params ProrationParams)
 			Mark(ierr.ErrValidation)
 	}
 
+	// A period without a duration can't be prorated, the day based strategy would charge it in full
+	if err := types.ValidatePeriodDuration(params.CurrentPeriodStart, params.CurrentPeriodEnd); err != nil {
+		return nil, err
+	}
+
 	// Load customer timezone
 	loc, err := time.LoadLocation(params.CustomerTimezone)
 	if err != nil {
@@ -321,9 +326,6 @@ func validateParams(params ProrationParams) error {
 	if params.CurrentPeriodStart.IsZero() || params.CurrentPeriodEnd.IsZero() {
 		return fmt.Errorf("billing period start and end dates are required")
 	}
-	if params.CurrentPeriodEnd.Before(params.CurrentPeriodStart) {
-		return fmt.Errorf("billing period end date cannot be before start date")
-	}
 	if params.CustomerTimezone == "" {
 		return fmt.Errorf("customer timezone is required")
 	}
@@ -499,3 +499,41 @@ func TestCalculator_CapCreditAmount(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculator_ZeroDurationPeriod(t *testing.T) {
+	periodStart := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		periodEnd time.Time
+		strategy  types.ProrationStrategy
+	}{
+		{name: "zero_duration_day_based", periodEnd: periodStart, strategy: types.StrategyDayBased},
+		{name: "zero_duration_second_based", periodEnd: periodStart, strategy: types.StrategySecondBased},
+		{name: "negative_duration_day_based", periodEnd: periodStart.Add(-time.Hour), strategy: types.StrategyDayBased},
+	}
+
+	logger, err := logger.NewLogger(config.GetDefaultConfig())
+	require.NoError(t, err)
+	calculator := NewCalculator(logger)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calculator.Calculate(context.Background(), ProrationParams{
+				Action:             types.ProrationActionAddItem,
+				NewPriceID:         "price_new",
+				NewQuantity:        decimal.NewFromInt(1),
+				NewPricePerUnit:    decimal.NewFromInt(20),
+				ProrationDate:      periodStart,
+				CurrentPeriodStart: periodStart,
+				CurrentPeriodEnd:   tt.periodEnd,
+				CustomerTimezone:   "UTC",
+				ProrationBehavior:  types.ProrationBehaviorCreateProrations,
+				ProrationStrategy:  tt.strategy,
+				PlanPayInAdvance:   true,
+				Currency:           "USD",
+			})
+			assert.Nil(t, result)
+			require.Error(t, err)
+			assert.True(t, types.IsZeroDurationPeriod(err))
+		})
+	}
+}
@@ -123,6 +123,8 @@ type Subscription struct {
 	// InvoicingCustomerID is the customer ID to use for invoicing
 	// This can differ from the subscription customer (e.g., parent company invoicing for child company)
 	InvoicingCustomerID *string `db:"invoicing_customer_id" json:"invoicing_customer_id,omitempty"`
+	// UsageFrozen stops metering usage for the subscription while leaving it active
+	UsageFrozen bool `db:"usage_frozen" json:"usage_frozen"`
 
 	types.BaseModel
 }
@@ -206,6 +208,7 @@ func GetSubscriptionFromEnt(sub *ent.Subscription) *Subscription {
 		ProrationBehavior:   types.ProrationBehavior(sub.ProrationBehavior),
 		EnableTrueUp:        sub.EnableTrueUp,
 		InvoicingCustomerID: sub.InvoicingCustomerID,
+		UsageFrozen:         sub.UsageFrozen,
 		BaseModel: types.BaseModel{
 			TenantID:  sub.TenantID,
 			Status:    types.Status(sub.Status),
@@ -20,6 +20,7 @@ var (
 	ErrDatabase         = new(ErrCodeDatabase, "database error")
 	ErrSystem           = new(ErrCodeSystemError, "system error")
 	ErrInternal         = new(ErrCodeInternalError, "internal error")
+	ErrTooManyRequests  = new(ErrCodeTooManyRequests, "too many requests")
 	// maps errors to http status codes
 	statusCodeMap = map[error]int{
 		ErrHTTPClient:       http.StatusInternalServerError,
@@ -32,6 +33,7 @@ var (
 		ErrPermissionDenied: http.StatusForbidden,
 		ErrSystem:           http.StatusInternalServerError,
 		ErrInternal:         http.StatusInternalServerError,
+		ErrTooManyRequests:  http.StatusTooManyRequests,
 	}
 )
 
@@ -46,6 +48,7 @@ const (
 	ErrCodeInvalidOperation = "invalid_operation"
 	ErrCodePermissionDenied = "permission_denied"
 	ErrCodeDatabase         = "database_error"
+	ErrCodeTooManyRequests  = "too_many_requests"
 )
 
 // InternalError represents a domain error
@@ -97,6 +100,11 @@ func As(err error, target any) bool {
 	return errors.As(err, target)
 }
 
+// Is checks if an error is, or is marked with, the reference error
+func Is(err, reference error) bool {
+	return errors.Is(err, reference)
+}
+
 // IsNotFound checks if an error is a not found error
 func IsNotFound(err error) bool {
 	return errors.Is(err, ErrNotFound)
@@ -139,6 +147,11 @@ func IsPermissionDenied(err error) bool {
 	return errors.Is(err, ErrPermissionDenied)
 }
 
+// IsTooManyRequests checks if an error is a too many requests error
+func IsTooManyRequests(err error) bool {
+	return errors.Is(err, ErrTooManyRequests)
+}
+
 // IsHTTPClient checks if an error is an http client error
 func IsHTTPClient(err error) bool {
 	return errors.Is(err, ErrHTTPClient)
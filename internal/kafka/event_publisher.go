@@ -3,6 +3,7 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 
@@ -30,9 +31,70 @@ func NewEventPublisher(producer *Producer, cfg *config.Configuration, logger *lo
 }
 
 func (p *EventPublisher) Publish(ctx context.Context, event *events.Event) error {
-	payload, err := json.Marshal(event)
+	msg, err := p.message(event)
 	if err != nil {
+		return err
+	}
+
+	/*
+		TODO: Once we support multiple event import integrations (e.g., S3, Postgres, etc.),
+		route those imported events to the lazy topic.
+	*/
+	if err := p.producer.Publish(p.determineTopic(event), msg); err != nil {
 		return ierr.WithError(err).
+			WithHint("Failed to publish event").
+			Mark(ierr.ErrValidation)
+	}
+	return nil
+}
+
+// PublishBatch publishes the events with a single publish per topic. A topic failing to publish
+// doesn't stop the other topics from being published, its events are returned in a
+// PublishBatchError.
+func (p *EventPublisher) PublishBatch(ctx context.Context, batch []*events.Event) error {
+	topics := make([]string, 0, 1)
+	messages := make(map[string][]*message.Message)
+	batches := make(map[string][]*events.Event)
+	for _, event := range batch {
+		msg, err := p.message(event)
+		if err != nil {
+			return err
+		}
+
+		topic := p.determineTopic(event)
+		if _, ok := messages[topic]; !ok {
+			topics = append(topics, topic)
+		}
+		messages[topic] = append(messages[topic], msg)
+		batches[topic] = append(batches[topic], event)
+	}
+
+	var failed []*events.Event
+	var errs []error
+	for _, topic := range topics {
+		if err := p.producer.Publish(topic, messages[topic]...); err != nil {
+			failed = append(failed, batches[topic]...)
+			errs = append(errs, ierr.WithError(err).
+				WithHint("Failed to publish events").
+				WithReportableDetails(map[string]interface{}{
+					"topic":  topic,
+					"events": len(messages[topic]),
+				}).
+				Mark(ierr.ErrValidation))
+		}
+	}
+
+	if len(failed) > 0 {
+		return &events.PublishBatchError{Failed: failed, Err: errors.Join(errs...)}
+	}
+	return nil
+}
+
+// message builds the kafka message of the event
+func (p *EventPublisher) message(event *events.Event) (*message.Message, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, ierr.WithError(err).
 			WithHint("Failed to marshal event").
 			Mark(ierr.ErrValidation)
 	}
@@ -58,16 +120,7 @@ func (p *EventPublisher) Publish(ctx context.Context, event *events.Event) error
 	msg.Metadata.Set("tenant_id", event.TenantID)
 	msg.Metadata.Set("environment_id", event.EnvironmentID)
 	msg.Metadata.Set("partition_key", partitionKey)
-	/*
-		TODO: Once we support multiple event import integrations (e.g., S3, Postgres, etc.),
-		route those imported events to the lazy topic.
-	*/
-	if err := p.producer.Publish(p.determineTopic(event), msg); err != nil {
-		return ierr.WithError(err).
-			WithHint("Failed to publish event").
-			Mark(ierr.ErrValidation)
-	}
-	return nil
+	return msg, nil
 }
 
 func (p *EventPublisher) determineTopic(event *events.Event) string {
@@ -7,6 +7,8 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/flexprice/flexprice/internal/config"
 	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
 )
 
 // ConsumerLag represents the lag for a consumer group on a topic
@@ -17,6 +19,84 @@ type ConsumerLag struct {
 	PartitionLags map[int32]int64
 }
 
+// ConsumerLagQuerier queries the lag of a consumer group on a topic
+type ConsumerLagQuerier interface {
+	GetConsumerLag(ctx context.Context, topic string, consumerGroup string) (*ConsumerLag, error)
+}
+
+// ConsumerGroup is a configured consumer group whose lag is reported
+type ConsumerGroup struct {
+	// Pipeline is the processing stage consuming the topic, e.g. event_consumption
+	Pipeline      string
+	Kind          types.ConsumerGroupKind
+	Topic         string
+	ConsumerGroup string
+}
+
+// ConsumerGroupLag is the lag of a configured consumer group, Error is set when it couldn't be queried
+type ConsumerGroupLag struct {
+	ConsumerGroup
+	TotalLag      int64
+	PartitionLags map[int32]int64
+	Error         error
+}
+
+// Consumer pipelines whose consumer group lag is reported
+const (
+	PipelineEventConsumption    = "event_consumption"
+	PipelineEventPostProcessing = "event_post_processing"
+	PipelineReplay              = "replay"
+)
+
+// ConfiguredConsumerGroups returns the consumer groups of the event consumption and post processing
+// pipelines, limited to the lag reporting kinds of the kafka config when set. Groups without a
+// topic or consumer group configured are left out.
+func ConfiguredConsumerGroups(cfg *config.Configuration) []ConsumerGroup {
+	groups := []ConsumerGroup{
+		{PipelineEventConsumption, types.ConsumerGroupKindNormal, cfg.EventProcessing.Topic, cfg.EventProcessing.ConsumerGroup},
+		{PipelineEventConsumption, types.ConsumerGroupKindBackfill, cfg.EventProcessing.TopicBackfill, cfg.EventProcessing.ConsumerGroupBackfill},
+		{PipelineEventConsumption, types.ConsumerGroupKindLazy, cfg.EventProcessingLazy.Topic, cfg.EventProcessingLazy.ConsumerGroup},
+		{PipelineEventPostProcessing, types.ConsumerGroupKindNormal, cfg.FeatureUsageTracking.Topic, cfg.FeatureUsageTracking.ConsumerGroup},
+		{PipelineEventPostProcessing, types.ConsumerGroupKindBackfill, cfg.FeatureUsageTracking.TopicBackfill, cfg.FeatureUsageTracking.ConsumerGroupBackfill},
+		{PipelineEventPostProcessing, types.ConsumerGroupKindLazy, cfg.FeatureUsageTrackingLazy.Topic, cfg.FeatureUsageTrackingLazy.ConsumerGroup},
+		{PipelineReplay, types.ConsumerGroupKindReplay, cfg.Kafka.ReplayTopic, cfg.Kafka.ReplayConsumerGroup},
+	}
+	for _, priority := range cfg.FeatureUsageTracking.PriorityTopics {
+		groups = append(groups, ConsumerGroup{PipelineEventPostProcessing, types.ConsumerGroupKindPriority, priority.Topic, priority.ConsumerGroup})
+	}
+
+	return lo.Filter(groups, func(g ConsumerGroup, _ int) bool {
+		if g.Topic == "" || g.ConsumerGroup == "" {
+			return false
+		}
+		return len(cfg.Kafka.LagReportingKinds) == 0 || lo.Contains(cfg.Kafka.LagReportingKinds, g.Kind)
+	})
+}
+
+// GetConsumerGroupLags queries the lag of each consumer group. A group whose lag can't be
+// queried is returned with its error so it doesn't hide the lag of the others.
+func GetConsumerGroupLags(ctx context.Context, querier ConsumerLagQuerier, groups []ConsumerGroup) []*ConsumerGroupLag {
+	lags := make([]*ConsumerGroupLag, 0, len(groups))
+	for _, group := range groups {
+		lag, err := querier.GetConsumerLag(ctx, group.Topic, group.ConsumerGroup)
+		if err != nil {
+			lags = append(lags, &ConsumerGroupLag{
+				ConsumerGroup: group,
+				PartitionLags: make(map[int32]int64),
+				Error:         err,
+			})
+			continue
+		}
+
+		lags = append(lags, &ConsumerGroupLag{
+			ConsumerGroup: group,
+			TotalLag:      lag.TotalLag,
+			PartitionLags: lag.PartitionLags,
+		})
+	}
+	return lags
+}
+
 // MonitoringService provides Kafka monitoring capabilities
 type MonitoringService struct {
 	config *config.Configuration
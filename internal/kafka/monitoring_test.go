@@ -0,0 +1,147 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLagQuerier struct {
+	// lags by consumer group, a group without a lag fails the query
+	lags    map[string]int64
+	queried []string
+}
+
+func (m *mockLagQuerier) GetConsumerLag(ctx context.Context, topic string, consumerGroup string) (*ConsumerLag, error) {
+	m.queried = append(m.queried, topic+":"+consumerGroup)
+	lag, ok := m.lags[consumerGroup]
+	if !ok {
+		return nil, errors.New("unknown consumer group")
+	}
+	return &ConsumerLag{
+		Topic:         topic,
+		ConsumerGroup: consumerGroup,
+		TotalLag:      lag,
+		PartitionLags: map[int32]int64{0: lag},
+	}, nil
+}
+
+func testMonitoringConfig() *config.Configuration {
+	cfg := &config.Configuration{}
+	cfg.EventProcessing = config.EventProcessingConfig{
+		Topic:                 "events",
+		ConsumerGroup:         "event_processing",
+		TopicBackfill:         "events_backfill",
+		ConsumerGroupBackfill: "event_processing_backfill",
+	}
+	cfg.EventProcessingLazy = config.EventProcessingLazyConfig{
+		Topic:         "events_lazy",
+		ConsumerGroup: "event_processing_lazy",
+	}
+	cfg.FeatureUsageTracking = config.FeatureUsageTrackingConfig{
+		Topic:                 "events",
+		ConsumerGroup:         "feature_tracking",
+		TopicBackfill:         "feature_tracking_backfill",
+		ConsumerGroupBackfill: "feature_tracking_backfill",
+	}
+	cfg.FeatureUsageTrackingLazy = config.FeatureUsageTrackingLazyConfig{
+		Topic:         "events_lazy",
+		ConsumerGroup: "feature_tracking_lazy",
+	}
+	return cfg
+}
+
+func TestConfiguredConsumerGroups(t *testing.T) {
+	t.Run("every configured consumer group", func(t *testing.T) {
+		cfg := testMonitoringConfig()
+		cfg.Kafka.ReplayTopic = "events"
+		cfg.Kafka.ReplayConsumerGroup = "event_replay"
+
+		groups := ConfiguredConsumerGroups(cfg)
+		require.Len(t, groups, 7)
+		assert.Equal(t, ConsumerGroup{
+			Pipeline:      PipelineEventPostProcessing,
+			Kind:          types.ConsumerGroupKindBackfill,
+			Topic:         "feature_tracking_backfill",
+			ConsumerGroup: "feature_tracking_backfill",
+		}, groups[4])
+		assert.Equal(t, ConsumerGroup{
+			Pipeline:      PipelineReplay,
+			Kind:          types.ConsumerGroupKindReplay,
+			Topic:         "events",
+			ConsumerGroup: "event_replay",
+		}, groups[6])
+	})
+
+	t.Run("replay needs a topic and a consumer group", func(t *testing.T) {
+		cfg := testMonitoringConfig()
+		cfg.Kafka.ReplayConsumerGroup = "event_replay"
+
+		groups := ConfiguredConsumerGroups(cfg)
+		assert.Len(t, groups, 6)
+		for _, g := range groups {
+			assert.NotEqual(t, types.ConsumerGroupKindReplay, g.Kind)
+		}
+	})
+
+	t.Run("priority consumer groups", func(t *testing.T) {
+		cfg := testMonitoringConfig()
+		cfg.FeatureUsageTracking.PriorityTopics = []config.FeatureUsagePriorityTopicConfig{
+			{Priority: "high", Topic: "events_high", ConsumerGroup: "feature_tracking_high", RateLimit: 10},
+		}
+
+		groups := ConfiguredConsumerGroups(cfg)
+		require.Len(t, groups, 7)
+		assert.Equal(t, ConsumerGroup{
+			Pipeline:      PipelineEventPostProcessing,
+			Kind:          types.ConsumerGroupKindPriority,
+			Topic:         "events_high",
+			ConsumerGroup: "feature_tracking_high",
+		}, groups[6])
+	})
+
+	t.Run("limited to the lag reporting kinds", func(t *testing.T) {
+		cfg := testMonitoringConfig()
+		cfg.Kafka.LagReportingKinds = []types.ConsumerGroupKind{types.ConsumerGroupKindBackfill}
+
+		groups := ConfiguredConsumerGroups(cfg)
+		require.Len(t, groups, 2)
+		assert.Equal(t, "event_processing_backfill", groups[0].ConsumerGroup)
+		assert.Equal(t, "feature_tracking_backfill", groups[1].ConsumerGroup)
+	})
+}
+
+func TestGetConsumerGroupLags(t *testing.T) {
+	querier := &mockLagQuerier{
+		lags: map[string]int64{
+			"event_processing":          3,
+			"event_processing_backfill": 1200,
+		},
+	}
+	groups := []ConsumerGroup{
+		{Pipeline: PipelineEventConsumption, Kind: types.ConsumerGroupKindNormal, Topic: "events", ConsumerGroup: "event_processing"},
+		{Pipeline: PipelineEventConsumption, Kind: types.ConsumerGroupKindLazy, Topic: "events_lazy", ConsumerGroup: "event_processing_lazy"},
+		{Pipeline: PipelineEventConsumption, Kind: types.ConsumerGroupKindBackfill, Topic: "events_backfill", ConsumerGroup: "event_processing_backfill"},
+	}
+
+	lags := GetConsumerGroupLags(context.Background(), querier, groups)
+	require.Len(t, lags, 3)
+	assert.Equal(t, []string{"events:event_processing", "events_lazy:event_processing_lazy", "events_backfill:event_processing_backfill"}, querier.queried)
+
+	assert.Equal(t, groups[0], lags[0].ConsumerGroup)
+	assert.Equal(t, int64(3), lags[0].TotalLag)
+	assert.NoError(t, lags[0].Error)
+
+	// A failed query is reported without hiding the lag of the groups after it
+	assert.Equal(t, groups[1], lags[1].ConsumerGroup)
+	assert.Error(t, lags[1].Error)
+	assert.Zero(t, lags[1].TotalLag)
+
+	assert.Equal(t, int64(1200), lags[2].TotalLag)
+	assert.Equal(t, map[int32]int64{0: 1200}, lags[2].PartitionLags)
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/logger"
+	"go.uber.org/fx"
+)
+
+// DefaultExportInterval is how often the exporter logs the recorded metrics
+const DefaultExportInterval = time.Minute
+
+// LogExporter periodically logs the aggregates recorded by a recorder since the previous export,
+// one structured log line per metric and tenant, so log based alerting can pick them up
+type LogExporter struct {
+	recorder *InMemoryRecorder
+	logger   *logger.Logger
+	interval time.Duration
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewLogExporter creates an exporter of the recorder, it exports once started
+func NewLogExporter(recorder *InMemoryRecorder, logger *logger.Logger, interval time.Duration) *LogExporter {
+	if interval <= 0 {
+		interval = DefaultExportInterval
+	}
+	return &LogExporter{
+		recorder: recorder,
+		logger:   logger,
+		interval: interval,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Start exports the recorded metrics every interval until the exporter is stopped
+func (e *LogExporter) Start() {
+	go func() {
+		defer close(e.stopped)
+
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.Export()
+			case <-e.done:
+				e.Export()
+				return
+			}
+		}
+	}()
+}
+
+// Stop exports the metrics recorded since the last export and stops the exporter
+func (e *LogExporter) Stop(ctx context.Context) error {
+	close(e.done)
+	select {
+	case <-e.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Export logs the metrics recorded since the last export
+func (e *LogExporter) Export() {
+	stats, counters := e.recorder.Drain()
+	for name, byTenant := range stats {
+		for tenantID, value := range byTenant {
+			e.logger.Infow("metric",
+				"metric", name,
+				"tenant_id", tenantID,
+				"count", value.Count,
+				"avg_ms", value.Avg().Milliseconds(),
+				"max_ms", value.Max.Milliseconds(),
+				"last_ms", value.Last.Milliseconds(),
+				"interval", e.interval.String(),
+			)
+		}
+	}
+	for name, byTenant := range counters {
+		for tenantID, value := range byTenant {
+			e.logger.Infow("metric",
+				"metric", name,
+				"tenant_id", tenantID,
+				"count", value,
+				"interval", e.interval.String(),
+			)
+		}
+	}
+}
+
+// RegisterHooks exports the metrics of the Default recorder while the application runs
+func RegisterHooks(lc fx.Lifecycle, logger *logger.Logger) {
+	exporter := NewLogExporter(Default(), logger, DefaultExportInterval)
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			exporter.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return exporter.Stop(ctx)
+		},
+	})
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogExporter(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	recorder := NewInMemoryRecorder()
+	exporter := NewLogExporter(recorder, &logger.Logger{SugaredLogger: zap.New(core).Sugar()}, time.Hour)
+
+	recorder.IncrementCounter(MetricPeriodCalculationFailure, "tenant_1")
+	recorder.IncrementCounter(MetricPeriodCalculationFailure, "tenant_1")
+	recorder.RecordDuration(MetricEventProcessingLag, "tenant_2", 3*time.Second)
+
+	exporter.Start()
+	require.NoError(t, exporter.Stop(context.Background()))
+
+	entries := logs.FilterMessage("metric").AllUntimed()
+	require.Len(t, entries, 2)
+	exported := make(map[string]map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		fields := entry.ContextMap()
+		exported[fields["metric"].(string)] = fields
+	}
+	assert.Equal(t, "tenant_1", exported[MetricPeriodCalculationFailure]["tenant_id"])
+	assert.EqualValues(t, 2, exported[MetricPeriodCalculationFailure]["count"])
+	assert.Equal(t, "tenant_2", exported[MetricEventProcessingLag]["tenant_id"])
+	assert.EqualValues(t, 3000, exported[MetricEventProcessingLag]["max_ms"])
+
+	// exported metrics are reset so each export covers the interval since the previous one
+	exporter.Export()
+	assert.Len(t, logs.FilterMessage("metric").AllUntimed(), 2)
+}
+
+func TestRegisterHooksExportsDefaultRecorder(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	lc := fxtest.NewLifecycle(t)
+	RegisterHooks(lc, &logger.Logger{SugaredLogger: zap.New(core).Sugar()})
+	lc.RequireStart()
+
+	// the services record to the default recorder, e.g. a subscription whose period failed
+	Default().IncrementCounter(MetricPeriodCalculationFailure, "tenant_hooks")
+	Default().IncrementCounter(MetricEventValueAnomaly, "tenant_hooks")
+	lc.RequireStop()
+
+	exported := make(map[string]interface{})
+	for _, entry := range logs.FilterMessage("metric").FilterField(zap.String("tenant_id", "tenant_hooks")).AllUntimed() {
+		fields := entry.ContextMap()
+		exported[fields["metric"].(string)] = fields["count"]
+	}
+	assert.EqualValues(t, 1, exported[MetricPeriodCalculationFailure])
+	assert.EqualValues(t, 1, exported[MetricEventValueAnomaly])
+}
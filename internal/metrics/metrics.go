@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Metric names recorded by the event processing pipeline
+const (
+	// MetricEventProcessingLag is the delay between the timestamp of an event and its processing
+	MetricEventProcessingLag = "event.processing_lag"
+	// MetricEventIngestionLag is the delay between the ingestion of an event and its processing
+	MetricEventIngestionLag = "event.ingestion_lag"
+	// MetricPeriodCalculationFailure counts the subscriptions whose billing period couldn't be calculated for an
+	// event, whichever the period calculation failure policy
+	MetricPeriodCalculationFailure = "event.period_calculation_failure"
+	// MetricEventValueAnomaly counts the event usage whose value is above the maximum event value of
+	// the meter, once per line item the event is matched to
+	MetricEventValueAnomaly = "event.value_anomaly"
+	// MetricEventDuplicate counts the events skipped because their id was processed within the dedup window
+	MetricEventDuplicate = "event.duplicate"
+	// MetricEventClockSkew is the delay between the timestamp of an event and its ingestion, negative
+	// when the event is timestamped in the future
+	MetricEventClockSkew = "event.clock_skew"
+	// MetricEventFutureTimestamp counts the events ingested before their timestamp, which only a
+	// producer clock running ahead explains
+	MetricEventFutureTimestamp = "event.future_timestamp"
+)
+
+// Recorder defines the interface for recording metrics
+type Recorder interface {
+	// RecordDuration records a duration observation of a metric for a tenant
+	RecordDuration(name string, tenantID string, value time.Duration)
+
+	// IncrementCounter adds one to a counter metric for a tenant
+	IncrementCounter(name string, tenantID string)
+}
+
+// DurationStats is the aggregate of the duration observations of a metric for a tenant
+type DurationStats struct {
+	Count int64
+	Sum   time.Duration
+	Max   time.Duration
+	Last  time.Duration
+}
+
+// Avg returns the average of the observations, zero when there are none
+func (s DurationStats) Avg() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+type statsKey struct {
+	name     string
+	tenantID string
+}
+
+// InMemoryRecorder aggregates the observations of each metric per tenant in memory
+type InMemoryRecorder struct {
+	mu       sync.Mutex
+	stats    map[statsKey]DurationStats
+	counters map[statsKey]int64
+}
+
+// defaultRecorder is the recorder shared by the services of the process, see Default
+var defaultRecorder = NewInMemoryRecorder()
+
+// Default returns the recorder shared by the services of the process, whose aggregates are
+// exported by the exporter registered with RegisterHooks
+func Default() *InMemoryRecorder {
+	return defaultRecorder
+}
+
+// NewInMemoryRecorder creates a new in-memory recorder
+func NewInMemoryRecorder() *InMemoryRecorder {
+	return &InMemoryRecorder{
+		stats:    make(map[statsKey]DurationStats),
+		counters: make(map[statsKey]int64),
+	}
+}
+
+// RecordDuration records a duration observation of a metric for a tenant
+func (r *InMemoryRecorder) RecordDuration(name string, tenantID string, value time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := statsKey{name: name, tenantID: tenantID}
+	stats := r.stats[key]
+	stats.Count++
+	stats.Sum += value
+	stats.Last = value
+	if value > stats.Max {
+		stats.Max = value
+	}
+	r.stats[key] = stats
+}
+
+// Get returns the aggregate of a metric for a tenant and whether it was recorded at all
+func (r *InMemoryRecorder) Get(name string, tenantID string) (DurationStats, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.stats[statsKey{name: name, tenantID: tenantID}]
+	return stats, ok
+}
+
+// Snapshot returns the aggregates of a metric keyed by tenant ID
+func (r *InMemoryRecorder) Snapshot(name string) map[string]DurationStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := make(map[string]DurationStats)
+	for key, stats := range r.stats {
+		if key.name == name {
+			snapshot[key.tenantID] = stats
+		}
+	}
+	return snapshot
+}
+
+// IncrementCounter adds one to a counter metric for a tenant
+func (r *InMemoryRecorder) IncrementCounter(name string, tenantID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.counters[statsKey{name: name, tenantID: tenantID}]++
+}
+
+// Count returns the value of a counter metric for a tenant, zero when it was never incremented
+func (r *InMemoryRecorder) Count(name string, tenantID string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.counters[statsKey{name: name, tenantID: tenantID}]
+}
+
+// Drain returns the duration aggregates and counters recorded since the last drain, keyed by
+// metric name and tenant ID, and resets them
+func (r *InMemoryRecorder) Drain() (map[string]map[string]DurationStats, map[string]map[string]int64) {
+	r.mu.Lock()
+	stats, counters := r.stats, r.counters
+	r.stats = make(map[statsKey]DurationStats)
+	r.counters = make(map[statsKey]int64)
+	r.mu.Unlock()
+
+	drainedStats := make(map[string]map[string]DurationStats)
+	for key, value := range stats {
+		if drainedStats[key.name] == nil {
+			drainedStats[key.name] = make(map[string]DurationStats)
+		}
+		drainedStats[key.name][key.tenantID] = value
+	}
+	drainedCounters := make(map[string]map[string]int64)
+	for key, value := range counters {
+		if drainedCounters[key.name] == nil {
+			drainedCounters[key.name] = make(map[string]int64)
+		}
+		drainedCounters[key.name][key.tenantID] = value
+	}
+	return drainedStats, drainedCounters
+}
@@ -0,0 +1,227 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/domain/events"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/samber/lo"
+	"go.uber.org/fx"
+)
+
+const (
+	defaultBufferFlushSize     = 500
+	defaultBufferFlushInterval = 100 * time.Millisecond
+)
+
+// BufferedEventPublisher buffers the published events and publishes them in batches, once
+// enough events are buffered or the flush interval elapses. Publishing is rejected with a too
+// many requests error while the buffer is full so clients back off instead of piling up events.
+// Events failing to publish are buffered again and retried with the next flush, up to
+// MaxRetries times.
+type BufferedEventPublisher struct {
+	next   EventPublisher
+	logger *logger.Logger
+	config config.EventBufferConfig
+
+	mu     sync.Mutex
+	buffer []*bufferedEvent
+	closed bool
+
+	flushCh chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// bufferedEvent is a buffered event with the number of times it failed to publish
+type bufferedEvent struct {
+	event    *events.Event
+	failures int
+}
+
+// NewBufferedEventPublisher creates a publisher buffering the events published to next and
+// starts flushing them in the background until it is closed
+func NewBufferedEventPublisher(next EventPublisher, cfg config.EventBufferConfig, logger *logger.Logger) *BufferedEventPublisher {
+	if cfg.FlushSize <= 0 {
+		cfg.FlushSize = defaultBufferFlushSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultBufferFlushInterval
+	}
+	if cfg.MaxBuffered < cfg.FlushSize {
+		cfg.MaxBuffered = cfg.FlushSize
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+
+	p := &BufferedEventPublisher{
+		next:    next,
+		logger:  logger,
+		config:  cfg,
+		buffer:  make([]*bufferedEvent, 0, cfg.FlushSize),
+		flushCh: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Publish buffers the event to be published with the next batch
+func (p *BufferedEventPublisher) Publish(ctx context.Context, event *events.Event) error {
+	return p.PublishBatch(ctx, []*events.Event{event})
+}
+
+// PublishBatch buffers all of the events, or none of them when they don't fit in the buffer
+func (p *BufferedEventPublisher) PublishBatch(ctx context.Context, batch []*events.Event) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ierr.NewError("event buffer is closed").
+			WithHint("The service is shutting down, please retry").
+			Mark(ierr.ErrSystem)
+	}
+
+	if len(p.buffer)+len(batch) > p.config.MaxBuffered {
+		buffered := len(p.buffer)
+		p.mu.Unlock()
+		return ierr.NewError("event buffer is full").
+			WithHint("Too many events are waiting to be published, please retry after a short backoff").
+			WithReportableDetails(map[string]interface{}{
+				"buffered":     buffered,
+				"max_buffered": p.config.MaxBuffered,
+			}).
+			Mark(ierr.ErrTooManyRequests)
+	}
+
+	for _, event := range batch {
+		p.buffer = append(p.buffer, &bufferedEvent{event: event})
+	}
+	full := len(p.buffer) >= p.config.FlushSize
+	p.mu.Unlock()
+
+	if full {
+		select {
+		case p.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Close stops buffering events and publishes the events still buffered
+func (p *BufferedEventPublisher) Close(ctx context.Context) error {
+	p.once.Do(func() {
+		p.mu.Lock()
+		p.closed = true
+		p.mu.Unlock()
+		close(p.done)
+	})
+
+	select {
+	case <-p.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *BufferedEventPublisher) run() {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.flush()
+		case <-p.flushCh:
+			p.flush()
+		case <-p.done:
+			for p.flush() > 0 {
+				// the failed events are flushed again until they are published or out of retries
+			}
+			return
+		}
+	}
+}
+
+// flush publishes the buffered events in batches of at most the flush size and returns the
+// number of events buffered again to be retried. Only the events failing to publish are retried,
+// the events out of retries are logged and dropped.
+func (p *BufferedEventPublisher) flush() int {
+	p.mu.Lock()
+	buffered := p.buffer
+	p.buffer = make([]*bufferedEvent, 0, p.config.FlushSize)
+	p.mu.Unlock()
+
+	ctx := context.Background()
+	var retried []*bufferedEvent
+	for _, chunk := range lo.Chunk(buffered, p.config.FlushSize) {
+		batch := lo.Map(chunk, func(buffered *bufferedEvent, _ int) *events.Event { return buffered.event })
+		err := publishBatch(ctx, p.next, batch)
+		if err == nil {
+			continue
+		}
+
+		failed := batch
+		var batchErr *events.PublishBatchError
+		if errors.As(err, &batchErr) {
+			failed = batchErr.Failed
+		}
+		failedEvents := lo.SliceToMap(failed, func(event *events.Event) (*events.Event, struct{}) {
+			return event, struct{}{}
+		})
+
+		dropped := 0
+		for _, buffered := range chunk {
+			if _, ok := failedEvents[buffered.event]; !ok {
+				continue
+			}
+			buffered.failures++
+			if buffered.failures > p.config.MaxRetries {
+				dropped++
+				continue
+			}
+			retried = append(retried, buffered)
+		}
+
+		p.logger.Errorw("failed to publish buffered events",
+			"events", len(batch),
+			"failed", len(failed),
+			"dropped", dropped,
+			"first_event_id", failed[0].ID,
+			"error", err,
+		)
+	}
+
+	if len(retried) > 0 {
+		// the retried events are published ahead of the events buffered since
+		p.mu.Lock()
+		p.buffer = append(retried, p.buffer...)
+		p.mu.Unlock()
+	}
+	return len(retried)
+}
+
+// RegisterHooks publishes the events still buffered when the application stops
+func RegisterHooks(lc fx.Lifecycle, publisher IngestEventPublisher) {
+	buffered, ok := publisher.(*BufferedEventPublisher)
+	if !ok {
+		return
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return buffered.Close(ctx)
+		},
+	})
+}
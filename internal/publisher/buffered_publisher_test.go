@@ -0,0 +1,232 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/domain/events"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPublisher struct {
+	mu      sync.Mutex
+	batches [][]*events.Event
+	// failures is the number of times each event fails to publish before it is published
+	failures map[string]int
+}
+
+func (r *recordingPublisher) Publish(ctx context.Context, event *events.Event) error {
+	return r.PublishBatch(ctx, []*events.Event{event})
+}
+
+func (r *recordingPublisher) PublishBatch(ctx context.Context, batch []*events.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	published := make([]*events.Event, 0, len(batch))
+	var failed []*events.Event
+	for _, event := range batch {
+		if r.failures[event.ID] > 0 {
+			r.failures[event.ID]--
+			failed = append(failed, event)
+			continue
+		}
+		published = append(published, event)
+	}
+	if len(published) > 0 {
+		r.batches = append(r.batches, published)
+	}
+	if len(failed) > 0 {
+		return &events.PublishBatchError{Failed: failed, Err: errors.New("topic unavailable")}
+	}
+	return nil
+}
+
+func (r *recordingPublisher) publishedIDs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for _, batch := range r.batches {
+		for _, event := range batch {
+			ids = append(ids, event.ID)
+		}
+	}
+	return ids
+}
+
+func (r *recordingPublisher) batchSizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sizes := make([]int, 0, len(r.batches))
+	for _, batch := range r.batches {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+func (r *recordingPublisher) published() int {
+	total := 0
+	for _, size := range r.batchSizes() {
+		total += size
+	}
+	return total
+}
+
+func testEvents(n int) []*events.Event {
+	batch := make([]*events.Event, 0, n)
+	for i := 0; i < n; i++ {
+		batch = append(batch, &events.Event{ID: fmt.Sprintf("event_%d", i), EventName: "api_call"})
+	}
+	return batch
+}
+
+func TestBufferedEventPublisher_FlushesWhenFull(t *testing.T) {
+	next := &recordingPublisher{}
+	p := NewBufferedEventPublisher(next, config.EventBufferConfig{
+		FlushSize:     3,
+		FlushInterval: time.Hour,
+		MaxBuffered:   10,
+	}, logger.GetLogger())
+	defer p.Close(context.Background())
+
+	for _, event := range testEvents(2) {
+		require.NoError(t, p.Publish(context.Background(), event))
+	}
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, next.batchSizes(), "events below the flush size should stay buffered")
+
+	require.NoError(t, p.Publish(context.Background(), testEvents(1)[0]))
+	require.Eventually(t, func() bool { return next.published() == 3 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []int{3}, next.batchSizes())
+}
+
+func TestBufferedEventPublisher_FlushesOnInterval(t *testing.T) {
+	next := &recordingPublisher{}
+	p := NewBufferedEventPublisher(next, config.EventBufferConfig{
+		FlushSize:     100,
+		FlushInterval: 10 * time.Millisecond,
+		MaxBuffered:   100,
+	}, logger.GetLogger())
+	defer p.Close(context.Background())
+
+	require.NoError(t, p.PublishBatch(context.Background(), testEvents(5)))
+	require.Eventually(t, func() bool { return next.published() == 5 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []int{5}, next.batchSizes())
+}
+
+func TestBufferedEventPublisher_SplitsBatchesByFlushSize(t *testing.T) {
+	next := &recordingPublisher{}
+	p := NewBufferedEventPublisher(next, config.EventBufferConfig{
+		FlushSize:     4,
+		FlushInterval: time.Hour,
+		MaxBuffered:   20,
+	}, logger.GetLogger())
+
+	require.NoError(t, p.PublishBatch(context.Background(), testEvents(10)))
+	require.NoError(t, p.Close(context.Background()))
+	assert.Equal(t, 10, next.published())
+	for _, size := range next.batchSizes() {
+		assert.LessOrEqual(t, size, 4)
+	}
+}
+
+func TestBufferedEventPublisher_Backpressure(t *testing.T) {
+	next := &recordingPublisher{}
+	p := NewBufferedEventPublisher(next, config.EventBufferConfig{
+		FlushSize:     5,
+		FlushInterval: time.Hour,
+		MaxBuffered:   5,
+	}, logger.GetLogger())
+
+	require.NoError(t, p.PublishBatch(context.Background(), testEvents(4)))
+
+	// the batch doesn't fit so none of it is buffered
+	err := p.PublishBatch(context.Background(), testEvents(2))
+	require.Error(t, err)
+	assert.True(t, ierr.IsTooManyRequests(err))
+
+	require.NoError(t, p.Close(context.Background()))
+	assert.Equal(t, 4, next.published())
+}
+
+func TestBufferedEventPublisher_Close(t *testing.T) {
+	next := &recordingPublisher{}
+	p := NewBufferedEventPublisher(next, config.EventBufferConfig{
+		FlushSize:     100,
+		FlushInterval: time.Hour,
+		MaxBuffered:   100,
+	}, logger.GetLogger())
+
+	require.NoError(t, p.PublishBatch(context.Background(), testEvents(3)))
+	require.NoError(t, p.Close(context.Background()))
+	assert.Equal(t, []int{3}, next.batchSizes())
+
+	// closing again is a no-op and publishing after close fails
+	require.NoError(t, p.Close(context.Background()))
+	assert.Error(t, p.Publish(context.Background(), testEvents(1)[0]))
+}
+
+func TestBufferedEventPublisher_RetriesFailedEvents(t *testing.T) {
+	// event_1 fails once, like a topic failing while the topic of the other events is published
+	next := &recordingPublisher{failures: map[string]int{"event_1": 1}}
+	p := NewBufferedEventPublisher(next, config.EventBufferConfig{
+		FlushSize:     10,
+		FlushInterval: time.Hour,
+		MaxBuffered:   10,
+		MaxRetries:    2,
+	}, logger.GetLogger())
+
+	require.NoError(t, p.PublishBatch(context.Background(), testEvents(3)))
+	require.NoError(t, p.Close(context.Background()))
+
+	// the other events are published once and only the failed event is retried
+	assert.Equal(t, []string{"event_0", "event_2", "event_1"}, next.publishedIDs())
+}
+
+func TestBufferedEventPublisher_DropsEventsOutOfRetries(t *testing.T) {
+	next := &recordingPublisher{failures: map[string]int{"event_0": 5}}
+	p := NewBufferedEventPublisher(next, config.EventBufferConfig{
+		FlushSize:     10,
+		FlushInterval: time.Hour,
+		MaxBuffered:   10,
+		MaxRetries:    2,
+	}, logger.GetLogger())
+
+	require.NoError(t, p.PublishBatch(context.Background(), testEvents(2)))
+	require.NoError(t, p.Close(context.Background()))
+
+	assert.Equal(t, []string{"event_1"}, next.publishedIDs())
+	// the event is tried once and retried twice before it is dropped
+	assert.Equal(t, 2, next.failures["event_0"])
+}
+
+func TestNewIngestEventPublisher(t *testing.T) {
+	next := &recordingPublisher{}
+
+	cfg := &config.Configuration{}
+	unbuffered := NewIngestEventPublisher(cfg, logger.GetLogger(), next)
+	require.NoError(t, unbuffered.PublishBatch(context.Background(), testEvents(2)))
+	assert.Equal(t, []int{2}, next.batchSizes(), "events are published right away when the buffer is disabled")
+
+	cfg.Event.Buffer.Enabled = true
+	buffered, ok := NewIngestEventPublisher(cfg, logger.GetLogger(), next).(*BufferedEventPublisher)
+	require.True(t, ok)
+	require.NoError(t, buffered.Close(context.Background()))
+}
+
+func TestNewBufferedEventPublisher_Defaults(t *testing.T) {
+	p := NewBufferedEventPublisher(&recordingPublisher{}, config.EventBufferConfig{MaxBuffered: 10}, logger.GetLogger())
+	defer p.Close(context.Background())
+
+	assert.Equal(t, defaultBufferFlushSize, p.config.FlushSize)
+	assert.Equal(t, defaultBufferFlushInterval, p.config.FlushInterval)
+	assert.Equal(t, defaultBufferFlushSize, p.config.MaxBuffered)
+}
@@ -2,6 +2,7 @@ package publisher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -19,6 +20,58 @@ type EventPublisher interface {
 	Publish(ctx context.Context, event *events.Event) error
 }
 
+// BatchPublisher is implemented by the publishers that publish several events at once. When some
+// of the events fail to publish the error is an events.PublishBatchError holding them.
+type BatchPublisher interface {
+	PublishBatch(ctx context.Context, batch []*events.Event) error
+}
+
+// IngestEventPublisher publishes the events ingested through the API, buffering them when the
+// event buffer is enabled. The other paths publish with the EventPublisher directly.
+type IngestEventPublisher interface {
+	EventPublisher
+	BatchPublisher
+}
+
+// NewIngestEventPublisher creates the publisher of the events ingested through the API
+func NewIngestEventPublisher(cfg *config.Configuration, logger *logger.Logger, publisher EventPublisher) IngestEventPublisher {
+	if cfg.Event.Buffer.Enabled {
+		return NewBufferedEventPublisher(publisher, cfg.Event.Buffer, logger)
+	}
+	return &batchEventPublisher{EventPublisher: publisher}
+}
+
+// batchEventPublisher publishes a batch with the batch publish of the wrapped publisher, or one
+// event at a time when it has none
+type batchEventPublisher struct {
+	EventPublisher
+}
+
+func (p *batchEventPublisher) PublishBatch(ctx context.Context, batch []*events.Event) error {
+	return publishBatch(ctx, p.EventPublisher, batch)
+}
+
+// publishBatch publishes the events with the batch publish of the publisher, or one event at a
+// time when it has none. The events failing to publish are returned in a PublishBatchError.
+func publishBatch(ctx context.Context, publisher EventPublisher, batch []*events.Event) error {
+	if batchPublisher, ok := publisher.(BatchPublisher); ok {
+		return batchPublisher.PublishBatch(ctx, batch)
+	}
+
+	var failed []*events.Event
+	var errs []error
+	for _, event := range batch {
+		if err := publisher.Publish(ctx, event); err != nil {
+			failed = append(failed, event)
+			errs = append(errs, err)
+		}
+	}
+	if len(failed) > 0 {
+		return &events.PublishBatchError{Failed: failed, Err: errors.Join(errs...)}
+	}
+	return nil
+}
+
 type eventPublisher struct {
 	kafkaPublisher  *kafka.EventPublisher
 	dynamoPublisher *dynamodb.EventPublisher
@@ -100,3 +153,28 @@ func (s *eventPublisher) Publish(ctx context.Context, event *events.Event) error
 		return fmt.Errorf("unknown publish destination: %s", s.config.PublishDestination)
 	}
 }
+
+// PublishBatch publishes the events to kafka in a single batch, the other destinations are
+// published to one event at a time
+func (s *eventPublisher) PublishBatch(ctx context.Context, batch []*events.Event) error {
+	if s.config.PublishDestination != types.PublishToKafka {
+		return publishBatch(ctx, eventPublisherFunc(s.Publish), batch)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.logger.With(
+		zap.Int("events", len(batch)),
+		zap.String("destination", string(s.config.PublishDestination)),
+	).Debug("publishing event batch")
+
+	return s.kafkaPublisher.PublishBatch(ctx, batch)
+}
+
+// eventPublisherFunc publishes events one at a time with the function
+type eventPublisherFunc func(ctx context.Context, event *events.Event) error
+
+func (f eventPublisherFunc) Publish(ctx context.Context, event *events.Event) error {
+	return f(ctx, event)
+}
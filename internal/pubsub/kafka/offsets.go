@@ -0,0 +1,182 @@
+package kafka
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/flexprice/flexprice/internal/config"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+)
+
+// OffsetSeeker looks up and commits the offsets of a consumer group
+type OffsetSeeker interface {
+	// Partitions returns the partitions of the topic
+	Partitions(topic string) ([]int32, error)
+	// OffsetForTime returns the offset of the first message at or after the timestamp.
+	// When no such message exists it returns the offset the next message will be written at.
+	OffsetForTime(topic string, partition int32, timestamp time.Time) (int64, error)
+	// CommitOffsets commits the offsets of the consumer group for the topic, keyed by partition
+	CommitOffsets(group, topic string, offsets map[int32]int64) error
+	Close() error
+}
+
+// PartitionOffset is the offset a consumer group resumes from on a partition
+type PartitionOffset struct {
+	Partition int32
+	Offset    int64
+}
+
+// SeekConsumerGroupToTime moves the committed offsets of the consumer group on every partition
+// of the topic to the first message at or after the timestamp, so the group replays messages
+// from that point the next time it starts. The consumer group must have no running members,
+// otherwise they overwrite the committed offsets. With dryRun set the offsets are only resolved.
+func SeekConsumerGroupToTime(seeker OffsetSeeker, group, topic string, timestamp time.Time, dryRun bool) ([]PartitionOffset, error) {
+	if group == "" || topic == "" {
+		return nil, ierr.NewError("consumer group and topic are required").
+			WithHint("Please provide the consumer group and the topic to seek").
+			Mark(ierr.ErrValidation)
+	}
+
+	if timestamp.IsZero() {
+		return nil, ierr.NewError("timestamp is required").
+			WithHint("Please provide the timestamp to seek the consumer group to").
+			Mark(ierr.ErrValidation)
+	}
+
+	partitions, err := seeker.Partitions(topic)
+	if err != nil {
+		return nil, ierr.WithError(err).
+			WithHint("Failed to list the partitions of the topic").
+			WithReportableDetails(map[string]interface{}{
+				"topic": topic,
+			}).
+			Mark(ierr.ErrSystem)
+	}
+
+	if len(partitions) == 0 {
+		return nil, ierr.NewError("topic has no partitions").
+			WithHint("Please check that the topic exists").
+			WithReportableDetails(map[string]interface{}{
+				"topic": topic,
+			}).
+			Mark(ierr.ErrNotFound)
+	}
+
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+
+	result := make([]PartitionOffset, 0, len(partitions))
+	offsets := make(map[int32]int64, len(partitions))
+	for _, partition := range partitions {
+		offset, err := seeker.OffsetForTime(topic, partition, timestamp)
+		if err != nil {
+			return nil, ierr.WithError(err).
+				WithHint("Failed to look up the partition offset for the timestamp").
+				WithReportableDetails(map[string]interface{}{
+					"topic":     topic,
+					"partition": partition,
+					"timestamp": timestamp,
+				}).
+				Mark(ierr.ErrSystem)
+		}
+
+		offsets[partition] = offset
+		result = append(result, PartitionOffset{Partition: partition, Offset: offset})
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if err := seeker.CommitOffsets(group, topic, offsets); err != nil {
+		return nil, ierr.WithError(err).
+			WithHint("Failed to commit the consumer group offsets").
+			WithReportableDetails(map[string]interface{}{
+				"consumer_group": group,
+				"topic":          topic,
+			}).
+			Mark(ierr.ErrSystem)
+	}
+
+	return result, nil
+}
+
+// SaramaOffsetSeeker is an OffsetSeeker backed by a sarama client
+type SaramaOffsetSeeker struct {
+	client sarama.Client
+}
+
+// NewOffsetSeeker creates an OffsetSeeker using the same kafka configuration as the pubsub clients
+func NewOffsetSeeker(cfg *config.Configuration) (*SaramaOffsetSeeker, error) {
+	saramaConfig := GetSaramaConfig(cfg)
+	// Offsets are committed explicitly and commit errors are returned to the caller
+	saramaConfig.Consumer.Offsets.AutoCommit.Enable = false
+	saramaConfig.Consumer.Return.Errors = true
+
+	client, err := sarama.NewClient(cfg.Kafka.Brokers, saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SaramaOffsetSeeker{client: client}, nil
+}
+
+func (s *SaramaOffsetSeeker) Partitions(topic string) ([]int32, error) {
+	return s.client.Partitions(topic)
+}
+
+func (s *SaramaOffsetSeeker) OffsetForTime(topic string, partition int32, timestamp time.Time) (int64, error) {
+	offset, err := s.client.GetOffset(topic, partition, timestamp.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+
+	// No message at or after the timestamp, resume from the end of the partition
+	if offset < 0 {
+		return s.client.GetOffset(topic, partition, sarama.OffsetNewest)
+	}
+
+	return offset, nil
+}
+
+func (s *SaramaOffsetSeeker) CommitOffsets(group, topic string, offsets map[int32]int64) error {
+	offsetManager, err := sarama.NewOffsetManagerFromClient(group, s.client)
+	if err != nil {
+		return err
+	}
+
+	partitionManagers := make([]sarama.PartitionOffsetManager, 0, len(offsets))
+	for partition, offset := range offsets {
+		partitionManager, err := offsetManager.ManagePartition(topic, partition)
+		if err != nil {
+			offsetManager.Close()
+			return err
+		}
+
+		// ResetOffset only moves the offset back and MarkOffset only moves it forward,
+		// together they set it to the offset regardless of the committed one
+		partitionManager.ResetOffset(offset, "")
+		partitionManager.MarkOffset(offset, "")
+		partitionManager.AsyncClose()
+		partitionManagers = append(partitionManagers, partitionManager)
+	}
+
+	offsetManager.Commit()
+	offsetManager.Close()
+
+	var errs sarama.ConsumerErrors
+	for _, partitionManager := range partitionManagers {
+		for err := range partitionManager.Errors() {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (s *SaramaOffsetSeeker) Close() error {
+	return s.client.Close()
+}
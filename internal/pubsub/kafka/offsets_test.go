@@ -0,0 +1,160 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockOffsetSeeker struct {
+	partitions []int32
+	// offsets by partition returned for any timestamp
+	offsets   map[int32]int64
+	commitErr error
+
+	committedGroup   string
+	committedTopic   string
+	committedOffsets map[int32]int64
+	requestedTimes   []time.Time
+}
+
+func (m *mockOffsetSeeker) Partitions(topic string) ([]int32, error) {
+	return m.partitions, nil
+}
+
+func (m *mockOffsetSeeker) OffsetForTime(topic string, partition int32, timestamp time.Time) (int64, error) {
+	m.requestedTimes = append(m.requestedTimes, timestamp)
+	offset, ok := m.offsets[partition]
+	if !ok {
+		return 0, errors.New("unknown partition")
+	}
+	return offset, nil
+}
+
+func (m *mockOffsetSeeker) CommitOffsets(group, topic string, offsets map[int32]int64) error {
+	if m.commitErr != nil {
+		return m.commitErr
+	}
+	m.committedGroup = group
+	m.committedTopic = topic
+	m.committedOffsets = offsets
+	return nil
+}
+
+func (m *mockOffsetSeeker) Close() error {
+	return nil
+}
+
+func TestSeekConsumerGroupToTime(t *testing.T) {
+	timestamp := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		group           string
+		timestamp       time.Time
+		dryRun          bool
+		seeker          *mockOffsetSeeker
+		expected        []PartitionOffset
+		expectCommitted bool
+		expectErr       func(error) bool
+	}{
+		{
+			name:      "commits the offset of every partition",
+			group:     "event-consumer",
+			timestamp: timestamp,
+			seeker: &mockOffsetSeeker{
+				partitions: []int32{2, 0, 1},
+				offsets:    map[int32]int64{0: 120, 1: 80, 2: 0},
+			},
+			expected: []PartitionOffset{
+				{Partition: 0, Offset: 120},
+				{Partition: 1, Offset: 80},
+				{Partition: 2, Offset: 0},
+			},
+			expectCommitted: true,
+		},
+		{
+			name:      "dry run does not commit",
+			group:     "event-consumer",
+			timestamp: timestamp,
+			dryRun:    true,
+			seeker: &mockOffsetSeeker{
+				partitions: []int32{0},
+				offsets:    map[int32]int64{0: 42},
+			},
+			expected: []PartitionOffset{{Partition: 0, Offset: 42}},
+		},
+		{
+			name:      "missing consumer group",
+			timestamp: timestamp,
+			seeker:    &mockOffsetSeeker{partitions: []int32{0}},
+			expectErr: ierr.IsValidation,
+		},
+		{
+			name:      "missing timestamp",
+			group:     "event-consumer",
+			seeker:    &mockOffsetSeeker{partitions: []int32{0}},
+			expectErr: ierr.IsValidation,
+		},
+		{
+			name:      "topic without partitions",
+			group:     "event-consumer",
+			timestamp: timestamp,
+			seeker:    &mockOffsetSeeker{},
+			expectErr: ierr.IsNotFound,
+		},
+		{
+			name:      "offset lookup failure",
+			group:     "event-consumer",
+			timestamp: timestamp,
+			seeker: &mockOffsetSeeker{
+				partitions: []int32{0, 1},
+				offsets:    map[int32]int64{0: 10},
+			},
+			expectErr: ierr.IsSystem,
+		},
+		{
+			name:      "commit failure",
+			group:     "event-consumer",
+			timestamp: timestamp,
+			seeker: &mockOffsetSeeker{
+				partitions: []int32{0},
+				offsets:    map[int32]int64{0: 10},
+				commitErr:  errors.New("coordinator not available"),
+			},
+			expectErr: ierr.IsSystem,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offsets, err := SeekConsumerGroupToTime(tt.seeker, tt.group, "events", tt.timestamp, tt.dryRun)
+			if tt.expectErr != nil {
+				assert.True(t, tt.expectErr(err), "unexpected error: %v", err)
+				assert.Nil(t, tt.seeker.committedOffsets)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, offsets)
+			for _, requested := range tt.seeker.requestedTimes {
+				assert.True(t, requested.Equal(tt.timestamp))
+			}
+
+			if !tt.expectCommitted {
+				assert.Nil(t, tt.seeker.committedOffsets)
+				return
+			}
+
+			assert.Equal(t, tt.group, tt.seeker.committedGroup)
+			assert.Equal(t, "events", tt.seeker.committedTopic)
+			for _, expected := range tt.expected {
+				assert.Equal(t, expected.Offset, tt.seeker.committedOffsets[expected.Partition])
+			}
+		})
+	}
+}
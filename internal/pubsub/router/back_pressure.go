@@ -0,0 +1,112 @@
+package router
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// BackPressure pauses a handler when a downstream store signals it is overloaded. Retrying the
+// failed message right away keeps the store busy, so after a back-pressure error every message
+// of the handler waits for the pause to end. Consecutive back-pressure errors double the pause
+// up to the max pause, a message handled without one resets it.
+type BackPressure struct {
+	isBackPressure func(error) bool
+	pause          time.Duration
+	maxPause       time.Duration
+	onPause        func(err error, pause time.Duration)
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+	consecutive int
+}
+
+// NewBackPressure creates a BackPressure middleware pausing for pause after an error for which
+// isBackPressure is true. A pause of zero or less disables it, a max pause below the pause keeps
+// the pause from growing. onPause, when set, is called with the error and the pause applied.
+func NewBackPressure(
+	isBackPressure func(error) bool,
+	pause time.Duration,
+	maxPause time.Duration,
+	onPause func(err error, pause time.Duration),
+) *BackPressure {
+	if pause <= 0 || isBackPressure == nil {
+		return &BackPressure{}
+	}
+	if maxPause < pause {
+		maxPause = pause
+	}
+
+	return &BackPressure{
+		isBackPressure: isBackPressure,
+		pause:          pause,
+		maxPause:       maxPause,
+		onPause:        onPause,
+	}
+}
+
+// Middleware waits out an ongoing pause before invoking the handler and starts a pause when the
+// handler fails with a back-pressure error. The error is still returned so the message is retried.
+func (b *BackPressure) Middleware(h message.HandlerFunc) message.HandlerFunc {
+	if b.pause <= 0 {
+		return h
+	}
+
+	return func(msg *message.Message) ([]*message.Message, error) {
+		if wait := b.remaining(); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-msg.Context().Done():
+				timer.Stop()
+				return nil, msg.Context().Err()
+			}
+		}
+
+		msgs, err := h(msg)
+		switch {
+		case err != nil && b.isBackPressure(err):
+			b.backOff(err)
+		case err == nil:
+			b.reset()
+		}
+		return msgs, err
+	}
+}
+
+// remaining returns how long the ongoing pause still lasts
+func (b *BackPressure) remaining() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return time.Until(b.pausedUntil)
+}
+
+// backOff starts a pause twice as long as the previous consecutive one
+func (b *BackPressure) backOff(err error) {
+	b.mu.Lock()
+	pause := b.pause
+	for i := 0; i < b.consecutive && pause < b.maxPause; i++ {
+		pause *= 2
+	}
+	pause = min(pause, b.maxPause)
+	b.consecutive++
+
+	// Concurrent failures don't shorten a longer pause already started
+	if until := time.Now().Add(pause); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+	b.mu.Unlock()
+
+	if b.onPause != nil {
+		b.onPause(err, pause)
+	}
+}
+
+func (b *BackPressure) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutive = 0
+}
@@ -0,0 +1,140 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	clickhouse_go "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/flexprice/flexprice/internal/clickhouse"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tooManyPartsError is the error an insert into feature_usage fails with while clickhouse is
+// behind on merges
+func tooManyPartsError() error {
+	return ierr.WithError(&clickhouse_go.Exception{Code: 252, Message: "Too many parts (300) in table"}).
+		WithHint("Failed to execute batch insert for feature usage").
+		Mark(ierr.ErrDatabase)
+}
+
+// pauseRecorder records the pauses started by a BackPressure
+type pauseRecorder struct {
+	mu     sync.Mutex
+	pauses []time.Duration
+}
+
+func (r *pauseRecorder) onPause(_ error, pause time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pauses = append(r.pauses, pause)
+}
+
+func (r *pauseRecorder) get() []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Duration(nil), r.pauses...)
+}
+
+// failingHandler fails with the queued errors in order and succeeds once they are used up
+func failingHandler(errs ...error) message.HandlerFunc {
+	var mu sync.Mutex
+	return func(msg *message.Message) ([]*message.Message, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(errs) == 0 {
+			return nil, nil
+		}
+		err := errs[0]
+		errs = errs[1:]
+		return nil, err
+	}
+}
+
+func newMessage() *message.Message {
+	return message.NewMessage(watermill.NewUUID(), nil)
+}
+
+func TestBackPressureTooManyPartsPausesHandler(t *testing.T) {
+	pause := 50 * time.Millisecond
+	recorder := &pauseRecorder{}
+	handler := NewBackPressure(clickhouse.IsBackPressureError, pause, time.Second, recorder.onPause).
+		Middleware(failingHandler(tooManyPartsError()))
+
+	_, err := handler(newMessage())
+	require.Error(t, err)
+	assert.True(t, ierr.IsDatabase(err), "the insert error is returned so the message is retried")
+	assert.Equal(t, []time.Duration{pause}, recorder.get())
+
+	// The retried message waits for the pause to end before reaching clickhouse again
+	start := time.Now()
+	_, err = handler(newMessage())
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), pause-5*time.Millisecond)
+}
+
+func TestBackPressureConsecutiveErrorsDoublePause(t *testing.T) {
+	pause := 5 * time.Millisecond
+	recorder := &pauseRecorder{}
+	handler := NewBackPressure(clickhouse.IsBackPressureError, pause, 15*time.Millisecond, recorder.onPause).
+		Middleware(failingHandler(tooManyPartsError(), tooManyPartsError(), tooManyPartsError(), nil, tooManyPartsError()))
+
+	for i := 0; i < 5; i++ {
+		_, _ = handler(newMessage())
+	}
+
+	// Doubled and capped at the max pause, the success in between resets it
+	assert.Equal(t, []time.Duration{
+		5 * time.Millisecond,
+		10 * time.Millisecond,
+		15 * time.Millisecond,
+		5 * time.Millisecond,
+	}, recorder.get())
+}
+
+func TestBackPressureIgnoresOtherErrors(t *testing.T) {
+	recorder := &pauseRecorder{}
+	handler := NewBackPressure(clickhouse.IsBackPressureError, time.Second, time.Minute, recorder.onPause).
+		Middleware(failingHandler(errors.New("connection refused")))
+
+	_, err := handler(newMessage())
+	assert.Error(t, err)
+	assert.Empty(t, recorder.get())
+
+	start := time.Now()
+	_, err = handler(newMessage())
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestBackPressureDisabled(t *testing.T) {
+	recorder := &pauseRecorder{}
+	handler := NewBackPressure(clickhouse.IsBackPressureError, 0, time.Minute, recorder.onPause).
+		Middleware(failingHandler(tooManyPartsError()))
+
+	_, err := handler(newMessage())
+	assert.Error(t, err)
+	assert.Empty(t, recorder.get())
+}
+
+func TestBackPressureCancelledMessageStopsWaiting(t *testing.T) {
+	handler := NewBackPressure(clickhouse.IsBackPressureError, time.Minute, time.Minute, nil).
+		Middleware(failingHandler(tooManyPartsError()))
+
+	_, err := handler(newMessage())
+	require.Error(t, err)
+
+	msg := newMessage()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	msg.SetContext(ctx)
+
+	_, err = handler(msg)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,42 @@
+package router
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// MaxInFlight caps the number of messages a handler processes concurrently.
+// Unlike throttling, which limits the rate messages are consumed at, it bounds
+// how many executions (and the connections they hold) can be open at once.
+type MaxInFlight struct {
+	slots chan struct{}
+}
+
+// NewMaxInFlight creates a MaxInFlight middleware allowing up to limit concurrent
+// executions. A limit of zero or less disables the cap.
+func NewMaxInFlight(limit int) *MaxInFlight {
+	if limit <= 0 {
+		return &MaxInFlight{}
+	}
+
+	return &MaxInFlight{
+		slots: make(chan struct{}, limit),
+	}
+}
+
+// Middleware blocks until a slot is available before invoking the handler
+func (m *MaxInFlight) Middleware(h message.HandlerFunc) message.HandlerFunc {
+	if m.slots == nil {
+		return h
+	}
+
+	return func(msg *message.Message) ([]*message.Message, error) {
+		select {
+		case m.slots <- struct{}{}:
+		case <-msg.Context().Done():
+			return nil, msg.Context().Err()
+		}
+		defer func() { <-m.slots }()
+
+		return h(msg)
+	}
+}
@@ -0,0 +1,108 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxInFlight(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		messages    int
+		maxExpected int64
+	}{
+		{
+			name:        "caps concurrent executions at the limit",
+			limit:       3,
+			messages:    20,
+			maxExpected: 3,
+		},
+		{
+			name:        "limit of one serializes executions",
+			limit:       1,
+			messages:    10,
+			maxExpected: 1,
+		},
+		{
+			name:        "zero limit disables the cap",
+			limit:       0,
+			messages:    10,
+			maxExpected: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight, maxSeen int64
+			release := make(chan struct{})
+
+			handler := NewMaxInFlight(tt.limit).Middleware(func(msg *message.Message) ([]*message.Message, error) {
+				current := atomic.AddInt64(&inFlight, 1)
+				defer atomic.AddInt64(&inFlight, -1)
+
+				for {
+					seen := atomic.LoadInt64(&maxSeen)
+					if current <= seen || atomic.CompareAndSwapInt64(&maxSeen, seen, current) {
+						break
+					}
+				}
+
+				<-release
+				return nil, nil
+			})
+
+			var wg sync.WaitGroup
+			for i := 0; i < tt.messages; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := handler(message.NewMessage(watermill.NewUUID(), nil))
+					assert.NoError(t, err)
+				}()
+			}
+
+			// Let the handlers pile up against the limit before releasing them
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt64(&inFlight) == tt.maxExpected
+			}, time.Second, time.Millisecond)
+			time.Sleep(20 * time.Millisecond)
+
+			close(release)
+			wg.Wait()
+
+			assert.Equal(t, tt.maxExpected, atomic.LoadInt64(&maxSeen))
+			assert.Equal(t, int64(0), atomic.LoadInt64(&inFlight))
+		})
+	}
+}
+
+func TestMaxInFlightReleasesOnCancelledMessage(t *testing.T) {
+	block := make(chan struct{})
+	handler := NewMaxInFlight(1).Middleware(func(msg *message.Message) ([]*message.Message, error) {
+		<-block
+		return nil, nil
+	})
+
+	// Occupy the only slot
+	go func() {
+		_, _ = handler(message.NewMessage(watermill.NewUUID(), nil))
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	msg := message.NewMessage(watermill.NewUUID(), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	msg.SetContext(ctx)
+
+	_, err := handler(msg)
+	assert.Error(t, err)
+	close(block)
+}
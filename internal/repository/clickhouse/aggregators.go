@@ -38,11 +38,14 @@ package clickhouse
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/repository/clickhouse/builder"
 	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
 )
 
@@ -64,6 +67,12 @@ func GetAggregator(aggregationType types.AggregationType) events.Aggregator {
 		return &MaxAggregator{}
 	case types.AggregationWeightedSum:
 		return &WeightedSumAggregator{}
+	case types.AggregationCountOncePerPeriod:
+		return &CountOncePerPeriodAggregator{}
+	case types.AggregationUptime:
+		return &UptimeAggregator{}
+	case types.AggregationCounterDelta:
+		return &CounterDeltaAggregator{}
 	}
 	return nil
 }
@@ -78,6 +87,12 @@ func formatClickHouseDateTime(t time.Time) string {
 
 func formatWindowSize(windowSize types.WindowSize) string {
 	switch windowSize {
+	case types.WindowSizeSecond:
+		return "toStartOfInterval(timestamp, INTERVAL 1 SECOND)"
+	case types.WindowSize10Sec:
+		return "toStartOfInterval(timestamp, INTERVAL 10 SECOND)"
+	case types.WindowSize30Sec:
+		return "toStartOfInterval(timestamp, INTERVAL 30 SECOND)"
 	case types.WindowSizeMinute:
 		return "toStartOfMinute(timestamp)"
 	case types.WindowSizeHour:
@@ -173,6 +188,61 @@ func buildFilterConditions(filters map[string][]string) string {
 	return "AND " + strings.Join(conditions, " AND ")
 }
 
+// buildEventConditions filters the events of the usage by the meter filters, the maximum event value,
+// the subscription the events are routed to and the customer creation
+func buildEventConditions(params *events.UsageParams) string {
+	return buildFilterConditions(params.Filters) + eventValueConditions(params) + subscriptionConditions(params) +
+		customerCreationConditions(params)
+}
+
+// customerCreationConditions skips the events timestamped before the customer was created when the
+// pre customer creation policy doesn't bill them in the period
+func customerCreationConditions(params *events.UsageParams) string {
+	if !params.ExcludesPreCustomerCreationEvents() {
+		return ""
+	}
+	return fmt.Sprintf(" AND timestamp >= toDateTime64('%s', 3)", formatClickHouseDateTime(*params.CustomerCreatedAt))
+}
+
+// subscriptionConditions skips the events routed to another subscription than the one billed,
+// the events without a subscription id are billed to all of the customer's subscriptions
+func subscriptionConditions(params *events.UsageParams) string {
+	if params.SubscriptionID == "" || params.SubscriptionIDProperty == "" {
+		return ""
+	}
+
+	path := lo.Map(strings.Split(params.SubscriptionIDProperty, "."), func(key string, _ int) string {
+		return quoteString(key)
+	})
+	return fmt.Sprintf(" AND trimBoth(JSONExtractString(properties, %s)) IN ('', %s)",
+		strings.Join(path, ", "), quoteString(params.SubscriptionID))
+}
+
+// eventValueExpression extracts the numeric field of the events, raised to the minimum event value
+// of the meter when the event has the field, and lowered to the maximum event value when the policy
+// clamps the values above it
+func eventValueExpression(params *events.UsageParams) string {
+	value := fmt.Sprintf("JSONExtractFloat(assumeNotNull(properties), '%s')", params.PropertyName)
+	if params.MinEventValue != nil {
+		value = fmt.Sprintf("if(JSONHas(assumeNotNull(properties), '%s'), greatest(%s, %s), 0)",
+			params.PropertyName, value, params.MinEventValue.String())
+	}
+	if params.MaxEventValue != nil && params.MaxEventValuePolicy == types.MaxEventValuePolicyClamp {
+		value = fmt.Sprintf("least(%s, %s)", value, params.MaxEventValue.String())
+	}
+	return value
+}
+
+// eventValueConditions skips the events whose field is above the maximum event value of the meter
+// when the policy rejects them, as feature usage does
+func eventValueConditions(params *events.UsageParams) string {
+	if params.MaxEventValue == nil || params.MaxEventValuePolicy != types.MaxEventValuePolicyReject {
+		return ""
+	}
+	return fmt.Sprintf(" AND JSONExtractFloat(assumeNotNull(properties), '%s') <= %s",
+		params.PropertyName, params.MaxEventValue.String())
+}
+
 func buildTimeConditions(params *events.UsageParams) string {
 	conditions := parseTimeConditions(params)
 
@@ -186,10 +256,8 @@ func buildTimeConditions(params *events.UsageParams) string {
 func parseTimeConditions(params *events.UsageParams) []string {
 	var conditions []string
 
-	if !params.StartTime.IsZero() {
-		conditions = append(conditions,
-			fmt.Sprintf("timestamp >= toDateTime64('%s', 3)",
-				formatClickHouseDateTime(params.StartTime)))
+	if condition := startTimeCondition(params); condition != "" {
+		conditions = append(conditions, condition)
 	}
 
 	if !params.EndTime.IsZero() {
@@ -201,6 +269,21 @@ func parseTimeConditions(params *events.UsageParams) []string {
 	return conditions
 }
 
+// startTimeCondition bounds the events to the start of the period, the events timestamped before the
+// customer was created are billed in the period they're clamped into, in the window of their timestamp
+func startTimeCondition(params *events.UsageParams) string {
+	if params.StartTime.IsZero() {
+		return ""
+	}
+
+	condition := fmt.Sprintf("timestamp >= toDateTime64('%s', 3)", formatClickHouseDateTime(params.StartTime))
+	if params.IncludesPreCustomerCreationEvents() {
+		condition = fmt.Sprintf("(%s OR timestamp < toDateTime64('%s', 3))",
+			condition, formatClickHouseDateTime(*params.CustomerCreatedAt))
+	}
+	return condition
+}
+
 // SumAggregator implements sum aggregation
 type SumAggregator struct{}
 
@@ -228,7 +311,7 @@ func (a *SumAggregator) GetQuery(ctx context.Context, params *events.UsageParams
 		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
 	}
 
-	filterConditions := buildFilterConditions(params.Filters)
+	filterConditions := buildEventConditions(params)
 	timeConditions := buildTimeConditions(params)
 
 	return fmt.Sprintf(`
@@ -236,11 +319,11 @@ func (a *SumAggregator) GetQuery(ctx context.Context, params *events.UsageParams
             %s sum(value) as total
         FROM (
             SELECT
-                %s anyLast(JSONExtractFloat(assumeNotNull(properties), '%s')) as value
+                %s anyLast(%s) as value
             FROM events
             PREWHERE tenant_id = '%s'
 				AND environment_id = '%s'
-				AND event_name = '%s'
+				AND %s
 				%s
 				%s
                 %s
@@ -251,10 +334,10 @@ func (a *SumAggregator) GetQuery(ctx context.Context, params *events.UsageParams
     `,
 		selectClause,
 		windowClause,
-		params.PropertyName,
+		eventValueExpression(params),
 		types.GetTenantID(ctx),
 		types.GetEnvironmentID(ctx),
-		params.EventName,
+		builder.EventNameCondition(params),
 		externalCustomerFilter,
 		customerFilter,
 		filterConditions,
@@ -291,7 +374,7 @@ func (a *CountAggregator) GetQuery(ctx context.Context, params *events.UsagePara
 		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
 	}
 
-	filterConditions := buildFilterConditions(params.Filters)
+	filterConditions := buildEventConditions(params)
 	timeConditions := buildTimeConditions(params)
 
 	return fmt.Sprintf(`
@@ -300,7 +383,7 @@ func (a *CountAggregator) GetQuery(ctx context.Context, params *events.UsagePara
         FROM events
         PREWHERE tenant_id = '%s'
 			AND environment_id = '%s'
-			AND event_name = '%s'
+			AND %s
 			%s
 			%s
             %s
@@ -311,7 +394,7 @@ func (a *CountAggregator) GetQuery(ctx context.Context, params *events.UsagePara
 		getDeduplicationKey(),
 		types.GetTenantID(ctx),
 		types.GetEnvironmentID(ctx),
-		params.EventName,
+		builder.EventNameCondition(params),
 		externalCustomerFilter,
 		customerFilter,
 		filterConditions,
@@ -350,7 +433,7 @@ func (a *CountUniqueAggregator) GetQuery(ctx context.Context, params *events.Usa
 		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
 	}
 
-	filterConditions := buildFilterConditions(params.Filters)
+	filterConditions := buildEventConditions(params)
 	timeConditions := buildTimeConditions(params)
 
 	return fmt.Sprintf(`
@@ -362,7 +445,7 @@ func (a *CountUniqueAggregator) GetQuery(ctx context.Context, params *events.Usa
             FROM events
             PREWHERE tenant_id = '%s'
 				AND environment_id = '%s'
-				AND event_name = '%s'
+				AND %s
 				%s
 				%s
                 %s
@@ -376,7 +459,7 @@ func (a *CountUniqueAggregator) GetQuery(ctx context.Context, params *events.Usa
 		params.PropertyName,
 		types.GetTenantID(ctx),
 		types.GetEnvironmentID(ctx),
-		params.EventName,
+		builder.EventNameCondition(params),
 		externalCustomerFilter,
 		customerFilter,
 		filterConditions,
@@ -390,6 +473,227 @@ func (a *CountUniqueAggregator) GetType() types.AggregationType {
 	return types.AggregationCountUnique
 }
 
+// CountOncePerPeriodAggregator implements count once per period aggregation, every customer with
+// events in the queried period is counted once, in the window of their first event
+type CountOncePerPeriodAggregator struct{}
+
+func (a *CountOncePerPeriodAggregator) GetQuery(ctx context.Context, params *events.UsageParams) string {
+	windowSize := formatWindowSizeWithBillingAnchor(params.WindowSize, params.BillingAnchor)
+	selectClause := ""
+	groupByClause := ""
+
+	if windowSize != "" {
+		selectClause = fmt.Sprintf("%s AS window_size,", windowSize)
+		groupByClause = "GROUP BY window_size ORDER BY window_size"
+	}
+
+	externalCustomerFilter := ""
+	if params.ExternalCustomerID != "" {
+		externalCustomerFilter = fmt.Sprintf("AND external_customer_id = '%s'", params.ExternalCustomerID)
+	}
+
+	customerFilter := ""
+	if params.CustomerID != "" {
+		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
+	}
+
+	filterConditions := buildEventConditions(params)
+	timeConditions := buildTimeConditions(params)
+
+	// The first event of each customer is renamed to timestamp so the window expression applies to it
+	return fmt.Sprintf(`
+        SELECT 
+            %s count() as total
+        FROM (
+            SELECT first_event_at AS timestamp
+            FROM (
+                SELECT min(timestamp) AS first_event_at
+                FROM events
+                PREWHERE tenant_id = '%s'
+					AND environment_id = '%s'
+					AND %s
+					%s
+					%s
+                    %s
+                    %s
+                GROUP BY external_customer_id
+            )
+        )
+        %s
+    `,
+		selectClause,
+		types.GetTenantID(ctx),
+		types.GetEnvironmentID(ctx),
+		builder.EventNameCondition(params),
+		externalCustomerFilter,
+		customerFilter,
+		filterConditions,
+		timeConditions,
+		groupByClause)
+}
+
+func (a *CountOncePerPeriodAggregator) GetType() types.AggregationType {
+	return types.AggregationCountOncePerPeriod
+}
+
+// UptimeAggregator implements uptime aggregation, every heartbeat interval of a customer with a
+// heartbeat counts as a heartbeat interval of activity. Unwindowed usage is capped at the length
+// of the queried period.
+type UptimeAggregator struct{}
+
+func (a *UptimeAggregator) GetQuery(ctx context.Context, params *events.UsageParams) string {
+	windowSize := formatWindowSizeWithBillingAnchor(params.WindowSize, params.BillingAnchor)
+	selectClause := ""
+	groupByClause := ""
+	totalClause := fmt.Sprintf("toFloat64(count() * %d)", params.HeartbeatIntervalSeconds)
+
+	if windowSize != "" {
+		selectClause = fmt.Sprintf("%s AS window_size,", windowSize)
+		groupByClause = "GROUP BY window_size ORDER BY window_size"
+	} else if !params.StartTime.IsZero() && !params.EndTime.IsZero() {
+		totalClause = fmt.Sprintf("least(%s, %d)", totalClause, int64(params.EndTime.Sub(params.StartTime).Seconds()))
+	}
+
+	externalCustomerFilter := ""
+	if params.ExternalCustomerID != "" {
+		externalCustomerFilter = fmt.Sprintf("AND external_customer_id = '%s'", params.ExternalCustomerID)
+	}
+
+	customerFilter := ""
+	if params.CustomerID != "" {
+		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
+	}
+
+	filterConditions := buildEventConditions(params)
+	timeConditions := buildTimeConditions(params)
+
+	// The first heartbeat of each interval is renamed to timestamp so the window expression applies to it
+	return fmt.Sprintf(`
+        SELECT 
+            %s %s as total
+        FROM (
+            SELECT first_heartbeat_at AS timestamp
+            FROM (
+                SELECT min(timestamp) AS first_heartbeat_at
+                FROM events
+                PREWHERE tenant_id = '%s'
+					AND environment_id = '%s'
+					AND %s
+					%s
+					%s
+                    %s
+                    %s
+                GROUP BY external_customer_id, intDiv(toUnixTimestamp(timestamp), %d)
+            )
+        )
+        %s
+    `,
+		selectClause,
+		totalClause,
+		types.GetTenantID(ctx),
+		types.GetEnvironmentID(ctx),
+		builder.EventNameCondition(params),
+		externalCustomerFilter,
+		customerFilter,
+		filterConditions,
+		timeConditions,
+		params.HeartbeatIntervalSeconds,
+		groupByClause)
+}
+
+func (a *UptimeAggregator) GetType() types.AggregationType {
+	return types.AggregationUptime
+}
+
+// CounterDeltaAggregator implements counter delta aggregation, every reading of a cumulative counter
+// counts its increase over the customer's previous reading, a reading below the previous one is a
+// counter reset and counts in full. The first reading of a customer counts nothing, readings before
+// the start time are read so the first reading of the period has its previous reading.
+type CounterDeltaAggregator struct{}
+
+func (a *CounterDeltaAggregator) GetQuery(ctx context.Context, params *events.UsageParams) string {
+	windowSize := formatWindowSizeWithBillingAnchor(params.WindowSize, params.BillingAnchor)
+	selectClause := ""
+	groupByClause := ""
+
+	if windowSize != "" {
+		selectClause = fmt.Sprintf("%s AS window_size,", windowSize)
+		groupByClause = "GROUP BY window_size ORDER BY window_size"
+	}
+
+	externalCustomerFilter := ""
+	if params.ExternalCustomerID != "" {
+		externalCustomerFilter = fmt.Sprintf("AND external_customer_id = '%s'", params.ExternalCustomerID)
+	}
+
+	customerFilter := ""
+	if params.CustomerID != "" {
+		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
+	}
+
+	filterConditions := buildEventConditions(params)
+
+	endCondition := ""
+	if !params.EndTime.IsZero() {
+		endCondition = fmt.Sprintf("AND timestamp < toDateTime64('%s', 3)", formatClickHouseDateTime(params.EndTime))
+	}
+
+	startCondition := ""
+	if condition := startTimeCondition(params); condition != "" {
+		startCondition = "WHERE " + condition
+	}
+
+	return fmt.Sprintf(`
+        SELECT 
+            %s sum(delta) as total
+        FROM (
+            SELECT
+                timestamp,
+                if(isNull(previous_reading), 0, if(reading < previous_reading, reading, reading - previous_reading)) AS delta
+            FROM (
+                SELECT
+                    timestamp,
+                    reading,
+                    lagInFrame(toNullable(reading)) OVER (
+                        PARTITION BY external_customer_id ORDER BY timestamp, id
+                        ROWS BETWEEN 1 PRECEDING AND CURRENT ROW
+                    ) AS previous_reading
+                FROM (
+                    SELECT
+                        id, external_customer_id, timestamp,
+                        anyLast(JSONExtractFloat(assumeNotNull(properties), '%s')) AS reading
+                    FROM events
+                    PREWHERE tenant_id = '%s'
+						AND environment_id = '%s'
+						AND %s
+						%s
+						%s
+                        %s
+                        %s
+                    GROUP BY id, external_customer_id, timestamp
+                )
+            )
+            %s
+        )
+        %s
+    `,
+		selectClause,
+		params.PropertyName,
+		types.GetTenantID(ctx),
+		types.GetEnvironmentID(ctx),
+		builder.EventNameCondition(params),
+		externalCustomerFilter,
+		customerFilter,
+		filterConditions,
+		endCondition,
+		startCondition,
+		groupByClause)
+}
+
+func (a *CounterDeltaAggregator) GetType() types.AggregationType {
+	return types.AggregationCounterDelta
+}
+
 // AvgAggregator implements avg aggregation
 type AvgAggregator struct{}
 
@@ -417,7 +721,7 @@ func (a *AvgAggregator) GetQuery(ctx context.Context, params *events.UsageParams
 		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
 	}
 
-	filterConditions := buildFilterConditions(params.Filters)
+	filterConditions := buildEventConditions(params)
 	timeConditions := buildTimeConditions(params)
 
 	return fmt.Sprintf(`
@@ -425,11 +729,11 @@ func (a *AvgAggregator) GetQuery(ctx context.Context, params *events.UsageParams
             %s avg(value) as total
         FROM (
             SELECT
-                %s anyLast(JSONExtractFloat(assumeNotNull(properties), '%s')) as value
+                %s anyLast(%s) as value
             FROM events
             PREWHERE tenant_id = '%s'
 				AND environment_id = '%s'
-				AND event_name = '%s' 
+				AND %s
 				%s
 				%s
 				%s
@@ -440,10 +744,10 @@ func (a *AvgAggregator) GetQuery(ctx context.Context, params *events.UsageParams
     `,
 		selectClause,
 		windowClause,
-		params.PropertyName,
+		eventValueExpression(params),
 		types.GetTenantID(ctx),
 		types.GetEnvironmentID(ctx),
-		params.EventName,
+		builder.EventNameCondition(params),
 		externalCustomerFilter,
 		customerFilter,
 		filterConditions,
@@ -480,16 +784,16 @@ func (a *LatestAggregator) GetQuery(ctx context.Context, params *events.UsagePar
 		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
 	}
 
-	filterConditions := buildFilterConditions(params.Filters)
+	filterConditions := buildEventConditions(params)
 	timeConditions := buildTimeConditions(params)
 
 	return fmt.Sprintf(`
         SELECT 
-            %s argMax(JSONExtractFloat(assumeNotNull(properties), '%s'), timestamp) as total
+            %s argMax(%s, timestamp) as total
         FROM 
 			events	PREWHERE tenant_id = '%s'
                 AND environment_id = '%s'
-                AND event_name = '%s'
+                AND %s
                 %s
                 %s
                 %s
@@ -497,10 +801,10 @@ func (a *LatestAggregator) GetQuery(ctx context.Context, params *events.UsagePar
         %s
     `,
 		windowClause,
-		params.PropertyName,
+		eventValueExpression(params),
 		types.GetTenantID(ctx),
 		types.GetEnvironmentID(ctx),
-		params.EventName,
+		builder.EventNameCondition(params),
 		externalCustomerFilter,
 		customerFilter,
 		filterConditions,
@@ -539,7 +843,7 @@ func (a *SumWithMultiAggregator) GetQuery(ctx context.Context, params *events.Us
 		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
 	}
 
-	filterConditions := buildFilterConditions(params.Filters)
+	filterConditions := buildEventConditions(params)
 	timeConditions := buildTimeConditions(params)
 
 	multiplier := decimal.NewFromInt(1)
@@ -547,16 +851,53 @@ func (a *SumWithMultiAggregator) GetQuery(ctx context.Context, params *events.Us
 		multiplier = *params.Multiplier
 	}
 
+	// With a rate table each event is scaled by the rate of its rate property value
+	if params.RateProperty != "" && len(params.RateTable) > 0 {
+		return fmt.Sprintf(`
+        SELECT 
+            %s sum(value * multiplier) as total
+        FROM (
+            SELECT
+                %s anyLast(%s) as value,
+                anyLast(%s) as multiplier
+            FROM events
+            PREWHERE tenant_id = '%s'
+				AND environment_id = '%s'
+				AND %s
+				%s
+				%s
+                %s
+                %s
+            GROUP BY %s %s
+        )
+        %s
+    `,
+			selectClause,
+			windowClause,
+			eventValueExpression(params),
+			rateTableExpression(params.RateProperty, params.RateTable, multiplier),
+			types.GetTenantID(ctx),
+			types.GetEnvironmentID(ctx),
+			builder.EventNameCondition(params),
+			externalCustomerFilter,
+			customerFilter,
+			filterConditions,
+			timeConditions,
+			getDeduplicationKey(),
+			windowGroupBy,
+			groupByClause)
+	}
+
 	return fmt.Sprintf(`
         SELECT 
             %s (sum(value) * %f) as total
         FROM (
             SELECT
-                %s anyLast(JSONExtractFloat(assumeNotNull(properties), '%s')) as value
+                %s anyLast(%s) as value
             FROM events
             PREWHERE tenant_id = '%s'
 				AND environment_id = '%s'
-				AND event_name = '%s'
+				AND %s
 				%s
 				%s
                 %s
@@ -568,10 +909,10 @@ func (a *SumWithMultiAggregator) GetQuery(ctx context.Context, params *events.Us
 		selectClause,
 		multiplier.InexactFloat64(),
 		windowClause,
-		params.PropertyName,
+		eventValueExpression(params),
 		types.GetTenantID(ctx),
 		types.GetEnvironmentID(ctx),
-		params.EventName,
+		builder.EventNameCondition(params),
 		externalCustomerFilter,
 		customerFilter,
 		filterConditions,
@@ -581,6 +922,34 @@ func (a *SumWithMultiAggregator) GetQuery(ctx context.Context, params *events.Us
 		groupByClause)
 }
 
+// rateTableExpression looks up the multiplier of an event by the value of its rate property, the
+// values are matched as strings the way feature usage formats them
+func rateTableExpression(rateProperty string, rateTable map[string]decimal.Decimal, fallback decimal.Decimal) string {
+	values := lo.Keys(rateTable)
+	sort.Strings(values)
+
+	keys := make([]string, len(values))
+	rates := make([]string, len(values))
+	for i, value := range values {
+		keys[i] = quoteString(value)
+		rates[i] = fmt.Sprintf("toFloat64(%s)", rateTable[value].String())
+	}
+
+	property := quoteString(rateProperty)
+	return fmt.Sprintf(
+		"transform(if(JSONType(properties, %s) = 'String', JSONExtractString(properties, %s), JSONExtractRaw(properties, %s)), [%s], [%s], toFloat64(%s))",
+		property, property, property,
+		strings.Join(keys, ", "),
+		strings.Join(rates, ", "),
+		fallback.String(),
+	)
+}
+
+// quoteString quotes a value as a ClickHouse string literal
+func quoteString(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
 func (a *SumWithMultiAggregator) GetType() types.AggregationType {
 	return types.AggregationSumWithMultiplier
 }
@@ -621,7 +990,7 @@ func (a *MaxAggregator) getNonWindowedQuery(ctx context.Context, params *events.
 		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
 	}
 
-	filterConditions := buildFilterConditions(params.Filters)
+	filterConditions := buildEventConditions(params)
 	timeConditions := buildTimeConditions(params)
 
 	return fmt.Sprintf(`
@@ -629,11 +998,11 @@ func (a *MaxAggregator) getNonWindowedQuery(ctx context.Context, params *events.
 			%s max(value) as total
 		FROM (
 			SELECT
-				%s anyLast(JSONExtractFloat(assumeNotNull(properties), '%s')) as value
+				%s anyLast(%s) as value
 			FROM events
 			PREWHERE tenant_id = '%s'
 				AND environment_id = '%s'
-				AND event_name = '%s'
+				AND %s
 				%s
 				%s
 				%s
@@ -644,10 +1013,10 @@ func (a *MaxAggregator) getNonWindowedQuery(ctx context.Context, params *events.
 	`,
 		selectClause,
 		windowClause,
-		params.PropertyName,
+		eventValueExpression(params),
 		types.GetTenantID(ctx),
 		types.GetEnvironmentID(ctx),
-		params.EventName,
+		builder.EventNameCondition(params),
 		externalCustomerFilter,
 		customerFilter,
 		filterConditions,
@@ -670,7 +1039,7 @@ func (a *MaxAggregator) getWindowedQuery(ctx context.Context, params *events.Usa
 		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
 	}
 
-	filterConditions := buildFilterConditions(params.Filters)
+	filterConditions := buildEventConditions(params)
 	timeConditions := buildTimeConditions(params)
 
 	// First get max values per bucket, then get the max across all buckets
@@ -678,11 +1047,11 @@ func (a *MaxAggregator) getWindowedQuery(ctx context.Context, params *events.Usa
 		WITH bucket_maxes AS (
 			SELECT
 				%s as bucket_start,
-				max(JSONExtractFloat(assumeNotNull(properties), '%s')) as bucket_max
+				max(%s) as bucket_max
 			FROM events
 			PREWHERE tenant_id = '%s'
 				AND environment_id = '%s'
-				AND event_name = '%s'
+				AND %s
 				%s
 				%s
 				%s
@@ -698,10 +1067,10 @@ func (a *MaxAggregator) getWindowedQuery(ctx context.Context, params *events.Usa
 		ORDER BY bucket_start
 	`,
 		bucketWindow,
-		params.PropertyName,
+		eventValueExpression(params),
 		types.GetTenantID(ctx),
 		types.GetEnvironmentID(ctx),
-		params.EventName,
+		builder.EventNameCondition(params),
 		externalCustomerFilter,
 		customerFilter,
 		filterConditions,
@@ -737,7 +1106,7 @@ func (a *WeightedSumAggregator) GetQuery(ctx context.Context, params *events.Usa
 		customerFilter = fmt.Sprintf("AND customer_id = '%s'", params.CustomerID)
 	}
 
-	filterConditions := buildFilterConditions(params.Filters)
+	filterConditions := buildEventConditions(params)
 	timeConditions := buildTimeConditions(params)
 
 	return fmt.Sprintf(`
@@ -747,7 +1116,7 @@ func (a *WeightedSumAggregator) GetQuery(ctx context.Context, params *events.Usa
             dateDiff('second', period_start, period_end) AS total_seconds
         SELECT 
             %s sum(
-                (JSONExtractFloat(assumeNotNull(properties), '%s') / nullIf(total_seconds, 0)) *
+                (%s / nullIf(total_seconds, 0)) *
                 dateDiff('second', timestamp, period_end)
             ) AS total
         FROM (
@@ -757,7 +1126,7 @@ func (a *WeightedSumAggregator) GetQuery(ctx context.Context, params *events.Usa
             FROM events
             PREWHERE tenant_id = '%s'
 				AND environment_id = '%s'
-				AND event_name = '%s'
+				AND %s
 				%s
 				%s
                 %s
@@ -768,11 +1137,11 @@ func (a *WeightedSumAggregator) GetQuery(ctx context.Context, params *events.Usa
 		formatClickHouseDateTime(params.StartTime),
 		formatClickHouseDateTime(params.EndTime),
 		selectClause,
-		params.PropertyName,
+		eventValueExpression(params),
 		windowClause,
 		types.GetTenantID(ctx),
 		types.GetEnvironmentID(ctx),
-		params.EventName,
+		builder.EventNameCondition(params),
 		externalCustomerFilter,
 		customerFilter,
 		filterConditions,
@@ -0,0 +1,226 @@
+package clickhouse
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// aggregatorQuery returns the query of the aggregation type's aggregator with its whitespace collapsed
+func aggregatorQuery(t *testing.T, aggregationType types.AggregationType, params *events.UsageParams) string {
+	ctx := context.WithValue(context.Background(), types.CtxTenantID, "tenant_1")
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, "env_1")
+
+	aggregator := GetAggregator(aggregationType)
+	require.NotNil(t, aggregator, "no aggregator for %s", aggregationType)
+	assert.Equal(t, aggregationType, aggregator.GetType())
+	return strings.Join(strings.Fields(aggregator.GetQuery(ctx, params)), " ")
+}
+
+func aggregatorParams(aggregationType types.AggregationType, windowSize types.WindowSize) *events.UsageParams {
+	return &events.UsageParams{
+		ExternalCustomerID: "cust_1",
+		EventName:          "api_call",
+		AggregationType:    aggregationType,
+		WindowSize:         windowSize,
+		StartTime:          time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:            time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestCountOncePerPeriodAggregator(t *testing.T) {
+	t.Run("counts each customer once", func(t *testing.T) {
+		query := aggregatorQuery(t, types.AggregationCountOncePerPeriod, aggregatorParams(types.AggregationCountOncePerPeriod, ""))
+		assert.Contains(t, query, "SELECT count() as total")
+		assert.Contains(t, query, "SELECT min(timestamp) AS first_event_at FROM events")
+		assert.Contains(t, query, "GROUP BY external_customer_id")
+		assert.Contains(t, query, "AND external_customer_id = 'cust_1'")
+		assert.Contains(t, query, "timestamp >= toDateTime64('2026-03-01 00:00:00.000', 3) AND timestamp < toDateTime64('2026-04-01 00:00:00.000', 3)")
+		assert.NotContains(t, query, "window_size")
+	})
+
+	t.Run("windows by the first event", func(t *testing.T) {
+		query := aggregatorQuery(t, types.AggregationCountOncePerPeriod, aggregatorParams(types.AggregationCountOncePerPeriod, types.WindowSizeDay))
+		assert.Contains(t, query, "SELECT toStartOfDay(timestamp) AS window_size, count() as total FROM ( SELECT first_event_at AS timestamp")
+		assert.True(t, strings.HasSuffix(query, "GROUP BY window_size ORDER BY window_size"), query)
+	})
+}
+
+func TestUptimeAggregator(t *testing.T) {
+	params := aggregatorParams(types.AggregationUptime, "")
+	params.HeartbeatIntervalSeconds = 60
+
+	t.Run("counts the intervals with a heartbeat capped at the period", func(t *testing.T) {
+		query := aggregatorQuery(t, types.AggregationUptime, params)
+		assert.Contains(t, query, "SELECT least(toFloat64(count() * 60), 2678400) as total")
+		assert.Contains(t, query, "GROUP BY external_customer_id, intDiv(toUnixTimestamp(timestamp), 60)")
+		assert.NotContains(t, query, "window_size")
+	})
+
+	t.Run("windows by the first heartbeat of the interval", func(t *testing.T) {
+		windowed := *params
+		windowed.WindowSize = types.WindowSizeDay
+		query := aggregatorQuery(t, types.AggregationUptime, &windowed)
+		assert.Contains(t, query, "SELECT toStartOfDay(timestamp) AS window_size, toFloat64(count() * 60) as total FROM ( SELECT first_heartbeat_at AS timestamp")
+		assert.True(t, strings.HasSuffix(query, "GROUP BY window_size ORDER BY window_size"), query)
+	})
+}
+
+func TestCounterDeltaAggregator(t *testing.T) {
+	params := aggregatorParams(types.AggregationCounterDelta, "")
+	params.PropertyName = "bytes"
+
+	query := aggregatorQuery(t, types.AggregationCounterDelta, params)
+	assert.Contains(t, query, "SELECT sum(delta) as total")
+	assert.Contains(t, query, "if(isNull(previous_reading), 0, if(reading < previous_reading, reading, reading - previous_reading)) AS delta")
+	assert.Contains(t, query, "PARTITION BY external_customer_id ORDER BY timestamp, id")
+	assert.Contains(t, query, "anyLast(JSONExtractFloat(assumeNotNull(properties), 'bytes')) AS reading")
+	// The readings before the start time are read for their deltas but not counted
+	assert.Contains(t, query, "AND timestamp < toDateTime64('2026-04-01 00:00:00.000', 3) GROUP BY id, external_customer_id, timestamp")
+	assert.Contains(t, query, ") WHERE timestamp >= toDateTime64('2026-03-01 00:00:00.000', 3) )")
+	assert.NotContains(t, query, "timestamp >= toDateTime64('2026-03-01 00:00:00.000', 3) AND")
+}
+
+func TestMaxEventValue(t *testing.T) {
+	maxEventValue := decimal.NewFromInt(1000)
+	params := aggregatorParams(types.AggregationSum, "")
+	params.PropertyName = "tokens"
+	params.MaxEventValue = &maxEventValue
+
+	t.Run("rejects the events above the max", func(t *testing.T) {
+		rejected := *params
+		rejected.MaxEventValuePolicy = types.MaxEventValuePolicyReject
+		query := aggregatorQuery(t, types.AggregationSum, &rejected)
+		assert.Contains(t, query, "AND JSONExtractFloat(assumeNotNull(properties), 'tokens') <= 1000")
+		assert.Contains(t, query, "anyLast(JSONExtractFloat(assumeNotNull(properties), 'tokens')) as value")
+	})
+
+	t.Run("clamps the events above the max", func(t *testing.T) {
+		clamped := *params
+		clamped.MaxEventValuePolicy = types.MaxEventValuePolicyClamp
+		query := aggregatorQuery(t, types.AggregationSum, &clamped)
+		assert.Contains(t, query, "anyLast(least(JSONExtractFloat(assumeNotNull(properties), 'tokens'), 1000)) as value")
+		assert.NotContains(t, query, "<= 1000")
+	})
+
+	t.Run("applies to every aggregation of a numeric field", func(t *testing.T) {
+		for _, aggregationType := range []types.AggregationType{
+			types.AggregationAvg,
+			types.AggregationLatest,
+			types.AggregationSumWithMultiplier,
+			types.AggregationMax,
+			types.AggregationWeightedSum,
+		} {
+			clamped := *params
+			clamped.AggregationType = aggregationType
+			clamped.MaxEventValuePolicy = types.MaxEventValuePolicyClamp
+			query := aggregatorQuery(t, aggregationType, &clamped)
+			assert.Contains(t, query, "least(JSONExtractFloat(assumeNotNull(properties), 'tokens'), 1000)", aggregationType)
+		}
+	})
+}
+
+func TestSumWithMultiplierRateTable(t *testing.T) {
+	multiplier := decimal.NewFromFloat(0.5)
+	params := aggregatorParams(types.AggregationSumWithMultiplier, "")
+	params.PropertyName = "tokens"
+	params.Multiplier = &multiplier
+
+	t.Run("scales by the static multiplier without a rate table", func(t *testing.T) {
+		query := aggregatorQuery(t, types.AggregationSumWithMultiplier, params)
+		assert.Contains(t, query, "SELECT (sum(value) * 0.500000) as total")
+		assert.NotContains(t, query, "transform")
+	})
+
+	t.Run("scales each event by the rate of its property value", func(t *testing.T) {
+		rated := *params
+		rated.RateProperty = "model"
+		rated.RateTable = map[string]decimal.Decimal{
+			"gpt-4":   decimal.NewFromInt(30),
+			"o'brien": decimal.NewFromFloat(1.5),
+		}
+		query := aggregatorQuery(t, types.AggregationSumWithMultiplier, &rated)
+		assert.Contains(t, query, "SELECT sum(value * multiplier) as total")
+		assert.Contains(t, query, "anyLast(transform(if(JSONType(properties, 'model') = 'String', JSONExtractString(properties, 'model'), JSONExtractRaw(properties, 'model')), ['gpt-4', 'o\\'brien'], [toFloat64(30), toFloat64(1.5)], toFloat64(0.5))) as multiplier")
+	})
+}
+
+func TestSubscriptionRouting(t *testing.T) {
+	params := aggregatorParams(types.AggregationCount, "")
+
+	t.Run("bills all the events without routing", func(t *testing.T) {
+		query := aggregatorQuery(t, types.AggregationCount, params)
+		assert.NotContains(t, query, "trimBoth")
+	})
+
+	t.Run("skips the events routed to another subscription", func(t *testing.T) {
+		routed := *params
+		routed.SubscriptionID = "subs_1"
+		routed.SubscriptionIDProperty = "billing.subscription_id"
+		query := aggregatorQuery(t, types.AggregationCount, &routed)
+		assert.Contains(t, query, "AND trimBoth(JSONExtractString(properties, 'billing', 'subscription_id')) IN ('', 'subs_1')")
+	})
+}
+
+func TestMinEventValue(t *testing.T) {
+	minEventValue := decimal.NewFromInt(60)
+	maxEventValue := decimal.NewFromInt(3600)
+	params := aggregatorParams(types.AggregationSum, "")
+	params.PropertyName = "duration"
+	params.MinEventValue = &minEventValue
+
+	t.Run("raises the values of the events with the field", func(t *testing.T) {
+		query := aggregatorQuery(t, types.AggregationSum, params)
+		assert.Contains(t, query, "anyLast(if(JSONHas(assumeNotNull(properties), 'duration'), greatest(JSONExtractFloat(assumeNotNull(properties), 'duration'), 60), 0)) as value")
+	})
+
+	t.Run("raises before clamping to the max", func(t *testing.T) {
+		bounded := *params
+		bounded.MaxEventValue = &maxEventValue
+		bounded.MaxEventValuePolicy = types.MaxEventValuePolicyClamp
+		query := aggregatorQuery(t, types.AggregationSum, &bounded)
+		assert.Contains(t, query, "anyLast(least(if(JSONHas(assumeNotNull(properties), 'duration'), greatest(JSONExtractFloat(assumeNotNull(properties), 'duration'), 60), 0), 3600)) as value")
+	})
+}
+
+func TestPreCustomerCreationPolicy(t *testing.T) {
+	createdAt := time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)
+	params := aggregatorParams(types.AggregationSum, "")
+	params.CustomerCreatedAt = &createdAt
+
+	t.Run("skip doesn't bill the events before the customer creation", func(t *testing.T) {
+		skipped := *params
+		skipped.PreCustomerCreationPolicy = types.PreCustomerCreationEventPolicySkip
+		query := aggregatorQuery(t, types.AggregationSum, &skipped)
+		assert.Contains(t, query, "AND timestamp >= toDateTime64('2026-03-10 00:00:00.000', 3)")
+	})
+
+	t.Run("clamp bills the events before the customer creation in the period they're clamped into", func(t *testing.T) {
+		clamped := *params
+		clamped.PreCustomerCreationPolicy = types.PreCustomerCreationEventPolicyClamp
+		clamped.PreCustomerCreationClampedAt = &createdAt
+		query := aggregatorQuery(t, types.AggregationSum, &clamped)
+		assert.Contains(t, query, "AND (timestamp >= toDateTime64('2026-03-01 00:00:00.000', 3) OR timestamp < toDateTime64('2026-03-10 00:00:00.000', 3)) AND timestamp < toDateTime64('2026-04-01 00:00:00.000', 3)")
+		assert.NotContains(t, query, "AND timestamp >= toDateTime64('2026-03-10 00:00:00.000', 3)")
+
+		counterQuery := aggregatorQuery(t, types.AggregationCounterDelta, &clamped)
+		assert.Contains(t, counterQuery, "WHERE (timestamp >= toDateTime64('2026-03-01 00:00:00.000', 3) OR timestamp < toDateTime64('2026-03-10 00:00:00.000', 3))")
+	})
+
+	t.Run("clamp doesn't bill the events before the customer creation in the other periods", func(t *testing.T) {
+		clamped := *params
+		clamped.PreCustomerCreationPolicy = types.PreCustomerCreationEventPolicyClamp
+		clampedAt := time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC)
+		clamped.PreCustomerCreationClampedAt = &clampedAt
+		query := aggregatorQuery(t, types.AggregationSum, &clamped)
+		assert.Contains(t, query, "AND timestamp >= toDateTime64('2026-03-10 00:00:00.000', 3)")
+		assert.NotContains(t, query, " OR timestamp < ")
+	})
+}
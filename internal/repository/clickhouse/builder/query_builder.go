@@ -33,7 +33,7 @@ func (qb *QueryBuilder) getDeduplicationKey() string {
 
 func (qb *QueryBuilder) WithBaseFilters(ctx context.Context, params *events.UsageParams) *QueryBuilder {
 	conditions := []string{
-		fmt.Sprintf("event_name = '%s'", params.EventName),
+		EventNameCondition(params),
 	}
 
 	tenantID := types.GetTenantID(ctx)
@@ -225,6 +225,19 @@ func (qb *QueryBuilder) Build() (string, map[string]interface{}) {
 	return query, qb.args
 }
 
+// EventNameCondition returns the condition matching the events of the usage params by name,
+// exactly or by prefix as configured on the meter, and optionally ignoring case
+func EventNameCondition(params *events.UsageParams) string {
+	column, eventName := "event_name", params.EventName
+	if params.CaseInsensitiveEventName {
+		column, eventName = "lower(event_name)", strings.ToLower(eventName)
+	}
+	if params.EventNameMatch == types.MeterEventNameMatchPrefix {
+		return fmt.Sprintf("startsWith(%s, '%s')", column, eventName)
+	}
+	return fmt.Sprintf("%s = '%s'", column, eventName)
+}
+
 func parseTimeConditions(params *events.UsageParams) []string {
 	var conditions []string
 
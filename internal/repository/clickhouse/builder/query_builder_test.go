@@ -42,6 +42,26 @@ func TestQueryBuilder_WithBaseFilters(t *testing.T) {
 			},
 			wantSQL: "WITH base_events AS (SELECT * FROM (SELECT DISTINCT ON (tenant_id, environment_id, timestamp, id) * FROM events WHERE event_name = 'api_calls' AND tenant_id = '00000000-0000-0000-0000-000000000000' AND timestamp >= toDateTime64('2024-01-01 00:00:00.000', 3) AND timestamp < toDateTime64('2024-01-02 00:00:00.000', 3) ORDER BY tenant_id, environment_id, timestamp, id DESC))",
 		},
+		{
+			name: "base filters matching event name by prefix",
+			params: &events.UsageParams{
+				EventName:      "api.users.",
+				EventNameMatch: types.MeterEventNameMatchPrefix,
+				StartTime:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				EndTime:        time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+			wantSQL: "WITH base_events AS (SELECT * FROM (SELECT DISTINCT ON (tenant_id, environment_id, timestamp, id) * FROM events WHERE startsWith(event_name, 'api.users.') AND tenant_id = '00000000-0000-0000-0000-000000000000' AND timestamp >= toDateTime64('2024-01-01 00:00:00.000', 3) AND timestamp < toDateTime64('2024-01-02 00:00:00.000', 3) ORDER BY tenant_id, environment_id, timestamp, id DESC))",
+		},
+		{
+			name: "base filters matching event name regardless of case",
+			params: &events.UsageParams{
+				EventName:                "API_Call",
+				CaseInsensitiveEventName: true,
+				StartTime:                time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				EndTime:                  time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+			wantSQL: "WITH base_events AS (SELECT * FROM (SELECT DISTINCT ON (tenant_id, environment_id, timestamp, id) * FROM events WHERE lower(event_name) = 'api_call' AND tenant_id = '00000000-0000-0000-0000-000000000000' AND timestamp >= toDateTime64('2024-01-01 00:00:00.000', 3) AND timestamp < toDateTime64('2024-01-02 00:00:00.000', 3) ORDER BY tenant_id, environment_id, timestamp, id DESC))",
+		},
 	}
 
 	for _, tt := range tests {
@@ -214,6 +214,18 @@ func (r *EventRepository) GetUsage(ctx context.Context, params *events.UsagePara
 		}
 	}
 
+	// Heartbeats are grouped by their interval, UPTIME usage can't be computed without it
+	if params.AggregationType == types.AggregationUptime && params.HeartbeatIntervalSeconds <= 0 {
+		err := ierr.NewError("heartbeat interval required for uptime aggregation").
+			WithHint("UPTIME aggregations require a positive heartbeat interval").
+			WithReportableDetails(map[string]interface{}{
+				"heartbeat_interval_seconds": params.HeartbeatIntervalSeconds,
+			}).
+			Mark(ierr.ErrValidation)
+		SetSpanError(span, err)
+		return nil, err
+	}
+
 	aggregator := GetAggregator(params.AggregationType)
 	if aggregator == nil {
 		err := ierr.NewError("unsupported aggregation type").
@@ -254,7 +266,7 @@ func (r *EventRepository) GetUsage(ctx context.Context, params *events.UsagePara
 			var total decimal.Decimal
 
 			switch params.AggregationType {
-			case types.AggregationCount, types.AggregationCountUnique:
+			case types.AggregationCount, types.AggregationCountUnique, types.AggregationCountOncePerPeriod:
 				var countValue uint64
 				if err := rows.Scan(&windowSize, &countValue); err != nil {
 					SetSpanError(span, err)
@@ -299,7 +311,7 @@ func (r *EventRepository) GetUsage(ctx context.Context, params *events.UsagePara
 					}
 					value = decimal.NewFromFloat(floatValue)
 				}
-			case types.AggregationSum, types.AggregationAvg, types.AggregationLatest, types.AggregationSumWithMultiplier, types.AggregationWeightedSum:
+			case types.AggregationSum, types.AggregationAvg, types.AggregationLatest, types.AggregationSumWithMultiplier, types.AggregationWeightedSum, types.AggregationUptime, types.AggregationCounterDelta:
 				var floatValue float64
 				if err := rows.Scan(&windowSize, &floatValue); err != nil {
 					SetSpanError(span, err)
@@ -336,7 +348,7 @@ func (r *EventRepository) GetUsage(ctx context.Context, params *events.UsagePara
 	} else {
 		if rows.Next() {
 			switch params.AggregationType {
-			case types.AggregationCount, types.AggregationCountUnique:
+			case types.AggregationCount, types.AggregationCountUnique, types.AggregationCountOncePerPeriod:
 				var value uint64
 				if err := rows.Scan(&value); err != nil {
 					SetSpanError(span, err)
@@ -348,7 +360,7 @@ func (r *EventRepository) GetUsage(ctx context.Context, params *events.UsagePara
 						Mark(ierr.ErrDatabase)
 				}
 				result.Value = decimal.NewFromUint64(value)
-			case types.AggregationSum, types.AggregationAvg, types.AggregationLatest, types.AggregationSumWithMultiplier, types.AggregationMax, types.AggregationWeightedSum:
+			case types.AggregationSum, types.AggregationAvg, types.AggregationLatest, types.AggregationSumWithMultiplier, types.AggregationMax, types.AggregationWeightedSum, types.AggregationUptime, types.AggregationCounterDelta:
 				var value float64
 				if err := rows.Scan(&value); err != nil {
 					SetSpanError(span, err)
@@ -839,7 +851,7 @@ func (r *EventRepository) FindUnprocessedEventsFromFeatureUsage(ctx context.Cont
 		FROM events e
 		ANTI JOIN (
 			SELECT id, tenant_id, environment_id
-			FROM feature_usage
+			FROM ` + r.store.FeatureUsageTable(types.GetTenantID(ctx)) + `
 			WHERE tenant_id = ?
 			AND environment_id = ?
 		) AS p
@@ -1037,6 +1049,104 @@ func (r *EventRepository) GetDistinctEventNames(ctx context.Context, externalCus
 	return eventNames, nil
 }
 
+// GetEventsByIDs fetches raw events by their IDs for the current tenant and environment
+func (r *EventRepository) GetEventsByIDs(ctx context.Context, eventIDs []string) ([]*events.Event, error) {
+	if len(eventIDs) == 0 {
+		return []*events.Event{}, nil
+	}
+
+	span := StartRepositorySpan(ctx, "event", "get_events_by_ids", map[string]interface{}{
+		"event_ids_count": len(eventIDs),
+	})
+	defer FinishSpan(span)
+
+	placeholders := make([]string, len(eventIDs))
+	args := make([]interface{}, 0, len(eventIDs)+2)
+	args = append(args, types.GetTenantID(ctx), types.GetEnvironmentID(ctx))
+	for i, eventID := range eventIDs {
+		placeholders[i] = "?"
+		args = append(args, eventID)
+	}
+
+	query := `
+		SELECT 
+			id,
+			external_customer_id,
+			customer_id,
+			tenant_id,
+			event_name,
+			timestamp,
+			source,
+			properties,
+			environment_id,
+			ingested_at
+		FROM events
+		WHERE tenant_id = ?
+		AND environment_id = ?
+		AND id IN (` + strings.Join(placeholders, ",") + `)
+		ORDER BY timestamp DESC, id DESC
+		LIMIT 1 BY id
+	`
+
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to query events by IDs").
+			WithReportableDetails(map[string]interface{}{
+				"event_ids_count": len(eventIDs),
+			}).
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	eventsList := make([]*events.Event, 0, len(eventIDs))
+	for rows.Next() {
+		var event events.Event
+		var propertiesJSON string
+
+		if err := rows.Scan(
+			&event.ID,
+			&event.ExternalCustomerID,
+			&event.CustomerID,
+			&event.TenantID,
+			&event.EventName,
+			&event.Timestamp,
+			&event.Source,
+			&propertiesJSON,
+			&event.EnvironmentID,
+			&event.IngestedAt,
+		); err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to scan event").
+				Mark(ierr.ErrDatabase)
+		}
+
+		if err := json.Unmarshal([]byte(propertiesJSON), &event.Properties); err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to unmarshal event properties").
+				WithReportableDetails(map[string]interface{}{
+					"event_id": event.ID,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+
+		eventsList = append(eventsList, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Error iterating event rows").
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return eventsList, nil
+}
+
 // GetTotalEventCount returns the total count of events in a given time range with optional windowed time-series data
 func (r *EventRepository) GetTotalEventCount(ctx context.Context, startTime, endTime time.Time, windowSize types.WindowSize) (*events.EventCountResult, error) {
 	span := StartRepositorySpan(ctx, "event", "get_total_event_count", map[string]interface{}{
@@ -1156,3 +1266,128 @@ func (r *EventRepository) GetTotalEventCount(ctx context.Context, startTime, end
 	SetSpanSuccess(span)
 	return result, nil
 }
+
+// GetClockSkewStats summarizes the delay between the timestamp and the ingestion of the events
+// ingested in the time range per customer and source, the most skewed producers first
+func (r *EventRepository) GetClockSkewStats(ctx context.Context, params *events.ClockSkewParams) ([]*events.ClockSkewStats, error) {
+	span := StartRepositorySpan(ctx, "event", "get_clock_skew_stats", map[string]interface{}{
+		"start_time":           params.StartTime,
+		"end_time":             params.EndTime,
+		"external_customer_id": params.ExternalCustomerID,
+		"source":               params.Source,
+	})
+	defer FinishSpan(span)
+
+	query := `
+		SELECT
+			external_customer_id,
+			coalesce(source, '') AS event_source,
+			count() AS event_count,
+			avg(skew_ms) AS avg_skew_ms,
+			min(skew_ms) AS min_skew_ms,
+			max(skew_ms) AS max_skew_ms,
+			quantiles(0.1, 0.5, 0.9)(skew_ms) AS skew_ms_quantiles
+		FROM (
+			SELECT
+				external_customer_id,
+				source,
+				toInt64(dateDiff('millisecond', timestamp, ingested_at)) AS skew_ms
+			FROM events
+			WHERE tenant_id = ?
+			AND environment_id = ?
+			AND ingested_at >= ?
+			AND ingested_at < ?
+	`
+
+	args := []interface{}{
+		types.GetTenantID(ctx),
+		types.GetEnvironmentID(ctx),
+		params.StartTime,
+		params.EndTime,
+	}
+
+	if params.MaxSkew > 0 {
+		query += " AND timestamp >= ? AND timestamp < ?"
+		args = append(args, params.StartTime.Add(-params.MaxSkew), params.EndTime.Add(params.MaxSkew))
+	}
+
+	if params.ExternalCustomerID != "" {
+		query += " AND external_customer_id = ?"
+		args = append(args, params.ExternalCustomerID)
+	}
+
+	if params.Source != "" {
+		query += " AND source = ?"
+		args = append(args, params.Source)
+	}
+
+	query += `
+		)
+		GROUP BY external_customer_id, event_source
+		HAVING event_count >= ?
+		ORDER BY abs(skew_ms_quantiles[2]) DESC, external_customer_id, event_source
+	`
+	args = append(args, params.MinEvents)
+
+	if params.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, params.Limit)
+	}
+
+	rows, err := r.store.GetConn().Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorw("failed to get clock skew stats",
+			"error", err,
+			"start_time", params.StartTime,
+			"end_time", params.EndTime)
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to execute clock skew query").
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	stats := make([]*events.ClockSkewStats, 0)
+	for rows.Next() {
+		var (
+			stat             events.ClockSkewStats
+			avgSkew          float64
+			minSkew, maxSkew int64
+			skewQuantiles    []float64
+		)
+		if err := rows.Scan(
+			&stat.ExternalCustomerID,
+			&stat.Source,
+			&stat.EventCount,
+			&avgSkew,
+			&minSkew,
+			&maxSkew,
+			&skewQuantiles,
+		); err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to scan clock skew row").
+				Mark(ierr.ErrDatabase)
+		}
+
+		stat.Avg = time.Duration(avgSkew) * time.Millisecond
+		stat.Min = time.Duration(minSkew) * time.Millisecond
+		stat.Max = time.Duration(maxSkew) * time.Millisecond
+		if len(skewQuantiles) == 3 {
+			stat.P10 = time.Duration(skewQuantiles[0]) * time.Millisecond
+			stat.P50 = time.Duration(skewQuantiles[1]) * time.Millisecond
+			stat.P90 = time.Duration(skewQuantiles[2]) * time.Millisecond
+		}
+		stats = append(stats, &stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Error iterating clock skew rows").
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return stats, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
@@ -31,14 +32,15 @@ func NewFeatureUsageRepository(store *clickhouse.ClickHouseStore, logger *logger
 
 // InsertProcessedEvent inserts a single processed event
 func (r *FeatureUsageRepository) InsertProcessedEvent(ctx context.Context, event *events.FeatureUsage) error {
+	table := r.store.FeatureUsageTable(event.TenantID)
 	query := `
-		INSERT INTO feature_usage (
+		INSERT INTO ` + table + ` (
 			id, tenant_id, external_customer_id, customer_id, event_name, source, 
 			timestamp, ingested_at, properties, environment_id,
 			subscription_id, sub_line_item_id, price_id, meter_id, feature_id, period_id,
-			unique_hash, qty_total, sign
+			unique_hash, qty_total, sign, correlation_id
 		) VALUES (
-			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 		)
 	`
 
@@ -78,6 +80,7 @@ func (r *FeatureUsageRepository) InsertProcessedEvent(ctx context.Context, event
 		event.UniqueHash,
 		event.QtyTotal,
 		sign,
+		event.CorrelationID,
 	}
 
 	err = r.store.GetConn().Exec(ctx, query, args...)
@@ -95,21 +98,87 @@ func (r *FeatureUsageRepository) InsertProcessedEvent(ctx context.Context, event
 
 // BulkInsertProcessedEvents inserts multiple processed events
 func (r *FeatureUsageRepository) BulkInsertProcessedEvents(ctx context.Context, events []*events.FeatureUsage) error {
-	if len(events) == 0 {
+	rows := make([]featureUsageRow, 0, len(events))
+	for _, event := range events {
+		// Default sign to 1 if not set
+		sign := event.Sign
+		if sign == 0 {
+			sign = 1
+		}
+		rows = append(rows, featureUsageRow{usage: event, sign: sign})
+	}
+	return r.bulkInsertFeatureUsage(ctx, rows)
+}
+
+// ReplaceProcessedEvents cancels the stored rows by inserting them again with sign 0 and inserts
+// their replacements, the replacement of a row at the same index. Each cancellation is written in
+// the same insert as its replacement so a failure never leaves one without the other. A cancelled
+// row only replaces the stored one once the parts are merged, so the usage is read with FINAL.
+func (r *FeatureUsageRepository) ReplaceProcessedEvents(ctx context.Context, cancelled, replacements []*events.FeatureUsage) error {
+	if len(cancelled) != len(replacements) {
+		return ierr.NewError("every cancelled feature usage row needs a replacement").
+			WithHint("Failed to replace feature usage").
+			WithReportableDetails(map[string]interface{}{
+				"cancelled":    len(cancelled),
+				"replacements": len(replacements),
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	rows := make([]featureUsageRow, 0, len(cancelled)*2)
+	for i := range cancelled {
+		sign := replacements[i].Sign
+		if sign == 0 {
+			sign = 1
+		}
+		rows = append(rows,
+			featureUsageRow{usage: cancelled[i], sign: 0},
+			featureUsageRow{usage: replacements[i], sign: sign},
+		)
+	}
+	return r.bulkInsertFeatureUsage(ctx, rows)
+}
+
+// featureUsageRow is a feature usage row with the sign it is written with
+type featureUsageRow struct {
+	usage *events.FeatureUsage
+	sign  int8
+}
+
+func (r *FeatureUsageRepository) bulkInsertFeatureUsage(ctx context.Context, rows []featureUsageRow) error {
+	if len(rows) == 0 {
 		return nil
 	}
 
-	// Split events in batches of 100
-	eventsBatches := lo.Chunk(events, 100)
+	// Route the events to the feature usage table of their tenant, in batches of 100
+	rowsByTable := lo.GroupBy(rows, func(row featureUsageRow) string {
+		return r.store.FeatureUsageTable(row.usage.TenantID)
+	})
+	tables := lo.Keys(rowsByTable)
+	sort.Strings(tables)
+
+	for _, table := range tables {
+		if err := r.insertFeatureUsageBatches(ctx, table, rowsByTable[table]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertFeatureUsageBatches inserts the rows into the table in batches of 100, an even size so
+// the cancellation and replacement pairs are never split across inserts
+func (r *FeatureUsageRepository) insertFeatureUsageBatches(ctx context.Context, table string, rows []featureUsageRow) error {
+	rowBatches := lo.Chunk(rows, 100)
 
-	for _, eventsBatch := range eventsBatches {
+	for _, rowBatch := range rowBatches {
 		// Prepare batch statement
 		batch, err := r.store.GetConn().PrepareBatch(ctx, `
-			INSERT INTO feature_usage (
+			INSERT INTO `+table+` (
 				id, tenant_id, external_customer_id, customer_id, event_name, source, 
 				timestamp, ingested_at, properties, environment_id,
 				subscription_id, sub_line_item_id, price_id, meter_id, feature_id, period_id,
-				unique_hash, qty_total, sign
+				unique_hash, qty_total, sign, correlation_id
 			)
 		`)
 		if err != nil {
@@ -118,7 +187,8 @@ func (r *FeatureUsageRepository) BulkInsertProcessedEvents(ctx context.Context,
 				Mark(ierr.ErrDatabase)
 		}
 
-		for _, event := range eventsBatch {
+		for _, row := range rowBatch {
+			event := row.usage
 			propertiesJSON, err := json.Marshal(event.Properties)
 			if err != nil {
 				return ierr.WithError(err).
@@ -129,12 +199,6 @@ func (r *FeatureUsageRepository) BulkInsertProcessedEvents(ctx context.Context,
 					Mark(ierr.ErrValidation)
 			}
 
-			// Default sign to 1 if not set
-			sign := event.Sign
-			if sign == 0 {
-				sign = 1
-			}
-
 			err = batch.Append(
 				event.ID,
 				event.TenantID,
@@ -154,7 +218,8 @@ func (r *FeatureUsageRepository) BulkInsertProcessedEvents(ctx context.Context,
 				event.PeriodID,
 				event.UniqueHash,
 				event.QtyTotal,
-				sign,
+				row.sign,
+				event.CorrelationID,
 			)
 
 			if err != nil {
@@ -172,7 +237,7 @@ func (r *FeatureUsageRepository) BulkInsertProcessedEvents(ctx context.Context,
 			return ierr.WithError(err).
 				WithHint("Failed to execute batch insert for feature usage").
 				WithReportableDetails(map[string]interface{}{
-					"event_count": len(events),
+					"event_count": len(rows),
 				}).
 				Mark(ierr.ErrDatabase)
 		}
@@ -183,26 +248,29 @@ func (r *FeatureUsageRepository) BulkInsertProcessedEvents(ctx context.Context,
 
 // GetProcessedEvents retrieves processed events based on the provided parameters
 func (r *FeatureUsageRepository) GetProcessedEvents(ctx context.Context, params *events.GetProcessedEventsParams) ([]*events.FeatureUsage, uint64, error) {
+	table := r.store.FeatureUsageTable(types.GetTenantID(ctx))
 	query := `
 		SELECT 
 			id, tenant_id, external_customer_id, customer_id, event_name, source, 
 			timestamp, ingested_at, properties, processed_at, environment_id,
 			subscription_id, sub_line_item_id, price_id, meter_id, feature_id, period_id,
-			unique_hash, qty_total,version, sign, processing_lag_ms
-		FROM feature_usage
+			unique_hash, qty_total,version, sign, processing_lag_ms, correlation_id
+		FROM ` + table + ` FINAL
 		WHERE tenant_id = ?
 		AND environment_id = ?
 		AND timestamp >= ?
 		AND timestamp <= ?
+		AND sign != 0
 	`
 
 	countQuery := `
 		SELECT COUNT(*)
-		FROM feature_usage
+		FROM ` + table + ` FINAL
 		WHERE tenant_id = ?
 		AND environment_id = ?
 		AND timestamp >= ?
 		AND timestamp <= ?
+		AND sign != 0
 	`
 
 	args := []interface{}{types.GetTenantID(ctx), types.GetEnvironmentID(ctx), params.StartTime, params.EndTime}
@@ -244,9 +312,8 @@ func (r *FeatureUsageRepository) GetProcessedEvents(ctx context.Context, params
 		countArgs = append(countArgs, params.PriceID)
 	}
 
-	// Add FINAL modifier to handle ReplacingMergeTree
-	query += " FINAL"
-	countQuery += " FINAL"
+	// A stable order keeps the pages consistent
+	query += " ORDER BY timestamp, id, sub_line_item_id"
 
 	// Apply pagination
 	if params.Limit > 0 {
@@ -307,6 +374,7 @@ func (r *FeatureUsageRepository) GetProcessedEvents(ctx context.Context, params
 			&event.Version,
 			&event.Sign,
 			&event.ProcessingLagMs,
+			&event.CorrelationID,
 		)
 		if err != nil {
 			return nil, 0, ierr.WithError(err).
@@ -333,9 +401,10 @@ func (r *FeatureUsageRepository) GetProcessedEvents(ctx context.Context, params
 
 // IsDuplicate checks if an event with the given unique hash already exists
 func (r *FeatureUsageRepository) IsDuplicate(ctx context.Context, subscriptionID, meterID string, periodID uint64, uniqueHash string) (bool, error) {
+	table := r.store.FeatureUsageTable(types.GetTenantID(ctx))
 	query := `
 		SELECT 1 
-		FROM feature_usage 
+		FROM ` + table + ` 
 		WHERE subscription_id = ? 
 		AND meter_id = ? 
 		AND period_id = ? 
@@ -358,12 +427,115 @@ func (r *FeatureUsageRepository) IsDuplicate(ctx context.Context, subscriptionID
 	return exists == 1, nil
 }
 
+// GetLatestProcessedEventBefore returns the latest feature usage of the customer's meter with a
+// timestamp before the given time, nil when there is none
+func (r *FeatureUsageRepository) GetLatestProcessedEventBefore(ctx context.Context, customerID, meterID string, before time.Time) (*events.FeatureUsage, error) {
+	table := r.store.FeatureUsageTable(types.GetTenantID(ctx))
+	span := StartRepositorySpan(ctx, "feature_usage", "get_latest_processed_event_before", map[string]interface{}{
+		"customer_id": customerID,
+		"meter_id":    meterID,
+		"before":      before,
+	})
+	defer FinishSpan(span)
+
+	query := `
+		SELECT
+			id, tenant_id, external_customer_id, customer_id, event_name, source,
+			timestamp, ingested_at, properties, processed_at, environment_id,
+			subscription_id, sub_line_item_id, price_id, meter_id, feature_id, period_id,
+			unique_hash, qty_total, version, sign, processing_lag_ms, correlation_id
+		FROM ` + table + ` FINAL
+		WHERE tenant_id = ?
+		AND environment_id = ?
+		AND customer_id = ?
+		AND meter_id = ?
+		AND timestamp < ?
+		AND sign != 0
+		ORDER BY timestamp DESC, id DESC
+		LIMIT 1
+	`
+
+	rows, err := r.store.GetConn().Query(ctx, query,
+		types.GetTenantID(ctx),
+		types.GetEnvironmentID(ctx),
+		customerID,
+		meterID,
+		before,
+	)
+	if err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to get the latest processed event").
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to get the latest processed event").
+				Mark(ierr.ErrDatabase)
+		}
+		SetSpanSuccess(span)
+		return nil, nil
+	}
+
+	var record events.FeatureUsage
+	var propertiesJSON string
+	if err := rows.Scan(
+		&record.ID,
+		&record.TenantID,
+		&record.ExternalCustomerID,
+		&record.CustomerID,
+		&record.EventName,
+		&record.Source,
+		&record.Timestamp,
+		&record.IngestedAt,
+		&propertiesJSON,
+		&record.ProcessedAt,
+		&record.EnvironmentID,
+		&record.SubscriptionID,
+		&record.SubLineItemID,
+		&record.PriceID,
+		&record.MeterID,
+		&record.FeatureID,
+		&record.PeriodID,
+		&record.UniqueHash,
+		&record.QtyTotal,
+		&record.Version,
+		&record.Sign,
+		&record.ProcessingLagMs,
+		&record.CorrelationID,
+	); err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to scan processed event").
+			Mark(ierr.ErrDatabase)
+	}
+
+	if propertiesJSON != "" {
+		if err := json.Unmarshal([]byte(propertiesJSON), &record.Properties); err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to unmarshal properties").
+				Mark(ierr.ErrValidation)
+		}
+	} else {
+		record.Properties = make(map[string]interface{})
+	}
+
+	SetSpanSuccess(span)
+	return &record, nil
+}
+
 // GetDetailedUsageAnalytics provides comprehensive usage analytics with filtering, grouping, and time-series data
 func (r *FeatureUsageRepository) GetDetailedUsageAnalytics(ctx context.Context, params *events.UsageAnalyticsParams, maxBucketFeatures map[string]*events.MaxBucketFeatureInfo) ([]*events.DetailedUsageAnalytic, error) {
 	span := StartRepositorySpan(ctx, "processed_event", "get_detailed_usage_analytics", map[string]interface{}{
 		"external_customer_id":   params.ExternalCustomerID,
 		"feature_ids_count":      len(params.FeatureIDs),
 		"sources_count":          len(params.Sources),
+		"price_ids_count":        len(params.PriceIDs),
 		"property_filters_count": len(params.PropertyFilters),
 	})
 	defer FinishSpan(span)
@@ -461,6 +633,7 @@ func (r *FeatureUsageRepository) getOtherFeatureIDs(requestedFeatureIDs []string
 
 // getStandardAnalytics handles analytics for non-MAX with bucket features
 func (r *FeatureUsageRepository) getStandardAnalytics(ctx context.Context, params *events.UsageAnalyticsParams, maxBucketFeatures map[string]*events.MaxBucketFeatureInfo) ([]*events.DetailedUsageAnalytic, error) {
+	table := r.store.FeatureUsageTable(params.TenantID)
 	// Initialize query parameters with the standard parameters that will be added later
 	// This ensures they're always in the right order
 	queryParams := []interface{}{
@@ -515,12 +688,20 @@ func (r *FeatureUsageRepository) getStandardAnalytics(ctx context.Context, param
 		"argMax(qty_total, timestamp) AS latest_usage",
 		"COUNT(DISTINCT unique_hash) AS count_unique_usage",
 		"COUNT(DISTINCT id) AS event_count", // Count distinct event IDs, not rows
+		"arraySort(arrayFilter(s -> s != '', groupUniqArray(source))) AS sources",
 	)
 
+	// Weight the usage of each event by the numeric value of the weight property, the weight
+	// parameter comes before the filter parameters
+	if params.WeightProperty != "" {
+		selectColumns = append(selectColumns, "SUM(qty_total * sign * toDecimal64(JSONExtractFloat(properties, ?), 6)) AS weighted_usage")
+		queryParams = append([]interface{}{params.WeightProperty}, queryParams...)
+	}
+
 	aggregateQuery := fmt.Sprintf(`
 		SELECT 
 			%s
-		FROM feature_usage
+		FROM `+table+` FINAL
 		WHERE tenant_id = ?
 		AND environment_id = ?
 		AND customer_id = ?
@@ -565,6 +746,21 @@ func (r *FeatureUsageRepository) getStandardAnalytics(ctx context.Context, param
 		aggregateQuery += " AND source IN (" + strings.Join(placeholders, ", ") + ")"
 	}
 
+	// Add filters for price ids
+	if len(params.PriceIDs) > 0 {
+		placeholders := make([]string, len(params.PriceIDs))
+		for i := range params.PriceIDs {
+			placeholders[i] = "?"
+			filterParams = append(filterParams, params.PriceIDs[i])
+		}
+		aggregateQuery += " AND price_id IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	// Only count the usage billed in the billable windows
+	billableQuery, billableArgs := billableWindowsCondition(params)
+	aggregateQuery += billableQuery
+	filterParams = append(filterParams, billableArgs...)
+
 	// add properties filters
 	if len(params.PropertyFilters) > 0 {
 		for property, values := range params.PropertyFilters {
@@ -604,7 +800,7 @@ func (r *FeatureUsageRepository) getStandardAnalytics(ctx context.Context, param
 	)
 
 	// Execute the query
-	rows, err := r.store.GetConn().Query(ctx, aggregateQuery, queryParams...)
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, aggregateQuery, queryParams...)
 	if err != nil {
 		return nil, ierr.WithError(err).
 			WithHint("Failed to execute usage analytics query").
@@ -631,7 +827,10 @@ func (r *FeatureUsageRepository) getStandardAnalytics(ctx context.Context, param
 		// The actual number of group by columns is determined by the query structure
 		// which includes feature_id + all requested grouping dimensions
 		totalGroupByColumns := len(groupByColumns) // This matches the actual GROUP BY columns in the query
-		expectedColumns := totalGroupByColumns + 5 // +5 for sum_usage, max_usage, latest_usage, count_unique_usage, event_count
+		expectedColumns := totalGroupByColumns + 6 // +6 for sum_usage, max_usage, latest_usage, count_unique_usage, event_count, sources
+		if params.WeightProperty != "" {
+			expectedColumns++ // weighted_usage
+		}
 		scanArgs := make([]interface{}, expectedColumns)
 
 		// Prepare scan targets: all group by columns
@@ -646,6 +845,10 @@ func (r *FeatureUsageRepository) getStandardAnalytics(ctx context.Context, param
 		scanArgs[totalGroupByColumns+2] = &analytics.LatestUsage
 		scanArgs[totalGroupByColumns+3] = &analytics.CountUniqueUsage
 		scanArgs[totalGroupByColumns+4] = &analytics.EventCount
+		scanArgs[totalGroupByColumns+5] = &analytics.Sources
+		if params.WeightProperty != "" {
+			scanArgs[totalGroupByColumns+6] = &analytics.WeightedUsage
+		}
 
 		if err := rows.Scan(scanArgs...); err != nil {
 			return nil, ierr.WithError(err).
@@ -735,8 +938,9 @@ func (r *FeatureUsageRepository) getMaxBucketAnalytics(ctx context.Context, para
 			return nil, err
 		}
 
-		// Get window-based time series points for each group
-		if featureInfo.BucketSize != "" {
+		// Get window-based time series points for each group, rolling window features have no
+		// buckets to build points from, only totals
+		if featureInfo.BucketSize != "" && featureInfo.RollingWindowHours == 0 {
 			// Need to get points per group to match totals
 			for _, total := range totals {
 				points, err := r.getMaxBucketPointsForGroup(ctx, &featureParams, featureInfo, total)
@@ -754,13 +958,17 @@ func (r *FeatureUsageRepository) getMaxBucketAnalytics(ctx context.Context, para
 	return allResults, nil
 }
 
-// getMaxBucketTotals calculates totals using bucket-based aggregation for MAX features
+// getMaxBucketTotals calculates totals using bucket-based aggregation for MAX features. Features
+// with a rolling window have no buckets, each event is assigned the usage summed over the window
+// ending at its timestamp and the total is the highest of those sums. Events up to a window
+// before the start time are read so the first windows of the range are complete.
 func (r *FeatureUsageRepository) getMaxBucketTotals(ctx context.Context, params *events.UsageAnalyticsParams, featureInfo *events.MaxBucketFeatureInfo) ([]*events.DetailedUsageAnalytic, error) {
-	// Build bucket window expression based on meter's bucket size
-	bucketWindowExpr := r.formatWindowSize(featureInfo.BucketSize, nil)
+	table := r.store.FeatureUsageTable(params.TenantID)
+	window := time.Duration(featureInfo.RollingWindowHours) * time.Hour
+	rolling := window > 0
 
 	// Build group by columns based on request parameters
-	groupByColumns := []string{"bucket_start", "feature_id", "price_id", "meter_id", "sub_line_item_id"}
+	groupByColumns := []string{"feature_id", "price_id", "meter_id", "sub_line_item_id"}
 	innerSelectColumns := []string{"feature_id", "price_id", "meter_id", "sub_line_item_id"} // For inner query (has access to properties column)
 	outerSelectColumns := []string{"feature_id", "price_id", "meter_id", "sub_line_item_id"} // For outer query (only has aliased columns)
 
@@ -787,31 +995,56 @@ func (r *FeatureUsageRepository) getMaxBucketTotals(ctx context.Context, params
 	// For MAX with bucket, we need to:
 	// 1. Find the max value within each bucket (grouped by requested fields)
 	// 2. Aggregate across all buckets to get totals
+	// For MAX with a rolling window, the frame covers (timestamp - window, timestamp] in epoch
+	// milliseconds and the partition plays the role of the bucket grouping
 
 	// Build inner query with filters
-	innerQuery := fmt.Sprintf(`
+	var innerQuery string
+	startTime := params.StartTime
+	if rolling {
+		startTime = params.StartTime.Add(-window)
+		innerQuery = fmt.Sprintf(`
+		SELECT
+			%s,
+			timestamp,
+			unique_hash,
+			id,
+			source as event_source,
+			sum(qty_total * sign) OVER (
+				PARTITION BY %s
+				ORDER BY toUnixTimestamp64Milli(timestamp)
+				RANGE BETWEEN %d PRECEDING AND CURRENT ROW
+			) as window_usage`, strings.Join(innerSelectColumns, ", "), strings.Join(groupByColumns, ", "), window.Milliseconds()-1)
+	} else {
+		// Build bucket window expression based on meter's bucket size
+		bucketWindowExpr := r.formatWindowSize(featureInfo.BucketSize, nil)
+		groupByColumns = append([]string{"bucket_start"}, groupByColumns...)
+		innerQuery = fmt.Sprintf(`
 		SELECT
 			%s as bucket_start,
 			%s,
 			max(qty_total * sign) as bucket_max,
 			argMax(qty_total, timestamp) as bucket_latest,
 			count(DISTINCT unique_hash) as bucket_count_unique,
-			count(DISTINCT id) as event_count
-		FROM feature_usage
+			count(DISTINCT id) as event_count,
+			groupUniqArray(source) as bucket_sources`, bucketWindowExpr, strings.Join(innerSelectColumns, ", "))
+	}
+	innerQuery += `
+		FROM ` + table + ` FINAL
 		WHERE tenant_id = ?
 		AND environment_id = ?
 		AND customer_id = ?
 		AND feature_id = ?
 		AND timestamp >= ?
 		AND timestamp < ?
-		AND sign != 0`, bucketWindowExpr, strings.Join(innerSelectColumns, ", "))
+		AND sign != 0`
 
 	queryParams := []interface{}{
 		params.TenantID,
 		params.EnvironmentID,
 		params.CustomerID,
 		featureInfo.FeatureID,
-		params.StartTime,
+		startTime,
 		params.EndTime,
 	}
 
@@ -827,6 +1060,23 @@ func (r *FeatureUsageRepository) getMaxBucketTotals(ctx context.Context, params
 		}
 	}
 
+	// Add filters for price ids to inner query
+	if len(params.PriceIDs) > 0 {
+		placeholders := make([]string, len(params.PriceIDs))
+		for i := range params.PriceIDs {
+			placeholders[i] = "?"
+		}
+		innerQuery += " AND price_id IN (" + strings.Join(placeholders, ", ") + ")"
+		for _, priceID := range params.PriceIDs {
+			queryParams = append(queryParams, priceID)
+		}
+	}
+
+	// Only count the usage billed in the billable windows
+	billableQuery, billableArgs := billableWindowsCondition(params)
+	innerQuery += billableQuery
+	queryParams = append(queryParams, billableArgs...)
+
 	// Add property filters to inner query
 	if len(params.PropertyFilters) > 0 {
 		for property, values := range params.PropertyFilters {
@@ -850,11 +1100,32 @@ func (r *FeatureUsageRepository) getMaxBucketTotals(ctx context.Context, params
 		}
 	}
 
-	// Complete the inner query with GROUP BY
-	innerQuery += fmt.Sprintf(" GROUP BY %s", strings.Join(groupByColumns, ", "))
+	var query string
+	if rolling {
+		// Only events within the requested range report their window, earlier events only
+		// contribute to the windows of later ones
+		query = fmt.Sprintf(`
+		WITH rolling_usage AS (
+			%s
+		)
+		SELECT
+			%s,
+			max(window_usage) as total_usage,
+			max(window_usage) as max_usage,
+			argMax(window_usage, timestamp) as latest_usage,
+			count(DISTINCT unique_hash) as count_unique_usage,
+			count(DISTINCT id) as event_count,
+			arraySort(arrayFilter(s -> s != '', groupUniqArray(event_source))) as sources
+		FROM rolling_usage
+		WHERE timestamp >= ?
+	`, innerQuery, strings.Join(outerSelectColumns, ", "))
+		queryParams = append(queryParams, params.StartTime)
+	} else {
+		// Complete the inner query with GROUP BY
+		innerQuery += fmt.Sprintf(" GROUP BY %s", strings.Join(groupByColumns, ", "))
 
-	// Build the complete query with CTE
-	query := fmt.Sprintf(`
+		// Build the complete query with CTE
+		query = fmt.Sprintf(`
 		WITH bucket_maxes AS (
 			%s
 		)
@@ -864,20 +1135,23 @@ func (r *FeatureUsageRepository) getMaxBucketTotals(ctx context.Context, params
 			max(bucket_max) as max_usage,
 			argMax(bucket_latest, bucket_start) as latest_usage,
 			sum(bucket_count_unique) as count_unique_usage,
-			sum(event_count) as event_count
+			sum(event_count) as event_count,
+			arraySort(arrayFilter(s -> s != '', groupUniqArrayArray(bucket_sources))) as sources
 		FROM bucket_maxes
 	`, innerQuery, strings.Join(outerSelectColumns, ", "))
+	}
 
 	// Add GROUP BY clause
 	query += " GROUP BY " + strings.Join(outerSelectColumns, ", ")
 
-	rows, err := r.store.GetConn().Query(ctx, query, queryParams...)
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, queryParams...)
 	if err != nil {
 		return nil, ierr.WithError(err).
 			WithHint("Failed to execute MAX bucket totals query").
 			WithReportableDetails(map[string]interface{}{
-				"feature_id":  featureInfo.FeatureID,
-				"bucket_size": featureInfo.BucketSize,
+				"feature_id":           featureInfo.FeatureID,
+				"bucket_size":          featureInfo.BucketSize,
+				"rolling_window_hours": featureInfo.RollingWindowHours,
 			}).
 			Mark(ierr.ErrDatabase)
 	}
@@ -895,8 +1169,8 @@ func (r *FeatureUsageRepository) getMaxBucketTotals(ctx context.Context, params
 		}
 
 		// Build scan targets dynamically based on outerSelectColumns structure
-		// The query selects: outerSelectColumns + total_usage + max_usage + latest_usage + count_unique_usage + event_count
-		totalSelectColumns := len(outerSelectColumns) + 5 // +5 for total_usage, max_usage, latest_usage, count_unique_usage, event_count
+		// The query selects: outerSelectColumns + total_usage + max_usage + latest_usage + count_unique_usage + event_count + sources
+		totalSelectColumns := len(outerSelectColumns) + 6 // +6 for total_usage, max_usage, latest_usage, count_unique_usage, event_count, sources
 		scanTargets := make([]interface{}, totalSelectColumns)
 
 		// Create string targets for all select columns
@@ -911,6 +1185,7 @@ func (r *FeatureUsageRepository) getMaxBucketTotals(ctx context.Context, params
 		scanTargets[len(outerSelectColumns)+2] = &analytics.LatestUsage
 		scanTargets[len(outerSelectColumns)+3] = &analytics.CountUniqueUsage
 		scanTargets[len(outerSelectColumns)+4] = &analytics.EventCount
+		scanTargets[len(outerSelectColumns)+5] = &analytics.Sources
 
 		err := rows.Scan(scanTargets...)
 		if err != nil {
@@ -949,6 +1224,7 @@ func (r *FeatureUsageRepository) getMaxBucketTotals(ctx context.Context, params
 
 // getMaxBucketPointsForGroup calculates time series points for a specific group
 func (r *FeatureUsageRepository) getMaxBucketPointsForGroup(ctx context.Context, params *events.UsageAnalyticsParams, featureInfo *events.MaxBucketFeatureInfo, group *events.DetailedUsageAnalytic) ([]events.UsageAnalyticPoint, error) {
+	table := r.store.FeatureUsageTable(params.TenantID)
 	// Build window expression based on request window size
 	windowExpr := r.formatWindowSize(featureInfo.BucketSize, params.BillingAnchor)
 
@@ -965,7 +1241,7 @@ func (r *FeatureUsageRepository) getMaxBucketPointsForGroup(ctx context.Context,
 			argMax(qty_total, timestamp) as bucket_latest,
 			count(DISTINCT unique_hash) as bucket_count_unique,
 			count(DISTINCT id) as event_count
-		FROM feature_usage
+		FROM `+table+` FINAL
 		WHERE tenant_id = ?
 		AND environment_id = ?
 		AND customer_id = ?
@@ -1007,6 +1283,11 @@ func (r *FeatureUsageRepository) getMaxBucketPointsForGroup(ctx context.Context,
 		queryParams = append(queryParams, group.SubLineItemID)
 	}
 
+	// Only count the usage billed in the billable windows
+	billableQuery, billableArgs := billableWindowsCondition(params)
+	innerQuery += billableQuery
+	queryParams = append(queryParams, billableArgs...)
+
 	// Add filters for this specific group's properties
 	if len(group.Properties) > 0 {
 		for propertyName, propertyValue := range group.Properties {
@@ -1061,7 +1342,7 @@ func (r *FeatureUsageRepository) getMaxBucketPointsForGroup(ctx context.Context,
 	// Add GROUP BY and ORDER BY clauses
 	query += " GROUP BY window_start ORDER BY window_start"
 
-	rows, err := r.store.GetConn().Query(ctx, query, queryParams...)
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, queryParams...)
 	if err != nil {
 		return nil, ierr.WithError(err).
 			WithHint("Failed to execute MAX bucket points query").
@@ -1104,6 +1385,12 @@ func (r *FeatureUsageRepository) getMaxBucketPointsForGroup(ctx context.Context,
 // formatWindowSize formats window size for ClickHouse queries
 func (r *FeatureUsageRepository) formatWindowSize(windowSize types.WindowSize, billingAnchor *time.Time) string {
 	switch windowSize {
+	case types.WindowSizeSecond:
+		return "toStartOfInterval(timestamp, INTERVAL 1 SECOND)"
+	case types.WindowSize10Sec:
+		return "toStartOfInterval(timestamp, INTERVAL 10 SECOND)"
+	case types.WindowSize30Sec:
+		return "toStartOfInterval(timestamp, INTERVAL 30 SECOND)"
 	case types.WindowSizeMinute:
 		return "toStartOfMinute(timestamp)"
 	case types.WindowSizeHour:
@@ -1146,10 +1433,17 @@ func (r *FeatureUsageRepository) getAnalyticsPoints(
 	params *events.UsageAnalyticsParams,
 	analytics *events.DetailedUsageAnalytic,
 ) ([]events.UsageAnalyticPoint, error) {
+	table := r.store.FeatureUsageTable(params.TenantID)
 	// Build the time window expression based on window size
 	var timeWindowExpr string
 
 	switch params.WindowSize {
+	case types.WindowSizeSecond:
+		timeWindowExpr = "toStartOfInterval(timestamp, INTERVAL 1 SECOND)"
+	case types.WindowSize10Sec:
+		timeWindowExpr = "toStartOfInterval(timestamp, INTERVAL 10 SECOND)"
+	case types.WindowSize30Sec:
+		timeWindowExpr = "toStartOfInterval(timestamp, INTERVAL 30 SECOND)"
 	case types.WindowSizeMinute:
 		timeWindowExpr = "toStartOfMinute(timestamp)"
 	case types.WindowSizeHour:
@@ -1202,7 +1496,7 @@ func (r *FeatureUsageRepository) getAnalyticsPoints(
 	query := fmt.Sprintf(`
 		SELECT 
 			%s
-		FROM feature_usage
+		FROM `+table+` FINAL
 		WHERE tenant_id = ?
 		AND environment_id = ?
 		AND customer_id = ?
@@ -1242,6 +1536,11 @@ func (r *FeatureUsageRepository) getAnalyticsPoints(
 		queryParams = append(queryParams, analytics.SubLineItemID)
 	}
 
+	// Only count the usage billed in the billable windows
+	billableQuery, billableArgs := billableWindowsCondition(params)
+	query += billableQuery
+	queryParams = append(queryParams, billableArgs...)
+
 	// Add source filter if present in analytics
 	if analytics.Source != "" {
 		query += " AND source = ?"
@@ -1297,7 +1596,7 @@ func (r *FeatureUsageRepository) getAnalyticsPoints(
 	)
 
 	// Execute the query
-	rows, err := r.store.GetConn().Query(ctx, query, queryParams...)
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, queryParams...)
 	if err != nil {
 		return nil, ierr.WithError(err).
 			WithHint("Failed to execute time-series query").
@@ -1347,6 +1646,7 @@ func (r *FeatureUsageRepository) getAnalyticsPoints(
 
 // GetFeatureUsageBySubscription gets usage data for a subscription using a single optimized query
 func (r *FeatureUsageRepository) GetFeatureUsageBySubscription(ctx context.Context, subscriptionID, externalCustomerID string, startTime, endTime time.Time) (map[string]*events.UsageByFeatureResult, error) {
+	table := r.store.FeatureUsageTable(types.GetTenantID(ctx))
 	// Extract tenantID and environmentID from context
 	tenantID := types.GetTenantID(ctx)
 	environmentID := types.GetEnvironmentID(ctx)
@@ -1373,7 +1673,7 @@ func (r *FeatureUsageRepository) GetFeatureUsageBySubscription(ctx context.Conte
 			count(DISTINCT id)                 AS count_distinct_ids,
 			count(DISTINCT unique_hash)        AS count_unique_qty,
 			argMax(qty_total * sign, "timestamp") AS latest_qty
-		FROM feature_usage
+		FROM ` + table + ` FINAL
 		WHERE 
 			subscription_id = ?
 			AND external_customer_id = ?
@@ -1445,6 +1745,7 @@ func (r *FeatureUsageRepository) GetFeatureUsageBySubscription(ctx context.Conte
 
 // GetFeatureUsageForExport retrieves feature usage data for export in batches
 func (r *FeatureUsageRepository) GetFeatureUsageForExport(ctx context.Context, startTime, endTime time.Time, batchSize int, offset int) ([]*events.FeatureUsage, error) {
+	table := r.store.FeatureUsageTable(types.GetTenantID(ctx))
 	// Extract tenantID and environmentID from context
 	tenantID := types.GetTenantID(ctx)
 	environmentID := types.GetEnvironmentID(ctx)
@@ -1480,7 +1781,7 @@ func (r *FeatureUsageRepository) GetFeatureUsageForExport(ctx context.Context, s
 			unique_hash,
 			qty_total,
 			sign
-		FROM feature_usage
+		FROM ` + table + ` FINAL
 		WHERE tenant_id = ?
 		  AND environment_id = ?
 		  AND timestamp >= ?
@@ -1626,6 +1927,7 @@ func (r *FeatureUsageRepository) GetUsageForMaxMetersWithBuckets(ctx context.Con
 }
 
 func (r *FeatureUsageRepository) getWindowedQuery(ctx context.Context, params *events.FeatureUsageParams) string {
+	table := r.store.FeatureUsageTable(types.GetTenantID(ctx))
 	bucketWindow := r.formatWindowSize(params.UsageParams.WindowSize, params.UsageParams.BillingAnchor)
 
 	externalCustomerFilter := ""
@@ -1662,7 +1964,7 @@ func (r *FeatureUsageRepository) getWindowedQuery(ctx context.Context, params *e
 			SELECT
 				%s as bucket_start,
 				max(qty_total * sign) as bucket_max
-			FROM feature_usage
+			FROM `+table+` FINAL
 			PREWHERE tenant_id = '%s'
 				AND environment_id = '%s'
 				%s
@@ -1694,8 +1996,10 @@ func (r *FeatureUsageRepository) getWindowedQuery(ctx context.Context, params *e
 		timeConditions)
 }
 
-// GetFeatureUsageByEventIDs queries the feature_usage table for events by their IDs
-func (r *FeatureUsageRepository) GetFeatureUsageByEventIDs(ctx context.Context, eventIDs []string) ([]*events.FeatureUsage, error) {
+// GetFeatureUsageByEventIDs queries the feature_usage table for events by their IDs, a positive
+// limit caps the rows returned per event
+func (r *FeatureUsageRepository) GetFeatureUsageByEventIDs(ctx context.Context, eventIDs []string, limit int) ([]*events.FeatureUsage, error) {
+	table := r.store.FeatureUsageTable(types.GetTenantID(ctx))
 	if len(eventIDs) == 0 {
 		return nil, nil
 	}
@@ -1709,11 +2013,12 @@ func (r *FeatureUsageRepository) GetFeatureUsageByEventIDs(ctx context.Context,
 			id, tenant_id, external_customer_id, customer_id, event_name, source, 
 			timestamp, ingested_at, properties, processed_at, environment_id,
 			subscription_id, sub_line_item_id, price_id, meter_id, feature_id, period_id,
-			unique_hash, qty_total, version, sign, processing_lag_ms
-		FROM feature_usage FINAL
+			unique_hash, qty_total, version, sign, processing_lag_ms, correlation_id
+		FROM ` + table + ` FINAL
 		WHERE tenant_id = ?
 		AND environment_id = ?
 		AND id IN (?)
+		ORDER BY id, sub_line_item_id
 	`
 
 	// ClickHouse requires special handling for IN clause with arrays
@@ -1729,7 +2034,12 @@ func (r *FeatureUsageRepository) GetFeatureUsageByEventIDs(ctx context.Context,
 
 	query = strings.Replace(query, "IN (?)", "IN ("+strings.Join(placeholders, ",")+")", 1)
 
-	rows, err := r.store.GetConn().Query(ctx, query, args...)
+	if limit > 0 {
+		query += " LIMIT ? BY id"
+		args = append(args, limit)
+	}
+
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, ierr.WithError(err).
 			WithHint("Failed to query feature_usage by event IDs").
@@ -1765,6 +2075,7 @@ func (r *FeatureUsageRepository) GetFeatureUsageByEventIDs(ctx context.Context,
 			&record.Version,
 			&record.Sign,
 			&record.ProcessingLagMs,
+			&record.CorrelationID,
 		)
 		if err != nil {
 			return nil, ierr.WithError(err).
@@ -1790,3 +2101,682 @@ func (r *FeatureUsageRepository) GetFeatureUsageByEventIDs(ctx context.Context,
 
 	return records, nil
 }
+
+// GetProcessedEventIDs returns the given event IDs that have feature usage recorded, without
+// reading the usage records themselves
+func (r *FeatureUsageRepository) GetProcessedEventIDs(ctx context.Context, eventIDs []string) ([]string, error) {
+	table := r.store.FeatureUsageTable(types.GetTenantID(ctx))
+	if len(eventIDs) == 0 {
+		return nil, nil
+	}
+
+	span := StartRepositorySpan(ctx, "feature_usage", "get_processed_event_ids", map[string]interface{}{
+		"event_ids_count": len(eventIDs),
+	})
+	defer FinishSpan(span)
+
+	placeholders := make([]string, len(eventIDs))
+	args := make([]interface{}, 0, 2+len(eventIDs))
+	args = append(args, types.GetTenantID(ctx), types.GetEnvironmentID(ctx))
+	for i, eventID := range eventIDs {
+		placeholders[i] = "?"
+		args = append(args, eventID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT id
+		FROM `+table+` FINAL
+		WHERE tenant_id = ?
+		AND environment_id = ?
+		AND sign != 0
+		AND id IN (%s)
+	`, strings.Join(placeholders, ","))
+
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to query processed event IDs").
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	processed := make([]string, 0, len(eventIDs))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to scan processed event ID").
+				Mark(ierr.ErrDatabase)
+		}
+		processed = append(processed, id)
+	}
+
+	SetSpanSuccess(span)
+	return processed, nil
+}
+
+// GetEventSampleIDs returns up to limit of the most recent event IDs per feature that
+// contributed usage for the customer in the analytics window
+func (r *FeatureUsageRepository) GetEventSampleIDs(ctx context.Context, params *events.UsageAnalyticsParams, limit int) (map[string][]string, error) {
+	table := r.store.FeatureUsageTable(params.TenantID)
+	samples := make(map[string][]string)
+	if limit <= 0 {
+		return samples, nil
+	}
+
+	span := StartRepositorySpan(ctx, "feature_usage", "get_event_sample_ids", map[string]interface{}{
+		"customer_id":       params.CustomerID,
+		"feature_ids_count": len(params.FeatureIDs),
+		"limit":             limit,
+	})
+	defer FinishSpan(span)
+
+	query := `
+		SELECT feature_id, id
+		FROM ` + table + ` FINAL
+		WHERE tenant_id = ?
+		AND environment_id = ?
+		AND customer_id = ?
+		AND timestamp >= ?
+		AND timestamp < ?
+		AND sign != 0
+	`
+	args := []interface{}{
+		params.TenantID,
+		params.EnvironmentID,
+		params.CustomerID,
+		params.StartTime,
+		params.EndTime,
+	}
+
+	if len(params.FeatureIDs) > 0 {
+		placeholders := make([]string, len(params.FeatureIDs))
+		for i := range params.FeatureIDs {
+			placeholders[i] = "?"
+			args = append(args, params.FeatureIDs[i])
+		}
+		query += " AND feature_id IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	if len(params.Sources) > 0 {
+		placeholders := make([]string, len(params.Sources))
+		for i := range params.Sources {
+			placeholders[i] = "?"
+			args = append(args, params.Sources[i])
+		}
+		query += " AND source IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	if len(params.PriceIDs) > 0 {
+		placeholders := make([]string, len(params.PriceIDs))
+		for i := range params.PriceIDs {
+			placeholders[i] = "?"
+			args = append(args, params.PriceIDs[i])
+		}
+		query += " AND price_id IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	// Only count the usage billed in the billable windows
+	billableQuery, billableArgs := billableWindowsCondition(params)
+	query += billableQuery
+	args = append(args, billableArgs...)
+
+	for property, values := range params.PropertyFilters {
+		if len(values) == 0 {
+			continue
+		}
+		placeholders := make([]string, len(values))
+		for i := range values {
+			placeholders[i] = "?"
+		}
+		query += " AND JSONExtractString(properties, ?) IN (" + strings.Join(placeholders, ",") + ")"
+		args = append(args, property)
+		for _, v := range values {
+			args = append(args, v)
+		}
+	}
+
+	// Distinct event IDs per feature, latest first
+	query += fmt.Sprintf(" GROUP BY feature_id, id ORDER BY max(timestamp) DESC LIMIT %d BY feature_id", limit)
+
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to fetch event samples for analytics").
+			WithReportableDetails(map[string]interface{}{
+				"customer_id": params.CustomerID,
+			}).
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var featureID, eventID string
+		if err := rows.Scan(&featureID, &eventID); err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to scan event sample row").
+				Mark(ierr.ErrDatabase)
+		}
+		samples[featureID] = append(samples[featureID], eventID)
+	}
+
+	if err := rows.Err(); err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Error iterating event sample rows").
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return samples, nil
+}
+
+// GetPropertyValuesByPrice returns the distinct values of the given event properties per price
+// for the customer in the analytics window. Events without a property are ignored for it. Up to
+// limit values are returned per property, 0 returns them all.
+func (r *FeatureUsageRepository) GetPropertyValuesByPrice(ctx context.Context, params *events.UsageAnalyticsParams, properties []string, limit int) (map[string]map[string][]string, error) {
+	table := r.store.FeatureUsageTable(params.TenantID)
+	values := make(map[string]map[string][]string)
+	if len(properties) == 0 {
+		return values, nil
+	}
+
+	span := StartRepositorySpan(ctx, "feature_usage", "get_property_values_by_price", map[string]interface{}{
+		"customer_id":      params.CustomerID,
+		"properties_count": len(properties),
+	})
+	defer FinishSpan(span)
+
+	uniqArray := "groupUniqArray"
+	if limit > 0 {
+		uniqArray = fmt.Sprintf("groupUniqArray(%d)", limit)
+	}
+
+	query := `
+		SELECT price_id, property, arraySort(` + uniqArray + `(JSONExtractString(properties, property)))
+		FROM ` + table + ` FINAL
+		ARRAY JOIN ? AS property
+		WHERE tenant_id = ?
+		AND environment_id = ?
+		AND customer_id = ?
+		AND timestamp >= ?
+		AND timestamp < ?
+		AND sign != 0
+		AND JSONHas(properties, property)
+	`
+	args := []interface{}{
+		properties,
+		params.TenantID,
+		params.EnvironmentID,
+		params.CustomerID,
+		params.StartTime,
+		params.EndTime,
+	}
+
+	if len(params.FeatureIDs) > 0 {
+		placeholders := make([]string, len(params.FeatureIDs))
+		for i := range params.FeatureIDs {
+			placeholders[i] = "?"
+			args = append(args, params.FeatureIDs[i])
+		}
+		query += " AND feature_id IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	if len(params.Sources) > 0 {
+		placeholders := make([]string, len(params.Sources))
+		for i := range params.Sources {
+			placeholders[i] = "?"
+			args = append(args, params.Sources[i])
+		}
+		query += " AND source IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	if len(params.PriceIDs) > 0 {
+		placeholders := make([]string, len(params.PriceIDs))
+		for i := range params.PriceIDs {
+			placeholders[i] = "?"
+			args = append(args, params.PriceIDs[i])
+		}
+		query += " AND price_id IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	// Only count the usage billed in the billable windows
+	billableQuery, billableArgs := billableWindowsCondition(params)
+	query += billableQuery
+	args = append(args, billableArgs...)
+
+	for property, propertyValues := range params.PropertyFilters {
+		if len(propertyValues) == 0 {
+			continue
+		}
+		placeholders := make([]string, len(propertyValues))
+		for i := range propertyValues {
+			placeholders[i] = "?"
+		}
+		query += " AND JSONExtractString(properties, ?) IN (" + strings.Join(placeholders, ",") + ")"
+		args = append(args, property)
+		for _, v := range propertyValues {
+			args = append(args, v)
+		}
+	}
+
+	query += " GROUP BY price_id, property"
+
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to fetch property values for analytics").
+			WithReportableDetails(map[string]interface{}{
+				"customer_id": params.CustomerID,
+			}).
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var priceID, property string
+		var propertyValues []string
+		if err := rows.Scan(&priceID, &property, &propertyValues); err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to scan property values row").
+				Mark(ierr.ErrDatabase)
+		}
+		if values[priceID] == nil {
+			values[priceID] = make(map[string][]string)
+		}
+		values[priceID][property] = propertyValues
+	}
+
+	if err := rows.Err(); err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Error iterating property values rows").
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return values, nil
+}
+
+// billableWindowsCondition returns the condition limiting usage to the billable windows of its
+// line items with its args, nothing unless only billable usage is requested
+func billableWindowsCondition(params *events.UsageAnalyticsParams) (string, []interface{}) {
+	if !params.BillableOnly {
+		return "", nil
+	}
+	if len(params.BillableWindows) == 0 {
+		return " AND 0", nil
+	}
+
+	conditions := make([]string, 0, len(params.BillableWindows))
+	args := make([]interface{}, 0, 3*len(params.BillableWindows))
+	for _, window := range params.BillableWindows {
+		if window.EndTime.IsZero() {
+			conditions = append(conditions, "(sub_line_item_id = ? AND timestamp >= ?)")
+			args = append(args, window.SubLineItemID, window.StartTime)
+			continue
+		}
+		conditions = append(conditions, "(sub_line_item_id = ? AND timestamp >= ? AND timestamp < ?)")
+		args = append(args, window.SubLineItemID, window.StartTime, window.EndTime)
+	}
+	return " AND (" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// GetUsageSubLineItemIDs returns the distinct subscription line items with usage in the window
+func (r *FeatureUsageRepository) GetUsageSubLineItemIDs(ctx context.Context, startTime, endTime time.Time) ([]string, error) {
+	table := r.store.FeatureUsageTable(types.GetTenantID(ctx))
+	span := StartRepositorySpan(ctx, "feature_usage", "get_usage_sub_line_item_ids", map[string]interface{}{
+		"start_time": startTime,
+		"end_time":   endTime,
+	})
+	defer FinishSpan(span)
+
+	// cancelled rows may still be merged away, so this can include line items without usage,
+	// which is harmless since the usage itself is read with FINAL
+	query := `
+		SELECT DISTINCT sub_line_item_id
+		FROM ` + table + `
+		WHERE tenant_id = ?
+		AND environment_id = ?
+		AND timestamp >= ?
+		AND timestamp < ?
+		AND sign != 0
+		AND sub_line_item_id != ''
+	`
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query,
+		types.GetTenantID(ctx),
+		types.GetEnvironmentID(ctx),
+		startTime,
+		endTime,
+	)
+	if err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to get the subscription line items with usage").
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	var subLineItemIDs []string
+	for rows.Next() {
+		var subLineItemID string
+		if err := rows.Scan(&subLineItemID); err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to scan subscription line item id").
+				Mark(ierr.ErrDatabase)
+		}
+		subLineItemIDs = append(subLineItemIDs, subLineItemID)
+	}
+
+	SetSpanSuccess(span)
+	return subLineItemIDs, nil
+}
+
+// FindOrphanedUsage returns feature usage in the window attributed to the given subscription
+// line items, the ones that no longer exist, e.g. after a line item was deleted
+func (r *FeatureUsageRepository) FindOrphanedUsage(ctx context.Context, params *events.FindOrphanedUsageParams) ([]*events.FeatureUsage, error) {
+	table := r.store.FeatureUsageTable(types.GetTenantID(ctx))
+	span := StartRepositorySpan(ctx, "feature_usage", "find_orphaned_usage", map[string]interface{}{
+		"start_time":              params.StartTime,
+		"end_time":                params.EndTime,
+		"sub_line_item_ids_count": len(params.SubLineItemIDs),
+	})
+	defer FinishSpan(span)
+
+	query := `
+		SELECT
+			id, tenant_id, external_customer_id, customer_id, event_name, source,
+			timestamp, ingested_at, properties, processed_at, environment_id,
+			subscription_id, sub_line_item_id, price_id, meter_id, feature_id, period_id,
+			unique_hash, qty_total, version, sign, processing_lag_ms, correlation_id
+		FROM ` + table + ` FINAL
+		WHERE tenant_id = ?
+		AND environment_id = ?
+		AND timestamp >= ?
+		AND timestamp < ?
+		AND sign != 0
+		AND sub_line_item_id != ''
+	`
+	args := []interface{}{
+		types.GetTenantID(ctx),
+		types.GetEnvironmentID(ctx),
+		params.StartTime,
+		params.EndTime,
+	}
+
+	if len(params.SubLineItemIDs) == 0 {
+		return nil, nil
+	}
+	placeholders := make([]string, len(params.SubLineItemIDs))
+	for i := range params.SubLineItemIDs {
+		placeholders[i] = "?"
+		args = append(args, params.SubLineItemIDs[i])
+	}
+	query += " AND sub_line_item_id IN (" + strings.Join(placeholders, ", ") + ")"
+
+	query += " ORDER BY timestamp"
+	if params.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, params.Limit)
+	}
+
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to find orphaned feature usage").
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	var records []*events.FeatureUsage
+	for rows.Next() {
+		var record events.FeatureUsage
+		var propertiesJSON string
+
+		err := rows.Scan(
+			&record.ID,
+			&record.TenantID,
+			&record.ExternalCustomerID,
+			&record.CustomerID,
+			&record.EventName,
+			&record.Source,
+			&record.Timestamp,
+			&record.IngestedAt,
+			&propertiesJSON,
+			&record.ProcessedAt,
+			&record.EnvironmentID,
+			&record.SubscriptionID,
+			&record.SubLineItemID,
+			&record.PriceID,
+			&record.MeterID,
+			&record.FeatureID,
+			&record.PeriodID,
+			&record.UniqueHash,
+			&record.QtyTotal,
+			&record.Version,
+			&record.Sign,
+			&record.ProcessingLagMs,
+			&record.CorrelationID,
+		)
+		if err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to scan orphaned feature usage").
+				Mark(ierr.ErrDatabase)
+		}
+
+		if propertiesJSON != "" {
+			if err := json.Unmarshal([]byte(propertiesJSON), &record.Properties); err != nil {
+				SetSpanError(span, err)
+				return nil, ierr.WithError(err).
+					WithHint("Failed to unmarshal properties").
+					Mark(ierr.ErrValidation)
+			}
+		} else {
+			record.Properties = make(map[string]interface{})
+		}
+
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Error iterating orphaned feature usage rows").
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return records, nil
+}
+
+// FindUsageWithoutCustomer returns feature usage in the window that has no customer id, ordered
+// by timestamp so batches can be paged with an offset
+func (r *FeatureUsageRepository) FindUsageWithoutCustomer(ctx context.Context, params *events.FindUsageWithoutCustomerParams) ([]*events.FeatureUsage, error) {
+	table := r.store.FeatureUsageTable(types.GetTenantID(ctx))
+	span := StartRepositorySpan(ctx, "feature_usage", "find_usage_without_customer", map[string]interface{}{
+		"start_time": params.StartTime,
+		"end_time":   params.EndTime,
+	})
+	defer FinishSpan(span)
+
+	query := `
+		SELECT
+			id, tenant_id, external_customer_id, customer_id, event_name, source,
+			timestamp, ingested_at, properties, processed_at, environment_id,
+			subscription_id, sub_line_item_id, price_id, meter_id, feature_id, period_id,
+			unique_hash, qty_total, version, sign, processing_lag_ms, correlation_id
+		FROM ` + table + ` FINAL
+		WHERE tenant_id = ?
+		AND environment_id = ?
+		AND timestamp >= ?
+		AND timestamp < ?
+		AND sign != 0
+		AND customer_id = ''
+	`
+	args := []interface{}{
+		types.GetTenantID(ctx),
+		types.GetEnvironmentID(ctx),
+		params.StartTime,
+		params.EndTime,
+	}
+
+	query += " ORDER BY timestamp, id"
+	if params.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, params.Limit, params.Offset)
+	}
+
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to find feature usage without customer").
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	var records []*events.FeatureUsage
+	for rows.Next() {
+		var record events.FeatureUsage
+		var propertiesJSON string
+
+		err := rows.Scan(
+			&record.ID,
+			&record.TenantID,
+			&record.ExternalCustomerID,
+			&record.CustomerID,
+			&record.EventName,
+			&record.Source,
+			&record.Timestamp,
+			&record.IngestedAt,
+			&propertiesJSON,
+			&record.ProcessedAt,
+			&record.EnvironmentID,
+			&record.SubscriptionID,
+			&record.SubLineItemID,
+			&record.PriceID,
+			&record.MeterID,
+			&record.FeatureID,
+			&record.PeriodID,
+			&record.UniqueHash,
+			&record.QtyTotal,
+			&record.Version,
+			&record.Sign,
+			&record.ProcessingLagMs,
+			&record.CorrelationID,
+		)
+		if err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to scan feature usage without customer").
+				Mark(ierr.ErrDatabase)
+		}
+
+		if propertiesJSON != "" {
+			if err := json.Unmarshal([]byte(propertiesJSON), &record.Properties); err != nil {
+				SetSpanError(span, err)
+				return nil, ierr.WithError(err).
+					WithHint("Failed to unmarshal properties").
+					Mark(ierr.ErrValidation)
+			}
+		} else {
+			record.Properties = make(map[string]interface{})
+		}
+
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Error iterating feature usage without customer rows").
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return records, nil
+}
+
+// GetPlatformUsageTotals returns usage totals per meter in the window across all tenants and
+// environments. It is meant for capacity planning and is deliberately not scoped by the tenant
+// and environment of the context.
+func (r *FeatureUsageRepository) GetPlatformUsageTotals(ctx context.Context, params *events.PlatformUsageParams) ([]*events.PlatformMeterUsage, error) {
+	span := StartRepositorySpan(ctx, "feature_usage", "get_platform_usage_totals", map[string]interface{}{
+		"start_time": params.StartTime,
+		"end_time":   params.EndTime,
+	})
+	defer FinishSpan(span)
+
+	// The usage of every tenant spans the shared table and the dedicated tables, each read with
+	// FINAL so a replaced row isn't counted with its replacement
+	tables := r.store.FeatureUsageTables()
+	table := tables[0] + " FINAL"
+	if len(tables) > 1 {
+		table = "(" + strings.Join(lo.Map(tables, func(table string, _ int) string {
+			return "SELECT * FROM " + table + " FINAL"
+		}), " UNION ALL ") + ")"
+	}
+
+	query := `
+		SELECT
+			tenant_id,
+			environment_id,
+			meter_id,
+			sum(qty_total * sign)       AS total_usage,
+			count(DISTINCT id)          AS event_count,
+			count(DISTINCT customer_id) AS customer_count
+		FROM ` + table + `
+		WHERE "timestamp" >= ?
+			AND "timestamp" < ?
+			AND sign != 0
+		GROUP BY tenant_id, environment_id, meter_id
+		ORDER BY tenant_id, environment_id, meter_id
+	`
+
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, params.StartTime, params.EndTime)
+	if err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to get platform usage totals").
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	totals := make([]*events.PlatformMeterUsage, 0)
+	for rows.Next() {
+		var total events.PlatformMeterUsage
+		if err := rows.Scan(
+			&total.TenantID,
+			&total.EnvironmentID,
+			&total.MeterID,
+			&total.TotalUsage,
+			&total.EventCount,
+			&total.CustomerCount,
+		); err != nil {
+			SetSpanError(span, err)
+			return nil, ierr.WithError(err).
+				WithHint("Failed to scan platform usage totals").
+				Mark(ierr.ErrDatabase)
+		}
+		totals = append(totals, &total)
+	}
+
+	if err := rows.Err(); err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Error iterating platform usage total rows").
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return totals, nil
+}
@@ -0,0 +1,231 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/flexprice/flexprice/internal/clickhouse"
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errQueryRecorded = errors.New("query recorded")
+
+// recordingConn records the statements sent to ClickHouse and fails them so nothing is scanned
+type recordingConn struct {
+	driver.Conn
+	queries []string
+	args    [][]any
+}
+
+func (c *recordingConn) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	c.queries = append(c.queries, query)
+	c.args = append(c.args, args)
+	return nil, errQueryRecorded
+}
+
+func (c *recordingConn) PrepareBatch(ctx context.Context, query string, options ...driver.PrepareBatchOption) (driver.Batch, error) {
+	c.queries = append(c.queries, query)
+	return nil, errQueryRecorded
+}
+
+// tables returns the feature usage tables the recorded statements read or write
+func (c *recordingConn) tables() []string {
+	tables := make([]string, 0, len(c.queries))
+	for _, query := range c.queries {
+		fields := strings.Fields(query)
+		for i, field := range fields {
+			if (field == "FROM" || field == "INTO") && i+1 < len(fields) && strings.Contains(fields[i+1], "feature_usage") {
+				tables = append(tables, fields[i+1])
+			}
+		}
+	}
+	return tables
+}
+
+func newRecordingFeatureUsageRepository(t *testing.T) (*FeatureUsageRepository, *recordingConn) {
+	conn := &recordingConn{}
+	store, err := clickhouse.NewClickHouseStoreWithConn(conn, []config.ClickHouseTenantTableConfig{
+		{TenantID: "tenant_large", FeatureUsageTable: "tenant_large.feature_usage"},
+	})
+	require.NoError(t, err)
+	log, err := logger.NewLogger(config.GetDefaultConfig())
+	require.NoError(t, err)
+	return NewFeatureUsageRepository(store, log).(*FeatureUsageRepository), conn
+}
+
+func TestFeatureUsageTableRouting(t *testing.T) {
+	tenantCtx := func(tenantID string) context.Context {
+		ctx := context.WithValue(context.Background(), types.CtxTenantID, tenantID)
+		return context.WithValue(ctx, types.CtxEnvironmentID, "env_1")
+	}
+
+	tests := []struct {
+		name     string
+		tenantID string
+		want     string
+	}{
+		{name: "dedicated table", tenantID: "tenant_large", want: "tenant_large.feature_usage"},
+		{name: "shared table", tenantID: "tenant_small", want: "feature_usage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, conn := newRecordingFeatureUsageRepository(t)
+			ctx := tenantCtx(tt.tenantID)
+			params := &events.UsageAnalyticsParams{
+				TenantID:      tt.tenantID,
+				EnvironmentID: "env_1",
+				CustomerID:    "cust_1",
+				StartTime:     time.Now().Add(-time.Hour),
+				EndTime:       time.Now(),
+			}
+
+			assert.Error(t, repo.BulkInsertProcessedEvents(ctx, []*events.FeatureUsage{{Event: events.Event{ID: "evt_1", TenantID: tt.tenantID}}}))
+			_, err := repo.GetProcessedEventIDs(ctx, []string{"evt_1"})
+			assert.Error(t, err)
+			_, err = repo.GetPropertyValuesByPrice(ctx, params, []string{"region"}, 0)
+			assert.Error(t, err)
+			_, err = repo.GetEventSampleIDs(ctx, params, 5)
+			assert.Error(t, err)
+
+			assert.Equal(t, []string{tt.want, tt.want, tt.want, tt.want}, conn.tables())
+		})
+	}
+
+	t.Run("inserts are split per tenant table", func(t *testing.T) {
+		repo, conn := newRecordingFeatureUsageRepository(t)
+
+		// The first table fails the insert, the events of the second are never sent
+		assert.Error(t, repo.BulkInsertProcessedEvents(tenantCtx("tenant_small"), []*events.FeatureUsage{
+			{Event: events.Event{ID: "evt_1", TenantID: "tenant_small"}},
+			{Event: events.Event{ID: "evt_2", TenantID: "tenant_large"}},
+		}))
+		assert.Equal(t, []string{"feature_usage"}, conn.tables())
+	})
+
+	t.Run("platform totals span every table", func(t *testing.T) {
+		repo, conn := newRecordingFeatureUsageRepository(t)
+
+		_, err := repo.GetPlatformUsageTotals(context.Background(), &events.PlatformUsageParams{
+			StartTime: time.Now().Add(-time.Hour),
+			EndTime:   time.Now(),
+		})
+		assert.Error(t, err)
+		require.Len(t, conn.queries, 1)
+		assert.Contains(t, conn.queries[0], "(SELECT * FROM feature_usage FINAL UNION ALL SELECT * FROM tenant_large.feature_usage FINAL)")
+	})
+}
+
+func TestGetDetailedUsageAnalyticsWeightProperty(t *testing.T) {
+	params := &events.UsageAnalyticsParams{
+		TenantID:      "tenant_small",
+		EnvironmentID: "env_1",
+		CustomerID:    "cust_1",
+		StartTime:     time.Now().Add(-time.Hour),
+		EndTime:       time.Now(),
+	}
+
+	t.Run("unweighted", func(t *testing.T) {
+		repo, conn := newRecordingFeatureUsageRepository(t)
+		_, err := repo.GetDetailedUsageAnalytics(context.Background(), params, nil)
+		assert.Error(t, err)
+		require.Len(t, conn.queries, 1)
+		assert.NotContains(t, conn.queries[0], "weighted_usage")
+	})
+
+	t.Run("weighted in the aggregate", func(t *testing.T) {
+		repo, conn := newRecordingFeatureUsageRepository(t)
+		weighted := *params
+		weighted.WeightProperty = "request_size"
+
+		_, err := repo.GetDetailedUsageAnalytics(context.Background(), &weighted, nil)
+		assert.Error(t, err)
+		require.Len(t, conn.queries, 1)
+		assert.Contains(t, conn.queries[0], "SUM(qty_total * sign * toDecimal64(JSONExtractFloat(properties, ?), 6)) AS weighted_usage")
+		// The weight property binds the placeholder of the select, before the filters
+		assert.Equal(t, []any{"request_size", "tenant_small", "env_1", "cust_1"}, conn.args[0][:4])
+	})
+}
+
+func TestGetProcessedEventsQuery(t *testing.T) {
+	repo, conn := newRecordingFeatureUsageRepository(t)
+	ctx := context.WithValue(context.Background(), types.CtxTenantID, "tenant_small")
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, "env_1")
+
+	_, _, err := repo.GetProcessedEvents(ctx, &events.GetProcessedEventsParams{
+		StartTime:      time.Now().Add(-time.Hour),
+		EndTime:        time.Now(),
+		SubscriptionID: "sub_1",
+		Limit:          100,
+		Offset:         200,
+	})
+	assert.Error(t, err)
+	require.Len(t, conn.queries, 1)
+
+	query := strings.Join(strings.Fields(conn.queries[0]), " ")
+	// FINAL belongs to the table, the cancelled rows are skipped and the pages follow a stable order
+	assert.Contains(t, query, "FROM feature_usage FINAL WHERE tenant_id = ?")
+	assert.Contains(t, query, "AND sign != 0")
+	assert.True(t, strings.HasSuffix(query, "ORDER BY timestamp, id, sub_line_item_id LIMIT ? OFFSET ?"), query)
+	assert.Equal(t, []any{100, 200}, conn.args[0][len(conn.args[0])-2:])
+}
+
+func TestUsageReadsCollapseCancelledRows(t *testing.T) {
+	ctx := context.WithValue(context.Background(), types.CtxTenantID, "tenant_small")
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, "env_1")
+	params := &events.UsageAnalyticsParams{
+		TenantID:      "tenant_small",
+		EnvironmentID: "env_1",
+		CustomerID:    "cust_1",
+		StartTime:     time.Now().Add(-time.Hour),
+		EndTime:       time.Now(),
+	}
+
+	// A replaced row and its replacement have different sorting keys, the cancelled row is only
+	// dropped by reading with FINAL until the parts are merged
+	tests := []struct {
+		name string
+		read func(repo *FeatureUsageRepository) error
+	}{
+		{name: "analytics", read: func(repo *FeatureUsageRepository) error {
+			_, err := repo.GetDetailedUsageAnalytics(ctx, params, nil)
+			return err
+		}},
+		{name: "usage by subscription", read: func(repo *FeatureUsageRepository) error {
+			_, err := repo.GetFeatureUsageBySubscription(ctx, "sub_1", "ext_cust_1", params.StartTime, params.EndTime)
+			return err
+		}},
+		{name: "event samples", read: func(repo *FeatureUsageRepository) error {
+			_, err := repo.GetEventSampleIDs(ctx, params, 5)
+			return err
+		}},
+		{name: "property values", read: func(repo *FeatureUsageRepository) error {
+			_, err := repo.GetPropertyValuesByPrice(ctx, params, []string{"region"}, 0)
+			return err
+		}},
+		{name: "export", read: func(repo *FeatureUsageRepository) error {
+			_, err := repo.GetFeatureUsageForExport(ctx, params.StartTime, params.EndTime, 100, 0)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, conn := newRecordingFeatureUsageRepository(t)
+			assert.Error(t, tt.read(repo))
+			require.NotEmpty(t, conn.queries)
+			for _, query := range conn.queries {
+				assert.Contains(t, strings.Join(strings.Fields(query), " "), "FROM feature_usage FINAL")
+			}
+		})
+	}
+}
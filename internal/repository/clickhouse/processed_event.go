@@ -488,7 +488,7 @@ func (r *ProcessedEventRepository) GetUsageAnalytics(ctx context.Context, tenant
 		LIMIT 100
 	`
 
-	rows, err := r.store.GetConn().Query(ctx, query, tenantID, environmentID, customerID, lookbackHours)
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, tenantID, environmentID, customerID, lookbackHours)
 	if err != nil {
 		return nil, ierr.WithError(err).
 			WithHint("Failed to query usage analytics").
@@ -517,6 +517,7 @@ func (r *ProcessedEventRepository) GetDetailedUsageAnalytics(ctx context.Context
 		"external_customer_id":   params.ExternalCustomerID,
 		"feature_ids_count":      len(params.FeatureIDs),
 		"sources_count":          len(params.Sources),
+		"price_ids_count":        len(params.PriceIDs),
 		"property_filters_count": len(params.PropertyFilters),
 	})
 	defer FinishSpan(span)
@@ -631,6 +632,16 @@ func (r *ProcessedEventRepository) GetDetailedUsageAnalytics(ctx context.Context
 		aggregateQuery += " AND source IN (" + strings.Join(placeholders, ", ") + ")"
 	}
 
+	// Add filters for price ids
+	if len(params.PriceIDs) > 0 {
+		placeholders := make([]string, len(params.PriceIDs))
+		for i := range params.PriceIDs {
+			placeholders[i] = "?"
+			filterParams = append(filterParams, params.PriceIDs[i])
+		}
+		aggregateQuery += " AND price_id IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
 	// add properties filters
 	if len(params.PropertyFilters) > 0 {
 		for property, values := range params.PropertyFilters {
@@ -670,7 +681,7 @@ func (r *ProcessedEventRepository) GetDetailedUsageAnalytics(ctx context.Context
 	)
 
 	// Execute the query
-	rows, err := r.store.GetConn().Query(ctx, aggregateQuery, queryParams...)
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, aggregateQuery, queryParams...)
 	if err != nil {
 		SetSpanError(span, err)
 		return nil, ierr.WithError(err).
@@ -773,6 +784,12 @@ func (r *ProcessedEventRepository) getAnalyticsPoints(
 	var timeWindowExpr string
 
 	switch params.WindowSize {
+	case types.WindowSizeSecond:
+		timeWindowExpr = "toStartOfInterval(timestamp, INTERVAL 1 SECOND)"
+	case types.WindowSize10Sec:
+		timeWindowExpr = "toStartOfInterval(timestamp, INTERVAL 10 SECOND)"
+	case types.WindowSize30Sec:
+		timeWindowExpr = "toStartOfInterval(timestamp, INTERVAL 30 SECOND)"
 	case types.WindowSizeMinute:
 		timeWindowExpr = "toStartOfMinute(timestamp)"
 	case types.WindowSizeHour:
@@ -900,7 +917,7 @@ func (r *ProcessedEventRepository) getAnalyticsPoints(
 	)
 
 	// Execute the query
-	rows, err := r.store.GetConn().Query(ctx, query, queryParams...)
+	rows, err := r.store.GetReadConn(ctx).Query(ctx, query, queryParams...)
 	if err != nil {
 		return nil, ierr.WithError(err).
 			WithHint("Failed to execute time-series query").
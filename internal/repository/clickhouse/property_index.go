@@ -0,0 +1,202 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/flexprice/flexprice/internal/clickhouse"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/samber/lo"
+)
+
+// The indexed properties of meters get a bloom filter skip index on the feature usage table of
+// their tenant over the extracted property, so the analytics filtering usage by the property skip
+// the granules without the value. The indexes are named with propertyIndexPrefix and owned by the
+// meters, an index whose property no meter indexes anymore is dropped.
+const propertyIndexPrefix = "prop_"
+
+var propertyIndexNameReplacer = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// PropertyIndexName returns the name of the skip index of the property. The property is
+// sanitized into an identifier and suffixed by its hash so properties differing only in case or
+// punctuation get distinct indexes.
+func PropertyIndexName(property string) string {
+	h := fnv.New32a()
+	h.Write([]byte(property))
+	name := propertyIndexNameReplacer.ReplaceAllString(strings.ToLower(property), "_")
+	return fmt.Sprintf("%s%s_%08x", propertyIndexPrefix, strings.Trim(name, "_"), h.Sum32())
+}
+
+// AddPropertyIndexDDL returns the statements adding the skip index of the property to the feature
+// usage table and building it for the existing parts, new parts are indexed on insert
+func AddPropertyIndexDDL(table, property string) []string {
+	name := PropertyIndexName(property)
+	return []string{
+		fmt.Sprintf("ALTER TABLE %s ADD INDEX IF NOT EXISTS %s JSONExtractString(properties, '%s') TYPE bloom_filter(0.01) GRANULARITY 128",
+			table, name, strings.ReplaceAll(property, "'", "\\'")),
+		fmt.Sprintf("ALTER TABLE %s MATERIALIZE INDEX %s", table, name),
+	}
+}
+
+// DropPropertyIndexDDL returns the statement dropping the property skip index of the feature
+// usage table
+func DropPropertyIndexDDL(table, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP INDEX IF EXISTS %s", table, name)
+}
+
+// PropertyIndexPlan is the changes bringing the property indexes of a feature usage table in line
+// with the indexed properties of the meters of its tenants
+type PropertyIndexPlan struct {
+	// Table is the feature usage table the plan applies to
+	Table string
+	// Add are the properties without an index
+	Add []string
+	// Drop are the names of the indexes no meter property maps to
+	Drop []string
+	// Skipped are the indexed properties left without an index by the cap on the indexes
+	Skipped []string
+	// Statements are the DDL statements applying the plan, in order
+	Statements []string
+}
+
+// IsEmpty returns true when the indexes are up to date
+func (p *PropertyIndexPlan) IsEmpty() bool {
+	return len(p.Statements) == 0
+}
+
+// PlanPropertyIndexes compares the indexed properties, one entry per meter indexing it, with the
+// names of the existing property indexes. At most maxIndexes properties are indexed, the ones
+// indexed by the most meters, so tenants can't grow the indexes of a shared table without bound.
+// The plan is sorted so it is stable across runs.
+func PlanPropertyIndexes(table string, properties []string, existing []string, maxIndexes int) *PropertyIndexPlan {
+	plan := &PropertyIndexPlan{
+		Table:      table,
+		Add:        make([]string, 0),
+		Drop:       make([]string, 0),
+		Skipped:    make([]string, 0),
+		Statements: make([]string, 0),
+	}
+
+	meters := lo.CountValues(properties)
+	ranked := lo.Keys(meters)
+	sort.Slice(ranked, func(i, j int) bool {
+		if meters[ranked[i]] != meters[ranked[j]] {
+			return meters[ranked[i]] > meters[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	if maxIndexes < 0 {
+		maxIndexes = 0
+	}
+	if len(ranked) > maxIndexes {
+		plan.Skipped = append(plan.Skipped, ranked[maxIndexes:]...)
+		ranked = ranked[:maxIndexes]
+	}
+
+	wanted := make(map[string]bool, len(ranked))
+	for _, property := range ranked {
+		name := PropertyIndexName(property)
+		wanted[name] = true
+		if !lo.Contains(existing, name) {
+			plan.Add = append(plan.Add, property)
+		}
+	}
+	for _, name := range existing {
+		if strings.HasPrefix(name, propertyIndexPrefix) && !wanted[name] {
+			plan.Drop = append(plan.Drop, name)
+		}
+	}
+	sort.Strings(plan.Add)
+	sort.Strings(plan.Drop)
+	sort.Strings(plan.Skipped)
+
+	for _, property := range plan.Add {
+		plan.Statements = append(plan.Statements, AddPropertyIndexDDL(table, property)...)
+	}
+	for _, name := range plan.Drop {
+		plan.Statements = append(plan.Statements, DropPropertyIndexDDL(table, name))
+	}
+	return plan
+}
+
+// PropertyIndexRepository reads and applies the property skip indexes of the feature usage tables
+type PropertyIndexRepository struct {
+	store  *clickhouse.ClickHouseStore
+	logger *logger.Logger
+}
+
+func NewPropertyIndexRepository(store *clickhouse.ClickHouseStore, logger *logger.Logger) *PropertyIndexRepository {
+	return &PropertyIndexRepository{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// FeatureUsageTables returns the shared feature usage table and the dedicated tables of tenants
+func (r *PropertyIndexRepository) FeatureUsageTables() []string {
+	return r.store.FeatureUsageTables()
+}
+
+// FeatureUsageTable returns the feature usage table the usage of the tenant is written to
+func (r *PropertyIndexRepository) FeatureUsageTable(tenantID string) string {
+	return r.store.FeatureUsageTable(tenantID)
+}
+
+// ListIndexes returns the names of the property indexes of the feature usage table, a table not
+// qualified with its database is in the current one
+func (r *PropertyIndexRepository) ListIndexes(ctx context.Context, table string) ([]string, error) {
+	query := `
+		SELECT name
+		FROM system.data_skipping_indices
+		WHERE database = if(? = '', currentDatabase(), ?) AND table = ? AND startsWith(name, ?)
+	`
+
+	database, name := "", table
+	if i := strings.Index(table, "."); i >= 0 {
+		database, name = table[:i], table[i+1:]
+	}
+
+	rows, err := r.store.GetConn().Query(ctx, query, database, database, name, propertyIndexPrefix)
+	if err != nil {
+		return nil, ierr.WithError(err).
+			WithHint("Failed to list property indexes").
+			WithReportableDetails(map[string]interface{}{
+				"table": table,
+			}).
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, ierr.WithError(err).
+				WithHint("Failed to scan property index").
+				Mark(ierr.ErrDatabase)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Apply runs the statements of the plan in order
+func (r *PropertyIndexRepository) Apply(ctx context.Context, plan *PropertyIndexPlan) error {
+	for _, statement := range plan.Statements {
+		r.logger.Infow("applying property index statement", "statement", statement)
+		if err := r.store.GetConn().Exec(ctx, statement); err != nil {
+			return ierr.WithError(err).
+				WithHint("Failed to apply property index").
+				WithReportableDetails(map[string]interface{}{
+					"statement": statement,
+				}).
+				Mark(ierr.ErrDatabase)
+		}
+	}
+	return nil
+}
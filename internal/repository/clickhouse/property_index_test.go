@@ -0,0 +1,71 @@
+package clickhouse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyIndexName(t *testing.T) {
+	name := PropertyIndexName("model.name")
+	assert.True(t, strings.HasPrefix(name, "prop_model_name_"), name)
+	assert.Equal(t, name, PropertyIndexName("model.name"))
+
+	// Properties sanitized into the same identifier still get distinct indexes
+	assert.NotEqual(t, name, PropertyIndexName("model_name"))
+	assert.NotEqual(t, PropertyIndexName("Region"), PropertyIndexName("region"))
+}
+
+func TestAddPropertyIndexDDL(t *testing.T) {
+	name := PropertyIndexName("region")
+	statements := AddPropertyIndexDDL("feature_usage", "region")
+	require.Len(t, statements, 2)
+	assert.Equal(t, "ALTER TABLE feature_usage ADD INDEX IF NOT EXISTS "+name+" JSONExtractString(properties, 'region') TYPE bloom_filter(0.01) GRANULARITY 128", statements[0])
+	assert.Equal(t, "ALTER TABLE feature_usage MATERIALIZE INDEX "+name, statements[1])
+
+	// The dedicated table of a tenant is indexed on its own
+	statements = AddPropertyIndexDDL("tenant_1.feature_usage", "region")
+	require.Len(t, statements, 2)
+	assert.Equal(t, "ALTER TABLE tenant_1.feature_usage MATERIALIZE INDEX "+name, statements[1])
+	assert.Equal(t, "ALTER TABLE tenant_1.feature_usage DROP INDEX IF EXISTS "+name, DropPropertyIndexDDL("tenant_1.feature_usage", name))
+}
+
+func TestPlanPropertyIndexes(t *testing.T) {
+	region := PropertyIndexName("region")
+	model := PropertyIndexName("model")
+
+	t.Run("adds the configured properties", func(t *testing.T) {
+		plan := PlanPropertyIndexes("feature_usage", []string{"region", "model", "region"}, nil, 16)
+		assert.Equal(t, []string{"model", "region"}, plan.Add)
+		assert.Empty(t, plan.Drop)
+		assert.Equal(t, append(AddPropertyIndexDDL("feature_usage", "model"), AddPropertyIndexDDL("feature_usage", "region")...), plan.Statements)
+	})
+
+	t.Run("drops the indexes of properties no longer configured", func(t *testing.T) {
+		plan := PlanPropertyIndexes("feature_usage", []string{"region"}, []string{region, model, "bf_price"}, 16)
+		assert.Empty(t, plan.Add)
+		assert.Equal(t, []string{model}, plan.Drop)
+		assert.Equal(t, []string{"ALTER TABLE feature_usage DROP INDEX IF EXISTS " + model}, plan.Statements)
+	})
+
+	t.Run("caps the indexes at the properties indexed by the most meters", func(t *testing.T) {
+		plan := PlanPropertyIndexes("feature_usage", []string{"region", "model", "region", "tier", "model", "region"}, []string{PropertyIndexName("tier")}, 2)
+		assert.Equal(t, []string{"model", "region"}, plan.Add)
+		assert.Equal(t, []string{PropertyIndexName("tier")}, plan.Drop)
+		assert.Equal(t, []string{"tier"}, plan.Skipped)
+	})
+
+	t.Run("no indexes without a cap", func(t *testing.T) {
+		plan := PlanPropertyIndexes("feature_usage", []string{"region"}, []string{region}, 0)
+		assert.Empty(t, plan.Add)
+		assert.Equal(t, []string{region}, plan.Drop)
+		assert.Equal(t, []string{"region"}, plan.Skipped)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		plan := PlanPropertyIndexes("feature_usage", []string{"region", "model"}, []string{model, region}, 16)
+		assert.True(t, plan.IsEmpty())
+	})
+}
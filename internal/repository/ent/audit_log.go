@@ -0,0 +1,107 @@
+package ent
+
+import (
+	"context"
+
+	"github.com/flexprice/flexprice/ent"
+	entAuditLog "github.com/flexprice/flexprice/ent/auditlog"
+	"github.com/flexprice/flexprice/internal/domain/auditlog"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/postgres"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// AuditLogRepository implements the audit log repository on the audit_logs table
+type AuditLogRepository struct {
+	client postgres.IClient
+	logger *logger.Logger
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(client postgres.IClient, logger *logger.Logger) auditlog.Repository {
+	return &AuditLogRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Create stores the audit log
+func (r *AuditLogRepository) Create(ctx context.Context, auditLog *auditlog.AuditLog) error {
+	span := StartRepositorySpan(ctx, "audit_log", "create", map[string]interface{}{
+		"entity_type": auditLog.EntityType,
+		"entity_id":   auditLog.EntityID,
+		"action":      auditLog.Action,
+	})
+	defer FinishSpan(span)
+
+	changes := auditLog.Changes
+	if changes == nil {
+		changes = make(map[string]types.AuditLogChange)
+	}
+
+	createQuery := r.client.Writer(ctx).AuditLog.Create().
+		SetID(auditLog.ID).
+		SetTenantID(auditLog.TenantID).
+		SetEnvironmentID(auditLog.EnvironmentID).
+		SetEntityType(string(auditLog.EntityType)).
+		SetEntityID(auditLog.EntityID).
+		SetAction(string(auditLog.Action)).
+		SetActor(auditLog.Actor).
+		SetChanges(changes).
+		SetCreatedAt(auditLog.CreatedAt)
+
+	// A missing entity is stored as null, not as an empty object
+	if auditLog.Before != nil {
+		createQuery = createQuery.SetBefore(auditLog.Before)
+	}
+	if auditLog.After != nil {
+		createQuery = createQuery.SetAfter(auditLog.After)
+	}
+
+	if _, err := createQuery.Save(ctx); err != nil {
+		SetSpanError(span, err)
+		return ierr.WithError(err).
+			WithHint("Failed to create audit log").
+			WithReportableDetails(map[string]interface{}{
+				"entity_type": auditLog.EntityType,
+				"entity_id":   auditLog.EntityID,
+			}).
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return nil
+}
+
+// ListByEntity returns the audit logs of an entity, the latest first
+func (r *AuditLogRepository) ListByEntity(ctx context.Context, entityType types.AuditLogEntityType, entityID string, limit int) ([]*auditlog.AuditLog, error) {
+	span := StartRepositorySpan(ctx, "audit_log", "list_by_entity", map[string]interface{}{
+		"entity_type": entityType,
+		"entity_id":   entityID,
+	})
+	defer FinishSpan(span)
+
+	query := r.client.Reader(ctx).AuditLog.Query().
+		Where(
+			entAuditLog.TenantID(types.GetTenantID(ctx)),
+			entAuditLog.EnvironmentID(types.GetEnvironmentID(ctx)),
+			entAuditLog.EntityType(string(entityType)),
+			entAuditLog.EntityID(entityID),
+		).
+		Order(ent.Desc(entAuditLog.FieldCreatedAt))
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	auditLogs, err := query.All(ctx)
+	if err != nil {
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to list audit logs").
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return auditlog.FromEntList(auditLogs), nil
+}
@@ -24,14 +24,17 @@ type customerRepository struct {
 	log       *logger.Logger
 	queryOpts CustomerQueryOptions
 	cache     cache.Cache
+	// lookupKeyConflictPolicy resolves lookups by external id matching several customers
+	lookupKeyConflictPolicy types.CustomerLookupKeyConflictPolicy
 }
 
-func NewCustomerRepository(client postgres.IClient, log *logger.Logger, cache cache.Cache) domainCustomer.Repository {
+func NewCustomerRepository(client postgres.IClient, log *logger.Logger, cache cache.Cache, lookupKeyConflictPolicy types.CustomerLookupKeyConflictPolicy) domainCustomer.Repository {
 	return &customerRepository{
-		client:    client,
-		log:       log,
-		queryOpts: CustomerQueryOptions{},
-		cache:     cache,
+		client:                  client,
+		log:                     log,
+		queryOpts:               CustomerQueryOptions{},
+		cache:                   cache,
+		lookupKeyConflictPolicy: lookupKeyConflictPolicy,
 	}
 }
 
@@ -177,33 +180,43 @@ func (r *customerRepository) GetByLookupKey(ctx context.Context, lookupKey strin
 
 	r.log.Debugw("getting customer by lookup key", "lookup_key", lookupKey)
 
-	c, err := client.Customer.Query().
+	customers, err := client.Customer.Query().
 		Where(
 			customer.ExternalID(lookupKey),
 			customer.TenantID(types.GetTenantID(ctx)),
 			customer.Status(string(types.StatusPublished)),
 			customer.EnvironmentID(types.GetEnvironmentID(ctx)),
 		).
-		Only(ctx)
-
+		All(ctx)
 	if err != nil {
 		SetSpanError(span, err)
-
-		if ent.IsNotFound(err) {
-			return nil, ierr.WithError(err).
-				WithHintf("Customer with lookup key %s was not found", lookupKey).
-				WithReportableDetails(map[string]any{
-					"lookup_key": lookupKey,
-				}).
-				Mark(ierr.ErrNotFound)
-		}
 		return nil, ierr.WithError(err).
 			WithHint("Failed to get customer by lookup key").
 			Mark(ierr.ErrDatabase)
 	}
 
+	matches := domainCustomer.FromEntList(customers)
+	c, err := domainCustomer.ResolveLookupKeyMatches(lookupKey, matches, r.lookupKeyConflictPolicy)
+	if len(matches) > 1 {
+		// External ids are expected to be unique, record every ambiguous lookup however it's resolved
+		picked := ""
+		if c != nil {
+			picked = c.ID
+		}
+		r.log.Warnw("multiple customers found for lookup key",
+			"lookup_key", lookupKey,
+			"matches", len(matches),
+			"policy", r.lookupKeyConflictPolicy,
+			"picked_customer_id", picked,
+		)
+	}
+	if err != nil {
+		SetSpanError(span, err)
+		return nil, err
+	}
+
 	SetSpanSuccess(span)
-	return domainCustomer.FromEnt(c), nil
+	return c, nil
 }
 
 func (r *customerRepository) List(ctx context.Context, filter *types.CustomerFilter) ([]*domainCustomer.Customer, error) {
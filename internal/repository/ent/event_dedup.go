@@ -0,0 +1,127 @@
+package ent
+
+import (
+	"context"
+	"time"
+
+	"github.com/flexprice/flexprice/ent/eventdedupkey"
+	"github.com/flexprice/flexprice/internal/domain/events"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/postgres"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// EventDedupRepository implements the event dedup repository on the event_dedup_keys table
+type EventDedupRepository struct {
+	client postgres.IClient
+	logger *logger.Logger
+}
+
+// NewEventDedupRepository creates a new event dedup repository
+func NewEventDedupRepository(client postgres.IClient, logger *logger.Logger) events.EventDedupRepository {
+	return &EventDedupRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Claim records the event id until expiresAt in a single statement, an expired record is claimed
+// again. No row is returned when the id is recorded and hasn't expired.
+func (r *EventDedupRepository) Claim(ctx context.Context, eventID string, expiresAt time.Time) (bool, error) {
+	span := StartRepositorySpan(ctx, "event_dedup", "claim", map[string]interface{}{
+		"event_id": eventID,
+	})
+	defer FinishSpan(span)
+
+	// Use raw SQL since ent doesn't support a condition on the OnConflict update
+	query := `
+		INSERT INTO event_dedup_keys (tenant_id, environment_id, event_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, environment_id, event_id) DO UPDATE
+		SET expires_at = EXCLUDED.expires_at,
+			created_at = EXCLUDED.created_at
+		WHERE event_dedup_keys.expires_at <= EXCLUDED.created_at
+		RETURNING event_id`
+
+	rows, err := r.client.Writer(ctx).QueryContext(ctx, query,
+		types.GetTenantID(ctx),
+		types.GetEnvironmentID(ctx),
+		eventID,
+		expiresAt.UTC(),
+		time.Now().UTC(),
+	)
+	if err != nil {
+		SetSpanError(span, err)
+		return false, ierr.WithError(err).
+			WithHint("Failed to claim event id").
+			WithReportableDetails(map[string]interface{}{
+				"event_id": eventID,
+			}).
+			Mark(ierr.ErrDatabase)
+	}
+	defer rows.Close()
+
+	claimed := rows.Next()
+	if err := rows.Err(); err != nil {
+		SetSpanError(span, err)
+		return false, ierr.WithError(err).
+			WithHint("Failed to claim event id").
+			WithReportableDetails(map[string]interface{}{
+				"event_id": eventID,
+			}).
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return claimed, nil
+}
+
+// Release removes the record of the event id
+func (r *EventDedupRepository) Release(ctx context.Context, eventID string) error {
+	span := StartRepositorySpan(ctx, "event_dedup", "release", map[string]interface{}{
+		"event_id": eventID,
+	})
+	defer FinishSpan(span)
+
+	_, err := r.client.Writer(ctx).EventDedupKey.Delete().
+		Where(
+			eventdedupkey.TenantID(types.GetTenantID(ctx)),
+			eventdedupkey.EnvironmentID(types.GetEnvironmentID(ctx)),
+			eventdedupkey.EventID(eventID),
+		).
+		Exec(ctx)
+	if err != nil {
+		SetSpanError(span, err)
+		return ierr.WithError(err).
+			WithHint("Failed to release event id").
+			WithReportableDetails(map[string]interface{}{
+				"event_id": eventID,
+			}).
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return nil
+}
+
+// DeleteExpired removes the records of all tenants that expired before the time
+func (r *EventDedupRepository) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	span := StartRepositorySpan(ctx, "event_dedup", "delete_expired", map[string]interface{}{
+		"before": before,
+	})
+	defer FinishSpan(span)
+
+	deleted, err := r.client.Writer(ctx).EventDedupKey.Delete().
+		Where(eventdedupkey.ExpiresAtLT(before.UTC())).
+		Exec(ctx)
+	if err != nil {
+		SetSpanError(span, err)
+		return 0, ierr.WithError(err).
+			WithHint("Failed to delete expired event ids").
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return deleted, nil
+}
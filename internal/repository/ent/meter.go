@@ -12,6 +12,7 @@ import (
 	"github.com/flexprice/flexprice/internal/logger"
 	"github.com/flexprice/flexprice/internal/postgres"
 	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
 )
 
 type meterRepository struct {
@@ -53,6 +54,10 @@ func (r *meterRepository) CreateMeter(ctx context.Context, m *domainMeter.Meter)
 		SetAggregation(m.ToEntAggregation()).
 		SetFilters(m.ToEntFilters()).
 		SetResetUsage(string(m.ResetUsage)).
+		SetSubscriptionStatuses(m.ToEntSubscriptionStatuses()).
+		SetNillableBillingMode(lo.EmptyableToPtr(string(m.BillingMode))).
+		SetNillableEventNameMatch(lo.EmptyableToPtr(string(m.EventNameMatch))).
+		SetIndexedProperties(m.IndexedProperties).
 		SetStatus(string(m.Status)).
 		SetCreatedAt(m.CreatedAt).
 		SetUpdatedAt(m.UpdatedAt).
@@ -98,6 +98,9 @@ func (r *priceRepository) Create(ctx context.Context, p *domainPrice.Price) erro
 	if !p.PriceUnitAmount.IsZero() {
 		priceBuilder.SetPriceUnitAmount(p.PriceUnitAmount.InexactFloat64())
 	}
+	if p.DiscountPercent != nil {
+		priceBuilder.SetDiscountPercent(p.DiscountPercent.InexactFloat64())
+	}
 	if p.DisplayPriceUnitAmount != "" {
 		priceBuilder.SetDisplayPriceUnitAmount(p.DisplayPriceUnitAmount)
 	}
@@ -423,6 +426,10 @@ func (r *priceRepository) CreateBulk(ctx context.Context, prices []*domainPrice.
 			SetCreatedBy(p.CreatedBy).
 			SetUpdatedBy(p.UpdatedBy).
 			SetNillableGroupID(lo.ToPtr(p.GroupID))
+
+		if p.DiscountPercent != nil {
+			builders[i].SetDiscountPercent(p.DiscountPercent.InexactFloat64())
+		}
 	}
 
 	_, err := client.Price.CreateBulk(builders...).Save(ctx)
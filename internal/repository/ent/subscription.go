@@ -89,6 +89,7 @@ func (r *subscriptionRepository) Create(ctx context.Context, sub *domainSub.Subs
 		SetNillableGatewayPaymentMethodID(sub.GatewayPaymentMethodID).
 		SetEnableTrueUp(sub.EnableTrueUp).
 		SetNillableInvoicingCustomerID(sub.InvoicingCustomerID).
+		SetUsageFrozen(sub.UsageFrozen).
 		Save(ctx)
 
 	if err != nil {
@@ -180,6 +181,7 @@ func (r *subscriptionRepository) Update(ctx context.Context, sub *domainSub.Subs
 		SetCollectionMethod(subscription.CollectionMethod(sub.CollectionMethod)).
 		SetNillableGatewayPaymentMethodID(sub.GatewayPaymentMethodID).
 		SetNillableInvoicingCustomerID(sub.InvoicingCustomerID).
+		SetUsageFrozen(sub.UsageFrozen).
 		SetUpdatedAt(now).
 		SetUpdatedBy(types.GetUserID(ctx)).
 		SetNillableEndDate(sub.EndDate).
@@ -636,6 +638,22 @@ func (o *SubscriptionQueryOptions) applyEntityQueryOptions(_ context.Context, f
 		)
 	}
 
+	// Apply active during filter, the subscription has to start before the range ends and
+	// end after it starts
+	if f.ActiveDuring != nil {
+		if f.ActiveDuring.EndTime != nil {
+			query = query.Where(subscription.StartDateLT(*f.ActiveDuring.EndTime))
+		}
+		if f.ActiveDuring.StartTime != nil {
+			query = query.Where(
+				subscription.Or(
+					subscription.EndDateGT(*f.ActiveDuring.StartTime),
+					subscription.EndDateIsNil(),
+				),
+			)
+		}
+	}
+
 	// Apply time range filters
 	if f.TimeRangeFilter != nil {
 		if f.TimeRangeFilter.StartTime != nil {
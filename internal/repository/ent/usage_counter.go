@@ -0,0 +1,177 @@
+package ent
+
+import (
+	"context"
+
+	"github.com/flexprice/flexprice/ent"
+	"github.com/flexprice/flexprice/ent/usagecounter"
+	"github.com/flexprice/flexprice/internal/domain/events"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/postgres"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
+)
+
+// UsageCounterRepository implements the usage counter repository on the usage_counters table
+type UsageCounterRepository struct {
+	client postgres.IClient
+	logger *logger.Logger
+}
+
+// NewUsageCounterRepository creates a new usage counter repository
+func NewUsageCounterRepository(client postgres.IClient, logger *logger.Logger) events.UsageCounterRepository {
+	return &UsageCounterRepository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Add adds each feature usage row to its counter in a single transaction. The row is recorded as
+// a usage counter entry in the same statement, so a row that was already added is skipped.
+func (r *UsageCounterRepository) Add(ctx context.Context, featureUsage []*events.FeatureUsage) error {
+	// Use raw SQL for atomic increment since ent doesn't support expressions in OnConflict updates
+	query := `
+		WITH entry AS (
+			INSERT INTO usage_counter_entries (tenant_id, environment_id, event_id, sub_line_item_id, customer_id, feature_id, period_id, value, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP)
+			ON CONFLICT (tenant_id, environment_id, event_id, sub_line_item_id) DO NOTHING
+			RETURNING tenant_id, environment_id, customer_id, feature_id, period_id, value
+		)
+		INSERT INTO usage_counters (tenant_id, environment_id, customer_id, feature_id, period_id, value, created_at, updated_at)
+		SELECT tenant_id, environment_id, customer_id, feature_id, period_id, value, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+		FROM entry
+		ON CONFLICT (tenant_id, environment_id, customer_id, feature_id, period_id) DO UPDATE
+		SET value = usage_counters.value + EXCLUDED.value,
+			updated_at = CURRENT_TIMESTAMP`
+
+	countable := lo.Filter(featureUsage, func(usage *events.FeatureUsage, _ int) bool {
+		return usage.IsCountable()
+	})
+	return r.exec(ctx, "add", query, countable, true)
+}
+
+// Cancel subtracts each feature usage row from the counter it was added to in a single
+// transaction. The row's entry is deleted in the same statement, so it is subtracted once and
+// from the customer and period it was added under.
+func (r *UsageCounterRepository) Cancel(ctx context.Context, featureUsage []*events.FeatureUsage) error {
+	query := `
+		WITH entry AS (
+			DELETE FROM usage_counter_entries
+			WHERE tenant_id = $1
+				AND environment_id = $2
+				AND event_id = $3
+				AND sub_line_item_id = $4
+			RETURNING tenant_id, environment_id, customer_id, feature_id, period_id, value
+		)
+		UPDATE usage_counters
+		SET value = usage_counters.value - entry.value,
+			updated_at = CURRENT_TIMESTAMP
+		FROM entry
+		WHERE usage_counters.tenant_id = entry.tenant_id
+			AND usage_counters.environment_id = entry.environment_id
+			AND usage_counters.customer_id = entry.customer_id
+			AND usage_counters.feature_id = entry.feature_id
+			AND usage_counters.period_id = entry.period_id`
+
+	return r.exec(ctx, "cancel", query, featureUsage, false)
+}
+
+// exec runs the query for each feature usage row in a single transaction, with the row's key as
+// the first four arguments and, when withValues, its customer, feature, period and value
+func (r *UsageCounterRepository) exec(ctx context.Context, operation, query string, featureUsage []*events.FeatureUsage, withValues bool) error {
+	if len(featureUsage) == 0 {
+		return nil
+	}
+
+	span := StartRepositorySpan(ctx, "usage_counter", operation, map[string]interface{}{
+		"rows": len(featureUsage),
+	})
+	defer FinishSpan(span)
+
+	err := r.client.WithTx(ctx, func(ctx context.Context) error {
+		client := r.client.Writer(ctx)
+		for _, usage := range featureUsage {
+			tenantID := usage.TenantID
+			if tenantID == "" {
+				tenantID = types.GetTenantID(ctx)
+			}
+			environmentID := usage.EnvironmentID
+			if environmentID == "" {
+				environmentID = types.GetEnvironmentID(ctx)
+			}
+
+			args := []interface{}{tenantID, environmentID, usage.ID, usage.SubLineItemID}
+			if withValues {
+				args = append(args,
+					usage.CustomerID,
+					usage.FeatureID,
+					int64(usage.PeriodID),
+					usage.QtyTotal,
+				)
+			}
+
+			if _, err := client.ExecContext(ctx, query, args...); err != nil {
+				return ierr.WithError(err).
+					WithHintf("Failed to %s usage counter", operation).
+					WithReportableDetails(map[string]interface{}{
+						"event_id":         usage.ID,
+						"sub_line_item_id": usage.SubLineItemID,
+						"customer_id":      usage.CustomerID,
+						"feature_id":       usage.FeatureID,
+						"period_id":        usage.PeriodID,
+					}).
+					Mark(ierr.ErrDatabase)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		SetSpanError(span, err)
+		return err
+	}
+
+	SetSpanSuccess(span)
+	return nil
+}
+
+// Get returns the counter of a customer feature period, nil when nothing was counted
+func (r *UsageCounterRepository) Get(ctx context.Context, customerID, featureID string, periodID uint64) (*events.UsageCounter, error) {
+	span := StartRepositorySpan(ctx, "usage_counter", "get", map[string]interface{}{
+		"customer_id": customerID,
+		"feature_id":  featureID,
+		"period_id":   periodID,
+	})
+	defer FinishSpan(span)
+
+	counter, err := r.client.Reader(ctx).UsageCounter.Query().
+		Where(
+			usagecounter.TenantID(types.GetTenantID(ctx)),
+			usagecounter.EnvironmentID(types.GetEnvironmentID(ctx)),
+			usagecounter.CustomerID(customerID),
+			usagecounter.FeatureID(featureID),
+			usagecounter.PeriodID(int64(periodID)),
+		).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			SetSpanSuccess(span)
+			return nil, nil
+		}
+		SetSpanError(span, err)
+		return nil, ierr.WithError(err).
+			WithHint("Failed to get usage counter").
+			Mark(ierr.ErrDatabase)
+	}
+
+	SetSpanSuccess(span)
+	return &events.UsageCounter{
+		TenantID:      counter.TenantID,
+		EnvironmentID: counter.EnvironmentID,
+		CustomerID:    counter.CustomerID,
+		FeatureID:     counter.FeatureID,
+		PeriodID:      uint64(counter.PeriodID),
+		Value:         counter.Value,
+		UpdatedAt:     counter.UpdatedAt,
+	}, nil
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"github.com/flexprice/flexprice/internal/cache"
 	"github.com/flexprice/flexprice/internal/clickhouse"
+	"github.com/flexprice/flexprice/internal/config"
 	"github.com/flexprice/flexprice/internal/domain/addon"
 	"github.com/flexprice/flexprice/internal/domain/addonassociation"
 	"github.com/flexprice/flexprice/internal/domain/alertlogs"
+	"github.com/flexprice/flexprice/internal/domain/auditlog"
 	"github.com/flexprice/flexprice/internal/domain/auth"
 	"github.com/flexprice/flexprice/internal/domain/connection"
 	"github.com/flexprice/flexprice/internal/domain/costsheet"
@@ -41,6 +43,7 @@ import (
 	"github.com/flexprice/flexprice/internal/domain/wallet"
 	"github.com/flexprice/flexprice/internal/logger"
 	"github.com/flexprice/flexprice/internal/postgres"
+	"github.com/flexprice/flexprice/internal/types"
 	clickhouseRepo "github.com/flexprice/flexprice/internal/repository/clickhouse"
 	entRepo "github.com/flexprice/flexprice/internal/repository/ent"
 	"go.uber.org/fx"
@@ -54,6 +57,7 @@ type RepositoryParams struct {
 	EntClient    postgres.IClient
 	ClickHouseDB *clickhouse.ClickHouseStore
 	Cache        cache.Cache
+	Config       *config.Configuration
 }
 
 func NewEventRepository(p RepositoryParams) events.Repository {
@@ -68,6 +72,18 @@ func NewFeatureUsageRepository(p RepositoryParams) events.FeatureUsageRepository
 	return clickhouseRepo.NewFeatureUsageRepository(p.ClickHouseDB, p.Logger)
 }
 
+func NewUsageCounterRepository(p RepositoryParams) events.UsageCounterRepository {
+	return entRepo.NewUsageCounterRepository(p.EntClient, p.Logger)
+}
+
+func NewEventDedupRepository(p RepositoryParams) events.EventDedupRepository {
+	return entRepo.NewEventDedupRepository(p.EntClient, p.Logger)
+}
+
+func NewAuditLogRepository(p RepositoryParams) auditlog.Repository {
+	return entRepo.NewAuditLogRepository(p.EntClient, p.Logger)
+}
+
 func NewMeterRepository(p RepositoryParams) meter.Repository {
 	return entRepo.NewMeterRepository(p.EntClient, p.Logger, p.Cache)
 }
@@ -85,7 +101,11 @@ func NewPriceRepository(p RepositoryParams) price.Repository {
 }
 
 func NewCustomerRepository(p RepositoryParams) customer.Repository {
-	return entRepo.NewCustomerRepository(p.EntClient, p.Logger, p.Cache)
+	var lookupKeyConflictPolicy types.CustomerLookupKeyConflictPolicy
+	if p.Config != nil {
+		lookupKeyConflictPolicy = p.Config.Customer.LookupKeyConflictPolicy
+	}
+	return entRepo.NewCustomerRepository(p.EntClient, p.Logger, p.Cache, lookupKeyConflictPolicy)
 }
 
 func NewPlanRepository(p RepositoryParams) plan.Repository {
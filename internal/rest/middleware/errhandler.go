@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"encoding/json"
+	"strconv"
 	"strings"
 
 	"github.com/cockroachdb/errors"
@@ -32,6 +33,11 @@ func ErrorHandler() gin.HandlerFunc {
 				},
 			}
 
+			// Tell rate limited clients when to retry
+			if retryAfter, ok := details["retry_after_seconds"].(float64); ok {
+				c.Header("Retry-After", strconv.Itoa(int(retryAfter)))
+			}
+
 			status := ierr.HTTPStatusFromErr(err)
 			c.JSON(status, response)
 		}
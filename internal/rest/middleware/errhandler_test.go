@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorHandlerRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(err error) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.POST("/events", func(c *gin.Context) {
+			_ = c.Error(err)
+		})
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/events", nil))
+		return recorder
+	}
+
+	t.Run("rate_limited_request_tells_when_to_retry", func(t *testing.T) {
+		recorder := serve(ierr.NewError("customer exceeded its event ingestion rate limit").
+			WithHint("Too many events are ingested, please retry after 3 seconds").
+			WithReportableDetails(map[string]interface{}{
+				"retry_after_seconds": 3,
+			}).
+			Mark(ierr.ErrTooManyRequests))
+
+		require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.Equal(t, "3", recorder.Header().Get("Retry-After"))
+	})
+
+	t.Run("other_errors_have_no_retry_after", func(t *testing.T) {
+		recorder := serve(ierr.NewError("event buffer is full").
+			Mark(ierr.ErrTooManyRequests))
+
+		require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("Retry-After"))
+	})
+}
@@ -360,7 +360,9 @@ func (s *alertLogsService) getWebhookEventName(alertType types.AlertType, alertS
 
 func (s *alertLogsService) publishWebhookEvent(ctx context.Context, eventName string, alertLog *alertlogs.AlertLog, alertType types.AlertType) error {
 	var webhookPayload []byte
+	var idempotencyKey string
 	var err error
+	now := time.Now().UTC()
 
 	switch alertType {
 	case types.AlertTypeFeatureWalletBalance:
@@ -387,6 +389,19 @@ func (s *alertLogsService) publishWebhookEvent(ctx context.Context, eventName st
 			s.Logger.Errorw("failed to marshal webhook payload", "error", err)
 			return err
 		}
+
+		// Repeated alerts for the same customer, feature and status within the window share the key
+		idempotencyKey = types.GenerateWebhookIdempotencyKey(
+			s.Config.Webhook.AlertDedupWindow,
+			now,
+			types.GetTenantID(ctx),
+			types.GetEnvironmentID(ctx),
+			eventName,
+			customerID,
+			alertLog.EntityID,
+			walletID,
+			string(alertLog.AlertStatus),
+		)
 	default:
 		return ierr.NewError("invalid alert type").
 			WithHint("Invalid alert type").
@@ -394,13 +409,14 @@ func (s *alertLogsService) publishWebhookEvent(ctx context.Context, eventName st
 	}
 
 	webhookEvent := &types.WebhookEvent{
-		ID:            types.GenerateUUIDWithPrefix(types.UUID_PREFIX_WEBHOOK_EVENT),
-		EventName:     eventName,
-		TenantID:      types.GetTenantID(ctx),
-		EnvironmentID: types.GetEnvironmentID(ctx),
-		UserID:        types.GetUserID(ctx),
-		Timestamp:     time.Now().UTC(),
-		Payload:       json.RawMessage(webhookPayload),
+		ID:             types.GenerateUUIDWithPrefix(types.UUID_PREFIX_WEBHOOK_EVENT),
+		EventName:      eventName,
+		TenantID:       types.GetTenantID(ctx),
+		EnvironmentID:  types.GetEnvironmentID(ctx),
+		UserID:         types.GetUserID(ctx),
+		Timestamp:      now,
+		Payload:        json.RawMessage(webhookPayload),
+		IdempotencyKey: idempotencyKey,
 	}
 
 	if err := s.WebhookPublisher.PublishWebhook(ctx, webhookEvent); err != nil {
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/domain/alertlogs"
+	"github.com/flexprice/flexprice/internal/testutil"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+)
+
+// capturingWebhookPublisher keeps the published webhook events
+type capturingWebhookPublisher struct {
+	events []*types.WebhookEvent
+}
+
+func (p *capturingWebhookPublisher) PublishWebhook(_ context.Context, event *types.WebhookEvent) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *capturingWebhookPublisher) Close() error {
+	return nil
+}
+
+type AlertLogsServiceSuite struct {
+	testutil.BaseServiceTestSuite
+	service   *alertLogsService
+	publisher *capturingWebhookPublisher
+}
+
+func TestAlertLogsService(t *testing.T) {
+	suite.Run(t, new(AlertLogsServiceSuite))
+}
+
+func (s *AlertLogsServiceSuite) SetupTest() {
+	s.BaseServiceTestSuite.SetupTest()
+
+	cfg := *s.GetConfig()
+	cfg.Webhook.AlertDedupWindow = time.Hour
+
+	s.publisher = &capturingWebhookPublisher{}
+	s.service = &alertLogsService{
+		ServiceParams: ServiceParams{
+			Logger:           s.GetLogger(),
+			Config:           &cfg,
+			WebhookPublisher: s.publisher,
+		},
+	}
+}
+
+func (s *AlertLogsServiceSuite) featureAlert(customerID string, status types.AlertState) *alertlogs.AlertLog {
+	return &alertlogs.AlertLog{
+		ID:               types.GenerateUUIDWithPrefix(types.UUID_PREFIX_ALERT_LOG),
+		EntityType:       types.AlertEntityTypeFeature,
+		EntityID:         "feat_alert",
+		ParentEntityType: lo.ToPtr("wallet"),
+		ParentEntityID:   lo.ToPtr("wallet_alert"),
+		CustomerID:       lo.ToPtr(customerID),
+		AlertType:        types.AlertTypeFeatureWalletBalance,
+		AlertStatus:      status,
+	}
+}
+
+func (s *AlertLogsServiceSuite) TestFeatureWalletBalanceAlertIdempotencyKey() {
+	ctx := s.GetContext()
+	eventName := types.WebhookEventFeatureWalletBalanceAlert
+	publish := func(alert *alertlogs.AlertLog) *types.WebhookEvent {
+		s.Require().NoError(s.service.publishWebhookEvent(ctx, eventName, alert, alert.AlertType))
+		return s.publisher.events[len(s.publisher.events)-1]
+	}
+
+	first := publish(s.featureAlert("cust_alert_1", types.AlertStateInAlarm))
+	repeated := publish(s.featureAlert("cust_alert_1", types.AlertStateInAlarm))
+	s.NotEmpty(first.IdempotencyKey)
+	s.NotEqual(first.ID, repeated.ID)
+
+	// The window is an hour, so the alerts are only split if the test runs across the hour
+	if first.Timestamp.Truncate(time.Hour).Equal(repeated.Timestamp.Truncate(time.Hour)) {
+		s.Equal(first.IdempotencyKey, repeated.IdempotencyKey, "repeated alerts within the window collapse")
+	}
+
+	otherCustomer := publish(s.featureAlert("cust_alert_2", types.AlertStateInAlarm))
+	s.NotEqual(first.IdempotencyKey, otherCustomer.IdempotencyKey)
+
+	recovered := publish(s.featureAlert("cust_alert_1", types.AlertStateOk))
+	s.NotEqual(first.IdempotencyKey, recovered.IdempotencyKey)
+
+	s.Run("no_key_without_window", func() {
+		s.service.Config.Webhook.AlertDedupWindow = 0
+		event := publish(s.featureAlert("cust_alert_1", types.AlertStateInAlarm))
+		s.Empty(event.IdempotencyKey)
+	})
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"github.com/flexprice/flexprice/internal/domain/auditlog"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// recordAuditLog stores the audit log of a change to a meter, price or feature, with the actor
+// taken from the context. before and after are nil for a creation and a deletion, before must be
+// a copy taken ahead of the change. The change is already made when it's recorded, so a failure
+// to store the audit log is logged instead of failing the request. Nothing is recorded without
+// an audit log repository.
+func recordAuditLog(
+	ctx context.Context,
+	repo auditlog.Repository,
+	log *logger.Logger,
+	entityType types.AuditLogEntityType,
+	entityID string,
+	action types.AuditLogAction,
+	before, after interface{},
+) {
+	if repo == nil {
+		return
+	}
+
+	auditLog, err := auditlog.New(ctx, entityType, entityID, action, before, after)
+	if err == nil {
+		err = repo.Create(ctx, auditLog)
+	}
+	if err != nil && log != nil {
+		log.Errorw("failed to record audit log",
+			"entity_type", entityType,
+			"entity_id", entityID,
+			"action", action,
+			"error", err,
+		)
+	}
+}
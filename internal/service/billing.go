@@ -117,7 +117,7 @@ func (s *billingService) CalculateFixedCharges(
 			return nil, fixedCost, err
 		}
 
-		amount := priceService.CalculateCost(ctx, price.Price, item.Quantity)
+		amount := s.lineItemCost(ctx, priceService, price.Price, item, item.Quantity, nil)
 
 		// Apply proration if applicable
 		proratedAmount, err := s.applyProrationToLineItem(ctx, sub, item, price.Price, amount, &periodStart, &periodEnd)
@@ -181,6 +181,11 @@ func (s *billingService) CalculateUsageCharges(
 		return nil, decimal.Zero, nil
 	}
 
+	// A period without a duration has no usage to charge, weighted sums would bill it as 0
+	if err := types.ValidatePeriodDuration(periodStart, periodEnd); err != nil {
+		return nil, decimal.Zero, err
+	}
+
 	usageCharges := make([]dto.CreateInvoiceLineItemRequest, 0)
 	totalUsageCost := decimal.Zero
 
@@ -232,6 +237,11 @@ func (s *billingService) CalculateUsageCharges(
 			continue
 		}
 
+		// Usage of shadow meters is recorded but never billed
+		if m, ok := meterMap[item.MeterID]; ok && m.IsShadow() {
+			continue
+		}
+
 		// Find matching usage charges - may have multiple if there's overage
 		var matchingCharges []*dto.SubscriptionUsageByMetersResponse
 		for _, charge := range usage.Charges {
@@ -253,7 +263,7 @@ func (s *billingService) CalculateUsageCharges(
 		if err != nil {
 			return nil, decimal.Zero, err
 		}
-		eventService := NewEventService(s.EventRepo, s.MeterRepo, s.EventPublisher, s.Logger, s.Config)
+		eventService := newEventServiceFromParams(s.ServiceParams)
 
 		// Process each matching charge individually (normal and overage charges)
 		for _, matchingCharge := range matchingCharges {
@@ -286,12 +296,15 @@ func (s *billingService) CalculateUsageCharges(
 
 						// Create usage request with daily window size
 						usageRequest := &dto.GetUsageByMeterRequest{
-							MeterID:            item.MeterID,
-							PriceID:            item.PriceID,
-							ExternalCustomerID: customer.ExternalID,
-							StartTime:          item.GetPeriodStart(periodStart),
-							EndTime:            item.GetPeriodEnd(periodEnd),
-							WindowSize:         types.WindowSizeDay, // Use daily window size
+							MeterID:               item.MeterID,
+							PriceID:               item.PriceID,
+							ExternalCustomerID:    customer.ExternalID,
+							SubscriptionID:        sub.ID,
+							CustomerCreatedAt:     customer.CreatedAt,
+							SubscriptionStartDate: sub.StartDate,
+							StartTime:             item.GetPeriodStart(periodStart),
+							EndTime:               item.GetPeriodEnd(periodEnd),
+							WindowSize:            types.WindowSizeDay, // Use daily window size
 						}
 
 						// Get usage data with daily windows
@@ -342,13 +355,16 @@ func (s *billingService) CalculateUsageCharges(
 
 						// Create usage request with monthly window size
 						usageRequest := &dto.GetUsageByMeterRequest{
-							MeterID:            item.MeterID,
-							PriceID:            item.PriceID,
-							ExternalCustomerID: customer.ExternalID,
-							StartTime:          item.GetPeriodStart(periodStart),
-							EndTime:            item.GetPeriodEnd(periodEnd),
-							BillingAnchor:      &sub.BillingAnchor,
-							WindowSize:         types.WindowSizeMonth, // Use monthly window size
+							MeterID:               item.MeterID,
+							PriceID:               item.PriceID,
+							ExternalCustomerID:    customer.ExternalID,
+							SubscriptionID:        sub.ID,
+							CustomerCreatedAt:     customer.CreatedAt,
+							SubscriptionStartDate: sub.StartDate,
+							StartTime:             item.GetPeriodStart(periodStart),
+							EndTime:               item.GetPeriodEnd(periodEnd),
+							BillingAnchor:         &sub.BillingAnchor,
+							WindowSize:            types.WindowSizeMonth, // Use monthly window size
 						}
 
 						// Get usage data with monthly windows
@@ -421,13 +437,16 @@ func (s *billingService) CalculateUsageCharges(
 						if meter.IsBucketedMaxMeter() {
 							// Get usage with bucketed values
 							usageRequest := &dto.GetUsageByMeterRequest{
-								MeterID:            item.MeterID,
-								PriceID:            item.PriceID,
-								ExternalCustomerID: customer.ExternalID,
-								StartTime:          item.GetPeriodStart(periodStart),
-								EndTime:            item.GetPeriodEnd(periodEnd),
-								WindowSize:         types.WindowSizeMonth, // Set monthly window size for custom billing periods
-								BillingAnchor:      &sub.BillingAnchor,
+								MeterID:               item.MeterID,
+								PriceID:               item.PriceID,
+								ExternalCustomerID:    customer.ExternalID,
+								SubscriptionID:        sub.ID,
+								CustomerCreatedAt:     customer.CreatedAt,
+								SubscriptionStartDate: sub.StartDate,
+								StartTime:             item.GetPeriodStart(periodStart),
+								EndTime:               item.GetPeriodEnd(periodEnd),
+								WindowSize:            types.WindowSizeMonth, // Set monthly window size for custom billing periods
+								BillingAnchor:         &sub.BillingAnchor,
 							}
 
 							// Get usage data with buckets
@@ -442,20 +461,21 @@ func (s *billingService) CalculateUsageCharges(
 								bucketedValues[i] = result.Value
 							}
 
-							// Calculate cost using bucketed values
-							adjustedAmount := priceService.CalculateBucketedCost(ctx, matchingCharge.Price, bucketedValues)
-							matchingCharge.Amount = adjustedAmount.InexactFloat64()
-
 							// Update quantity to reflect the sum of all bucket maxes
 							totalBucketQuantity := decimal.Zero
 							for _, bucketValue := range bucketedValues {
 								totalBucketQuantity = totalBucketQuantity.Add(bucketValue)
 							}
+
+							// Calculate cost using bucketed values
+							bucketedCost := priceService.CalculateBucketedCost(ctx, matchingCharge.Price, bucketedValues)
+							adjustedAmount := s.lineItemCost(ctx, priceService, matchingCharge.Price, item, totalBucketQuantity, &bucketedCost)
+							matchingCharge.Amount = adjustedAmount.InexactFloat64()
 							matchingCharge.Quantity = totalBucketQuantity.InexactFloat64()
 							quantityForCalculation = totalBucketQuantity
 						} else {
 							// For regular pricing, use standard cost calculation
-							adjustedAmount := priceService.CalculateCost(ctx, matchingCharge.Price, quantityForCalculation)
+							adjustedAmount := s.lineItemCost(ctx, priceService, matchingCharge.Price, item, quantityForCalculation, nil)
 							matchingCharge.Amount = adjustedAmount.InexactFloat64()
 						}
 					}
@@ -468,6 +488,12 @@ func (s *billingService) CalculateUsageCharges(
 			// For all other cases (no entitlement, disabled entitlement, or overage),
 			// use the full quantity and calculate the amount normally
 
+			// Usage beyond a hard usage limit is not billed when caps are enforced
+			if ok && s.isHardUsageCapped(matchingEntitlement) {
+				quantityForCalculation = decimal.Zero
+				matchingCharge.Amount = 0
+			}
+
 			// Add the amount to total usage cost
 			lineItemAmount := decimal.NewFromFloat(matchingCharge.Amount)
 			totalUsageCost = totalUsageCost.Add(lineItemAmount)
@@ -592,6 +618,26 @@ func (s *billingService) CalculateUsageCharges(
 	return usageCharges, totalUsageCost, nil
 }
 
+// isHardUsageCapped reports whether the usage beyond the entitlement's usage limit is dropped
+// instead of billed, the usage within the limit is included in the entitlement
+func (s *billingService) isHardUsageCapped(entitlement *dto.AggregatedEntitlement) bool {
+	return s.Config.FeatureUsageTracking.EnforceHardUsageCaps && entitlement != nil &&
+		entitlement.IsEnabled && !entitlement.IsSoftLimit && entitlement.UsageLimit != nil
+}
+
+// lineItemCost calculates the cost of the quantity of the line item billed on the invoice with
+// the effective cost computation analytics use. The commitment is applied to the usage charges
+// of the subscription before they are billed, so it isn't applied again here.
+func (s *billingService) lineItemCost(ctx context.Context, priceService PriceService, p *price.Price, item *subscription.SubscriptionLineItem, quantity decimal.Decimal, baseCost *decimal.Decimal) decimal.Decimal {
+	breakdown := priceService.CalculateEffectiveCost(ctx, dto.EffectiveCostRequest{
+		Price:    p,
+		LineItem: item,
+		Quantity: quantity,
+		BaseCost: baseCost,
+	})
+	return breakdown.Total
+}
+
 // calculateRemainingCommitment calculates the remaining commitment amount
 // that needs to be charged as a true-up
 func (s *billingService) calculateRemainingCommitment(
@@ -619,6 +665,11 @@ func (s *billingService) CalculateUsageChargesForPreview(
 		return nil, decimal.Zero, nil
 	}
 
+	// A period without a duration has no usage to charge, weighted sums would bill it as 0
+	if err := types.ValidatePeriodDuration(periodStart, periodEnd); err != nil {
+		return nil, decimal.Zero, err
+	}
+
 	usageCharges := make([]dto.CreateInvoiceLineItemRequest, 0)
 	totalUsageCost := decimal.Zero
 
@@ -670,6 +721,11 @@ func (s *billingService) CalculateUsageChargesForPreview(
 			continue
 		}
 
+		// Usage of shadow meters is recorded but never billed
+		if m, ok := meterMap[item.MeterID]; ok && m.IsShadow() {
+			continue
+		}
+
 		// Find matching usage charges - may have multiple if there's overage
 		var matchingCharges []*dto.SubscriptionUsageByMetersResponse
 		for _, charge := range usage.Charges {
@@ -691,7 +747,7 @@ func (s *billingService) CalculateUsageChargesForPreview(
 		if err != nil {
 			return nil, decimal.Zero, err
 		}
-		eventService := NewEventService(s.EventRepo, s.MeterRepo, s.EventPublisher, s.Logger, s.Config)
+		eventService := newEventServiceFromParams(s.ServiceParams)
 
 		// Get meter from pre-fetched map (needed for bucketed meter check)
 		meter, meterOk := meterMap[item.MeterID]
@@ -738,15 +794,16 @@ func (s *billingService) CalculateUsageChargesForPreview(
 					bucketedValues[i] = result.Value
 				}
 
-				// Calculate cost using bucketed values
-				adjustedAmount := priceService.CalculateBucketedCost(ctx, matchingCharge.Price, bucketedValues)
-				matchingCharge.Amount = price.FormatAmountToFloat64WithPrecision(adjustedAmount, matchingCharge.Price.Currency)
-
 				// Update quantity to reflect the sum of all bucket maxes
 				totalBucketQuantity := decimal.Zero
 				for _, bucketValue := range bucketedValues {
 					totalBucketQuantity = totalBucketQuantity.Add(bucketValue)
 				}
+
+				// Calculate cost using bucketed values
+				bucketedCost := priceService.CalculateBucketedCost(ctx, matchingCharge.Price, bucketedValues)
+				adjustedAmount := s.lineItemCost(ctx, priceService, matchingCharge.Price, item, totalBucketQuantity, &bucketedCost)
+				matchingCharge.Amount = price.FormatAmountToFloat64WithPrecision(adjustedAmount, matchingCharge.Price.Currency)
 				matchingCharge.Quantity = totalBucketQuantity.InexactFloat64()
 				quantityForCalculation = totalBucketQuantity
 			}
@@ -778,12 +835,15 @@ func (s *billingService) CalculateUsageChargesForPreview(
 
 						// Create usage request with daily window size
 						usageRequest := &dto.GetUsageByMeterRequest{
-							MeterID:            item.MeterID,
-							PriceID:            item.PriceID,
-							ExternalCustomerID: customer.ExternalID,
-							StartTime:          item.GetPeriodStart(periodStart),
-							EndTime:            item.GetPeriodEnd(periodEnd),
-							WindowSize:         types.WindowSizeDay, // Use daily window size
+							MeterID:               item.MeterID,
+							PriceID:               item.PriceID,
+							ExternalCustomerID:    customer.ExternalID,
+							SubscriptionID:        sub.ID,
+							CustomerCreatedAt:     customer.CreatedAt,
+							SubscriptionStartDate: sub.StartDate,
+							StartTime:             item.GetPeriodStart(periodStart),
+							EndTime:               item.GetPeriodEnd(periodEnd),
+							WindowSize:            types.WindowSizeDay, // Use daily window size
 						}
 
 						// Get usage data with daily windows
@@ -834,13 +894,16 @@ func (s *billingService) CalculateUsageChargesForPreview(
 
 						// Create usage request with monthly window size
 						usageRequest := &dto.GetUsageByMeterRequest{
-							MeterID:            item.MeterID,
-							PriceID:            item.PriceID,
-							ExternalCustomerID: customer.ExternalID,
-							StartTime:          item.GetPeriodStart(periodStart),
-							EndTime:            item.GetPeriodEnd(periodEnd),
-							BillingAnchor:      &sub.BillingAnchor,
-							WindowSize:         types.WindowSizeMonth, // Use monthly window size
+							MeterID:               item.MeterID,
+							PriceID:               item.PriceID,
+							ExternalCustomerID:    customer.ExternalID,
+							SubscriptionID:        sub.ID,
+							CustomerCreatedAt:     customer.CreatedAt,
+							SubscriptionStartDate: sub.StartDate,
+							StartTime:             item.GetPeriodStart(periodStart),
+							EndTime:               item.GetPeriodEnd(periodEnd),
+							BillingAnchor:         &sub.BillingAnchor,
+							WindowSize:            types.WindowSizeMonth, // Use monthly window size
 						}
 
 						// Get usage data with monthly windows
@@ -899,7 +962,7 @@ func (s *billingService) CalculateUsageChargesForPreview(
 					// Recalculate the amount based on the adjusted quantity (only for non-bucketed meters)
 					if matchingCharge.Price != nil {
 						// For regular pricing, use standard cost calculation
-						adjustedAmount := priceService.CalculateCost(ctx, matchingCharge.Price, quantityForCalculation)
+						adjustedAmount := s.lineItemCost(ctx, priceService, matchingCharge.Price, item, quantityForCalculation, nil)
 						matchingCharge.Amount = price.FormatAmountToFloat64WithPrecision(adjustedAmount, matchingCharge.Price.Currency)
 					}
 				} else {
@@ -911,10 +974,16 @@ func (s *billingService) CalculateUsageChargesForPreview(
 				// For non-bucketed meters without entitlements (but not overage charges),
 				// calculate cost normally. Overage charges already have the correct amount
 				// calculated by GetFeatureUsageBySubscription with the overage factor applied.
-				adjustedAmount := priceService.CalculateCost(ctx, matchingCharge.Price, quantityForCalculation)
+				adjustedAmount := s.lineItemCost(ctx, priceService, matchingCharge.Price, item, quantityForCalculation, nil)
 				matchingCharge.Amount = price.FormatAmountToFloat64WithPrecision(adjustedAmount, matchingCharge.Price.Currency)
 			}
 
+			// Usage beyond a hard usage limit is not billed when caps are enforced
+			if entitlementOk && s.isHardUsageCapped(matchingEntitlement) {
+				quantityForCalculation = decimal.Zero
+				matchingCharge.Amount = 0
+			}
+
 			// Add the amount to total usage cost
 			lineItemAmount := decimal.NewFromFloat(matchingCharge.Amount)
 			totalUsageCost = totalUsageCost.Add(lineItemAmount)
@@ -2047,7 +2116,7 @@ func (s *billingService) GetCustomerEntitlements(ctx context.Context, customerID
 
 func (s *billingService) GetCustomerUsageSummary(ctx context.Context, customerID string, req *dto.GetCustomerUsageSummaryRequest) (*dto.CustomerUsageSummaryResponse, error) {
 	subscriptionService := NewSubscriptionService(s.ServiceParams)
-	eventService := NewEventService(s.EventRepo, s.MeterRepo, s.EventPublisher, s.Logger, s.Config)
+	eventService := newEventServiceFromParams(s.ServiceParams)
 
 	// get customer
 	customer, err := s.CustomerRepo.Get(ctx, customerID)
@@ -2161,11 +2230,14 @@ func (s *billingService) GetCustomerUsageSummary(ctx context.Context, customerID
 					meterID := featureMeterMap[featureID]
 					// Create usage request with daily window size for current billing period
 					usageRequest := &dto.GetUsageByMeterRequest{
-						MeterID:            meterID,
-						ExternalCustomerID: customer.ExternalID,
-						StartTime:          sub.CurrentPeriodStart,
-						EndTime:            sub.CurrentPeriodEnd,
-						WindowSize:         types.WindowSizeDay,
+						MeterID:               meterID,
+						ExternalCustomerID:    customer.ExternalID,
+						SubscriptionID:        sub.ID,
+						CustomerCreatedAt:     customer.CreatedAt,
+						SubscriptionStartDate: sub.StartDate,
+						StartTime:             sub.CurrentPeriodStart,
+						EndTime:               sub.CurrentPeriodEnd,
+						WindowSize:            types.WindowSizeDay,
 					}
 
 					// Get usage data with daily windows
@@ -2212,12 +2284,15 @@ func (s *billingService) GetCustomerUsageSummary(ctx context.Context, customerID
 
 					// Create usage request for current month with monthly window size
 					usageRequest := &dto.GetUsageByMeterRequest{
-						MeterID:            meterID,
-						ExternalCustomerID: customer.ExternalID,
-						StartTime:          sub.CurrentPeriodStart,
-						EndTime:            sub.CurrentPeriodEnd,
-						WindowSize:         types.WindowSizeMonth,
-						BillingAnchor:      &sub.BillingAnchor,
+						MeterID:               meterID,
+						ExternalCustomerID:    customer.ExternalID,
+						SubscriptionID:        sub.ID,
+						CustomerCreatedAt:     customer.CreatedAt,
+						SubscriptionStartDate: sub.StartDate,
+						StartTime:             sub.CurrentPeriodStart,
+						EndTime:               sub.CurrentPeriodEnd,
+						WindowSize:            types.WindowSizeMonth,
+						BillingAnchor:         &sub.BillingAnchor,
 					}
 
 					// Get usage data for current month
@@ -2270,10 +2345,13 @@ func (s *billingService) GetCustomerUsageSummary(ctx context.Context, customerID
 					// For never reset features, calculate cumulative usage from subscription start to current period end
 					// This maintains consistency with the billing logic
 					totalUsageRequest := &dto.GetUsageByMeterRequest{
-						MeterID:            meterID,
-						ExternalCustomerID: customer.ExternalID,
-						StartTime:          sub.StartDate,
-						EndTime:            sub.CurrentPeriodEnd,
+						MeterID:               meterID,
+						ExternalCustomerID:    customer.ExternalID,
+						SubscriptionID:        sub.ID,
+						CustomerCreatedAt:     customer.CreatedAt,
+						SubscriptionStartDate: sub.StartDate,
+						StartTime:             sub.StartDate,
+						EndTime:               sub.CurrentPeriodEnd,
 					}
 
 					totalUsageResult, err := eventService.GetUsageByMeter(ctx, totalUsageRequest)
@@ -2424,12 +2502,15 @@ func (s *billingService) calculateNeverResetUsage(
 
 	// Get total cumulative usage from subscription start to line item period end
 	totalUsageRequest := &dto.GetUsageByMeterRequest{
-		MeterID:            item.MeterID,
-		PriceID:            item.PriceID,
-		ExternalCustomerID: customer.ExternalID,
-		StartTime:          sub.StartDate,
-		EndTime:            lineItemPeriodEnd,
-		BillingAnchor:      &sub.BillingAnchor,
+		MeterID:               item.MeterID,
+		PriceID:               item.PriceID,
+		ExternalCustomerID:    customer.ExternalID,
+		SubscriptionID:        sub.ID,
+		CustomerCreatedAt:     customer.CreatedAt,
+		SubscriptionStartDate: sub.StartDate,
+		StartTime:             sub.StartDate,
+		EndTime:               lineItemPeriodEnd,
+		BillingAnchor:         &sub.BillingAnchor,
 	}
 
 	totalUsageResult, err := eventService.GetUsageByMeter(ctx, totalUsageRequest)
@@ -2440,11 +2521,14 @@ func (s *billingService) calculateNeverResetUsage(
 	// Get cumulative usage from subscription start to line item period start
 	// This represents usage that was already billed in previous periods
 	previousPeriodUsageRequest := &dto.GetUsageByMeterRequest{
-		MeterID:            item.MeterID,
-		PriceID:            item.PriceID,
-		ExternalCustomerID: customer.ExternalID,
-		StartTime:          sub.StartDate,
-		EndTime:            lineItemPeriodStart,
+		MeterID:               item.MeterID,
+		PriceID:               item.PriceID,
+		ExternalCustomerID:    customer.ExternalID,
+		SubscriptionID:        sub.ID,
+		CustomerCreatedAt:     customer.CreatedAt,
+		SubscriptionStartDate: sub.StartDate,
+		StartTime:             sub.StartDate,
+		EndTime:               lineItemPeriodStart,
 	}
 
 	previousPeriodUsageResult, err := eventService.GetUsageByMeter(ctx, previousPeriodUsageRequest)
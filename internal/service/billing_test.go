@@ -1360,6 +1360,112 @@ func (s *BillingServiceSuite) TestCalculateUsageChargesWithEntitlements() {
 	}
 }
 
+func (s *BillingServiceSuite) TestCalculateUsageChargesWithHardUsageCap() {
+	s.GetConfig().FeatureUsageTracking.EnforceHardUsageCaps = true
+	defer func() { s.GetConfig().FeatureUsageTracking.EnforceHardUsageCaps = false }()
+
+	testFeature := &feature.Feature{
+		ID:        "feat_hard_cap",
+		Name:      "Capped Feature",
+		Type:      types.FeatureTypeMetered,
+		MeterID:   s.testData.meters.apiCalls.ID,
+		BaseModel: types.GetDefaultBaseModel(s.GetContext()),
+	}
+	s.NoError(s.GetStores().FeatureRepo.Create(s.GetContext(), testFeature))
+
+	ent := &entitlement.Entitlement{
+		ID:               "ent_hard_cap",
+		EntityType:       types.ENTITLEMENT_ENTITY_TYPE_PLAN,
+		EntityID:         s.testData.plan.ID,
+		FeatureID:        testFeature.ID,
+		FeatureType:      types.FeatureTypeMetered,
+		IsEnabled:        true,
+		UsageLimit:       lo.ToPtr(int64(100)),
+		UsageResetPeriod: types.ENTITLEMENT_USAGE_RESET_PERIOD_MONTHLY,
+		BaseModel:        types.GetDefaultBaseModel(s.GetContext()),
+	}
+	_, err := s.GetStores().EntitlementRepo.Create(s.GetContext(), ent)
+	s.NoError(err)
+
+	calculate := func() ([]dto.CreateInvoiceLineItemRequest, decimal.Decimal) {
+		// 500 units at $0.02, 400 of them beyond the limit of 100
+		usage := &dto.GetUsageBySubscriptionResponse{
+			StartTime: s.testData.subscription.CurrentPeriodStart,
+			EndTime:   s.testData.subscription.CurrentPeriodEnd,
+			Currency:  s.testData.subscription.Currency,
+			Charges: []*dto.SubscriptionUsageByMetersResponse{{
+				Price:    s.testData.prices.apiCalls,
+				Quantity: 500,
+				Amount:   10,
+				MeterID:  s.testData.meters.apiCalls.ID,
+			}},
+		}
+		lineItems, total, err := s.service.CalculateUsageCharges(s.GetContext(), s.testData.subscription, usage,
+			s.testData.subscription.CurrentPeriodStart, s.testData.subscription.CurrentPeriodEnd)
+		s.Require().NoError(err)
+		return lineItems, total
+	}
+
+	s.Run("usage_beyond_hard_limit_is_not_billed", func() {
+		lineItems, total := calculate()
+		s.Require().Len(lineItems, 1)
+		s.True(total.IsZero(), "total: %s", total)
+		s.True(lineItems[0].Quantity.IsZero())
+	})
+
+	s.Run("soft_limit_bills_overage", func() {
+		ent.IsSoftLimit = true
+		_, err := s.GetStores().EntitlementRepo.Update(s.GetContext(), ent)
+		s.Require().NoError(err)
+		defer func() {
+			ent.IsSoftLimit = false
+			_, err := s.GetStores().EntitlementRepo.Update(s.GetContext(), ent)
+			s.Require().NoError(err)
+		}()
+
+		_, total := calculate()
+		s.True(decimal.NewFromInt(8).Equal(total), "total: %s", total)
+	})
+
+	s.Run("disabled_enforcement_bills_overage", func() {
+		s.GetConfig().FeatureUsageTracking.EnforceHardUsageCaps = false
+		defer func() { s.GetConfig().FeatureUsageTracking.EnforceHardUsageCaps = true }()
+
+		_, total := calculate()
+		s.True(decimal.NewFromInt(8).Equal(total), "total: %s", total)
+	})
+}
+
+func (s *BillingServiceSuite) TestCalculateUsageChargesWithZeroDurationPeriod() {
+	periodStart := s.testData.subscription.CurrentPeriodStart
+	usage := &dto.GetUsageBySubscriptionResponse{
+		StartTime: periodStart,
+		EndTime:   periodStart,
+		Currency:  s.testData.subscription.Currency,
+		Charges: []*dto.SubscriptionUsageByMetersResponse{{
+			Price:    s.testData.prices.apiCalls,
+			Quantity: 500,
+			Amount:   10,
+			MeterID:  s.testData.meters.apiCalls.ID,
+		}},
+	}
+
+	s.Run("invoice_charges_fail_instead_of_billing_zero", func() {
+		lineItems, total, err := s.service.CalculateUsageCharges(s.GetContext(), s.testData.subscription, usage,
+			periodStart, periodStart)
+		s.Error(err)
+		s.True(types.IsZeroDurationPeriod(err), "error: %v", err)
+		s.Empty(lineItems)
+		s.True(total.IsZero())
+	})
+
+	s.Run("preview_charges_fail_instead_of_billing_zero", func() {
+		_, _, err := s.service.(*billingService).CalculateUsageChargesForPreview(s.GetContext(), s.testData.subscription, usage,
+			periodStart, periodStart)
+		s.True(types.IsZeroDurationPeriod(err), "error: %v", err)
+	})
+}
+
 func (s *BillingServiceSuite) TestCalculateUsageChargesWithDailyReset() {
 	// Setup test data for daily usage calculation
 	ctx := s.GetContext()
@@ -4,6 +4,7 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/flexprice/flexprice/internal/api/dto"
 	domainCustomer "github.com/flexprice/flexprice/internal/domain/customer"
@@ -537,3 +538,64 @@ func (s *CustomerServiceSuite) TestGetCustomerByLookupKey() {
 		})
 	}
 }
+
+func (s *CustomerServiceSuite) TestGetCustomerByLookupKeyMultipleMatches() {
+	store := s.GetStores().CustomerRepo.(*testutil.InMemoryCustomerStore)
+	defer func() { store.LookupKeyConflictPolicy = "" }()
+
+	now := time.Now().UTC()
+	for _, c := range []*domainCustomer.Customer{
+		{ID: "cust-dup-newest", ExternalID: "ext-dup", Name: "Newest", BaseModel: types.BaseModel{CreatedAt: now}},
+		{ID: "cust-dup-oldest", ExternalID: "ext-dup", Name: "Oldest", BaseModel: types.BaseModel{CreatedAt: now.Add(-time.Hour)}},
+		{ID: "cust-dup-middle", ExternalID: "ext-dup", Name: "Middle", BaseModel: types.BaseModel{CreatedAt: now.Add(-time.Minute)}},
+	} {
+		s.NoError(store.Create(s.ctx, c))
+	}
+
+	testCases := []struct {
+		name       string
+		policy     types.CustomerLookupKeyConflictPolicy
+		expectedID string
+	}{
+		{name: "unset_policy_errors", policy: ""},
+		{name: "error_policy_errors", policy: types.CustomerLookupKeyConflictPolicyError},
+		{name: "invalid_policy_errors", policy: "random"},
+		{name: "oldest_policy_picks_first_created", policy: types.CustomerLookupKeyConflictPolicyOldest, expectedID: "cust-dup-oldest"},
+		{name: "newest_policy_picks_last_created", policy: types.CustomerLookupKeyConflictPolicyNewest, expectedID: "cust-dup-newest"},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			store.LookupKeyConflictPolicy = tc.policy
+
+			resp, err := s.service.GetCustomerByLookupKey(s.ctx, "ext-dup")
+			if tc.expectedID == "" {
+				s.Error(err)
+				s.Nil(resp)
+				s.True(ierr.IsInvalidOperation(err), "Expected invalid operation error, got %v", err)
+				return
+			}
+			s.NoError(err)
+			s.Equal(tc.expectedID, resp.Customer.ID)
+		})
+	}
+
+	s.Run("ties_broken_by_id", func() {
+		s.NoError(store.Create(s.ctx, &domainCustomer.Customer{
+			ID: "cust-dup-a", ExternalID: "ext-tie", BaseModel: types.BaseModel{CreatedAt: now},
+		}))
+		s.NoError(store.Create(s.ctx, &domainCustomer.Customer{
+			ID: "cust-dup-b", ExternalID: "ext-tie", BaseModel: types.BaseModel{CreatedAt: now},
+		}))
+
+		store.LookupKeyConflictPolicy = types.CustomerLookupKeyConflictPolicyOldest
+		resp, err := s.service.GetCustomerByLookupKey(s.ctx, "ext-tie")
+		s.NoError(err)
+		s.Equal("cust-dup-a", resp.Customer.ID)
+
+		store.LookupKeyConflictPolicy = types.CustomerLookupKeyConflictPolicyNewest
+		resp, err = s.service.GetCustomerByLookupKey(s.ctx, "ext-tie")
+		s.NoError(err)
+		s.Equal("cust-dup-b", resp.Customer.ID)
+	})
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
@@ -13,9 +14,11 @@ import (
 	"github.com/flexprice/flexprice/internal/config"
 	"github.com/flexprice/flexprice/internal/domain/events"
 	"github.com/flexprice/flexprice/internal/domain/meter"
+	"github.com/flexprice/flexprice/internal/domain/settings"
 	ierr "github.com/flexprice/flexprice/internal/errors"
 	"github.com/flexprice/flexprice/internal/kafka"
 	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/metrics"
 	"github.com/flexprice/flexprice/internal/publisher"
 	"github.com/flexprice/flexprice/internal/sentry"
 	"github.com/flexprice/flexprice/internal/types"
@@ -32,7 +35,9 @@ type EventService interface {
 	GetUsageByMeterWithFilters(ctx context.Context, req *dto.GetUsageByMeterRequest, filterGroups map[string]map[string][]string) ([]*events.AggregationResult, error)
 	GetEvents(ctx context.Context, req *dto.GetEventsRequest) (*dto.GetEventsResponse, error)
 	GetMonitoringData(ctx context.Context, req *dto.GetMonitoringDataRequest) (*dto.GetMonitoringDataResponse, error)
-	MonitorKafkaLag(ctx context.Context) error
+	MonitorKafkaLag(ctx context.Context) (*dto.KafkaLagMonitoringResponse, error)
+	// GetClockSkew reports the clock skew of the event producers and flags the consistently skewed ones
+	GetClockSkew(ctx context.Context, req *dto.GetClockSkewRequest) (*dto.GetClockSkewResponse, error)
 }
 
 type eventService struct {
@@ -41,6 +46,15 @@ type eventService struct {
 	publisher publisher.EventPublisher
 	logger    *logger.Logger
 	config    *config.Configuration
+	// lagQuerier queries the lag of the kafka consumer groups
+	lagQuerier kafka.ConsumerLagQuerier
+	// rateLimiter limits the rate at which each customer ingests events
+	rateLimiter *eventRateLimiter
+	metrics     metrics.Recorder // Per tenant clock skew of the ingested events
+	// unitMeters caches the meters whose unit the ingested values are converted to
+	unitMeters *unitMeterCache
+	// settingsRepo reads the event config of the tenant, without it the defaults are used
+	settingsRepo settings.Repository
 }
 
 func NewEventService(
@@ -51,46 +65,270 @@ func NewEventService(
 	config *config.Configuration,
 ) EventService {
 	return &eventService{
-		eventRepo: eventRepo,
-		meterRepo: meterRepo,
-		publisher: publisher,
-		logger:    logger,
-		config:    config,
+		eventRepo:   eventRepo,
+		meterRepo:   meterRepo,
+		publisher:   publisher,
+		logger:      logger,
+		config:      config,
+		lagQuerier:  kafka.NewMonitoringService(config, logger),
+		rateLimiter: newEventRateLimiter(config.Event.RateLimit),
+		metrics:     metrics.Default(),
+		unitMeters:  newUnitMeterCache(),
 	}
 }
 
+// NewIngestEventService creates the event service of the ingestion API, which publishes with the
+// ingest publisher buffering the events when configured
+func NewIngestEventService(
+	eventRepo events.Repository,
+	meterRepo meter.Repository,
+	publisher publisher.IngestEventPublisher,
+	logger *logger.Logger,
+	config *config.Configuration,
+	settingsRepo settings.Repository,
+) EventService {
+	svc := NewEventService(eventRepo, meterRepo, publisher, logger, config).(*eventService)
+	svc.settingsRepo = settingsRepo
+	return svc
+}
+
+// newEventServiceFromParams creates the event service used by other services, which matches
+// events to meters as the tenant's event config sets
+func newEventServiceFromParams(params ServiceParams) EventService {
+	svc := NewEventService(params.EventRepo, params.MeterRepo, params.EventPublisher, params.Logger, params.Config).(*eventService)
+	svc.settingsRepo = params.SettingsRepo
+	return svc
+}
+
+// eventConfig returns the event config of the tenant, the defaults when settings can't be read
+func (s *eventService) eventConfig(ctx context.Context) *types.EventConfig {
+	if s.settingsRepo == nil {
+		return types.EventConfigFromValue(nil)
+	}
+	return getEventConfig(ctx, ServiceParams{SettingsRepo: s.settingsRepo, Logger: s.logger, Config: s.config})
+}
+
 func (s *eventService) CreateEvent(ctx context.Context, createEventRequest *dto.IngestEventRequest) error {
+	createEventRequest.ResolveExternalCustomerID(s.config.FeatureUsageTracking.ExternalCustomerIDProperty)
 	if err := createEventRequest.Validate(); err != nil {
 		return err
 	}
 
-	event := createEventRequest.ToEvent(ctx)
+	if err := s.rateLimiter.Allow(ctx, map[string]int{createEventRequest.ExternalCustomerID: 1}); err != nil {
+		return err
+	}
 
-	if err := s.publisher.Publish(ctx, event); err != nil {
-		// Log the error but don't fail the request
-		s.logger.With(
-			"event_id", event.ID,
-			"error", err,
-		).Error("failed to publish event")
+	return s.publishEvent(ctx, createEventRequest)
+}
+
+// publishEvent publishes the validated event to the downstream processing
+func (s *eventService) publishEvent(ctx context.Context, createEventRequest *dto.IngestEventRequest) error {
+	event, err := s.prepareEvent(ctx, createEventRequest)
+	if err != nil {
+		return err
+	}
+
+	if err := s.publishEvents(ctx, []*events.Event{event}); err != nil {
+		return err
 	}
 
 	createEventRequest.EventID = event.ID
 	return nil
 }
 
+// prepareEvent builds the event to publish from the validated request
+func (s *eventService) prepareEvent(ctx context.Context, createEventRequest *dto.IngestEventRequest) (*events.Event, error) {
+	// the timestamp defaults to the ingestion time when the producer sends none
+	hasTimestamp := !createEventRequest.Timestamp.IsZero()
+
+	event := createEventRequest.ToEvent(ctx)
+	if err := s.normalizeEventName(event); err != nil {
+		return nil, err
+	}
+	s.normalizeValueUnits(ctx, event)
+
+	if hasTimestamp {
+		s.recordClockSkew(event, time.Now().UTC())
+	}
+	return event, nil
+}
+
+// prepareEvents validates the requests and builds the events to publish
+func (s *eventService) prepareEvents(ctx context.Context, requests []*dto.IngestEventRequest) ([]*events.Event, error) {
+	batch := make([]*events.Event, 0, len(requests))
+	for _, request := range requests {
+		if err := request.Validate(); err != nil {
+			return nil, err
+		}
+		event, err := s.prepareEvent(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		batch = append(batch, event)
+	}
+	return batch, nil
+}
+
+// publishEvents publishes the events together when the publisher publishes batches, so a full
+// event buffer rejects all of them or none. Other publish errors are logged and don't fail the
+// request.
+func (s *eventService) publishEvents(ctx context.Context, batch []*events.Event) error {
+	var err error
+	if batchPublisher, ok := s.publisher.(publisher.BatchPublisher); ok {
+		err = batchPublisher.PublishBatch(ctx, batch)
+	} else {
+		var errs []error
+		for _, event := range batch {
+			if publishErr := s.publisher.Publish(ctx, event); publishErr != nil {
+				errs = append(errs, publishErr)
+			}
+		}
+		err = errors.Join(errs...)
+	}
+	if err == nil {
+		return nil
+	}
+
+	// Let the client back off when the event buffer is full
+	if ierr.IsTooManyRequests(err) {
+		return err
+	}
+	// Log the error but don't fail the request
+	s.logger.With(
+		"events", len(batch),
+		"first_event_id", batch[0].ID,
+		"error", err,
+	).Error("failed to publish events")
+	return nil
+}
+
+// recordClockSkew records how far the timestamp of the event is from its ingestion, per tenant, and
+// counts the events timestamped in the future so producers with a skewed clock can be alerted on
+func (s *eventService) recordClockSkew(event *events.Event, ingestedAt time.Time) {
+	if s.metrics == nil {
+		return
+	}
+
+	skew := ingestedAt.Sub(event.Timestamp)
+	s.metrics.RecordDuration(metrics.MetricEventClockSkew, event.TenantID, skew)
+	if skew < 0 {
+		s.metrics.IncrementCounter(metrics.MetricEventFutureTimestamp, event.TenantID)
+	}
+}
+
+// normalizeEventName applies the configured event name normalization to the event, recording
+// the name it was sent with in its properties when that changes it
+func (s *eventService) normalizeEventName(event *events.Event) error {
+	normalized := eventNameNormalization(s.config, s.logger).Normalize(event.EventName)
+	if normalized == event.EventName {
+		return nil
+	}
+
+	if normalized == "" {
+		return ierr.NewError("event_name is empty after normalization").
+			WithHint("Event name must contain more than whitespace").
+			WithReportableDetails(map[string]interface{}{
+				"event_name": event.EventName,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	if event.Properties == nil {
+		event.Properties = make(map[string]interface{})
+	}
+	event.Properties[events.OriginalEventNameProperty] = event.EventName
+	event.EventName = normalized
+	return nil
+}
+
+// maxEventValuePolicy returns the configured max event value policy, falling back to reject when
+// it is unset or invalid. Invoices apply the same policy to the events they bill.
+func maxEventValuePolicy(cfg *config.Configuration, log *logger.Logger) types.MaxEventValuePolicy {
+	if cfg == nil || cfg.FeatureUsageTracking.MaxEventValuePolicy == "" {
+		return types.MaxEventValuePolicyReject
+	}
+
+	policy := cfg.FeatureUsageTracking.MaxEventValuePolicy
+	if err := policy.Validate(); err != nil {
+		log.Warnw("invalid max event value policy configured, falling back to reject",
+			"policy", policy,
+			"error", err,
+		)
+		return types.MaxEventValuePolicyReject
+	}
+
+	return policy
+}
+
+// preCustomerCreationPolicy returns the configured policy for events timestamped before the
+// customer was created, falling back to match when it is unset or invalid. Invoices apply the
+// same policy to the events they bill.
+func preCustomerCreationPolicy(cfg *config.Configuration, log *logger.Logger) types.PreCustomerCreationEventPolicy {
+	if cfg == nil || cfg.FeatureUsageTracking.PreCustomerCreationPolicy == "" {
+		return types.PreCustomerCreationEventPolicyMatch
+	}
+
+	policy := cfg.FeatureUsageTracking.PreCustomerCreationPolicy
+	if err := policy.Validate(); err != nil {
+		log.Warnw("invalid pre customer creation policy configured, falling back to match",
+			"policy", policy,
+			"error", err,
+		)
+		return types.PreCustomerCreationEventPolicyMatch
+	}
+
+	return policy
+}
+
+// eventNameNormalization returns the configured event name normalization, falling back to none
+// when it is unset or invalid
+func eventNameNormalization(cfg *config.Configuration, log *logger.Logger) types.EventNameNormalization {
+	if cfg == nil || cfg.Event.NameNormalization == "" {
+		return types.EventNameNormalizationNone
+	}
+
+	normalization := cfg.Event.NameNormalization
+	if err := normalization.Validate(); err != nil {
+		log.Warnw("invalid event name normalization configured, falling back to none",
+			"normalization", normalization,
+			"error", err,
+		)
+		return types.EventNameNormalizationNone
+	}
+
+	return normalization
+}
+
 // CreateBulkEvents creates multiple events in a single operation
 func (s *eventService) BulkCreateEvents(ctx context.Context, events *dto.BulkIngestEventRequest) error {
 	if len(events.Events) == 0 {
 		return nil
 	}
 
-	// publish events to Kafka for downstream processing
+	// the events of the request are rate limited together so a rejected request ingests none of them
+	eventsByCustomer := make(map[string]int)
 	for _, event := range events.Events {
-		if err := s.CreateEvent(ctx, event); err != nil {
-			return err
-		}
+		event.ResolveExternalCustomerID(s.config.FeatureUsageTracking.ExternalCustomerIDProperty)
+		eventsByCustomer[event.ExternalCustomerID]++
+	}
+	if err := s.rateLimiter.Allow(ctx, eventsByCustomer); err != nil {
+		return err
 	}
 
+	// the events are published together once all of them are valid
+	batch, err := s.prepareEvents(ctx, events.Events)
+	if err != nil {
+		return err
+	}
+
+	// publish events to Kafka for downstream processing
+	if err := s.publishEvents(ctx, batch); err != nil {
+		return err
+	}
+
+	for i, event := range events.Events {
+		event.EventID = batch[i].ID
+	}
 	return nil
 }
 
@@ -121,10 +359,12 @@ func (s *eventService) GetUsageByMeter(ctx context.Context, req *dto.GetUsageByM
 		m = req.Meter
 	}
 
+	// Stored event names are normalized at ingestion, so the meter's name must be too
 	getUsageRequest := dto.GetUsageRequest{
 		ExternalCustomerID: req.ExternalCustomerID,
 		CustomerID:         req.CustomerID,
-		EventName:          m.EventName,
+		EventName:          eventNameNormalization(s.config, s.logger).Normalize(m.EventName),
+		EventNameMatch:     m.EventNameMatch,
 		PropertyName:       m.Aggregation.Field,
 		AggregationType:    m.Aggregation.Type,
 		StartTime:          req.StartTime,
@@ -136,9 +376,26 @@ func (s *eventService) GetUsageByMeter(ctx context.Context, req *dto.GetUsageByM
 		BillingAnchor:      req.BillingAnchor,
 	}
 
-	// Pass the multiplier from meter configuration if it's a SUM_WITH_MULTIPLIER aggregation
+	// Pass the multiplier and rate table from meter configuration if it's a SUM_WITH_MULTIPLIER aggregation
 	if m.Aggregation.Type == types.AggregationSumWithMultiplier {
 		getUsageRequest.Multiplier = m.Aggregation.Multiplier
+		getUsageRequest.RateProperty = m.Aggregation.RateProperty
+		getUsageRequest.RateTable = m.Aggregation.RateTable
+	}
+
+	// WEIGHTED_SUM weighs the values over the period, a period without a duration can't weigh them
+	if m.Aggregation.Type == types.AggregationWeightedSum {
+		if err := types.ValidatePeriodDuration(req.StartTime, req.EndTime); err != nil {
+			return nil, err
+		}
+	}
+
+	// Match the event names regardless of case when the tenant's event config sets it
+	getUsageRequest.CaseInsensitiveEventName = s.eventConfig(ctx).CaseInsensitiveEventNames
+
+	// Pass the heartbeat interval from meter configuration if it's an UPTIME aggregation
+	if m.Aggregation.Type == types.AggregationUptime {
+		getUsageRequest.HeartbeatInterval = m.Aggregation.HeartbeatIntervalSeconds
 	}
 
 	// Pass the bucket_size from meter configuration if it's a MAX aggregation with bucket_size set
@@ -146,6 +403,35 @@ func (s *eventService) GetUsageByMeter(ctx context.Context, req *dto.GetUsageByM
 		getUsageRequest.BucketSize = m.Aggregation.BucketSize
 	}
 
+	// Events routed to another subscription of the customer aren't billed to this one
+	if property := s.config.FeatureUsageTracking.SubscriptionIDProperty; property != "" && req.SubscriptionID != "" {
+		getUsageRequest.SubscriptionID = req.SubscriptionID
+		getUsageRequest.SubscriptionIDProperty = property
+	}
+
+	// Bill the values below the meter's minimum and above its maximum as feature usage counts them
+	if m.Aggregation.MinEventValue != nil && m.Aggregation.Field != "" {
+		getUsageRequest.MinEventValue = m.Aggregation.MinEventValue
+	}
+	if m.Aggregation.MaxEventValue != nil && m.Aggregation.Field != "" {
+		getUsageRequest.MaxEventValue = m.Aggregation.MaxEventValue
+		getUsageRequest.MaxEventValuePolicy = maxEventValuePolicy(s.config, s.logger)
+	}
+
+	// Skip or clamp the events timestamped before the customer was created as feature usage does,
+	// the clamped events are billed in the period of the customer creation or subscription start
+	if !req.CustomerCreatedAt.IsZero() {
+		if policy := preCustomerCreationPolicy(s.config, s.logger); policy != types.PreCustomerCreationEventPolicyMatch {
+			createdAt, clampedAt := req.CustomerCreatedAt, req.CustomerCreatedAt
+			if req.SubscriptionStartDate.After(clampedAt) {
+				clampedAt = req.SubscriptionStartDate
+			}
+			getUsageRequest.CustomerCreatedAt = &createdAt
+			getUsageRequest.PreCustomerCreationPolicy = policy
+			getUsageRequest.PreCustomerCreationClampedAt = &clampedAt
+		}
+	}
+
 	usage, err := s.GetUsage(ctx, &getUsageRequest)
 	if err != nil {
 		return nil, err
@@ -346,7 +632,8 @@ func (s *eventService) GetUsageByMeterWithFilters(ctx context.Context, req *dto.
 
 	params := &events.UsageWithFiltersParams{
 		UsageParams: &events.UsageParams{
-			EventName:          m.EventName,
+			EventName:          eventNameNormalization(s.config, s.logger).Normalize(m.EventName),
+			EventNameMatch:     m.EventNameMatch,
 			PropertyName:       m.Aggregation.Field,
 			AggregationType:    m.Aggregation.Type,
 			ExternalCustomerID: req.ExternalCustomerID,
@@ -356,6 +643,7 @@ func (s *eventService) GetUsageByMeterWithFilters(ctx context.Context, req *dto.
 		},
 		FilterGroups: prioritizedGroups,
 	}
+	params.UsageParams.CaseInsensitiveEventName = s.eventConfig(ctx).CaseInsensitiveEventNames
 
 	results, err := s.eventRepo.GetUsageWithFilters(ctx, params)
 	if err != nil {
@@ -539,86 +827,86 @@ func createEventIteratorKey(timestamp time.Time, id string) string {
 	return fmt.Sprintf("%d::%s", timestamp.UnixNano(), id)
 }
 
-// MonitorKafkaLag monitors Kafka consumer lag for event consumption and post-processing pipelines.
-// It creates Sentry monitoring spans to track lag metrics for alerting and observability.
-func (s *eventService) MonitorKafkaLag(ctx context.Context) error {
+// MonitorKafkaLag monitors the lag of every configured Kafka consumer group, normal, backfill, lazy
+// and replay. It creates Sentry monitoring spans to track lag metrics for alerting and observability
+// and returns the lags.
+func (s *eventService) MonitorKafkaLag(ctx context.Context) (*dto.KafkaLagMonitoringResponse, error) {
 	sentrySvc := sentry.NewSentryService(s.config, s.logger)
-	kafkaMonitoring := kafka.NewMonitoringService(s.config, s.logger)
 
-	// Get Kafka configuration for the current tenant
-	eventConsumptionTopic, eventConsumptionConsumerGroup, eventPostProcessingTopic, eventPostProcessingConsumerGroup := s.getKafkaConsumerConfig(ctx)
+	lags := s.consumerGroupLags(ctx)
+	response := &dto.KafkaLagMonitoringResponse{
+		ConsumerGroups: make([]dto.ConsumerGroupLag, 0, len(lags)),
+	}
 
-	// Monitor event consumption pipeline lag
-	if err := s.monitorConsumerLag(
-		ctx,
-		sentrySvc,
-		kafkaMonitoring,
-		eventConsumptionTopic,
-		eventConsumptionConsumerGroup,
-		"kafka.lag.event_consumption",
-	); err != nil {
-		s.logger.Warnw("failed to monitor event consumption lag",
-			"error", err,
-			"topic", eventConsumptionTopic,
-			"consumer_group", eventConsumptionConsumerGroup)
+	for _, lag := range lags {
+		item := dto.ConsumerGroupLag{
+			Pipeline:      lag.Pipeline,
+			Kind:          lag.Kind,
+			Topic:         lag.Topic,
+			ConsumerGroup: lag.ConsumerGroup.ConsumerGroup,
+			TotalLag:      lag.TotalLag,
+			PartitionLags: lag.PartitionLags,
+		}
+
+		if lag.Error != nil {
+			item.Error = lag.Error.Error()
+			response.ConsumerGroups = append(response.ConsumerGroups, item)
+			s.logger.Warnw("failed to monitor consumer lag",
+				"error", lag.Error,
+				"pipeline", lag.Pipeline,
+				"kind", lag.Kind,
+				"topic", lag.Topic,
+				"consumer_group", lag.ConsumerGroup.ConsumerGroup)
+			continue
+		}
+
+		response.ConsumerGroups = append(response.ConsumerGroups, item)
+		s.reportConsumerLag(ctx, sentrySvc, lag)
 	}
 
-	// Monitor event post-processing pipeline lag
-	if err := s.monitorConsumerLag(
-		ctx,
-		sentrySvc,
-		kafkaMonitoring,
-		eventPostProcessingTopic,
-		eventPostProcessingConsumerGroup,
-		"kafka.lag.event_post_processing",
-	); err != nil {
-		s.logger.Warnw("failed to monitor event post-processing lag",
-			"error", err,
-			"topic", eventPostProcessingTopic,
-			"consumer_group", eventPostProcessingConsumerGroup)
+	return response, nil
+}
+
+// consumerGroupLags returns the lag of the Kafka consumer groups configured for lag reporting.
+// A consumer group whose lag can't be queried is returned with its error.
+func (s *eventService) consumerGroupLags(ctx context.Context) []*kafka.ConsumerGroupLag {
+	for _, kind := range s.config.Kafka.LagReportingKinds {
+		if err := kind.Validate(); err != nil {
+			s.logger.Warnw("ignoring invalid consumer group kind for lag reporting", "kind", kind)
+		}
 	}
 
-	return nil
+	return kafka.GetConsumerGroupLags(ctx, s.lagQuerier, kafka.ConfiguredConsumerGroups(s.config))
 }
 
-// monitorConsumerLag retrieves and reports consumer lag metrics for a specific Kafka topic and consumer group.
-// It creates a Sentry monitoring span with lag details for observability.
-func (s *eventService) monitorConsumerLag(
-	ctx context.Context,
-	sentrySvc *sentry.Service,
-	kafkaMonitoring *kafka.MonitoringService,
-	topic string,
-	consumerGroup string,
-	spanName string,
-) error {
-	// Retrieve consumer lag metrics
-	lag, err := kafkaMonitoring.GetConsumerLag(ctx, topic, consumerGroup)
-	if err != nil {
-		return err
+// reportConsumerLag reports the lag of a consumer group in a Sentry monitoring span. The span of
+// a normal consumer group is named after its pipeline, e.g. kafka.lag.event_consumption, the
+// others are suffixed by their kind, e.g. kafka.lag.event_consumption.backfill.
+func (s *eventService) reportConsumerLag(ctx context.Context, sentrySvc *sentry.Service, lag *kafka.ConsumerGroupLag) {
+	spanName := "kafka.lag." + lag.Pipeline
+	if lag.Kind != types.ConsumerGroupKindNormal {
+		spanName += "." + lag.Kind.String()
 	}
 
 	// Create monitoring span for lag tracking
 	spanParams := map[string]interface{}{
-		"topic":          topic,
-		"consumer_group": consumerGroup,
+		"topic":          lag.Topic,
+		"consumer_group": lag.ConsumerGroup.ConsumerGroup,
+		"kind":           lag.Kind.String(),
 		"total_lag":      lag.TotalLag,
 	}
 
-	span, spanCtx := sentrySvc.StartKafkaLagMonitoringSpan(ctx, spanName, spanParams)
+	span, _ := sentrySvc.StartKafkaLagMonitoringSpan(ctx, spanName, spanParams)
 	if span != nil {
 		defer span.Finish()
 	}
 
 	s.logger.Infow("kafka lag monitored",
-		"topic", topic,
-		"consumer_group", consumerGroup,
+		"topic", lag.Topic,
+		"consumer_group", lag.ConsumerGroup.ConsumerGroup,
+		"kind", lag.Kind,
 		"total_lag", lag.TotalLag,
 		"span_name", spanName)
-
-	// Use the spanCtx to ensure proper context propagation
-	_ = spanCtx
-
-	return nil
 }
 
 func (s *eventService) GetMonitoringData(ctx context.Context, req *dto.GetMonitoringDataRequest) (*dto.GetMonitoringDataResponse, error) {
@@ -742,3 +1030,80 @@ func (s *eventService) getKafkaConsumerConfig(ctx context.Context) (
 
 	return eventConsumptionTopic, eventConsumptionConsumerGroup, eventPostProcessingTopic, eventPostProcessingConsumerGroup
 }
+
+// maxClockSkewProducers caps the number of producers reported by the clock skew diagnostic
+const maxClockSkewProducers = 1000
+
+// maxClockSkew bounds the timestamps scanned by the clock skew diagnostic around the ingestion
+// window, the events lagging further are backfills rather than a skewed clock
+const maxClockSkew = 7 * 24 * time.Hour
+
+// GetClockSkew reports the distribution of the delay between the timestamp and the ingestion of the
+// events of each customer and source. A producer is flagged ahead when nearly all of its events are
+// timestamped in the future by more than the threshold. The delay also includes the time the event
+// took to be ingested and the events sent late on purpose, such as backfills, so a producer is only
+// flagged behind when nearly all of its events lag by more than the threshold.
+func (s *eventService) GetClockSkew(ctx context.Context, req *dto.GetClockSkewRequest) (*dto.GetClockSkewResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.eventRepo.GetClockSkewStats(ctx, &events.ClockSkewParams{
+		StartTime:          req.StartTime,
+		EndTime:            req.EndTime,
+		ExternalCustomerID: req.ExternalCustomerID,
+		Source:             req.Source,
+		MaxSkew:            maxClockSkew,
+		MinEvents:          req.MinEvents,
+		Limit:              maxClockSkewProducers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := time.Duration(req.ThresholdSeconds) * time.Second
+	response := &dto.GetClockSkewResponse{
+		ThresholdSeconds: req.ThresholdSeconds,
+		Items:            make([]*dto.ClockSkewItem, 0, len(stats)),
+	}
+	for _, stat := range stats {
+		item := &dto.ClockSkewItem{
+			ExternalCustomerID: stat.ExternalCustomerID,
+			Source:             stat.Source,
+			EventCount:         stat.EventCount,
+			AvgSkewSeconds:     stat.Avg.Seconds(),
+			MinSkewSeconds:     stat.Min.Seconds(),
+			P10SkewSeconds:     stat.P10.Seconds(),
+			MedianSkewSeconds:  stat.P50.Seconds(),
+			P90SkewSeconds:     stat.P90.Seconds(),
+			MaxSkewSeconds:     stat.Max.Seconds(),
+		}
+
+		switch {
+		case stat.P90 <= -threshold:
+			item.Skewed = true
+			item.Direction = types.ClockSkewDirectionAhead
+		case stat.P10 >= threshold:
+			item.Skewed = true
+			item.Direction = types.ClockSkewDirectionBehind
+		}
+
+		if item.Skewed {
+			s.logger.Warnw("event producer clock is skewed",
+				"external_customer_id", stat.ExternalCustomerID,
+				"source", stat.Source,
+				"direction", item.Direction,
+				"median_skew", stat.P50.String(),
+				"event_count", stat.EventCount,
+			)
+		}
+		response.Items = append(response.Items, item)
+	}
+
+	// the skewed producers first, the repository orders by the median skew within each group
+	sort.SliceStable(response.Items, func(i, j int) bool {
+		return response.Items[i].Skewed && !response.Items[j].Skewed
+	})
+
+	return response, nil
+}
@@ -521,6 +521,9 @@ func (s *eventPostProcessingService) prepareProcessedEvents(ctx context.Context,
 			featureMeterMap[f.MeterID] = f
 		}
 	}
+	// Resolve how event names are matched to meters for the tenant
+	eventConfig := getEventConfig(ctx, s.ServiceParams)
+
 	// Process the event against each subscription
 	processedEventsPerSub := make([]*events.ProcessedEvent, 0)
 
@@ -579,7 +582,7 @@ func (s *eventPostProcessingService) prepareProcessedEvents(ctx context.Context,
 		}
 
 		// Find meters and prices that match this event
-		matches := s.findMatchingPricesForEvent(event, prices, meterMap)
+		matches := s.findMatchingPricesForEvent(event, prices, meterMap, eventConfig.CaseInsensitiveEventNames)
 
 		if len(matches) == 0 {
 			s.Logger.Debugw("no matching prices/meters found for subscription",
@@ -590,7 +593,33 @@ func (s *eventPostProcessingService) prepareProcessedEvents(ctx context.Context,
 			continue
 		}
 
+		// The status of the subscription at the event, e.g. trialing for usage before the trial
+		// ended even when the subscription is active by now
+		meteringStatus := sub.SubscriptionStatus
+		if gatedBySubscriptionStatus(matches) {
+			meteringStatus, err = meteringSubscriptionStatus(ctx, s.ServiceParams, sub, event.Timestamp)
+			if err != nil {
+				s.Logger.Errorw("failed to get subscription status at event",
+					"event_id", event.ID,
+					"subscription_id", sub.ID,
+					"error", err,
+				)
+				return results, err
+			}
+		}
+
 		for _, match := range matches {
+			// Skip meters that don't record usage in the subscription's status at the event
+			if !match.Meter.CountsInSubscriptionStatus(meteringStatus) {
+				s.Logger.Debugw("meter does not count usage in subscription status",
+					"event_id", event.ID,
+					"subscription_id", sub.ID,
+					"meter_id", match.Meter.ID,
+					"subscription_status", meteringStatus,
+				)
+				continue
+			}
+
 			// Find the corresponding line item
 			lineItem, ok := subLineItemMap[match.Price.ID]
 			if !ok {
@@ -723,8 +752,10 @@ func (s *eventPostProcessingService) findMatchingPricesForEvent(
 	event *events.Event,
 	prices []*price.Price,
 	meterMap map[string]*meter.Meter,
+	caseInsensitiveEventNames bool,
 ) []PriceMatch {
 	matches := make([]PriceMatch, 0)
+	normalization := eventNameNormalization(s.Config, s.Logger)
 
 	// Find prices with associated meters
 	for _, price := range prices {
@@ -743,7 +774,7 @@ func (s *eventPostProcessingService) findMatchingPricesForEvent(
 		}
 
 		// Skip if meter doesn't match the event name
-		if meter.EventName != event.EventName {
+		if !meter.MatchesEventName(event.EventName, caseInsensitiveEventNames, normalization) {
 			continue
 		}
 
@@ -917,6 +948,19 @@ func (s *eventPostProcessingService) extractQuantityFromEvent(
 			return decimal.Zero, stringValue
 		}
 
+		// Values outside the storable range would fail the insert of the whole batch
+		if decimalValue.Abs().GreaterThanOrEqual(maxStorableQuantity) {
+			s.Logger.Warnw("aggregation value out of storable range, skipping",
+				"event_id", event.ID,
+				"meter_id", meter.ID,
+				"field", meter.Aggregation.Field,
+				"value", stringValue,
+				"max_quantity", maxStorableQuantity.String(),
+				"reason", "quantity_out_of_range",
+			)
+			return decimal.Zero, stringValue
+		}
+
 		return decimalValue, stringValue
 
 	default:
@@ -1019,6 +1063,7 @@ func (s *eventPostProcessingService) GetDetailedUsageAnalytics(ctx context.Conte
 		ExternalCustomerID: req.ExternalCustomerID,
 		FeatureIDs:         req.FeatureIDs,
 		Sources:            req.Sources,
+		PriceIDs:           req.PriceIDs,
 		StartTime:          req.StartTime,
 		EndTime:            req.EndTime,
 		GroupBy:            req.GroupBy,
@@ -1247,11 +1292,22 @@ func (s *eventPostProcessingService) ReprocessEvents(ctx context.Context, params
 }
 
 // isSubscriptionValidForEvent checks if a subscription is valid for processing the given event
-// It ensures the event timestamp falls within the subscription's active period
+// It ensures the subscription is metering usage and the event timestamp falls within the
+// subscription's active period
 func (s *eventPostProcessingService) isSubscriptionValidForEvent(
 	sub *dto.SubscriptionResponse,
 	event *events.Event,
 ) bool {
+	// Frozen subscriptions stay active but don't meter usage
+	if sub.UsageFrozen {
+		s.Logger.Debugw("subscription usage is frozen, skipping event",
+			"event_id", event.ID,
+			"subscription_id", sub.ID,
+			"reason", "usage_frozen",
+		)
+		return false
+	}
+
 	// Event must be after subscription start date
 	if event.Timestamp.Before(sub.StartDate) {
 		s.Logger.Debugw("event timestamp before subscription start date",
@@ -1274,14 +1330,17 @@ func (s *eventPostProcessingService) isSubscriptionValidForEvent(
 		return false
 	}
 
-	// Additional check: if subscription is cancelled, make sure event is before cancellation
+	// Additional check: if subscription is cancelled, make sure event is before cancellation,
+	// events exactly at the cancellation are billed depending on the configured boundary
 	if sub.SubscriptionStatus == types.SubscriptionStatusCancelled && sub.CancelledAt != nil {
-		if event.Timestamp.After(*sub.CancelledAt) {
+		boundary := cancellationBoundary(s.ServiceParams)
+		if !boundary.Covers(event.Timestamp, *sub.CancelledAt) {
 			s.Logger.Debugw("event timestamp after subscription cancellation date",
 				"event_id", event.ID,
 				"subscription_id", sub.ID,
 				"event_timestamp", event.Timestamp,
 				"subscription_cancelled_at", *sub.CancelledAt,
+				"cancellation_boundary", boundary,
 			)
 			return false
 		}
@@ -1309,6 +1368,7 @@ func (s *eventPostProcessingService) ToGetUsageAnalyticsResponseDTO(ctx context.
 			AggregationType: analytic.AggregationType,
 			TotalUsage:      analytic.TotalUsage,
 			TotalCost:       analytic.TotalCost,
+			EffectiveRate:   effectiveRate(analytic.TotalCost, analytic.TotalUsage),
 			Currency:        analytic.Currency,
 			EventCount:      analytic.EventCount,
 			Properties:      analytic.Properties,
@@ -1318,10 +1378,11 @@ func (s *eventPostProcessingService) ToGetUsageAnalyticsResponseDTO(ctx context.
 		// Map time-series points if available
 		for _, point := range analytic.Points {
 			item.Points = append(item.Points, dto.UsageAnalyticPoint{
-				Timestamp:  point.Timestamp,
-				Usage:      point.Usage,
-				Cost:       point.Cost,
-				EventCount: point.EventCount,
+				Timestamp:     point.Timestamp,
+				Usage:         point.Usage,
+				Cost:          point.Cost,
+				EffectiveRate: effectiveRate(point.Cost, point.Usage),
+				EventCount:    point.EventCount,
 			})
 		}
 
@@ -1 +1,56 @@
 package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/domain/meter"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventPostProcessingExtractQuantityOutOfRange(t *testing.T) {
+	s := &eventPostProcessingService{ServiceParams: ServiceParams{Logger: logger.GetLogger()}}
+	m := &meter.Meter{
+		ID:          "meter_tokens",
+		Aggregation: meter.Aggregation{Type: types.AggregationSum, Field: "tokens"},
+	}
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected decimal.Decimal
+	}{
+		{
+			name:     "largest_storable_value",
+			value:    "9999999999.999999999999999",
+			expected: decimal.RequireFromString("9999999999.999999999999999"),
+		},
+		{
+			name:     "uint64_above_range",
+			value:    uint64(math.MaxUint64),
+			expected: decimal.Zero,
+		},
+		{
+			name:     "negative_string_below_range",
+			value:    "-1e12",
+			expected: decimal.Zero,
+		},
+		{
+			name:     "float_above_range",
+			value:    float64(1e20),
+			expected: decimal.Zero,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &events.Event{ID: "evt_range", Properties: map[string]interface{}{"tokens": tt.value}}
+			value, _ := s.extractQuantityFromEvent(event, m)
+			assert.True(t, tt.expected.Equal(value), "value: %s", value)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/config"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/types"
+	"golang.org/x/time/rate"
+)
+
+// maxIdleEventRateLimiters is the number of tracked customers beyond which the limiters of the
+// customers that stopped ingesting are dropped
+const maxIdleEventRateLimiters = 10000
+
+// eventRateLimiter limits the rate at which each customer ingests events with a token bucket
+// per customer of a tenant environment
+type eventRateLimiter struct {
+	config config.EventRateLimitConfig
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newEventRateLimiter(cfg config.EventRateLimitConfig) *eventRateLimiter {
+	return &eventRateLimiter{
+		config:   cfg,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// Allow takes the events of each customer, keyed by external customer ID, from its limit. The
+// events are all taken or none of them, and when a customer exceeds its limit a too many
+// requests error with the seconds to wait before retrying is returned.
+func (l *eventRateLimiter) Allow(ctx context.Context, eventsByCustomer map[string]int) error {
+	if l == nil || !l.config.Enabled {
+		return nil
+	}
+
+	tenantID := types.GetTenantID(ctx)
+	environmentID := types.GetEnvironmentID(ctx)
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	reservations := make([]*rate.Reservation, 0, len(eventsByCustomer))
+	for externalCustomerID, count := range eventsByCustomer {
+		limiter := l.limiter(tenantID, environmentID, externalCustomerID, now)
+		if limiter == nil {
+			continue
+		}
+
+		reservation := limiter.ReserveN(now, count)
+		delay := reservation.DelayFrom(now)
+		if reservation.OK() && delay == 0 {
+			reservations = append(reservations, reservation)
+			continue
+		}
+
+		for _, r := range append(reservations, reservation) {
+			r.CancelAt(now)
+		}
+
+		details := map[string]interface{}{
+			"external_customer_id": externalCustomerID,
+			"events":               count,
+			"events_per_second":    float64(limiter.Limit()),
+			"burst":                limiter.Burst(),
+		}
+		if !reservation.OK() {
+			// the events never fit in the bucket of the customer at once
+			return ierr.NewErrorf("customer %s ingested more events at once than its event ingestion rate limit allows", externalCustomerID).
+				WithHintf("At most %d events can be ingested at once for customer %s, please send them in smaller batches", limiter.Burst(), externalCustomerID).
+				WithReportableDetails(details).
+				Mark(ierr.ErrTooManyRequests)
+		}
+
+		retryAfter := int(math.Ceil(delay.Seconds()))
+		details["retry_after_seconds"] = retryAfter
+		return ierr.NewErrorf("customer %s exceeded its event ingestion rate limit", externalCustomerID).
+			WithHintf("Too many events are ingested for customer %s, please retry after %d seconds", externalCustomerID, retryAfter).
+			WithReportableDetails(details).
+			Mark(ierr.ErrTooManyRequests)
+	}
+	return nil
+}
+
+// limiter returns the limiter of the customer, nil when the customer is unlimited. It must be
+// called with the lock held.
+func (l *eventRateLimiter) limiter(tenantID, environmentID, externalCustomerID string, now time.Time) *rate.Limiter {
+	key := tenantID + ":" + environmentID + ":" + externalCustomerID
+	if limiter, ok := l.limiters[key]; ok {
+		return limiter
+	}
+
+	eventsPerSecond, burst := l.config.Limit(tenantID, externalCustomerID)
+	if eventsPerSecond <= 0 {
+		return nil
+	}
+
+	if len(l.limiters) >= maxIdleEventRateLimiters {
+		l.dropIdle(now)
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(eventsPerSecond), burst)
+	l.limiters[key] = limiter
+	return limiter
+}
+
+// dropIdle drops the limiters whose bucket refilled, a new limiter starts with a full bucket so
+// dropping them doesn't change the limits
+func (l *eventRateLimiter) dropIdle(now time.Time) {
+	for key, limiter := range l.limiters {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(l.limiters, key)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flexprice/flexprice/internal/config"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/testutil"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventRateLimiter(t *testing.T) {
+	ctx := testutil.SetupContext()
+	cfg := config.EventRateLimitConfig{
+		Enabled:         true,
+		EventsPerSecond: 1,
+		Burst:           3,
+		Overrides: []config.EventRateLimitOverride{
+			{ExternalCustomerID: "big_customer", EventsPerSecond: 10, Burst: 10},
+			{ExternalCustomerID: "unlimited_customer", EventsPerSecond: 0},
+			{TenantID: "other_tenant", ExternalCustomerID: "customer_1", EventsPerSecond: 1, Burst: 5},
+		},
+	}
+
+	t.Run("customer_exceeding_its_limit_is_rejected", func(t *testing.T) {
+		limiter := newEventRateLimiter(cfg)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, limiter.Allow(ctx, map[string]int{"customer_1": 1}))
+		}
+
+		err := limiter.Allow(ctx, map[string]int{"customer_1": 1})
+		require.Error(t, err)
+		assert.True(t, ierr.IsTooManyRequests(err))
+
+		// the other customers keep their own limit
+		assert.NoError(t, limiter.Allow(ctx, map[string]int{"customer_2": 3}))
+	})
+
+	t.Run("customer_is_limited_per_tenant_environment", func(t *testing.T) {
+		limiter := newEventRateLimiter(cfg)
+		require.NoError(t, limiter.Allow(ctx, map[string]int{"customer_1": 3}))
+
+		otherEnv := context.WithValue(ctx, types.CtxEnvironmentID, "env_production")
+		assert.NoError(t, limiter.Allow(otherEnv, map[string]int{"customer_1": 3}))
+
+		// the override of the other tenant raises its burst
+		otherTenant := context.WithValue(ctx, types.CtxTenantID, "other_tenant")
+		assert.NoError(t, limiter.Allow(otherTenant, map[string]int{"customer_1": 5}))
+	})
+
+	t.Run("overrides_replace_the_default_limit", func(t *testing.T) {
+		limiter := newEventRateLimiter(cfg)
+		assert.NoError(t, limiter.Allow(ctx, map[string]int{"big_customer": 10}))
+		assert.True(t, ierr.IsTooManyRequests(limiter.Allow(ctx, map[string]int{"big_customer": 1})))
+
+		for i := 0; i < 100; i++ {
+			require.NoError(t, limiter.Allow(ctx, map[string]int{"unlimited_customer": 10}))
+		}
+	})
+
+	t.Run("rejected_events_are_not_taken", func(t *testing.T) {
+		limiter := newEventRateLimiter(cfg)
+		require.NoError(t, limiter.Allow(ctx, map[string]int{"customer_2": 2}))
+
+		// customer_2 exceeds its limit so customer_1 keeps its events as well
+		err := limiter.Allow(ctx, map[string]int{"customer_1": 3, "customer_2": 2})
+		assert.True(t, ierr.IsTooManyRequests(err))
+		assert.NoError(t, limiter.Allow(ctx, map[string]int{"customer_1": 3}))
+		assert.NoError(t, limiter.Allow(ctx, map[string]int{"customer_2": 1}))
+	})
+
+	t.Run("events_beyond_the_burst_are_rejected", func(t *testing.T) {
+		limiter := newEventRateLimiter(cfg)
+		err := limiter.Allow(ctx, map[string]int{"customer_1": 4})
+		assert.True(t, ierr.IsTooManyRequests(err))
+		assert.NoError(t, limiter.Allow(ctx, map[string]int{"customer_1": 3}))
+	})
+
+	t.Run("disabled_limiter_allows_everything", func(t *testing.T) {
+		disabled := cfg
+		disabled.Enabled = false
+		limiter := newEventRateLimiter(disabled)
+		for i := 0; i < 10; i++ {
+			require.NoError(t, limiter.Allow(ctx, map[string]int{"customer_1": 3}))
+		}
+	})
+}
+
+func TestEventRateLimitConfig_Limit(t *testing.T) {
+	cfg := config.EventRateLimitConfig{
+		EventsPerSecond: 100,
+		Burst:           10,
+		Overrides: []config.EventRateLimitOverride{
+			{ExternalCustomerID: "customer_1", EventsPerSecond: 5, Burst: 50},
+			{TenantID: "tenant_1", ExternalCustomerID: "customer_1", EventsPerSecond: 7, Burst: 70},
+		},
+	}
+
+	eventsPerSecond, burst := cfg.Limit("tenant_2", "customer_2")
+	assert.Equal(t, float64(100), eventsPerSecond)
+	assert.Equal(t, 100, burst, "burst is raised to the events per second")
+
+	eventsPerSecond, burst = cfg.Limit("tenant_2", "customer_1")
+	assert.Equal(t, float64(5), eventsPerSecond)
+	assert.Equal(t, 50, burst)
+
+	eventsPerSecond, burst = cfg.Limit("tenant_1", "customer_1")
+	assert.Equal(t, float64(7), eventsPerSecond)
+	assert.Equal(t, 70, burst)
+
+	t.Run("limits_are_divided_between_replicas", func(t *testing.T) {
+		replicated := cfg
+		replicated.Replicas = 4
+
+		eventsPerSecond, burst := replicated.Limit("tenant_2", "customer_2")
+		assert.Equal(t, float64(25), eventsPerSecond)
+		assert.Equal(t, 25, burst, "burst is raised to the events per second of the replica")
+
+		eventsPerSecond, burst = replicated.Limit("tenant_1", "customer_1")
+		assert.Equal(t, 1.75, eventsPerSecond)
+		assert.Equal(t, 18, burst)
+	})
+}
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -9,9 +12,15 @@ import (
 	"github.com/flexprice/flexprice/internal/config"
 	"github.com/flexprice/flexprice/internal/domain/events"
 	"github.com/flexprice/flexprice/internal/domain/meter"
+	"github.com/flexprice/flexprice/internal/domain/settings"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/kafka"
 	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/metrics"
+	"github.com/flexprice/flexprice/internal/publisher"
 	"github.com/flexprice/flexprice/internal/testutil"
 	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/suite"
 )
@@ -152,6 +161,306 @@ func (s *EventServiceSuite) TestCreateEvent() {
 	}
 }
 
+func (s *EventServiceSuite) TestCreateEventRateLimit() {
+	s.config.Event.RateLimit = config.EventRateLimitConfig{
+		Enabled:         true,
+		EventsPerSecond: 1,
+		Burst:           2,
+		Overrides: []config.EventRateLimitOverride{
+			{ExternalCustomerID: "customer-bulk", EventsPerSecond: 1, Burst: 3},
+		},
+	}
+	service := NewEventService(s.eventRepo, nil, s.publisher, s.logger, s.config)
+	newRequest := func(id, customerID string) *dto.IngestEventRequest {
+		return &dto.IngestEventRequest{
+			EventID:            id,
+			ExternalCustomerID: customerID,
+			EventName:          "api_request",
+			Timestamp:          time.Now(),
+		}
+	}
+
+	s.Run("customer_exceeding_its_limit_is_rejected", func() {
+		s.NoError(service.CreateEvent(s.ctx, newRequest("evt_limit_1", "customer-1")))
+		s.NoError(service.CreateEvent(s.ctx, newRequest("evt_limit_2", "customer-1")))
+
+		err := service.CreateEvent(s.ctx, newRequest("evt_limit_3", "customer-1"))
+		s.Error(err)
+		s.True(ierr.IsTooManyRequests(err))
+		s.Equal(http.StatusTooManyRequests, ierr.HTTPStatusFromErr(err))
+		s.Len(s.publisher.GetEvents(), 2)
+
+		// another customer isn't affected
+		s.NoError(service.CreateEvent(s.ctx, newRequest("evt_limit_4", "customer-2")))
+	})
+
+	s.Run("bulk_request_exceeding_the_limit_ingests_no_events", func() {
+		s.publisher.Clear()
+		err := service.BulkCreateEvents(s.ctx, &dto.BulkIngestEventRequest{Events: []*dto.IngestEventRequest{
+			newRequest("evt_bulk_1", "customer-3"),
+			newRequest("evt_bulk_2", "customer-3"),
+			newRequest("evt_bulk_3", "customer-3"),
+		}})
+		s.True(ierr.IsTooManyRequests(err))
+		s.Empty(s.publisher.GetEvents())
+
+		// the override allows bursts of 3 events
+		s.NoError(service.BulkCreateEvents(s.ctx, &dto.BulkIngestEventRequest{Events: []*dto.IngestEventRequest{
+			newRequest("evt_bulk_4", "customer-bulk"),
+			newRequest("evt_bulk_5", "customer-bulk"),
+			newRequest("evt_bulk_6", "customer-bulk"),
+		}}))
+		s.Len(s.publisher.GetEvents(), 3)
+	})
+}
+
+func (s *EventServiceSuite) TestBulkCreateEventsBufferFull() {
+	buffered := publisher.NewBufferedEventPublisher(s.publisher, config.EventBufferConfig{
+		FlushSize:     2,
+		FlushInterval: time.Hour,
+		MaxBuffered:   2,
+	}, s.logger)
+	service := NewEventService(s.eventRepo, nil, buffered, s.logger, s.config)
+	newRequest := func(id string) *dto.IngestEventRequest {
+		return &dto.IngestEventRequest{
+			EventID:            id,
+			ExternalCustomerID: "customer-buffered",
+			EventName:          "api_request",
+			Timestamp:          time.Now(),
+		}
+	}
+
+	// the request doesn't fit in the buffer so none of its events are buffered
+	err := service.BulkCreateEvents(s.ctx, &dto.BulkIngestEventRequest{Events: []*dto.IngestEventRequest{
+		newRequest("evt_buffered_1"),
+		newRequest("evt_buffered_2"),
+		newRequest("evt_buffered_3"),
+	}})
+	s.True(ierr.IsTooManyRequests(err))
+
+	s.NoError(service.BulkCreateEvents(s.ctx, &dto.BulkIngestEventRequest{Events: []*dto.IngestEventRequest{
+		newRequest("evt_buffered_4"),
+		newRequest("evt_buffered_5"),
+	}}))
+	s.NoError(buffered.Close(s.ctx))
+
+	published := lo.Map(s.publisher.GetEvents(), func(event *events.Event, _ int) string { return event.ID })
+	s.ElementsMatch([]string{"evt_buffered_4", "evt_buffered_5"}, published)
+}
+
+func (s *EventServiceSuite) TestCreateEventNameNormalization() {
+	publishedEvent := func(id string) *events.Event {
+		for _, event := range s.publisher.GetEvents() {
+			if event.ID == id {
+				return event
+			}
+		}
+		return nil
+	}
+
+	testCases := []struct {
+		name                 string
+		normalization        types.EventNameNormalization
+		eventName            string
+		expectedEventName    string
+		expectedOriginalName interface{}
+		expectedError        bool
+	}{
+		{
+			name:              "none_keeps_name",
+			normalization:     types.EventNameNormalizationNone,
+			eventName:         " API_Request ",
+			expectedEventName: " API_Request ",
+		},
+		{
+			name:                 "trim_removes_whitespace",
+			normalization:        types.EventNameNormalizationTrim,
+			eventName:            " API_Request\n",
+			expectedEventName:    "API_Request",
+			expectedOriginalName: " API_Request\n",
+		},
+		{
+			name:                 "lowercase_trims_and_lowercases",
+			normalization:        types.EventNameNormalizationLowercase,
+			eventName:            " API_Request ",
+			expectedEventName:    "api_request",
+			expectedOriginalName: " API_Request ",
+		},
+		{
+			name:              "already_normalized_name_is_not_recorded",
+			normalization:     types.EventNameNormalizationLowercase,
+			eventName:         "api_request",
+			expectedEventName: "api_request",
+		},
+		{
+			name:          "whitespace_only_name_is_rejected",
+			normalization: types.EventNameNormalizationTrim,
+			eventName:     "   ",
+			expectedError: true,
+		},
+		{
+			name:              "invalid_normalization_keeps_name",
+			normalization:     "uppercase",
+			eventName:         " API_Request ",
+			expectedEventName: " API_Request ",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.config.Event.NameNormalization = tc.normalization
+			req := &dto.IngestEventRequest{
+				EventID:            "evt_normalize_" + tc.name,
+				ExternalCustomerID: "customer-1",
+				EventName:          tc.eventName,
+				Timestamp:          time.Now(),
+				Properties:         map[string]interface{}{"duration_ms": 150},
+			}
+
+			err := s.service.CreateEvent(s.ctx, req)
+			if tc.expectedError {
+				s.Error(err)
+				s.Nil(publishedEvent(req.EventID))
+				return
+			}
+			s.NoError(err)
+
+			event := publishedEvent(req.EventID)
+			s.Require().NotNil(event)
+			s.Equal(tc.expectedEventName, event.EventName)
+			s.Equal(tc.expectedOriginalName, event.Properties[events.OriginalEventNameProperty])
+			s.Equal(150, event.Properties["duration_ms"])
+		})
+	}
+}
+
+func (s *EventServiceSuite) TestCreateEventExternalCustomerIDProperty() {
+	publishedEvent := func(id string) *events.Event {
+		for _, event := range s.publisher.GetEvents() {
+			if event.ID == id {
+				return event
+			}
+		}
+		return nil
+	}
+	newRequest := func(id string) *dto.IngestEventRequest {
+		return &dto.IngestEventRequest{
+			EventID:    id,
+			EventName:  "api_request",
+			Timestamp:  time.Now(),
+			Properties: map[string]interface{}{"customer": map[string]interface{}{"id": "customer-nested"}},
+		}
+	}
+
+	s.Run("rejected_without_the_property_configured", func() {
+		err := s.service.CreateEvent(s.ctx, newRequest("evt_property_1"))
+		s.True(ierr.IsValidation(err))
+		s.Nil(publishedEvent("evt_property_1"))
+	})
+
+	s.config.FeatureUsageTracking.ExternalCustomerIDProperty = "customer.id"
+
+	s.Run("resolved_from_the_configured_property", func() {
+		s.NoError(s.service.CreateEvent(s.ctx, newRequest("evt_property_2")))
+		event := publishedEvent("evt_property_2")
+		s.Require().NotNil(event)
+		s.Equal("customer-nested", event.ExternalCustomerID)
+	})
+
+	s.Run("bulk_events_resolved_from_the_configured_property", func() {
+		s.NoError(s.service.BulkCreateEvents(s.ctx, &dto.BulkIngestEventRequest{Events: []*dto.IngestEventRequest{
+			newRequest("evt_property_3"),
+		}}))
+		event := publishedEvent("evt_property_3")
+		s.Require().NotNil(event)
+		s.Equal("customer-nested", event.ExternalCustomerID)
+	})
+
+	s.Run("rejected_when_the_property_is_missing", func() {
+		req := newRequest("evt_property_4")
+		req.Properties = map[string]interface{}{"duration_ms": 150}
+		err := s.service.CreateEvent(s.ctx, req)
+		s.True(ierr.IsValidation(err))
+		s.Nil(publishedEvent("evt_property_4"))
+	})
+}
+
+func (s *EventServiceSuite) TestCreateEventValueUnits() {
+	newMeter := func(id, eventName string, unit types.ValueUnit) *meter.Meter {
+		return &meter.Meter{
+			ID:        id,
+			Name:      id,
+			EventName: eventName,
+			Aggregation: meter.Aggregation{
+				Type:  types.AggregationSum,
+				Field: "size",
+				Unit:  unit,
+			},
+			ResetUsage: types.ResetUsageBillingPeriod,
+			BaseModel: types.BaseModel{
+				TenantID: types.GetTenantID(s.ctx),
+				Status:   types.StatusPublished,
+			},
+			EnvironmentID: types.GetEnvironmentID(s.ctx),
+		}
+	}
+	meterRepo := testutil.NewInMemoryMeterStore()
+	s.NoError(meterRepo.CreateMeter(s.ctx, newMeter("meter-upload-mb", "upload", types.ValueUnitMegabyte)))
+	s.NoError(meterRepo.CreateMeter(s.ctx, newMeter("meter-backup-mb", "backup", types.ValueUnitMegabyte)))
+	s.NoError(meterRepo.CreateMeter(s.ctx, newMeter("meter-backup-gb", "backup", types.ValueUnitGigabyte)))
+	s.service = NewEventService(s.eventRepo, meterRepo, s.publisher, s.logger, s.config)
+
+	ingest := func(eventName string, size interface{}) *events.Event {
+		eventID := types.GenerateUUIDWithPrefix(types.UUID_PREFIX_EVENT)
+		s.Require().NoError(s.service.CreateEvent(s.ctx, &dto.IngestEventRequest{
+			EventID:            eventID,
+			EventName:          eventName,
+			ExternalCustomerID: "cust-units",
+			Timestamp:          time.Now().Add(-time.Minute),
+			Properties:         map[string]interface{}{"size": size},
+		}))
+		for _, event := range s.publisher.GetEvents() {
+			if event.ID == eventID {
+				return event
+			}
+		}
+		s.FailNow("event not published", eventID)
+		return nil
+	}
+
+	s.Run("values_with_a_unit_are_converted_to_the_meter_unit", func() {
+		event := ingest("upload", "1.5GB")
+		s.Equal(1500.0, event.Properties["size"])
+		s.Equal(map[string]interface{}{"size": "1.5GB"}, event.Properties[events.OriginalValuesProperty])
+
+		s.NoError(s.eventRepo.InsertEvent(s.ctx, event))
+		result, err := s.service.GetUsageByMeter(s.ctx, &dto.GetUsageByMeterRequest{
+			MeterID:            "meter-upload-mb",
+			ExternalCustomerID: "cust-units",
+			StartTime:          time.Now().Add(-time.Hour),
+			EndTime:            time.Now(),
+		})
+		s.Require().NoError(err)
+		s.True(decimal.NewFromInt(1500).Equal(result.Value), result.Value.String())
+	})
+
+	s.Run("plain_and_unparsable_values_are_kept", func() {
+		event := ingest("upload", 42)
+		s.Equal(42, event.Properties["size"])
+		s.NotContains(event.Properties, events.OriginalValuesProperty)
+
+		event = ingest("upload", "1.5 parsecs")
+		s.Equal("1.5 parsecs", event.Properties["size"])
+		s.NotContains(event.Properties, events.OriginalValuesProperty)
+	})
+
+	s.Run("values_of_a_field_aggregated_in_different_units_are_kept", func() {
+		event := ingest("backup", "2GB")
+		s.Equal("2GB", event.Properties["size"])
+		s.NotContains(event.Properties, events.OriginalValuesProperty)
+	})
+}
+
 func (s *EventServiceSuite) TestGetUsage() {
 	// Setup test data with properties for filtering
 	testingEvents := []*dto.IngestEventRequest{
@@ -303,6 +612,18 @@ func (s *EventServiceSuite) TestGetUsage() {
 			expectedValue: decimal.NewFromFloat(15), // Should still return the overall maximum
 			expectedError: false,
 		},
+		{
+			name: "count_once_per_period",
+			request: &dto.GetUsageRequest{
+				ExternalCustomerID: "cust-1",
+				EventName:          "api_request",
+				AggregationType:    types.AggregationCountOncePerPeriod,
+				StartTime:          time.Now().Add(-2 * time.Hour),
+				EndTime:            time.Now(),
+			},
+			expectedValue: decimal.NewFromFloat(1), // The customer is counted once however many events it sent
+			expectedError: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -442,6 +763,459 @@ func (s *EventServiceSuite) TestGetUsageByMeter() {
 	s.Equal(types.AggregationSum, result.Type)
 }
 
+func (s *EventServiceSuite) TestGetUsageByMeterUptime() {
+	uptimeMeter := &meter.Meter{
+		ID:        "meter-uptime",
+		Name:      "Uptime",
+		EventName: "heartbeat",
+		Aggregation: meter.Aggregation{
+			Type:                     types.AggregationUptime,
+			HeartbeatIntervalSeconds: 60,
+		},
+		ResetUsage: types.ResetUsageBillingPeriod,
+		BaseModel: types.BaseModel{
+			TenantID: types.GetTenantID(s.ctx),
+		},
+	}
+	meterRepo := testutil.NewInMemoryMeterStore()
+	s.NoError(meterRepo.CreateMeter(s.ctx, uptimeMeter))
+	s.service = NewEventService(s.eventRepo, meterRepo, s.publisher, s.logger, s.config)
+
+	start := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	// Heartbeats in the first, second and fourth minute, the second one of the first minute counts once
+	for i, offset := range []time.Duration{0, 10 * time.Second, 70 * time.Second, 200 * time.Second} {
+		event := events.NewEvent("heartbeat", types.GetTenantID(s.ctx), "cust-uptime", nil, start.Add(offset),
+			fmt.Sprintf("evt-heartbeat-%d", i), "", "", types.GetEnvironmentID(s.ctx))
+		s.NoError(s.eventRepo.InsertEvent(s.ctx, event))
+	}
+
+	result, err := s.service.GetUsageByMeter(s.ctx, &dto.GetUsageByMeterRequest{
+		MeterID:            uptimeMeter.ID,
+		ExternalCustomerID: "cust-uptime",
+		StartTime:          start,
+		EndTime:            start.Add(time.Hour),
+	})
+	s.NoError(err)
+	s.Equal(types.AggregationUptime, result.Type)
+	s.True(decimal.NewFromInt(180).Equal(result.Value), result.Value.String())
+}
+
+func (s *EventServiceSuite) TestGetUsageByMeterCounterDelta() {
+	counterMeter := &meter.Meter{
+		ID:        "meter-counter",
+		Name:      "Bytes Sent",
+		EventName: "bytes_counter",
+		Aggregation: meter.Aggregation{
+			Type:  types.AggregationCounterDelta,
+			Field: "bytes",
+		},
+		ResetUsage: types.ResetUsageBillingPeriod,
+		BaseModel: types.BaseModel{
+			TenantID: types.GetTenantID(s.ctx),
+		},
+	}
+	meterRepo := testutil.NewInMemoryMeterStore()
+	s.NoError(meterRepo.CreateMeter(s.ctx, counterMeter))
+	s.service = NewEventService(s.eventRepo, meterRepo, s.publisher, s.logger, s.config)
+
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	// The reading before the period is the previous reading of the first one, 20 is a counter reset
+	for i, reading := range []float64{100, 130, 150, 20, 50} {
+		event := events.NewEvent("bytes_counter", types.GetTenantID(s.ctx), "cust-counter",
+			map[string]interface{}{"bytes": reading}, start.Add(time.Duration(i-1)*time.Hour),
+			fmt.Sprintf("evt-counter-%d", i), "", "", types.GetEnvironmentID(s.ctx))
+		s.NoError(s.eventRepo.InsertEvent(s.ctx, event))
+	}
+
+	result, err := s.service.GetUsageByMeter(s.ctx, &dto.GetUsageByMeterRequest{
+		MeterID:            counterMeter.ID,
+		ExternalCustomerID: "cust-counter",
+		StartTime:          start,
+		EndTime:            start.Add(24 * time.Hour),
+	})
+	s.NoError(err)
+	s.Equal(types.AggregationCounterDelta, result.Type)
+	// 30 + 20 + 20 + 30
+	s.True(decimal.NewFromInt(100).Equal(result.Value), result.Value.String())
+}
+
+func (s *EventServiceSuite) TestGetUsageByMeterNormalizedEventName() {
+	s.config.Event.NameNormalization = types.EventNameNormalizationLowercase
+	apiMeter := &meter.Meter{
+		ID:        "meter-normalized",
+		Name:      "API Requests",
+		EventName: " API_Request",
+		Aggregation: meter.Aggregation{
+			Type: types.AggregationCount,
+		},
+		ResetUsage: types.ResetUsageBillingPeriod,
+		BaseModel: types.BaseModel{
+			TenantID: types.GetTenantID(s.ctx),
+		},
+	}
+	meterRepo := testutil.NewInMemoryMeterStore()
+	s.NoError(meterRepo.CreateMeter(s.ctx, apiMeter))
+	s.service = NewEventService(s.eventRepo, meterRepo, s.publisher, s.logger, s.config)
+
+	start := time.Now().Add(-time.Hour)
+	for i, eventName := range []string{"API_Request", "api_request "} {
+		s.NoError(s.service.CreateEvent(s.ctx, &dto.IngestEventRequest{
+			EventID:            fmt.Sprintf("evt-normalized-%d", i),
+			ExternalCustomerID: "cust-normalized",
+			EventName:          eventName,
+			Timestamp:          start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	for _, event := range s.publisher.GetEvents() {
+		s.NoError(s.eventRepo.InsertEvent(s.ctx, event))
+	}
+
+	result, err := s.service.GetUsageByMeter(s.ctx, &dto.GetUsageByMeterRequest{
+		MeterID:            apiMeter.ID,
+		ExternalCustomerID: "cust-normalized",
+		StartTime:          start.Add(-time.Minute),
+		EndTime:            start.Add(time.Hour),
+	})
+	s.NoError(err)
+	s.Equal("api_request", result.EventName)
+	s.True(decimal.NewFromInt(2).Equal(result.Value), result.Value.String())
+}
+
+func (s *EventServiceSuite) TestGetUsageByMeterCaseInsensitiveEventName() {
+	apiMeter := &meter.Meter{
+		ID:        "meter-case",
+		Name:      "API Calls",
+		EventName: "api_call",
+		Aggregation: meter.Aggregation{
+			Type: types.AggregationCount,
+		},
+		ResetUsage: types.ResetUsageBillingPeriod,
+		BaseModel: types.BaseModel{
+			TenantID: types.GetTenantID(s.ctx),
+		},
+	}
+	meterRepo := testutil.NewInMemoryMeterStore()
+	s.NoError(meterRepo.CreateMeter(s.ctx, apiMeter))
+	settingsRepo := testutil.NewInMemorySettingsStore()
+	service := NewEventService(s.eventRepo, meterRepo, s.publisher, s.logger, s.config).(*eventService)
+	service.settingsRepo = settingsRepo
+
+	start := time.Now().Add(-time.Hour)
+	for i, eventName := range []string{"api_call", "API_Call", "api_call_v2"} {
+		s.NoError(s.eventRepo.InsertEvent(s.ctx, &events.Event{
+			ID:                 fmt.Sprintf("evt-case-%d", i),
+			TenantID:           types.GetTenantID(s.ctx),
+			EnvironmentID:      types.GetEnvironmentID(s.ctx),
+			EventName:          eventName,
+			ExternalCustomerID: "cust-case",
+			Timestamp:          start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	usage := func() decimal.Decimal {
+		result, err := service.GetUsageByMeter(s.ctx, &dto.GetUsageByMeterRequest{
+			MeterID:            apiMeter.ID,
+			ExternalCustomerID: "cust-case",
+			StartTime:          start.Add(-time.Minute),
+			EndTime:            start.Add(time.Hour),
+		})
+		s.Require().NoError(err)
+		return result.Value
+	}
+
+	s.Run("case_sensitive_by_default", func() {
+		s.True(decimal.NewFromInt(1).Equal(usage()))
+	})
+
+	s.Run("case_insensitive_when_enabled", func() {
+		s.NoError(settingsRepo.Create(s.ctx, &settings.Setting{
+			ID:  types.GenerateUUIDWithPrefix(types.UUID_PREFIX_SETTING),
+			Key: string(types.SettingKeyEventConfig),
+			Value: map[string]interface{}{
+				"case_insensitive_event_names": true,
+			},
+			EnvironmentID: types.GetEnvironmentID(s.ctx),
+			BaseModel:     types.GetDefaultBaseModel(s.ctx),
+		}))
+		s.True(decimal.NewFromInt(2).Equal(usage()))
+	})
+}
+
+func (s *EventServiceSuite) TestGetUsageByMeterMaxEventValue() {
+	maxEventValue := decimal.NewFromInt(1000)
+	tokensMeter := &meter.Meter{
+		ID:        "meter-max-event-value",
+		Name:      "Tokens",
+		EventName: "tokens_used",
+		Aggregation: meter.Aggregation{
+			Type:          types.AggregationSum,
+			Field:         "tokens",
+			MaxEventValue: &maxEventValue,
+		},
+		ResetUsage: types.ResetUsageBillingPeriod,
+		BaseModel: types.BaseModel{
+			TenantID: types.GetTenantID(s.ctx),
+		},
+	}
+	meterRepo := testutil.NewInMemoryMeterStore()
+	s.NoError(meterRepo.CreateMeter(s.ctx, tokensMeter))
+
+	start := time.Now().Add(-time.Hour)
+	for i, tokens := range []float64{100, 200, 5000} {
+		event := events.NewEvent("tokens_used", types.GetTenantID(s.ctx), "cust-max-event-value",
+			map[string]interface{}{"tokens": tokens}, start.Add(time.Duration(i)*time.Minute),
+			fmt.Sprintf("evt-max-event-value-%d", i), "", "", types.GetEnvironmentID(s.ctx))
+		s.NoError(s.eventRepo.InsertEvent(s.ctx, event))
+	}
+
+	getUsage := func(policy types.MaxEventValuePolicy) decimal.Decimal {
+		s.config.FeatureUsageTracking.MaxEventValuePolicy = policy
+		service := NewEventService(s.eventRepo, meterRepo, s.publisher, s.logger, s.config)
+		result, err := service.GetUsageByMeter(s.ctx, &dto.GetUsageByMeterRequest{
+			MeterID:            tokensMeter.ID,
+			ExternalCustomerID: "cust-max-event-value",
+			StartTime:          start.Add(-time.Minute),
+			EndTime:            start.Add(time.Hour),
+		})
+		s.Require().NoError(err)
+		return result.Value
+	}
+
+	s.Run("values_above_the_max_are_not_billed_by_default", func() {
+		value := getUsage("")
+		s.True(decimal.NewFromInt(300).Equal(value), value.String())
+	})
+
+	s.Run("values_above_the_max_are_billed_at_the_max_when_clamped", func() {
+		value := getUsage(types.MaxEventValuePolicyClamp)
+		s.True(decimal.NewFromInt(1300).Equal(value), value.String())
+	})
+}
+
+func (s *EventServiceSuite) TestGetUsageByMeterPreCustomerCreationPolicy() {
+	callsMeter := &meter.Meter{
+		ID:        "meter-pre-customer-creation",
+		Name:      "Calls",
+		EventName: "call_made",
+		Aggregation: meter.Aggregation{
+			Type: types.AggregationCount,
+		},
+		ResetUsage: types.ResetUsageBillingPeriod,
+		BaseModel: types.BaseModel{
+			TenantID: types.GetTenantID(s.ctx),
+		},
+	}
+	meterRepo := testutil.NewInMemoryMeterStore()
+	s.NoError(meterRepo.CreateMeter(s.ctx, callsMeter))
+
+	createdAt := time.Now().Add(-48 * time.Hour).Truncate(time.Hour)
+	for i, timestamp := range []time.Time{
+		createdAt.Add(-24 * time.Hour),
+		createdAt.Add(time.Hour),
+		createdAt.Add(2 * time.Hour),
+	} {
+		event := events.NewEvent("call_made", types.GetTenantID(s.ctx), "cust-pre-customer-creation", nil,
+			timestamp, fmt.Sprintf("evt-pre-customer-creation-%d", i), "", "", types.GetEnvironmentID(s.ctx))
+		s.NoError(s.eventRepo.InsertEvent(s.ctx, event))
+	}
+
+	getUsage := func(policy types.PreCustomerCreationEventPolicy, start, end time.Time) decimal.Decimal {
+		s.config.FeatureUsageTracking.PreCustomerCreationPolicy = policy
+		service := NewEventService(s.eventRepo, meterRepo, s.publisher, s.logger, s.config)
+		result, err := service.GetUsageByMeter(s.ctx, &dto.GetUsageByMeterRequest{
+			MeterID:               callsMeter.ID,
+			ExternalCustomerID:    "cust-pre-customer-creation",
+			CustomerCreatedAt:     createdAt,
+			SubscriptionStartDate: createdAt,
+			StartTime:             start,
+			EndTime:               end,
+		})
+		s.Require().NoError(err)
+		return result.Value
+	}
+
+	s.Run("events_before_the_customer_creation_are_billed_by_their_timestamp_by_default", func() {
+		value := getUsage("", createdAt.Add(-48*time.Hour), createdAt.Add(24*time.Hour))
+		s.True(decimal.NewFromInt(3).Equal(value), value.String())
+	})
+
+	s.Run("events_before_the_customer_creation_are_not_billed_when_skipped", func() {
+		value := getUsage(types.PreCustomerCreationEventPolicySkip, createdAt.Add(-48*time.Hour), createdAt.Add(24*time.Hour))
+		s.True(decimal.NewFromInt(2).Equal(value), value.String())
+	})
+
+	s.Run("events_before_the_customer_creation_are_billed_in_the_first_period_when_clamped", func() {
+		value := getUsage(types.PreCustomerCreationEventPolicyClamp, createdAt, createdAt.Add(24*time.Hour))
+		s.True(decimal.NewFromInt(3).Equal(value), value.String())
+	})
+
+	s.Run("events_before_the_customer_creation_are_not_billed_before_the_first_period_when_clamped", func() {
+		value := getUsage(types.PreCustomerCreationEventPolicyClamp, createdAt.Add(-48*time.Hour), createdAt)
+		s.True(decimal.Zero.Equal(value), value.String())
+	})
+}
+
+func (s *EventServiceSuite) TestGetUsageByMeterWeightedSumZeroDurationPeriod() {
+	storageMeter := &meter.Meter{
+		ID:        "meter-weighted-sum-zero-duration",
+		Name:      "Storage",
+		EventName: "storage_used",
+		Aggregation: meter.Aggregation{
+			Type:  types.AggregationWeightedSum,
+			Field: "bytes",
+		},
+		ResetUsage: types.ResetUsageBillingPeriod,
+		BaseModel: types.BaseModel{
+			TenantID: types.GetTenantID(s.ctx),
+		},
+	}
+	meterRepo := testutil.NewInMemoryMeterStore()
+	s.NoError(meterRepo.CreateMeter(s.ctx, storageMeter))
+	s.service = NewEventService(s.eventRepo, meterRepo, s.publisher, s.logger, s.config)
+
+	periodStart := time.Now().Add(-time.Hour)
+	_, err := s.service.GetUsageByMeter(s.ctx, &dto.GetUsageByMeterRequest{
+		MeterID:            storageMeter.ID,
+		ExternalCustomerID: "cust-weighted-sum-zero-duration",
+		StartTime:          periodStart,
+		EndTime:            periodStart,
+	})
+	s.True(types.IsZeroDurationPeriod(err), "error: %v", err)
+}
+
+func (s *EventServiceSuite) TestGetUsageByMeterMinEventValue() {
+	minEventValue := decimal.NewFromInt(60)
+	callsMeter := &meter.Meter{
+		ID:        "meter-min-event-value",
+		Name:      "Call Seconds",
+		EventName: "call_ended",
+		Aggregation: meter.Aggregation{
+			Type:          types.AggregationSum,
+			Field:         "duration",
+			MinEventValue: &minEventValue,
+		},
+		ResetUsage: types.ResetUsageBillingPeriod,
+		BaseModel: types.BaseModel{
+			TenantID: types.GetTenantID(s.ctx),
+		},
+	}
+	meterRepo := testutil.NewInMemoryMeterStore()
+	s.NoError(meterRepo.CreateMeter(s.ctx, callsMeter))
+	s.service = NewEventService(s.eventRepo, meterRepo, s.publisher, s.logger, s.config)
+
+	start := time.Now().Add(-time.Hour)
+	for i, properties := range []map[string]interface{}{
+		{"duration": 5},
+		{"duration": 90},
+		// an event without the field isn't billed the minimum
+		{},
+	} {
+		event := events.NewEvent("call_ended", types.GetTenantID(s.ctx), "cust-min-event-value", properties,
+			start.Add(time.Duration(i)*time.Minute), fmt.Sprintf("evt-min-event-value-%d", i), "", "", types.GetEnvironmentID(s.ctx))
+		s.NoError(s.eventRepo.InsertEvent(s.ctx, event))
+	}
+
+	result, err := s.service.GetUsageByMeter(s.ctx, &dto.GetUsageByMeterRequest{
+		MeterID:            callsMeter.ID,
+		ExternalCustomerID: "cust-min-event-value",
+		StartTime:          start.Add(-time.Minute),
+		EndTime:            start.Add(time.Hour),
+	})
+	s.NoError(err)
+	s.True(decimal.NewFromInt(150).Equal(result.Value), result.Value.String())
+}
+
+func (s *EventServiceSuite) TestGetUsageByMeterRateTable() {
+	multiplier := decimal.NewFromInt(1)
+	tokensMeter := &meter.Meter{
+		ID:        "meter-rate-table",
+		Name:      "Tokens",
+		EventName: "tokens_used",
+		Aggregation: meter.Aggregation{
+			Type:         types.AggregationSumWithMultiplier,
+			Field:        "tokens",
+			Multiplier:   &multiplier,
+			RateProperty: "model",
+			RateTable: map[string]decimal.Decimal{
+				"large": decimal.NewFromInt(10),
+			},
+		},
+		ResetUsage: types.ResetUsageBillingPeriod,
+		BaseModel: types.BaseModel{
+			TenantID: types.GetTenantID(s.ctx),
+		},
+	}
+	meterRepo := testutil.NewInMemoryMeterStore()
+	s.NoError(meterRepo.CreateMeter(s.ctx, tokensMeter))
+	s.service = NewEventService(s.eventRepo, meterRepo, s.publisher, s.logger, s.config)
+
+	start := time.Now().Add(-time.Hour)
+	for i, model := range []string{"large", "small"} {
+		event := events.NewEvent("tokens_used", types.GetTenantID(s.ctx), "cust-rate-table",
+			map[string]interface{}{"tokens": 100, "model": model}, start.Add(time.Duration(i)*time.Minute),
+			fmt.Sprintf("evt-rate-table-%d", i), "", "", types.GetEnvironmentID(s.ctx))
+		s.NoError(s.eventRepo.InsertEvent(s.ctx, event))
+	}
+
+	// the large model is billed at its rate and the small one at the static multiplier
+	result, err := s.service.GetUsageByMeter(s.ctx, &dto.GetUsageByMeterRequest{
+		MeterID:            tokensMeter.ID,
+		ExternalCustomerID: "cust-rate-table",
+		StartTime:          start.Add(-time.Minute),
+		EndTime:            start.Add(time.Hour),
+	})
+	s.NoError(err)
+	s.True(decimal.NewFromInt(1100).Equal(result.Value), result.Value.String())
+}
+
+func (s *EventServiceSuite) TestGetUsageByMeterSubscriptionRouting() {
+	s.config.FeatureUsageTracking.SubscriptionIDProperty = "subscription_id"
+	apiMeter := &meter.Meter{
+		ID:        "meter-subscription-routing",
+		Name:      "API Requests",
+		EventName: "api_request",
+		Aggregation: meter.Aggregation{
+			Type: types.AggregationCount,
+		},
+		ResetUsage: types.ResetUsageBillingPeriod,
+		BaseModel: types.BaseModel{
+			TenantID: types.GetTenantID(s.ctx),
+		},
+	}
+	meterRepo := testutil.NewInMemoryMeterStore()
+	s.NoError(meterRepo.CreateMeter(s.ctx, apiMeter))
+	s.service = NewEventService(s.eventRepo, meterRepo, s.publisher, s.logger, s.config)
+
+	start := time.Now().Add(-time.Hour)
+	for i, properties := range []map[string]interface{}{
+		{"subscription_id": "subs_a"},
+		{"subscription_id": "subs_b"},
+		{"subscription_id": " subs_b "},
+		{},
+	} {
+		event := events.NewEvent("api_request", types.GetTenantID(s.ctx), "cust-routing", properties,
+			start.Add(time.Duration(i)*time.Minute), fmt.Sprintf("evt-routing-%d", i), "", "", types.GetEnvironmentID(s.ctx))
+		s.NoError(s.eventRepo.InsertEvent(s.ctx, event))
+	}
+
+	getUsage := func(subscriptionID string) decimal.Decimal {
+		result, err := s.service.GetUsageByMeter(s.ctx, &dto.GetUsageByMeterRequest{
+			MeterID:            apiMeter.ID,
+			ExternalCustomerID: "cust-routing",
+			SubscriptionID:     subscriptionID,
+			StartTime:          start.Add(-time.Minute),
+			EndTime:            start.Add(time.Hour),
+		})
+		s.Require().NoError(err)
+		return result.Value
+	}
+
+	// the events without a subscription are billed to both subscriptions
+	s.True(decimal.NewFromInt(2).Equal(getUsage("subs_a")))
+	s.True(decimal.NewFromInt(3).Equal(getUsage("subs_b")))
+	// the customer level usage counts every event
+	s.True(decimal.NewFromInt(4).Equal(getUsage("")))
+}
+
 func (s *EventServiceSuite) TestGetEvents() {
 	now := time.Now()
 	// Setup test data
@@ -649,3 +1423,183 @@ func (s *EventServiceSuite) TestGetEvents() {
 		s.Equal("evt-5", result.Events[0].ID) // Only the new event
 	})
 }
+
+type stubLagQuerier struct {
+	lags map[string]int64
+}
+
+func (q *stubLagQuerier) GetConsumerLag(ctx context.Context, topic string, consumerGroup string) (*kafka.ConsumerLag, error) {
+	lag, ok := q.lags[consumerGroup]
+	if !ok {
+		return nil, errors.New("consumer group not found")
+	}
+	return &kafka.ConsumerLag{Topic: topic, ConsumerGroup: consumerGroup, TotalLag: lag}, nil
+}
+
+func (s *EventServiceSuite) TestMonitorKafkaLag() {
+	s.config.EventProcessing.TopicBackfill = "events_backfill"
+	s.config.EventProcessing.ConsumerGroupBackfill = "v1_event_processing_backfill"
+	s.config.Kafka.ReplayTopic = "events"
+	s.config.Kafka.ReplayConsumerGroup = "v1_event_replay"
+	s.service.(*eventService).lagQuerier = &stubLagQuerier{
+		lags: map[string]int64{
+			"flexprice-consumer-test":          2,
+			"v1_event_processing_backfill":     5000,
+			"v1_event_processing_lazy":         0,
+			"v1_feature_tracking_service":      7,
+			"v1_feature_tracking_service_lazy": 1,
+		},
+	}
+
+	response, err := s.service.MonitorKafkaLag(s.ctx)
+	s.NoError(err)
+
+	lags := make(map[types.ConsumerGroupKind]map[string]dto.ConsumerGroupLag)
+	for _, lag := range response.ConsumerGroups {
+		if lags[lag.Kind] == nil {
+			lags[lag.Kind] = make(map[string]dto.ConsumerGroupLag)
+		}
+		lags[lag.Kind][lag.Pipeline] = lag
+	}
+
+	s.Len(response.ConsumerGroups, 6)
+	s.Equal(int64(2), lags[types.ConsumerGroupKindNormal][kafka.PipelineEventConsumption].TotalLag)
+	s.Equal(int64(7), lags[types.ConsumerGroupKindNormal][kafka.PipelineEventPostProcessing].TotalLag)
+	s.Equal(int64(1), lags[types.ConsumerGroupKindLazy][kafka.PipelineEventPostProcessing].TotalLag)
+
+	backfill := lags[types.ConsumerGroupKindBackfill][kafka.PipelineEventConsumption]
+	s.Equal("events_backfill", backfill.Topic)
+	s.Equal(int64(5000), backfill.TotalLag)
+
+	// The replay consumer group couldn't be queried, it is reported with the error
+	replay := lags[types.ConsumerGroupKindReplay][kafka.PipelineReplay]
+	s.Equal("v1_event_replay", replay.ConsumerGroup)
+	s.NotEmpty(replay.Error)
+
+	s.Run("limited to the lag reporting kinds", func() {
+		s.config.Kafka.LagReportingKinds = []types.ConsumerGroupKind{types.ConsumerGroupKindBackfill}
+		defer func() { s.config.Kafka.LagReportingKinds = nil }()
+
+		response, err := s.service.MonitorKafkaLag(s.ctx)
+		s.NoError(err)
+		s.Len(response.ConsumerGroups, 1)
+		s.Equal("v1_event_processing_backfill", response.ConsumerGroups[0].ConsumerGroup)
+	})
+}
+
+func (s *EventServiceSuite) TestGetClockSkew() {
+	ingestedAt := time.Now().UTC().Add(-time.Hour)
+	insertEvents := func(customerID, source string, skew time.Duration, count int) {
+		for i := 0; i < count; i++ {
+			ingested := ingestedAt.Add(time.Duration(i) * time.Second)
+			event := events.NewEvent("api_request", types.GetTenantID(s.ctx), customerID, nil,
+				ingested.Add(-skew), "", "", source, types.GetEnvironmentID(s.ctx))
+			event.IngestedAt = ingested
+			s.Require().NoError(s.eventRepo.InsertEvent(s.ctx, event))
+		}
+	}
+
+	// a producer whose clock runs 10 minutes ahead, one 2 hours behind and an accurate one
+	insertEvents("customer-ahead", "sdk", -10*time.Minute, 12)
+	insertEvents("customer-behind", "batch", 2*time.Hour, 12)
+	insertEvents("customer-ok", "sdk", 2*time.Second, 12)
+	// a backfill mixed with realtime events isn't a skewed clock
+	insertEvents("customer-backfill", "sdk", time.Second, 6)
+	insertEvents("customer-backfill", "sdk", 72*time.Hour, 6)
+	// too few events to tell
+	insertEvents("customer-few", "sdk", -time.Hour, 2)
+
+	response, err := s.service.GetClockSkew(s.ctx, &dto.GetClockSkewRequest{})
+	s.Require().NoError(err)
+	s.Equal(dto.DefaultClockSkewThresholdSeconds, response.ThresholdSeconds)
+
+	items := make(map[string]*dto.ClockSkewItem)
+	for _, item := range response.Items {
+		items[item.ExternalCustomerID] = item
+	}
+	s.Len(items, 4)
+	s.NotContains(items, "customer-few")
+
+	ahead := items["customer-ahead"]
+	s.Require().NotNil(ahead)
+	s.True(ahead.Skewed)
+	s.Equal(types.ClockSkewDirectionAhead, ahead.Direction)
+	s.Equal(uint64(12), ahead.EventCount)
+	s.InDelta(-600, ahead.MedianSkewSeconds, 0.001)
+
+	behind := items["customer-behind"]
+	s.Require().NotNil(behind)
+	s.True(behind.Skewed)
+	s.Equal(types.ClockSkewDirectionBehind, behind.Direction)
+	s.Equal("batch", behind.Source)
+
+	s.False(items["customer-ok"].Skewed)
+	s.Empty(items["customer-ok"].Direction)
+	s.False(items["customer-backfill"].Skewed)
+
+	// the skewed producers are reported first
+	s.True(response.Items[0].Skewed)
+	s.True(response.Items[1].Skewed)
+
+	s.Run("filtered_by_customer_with_a_larger_threshold", func() {
+		response, err := s.service.GetClockSkew(s.ctx, &dto.GetClockSkewRequest{
+			ExternalCustomerID: "customer-ahead",
+			ThresholdSeconds:   3600,
+		})
+		s.Require().NoError(err)
+		s.Require().Len(response.Items, 1)
+		s.False(response.Items[0].Skewed)
+	})
+
+	s.Run("events_ingested_outside_the_range_are_ignored", func() {
+		response, err := s.service.GetClockSkew(s.ctx, &dto.GetClockSkewRequest{
+			StartTime: ingestedAt.Add(-48 * time.Hour),
+			EndTime:   ingestedAt.Add(-24 * time.Hour),
+		})
+		s.Require().NoError(err)
+		s.Empty(response.Items)
+	})
+
+	s.Run("events_lagging_beyond_the_max_skew_are_ignored", func() {
+		// timestamped a week before the start of the default 24 hour window
+		insertEvents("customer-archive", "import", maxClockSkew+48*time.Hour, 12)
+
+		response, err := s.service.GetClockSkew(s.ctx, &dto.GetClockSkewRequest{
+			ExternalCustomerID: "customer-archive",
+		})
+		s.Require().NoError(err)
+		s.Empty(response.Items)
+	})
+}
+
+func (s *EventServiceSuite) TestCreateEventRecordsClockSkewToTheDefaultRecorder() {
+	s.Same(metrics.Default(), s.service.(*eventService).metrics)
+}
+
+func (s *EventServiceSuite) TestCreateEventRecordsClockSkew() {
+	recorder := metrics.NewInMemoryRecorder()
+	s.service.(*eventService).metrics = recorder
+	tenantID := types.GetTenantID(s.ctx)
+
+	s.Require().NoError(s.service.CreateEvent(s.ctx, &dto.IngestEventRequest{
+		EventName:          "api_request",
+		ExternalCustomerID: "customer-1",
+		Timestamp:          time.Now().Add(-time.Minute),
+	}))
+	s.Require().NoError(s.service.CreateEvent(s.ctx, &dto.IngestEventRequest{
+		EventName:          "api_request",
+		ExternalCustomerID: "customer-1",
+		Timestamp:          time.Now().Add(10 * time.Minute),
+	}))
+	// the ingestion time is used for events sent without a timestamp, they aren't skewed
+	s.Require().NoError(s.service.CreateEvent(s.ctx, &dto.IngestEventRequest{
+		EventName:          "api_request",
+		ExternalCustomerID: "customer-1",
+	}))
+
+	skew, ok := recorder.Get(metrics.MetricEventClockSkew, tenantID)
+	s.Require().True(ok)
+	s.Equal(int64(2), skew.Count)
+	s.GreaterOrEqual(skew.Max, time.Minute)
+	s.Equal(int64(1), recorder.Count(metrics.MetricEventFutureTimestamp, tenantID))
+}
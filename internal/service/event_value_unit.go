@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/domain/meter"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// unitMetersTTL is how long the meters aggregating a field in a unit are cached for ingestion, a
+// unit set on a meter applies to the events ingested after it expires
+const unitMetersTTL = time.Minute
+
+// unitMeterCache caches the meters aggregating a field in a unit per tenant and environment, so
+// ingestion doesn't list the meters for every event
+type unitMeterCache struct {
+	mu      sync.Mutex
+	entries map[string]unitMeterCacheEntry
+}
+
+type unitMeterCacheEntry struct {
+	meters    []*meter.Meter
+	expiresAt time.Time
+}
+
+func newUnitMeterCache() *unitMeterCache {
+	return &unitMeterCache{entries: make(map[string]unitMeterCacheEntry)}
+}
+
+// get returns the unit meters of the tenant and environment, listing them with list when they
+// aren't cached or expired
+func (c *unitMeterCache) get(ctx context.Context, list func(ctx context.Context) ([]*meter.Meter, error)) ([]*meter.Meter, error) {
+	key := types.GetTenantID(ctx) + ":" + types.GetEnvironmentID(ctx)
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.meters, nil
+	}
+
+	meters, err := list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = unitMeterCacheEntry{meters: meters, expiresAt: now.Add(unitMetersTTL)}
+	c.mu.Unlock()
+	return meters, nil
+}
+
+// listUnitMeters lists the meters of the tenant and environment aggregating a field in a unit
+func (s *eventService) listUnitMeters(ctx context.Context) ([]*meter.Meter, error) {
+	meters, err := s.meterRepo.ListAll(ctx, types.NewNoLimitMeterFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	unitMeters := make([]*meter.Meter, 0)
+	for _, m := range meters {
+		if m.Aggregation.Unit != "" && m.Aggregation.Field != "" {
+			unitMeters = append(unitMeters, m)
+		}
+	}
+	return unitMeters, nil
+}
+
+// normalizeValueUnits converts the string values with a unit suffix, e.g. "1.5GB", of the fields
+// meters aggregate in a unit to numbers in that unit, so feature usage and invoices bill the same
+// value. The values sent are recorded in the event properties. A field aggregated in different
+// units by the meters of the event is left as is.
+func (s *eventService) normalizeValueUnits(ctx context.Context, event *events.Event) {
+	if s.meterRepo == nil || s.unitMeters == nil || len(event.Properties) == 0 {
+		return
+	}
+
+	meters, err := s.unitMeters.get(ctx, s.listUnitMeters)
+	if err != nil {
+		s.logger.Warnw("failed to list the meters with a unit, values are ingested as sent",
+			"event_id", event.ID,
+			"error", err,
+		)
+		return
+	}
+
+	normalization := eventNameNormalization(s.config, s.logger)
+	units := make(map[string]types.ValueUnit)
+	conflicts := make(map[string]bool)
+	for _, m := range meters {
+		if !m.MatchesEventName(event.EventName, false, normalization) {
+			continue
+		}
+
+		field := m.Aggregation.Field
+		if unit, ok := units[field]; ok && unit != m.Aggregation.Unit {
+			conflicts[field] = true
+			continue
+		}
+		units[field] = m.Aggregation.Unit
+	}
+
+	originalValues := make(map[string]interface{})
+	for field, unit := range units {
+		raw, ok := event.Properties[field].(string)
+		if !ok {
+			continue
+		}
+		if conflicts[field] {
+			s.logger.Warnw("field aggregated in different units by the meters of the event, value is ingested as sent",
+				"event_id", event.ID,
+				"field", field,
+			)
+			continue
+		}
+
+		value, err := unit.Parse(raw)
+		if err != nil {
+			s.logger.Warnw("failed to parse value with unit, value is ingested as sent",
+				"event_id", event.ID,
+				"field", field,
+				"value", raw,
+				"unit", unit,
+				"error", err,
+			)
+			continue
+		}
+
+		originalValues[field] = raw
+		event.Properties[field] = value.InexactFloat64()
+	}
+
+	if len(originalValues) > 0 {
+		event.Properties[events.OriginalValuesProperty] = originalValues
+	}
+}
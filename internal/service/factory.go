@@ -5,6 +5,7 @@ import (
 	"github.com/flexprice/flexprice/internal/domain/addon"
 	"github.com/flexprice/flexprice/internal/domain/addonassociation"
 	"github.com/flexprice/flexprice/internal/domain/alertlogs"
+	"github.com/flexprice/flexprice/internal/domain/auditlog"
 	"github.com/flexprice/flexprice/internal/domain/auth"
 	"github.com/flexprice/flexprice/internal/domain/connection"
 	costsheet "github.com/flexprice/flexprice/internal/domain/costsheet"
@@ -64,6 +65,9 @@ type ServiceParams struct {
 	EventRepo                    events.Repository
 	ProcessedEventRepo           events.ProcessedEventRepository
 	FeatureUsageRepo             events.FeatureUsageRepository
+	UsageCounterRepo             events.UsageCounterRepository
+	EventDedupRepo               events.EventDedupRepository
+	AuditLogRepo                 auditlog.Repository
 	MeterRepo                    meter.Repository
 	PriceRepo                    price.Repository
 	PriceUnitRepo                priceunit.Repository
@@ -126,6 +130,9 @@ func NewServiceParams(
 	eventRepo events.Repository,
 	processedEventRepo events.ProcessedEventRepository,
 	featureUsageRepo events.FeatureUsageRepository,
+	usageCounterRepo events.UsageCounterRepository,
+	eventDedupRepo events.EventDedupRepository,
+	auditLogRepo auditlog.Repository,
 	meterRepo meter.Repository,
 	priceRepo price.Repository,
 	priceUnitRepo priceunit.Repository,
@@ -179,6 +186,9 @@ func NewServiceParams(
 		EventRepo:                    eventRepo,
 		ProcessedEventRepo:           processedEventRepo,
 		FeatureUsageRepo:             featureUsageRepo,
+		UsageCounterRepo:             usageCounterRepo,
+		EventDedupRepo:               eventDedupRepo,
+		AuditLogRepo:                 auditLogRepo,
 		MeterRepo:                    meterRepo,
 		PriceRepo:                    priceRepo,
 		PriceUnitRepo:                priceUnitRepo,
@@ -33,7 +33,7 @@ func NewFeatureService(params ServiceParams) FeatureService {
 }
 
 func (s *featureService) CreateFeature(ctx context.Context, req dto.CreateFeatureRequest) (*dto.FeatureResponse, error) {
-	meterService := NewMeterService(s.MeterRepo)
+	meterService := NewMeterService(s.MeterRepo, s.AuditLogRepo, s.Logger)
 	err := req.Validate()
 	if err != nil {
 		return nil, err // Validation errors are already properly formatted in the DTO
@@ -72,6 +72,8 @@ func (s *featureService) CreateFeature(ctx context.Context, req dto.CreateFeatur
 		return nil, err
 	}
 
+	recordAuditLog(ctx, s.AuditLogRepo, s.Logger, types.AuditLogEntityTypeFeature, featureModel.ID, types.AuditLogActionCreate, nil, featureModel)
+
 	// Publish webhook event
 	s.publishWebhookEvent(ctx, types.WebhookEventFeatureCreated, featureModel.ID)
 
@@ -193,6 +195,8 @@ func (s *featureService) UpdateFeature(ctx context.Context, id string, req dto.U
 	if err != nil {
 		return nil, err
 	}
+	before := *feature
+
 	if req.Description != nil {
 		feature.Description = *req.Description
 	}
@@ -250,7 +254,7 @@ func (s *featureService) UpdateFeature(ctx context.Context, id string, req dto.U
 
 	if feature.Type == types.FeatureTypeMetered && feature.MeterID != "" {
 		// update meter filters if provided
-		meterService := NewMeterService(s.MeterRepo)
+		meterService := NewMeterService(s.MeterRepo, s.AuditLogRepo, s.Logger)
 		if req.Filters != nil {
 			if _, err := meterService.UpdateMeter(ctx, feature.MeterID, *req.Filters); err != nil {
 				return nil, err
@@ -269,6 +273,8 @@ func (s *featureService) UpdateFeature(ctx context.Context, id string, req dto.U
 		return nil, err
 	}
 
+	recordAuditLog(ctx, s.AuditLogRepo, s.Logger, types.AuditLogEntityTypeFeature, feature.ID, types.AuditLogActionUpdate, &before, feature)
+
 	// Publish webhook event
 	s.publishWebhookEvent(ctx, types.WebhookEventFeatureUpdated, feature.ID)
 
@@ -314,6 +320,8 @@ func (s *featureService) DeleteFeature(ctx context.Context, id string) error {
 		return err
 	}
 
+	recordAuditLog(ctx, s.AuditLogRepo, s.Logger, types.AuditLogEntityTypeFeature, id, types.AuditLogActionDelete, feature, nil)
+
 	// Publish webhook event
 	s.publishWebhookEvent(ctx, types.WebhookEventFeatureDeleted, id)
 
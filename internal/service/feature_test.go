@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/domain/auditlog"
 	"github.com/flexprice/flexprice/internal/domain/feature"
 	"github.com/flexprice/flexprice/internal/domain/meter"
 	"github.com/flexprice/flexprice/internal/testutil"
@@ -61,6 +62,7 @@ func (s *FeatureServiceSuite) setupService() {
 		FeatureRepo:      s.featureRepo,
 		MeterRepo:        s.meterRepo,
 		EntitlementRepo:  s.entitlementRepo,
+		AuditLogRepo:     s.GetStores().AuditLogRepo,
 		WebhookPublisher: s.GetWebhookPublisher(),
 	})
 }
@@ -877,3 +879,41 @@ func (s *FeatureServiceSuite) TestDeleteFeature() {
 		})
 	}
 }
+
+func (s *FeatureServiceSuite) TestFeatureAuditLog() {
+	auditLogs := func(featureID string) []*auditlog.AuditLog {
+		logs, err := s.GetStores().AuditLogRepo.ListByEntity(s.GetContext(), types.AuditLogEntityTypeFeature, featureID, 0)
+		s.Require().NoError(err)
+		return logs
+	}
+
+	s.Run("update records the changed fields", func() {
+		_, err := s.service.UpdateFeature(s.GetContext(), s.testData.features.storage.ID, dto.UpdateFeatureRequest{
+			Name:        lo.ToPtr("Storage (GB)"),
+			Description: lo.ToPtr("Track storage usage"),
+		})
+		s.NoError(err)
+
+		logs := auditLogs(s.testData.features.storage.ID)
+		s.Require().Len(logs, 1)
+		s.Equal(types.AuditLogActionUpdate, logs[0].Action)
+		s.Equal(types.DefaultUserID, logs[0].Actor)
+		s.Equal("Storage Feature", logs[0].Before["name"])
+		s.Equal("Storage (GB)", logs[0].After["name"])
+		// The description was sent unchanged so only the name is part of the diff
+		s.Equal(map[string]types.AuditLogChange{
+			"name": {Before: "Storage Feature", After: "Storage (GB)"},
+		}, logs[0].Changes)
+	})
+
+	s.Run("delete records the feature as it was", func() {
+		s.NoError(s.service.DeleteFeature(s.GetContext(), s.testData.features.boolean.ID))
+
+		logs := auditLogs(s.testData.features.boolean.ID)
+		s.Require().Len(logs, 1)
+		s.Equal(types.AuditLogActionDelete, logs[0].Action)
+		s.Equal("Boolean Feature", logs[0].Before["name"])
+		s.Nil(logs[0].After)
+		s.Equal(types.AuditLogChange{Before: "Boolean Feature", After: nil}, logs[0].Changes["name"])
+	})
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"time"
+
+	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+)
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetProcessedEvents() {
+	start := s.testData.now.Add(-48 * time.Hour)
+	insert := func(id string, timestamp time.Time, sign int8) {
+		s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(s.GetContext(), &events.FeatureUsage{
+			Event: events.Event{
+				ID:            id,
+				TenantID:      s.testData.customer.TenantID,
+				EnvironmentID: s.testData.customer.EnvironmentID,
+				Timestamp:     timestamp,
+			},
+			SubscriptionID: s.testData.subscription.ID,
+			SubLineItemID:  "subli_processed_events",
+			MeterID:        s.testData.meter.ID,
+			QtyTotal:       decimal.NewFromInt(1),
+			Sign:           sign,
+		}))
+	}
+	ids := func(rows []*events.FeatureUsage) []string {
+		return lo.Map(rows, func(row *events.FeatureUsage, _ int) string { return row.ID })
+	}
+
+	insert("event_c", start.Add(2*time.Hour), 1)
+	insert("event_b", start.Add(time.Hour), 1)
+	insert("event_a", start.Add(time.Hour), 1)
+	insert("event_cancelled", start.Add(time.Hour), 0)
+	insert("event_outside", start.Add(-time.Hour), 1)
+
+	params := func(offset, limit int) *events.GetProcessedEventsParams {
+		return &events.GetProcessedEventsParams{
+			StartTime:      start,
+			EndTime:        start.Add(24 * time.Hour),
+			SubscriptionID: s.testData.subscription.ID,
+			Offset:         offset,
+			Limit:          limit,
+		}
+	}
+
+	s.Run("skips_cancelled_rows_and_orders_by_timestamp_and_id", func() {
+		rows, total, err := s.GetStores().FeatureUsageRepo.GetProcessedEvents(s.GetContext(), params(0, 0))
+		s.NoError(err)
+		s.Equal(uint64(3), total)
+		s.Equal([]string{"event_a", "event_b", "event_c"}, ids(rows))
+	})
+
+	s.Run("pages_in_a_stable_order", func() {
+		first, total, err := s.GetStores().FeatureUsageRepo.GetProcessedEvents(s.GetContext(), params(0, 2))
+		s.NoError(err)
+		s.Equal(uint64(3), total)
+		s.Equal([]string{"event_a", "event_b"}, ids(first))
+
+		second, _, err := s.GetStores().FeatureUsageRepo.GetProcessedEvents(s.GetContext(), params(2, 2))
+		s.NoError(err)
+		s.Equal([]string{"event_c"}, ids(second))
+
+		past, _, err := s.GetStores().FeatureUsageRepo.GetProcessedEvents(s.GetContext(), params(3, 2))
+		s.NoError(err)
+		s.Empty(past)
+	})
+}
@@ -3,18 +3,22 @@ package service
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
 	"math/rand"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
 	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/clickhouse"
 	"github.com/flexprice/flexprice/internal/config"
 	"github.com/flexprice/flexprice/internal/domain/addon"
 	"github.com/flexprice/flexprice/internal/domain/customer"
@@ -25,12 +29,14 @@ import (
 	"github.com/flexprice/flexprice/internal/domain/price"
 	"github.com/flexprice/flexprice/internal/domain/subscription"
 	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/metrics"
 	"github.com/flexprice/flexprice/internal/pubsub"
 	"github.com/flexprice/flexprice/internal/pubsub/kafka"
 	pubsubRouter "github.com/flexprice/flexprice/internal/pubsub/router"
 	"github.com/flexprice/flexprice/internal/types"
 	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
+	"golang.org/x/sync/errgroup"
 )
 
 // FeatureUsageTrackingService handles feature usage tracking operations for metered events
@@ -50,20 +56,49 @@ type FeatureUsageTrackingService interface {
 	// Get detailed usage analytics version 2 with filtering, grouping, and time-series data
 	GetDetailedUsageAnalyticsV2(ctx context.Context, req *dto.GetUsageAnalyticsRequest) (*dto.GetUsageAnalyticsResponse, error)
 
+	// Compare the usage and cost of each feature between two time ranges
+	CompareUsageAnalytics(ctx context.Context, req *dto.CompareUsageAnalyticsRequest) (*dto.CompareUsageAnalyticsResponse, error)
+
+	// Forecast the usage of a feature in the next period from its usage in the previous periods
+	ForecastFeatureUsage(ctx context.Context, req *dto.ForecastFeatureUsageRequest) (*dto.ForecastFeatureUsageResponse, error)
+
 	// Reprocess events for a specific customer or with other filters
 	ReprocessEvents(ctx context.Context, params *events.ReprocessEventsParams) error
 
+	// Find feature usage attributed to subscription line items that no longer exist
+	FindOrphanedUsage(ctx context.Context, params *events.FindOrphanedUsageParams) ([]*events.FeatureUsage, error)
+
+	// Get usage totals per meter across all tenants and environments, for operators only
+	GetPlatformUsage(ctx context.Context, req *dto.GetPlatformUsageRequest) (*dto.GetPlatformUsageResponse, error)
+
+	// Ingest usage that was already aggregated over a period instead of raw events
+	IngestUsageRecord(ctx context.Context, req *dto.IngestUsageRecordRequest) (*dto.IngestUsageRecordResponse, error)
+
+	// Preview the invoice of a subscription's current period with usage projected to the end of the period
+	GetProjectedInvoice(ctx context.Context, subscriptionID string) (*dto.ProjectedInvoiceResponse, error)
+
+	// Get the processing status of many events without loading their usage records
+	GetEventsStatus(ctx context.Context, eventIDs []string) (*dto.GetEventsStatusResponse, error)
+
+	// List the events of a customer in a window that produced no feature usage and why
+	GetUnbilledEvents(ctx context.Context, req *dto.GetUnbilledEventsRequest) (*dto.GetUnbilledEventsResponse, error)
+
+	// Delete the event ids whose dedup window ended, of all tenants
+	DeleteExpiredEventDedupKeys(ctx context.Context) (int, error)
+
 	// Get HuggingFace Inference
 	GetHuggingFaceBillingData(ctx context.Context, req *dto.GetHuggingFaceBillingDataRequest) (*dto.GetHuggingFaceBillingDataResponse, error)
 }
 
 type featureUsageTrackingService struct {
 	ServiceParams
-	pubSub           pubsub.PubSub // Regular PubSub for normal processing
-	backfillPubSub   pubsub.PubSub // Dedicated Kafka PubSub for backfill processing
-	lazyPubSub       pubsub.PubSub // Dedicated Kafka PubSub for lazy processing
+	pubSub           pubsub.PubSub            // Regular PubSub for normal processing
+	backfillPubSub   pubsub.PubSub            // Dedicated Kafka PubSub for backfill processing
+	lazyPubSub       pubsub.PubSub            // Dedicated Kafka PubSub for lazy processing
+	priorityPubSubs  map[string]pubsub.PubSub // Kafka PubSub consuming each priority topic, by priority
 	eventRepo        events.Repository
 	featureUsageRepo events.FeatureUsageRepository
+	metrics          metrics.Recorder // Per tenant processing lag and failures, exported by metrics.RegisterHooks
 }
 
 // NewFeatureUsageTrackingService creates a new feature usage tracking service
@@ -76,6 +111,7 @@ func NewFeatureUsageTrackingService(
 		ServiceParams:    params,
 		eventRepo:        eventRepo,
 		featureUsageRepo: featureUsageRepo,
+		metrics:          metrics.Default(),
 	}
 
 	pubSub, err := kafka.NewPubSubFromConfig(
@@ -113,10 +149,25 @@ func NewFeatureUsageTrackingService(
 	}
 	ev.lazyPubSub = lazyPubSub
 
+	ev.priorityPubSubs = make(map[string]pubsub.PubSub, len(params.Config.FeatureUsageTracking.PriorityTopics))
+	for _, priority := range params.Config.FeatureUsageTracking.PriorityTopics {
+		priorityPubSub, err := kafka.NewPubSubFromConfig(
+			params.Config,
+			params.Logger,
+			priority.ConsumerGroup,
+		)
+		if err != nil {
+			params.Logger.Fatalw("failed to create priority pubsub", "priority", priority.Priority, "error", err)
+			return nil
+		}
+		ev.priorityPubSubs[strings.ToLower(priority.Priority)] = priorityPubSub
+	}
+
 	return ev
 }
 
-// PublishEvent publishes an event to the feature usage tracking topic
+// PublishEvent publishes an event to the feature usage tracking topic, the backfill topic when
+// backfilling or the topic of the priority the event is tagged with
 func (s *featureUsageTrackingService) PublishEvent(ctx context.Context, event *events.Event, isBackfill bool) error {
 	// Create message payload
 	payload, err := json.Marshal(event)
@@ -147,8 +198,13 @@ func (s *featureUsageTrackingService) PublishEvent(ctx context.Context, event *e
 	pubSub := s.pubSub
 	topic := s.Config.FeatureUsageTracking.Topic
 	if isBackfill {
+		// Backfilled events are reprocessed on purpose so they bypass the dedup window
+		msg.Metadata.Set("backfill", "true")
 		pubSub = s.backfillPubSub
 		topic = s.Config.FeatureUsageTracking.TopicBackfill
+	} else if priority, ok := s.priorityTopic(event); ok {
+		msg.Metadata.Set("priority", priority.Priority)
+		topic = priority.Topic
 	}
 
 	if pubSub == nil {
@@ -177,6 +233,7 @@ func (s *featureUsageTrackingService) PublishEvent(ctx context.Context, event *e
 func (s *featureUsageTrackingService) RegisterHandler(router *pubsubRouter.Router, cfg *config.Configuration) {
 	// Add throttle middleware to this specific handler
 	throttle := middleware.NewThrottle(cfg.FeatureUsageTracking.RateLimit, time.Second)
+	maxInFlight := pubsubRouter.NewMaxInFlight(cfg.FeatureUsageTracking.MaxInFlight)
 
 	// Add the handler
 	router.AddNoPublishHandler(
@@ -185,13 +242,19 @@ func (s *featureUsageTrackingService) RegisterHandler(router *pubsubRouter.Route
 		s.pubSub,
 		s.processMessage,
 		throttle.Middleware,
+		maxInFlight.Middleware,
+		s.newBackPressure("feature_usage_tracking_handler", cfg).Middleware,
 	)
 
 	s.Logger.Infow("registered event feature usage tracking handler",
 		"topic", cfg.FeatureUsageTracking.Topic,
 		"rate_limit", cfg.FeatureUsageTracking.RateLimit,
+		"max_in_flight", cfg.FeatureUsageTracking.MaxInFlight,
+		"back_pressure_pause", cfg.FeatureUsageTracking.BackPressurePause,
 	)
 
+	s.registerPriorityHandlers(router, cfg)
+
 	// Add backfill handler
 	if cfg.FeatureUsageTracking.TopicBackfill == "" {
 		s.Logger.Warnw("backfill topic not set, skipping backfill handler")
@@ -199,25 +262,69 @@ func (s *featureUsageTrackingService) RegisterHandler(router *pubsubRouter.Route
 	}
 
 	backfillThrottle := middleware.NewThrottle(cfg.FeatureUsageTracking.RateLimitBackfill, time.Second)
+	backfillMaxInFlight := pubsubRouter.NewMaxInFlight(cfg.FeatureUsageTracking.MaxInFlightBackfill)
 	router.AddNoPublishHandler(
 		"feature_usage_tracking_backfill_handler",
 		cfg.FeatureUsageTracking.TopicBackfill,
 		s.backfillPubSub, // Use the dedicated Kafka backfill PubSub
 		s.processMessage,
 		backfillThrottle.Middleware,
+		backfillMaxInFlight.Middleware,
+		s.newBackPressure("feature_usage_tracking_backfill_handler", cfg).Middleware,
 	)
 
 	s.Logger.Infow("registered event feature usage tracking backfill handler",
 		"topic", cfg.FeatureUsageTracking.TopicBackfill,
 		"rate_limit", cfg.FeatureUsageTracking.RateLimitBackfill,
+		"max_in_flight", cfg.FeatureUsageTracking.MaxInFlightBackfill,
 		"pubsub_type", "kafka",
 	)
 }
 
+// priorityTopic returns the priority topic of the event's priority tag, false when the event has
+// no priority tag or its priority has no topic
+func (s *featureUsageTrackingService) priorityTopic(event *events.Event) (config.FeatureUsagePriorityTopicConfig, bool) {
+	priority := s.stringPropertyValue(event, s.Config.FeatureUsageTracking.PriorityProperty)
+	return s.Config.FeatureUsageTracking.PriorityTopic(priority)
+}
+
+// registerPriorityHandlers registers a handler for each priority topic, rate limited by the
+// priority's own limits so a burst of normal events doesn't delay the prioritized ones
+func (s *featureUsageTrackingService) registerPriorityHandlers(router *pubsubRouter.Router, cfg *config.Configuration) {
+	for _, priority := range cfg.FeatureUsageTracking.PriorityTopics {
+		priorityPubSub, ok := s.priorityPubSubs[strings.ToLower(priority.Priority)]
+		if !ok {
+			s.Logger.Warnw("priority pubsub not initialized, skipping priority handler", "priority", priority.Priority)
+			continue
+		}
+
+		handlerName := fmt.Sprintf("feature_usage_tracking_priority_%s_handler", strings.ToLower(priority.Priority))
+		throttle := middleware.NewThrottle(priority.RateLimit, time.Second)
+		maxInFlight := pubsubRouter.NewMaxInFlight(priority.MaxInFlight)
+		router.AddNoPublishHandler(
+			handlerName,
+			priority.Topic,
+			priorityPubSub,
+			s.processMessage,
+			throttle.Middleware,
+			maxInFlight.Middleware,
+			s.newBackPressure(handlerName, cfg).Middleware,
+		)
+
+		s.Logger.Infow("registered event feature usage tracking priority handler",
+			"priority", priority.Priority,
+			"topic", priority.Topic,
+			"rate_limit", priority.RateLimit,
+			"max_in_flight", priority.MaxInFlight,
+		)
+	}
+}
+
 // RegisterHandler registers a handler for the feature usage tracking topic with rate limiting
 func (s *featureUsageTrackingService) RegisterHandlerLazy(router *pubsubRouter.Router, cfg *config.Configuration) {
 	// Add throttle middleware to this specific handler
 	throttle := middleware.NewThrottle(cfg.FeatureUsageTrackingLazy.RateLimit, time.Second)
+	maxInFlight := pubsubRouter.NewMaxInFlight(cfg.FeatureUsageTrackingLazy.MaxInFlight)
 
 	// Add the handler
 	router.AddNoPublishHandler(
@@ -226,11 +333,31 @@ func (s *featureUsageTrackingService) RegisterHandlerLazy(router *pubsubRouter.R
 		s.lazyPubSub,
 		s.processMessage,
 		throttle.Middleware,
+		maxInFlight.Middleware,
+		s.newBackPressure("feature_usage_tracking_lazy_handler", cfg).Middleware,
 	)
 
 	s.Logger.Infow("registered event feature usage tracking lazy handler",
 		"topic", cfg.FeatureUsageTrackingLazy.Topic,
 		"rate_limit", cfg.FeatureUsageTrackingLazy.RateLimit,
+		"max_in_flight", cfg.FeatureUsageTrackingLazy.MaxInFlight,
+	)
+}
+
+// newBackPressure creates the middleware pausing the handler when clickhouse rejects feature
+// usage inserts because it is overloaded
+func (s *featureUsageTrackingService) newBackPressure(handlerName string, cfg *config.Configuration) *pubsubRouter.BackPressure {
+	return pubsubRouter.NewBackPressure(
+		clickhouse.IsBackPressureError,
+		cfg.FeatureUsageTracking.BackPressurePause,
+		cfg.FeatureUsageTracking.BackPressureMaxPause,
+		func(err error, pause time.Duration) {
+			s.Logger.Warnw("clickhouse is overloaded, pausing feature usage tracking",
+				"handler", handlerName,
+				"pause", pause,
+				"error", err,
+			)
+		},
 	)
 }
 
@@ -258,6 +385,10 @@ func (s *featureUsageTrackingService) processMessage(msg *message.Message) error
 		ctx = context.WithValue(ctx, types.CtxEnvironmentID, environmentID)
 	}
 
+	if msg.Metadata.Get("backfill") == "true" {
+		ctx = context.WithValue(ctx, skipEventDedupKey{}, true)
+	}
+
 	// Unmarshal the event
 	var event events.Event
 	if err := json.Unmarshal(msg.Payload, &event); err != nil {
@@ -288,6 +419,8 @@ func (s *featureUsageTrackingService) processMessage(msg *message.Message) error
 		return err // Return error for retry
 	}
 
+	s.recordProcessingLag(&event, time.Now().UTC())
+
 	s.Logger.Infow("event for feature usage tracking processed successfully",
 		"event_id", event.ID,
 		"event_name", event.EventName,
@@ -296,6 +429,71 @@ func (s *featureUsageTrackingService) processMessage(msg *message.Message) error
 	return nil
 }
 
+// recordProcessingLag records how far behind the event timestamp and its ingestion the event
+// was processed, per tenant, so a growing lag can be alerted on
+func (s *featureUsageTrackingService) recordProcessingLag(event *events.Event, processedAt time.Time) {
+	if s.metrics == nil {
+		return
+	}
+
+	if !event.Timestamp.IsZero() {
+		s.metrics.RecordDuration(metrics.MetricEventProcessingLag, event.TenantID, processedAt.Sub(event.Timestamp))
+	}
+
+	// Events published straight to the queue are not ingested first
+	if !event.IngestedAt.IsZero() {
+		s.metrics.RecordDuration(metrics.MetricEventIngestionLag, event.TenantID, processedAt.Sub(event.IngestedAt))
+	}
+}
+
+// recordPeriodCalculationFailure counts the subscriptions whose billing period couldn't be
+// calculated for an event, per tenant, so the failures are visible
+func (s *featureUsageTrackingService) recordPeriodCalculationFailure(event *events.Event) {
+	if s.metrics == nil {
+		return
+	}
+
+	s.metrics.IncrementCounter(metrics.MetricPeriodCalculationFailure, event.TenantID)
+}
+
+// isEventValueAnomaly checks the value of the event field against the maximum event value of the
+// meter. Anomalous values are logged and counted per tenant whatever the policy.
+func (s *featureUsageTrackingService) isEventValueAnomaly(event *events.Event, meter *meter.Meter) bool {
+	if !s.exceedsMaxEventValue(event, meter) {
+		return false
+	}
+
+	val := event.Properties[meter.Aggregation.Field]
+	value, _ := s.convertPropertyToDecimal(val, event, meter)
+	s.Logger.Warnw("event value above the max event value of the meter",
+		"event_id", event.ID,
+		"meter_id", meter.ID,
+		"field", meter.Aggregation.Field,
+		"value", value.String(),
+		"max_event_value", meter.Aggregation.MaxEventValue.String(),
+	)
+	if s.metrics != nil {
+		s.metrics.IncrementCounter(metrics.MetricEventValueAnomaly, event.TenantID)
+	}
+	return true
+}
+
+// exceedsMaxEventValue reports whether the value of the event field is above the maximum event
+// value of the meter
+func (s *featureUsageTrackingService) exceedsMaxEventValue(event *events.Event, meter *meter.Meter) bool {
+	if meter.Aggregation.MaxEventValue == nil || meter.Aggregation.Field == "" {
+		return false
+	}
+
+	val, ok := event.Properties[meter.Aggregation.Field]
+	if !ok {
+		return false
+	}
+
+	value, _ := s.convertPropertyToDecimal(val, event, meter)
+	return meter.Aggregation.ExceedsMaxEventValue(value)
+}
+
 // Process a single event for feature usage tracking
 func (s *featureUsageTrackingService) processEvent(ctx context.Context, event *events.Event) error {
 	s.Logger.Debugw("processing event",
@@ -305,6 +503,30 @@ func (s *featureUsageTrackingService) processEvent(ctx context.Context, event *e
 		"ingested_at", event.IngestedAt,
 	)
 
+	claimed, duplicate := s.claimEvent(ctx, event)
+	if duplicate {
+		s.Logger.Infow("skipping event already processed within the dedup window",
+			"event_id", event.ID,
+			"event_name", event.EventName,
+			"dedup_window", s.Config.FeatureUsageTracking.DedupWindow,
+		)
+		if s.metrics != nil {
+			s.metrics.IncrementCounter(metrics.MetricEventDuplicate, event.TenantID)
+		}
+		return nil
+	}
+
+	if err := s.trackEventUsage(ctx, event); err != nil {
+		if claimed {
+			s.releaseEvent(ctx, event)
+		}
+		return err
+	}
+	return nil
+}
+
+// trackEventUsage prepares and stores the feature usage of the event
+func (s *featureUsageTrackingService) trackEventUsage(ctx context.Context, event *events.Event) error {
 	featureUsage, err := s.prepareProcessedEvents(ctx, event)
 	if err != nil {
 		s.Logger.Errorw("failed to prepare feature usage",
@@ -318,22 +540,100 @@ func (s *featureUsageTrackingService) processEvent(ctx context.Context, event *e
 		if err := s.featureUsageRepo.BulkInsertProcessedEvents(ctx, featureUsage); err != nil {
 			return err
 		}
+		s.incrementUsageCounters(ctx, featureUsage)
 	}
-
 	return nil
 }
 
+// skipEventDedupKey marks a context whose event is reprocessed on purpose and bypasses the
+// dedup window
+type skipEventDedupKey struct{}
+
+// claimEvent claims the event id in the dedup store for the dedup window, before the event is
+// processed so concurrent deliveries to different consumers are processed once. It returns
+// whether the id was claimed and whether the event is a duplicate. The window is disabled when
+// it is 0 or the event is reprocessed. A failing store doesn't block processing, the unique hash
+// of the feature usage still deduplicates the event.
+func (s *featureUsageTrackingService) claimEvent(ctx context.Context, event *events.Event) (claimed bool, duplicate bool) {
+	window := s.Config.FeatureUsageTracking.DedupWindow
+	if s.EventDedupRepo == nil || window <= 0 || event.ID == "" {
+		return false, false
+	}
+	if skip, _ := ctx.Value(skipEventDedupKey{}).(bool); skip {
+		return false, false
+	}
+
+	claimed, err := s.EventDedupRepo.Claim(ctx, event.ID, time.Now().UTC().Add(window))
+	if err != nil {
+		s.Logger.Warnw("failed to claim event in the dedup store, processing it",
+			"event_id", event.ID,
+			"error", err,
+		)
+		return false, false
+	}
+	return claimed, !claimed
+}
+
+// releaseEvent releases the event id claimed in the dedup store, so a failed event is processed
+// when it is retried
+func (s *featureUsageTrackingService) releaseEvent(ctx context.Context, event *events.Event) {
+	if err := s.EventDedupRepo.Release(ctx, event.ID); err != nil {
+		s.Logger.Errorw("failed to release event in the dedup store, its retries are skipped until the dedup window ends",
+			"event_id", event.ID,
+			"error", err,
+		)
+	}
+}
+
+// DeleteExpiredEventDedupKeys deletes the event ids whose dedup window ended, of all tenants
+func (s *featureUsageTrackingService) DeleteExpiredEventDedupKeys(ctx context.Context) (int, error) {
+	if s.EventDedupRepo == nil {
+		return 0, nil
+	}
+	return s.EventDedupRepo.DeleteExpired(ctx, time.Now().UTC())
+}
+
+// incrementUsageCounters adds the tracked usage to the per period usage counters when the
+// secondary sink is enabled. Rows are added once, so reprocessing an event doesn't count it
+// again. Feature usage in ClickHouse is the source of truth, so failures are logged and never
+// fail the event.
+func (s *featureUsageTrackingService) incrementUsageCounters(ctx context.Context, featureUsage []*events.FeatureUsage) {
+	if s.UsageCounterRepo == nil || !s.Config.FeatureUsageTracking.UsageCounterSinkEnabled {
+		return
+	}
+
+	if err := s.UsageCounterRepo.Add(ctx, featureUsage); err != nil {
+		s.Logger.Errorw("failed to increment usage counters",
+			"event_id", featureUsage[0].ID,
+			"error", err,
+		)
+	}
+}
+
 // Generate a unique hash for deduplication
-// there are 2 cases:
+// there are 3 cases:
 // 1. event_name + event_id // for non COUNT_UNIQUE aggregation types
 // 2. event_name + event_field_name + event_field_value // for COUNT_UNIQUE aggregation types
-func (s *featureUsageTrackingService) generateUniqueHash(event *events.Event, meter *meter.Meter) string {
+// 3. event_name + customer_id + period_id // for COUNT_ONCE_PER_PERIOD aggregation types
+// 4. event_name + customer_id + heartbeat interval // for UPTIME aggregation types
+func (s *featureUsageTrackingService) generateUniqueHash(event *events.Event, meter *meter.Meter, customerID string, periodID uint64) string {
 	hashStr := fmt.Sprintf("%s:%s", event.EventName, event.ID)
 
-	// For meters with field-based aggregation, include the field value in the hash
-	if meter.Aggregation.Type == types.AggregationCountUnique && meter.Aggregation.Field != "" {
-		if fieldValue, ok := event.Properties[meter.Aggregation.Field]; ok {
-			hashStr = fmt.Sprintf("%s:%s:%v", event.EventName, meter.Aggregation.Field, fieldValue)
+	switch meter.Aggregation.Type {
+	case types.AggregationCountUnique:
+		// For meters with field-based aggregation, include the field value in the hash
+		if meter.Aggregation.Field != "" {
+			if fieldValue, ok := event.Properties[meter.Aggregation.Field]; ok {
+				hashStr = fmt.Sprintf("%s:%s:%v", event.EventName, meter.Aggregation.Field, fieldValue)
+			}
+		}
+	case types.AggregationCountOncePerPeriod:
+		// All events of a customer in a period share the hash so they are counted once
+		hashStr = fmt.Sprintf("%s:%s:%d", event.EventName, customerID, periodID)
+	case types.AggregationUptime:
+		// Heartbeats of a customer in the same interval share the hash so the interval counts once
+		if interval := int64(meter.Aggregation.HeartbeatInterval().Seconds()); interval > 0 {
+			hashStr = fmt.Sprintf("%s:%s:%d", event.EventName, customerID, event.Timestamp.Unix()/interval)
 		}
 	}
 
@@ -341,9 +641,47 @@ func (s *featureUsageTrackingService) generateUniqueHash(event *events.Event, me
 	return hex.EncodeToString(hash[:])
 }
 
+// usageRecordOverride replaces the per-event meter matching and quantity extraction
+// when preparing feature usage for a pre-aggregated usage record
+type usageRecordOverride struct {
+	meterID  string
+	quantity decimal.Decimal
+	// periodID is the period the usage is placed into, 0 derives it from the timestamp
+	periodID uint64
+}
+
+// unbilledEventTracker records why preparing an event produced no feature usage. The last
+// recorded reason wins, so with many subscriptions it is the reason of the last one checked.
+type unbilledEventTracker struct {
+	reason types.UnbilledEventReason
+}
+
+func (t *unbilledEventTracker) skip(reason types.UnbilledEventReason) {
+	if t != nil {
+		t.reason = reason
+	}
+}
+
+// explaining reports whether the event is only prepared to explain why it produced no usage
+func (t *unbilledEventTracker) explaining() bool {
+	return t != nil
+}
+
 func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context, event *events.Event) ([]*events.FeatureUsage, error) {
+	return s.prepareFeatureUsage(ctx, event, nil, nil)
+}
+
+// prepareFeatureUsage resolves the subscriptions, prices and meters the event is billed against.
+// For usage records the event only carries the customer and period, it is matched to the
+// record's meter and the recorded quantity is used as is. When a tracker is given it is told
+// why the event was skipped and the event is only explained: no metrics are recorded and an
+// event that can't be placed into a period is reported as skipped rather than failed.
+func (s *featureUsageTrackingService) prepareFeatureUsage(ctx context.Context, event *events.Event, record *usageRecordOverride, skips *unbilledEventTracker) ([]*events.FeatureUsage, error) {
 	subscriptionService := NewSubscriptionService(s.ServiceParams)
 
+	// Events without an external customer id may carry it in a property
+	s.resolveExternalCustomerID(event)
+
 	// Create a base processed event
 	baseProcessedEvent := event.ToProcessedEvent()
 
@@ -360,6 +698,12 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 		)
 		// Simply skip the event if customer not found
 		// TODO: add sentry span for customer not found
+		if ierr.IsInvalidOperation(err) {
+			// Several customers have the external id and the lookup conflict policy doesn't pick one
+			skips.skip(types.UnbilledEventReasonAmbiguousCustomer)
+		} else {
+			skips.skip(types.UnbilledEventReasonCustomerNotFound)
+		}
 		return results, nil
 	}
 
@@ -369,15 +713,27 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 		baseProcessedEvent.CustomerID = customer.ID
 	}
 
+	// Events timestamped before the customer was created are handled per the configured policy
+	preCreationPolicy := s.preCustomerCreationPolicy()
+	beforeCustomerCreation := event.Timestamp.Before(customer.CreatedAt)
+	if beforeCustomerCreation && preCreationPolicy == types.PreCustomerCreationEventPolicySkip {
+		s.Logger.Debugw("event timestamp before customer creation, skipping",
+			"event_id", event.ID,
+			"customer_id", customer.ID,
+			"event_timestamp", event.Timestamp,
+			"customer_created_at", customer.CreatedAt,
+			"reason", "before_customer_creation",
+		)
+		skips.skip(types.UnbilledEventReasonBeforeCustomerCreation)
+		return results, nil
+	}
+
 	// CASE 2: Get active subscriptions
 	filter := types.NewSubscriptionFilter()
 	filter.CustomerID = customer.ID
 	filter.WithLineItems = true
 	filter.Expand = lo.ToPtr(string(types.ExpandPrices) + "," + string(types.ExpandMeters) + "," + string(types.ExpandFeatures))
-	filter.SubscriptionStatus = []types.SubscriptionStatus{
-		types.SubscriptionStatusActive,
-		types.SubscriptionStatusTrialing,
-	}
+	filter.SubscriptionStatus = usageSubscriptionStatuses
 
 	subscriptionsList, err := subscriptionService.ListSubscriptions(ctx, filter)
 	if err != nil {
@@ -397,15 +753,69 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 			"customer_id", customer.ID,
 		)
 		// TODO: add sentry span for no active subscriptions found
+		skips.skip(types.UnbilledEventReasonNoActiveSubscription)
 		return results, nil
 	}
 
+	// Events naming a subscription are only billed to it, it has to be one of the customer's
+	if subscriptionID := s.targetSubscriptionID(event); subscriptionID != "" {
+		subscriptions = lo.Filter(subscriptions, func(sub *dto.SubscriptionResponse, _ int) bool {
+			return sub.ID == subscriptionID
+		})
+		if len(subscriptions) == 0 {
+			s.Logger.Warnw("subscription named by event not found for customer, skipping",
+				"event_id", event.ID,
+				"customer_id", customer.ID,
+				"subscription_id", subscriptionID,
+			)
+			skips.skip(types.UnbilledEventReasonSubscriptionNotFound)
+			return results, nil
+		}
+	}
+
+	if beforeCustomerCreation && preCreationPolicy == types.PreCustomerCreationEventPolicyClamp {
+		s.clampEventToCustomerStart(event, customer, subscriptions)
+	}
+
+	correlationID := s.correlationID(event)
+
 	// Filter subscriptions to only include those that are active for the event timestamp
+	// and resolve the paused subscription policy for events received during a pause
+	pausedPolicy := s.pausedSubscriptionPolicy()
+	accruedPeriodIDs := make(map[string]uint64) // Map subscription_id -> period id usage accrues to
 	validSubscriptions := make([]*dto.SubscriptionResponse, 0)
 	for _, sub := range subscriptions {
-		if s.isSubscriptionValidForEvent(sub, event) {
-			validSubscriptions = append(validSubscriptions, sub)
+		if !s.isSubscriptionValidForEvent(sub, event) {
+			skips.skip(lo.Ternary(sub.UsageFrozen, types.UnbilledEventReasonUsageFrozen, types.UnbilledEventReasonOutsideSubscription))
+			continue
+		}
+
+		pause, err := s.getPauseForEvent(ctx, sub, event)
+		if err != nil {
+			s.Logger.Errorw("failed to get subscription pause",
+				"event_id", event.ID,
+				"subscription_id", sub.ID,
+				"error", err,
+			)
+			return results, err
+		}
+
+		if pause != nil {
+			switch pausedPolicy {
+			case types.PausedSubscriptionUsagePolicySkip:
+				s.Logger.Debugw("event received during subscription pause, skipping",
+					"event_id", event.ID,
+					"subscription_id", sub.ID,
+					"pause_id", pause.ID,
+				)
+				skips.skip(types.UnbilledEventReasonSubscriptionPaused)
+				continue
+			case types.PausedSubscriptionUsagePolicyAccrue:
+				accruedPeriodIDs[sub.ID] = uint64(pause.OriginalPeriodStart.Unix() * 1000)
+			}
 		}
+
+		validSubscriptions = append(validSubscriptions, sub)
 	}
 
 	subscriptions = validSubscriptions
@@ -513,9 +923,15 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 		}
 	}
 
+	// Resolve how event names are matched to meters for the tenant
+	eventConfig := getEventConfig(ctx, s.ServiceParams)
+
 	// Process the event against each subscription
 	featureUsagePerSub := make([]*events.FeatureUsage, 0)
 
+	// An explicit period id places the usage into that period instead of the timestamp's
+	overridePeriodID, hasPeriodOverride := s.periodIDOverride(event, record)
+
 	for _, sub := range subscriptions {
 		// Calculate the period ID for this subscription (epoch-ms of period start)
 		periodID, err := types.CalculatePeriodID(
@@ -527,19 +943,54 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 			sub.BillingPeriodCount,
 			sub.BillingPeriod,
 		)
-		if err != nil {
+		if err != nil && !hasPeriodOverride {
+			policy := s.periodCalculationFailurePolicy()
 			s.Logger.Errorw("failed to calculate period id",
 				"event_id", event.ID,
 				"subscription_id", sub.ID,
+				"policy", policy,
+				"reason", "period_calculation_failed",
 				"error", err,
 			)
-			// TODO: add sentry span for failed to calculate period id
+			if !skips.explaining() {
+				s.recordPeriodCalculationFailure(event)
+
+				// Failing the event gets it retried instead of dropping the usage of the subscription
+				if policy == types.PeriodCalculationFailurePolicyError {
+					return results, err
+				}
+			}
+			skips.skip(types.UnbilledEventReasonPeriodCalculationFailed)
 			continue
 		}
 
+		// Usage accrued during a pause belongs to the period interrupted by the pause
+		if accruedPeriodID, ok := accruedPeriodIDs[sub.ID]; ok {
+			periodID = accruedPeriodID
+		}
+
+		// The usage is timestamped in the period it's placed into, so the time bounded queries of
+		// the period count it
+		usageTimestamp := event.Timestamp
+		if hasPeriodOverride {
+			if !isSubscriptionPeriodID(sub, overridePeriodID) {
+				s.Logger.Warnw("event names a period id that is not a period of the subscription, skipping",
+					"event_id", event.ID,
+					"subscription_id", sub.ID,
+					"period_id", overridePeriodID,
+				)
+				skips.skip(types.UnbilledEventReasonInvalidPeriodID)
+				continue
+			}
+			if err != nil || periodID != overridePeriodID {
+				usageTimestamp = periodIDTimestamp(overridePeriodID)
+			}
+			periodID = overridePeriodID
+		}
+
 		// Get active usage-based line items
 		subscriptionLineItems := lo.Filter(sub.LineItems, func(item *subscription.SubscriptionLineItem, _ int) bool {
-			return item.IsUsage() && item.IsActive(event.Timestamp)
+			return item.IsUsage() && item.IsActive(usageTimestamp)
 		})
 
 		if len(subscriptionLineItems) == 0 {
@@ -547,6 +998,7 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 				"event_id", event.ID,
 				"subscription_id", sub.ID,
 			)
+			skips.skip(types.UnbilledEventReasonNoLineItem)
 			continue
 		}
 
@@ -554,7 +1006,7 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 		prices := make([]*price.Price, 0, len(subscriptionLineItems))
 		for _, item := range subscriptionLineItems {
 			if price, ok := priceMap[item.PriceID]; ok {
-				if event.Timestamp.Before(item.StartDate) || (!item.EndDate.IsZero() && event.Timestamp.After(item.EndDate)) {
+				if usageTimestamp.Before(item.StartDate) || (!item.EndDate.IsZero() && usageTimestamp.After(item.EndDate)) {
 					continue
 				}
 				prices = append(prices, price)
@@ -571,7 +1023,12 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 		}
 
 		// Find meters and prices that match this event
-		matches := s.findMatchingPricesForEvent(event, prices, meterMap)
+		var matches []PriceMatch
+		if record != nil {
+			matches = s.findMatchingPricesForMeter(record.meterID, prices, meterMap)
+		} else {
+			matches = s.findMatchingPricesForEvent(event, prices, meterMap, eventConfig.CaseInsensitiveEventNames)
+		}
 
 		if len(matches) == 0 {
 			s.Logger.Debugw("no matching prices/meters found for subscription",
@@ -579,10 +1036,38 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 				"subscription_id", sub.ID,
 				"event_name", event.EventName,
 			)
+			skips.skip(types.UnbilledEventReasonNoMatchingMeter)
 			continue
 		}
 
+		// The status of the subscription at the event, e.g. trialing for usage before the trial
+		// ended even when the subscription is active by now
+		meteringStatus := sub.SubscriptionStatus
+		if gatedBySubscriptionStatus(matches) {
+			meteringStatus, err = meteringSubscriptionStatus(ctx, s.ServiceParams, sub, usageTimestamp)
+			if err != nil {
+				s.Logger.Errorw("failed to get subscription status at event",
+					"event_id", event.ID,
+					"subscription_id", sub.ID,
+					"error", err,
+				)
+				return results, err
+			}
+		}
+
 		for _, match := range matches {
+			// Skip meters that don't record usage in the subscription's status at the event
+			if !match.Meter.CountsInSubscriptionStatus(meteringStatus) {
+				s.Logger.Debugw("meter does not count usage in subscription status",
+					"event_id", event.ID,
+					"subscription_id", sub.ID,
+					"meter_id", match.Meter.ID,
+					"subscription_status", meteringStatus,
+				)
+				skips.skip(types.UnbilledEventReasonMeterStatusExcluded)
+				continue
+			}
+
 			// Find the corresponding line item
 			lineItem, ok := subLineItemMap[match.Price.ID]
 			if !ok {
@@ -595,7 +1080,7 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 			}
 
 			// Create a unique hash for deduplication
-			uniqueHash := s.generateUniqueHash(event, match.Meter)
+			uniqueHash := s.generateUniqueHash(event, match.Meter, customer.ID, periodID)
 
 			// TODO: Check for duplicate events also maybe just call for COUNT_UNIQUE and not all cases
 
@@ -607,9 +1092,11 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 				PriceID:        match.Price.ID,
 				MeterID:        match.Meter.ID,
 				PeriodID:       periodID,
+				CorrelationID:  correlationID,
 				UniqueHash:     uniqueHash,
 				Sign:           1, // Default to positive sign
 			}
+			featureUsageCopy.Timestamp = usageTimestamp
 
 			// Set feature ID if available
 			if feature, ok := featureMeterMap[match.Meter.ID]; ok {
@@ -619,11 +1106,38 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 					"event_id", event.ID,
 					"meter_id", match.Meter.ID,
 				)
+				skips.skip(types.UnbilledEventReasonFeatureNotFound)
 				continue
 			}
 
-			// Extract quantity based on meter aggregation
-			quantity, _ := s.extractQuantityFromEvent(event, match.Meter, sub.Subscription, periodID)
+			// Extract quantity based on meter aggregation, usage records carry their own quantity
+			var quantity decimal.Decimal
+			if record != nil {
+				quantity = record.quantity
+			} else {
+				// Values above the meter's maximum are rejected or lowered to it when extracted
+				var anomaly bool
+				if skips.explaining() {
+					anomaly = s.exceedsMaxEventValue(event, match.Meter)
+				} else {
+					anomaly = s.isEventValueAnomaly(event, match.Meter)
+				}
+				if anomaly && s.maxEventValuePolicy() == types.MaxEventValuePolicyReject {
+					skips.skip(types.UnbilledEventReasonAboveMaxEventValue)
+					continue
+				}
+
+				quantity, _, err = s.extractQuantityFromEvent(event, match.Meter, sub.Subscription, periodID)
+				if err != nil {
+					return results, err
+				}
+				if match.Meter.Aggregation.Type == types.AggregationCounterDelta {
+					quantity, err = s.counterDelta(ctx, event, match.Meter, customer.ID, quantity)
+					if err != nil {
+						return results, err
+					}
+				}
+			}
 
 			// Validate the quantity is positive and within reasonable bounds
 			if quantity.IsNegative() {
@@ -655,13 +1169,70 @@ func (s *featureUsageTrackingService) prepareProcessedEvents(ctx context.Context
 	return results, nil
 }
 
+// counterDelta returns the usage of a COUNTER_DELTA reading, the increase of the cumulative counter
+// since the previous reading of the customer. The first reading only sets the baseline, and a
+// reading lower than the previous one means the counter was reset, so all of it is new usage.
+// Readings are expected in order, a late reading is compared to the reading before it but the
+// increase already recorded for the reading after it isn't recomputed.
+func (s *featureUsageTrackingService) counterDelta(ctx context.Context, event *events.Event, meter *meter.Meter, customerID string, reading decimal.Decimal) (decimal.Decimal, error) {
+	previous, err := s.featureUsageRepo.GetLatestProcessedEventBefore(ctx, customerID, meter.ID, event.Timestamp)
+	if err != nil {
+		s.Logger.Errorw("failed to get the previous counter reading",
+			"event_id", event.ID,
+			"meter_id", meter.ID,
+			"customer_id", customerID,
+			"error", err,
+		)
+		return decimal.Zero, err
+	}
+	if previous == nil {
+		return decimal.Zero, nil
+	}
+
+	previousReading := decimal.Zero
+	if val, ok := previous.Properties[meter.Aggregation.Field]; ok {
+		previousReading, _ = s.convertPropertyToDecimal(val, &previous.Event, meter)
+	}
+	if reading.LessThan(previousReading) {
+		return reading, nil
+	}
+	return reading.Sub(previousReading), nil
+}
+
+// findMatchingPricesForMeter returns the usage prices billed on the given meter
+func (s *featureUsageTrackingService) findMatchingPricesForMeter(
+	meterID string,
+	prices []*price.Price,
+	meterMap map[string]*meter.Meter,
+) []PriceMatch {
+	matches := make([]PriceMatch, 0)
+	for _, price := range prices {
+		if !price.IsUsage() || price.MeterID != meterID {
+			continue
+		}
+
+		meter, ok := meterMap[price.MeterID]
+		if !ok || meter == nil {
+			continue
+		}
+
+		matches = append(matches, PriceMatch{
+			Price: price,
+			Meter: meter,
+		})
+	}
+	return matches
+}
+
 // Find matching prices for an event based on meter configuration and filters
 func (s *featureUsageTrackingService) findMatchingPricesForEvent(
 	event *events.Event,
 	prices []*price.Price,
 	meterMap map[string]*meter.Meter,
+	caseInsensitiveEventNames bool,
 ) []PriceMatch {
 	matches := make([]PriceMatch, 0)
+	normalization := eventNameNormalization(s.Config, s.Logger)
 
 	// Find prices with associated meters
 	for _, price := range prices {
@@ -680,7 +1251,7 @@ func (s *featureUsageTrackingService) findMatchingPricesForEvent(
 		}
 
 		// Skip if meter doesn't match the event name
-		if meter.EventName != event.EventName {
+		if !meter.MatchesEventName(event.EventName, caseInsensitiveEventNames, normalization) {
 			continue
 		}
 
@@ -737,27 +1308,36 @@ func (s *featureUsageTrackingService) checkMeterFilters(event *events.Event, fil
 	return true
 }
 
-// Extract quantity from event based on meter aggregation
-// Returns the quantity and the string representation of the field value
+// Extract quantity from event based on meter aggregation, field values are bounded to the meter's
+// minimum and maximum event value before any multiplier is applied
+// Returns the quantity and the string representation of the field value, unknown aggregation
+// types are extracted as SUM or fail according to the unknown aggregation policy
 func (s *featureUsageTrackingService) extractQuantityFromEvent(
 	event *events.Event,
 	meter *meter.Meter,
 	subscription *subscription.Subscription,
 	periodID uint64,
-) (decimal.Decimal, string) {
+) (decimal.Decimal, string, error) {
 	switch meter.Aggregation.Type {
-	case types.AggregationCount:
+	case types.AggregationCount, types.AggregationCountOncePerPeriod:
 		// For count, always return 1 and empty string for field value
-		return decimal.NewFromInt(1), ""
+		// Once per period counts are deduplicated by their unique hash at aggregation level
+		return decimal.NewFromInt(1), "", nil
 
-	case types.AggregationSum, types.AggregationAvg, types.AggregationLatest, types.AggregationMax:
+	case types.AggregationUptime:
+		// Each heartbeat stands for a heartbeat interval of activity, heartbeats of the same
+		// interval are deduplicated by their unique hash at aggregation level
+		return decimal.NewFromFloat(meter.Aggregation.HeartbeatInterval().Seconds()), "", nil
+
+	case types.AggregationSum, types.AggregationAvg, types.AggregationLatest, types.AggregationMax, types.AggregationCounterDelta:
+		// For COUNTER_DELTA this is the counter reading, the increase is computed from the previous reading
 		if meter.Aggregation.Field == "" {
 			s.Logger.Warnw("aggregation with empty field name",
 				"event_id", event.ID,
 				"meter_id", meter.ID,
 				"aggregation_type", meter.Aggregation.Type,
 			)
-			return decimal.Zero, ""
+			return decimal.Zero, "", nil
 		}
 
 		val, ok := event.Properties[meter.Aggregation.Field]
@@ -768,12 +1348,12 @@ func (s *featureUsageTrackingService) extractQuantityFromEvent(
 				"field", meter.Aggregation.Field,
 				"aggregation_type", meter.Aggregation.Type,
 			)
-			return decimal.Zero, ""
+			return decimal.Zero, "", nil
 		}
 
 		// Convert value to decimal and string with detailed error handling
-		decimalValue, stringValue := s.convertValueToDecimal(val, event, meter)
-		return decimalValue, stringValue
+		decimalValue, stringValue := s.convertPropertyToDecimal(val, event, meter)
+		return meter.Aggregation.BoundEventValue(decimalValue), stringValue, nil
 
 	case types.AggregationSumWithMultiplier:
 		if meter.Aggregation.Field == "" {
@@ -781,15 +1361,17 @@ func (s *featureUsageTrackingService) extractQuantityFromEvent(
 				"event_id", event.ID,
 				"meter_id", meter.ID,
 			)
-			return decimal.Zero, ""
+			return decimal.Zero, "", nil
 		}
 
-		if meter.Aggregation.Multiplier == nil {
+		// The multiplier may be looked up in the rate table by an event property
+		multiplier := meter.Aggregation.MultiplierFor(event.Properties)
+		if multiplier == nil {
 			s.Logger.Warnw("sum_with_multiplier aggregation without multiplier",
 				"event_id", event.ID,
 				"meter_id", meter.ID,
 			)
-			return decimal.Zero, ""
+			return decimal.Zero, "", nil
 		}
 
 		val, ok := event.Properties[meter.Aggregation.Field]
@@ -799,18 +1381,19 @@ func (s *featureUsageTrackingService) extractQuantityFromEvent(
 				"meter_id", meter.ID,
 				"field", meter.Aggregation.Field,
 			)
-			return decimal.Zero, ""
+			return decimal.Zero, "", nil
 		}
 
 		// Convert value to decimal and apply multiplier
-		decimalValue, stringValue := s.convertValueToDecimal(val, event, meter)
+		decimalValue, stringValue := s.convertPropertyToDecimal(val, event, meter)
+		decimalValue = meter.Aggregation.BoundEventValue(decimalValue)
 		if decimalValue.IsZero() {
-			return decimal.Zero, stringValue
+			return decimal.Zero, stringValue, nil
 		}
 
 		// Apply multiplier
-		result := decimalValue.Mul(*meter.Aggregation.Multiplier)
-		return result, stringValue
+		result := decimalValue.Mul(*multiplier)
+		return result, stringValue, nil
 
 	case types.AggregationCountUnique:
 		if meter.Aggregation.Field == "" {
@@ -818,7 +1401,7 @@ func (s *featureUsageTrackingService) extractQuantityFromEvent(
 				"event_id", event.ID,
 				"meter_id", meter.ID,
 			)
-			return decimal.Zero, ""
+			return decimal.Zero, "", nil
 		}
 
 		val, ok := event.Properties[meter.Aggregation.Field]
@@ -828,20 +1411,20 @@ func (s *featureUsageTrackingService) extractQuantityFromEvent(
 				"meter_id", meter.ID,
 				"field", meter.Aggregation.Field,
 			)
-			return decimal.Zero, ""
+			return decimal.Zero, "", nil
 		}
 
 		// For count_unique, we return 1 if the value exists (uniqueness is handled at aggregation level)
 		// and convert the value to string for tracking
 		stringValue := s.convertValueToString(val)
-		return decimal.NewFromInt(1), stringValue
+		return decimal.NewFromInt(1), stringValue, nil
 	case types.AggregationWeightedSum:
 		if meter.Aggregation.Field == "" {
 			s.Logger.Warnw("weighted_sum aggregation with empty field name",
 				"event_id", event.ID,
 				"meter_id", meter.ID,
 			)
-			return decimal.Zero, ""
+			return decimal.Zero, "", nil
 		}
 
 		val, ok := event.Properties[meter.Aggregation.Field]
@@ -851,39 +1434,95 @@ func (s *featureUsageTrackingService) extractQuantityFromEvent(
 				"meter_id", meter.ID,
 				"field", meter.Aggregation.Field,
 			)
-			return decimal.Zero, ""
+			return decimal.Zero, "", nil
 		}
 
 		// Convert value to decimal and apply multiplier
-		decimalValue, stringValue := s.convertValueToDecimal(val, event, meter)
+		decimalValue, stringValue := s.convertPropertyToDecimal(val, event, meter)
+		decimalValue = meter.Aggregation.BoundEventValue(decimalValue)
 		if decimalValue.IsZero() {
-			return decimal.Zero, stringValue
+			return decimal.Zero, stringValue, nil
 		}
 
 		// Apply multiplier
 		result, err := s.getTotalUsageForWeightedSumAggregation(subscription, event, decimalValue, periodID)
 		if err != nil {
-			return decimal.Zero, stringValue
+			s.Logger.Warnw("failed to weigh value for weighted_sum aggregation",
+				"event_id", event.ID,
+				"meter_id", meter.ID,
+				"subscription_id", subscription.ID,
+				"period_id", periodID,
+				"zero_duration_period", types.IsZeroDurationPeriod(err),
+				"error", err,
+			)
+			return decimal.Zero, stringValue, nil
 		}
-		return result, stringValue
+		return result, stringValue, nil
 	default:
-		s.Logger.Warnw("unsupported aggregation type",
-			"event_id", event.ID,
-			"meter_id", meter.ID,
-			"aggregation_type", meter.Aggregation.Type,
-		)
-		return decimal.Zero, ""
-	}
-}
+		extractor, ok := aggregationExtractor(meter.Aggregation.Type)
+		if !ok {
+			if err := s.checkAggregationType(meter); err != nil {
+				return decimal.Zero, "", err
+			}
 
-// convertValueToDecimal converts a property value to decimal and string representation
-func (s *featureUsageTrackingService) convertValueToDecimal(val interface{}, event *events.Event, meter *meter.Meter) (decimal.Decimal, string) {
-	var decimalValue decimal.Decimal
-	var stringValue string
+			// Extract the quantity of the unknown type as SUM
+			sumMeter := *meter
+			sumMeter.Aggregation.Type = types.AggregationSum
+			return s.extractQuantityFromEvent(event, &sumMeter, subscription, periodID)
+		}
 
-	switch v := val.(type) {
-	case float64:
-		decimalValue = decimal.NewFromFloat(v)
+		result, stringValue, err := extractor(event, meter)
+		if err != nil {
+			s.Logger.Warnw("custom aggregation extractor failed",
+				"event_id", event.ID,
+				"meter_id", meter.ID,
+				"aggregation_type", meter.Aggregation.Type,
+				"error", err,
+			)
+			return decimal.Zero, "", nil
+		}
+		return result, stringValue, nil
+	}
+}
+
+// convertPropertyToDecimal converts a property value to decimal and string representation,
+// converting string values with a unit suffix to the unit of the meter first. Ingestion already
+// converts them, this covers the events published to the queue directly.
+func (s *featureUsageTrackingService) convertPropertyToDecimal(val interface{}, event *events.Event, meter *meter.Meter) (decimal.Decimal, string) {
+	str, ok := val.(string)
+	if !ok || meter.Aggregation.Unit == "" {
+		return s.convertValueToDecimal(val, event, meter)
+	}
+
+	value, err := meter.Aggregation.Unit.Parse(str)
+	if err != nil {
+		s.Logger.Warnw("failed to parse value with unit",
+			"event_id", event.ID,
+			"meter_id", meter.ID,
+			"value", str,
+			"unit", meter.Aggregation.Unit,
+			"error", err,
+		)
+		return decimal.Zero, str
+	}
+
+	decimalValue, _ := s.convertValueToDecimal(value.String(), event, meter)
+	return decimalValue, str
+}
+
+// maxStorableQuantity bounds the quantities that fit the Decimal(25,15) qty_total column of
+// feature_usage and events_processed, which holds at most 10 integer digits
+var maxStorableQuantity = decimal.New(1, 10)
+
+// convertValueToDecimal converts a property value to decimal and string representation.
+// Values outside the storable range are skipped, so they can't fail the insert of the whole batch.
+func (s *featureUsageTrackingService) convertValueToDecimal(val interface{}, event *events.Event, meter *meter.Meter) (decimal.Decimal, string) {
+	var decimalValue decimal.Decimal
+	var stringValue string
+
+	switch v := val.(type) {
+	case float64:
+		decimalValue = decimal.NewFromFloat(v)
 		stringValue = fmt.Sprintf("%f", v)
 
 	case float32:
@@ -965,6 +1604,18 @@ func (s *featureUsageTrackingService) convertValueToDecimal(val interface{}, eve
 		return decimal.Zero, stringValue
 	}
 
+	if decimalValue.Abs().GreaterThanOrEqual(maxStorableQuantity) {
+		s.Logger.Warnw("aggregation value out of storable range, skipping",
+			"event_id", event.ID,
+			"meter_id", meter.ID,
+			"field", meter.Aggregation.Field,
+			"value", stringValue,
+			"max_quantity", maxStorableQuantity.String(),
+			"reason", "quantity_out_of_range",
+		)
+		return decimal.Zero, stringValue
+	}
+
 	return decimalValue, stringValue
 }
 
@@ -997,6 +1648,11 @@ type AnalyticsData struct {
 	Addons                map[string]*addon.Addon       // Map of addon ID -> addon
 	Currency              string
 	Params                *events.UsageAnalyticsParams
+	EventSamples          map[string][]*events.Event     // Map of feature ID -> sampled raw events
+	FilterValues          map[string]map[string][]string // Map of price ID -> meter filter key -> values of the contributing events
+	AggregationValues     map[string]map[string][]string // Map of price ID -> aggregation field -> sampled values of the contributing events
+	Warnings              []dto.UsageAnalyticsWarning
+	Calendar              types.Calendar // Periods of the MONTH windows, nil for calendar months
 }
 
 // GetDetailedUsageAnalytics provides detailed usage analytics with filtering, grouping, and time-series data
@@ -1013,7 +1669,94 @@ func (s *featureUsageTrackingService) GetDetailedUsageAnalytics(ctx context.Cont
 	}
 
 	// 3. Process and return response
-	return s.buildAnalyticsResponse(ctx, data, req)
+	resp, err := s.buildAnalyticsResponse(ctx, data, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.paginateAnalyticsResponse(ctx, resp, req)
+}
+
+// paginateAnalyticsResponse keeps the items of the requested page and rounds the costs to the
+// precision configured for the tenant. The items are already sorted by feature name, the total
+// cost stays the cost of all items.
+func (s *featureUsageTrackingService) paginateAnalyticsResponse(ctx context.Context, resp *dto.GetUsageAnalyticsResponse, req *dto.GetUsageAnalyticsRequest) (*dto.GetUsageAnalyticsResponse, error) {
+	if req.Limit > 0 {
+		offset, err := decodeAnalyticsCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		total := len(resp.Items)
+		start := min(offset, total)
+		end := min(start+req.Limit, total)
+		resp.Items = resp.Items[start:end]
+
+		pageTotalCost := decimal.Zero
+		for _, item := range resp.Items {
+			pageTotalCost = pageTotalCost.Add(item.TotalCost)
+		}
+		resp.PageTotalCost = &pageTotalCost
+
+		if end < total {
+			resp.HasMore = true
+			resp.NextCursor = encodeAnalyticsCursor(end)
+		}
+	}
+
+	roundAnalyticsCosts(resp, getAnalyticsConfig(ctx, s.ServiceParams).CostPrecision)
+	return resp, nil
+}
+
+// roundAnalyticsCosts rounds the costs of the response to the precision, nil keeps them at the
+// precision they are computed at. Costs are computed unrounded and only rounded once paged, so
+// the total and the page total are the rounded sums of the exact item costs rather than the sums
+// of rounded ones.
+func roundAnalyticsCosts(resp *dto.GetUsageAnalyticsResponse, precision *int) {
+	if precision == nil {
+		return
+	}
+
+	round := func(amount *decimal.Decimal) *decimal.Decimal {
+		if amount == nil {
+			return nil
+		}
+		return lo.ToPtr(amount.Round(int32(*precision)))
+	}
+	for i := range resp.Items {
+		resp.Items[i].TotalCost = resp.Items[i].TotalCost.Round(int32(*precision))
+		resp.Items[i].SettledCost = round(resp.Items[i].SettledCost)
+	}
+	resp.TotalCost = resp.TotalCost.Round(int32(*precision))
+	resp.SettledTotalCost = round(resp.SettledTotalCost)
+	resp.PageTotalCost = round(resp.PageTotalCost)
+}
+
+// encodeAnalyticsCursor returns the cursor of the page starting at the item offset
+func encodeAnalyticsCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// decodeAnalyticsCursor returns the item offset of the cursor, zero when unset
+func decodeAnalyticsCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		offset, convErr := strconv.Atoi(string(decoded))
+		if convErr == nil && offset >= 0 {
+			return offset, nil
+		}
+	}
+
+	return 0, ierr.NewError("invalid cursor").
+		WithHint("Cursor must be the next_cursor of a previous page").
+		WithReportableDetails(map[string]interface{}{
+			"cursor": cursor,
+		}).
+		Mark(ierr.ErrValidation)
 }
 
 func (s *featureUsageTrackingService) GetDetailedUsageAnalyticsV2(ctx context.Context, req *dto.GetUsageAnalyticsRequest) (*dto.GetUsageAnalyticsResponse, error) {
@@ -1033,10 +1776,11 @@ func (s *featureUsageTrackingService) GetDetailedUsageAnalyticsV2(ctx context.Co
 	var currency string
 
 	// Process each customer and aggregate their analytics data
-	for i, customer := range customers {
+	for _, customer := range customers {
 		// Create a customer-specific request
 		customerReq := *req
 		customerReq.ExternalCustomerID = customer.ExternalID
+		customerReq.ExternalCustomerIDs = nil
 
 		// Fetch analytics data for this customer
 		data, err := s.fetchAnalyticsData(ctx, &customerReq)
@@ -1053,7 +1797,7 @@ func (s *featureUsageTrackingService) GetDetailedUsageAnalyticsV2(ctx context.Co
 		allAnalytics = append(allAnalytics, data.Analytics...)
 
 		// Use the first customer's data structure as the base for aggregation
-		if i == 0 {
+		if aggregatedData == nil {
 			aggregatedData = data
 			currency = data.Currency
 		} else {
@@ -1087,7 +1831,180 @@ func (s *featureUsageTrackingService) GetDetailedUsageAnalyticsV2(ctx context.Co
 	aggregatedData.Currency = currency
 
 	// 3. Process and return response
-	return s.buildAnalyticsResponse(ctx, aggregatedData, req)
+	resp, err := s.buildAnalyticsResponse(ctx, aggregatedData, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.paginateAnalyticsResponse(ctx, resp, req)
+}
+
+// CompareUsageAnalytics runs the usage analytics for both ranges and returns the change of
+// usage and cost per feature. Features with usage in only one of the ranges are compared
+// against zero.
+func (s *featureUsageTrackingService) CompareUsageAnalytics(ctx context.Context, req *dto.CompareUsageAnalyticsRequest) (*dto.CompareUsageAnalyticsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := s.GetDetailedUsageAnalytics(ctx, req.CurrentAnalyticsRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.GetDetailedUsageAnalytics(ctx, req.PreviousAnalyticsRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	deltas := make(map[string]*dto.UsageAnalyticsDelta)
+	deltaFor := func(item dto.UsageAnalyticItem) *dto.UsageAnalyticsDelta {
+		delta, ok := deltas[item.FeatureID]
+		if !ok {
+			delta = &dto.UsageAnalyticsDelta{FeatureID: item.FeatureID}
+			deltas[item.FeatureID] = delta
+		}
+		if delta.FeatureName == "" {
+			delta.FeatureName = item.FeatureName
+		}
+		return delta
+	}
+
+	// Items of a feature are summed since a feature can be billed on several line items
+	for _, item := range current.Items {
+		delta := deltaFor(item)
+		delta.CurrentUsage = delta.CurrentUsage.Add(item.TotalUsage)
+		delta.CurrentCost = delta.CurrentCost.Add(item.TotalCost)
+	}
+	for _, item := range previous.Items {
+		delta := deltaFor(item)
+		delta.PreviousUsage = delta.PreviousUsage.Add(item.TotalUsage)
+		delta.PreviousCost = delta.PreviousCost.Add(item.TotalCost)
+	}
+
+	response := &dto.CompareUsageAnalyticsResponse{
+		Currency:          lo.CoalesceOrEmpty(current.Currency, previous.Currency),
+		CurrentTotalCost:  current.TotalCost,
+		PreviousTotalCost: previous.TotalCost,
+		TotalCostDelta:    current.TotalCost.Sub(previous.TotalCost),
+		Items:             make([]dto.UsageAnalyticsDelta, 0, len(deltas)),
+	}
+
+	featureIDs := lo.Keys(deltas)
+	slices.Sort(featureIDs)
+	for _, featureID := range featureIDs {
+		delta := deltas[featureID]
+		delta.UsageDelta = delta.CurrentUsage.Sub(delta.PreviousUsage)
+		delta.UsageDeltaPercent = deltaPercent(delta.UsageDelta, delta.PreviousUsage)
+		delta.CostDelta = delta.CurrentCost.Sub(delta.PreviousCost)
+		delta.CostDeltaPercent = deltaPercent(delta.CostDelta, delta.PreviousCost)
+		response.Items = append(response.Items, *delta)
+	}
+
+	return response, nil
+}
+
+// ForecastFeatureUsage runs the usage analytics of the feature per period and forecasts the usage
+// of the next period with the requested model. Periods without usage between the request bounds
+// count as zero usage and the usage of the feature's line items is combined per period by the
+// meter's aggregation.
+func (s *featureUsageTrackingService) ForecastFeatureUsage(ctx context.Context, req *dto.ForecastFeatureUsageRequest) (*dto.ForecastFeatureUsageResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	forecaster, err := NewUsageForecaster(req.Model, req.Periods)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics, err := s.GetDetailedUsageAnalytics(ctx, req.AnalyticsRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ForecastFeatureUsageResponse{
+		FeatureID:  req.FeatureID,
+		Model:      req.Model,
+		WindowSize: req.WindowSize,
+	}
+
+	usageByPeriod := make(map[time.Time]decimal.Decimal)
+	for _, item := range analytics.Items {
+		if item.FeatureID != req.FeatureID {
+			continue
+		}
+		response.FeatureName = lo.CoalesceOrEmpty(response.FeatureName, item.FeatureName)
+		for _, point := range item.Points {
+			timestamp := point.Timestamp.UTC()
+			if usage, ok := usageByPeriod[timestamp]; ok {
+				usageByPeriod[timestamp] = combineLineItemUsage(item.AggregationType, usage, point.Usage)
+			} else {
+				usageByPeriod[timestamp] = point.Usage
+			}
+		}
+	}
+
+	// Calendar windows are only filled between points, the windows of the request bounds are
+	// added so the periods without usage at either end count too
+	if _, fixed := req.WindowSize.Duration(); !fixed {
+		for _, bound := range []time.Time{req.StartTime, req.EndTime.Add(-time.Nanosecond)} {
+			window := calendarWindowStart(bound, req.WindowSize)
+			if _, ok := usageByPeriod[window]; !ok {
+				usageByPeriod[window] = decimal.Zero
+			}
+		}
+	}
+
+	points := make([]events.UsageAnalyticPoint, 0, len(usageByPeriod))
+	for timestamp, usage := range usageByPeriod {
+		points = append(points, events.UsageAnalyticPoint{Timestamp: timestamp, Usage: usage})
+	}
+	points = fillAnalyticPointGaps(points, req.WindowSize, nil, req.StartTime, req.EndTime, types.MeterGapFillModeZero)
+
+	history := make([]decimal.Decimal, 0, len(points))
+	response.History = make([]dto.UsageForecastPoint, 0, len(points))
+	for _, point := range points {
+		history = append(history, point.Usage)
+		response.History = append(response.History, dto.UsageForecastPoint{
+			Timestamp: point.Timestamp,
+			Usage:     point.Usage,
+		})
+	}
+
+	response.ForecastUsage = forecaster.Forecast(history)
+	return response, nil
+}
+
+// combineLineItemUsage combines the usage of two line items of a feature in a window. The line
+// items of a feature count the same events, so the maximum, latest and unique count usage is the
+// larger one rather than the sum.
+func combineLineItemUsage(aggregationType types.AggregationType, a, b decimal.Decimal) decimal.Decimal {
+	switch aggregationType {
+	case types.AggregationMax, types.AggregationLatest, types.AggregationCountUnique:
+		return decimal.Max(a, b)
+	default:
+		return a.Add(b)
+	}
+}
+
+// calendarWindowStart returns the start of the WEEK or MONTH analytics window containing t, weeks
+// start on Sunday as they do in the analytics queries
+func calendarWindowStart(t time.Time, windowSize types.WindowSize) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if windowSize == types.WindowSizeWeek {
+		return day.AddDate(0, 0, -int(day.Weekday()))
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// deltaPercent returns the delta as a percentage of the previous value, nil when the
+// previous value is zero since the change can't be expressed as a percentage
+func deltaPercent(delta, previous decimal.Decimal) *decimal.Decimal {
+	if previous.IsZero() {
+		return nil
+	}
+	return lo.ToPtr(delta.Div(previous.Abs()).Mul(decimal.NewFromInt(100)).Round(2))
 }
 
 // validateAnalyticsRequest validates the analytics request
@@ -1098,22 +2015,169 @@ func (s *featureUsageTrackingService) validateAnalyticsRequest(req *dto.GetUsage
 			Mark(ierr.ErrValidation)
 	}
 
-	if req.WindowSize != "" {
-		return req.WindowSize.Validate()
+	if len(req.ExternalCustomerIDs) > 0 {
+		return ierr.NewError("external_customer_ids is not supported").
+			WithHint("Customer segments are only supported by the multi customer analytics").
+			Mark(ierr.ErrValidation)
 	}
 
-	return nil
+	if err := s.validateEventSampleSize(req); err != nil {
+		return err
+	}
+
+	if err := s.validateAnalyticsPage(req); err != nil {
+		return err
+	}
+
+	if err := s.validateAnalyticsEntityType(req); err != nil {
+		return err
+	}
+
+	return s.validateWindowSizes(req)
 }
 
 func (s *featureUsageTrackingService) validateAnalyticsRequestV2(req *dto.GetUsageAnalyticsRequest) error {
+	if err := s.validateAnalyticsSegment(req); err != nil {
+		return err
+	}
+
+	if err := s.validateEventSampleSize(req); err != nil {
+		return err
+	}
+
+	if err := s.validateAnalyticsPage(req); err != nil {
+		return err
+	}
+
+	if err := s.validateAnalyticsEntityType(req); err != nil {
+		return err
+	}
+
+	return s.validateWindowSizes(req)
+}
+
+// validateAnalyticsSegment ensures a customer segment isn't combined with a single customer
+// and stays within the segment size limit
+func (s *featureUsageTrackingService) validateAnalyticsSegment(req *dto.GetUsageAnalyticsRequest) error {
+	if len(req.ExternalCustomerIDs) == 0 {
+		return nil
+	}
+
+	if req.ExternalCustomerID != "" {
+		return ierr.NewError("external_customer_id and external_customer_ids can't be combined").
+			WithHint("Please provide either a single customer or a customer segment").
+			Mark(ierr.ErrValidation)
+	}
+
+	if len(lo.Uniq(req.ExternalCustomerIDs)) > dto.MaxAnalyticsSegmentSize {
+		return ierr.NewError("customer segment too large").
+			WithHintf("A customer segment can have at most %d customers", dto.MaxAnalyticsSegmentSize).
+			WithReportableDetails(map[string]interface{}{
+				"customers":     len(req.ExternalCustomerIDs),
+				"max_customers": dto.MaxAnalyticsSegmentSize,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// validateAnalyticsEntityType ensures the analytics are only restricted to plan or addon usage
+func (s *featureUsageTrackingService) validateAnalyticsEntityType(req *dto.GetUsageAnalyticsRequest) error {
+	switch req.EntityType {
+	case "", types.PRICE_ENTITY_TYPE_PLAN, types.PRICE_ENTITY_TYPE_ADDON:
+		return nil
+	}
+	return ierr.NewError("invalid entity_type").
+		WithHintf("Entity type must be one of %s or %s", types.PRICE_ENTITY_TYPE_PLAN, types.PRICE_ENTITY_TYPE_ADDON).
+		WithReportableDetails(map[string]interface{}{
+			"entity_type": req.EntityType,
+		}).
+		Mark(ierr.ErrValidation)
+}
+
+// validateEventSampleSize ensures the requested event sample size is within bounds
+func (s *featureUsageTrackingService) validateEventSampleSize(req *dto.GetUsageAnalyticsRequest) error {
+	if req.EventSampleSize < 0 || req.EventSampleSize > dto.MaxAnalyticsEventSampleSize {
+		return ierr.NewError("invalid event_sample_size").
+			WithHintf("Event sample size must be between 0 and %d", dto.MaxAnalyticsEventSampleSize).
+			WithReportableDetails(map[string]interface{}{
+				"event_sample_size": req.EventSampleSize,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+	return nil
+}
+
+// validateAnalyticsPage ensures the page size is within bounds and the cursor is valid
+func (s *featureUsageTrackingService) validateAnalyticsPage(req *dto.GetUsageAnalyticsRequest) error {
+	if req.Limit < 0 || req.Limit > dto.MaxAnalyticsPageSize {
+		return ierr.NewError("invalid limit").
+			WithHintf("Limit must be between 0 and %d", dto.MaxAnalyticsPageSize).
+			WithReportableDetails(map[string]interface{}{
+				"limit": req.Limit,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	if req.Cursor != "" && req.Limit == 0 {
+		return ierr.NewError("cursor requires a limit").
+			WithHint("Please provide the limit of the pages along with the cursor").
+			Mark(ierr.ErrValidation)
+	}
+
+	_, err := decodeAnalyticsCursor(req.Cursor)
+	return err
+}
+
+// validateWindowSizes validates the window size and every additional window size requested,
+// including that sub-minute windows are only used over short enough ranges
+func (s *featureUsageTrackingService) validateWindowSizes(req *dto.GetUsageAnalyticsRequest) error {
+	// Analytics default the end time to now
+	startTime, endTime := req.StartTime, req.EndTime
+	if endTime.IsZero() {
+		endTime = time.Now().UTC()
+	}
+
 	if req.WindowSize != "" {
-		return req.WindowSize.Validate()
+		if err := req.WindowSize.Validate(); err != nil {
+			return err
+		}
+		if err := req.WindowSize.ValidateRange(startTime, endTime); err != nil {
+			return err
+		}
+	}
+
+	for _, windowSize := range req.WindowSizes {
+		if windowSize == "" {
+			return ierr.NewError("invalid window size").
+				WithHint("Window sizes must not be empty").
+				Mark(ierr.ErrValidation)
+		}
+		if err := windowSize.Validate(); err != nil {
+			return err
+		}
+		if err := windowSize.ValidateRange(startTime, endTime); err != nil {
+			return err
+		}
+	}
+
+	if req.Calendar != nil {
+		if err := req.Calendar.Validate(); err != nil {
+			return err
+		}
+		if req.WindowSize != types.WindowSizeMonth && !lo.Contains(req.WindowSizes, types.WindowSizeMonth) {
+			return ierr.NewError("calendar requires a MONTH window").
+				WithHint("The accounting calendar defines the MONTH windows, please request the MONTH window size").
+				Mark(ierr.ErrValidation)
+		}
 	}
 
 	return nil
 }
 
-// fetchAnalyticsData fetches all required data sequentially
+// fetchAnalyticsData fetches the customer, subscriptions and analytics in order and then enriches
+// the analytics with their metadata
 func (s *featureUsageTrackingService) fetchAnalyticsData(ctx context.Context, req *dto.GetUsageAnalyticsRequest) (*AnalyticsData, error) {
 	// 1. Fetch customer
 	customer, err := s.fetchCustomer(ctx, req.ExternalCustomerID)
@@ -1122,7 +2186,7 @@ func (s *featureUsageTrackingService) fetchAnalyticsData(ctx context.Context, re
 	}
 
 	// 2. Fetch subscriptions
-	subscriptions, err := s.fetchSubscriptions(ctx, customer.ID)
+	subscriptions, err := s.fetchSubscriptions(ctx, customer.ID, req.StartTime, req.EndTime)
 	if err != nil {
 		return nil, err
 	}
@@ -1136,6 +2200,13 @@ func (s *featureUsageTrackingService) fetchAnalyticsData(ctx context.Context, re
 	// 4. Create params and fetch analytics
 	params := s.createAnalyticsParams(ctx, req)
 	params.CustomerID = customer.ID
+	if req.BillableOnly {
+		params.BillableOnly = true
+		params.BillableWindows, err = s.billableWindows(ctx, subscriptions)
+		if err != nil {
+			return nil, err
+		}
+	}
 	analytics, err := s.fetchAnalytics(ctx, params)
 	if err != nil {
 		return nil, err
@@ -1156,6 +2227,12 @@ func (s *featureUsageTrackingService) fetchAnalyticsData(ctx context.Context, re
 		Plans:                 make(map[string]*plan.Plan),
 		Addons:                make(map[string]*addon.Addon),
 		PriceResponses:        make(map[string]*dto.PriceResponse),
+		EventSamples:          make(map[string][]*events.Event),
+		FilterValues:          make(map[string]map[string][]string),
+		AggregationValues:     make(map[string]map[string][]string),
+	}
+	if req.Calendar != nil {
+		data.Calendar = req.Calendar
 	}
 
 	// Build subscription maps
@@ -1175,36 +2252,496 @@ func (s *featureUsageTrackingService) fetchAnalyticsData(ctx context.Context, re
 			)
 			// Continue with partial data rather than failing completely
 		}
-	}
-
-	return data, nil
-}
 
-// buildAnalyticsResponse processes the data and builds the final response
-func (s *featureUsageTrackingService) buildAnalyticsResponse(ctx context.Context, data *AnalyticsData, req *dto.GetUsageAnalyticsRequest) (*dto.GetUsageAnalyticsResponse, error) {
-	// If no results, return early
-	if len(data.Analytics) == 0 {
-		return s.ToGetUsageAnalyticsResponseDTO(ctx, data, req)
+		// Usage of meters with an aggregation type that isn't handled would be reported as SUM
+		for _, m := range data.Meters {
+			if err := s.checkAggregationType(m); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	// Calculate costs
-	if err := s.calculateCosts(ctx, data); err != nil {
-		s.Logger.Warnw("failed to calculate costs",
-			"error", err,
-			"analytics_count", len(data.Analytics),
-		)
-		// Continue with partial data rather than failing completely
+	// 7. Fill the points of every requested window size
+	if len(req.WindowSizes) > 0 && len(analytics) > 0 {
+		if err := s.fetchAnalyticsWindows(ctx, data, req.WindowSizes); err != nil {
+			return nil, err
+		}
 	}
 
-	// Set currency on all analytics items
-	if data.Currency != "" {
+	// MONTH windows of an accounting calendar were queried daily and are rolled up into its periods
+	if data.Calendar != nil && req.WindowSize == types.WindowSizeMonth && len(analytics) > 0 {
+		if !s.canRollUpAnalytics(data) {
+			return nil, errAnalyticsCalendarRollUp()
+		}
 		for _, item := range data.Analytics {
-			item.Currency = data.Currency
+			item.Points = s.rollUpAnalyticPointsBy(item.Points, data.Calendar.PeriodStart)
 		}
+		data.Params.WindowSize = types.WindowSizeMonth
 	}
 
-	// Aggregate results by requested grouping dimensions
-	data.Analytics = s.aggregateAnalyticsByGrouping(data.Analytics, data.Params.GroupBy)
+	// Report points for the windows without events of gauges that fill gaps
+	if len(analytics) > 0 {
+		s.fillAnalyticsGaps(data)
+	}
+
+	// 8. Fetch raw event samples if requested
+	if req.EventSampleSize > 0 && len(analytics) > 0 {
+		if err := s.fetchEventSamples(ctx, data, req.EventSampleSize); err != nil {
+			s.Logger.Warnw("failed to fetch event samples for analytics",
+				"error", err,
+				"customer_id", customer.ID,
+			)
+			// Samples are best-effort debugging data
+		}
+	}
+
+	// 9. Fetch the values that matched the meter filters if requested
+	if lo.Contains(req.Expand, "matched_filters") && len(analytics) > 0 {
+		if err := s.fetchFilterValues(ctx, data); err != nil {
+			s.Logger.Warnw("failed to fetch matched meter filters for analytics",
+				"error", err,
+				"customer_id", customer.ID,
+			)
+			// Matched filters are best-effort explanation data
+		}
+	}
+
+	// 10. Fetch the sampled values of the aggregation fields if requested
+	if lo.Contains(req.Expand, "aggregation_field") && len(analytics) > 0 {
+		if err := s.fetchAggregationValues(ctx, data); err != nil {
+			s.Logger.Warnw("failed to fetch aggregation field values for analytics",
+				"error", err,
+				"customer_id", customer.ID,
+			)
+			// Aggregation field values are best-effort explanation data
+		}
+	}
+
+	return data, nil
+}
+
+// rollUpAggregationTypes are the aggregations whose points can be combined into coarser windows
+var rollUpAggregationTypes = []types.AggregationType{
+	types.AggregationSum,
+	types.AggregationSumWithMultiplier,
+	types.AggregationCount,
+	types.AggregationMax,
+	types.AggregationLatest,
+}
+
+// fetchAnalyticsWindows fills the points of the items for each requested window size. The
+// analytics are queried at the finest requested window and coarser fixed length windows are
+// rolled up from those points. Calendar windows, and items whose aggregation can't be rolled
+// up, are queried for each window size instead. The MONTH windows of an accounting calendar
+// are always rolled up from daily, or finer, points.
+func (s *featureUsageTrackingService) fetchAnalyticsWindows(ctx context.Context, data *AnalyticsData, windowSizes []types.WindowSize) error {
+	windowSizes = lo.Uniq(windowSizes)
+	slices.SortFunc(windowSizes, func(a, b types.WindowSize) int {
+		return a.Compare(b)
+	})
+
+	// Window sizes whose points are already on the items
+	var fetched []types.WindowSize
+	for _, item := range data.Analytics {
+		item.PointsByWindow = make(map[types.WindowSize][]events.UsageAnalyticPoint, len(windowSizes))
+		if data.Params.WindowSize != "" {
+			item.PointsByWindow[data.Params.WindowSize] = slices.Clone(item.Points)
+		}
+	}
+	if data.Params.WindowSize != "" {
+		fetched = append(fetched, data.Params.WindowSize)
+	}
+
+	canRollUp := s.canRollUpAnalytics(data)
+	for _, windowSize := range windowSizes {
+		if lo.Contains(fetched, windowSize) {
+			continue
+		}
+
+		if windowSize == types.WindowSizeMonth && data.Calendar != nil {
+			if !canRollUp {
+				return errAnalyticsCalendarRollUp()
+			}
+
+			source, ok := lo.Find(fetched, func(w types.WindowSize) bool {
+				return w == types.WindowSizeDay || w.CanRollUpTo(types.WindowSizeDay)
+			})
+			if !ok {
+				if err := s.fetchAnalyticsWindowPoints(ctx, data, types.WindowSizeDay); err != nil {
+					return err
+				}
+				source = types.WindowSizeDay
+				fetched = append(fetched, source)
+			}
+			for _, item := range data.Analytics {
+				item.PointsByWindow[windowSize] = s.rollUpAnalyticPointsBy(item.PointsByWindow[source], data.Calendar.PeriodStart)
+			}
+			fetched = append(fetched, windowSize)
+			continue
+		}
+
+		if source, ok := lo.Find(fetched, func(w types.WindowSize) bool {
+			return canRollUp && w.CanRollUpTo(windowSize)
+		}); ok {
+			for _, item := range data.Analytics {
+				item.PointsByWindow[windowSize] = s.rollUpAnalyticPoints(item.PointsByWindow[source], windowSize)
+			}
+			fetched = append(fetched, windowSize)
+			continue
+		}
+
+		if err := s.fetchAnalyticsWindowPoints(ctx, data, windowSize); err != nil {
+			return err
+		}
+		fetched = append(fetched, windowSize)
+	}
+
+	// The query window, and windows only fetched to be rolled up, are only kept when requested
+	for _, windowSize := range fetched {
+		if !lo.Contains(windowSizes, windowSize) {
+			for _, item := range data.Analytics {
+				delete(item.PointsByWindow, windowSize)
+			}
+		}
+	}
+
+	return nil
+}
+
+// fetchAnalyticsWindowPoints queries the analytics at the window size and sets their points on
+// the matching items
+func (s *featureUsageTrackingService) fetchAnalyticsWindowPoints(ctx context.Context, data *AnalyticsData, windowSize types.WindowSize) error {
+	windowParams := *data.Params
+	windowParams.WindowSize = windowSize
+	windowAnalytics, err := s.fetchAnalytics(ctx, &windowParams)
+	if err != nil {
+		return err
+	}
+
+	pointsByItem := make(map[string][]events.UsageAnalyticPoint, len(windowAnalytics))
+	for _, windowItem := range windowAnalytics {
+		pointsByItem[s.analyticsItemKey(windowItem)] = windowItem.Points
+	}
+	for _, item := range data.Analytics {
+		item.PointsByWindow[windowSize] = pointsByItem[s.analyticsItemKey(item)]
+	}
+	return nil
+}
+
+// errAnalyticsCalendarRollUp is returned when the usage can't be reported in the periods of an
+// accounting calendar, which are rolled up from daily points
+func errAnalyticsCalendarRollUp() error {
+	return ierr.NewError("usage can't be reported in accounting calendar periods").
+		WithHint("Accounting calendars are only supported for features with SUM, COUNT, MAX or LATEST meters").
+		Mark(ierr.ErrValidation)
+}
+
+// canRollUpAnalytics reports whether the points of every item can be combined into coarser
+// windows. Distinct counts and averages can't be derived from smaller windows, bucketed
+// max meters compute their points per bucket and rolling window max meters have no points.
+func (s *featureUsageTrackingService) canRollUpAnalytics(data *AnalyticsData) bool {
+	for _, item := range data.Analytics {
+		if !lo.Contains(rollUpAggregationTypes, item.AggregationType) {
+			return false
+		}
+
+		feature, ok := data.Features[item.FeatureID]
+		if !ok {
+			return false
+		}
+		meter, ok := data.Meters[feature.MeterID]
+		if !ok || meter.IsBucketedMaxMeter() || meter.IsRollingWindowMaxMeter() {
+			return false
+		}
+	}
+	return true
+}
+
+// rollUpAnalyticPoints combines points into windows of the given fixed length window size
+func (s *featureUsageTrackingService) rollUpAnalyticPoints(points []events.UsageAnalyticPoint, windowSize types.WindowSize) []events.UsageAnalyticPoint {
+	windowLength, _ := windowSize.Duration()
+	return s.rollUpAnalyticPointsBy(points, func(t time.Time) time.Time {
+		return t.Truncate(windowLength)
+	})
+}
+
+// rollUpAnalyticPointsBy combines points into the windows starting at the time windowStart
+// returns for them
+func (s *featureUsageTrackingService) rollUpAnalyticPointsBy(points []events.UsageAnalyticPoint, windowStart func(time.Time) time.Time) []events.UsageAnalyticPoint {
+	points = slices.Clone(points)
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+
+	result := make([]events.UsageAnalyticPoint, 0, len(points))
+	for _, point := range points {
+		windowStart := windowStart(point.Timestamp)
+
+		if n := len(result); n > 0 && result[n-1].Timestamp.Equal(windowStart) {
+			current := &result[n-1]
+			current.Usage = current.Usage.Add(point.Usage)
+			current.MaxUsage = decimal.Max(current.MaxUsage, point.MaxUsage)
+			current.LatestUsage = point.LatestUsage
+			current.CountUniqueUsage += point.CountUniqueUsage
+			current.EventCount += point.EventCount
+			continue
+		}
+
+		result = append(result, events.UsageAnalyticPoint{
+			Timestamp:        windowStart,
+			Usage:            point.Usage,
+			MaxUsage:         point.MaxUsage,
+			LatestUsage:      point.LatestUsage,
+			CountUniqueUsage: point.CountUniqueUsage,
+			EventCount:       point.EventCount,
+		})
+	}
+
+	return result
+}
+
+// fillAnalyticsGaps adds points for the windows without events of items whose meter fills gaps
+func (s *featureUsageTrackingService) fillAnalyticsGaps(data *AnalyticsData) {
+	for _, item := range data.Analytics {
+		feature, ok := data.Features[item.FeatureID]
+		if !ok {
+			continue
+		}
+		meter, ok := data.Meters[feature.MeterID]
+		if !ok || meter.Aggregation.FillGaps == "" {
+			continue
+		}
+
+		mode := meter.Aggregation.FillGaps
+		if data.Params.WindowSize != "" {
+			item.Points = fillAnalyticPointGaps(item.Points, data.Params.WindowSize, data.Calendar, data.Params.StartTime, data.Params.EndTime, mode)
+		}
+		for windowSize, points := range item.PointsByWindow {
+			item.PointsByWindow[windowSize] = fillAnalyticPointGaps(points, windowSize, data.Calendar, data.Params.StartTime, data.Params.EndTime, mode)
+		}
+	}
+}
+
+// fillAnalyticPointGaps adds a point for every window between start and end without one, with
+// zero usage or the usage of the previous point carried over. Fixed length windows are filled
+// over the whole range, calendar windows (WEEK and MONTH) only between the existing points
+// since their boundaries depend on the billing anchor. MONTH windows of an accounting calendar
+// are its periods and are filled over the whole range. Filled points have no events.
+func fillAnalyticPointGaps(points []events.UsageAnalyticPoint, windowSize types.WindowSize, calendar types.Calendar, start, end time.Time, mode types.MeterGapFillMode) []events.UsageAnalyticPoint {
+	points = slices.Clone(points)
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+
+	windowLength, fixed := windowSize.Duration()
+	fiscal := windowSize == types.WindowSizeMonth && calendar != nil
+	next := func(t time.Time) time.Time {
+		if fixed {
+			return t.Add(windowLength)
+		}
+		if fiscal {
+			return calendar.NextPeriodStart(t)
+		}
+		if windowSize == types.WindowSizeWeek {
+			return t.AddDate(0, 0, 7)
+		}
+		return t.AddDate(0, 1, 0)
+	}
+
+	var first, last time.Time
+	switch {
+	case fixed && !start.IsZero() && !end.IsZero():
+		first = start.Truncate(windowLength)
+		last = end
+	case fiscal && !start.IsZero() && !end.IsZero():
+		first = calendar.PeriodStart(start)
+		last = end
+	case len(points) > 0:
+		first = points[0].Timestamp
+		last = points[len(points)-1].Timestamp.Add(time.Nanosecond)
+	default:
+		return points
+	}
+
+	result := make([]events.UsageAnalyticPoint, 0, len(points))
+	var previous *events.UsageAnalyticPoint
+	i := 0
+	for window := first; window.Before(last); window = next(window) {
+		// Points of windows before this one are kept as they are
+		for i < len(points) && points[i].Timestamp.Before(window) {
+			result = append(result, points[i])
+			previous = &points[i]
+			i++
+		}
+
+		if i < len(points) && points[i].Timestamp.Equal(window) {
+			result = append(result, points[i])
+			previous = &points[i]
+			i++
+			continue
+		}
+
+		filled := events.UsageAnalyticPoint{Timestamp: window}
+		if mode == types.MeterGapFillModeCarryOver && previous != nil {
+			filled.Usage = previous.Usage
+			filled.MaxUsage = previous.MaxUsage
+			filled.LatestUsage = previous.LatestUsage
+		}
+		result = append(result, filled)
+	}
+
+	return append(result, points[i:]...)
+}
+
+// analyticsItemKey identifies an analytics item across queries with the same grouping
+func (s *featureUsageTrackingService) analyticsItemKey(item *events.DetailedUsageAnalytic) string {
+	keyParts := []string{item.FeatureID, item.PriceID, item.SubLineItemID, item.SubscriptionID, item.Source}
+	propertyNames := lo.Keys(item.Properties)
+	slices.Sort(propertyNames)
+	for _, name := range propertyNames {
+		keyParts = append(keyParts, name, item.Properties[name])
+	}
+	return encodeKeyParts(keyParts)
+}
+
+// encodeKeyParts joins the parts of a grouping key. Each part is prefixed with its length so
+// values containing the separator can't shift into their neighbours and collide with another key.
+func encodeKeyParts(keyParts []string) string {
+	var key strings.Builder
+	for _, part := range keyParts {
+		key.WriteString(strconv.Itoa(len(part)))
+		key.WriteByte(':')
+		key.WriteString(part)
+		key.WriteByte('|')
+	}
+	return key.String()
+}
+
+// fetchEventSamples fetches a sample of contributing event IDs per feature from feature_usage
+// and hydrates them from the events table
+func (s *featureUsageTrackingService) fetchEventSamples(ctx context.Context, data *AnalyticsData, sampleSize int) error {
+	sampleSize = lo.Min([]int{sampleSize, dto.MaxAnalyticsEventSampleSize})
+
+	sampleIDs, err := s.featureUsageRepo.GetEventSampleIDs(ctx, data.Params, sampleSize)
+	if err != nil {
+		return err
+	}
+
+	eventIDs := lo.Uniq(lo.Flatten(lo.Values(sampleIDs)))
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	rawEvents, err := s.eventRepo.GetEventsByIDs(ctx, eventIDs)
+	if err != nil {
+		return err
+	}
+
+	eventMap := lo.SliceToMap(rawEvents, func(e *events.Event) (string, *events.Event) {
+		return e.ID, e
+	})
+
+	for featureID, ids := range sampleIDs {
+		for _, id := range ids {
+			if event, ok := eventMap[id]; ok {
+				data.EventSamples[featureID] = append(data.EventSamples[featureID], event)
+			}
+		}
+	}
+
+	return nil
+}
+
+// fetchFilterValues fetches the distinct values of the filter keys of the analytics meters in
+// the contributing events of each price
+func (s *featureUsageTrackingService) fetchFilterValues(ctx context.Context, data *AnalyticsData) error {
+	keys := make([]string, 0)
+	for _, analytic := range data.Analytics {
+		if m, ok := data.Meters[analytic.MeterID]; ok {
+			for _, filter := range m.Filters {
+				keys = append(keys, filter.Key)
+			}
+		}
+	}
+	keys = lo.Uniq(keys)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	values, err := s.featureUsageRepo.GetPropertyValuesByPrice(ctx, data.Params, keys, 0)
+	if err != nil {
+		return err
+	}
+	data.FilterValues = values
+	return nil
+}
+
+// fetchAggregationValues fetches a sample of the distinct values of the aggregation field of the
+// count unique meters of the analytics, per price
+func (s *featureUsageTrackingService) fetchAggregationValues(ctx context.Context, data *AnalyticsData) error {
+	fields := make([]string, 0)
+	for _, analytic := range data.Analytics {
+		if m, ok := data.Meters[analytic.MeterID]; ok && m.Aggregation.Type == types.AggregationCountUnique && m.Aggregation.Field != "" {
+			fields = append(fields, m.Aggregation.Field)
+		}
+	}
+	fields = lo.Uniq(fields)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	values, err := s.featureUsageRepo.GetPropertyValuesByPrice(ctx, data.Params, fields, dto.MaxAnalyticsAggregationValues)
+	if err != nil {
+		return err
+	}
+	data.AggregationValues = values
+	return nil
+}
+
+// matchedFilters returns the meter filters narrowed to the values the price's contributing events
+// matched them with, filters none of the events matched are left out
+func matchedFilters(m *meter.Meter, values map[string][]string) []meter.Filter {
+	matched := make([]meter.Filter, 0, len(m.Filters))
+	for _, filter := range m.Filters {
+		matchedValues := lo.Filter(filter.Values, func(value string, _ int) bool {
+			return lo.Contains(values[filter.Key], value)
+		})
+		if len(matchedValues) == 0 {
+			continue
+		}
+		matched = append(matched, meter.Filter{Key: filter.Key, Values: matchedValues})
+	}
+	return matched
+}
+
+// buildAnalyticsResponse processes the data and builds the final response
+func (s *featureUsageTrackingService) buildAnalyticsResponse(ctx context.Context, data *AnalyticsData, req *dto.GetUsageAnalyticsRequest) (*dto.GetUsageAnalyticsResponse, error) {
+	// If no results, return early
+	if len(data.Analytics) == 0 {
+		return s.ToGetUsageAnalyticsResponseDTO(ctx, data, req)
+	}
+
+	// Calculate costs
+	if err := s.calculateCosts(ctx, data, req.FailOnMissingPrice); err != nil {
+		if req.FailOnMissingPrice {
+			return nil, err
+		}
+		s.Logger.Warnw("failed to calculate costs",
+			"error", err,
+			"analytics_count", len(data.Analytics),
+		)
+		// Continue with partial data rather than failing completely
+	}
+
+	// Set currency on all analytics items
+	if data.Currency != "" {
+		for _, item := range data.Analytics {
+			item.Currency = data.Currency
+		}
+	}
+
+	// Aggregate results by requested grouping dimensions
+	data.Analytics = s.aggregateAnalyticsByGrouping(data.Analytics, data.Params.GroupBy)
 
 	return s.ToGetUsageAnalyticsResponseDTO(ctx, data, req)
 }
@@ -1223,17 +2760,29 @@ func (s *featureUsageTrackingService) fetchCustomer(ctx context.Context, externa
 	return customer, nil
 }
 
-// fetchSubscriptions fetches active subscriptions for a customer
-func (s *featureUsageTrackingService) fetchSubscriptions(ctx context.Context, customerID string) ([]*subscription.Subscription, error) {
+// fetchSubscriptions fetches the subscriptions of a customer active during the range, a zero
+// start or end leaves that side of the range open
+func (s *featureUsageTrackingService) fetchSubscriptions(ctx context.Context, customerID string, startTime, endTime time.Time) ([]*subscription.Subscription, error) {
 	subscriptionService := NewSubscriptionService(s.ServiceParams)
 	filter := types.NewSubscriptionFilter()
 	filter.CustomerID = customerID
 	filter.WithLineItems = true
-	filter.SubscriptionStatus = []types.SubscriptionStatus{
-		types.SubscriptionStatusActive,
-		types.SubscriptionStatusTrialing,
-		types.SubscriptionStatusPaused,
+	// Cancelled subscriptions are included on top of the statuses usage is processed for
+	// so that historical usage can still be priced
+	filter.SubscriptionStatus = append(
+		slices.Clone(usageSubscriptionStatuses),
 		types.SubscriptionStatusCancelled,
+	)
+	// Only subscriptions overlapping the requested range can have usage in it, long cancelled
+	// subscriptions are left out
+	if !startTime.IsZero() || !endTime.IsZero() {
+		filter.ActiveDuring = &types.TimeRangeFilter{}
+		if !startTime.IsZero() {
+			filter.ActiveDuring.StartTime = &startTime
+		}
+		if !endTime.IsZero() {
+			filter.ActiveDuring.EndTime = &endTime
+		}
 	}
 
 	subscriptionsList, err := subscriptionService.ListSubscriptions(ctx, filter)
@@ -1335,16 +2884,17 @@ func (s *featureUsageTrackingService) buildMaxBucketFeatures(ctx context.Context
 			meterMap[m.ID] = m
 		}
 
-		// Check features for bucketed max meters
+		// Check features for bucketed and rolling window max meters
 		for _, f := range features {
 			if meterID := featureToMeterMap[f.ID]; meterID != "" {
-				if m, exists := meterMap[meterID]; exists && m.IsBucketedMaxMeter() {
+				if m, exists := meterMap[meterID]; exists && (m.IsBucketedMaxMeter() || m.IsRollingWindowMaxMeter()) {
 					maxBucketFeatures[f.ID] = &events.MaxBucketFeatureInfo{
-						FeatureID:    f.ID,
-						MeterID:      meterID,
-						BucketSize:   types.WindowSize(m.Aggregation.BucketSize),
-						EventName:    m.EventName,
-						PropertyName: m.Aggregation.Field,
+						FeatureID:          f.ID,
+						MeterID:            meterID,
+						BucketSize:         types.WindowSize(m.Aggregation.BucketSize),
+						EventName:          m.EventName,
+						PropertyName:       m.Aggregation.Field,
+						RollingWindowHours: m.Aggregation.RollingWindowHours,
 					}
 				}
 			}
@@ -1382,12 +2932,89 @@ func (s *featureUsageTrackingService) createAnalyticsParams(ctx context.Context,
 		ExternalCustomerID: req.ExternalCustomerID,
 		FeatureIDs:         req.FeatureIDs,
 		Sources:            req.Sources,
+		PriceIDs:           req.PriceIDs,
 		StartTime:          req.StartTime,
 		EndTime:            req.EndTime,
 		GroupBy:            req.GroupBy,
-		WindowSize:         req.WindowSize,
+		WindowSize:         s.analyticsQueryWindowSize(req),
 		PropertyFilters:    req.PropertyFilters,
+		WeightProperty:     req.WeightProperty,
+	}
+}
+
+// billableWindows returns the windows during which the usage line items of the subscriptions
+// are billed, their active dates within the subscription. Line items of shadow meters are never
+// billed.
+func (s *featureUsageTrackingService) billableWindows(ctx context.Context, subscriptions []*subscription.Subscription) ([]events.BillableWindow, error) {
+	lineItems := make([]*subscription.SubscriptionLineItem, 0)
+	for _, sub := range subscriptions {
+		for _, lineItem := range sub.LineItems {
+			if lineItem.IsUsage() && lineItem.Status == types.StatusPublished {
+				lineItems = append(lineItems, lineItem)
+			}
+		}
 	}
+	if len(lineItems) == 0 {
+		return nil, nil
+	}
+
+	meterIDs := lo.Uniq(lo.Map(lineItems, func(lineItem *subscription.SubscriptionLineItem, _ int) string {
+		return lineItem.MeterID
+	}))
+	meterFilter := types.NewNoLimitMeterFilter()
+	meterFilter.MeterIDs = meterIDs
+	meters, err := s.MeterRepo.List(ctx, meterFilter)
+	if err != nil {
+		return nil, err
+	}
+	shadowMeterIDs := make(map[string]bool, len(meters))
+	for _, m := range meters {
+		shadowMeterIDs[m.ID] = m.IsShadow()
+	}
+
+	subscriptionsMap := lo.KeyBy(subscriptions, func(sub *subscription.Subscription) string {
+		return sub.ID
+	})
+	windows := make([]events.BillableWindow, 0, len(lineItems))
+	for _, lineItem := range lineItems {
+		if shadowMeterIDs[lineItem.MeterID] {
+			continue
+		}
+
+		sub := subscriptionsMap[lineItem.SubscriptionID]
+		window := events.BillableWindow{
+			SubLineItemID: lineItem.ID,
+			StartTime:     lineItem.StartDate,
+			EndTime:       lineItem.EndDate,
+		}
+		if sub != nil {
+			if window.StartTime.IsZero() || window.StartTime.Before(sub.StartDate) {
+				window.StartTime = sub.StartDate
+			}
+			if sub.EndDate != nil && (window.EndTime.IsZero() || sub.EndDate.Before(window.EndTime)) {
+				window.EndTime = *sub.EndDate
+			}
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// analyticsQueryWindowSize returns the window size the analytics are queried at. When only
+// window_sizes is set, the finest of them is queried so coarser windows can be rolled up.
+// MONTH windows of an accounting calendar are queried daily to be rolled up into its periods.
+func (s *featureUsageTrackingService) analyticsQueryWindowSize(req *dto.GetUsageAnalyticsRequest) types.WindowSize {
+	windowSize := req.WindowSize
+	if windowSize == "" && len(req.WindowSizes) > 0 {
+		windowSize = slices.MinFunc(req.WindowSizes, func(a, b types.WindowSize) int {
+			return a.Compare(b)
+		})
+	}
+
+	if req.Calendar != nil && windowSize == types.WindowSizeMonth {
+		return types.WindowSizeDay
+	}
+	return windowSize
 }
 
 // validateCurrency validates currency consistency across subscriptions
@@ -1411,7 +3038,9 @@ func (s *featureUsageTrackingService) validateCurrency(subscriptions []*subscrip
 	return currency, nil
 }
 
-// enrichWithMetadata enriches analytics data with feature, meter, and price information
+// enrichWithMetadata enriches analytics data with feature, meter, and price information.
+// The fetches are independent of each other, except meters which need the features, so they
+// run concurrently and the first error is returned.
 func (s *featureUsageTrackingService) enrichWithMetadata(ctx context.Context, data *AnalyticsData, req *dto.GetUsageAnalyticsRequest) error {
 	// Extract unique feature IDs
 	featureIDs := s.extractUniqueFeatureIDs(data.Analytics)
@@ -1419,12 +3048,61 @@ func (s *featureUsageTrackingService) enrichWithMetadata(ctx context.Context, da
 		return nil
 	}
 
-	// Fetch features with meter expansion
-	featureFilter := types.NewNoLimitFeatureFilter()
-	featureFilter.FeatureIDs = featureIDs
-	featureFilter.Expand = lo.ToPtr(string(types.ExpandMeters))
-	features, err := s.FeatureRepo.List(ctx, featureFilter)
-	if err != nil {
+	var planMap map[string]*plan.Plan
+	var addonMap map[string]*addon.Addon
+
+	g, gCtx := errgroup.WithContext(ctx)
+
+	// Fetch features and then their meters
+	g.Go(func() error {
+		return s.fetchFeaturesAndMeters(gCtx, data, featureIDs)
+	})
+
+	// Fetch prices from subscription line items
+	g.Go(func() error {
+		return s.fetchSubscriptionPrices(gCtx, data)
+	})
+
+	if req.Expand != nil && lo.Contains(req.Expand, "plan") {
+		g.Go(func() error {
+			var err error
+			planMap, err = s.fetchPlans(gCtx, data)
+			return err
+		})
+	}
+	if req.Expand != nil && lo.Contains(req.Expand, "addon") {
+		g.Go(func() error {
+			var err error
+			addonMap, err = s.fetchAddons(gCtx, data)
+			return err
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	if planMap != nil {
+		data.Plans = planMap
+	}
+	if addonMap != nil {
+		data.Addons = addonMap
+	}
+
+	// Enrich analytics with metadata
+	s.enrichAnalyticsWithMetadata(data)
+
+	return nil
+}
+
+// fetchFeaturesAndMeters fetches the features of the analytics and the meters they track
+func (s *featureUsageTrackingService) fetchFeaturesAndMeters(ctx context.Context, data *AnalyticsData, featureIDs []string) error {
+	// Fetch features with meter expansion
+	featureFilter := types.NewNoLimitFeatureFilter()
+	featureFilter.FeatureIDs = featureIDs
+	featureFilter.Expand = lo.ToPtr(string(types.ExpandMeters))
+	features, err := s.FeatureRepo.List(ctx, featureFilter)
+	if err != nil {
 		return ierr.WithError(err).
 			WithHint("Failed to fetch features for enrichment").
 			Mark(ierr.ErrDatabase)
@@ -1457,29 +3135,6 @@ func (s *featureUsageTrackingService) enrichWithMetadata(ctx context.Context, da
 		}
 	}
 
-	// Fetch prices from subscription line items
-	if err := s.fetchSubscriptionPrices(ctx, data); err != nil {
-		return err
-	}
-
-	if req.Expand != nil && lo.Contains(req.Expand, "plan") {
-		planMap, err := s.fetchPlans(ctx, data)
-		if err != nil {
-			return err
-		}
-		data.Plans = planMap
-	}
-	if req.Expand != nil && lo.Contains(req.Expand, "addon") {
-		addonMap, err := s.fetchAddons(ctx, data)
-		if err != nil {
-			return err
-		}
-		data.Addons = addonMap
-	}
-
-	// Enrich analytics with metadata
-	s.enrichAnalyticsWithMetadata(data)
-
 	return nil
 }
 
@@ -1591,26 +3246,77 @@ func (s *featureUsageTrackingService) enrichAnalyticsWithMetadata(data *Analytic
 				item.MeterID = meter.ID
 				item.EventName = meter.EventName
 				item.AggregationType = meter.Aggregation.Type
+				item.HeartbeatInterval = meter.Aggregation.HeartbeatInterval()
 			}
 		}
 	}
 }
 
 // calculateCosts calculates costs for analytics items
-func (s *featureUsageTrackingService) calculateCosts(ctx context.Context, data *AnalyticsData) error {
+// Usage recorded against a price that couldn't be fetched is costed at zero and flagged with a
+// warning, or fails the calculation when failOnMissingPrice is set.
+func (s *featureUsageTrackingService) calculateCosts(ctx context.Context, data *AnalyticsData, failOnMissingPrice bool) error {
 	priceService := NewPriceService(s.ServiceParams)
 
+	// Usage is charged against the commitment of its subscription in order, as on invoices
+	remainingCommitments := make(map[string]decimal.Decimal)
+	missingPrices := make(map[string]bool)
+
 	for _, item := range data.Analytics {
 		if feature, ok := data.Features[item.FeatureID]; ok {
 			if meter, ok := data.Meters[feature.MeterID]; ok {
+				if _, hasPricing := data.Prices[item.PriceID]; !hasPricing && item.PriceID != "" {
+					if failOnMissingPrice {
+						return ierr.NewError("price not found for usage").
+							WithHint("Usage was recorded against a price that could not be found").
+							WithReportableDetails(map[string]interface{}{
+								"feature_id": item.FeatureID,
+								"price_id":   item.PriceID,
+							}).
+							Mark(ierr.ErrNotFound)
+					}
+
+					if !missingPrices[item.FeatureID+":"+item.PriceID] {
+						missingPrices[item.FeatureID+":"+item.PriceID] = true
+						s.Logger.Warnw("price not found for usage analytics, reporting zero cost",
+							"feature_id", item.FeatureID,
+							"price_id", item.PriceID,
+						)
+						data.Warnings = append(data.Warnings, dto.UsageAnalyticsWarning{
+							Code:      types.UsageAnalyticsWarningMissingPrice,
+							Message:   "usage was recorded against a price that could not be found, its cost is reported as zero",
+							FeatureID: item.FeatureID,
+							PriceID:   item.PriceID,
+						})
+					}
+				}
+
 				// Use price_id from the analytics item - this ensures we use the correct price
 				// that was active when the usage was recorded (important for cancelled/new subscriptions)
 				if price, hasPricing := data.Prices[item.PriceID]; hasPricing {
+					// Usage of shadow meters is reported without cost
+					if meter.IsShadow() {
+						if !meter.IsBucketedMaxMeter() {
+							item.TotalUsage = s.getCorrectUsageValue(item, meter.Aggregation.Type)
+						}
+						item.Currency = price.Currency
+						continue
+					}
+
+					commitment := s.analyticsCommitment(data.SubscriptionsMap[item.SubscriptionID], remainingCommitments, data.Params.StartTime, data.Params.EndTime)
+
 					// Calculate cost based on meter type
+					var breakdown dto.EffectiveCostBreakdown
 					if meter.IsBucketedMaxMeter() {
-						s.calculateBucketedCost(ctx, priceService, item, price)
+						breakdown = s.calculateBucketedCost(ctx, priceService, item, price, commitment)
 					} else {
-						s.calculateRegularCost(ctx, priceService, item, meter, price)
+						breakdown = s.calculateRegularCost(ctx, priceService, item, meter, price, data.SubscriptionLineItems[item.SubLineItemID], commitment)
+					}
+
+					if commitment != nil {
+						remainingCommitments[item.SubscriptionID] = breakdown.RemainingCommitment
+						commitmentAdjustedCost := breakdown.Total
+						item.CommitmentAdjustedCost = &commitmentAdjustedCost
 					}
 				}
 			}
@@ -1620,23 +3326,103 @@ func (s *featureUsageTrackingService) calculateCosts(ctx context.Context, data *
 	return nil
 }
 
-// calculateBucketedCost calculates cost for bucketed max meters
-func (s *featureUsageTrackingService) calculateBucketedCost(ctx context.Context, priceService PriceService, item *events.DetailedUsageAnalytic, price *price.Price) {
+// analyticsCommitment returns the commitment left on the subscription for the next charge,
+// nil when the subscription has no commitment or the range isn't within one of its billing
+// periods, since the commitment is reset every period
+func (s *featureUsageTrackingService) analyticsCommitment(sub *subscription.Subscription, remainingCommitments map[string]decimal.Decimal, start, end time.Time) *dto.EffectiveCostCommitment {
+	if sub == nil || !withinOneBillingPeriod(sub, start, end) {
+		return nil
+	}
+
+	commitmentAmount := lo.FromPtr(sub.CommitmentAmount)
+	overageFactor := lo.FromPtr(sub.OverageFactor)
+	if !commitmentAmount.GreaterThan(decimal.Zero) || !overageFactor.GreaterThan(decimal.NewFromInt(1)) {
+		return nil
+	}
+
+	remaining, ok := remainingCommitments[sub.ID]
+	if !ok {
+		remaining = commitmentAmount
+	}
+
+	return &dto.EffectiveCostCommitment{
+		Remaining:     remaining,
+		OverageFactor: overageFactor,
+	}
+}
+
+// withinOneBillingPeriod reports whether the range from start to end falls within a single
+// billing period of the subscription. Analytics default the end time to now.
+func withinOneBillingPeriod(sub *subscription.Subscription, start, end time.Time) bool {
+	if start.IsZero() {
+		return false
+	}
+	if end.IsZero() {
+		end = time.Now().UTC()
+	}
+	if !end.After(start) {
+		return false
+	}
+
+	periodIDs := make([]uint64, 0, 2)
+	for _, t := range []time.Time{start, end.Add(-time.Nanosecond)} {
+		periodID, err := types.CalculatePeriodID(
+			t,
+			sub.StartDate,
+			sub.CurrentPeriodStart,
+			sub.CurrentPeriodEnd,
+			sub.BillingAnchor,
+			sub.BillingPeriodCount,
+			sub.BillingPeriod,
+		)
+		if err != nil {
+			return false
+		}
+		periodIDs = append(periodIDs, periodID)
+	}
+	return periodIDs[0] == periodIDs[1]
+}
+
+// addCommitmentAdjustedCosts adds the commitment adjusted costs of merged items, nil unless
+// both items have one
+func addCommitmentAdjustedCosts(a, b *decimal.Decimal) *decimal.Decimal {
+	if a == nil || b == nil {
+		return nil
+	}
+	sum := a.Add(*b)
+	return &sum
+}
+
+// calculateBucketedCost calculates cost for bucketed max meters. Gap filled points have no
+// events and are not buckets, they are reported without cost.
+func (s *featureUsageTrackingService) calculateBucketedCost(ctx context.Context, priceService PriceService, item *events.DetailedUsageAnalytic, price *price.Price, commitment *dto.EffectiveCostCommitment) dto.EffectiveCostBreakdown {
 	var cost decimal.Decimal
 
+	costPoints := func(points []events.UsageAnalyticPoint) {
+		for i := range points {
+			if points[i].EventCount == 0 {
+				points[i].Cost = decimal.Zero
+				continue
+			}
+			points[i].Cost = priceService.CalculateCost(ctx, price, s.getCorrectUsageValueForPoint(points[i], types.AggregationMax, 0))
+		}
+	}
+
 	if len(item.Points) > 0 {
-		// Use points as buckets
-		bucketedValues := make([]decimal.Decimal, len(item.Points))
-		for i, point := range item.Points {
-			bucketedValues[i] = s.getCorrectUsageValueForPoint(point, types.AggregationMax)
+		// Use points with events as buckets
+		bucketedValues := make([]decimal.Decimal, 0, len(item.Points))
+		for _, point := range item.Points {
+			if point.EventCount == 0 {
+				continue
+			}
+			bucketedValues = append(bucketedValues, s.getCorrectUsageValueForPoint(point, types.AggregationMax, 0))
+		}
+		if len(bucketedValues) > 0 {
+			cost = priceService.CalculateBucketedCost(ctx, price, bucketedValues)
 		}
-		cost = priceService.CalculateBucketedCost(ctx, price, bucketedValues)
 
 		// Calculate cost for each point
-		for i := range item.Points {
-			pointCost := priceService.CalculateCost(ctx, price, s.getCorrectUsageValueForPoint(item.Points[i], types.AggregationMax))
-			item.Points[i].Cost = pointCost
-		}
+		costPoints(item.Points)
 	} else {
 		// Treat total usage as single bucket
 		if item.MaxUsage.IsPositive() {
@@ -1645,26 +3431,53 @@ func (s *featureUsageTrackingService) calculateBucketedCost(ctx context.Context,
 		}
 	}
 
-	item.TotalCost = cost
+	for _, points := range item.PointsByWindow {
+		costPoints(points)
+	}
+
+	breakdown := priceService.CalculateEffectiveCost(ctx, dto.EffectiveCostRequest{
+		Price:      price,
+		Quantity:   item.TotalUsage,
+		BaseCost:   &cost,
+		Commitment: commitment,
+	})
+	item.TotalCost = breakdown.BaseCost
 	item.Currency = price.Currency
+	return breakdown
 }
 
-// calculateRegularCost calculates cost for regular meters
-func (s *featureUsageTrackingService) calculateRegularCost(ctx context.Context, priceService PriceService, item *events.DetailedUsageAnalytic, meter *meter.Meter, price *price.Price) {
+// calculateRegularCost calculates cost for regular meters using the same effective cost
+// computation as invoices
+func (s *featureUsageTrackingService) calculateRegularCost(ctx context.Context, priceService PriceService, item *events.DetailedUsageAnalytic, meter *meter.Meter, price *price.Price, lineItem *subscription.SubscriptionLineItem, commitment *dto.EffectiveCostCommitment) dto.EffectiveCostBreakdown {
 	// Set correct usage value
 	item.TotalUsage = s.getCorrectUsageValue(item, meter.Aggregation.Type)
 
 	// Calculate total cost
-	cost := priceService.CalculateCost(ctx, price, item.TotalUsage)
-	item.TotalCost = cost
+	breakdown := priceService.CalculateEffectiveCost(ctx, dto.EffectiveCostRequest{
+		Price:      price,
+		LineItem:   lineItem,
+		Quantity:   item.TotalUsage,
+		Commitment: commitment,
+	})
+	item.TotalCost = breakdown.BaseCost
 	item.Currency = price.Currency
 
 	// Calculate cost for each point
-	for i := range item.Points {
-		pointUsage := s.getCorrectUsageValueForPoint(item.Points[i], meter.Aggregation.Type)
-		pointCost := priceService.CalculateCost(ctx, price, pointUsage)
-		item.Points[i].Cost = pointCost
+	costPoints := func(points []events.UsageAnalyticPoint) {
+		for i := range points {
+			pointBreakdown := priceService.CalculateEffectiveCost(ctx, dto.EffectiveCostRequest{
+				Price:    price,
+				LineItem: lineItem,
+				Quantity: s.getCorrectUsageValueForPoint(points[i], meter.Aggregation.Type, meter.Aggregation.HeartbeatInterval()),
+			})
+			points[i].Cost = pointBreakdown.Total
+		}
+	}
+	costPoints(item.Points)
+	for _, points := range item.PointsByWindow {
+		costPoints(points)
 	}
+	return breakdown
 }
 
 // aggregateAnalyticsByGrouping aggregates analytics results by the requested grouping dimensions
@@ -1685,38 +3498,50 @@ func (s *featureUsageTrackingService) aggregateAnalyticsByGrouping(analytics []*
 		if existing, exists := aggregatedMap[key]; exists {
 			// Aggregate with existing item
 			existing.TotalUsage = existing.TotalUsage.Add(item.TotalUsage)
+			existing.WeightedUsage = existing.WeightedUsage.Add(item.WeightedUsage)
 			existing.MaxUsage = lo.Ternary(existing.MaxUsage.GreaterThan(item.MaxUsage), existing.MaxUsage, item.MaxUsage)
 			existing.LatestUsage = lo.Ternary(existing.LatestUsage.GreaterThan(item.LatestUsage), existing.LatestUsage, item.LatestUsage)
 			existing.CountUniqueUsage += item.CountUniqueUsage
 			existing.EventCount += item.EventCount
 			existing.TotalCost = existing.TotalCost.Add(item.TotalCost)
+			existing.CommitmentAdjustedCost = addCommitmentAdjustedCosts(existing.CommitmentAdjustedCost, item.CommitmentAdjustedCost)
+			existing.Sources = mergeSources(existing.Sources, item)
 
 			// For time series points, we need to merge them by timestamp
 			existing.Points = s.mergeTimeSeriesPoints(existing.Points, item.Points)
+			if existing.PointsByWindow == nil && item.PointsByWindow != nil {
+				existing.PointsByWindow = make(map[types.WindowSize][]events.UsageAnalyticPoint, len(item.PointsByWindow))
+			}
+			for windowSize, points := range item.PointsByWindow {
+				existing.PointsByWindow[windowSize] = s.mergeTimeSeriesPoints(existing.PointsByWindow[windowSize], points)
+			}
 		} else {
 			// Create a new aggregated item
 			aggregated := &events.DetailedUsageAnalytic{
-				FeatureID:        item.FeatureID,
-				PriceID:          item.PriceID,
-				MeterID:          item.MeterID,
-				SubLineItemID:    item.SubLineItemID,
-				SubscriptionID:   item.SubscriptionID,
-				FeatureName:      item.FeatureName,
-				EventName:        item.EventName,
-				Source:           item.Source,
-				Unit:             item.Unit,
-				UnitPlural:       item.UnitPlural,
-				AggregationType:  item.AggregationType,
-				TotalUsage:       item.TotalUsage,
-				MaxUsage:         item.MaxUsage,
-				LatestUsage:      item.LatestUsage,
-				CountUniqueUsage: item.CountUniqueUsage,
-				EventCount:       item.EventCount,
-				TotalCost:        item.TotalCost,
-				Currency:         item.Currency,
-				Properties:       make(map[string]string),
-				Points:           make([]events.UsageAnalyticPoint, len(item.Points)),
+				FeatureID:         item.FeatureID,
+				PriceID:           item.PriceID,
+				MeterID:           item.MeterID,
+				SubLineItemID:     item.SubLineItemID,
+				SubscriptionID:    item.SubscriptionID,
+				FeatureName:       item.FeatureName,
+				EventName:         item.EventName,
+				Sources:           mergeSources(nil, item),
+				Unit:              item.Unit,
+				UnitPlural:        item.UnitPlural,
+				AggregationType:   item.AggregationType,
+				HeartbeatInterval: item.HeartbeatInterval,
+				TotalUsage:        item.TotalUsage,
+				WeightedUsage:     item.WeightedUsage,
+				MaxUsage:          item.MaxUsage,
+				LatestUsage:       item.LatestUsage,
+				CountUniqueUsage:  item.CountUniqueUsage,
+				EventCount:        item.EventCount,
+				TotalCost:         item.TotalCost,
+				Currency:          item.Currency,
+				Properties:        make(map[string]string),
+				Points:            make([]events.UsageAnalyticPoint, len(item.Points)),
 			}
+			aggregated.CommitmentAdjustedCost = item.CommitmentAdjustedCost
 
 			// Copy properties
 			for k, v := range item.Properties {
@@ -1725,6 +3550,12 @@ func (s *featureUsageTrackingService) aggregateAnalyticsByGrouping(analytics []*
 
 			// Copy points
 			copy(aggregated.Points, item.Points)
+			if item.PointsByWindow != nil {
+				aggregated.PointsByWindow = make(map[types.WindowSize][]events.UsageAnalyticPoint, len(item.PointsByWindow))
+				for windowSize, points := range item.PointsByWindow {
+					aggregated.PointsByWindow[windowSize] = slices.Clone(points)
+				}
+			}
 
 			// Set grouping-specific fields
 			s.setGroupingFields(aggregated, item, groupBy)
@@ -1768,7 +3599,19 @@ func (s *featureUsageTrackingService) createGroupingKey(item *events.DetailedUsa
 		}
 	}
 
-	return strings.Join(keyParts, "|")
+	return encodeKeyParts(keyParts)
+}
+
+// mergeSources adds the sources of the item to the sorted set of sources, an item grouped by
+// source contributes its own source
+func mergeSources(sources []string, item *events.DetailedUsageAnalytic) []string {
+	merged := append(slices.Clone(sources), item.Sources...)
+	if item.Source != "" {
+		merged = append(merged, item.Source)
+	}
+	merged = lo.Uniq(merged)
+	slices.Sort(merged)
+	return merged
 }
 
 // setGroupingFields sets the appropriate fields based on the grouping dimensions
@@ -1781,10 +3624,9 @@ func (s *featureUsageTrackingService) setGroupingFields(aggregated *events.Detai
 				aggregated.FeatureID = item.FeatureID
 			}
 		case "source":
-			// For source grouping, keep the first source encountered
-			if aggregated.Source == "" {
-				aggregated.Source = item.Source
-			}
+			// Source is part of the grouping key so all items of the group share it, without
+			// source grouping only the merged set of sources is reported
+			aggregated.Source = item.Source
 		default:
 			if strings.HasPrefix(group, "properties.") {
 				propertyName := strings.TrimPrefix(group, "properties.")
@@ -1798,36 +3640,29 @@ func (s *featureUsageTrackingService) setGroupingFields(aggregated *events.Detai
 	}
 }
 
-// mergeTimeSeriesPoints merges time series points from two analytics items
+// mergeTimeSeriesPoints merges time series points from two analytics items. Points of the same
+// window are combined and their event counts summed. Windows are matched by instant, as the same
+// window can be reported in different locations, and the points of both items are left unchanged.
 func (s *featureUsageTrackingService) mergeTimeSeriesPoints(existing []events.UsageAnalyticPoint, new []events.UsageAnalyticPoint) []events.UsageAnalyticPoint {
-	// Create a map to track points by timestamp
-	pointMap := make(map[time.Time]*events.UsageAnalyticPoint)
-
-	// Add existing points
-	for i := range existing {
-		pointMap[existing[i].Timestamp] = &existing[i]
-	}
-
-	// Merge new points
-	for i := range new {
-		if existingPoint, exists := pointMap[new[i].Timestamp]; exists {
-			// Aggregate with existing point
-			existingPoint.Usage = existingPoint.Usage.Add(new[i].Usage)
-			existingPoint.MaxUsage = lo.Ternary(existingPoint.MaxUsage.GreaterThan(new[i].MaxUsage), existingPoint.MaxUsage, new[i].MaxUsage)
-			existingPoint.LatestUsage = lo.Ternary(existingPoint.LatestUsage.GreaterThan(new[i].LatestUsage), existingPoint.LatestUsage, new[i].LatestUsage)
-			existingPoint.CountUniqueUsage += new[i].CountUniqueUsage
-			existingPoint.EventCount += new[i].EventCount
-			existingPoint.Cost = existingPoint.Cost.Add(new[i].Cost)
-		} else {
-			// Add new point
-			pointMap[new[i].Timestamp] = &new[i]
+	result := make([]events.UsageAnalyticPoint, 0, len(existing)+len(new))
+	indexByWindow := make(map[int64]int, len(existing)+len(new))
+
+	for _, point := range slices.Concat(existing, new) {
+		i, exists := indexByWindow[point.Timestamp.UnixNano()]
+		if !exists {
+			indexByWindow[point.Timestamp.UnixNano()] = len(result)
+			result = append(result, point)
+			continue
 		}
-	}
 
-	// Convert back to slice and sort by timestamp
-	result := make([]events.UsageAnalyticPoint, 0, len(pointMap))
-	for _, point := range pointMap {
-		result = append(result, *point)
+		// Aggregate with the point of the window
+		merged := &result[i]
+		merged.Usage = merged.Usage.Add(point.Usage)
+		merged.MaxUsage = decimal.Max(merged.MaxUsage, point.MaxUsage)
+		merged.LatestUsage = decimal.Max(merged.LatestUsage, point.LatestUsage)
+		merged.CountUniqueUsage += point.CountUniqueUsage
+		merged.EventCount += point.EventCount
+		merged.Cost = merged.Cost.Add(point.Cost)
 	}
 
 	// Sort by timestamp
@@ -1841,38 +3676,46 @@ func (s *featureUsageTrackingService) mergeTimeSeriesPoints(existing []events.Us
 // getCorrectUsageValue returns the correct usage value based on the meter's aggregation type
 func (s *featureUsageTrackingService) getCorrectUsageValue(item *events.DetailedUsageAnalytic, aggregationType types.AggregationType) decimal.Decimal {
 	switch aggregationType {
-	case types.AggregationCountUnique:
+	case types.AggregationCountUnique, types.AggregationCountOncePerPeriod:
 		return decimal.NewFromInt(int64(item.CountUniqueUsage))
+	case types.AggregationUptime:
+		return meter.UptimeUsage(item.CountUniqueUsage, item.HeartbeatInterval, 0)
 	case types.AggregationMax:
 		return item.MaxUsage
 	case types.AggregationLatest:
 		return item.LatestUsage
-	case types.AggregationSum, types.AggregationSumWithMultiplier, types.AggregationAvg, types.AggregationWeightedSum:
+	case types.AggregationSum, types.AggregationSumWithMultiplier, types.AggregationAvg, types.AggregationWeightedSum, types.AggregationCounterDelta:
 		return item.TotalUsage
 	default:
-		// Default to SUM for unknown types
+		// Unknown types fall back to SUM, the error policy rejects them when analytics are fetched
 		return item.TotalUsage
 	}
 }
 
-// getCorrectUsageValueForPoint returns the correct usage value for a time series point based on aggregation type
-func (s *featureUsageTrackingService) getCorrectUsageValueForPoint(point events.UsageAnalyticPoint, aggregationType types.AggregationType) decimal.Decimal {
+// getCorrectUsageValueForPoint returns the correct usage value for a time series point based on
+// aggregation type, the heartbeat interval is only used for UPTIME
+func (s *featureUsageTrackingService) getCorrectUsageValueForPoint(point events.UsageAnalyticPoint, aggregationType types.AggregationType, heartbeatInterval time.Duration) decimal.Decimal {
 	switch aggregationType {
-	case types.AggregationCountUnique:
+	case types.AggregationCountUnique, types.AggregationCountOncePerPeriod:
 		return decimal.NewFromInt(int64(point.CountUniqueUsage))
+	case types.AggregationUptime:
+		return meter.UptimeUsage(point.CountUniqueUsage, heartbeatInterval, 0)
 	case types.AggregationMax:
 		return point.MaxUsage
 	case types.AggregationLatest:
 		return point.LatestUsage
-	case types.AggregationSum, types.AggregationSumWithMultiplier, types.AggregationAvg, types.AggregationWeightedSum:
+	case types.AggregationSum, types.AggregationSumWithMultiplier, types.AggregationAvg, types.AggregationWeightedSum, types.AggregationCounterDelta:
 		return point.Usage
 	default:
-		// Default to SUM for unknown types
+		// Unknown types fall back to SUM, the error policy rejects them when analytics are fetched
 		return point.Usage
 	}
 }
 
-// ReprocessEvents triggers reprocessing of events for a customer or with other filters
+// ReprocessEvents triggers reprocessing of events for a customer or with other filters.
+// The range is reprocessed one calendar month at a time so a long backfill is split into small,
+// independently paginated windows, and it can't exceed the configured max. The start time is
+// required, a missing end time reprocesses up to now.
 func (s *featureUsageTrackingService) ReprocessEvents(ctx context.Context, params *events.ReprocessEventsParams) error {
 	s.Logger.Infow("starting event reprocessing for feature usage tracking",
 		"external_customer_id", params.ExternalCustomerID,
@@ -1881,25 +3724,133 @@ func (s *featureUsageTrackingService) ReprocessEvents(ctx context.Context, param
 		"end_time", params.EndTime,
 	)
 
+	// A missing end time reprocesses up to now, the range is still capped and split
+	if params.EndTime.IsZero() {
+		resolved := *params
+		resolved.EndTime = time.Now().UTC()
+		params = &resolved
+	}
+
+	if err := s.validateReprocessRange(params); err != nil {
+		return err
+	}
+
 	// Set default batch size if not provided
 	batchSize := params.BatchSize
 	if batchSize <= 0 {
 		batchSize = 100
 	}
 
-	// Create find params from reprocess params
+	windows := splitReprocessRange(params.StartTime, params.EndTime)
+	progress := &reprocessProgress{}
+	for i, window := range windows {
+		if err := s.reprocessEventsWindow(ctx, params, window, batchSize, progress); err != nil {
+			return err
+		}
+
+		s.Logger.Infow("reprocessed window for feature usage tracking",
+			"window", i+1,
+			"total_windows", len(windows),
+			"window_start", window.start,
+			"window_end", window.end,
+			"total_events_found", progress.eventsFound,
+			"total_events_published", progress.eventsPublished,
+		)
+	}
+
+	s.Logger.Infow("completed event reprocessing for feature usage tracking",
+		"external_customer_id", params.ExternalCustomerID,
+		"event_name", params.EventName,
+		"windows_processed", len(windows),
+		"batches_processed", progress.batches,
+		"total_events_found", progress.eventsFound,
+		"total_events_published", progress.eventsPublished,
+	)
+
+	return nil
+}
+
+// reprocessWindow is an inclusive time range of events to reprocess
+type reprocessWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+// reprocessProgress aggregates the progress of a reprocess run across its windows
+type reprocessProgress struct {
+	batches         int
+	eventsFound     int
+	eventsPublished int
+}
+
+// validateReprocessRange rejects ranges without a start, inverted ranges and ranges longer than
+// the configured max
+func (s *featureUsageTrackingService) validateReprocessRange(params *events.ReprocessEventsParams) error {
+	if params.StartTime.IsZero() {
+		return ierr.NewError("start_time is required").
+			WithHint("Please provide the start time of the events to reprocess").
+			Mark(ierr.ErrValidation)
+	}
+
+	if params.EndTime.Before(params.StartTime) {
+		return ierr.NewError("end_time must be after start_time").
+			WithHint("Please provide an end time after the start time").
+			WithReportableDetails(map[string]interface{}{
+				"start_time": params.StartTime,
+				"end_time":   params.EndTime,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	maxRange := s.Config.FeatureUsageTracking.ReprocessMaxRange
+	if maxRange > 0 && params.EndTime.Sub(params.StartTime) > maxRange {
+		return ierr.NewError("reprocess range exceeds the maximum allowed range").
+			WithHint("Please reprocess a shorter range, split long backfills into multiple requests").
+			WithReportableDetails(map[string]interface{}{
+				"start_time": params.StartTime,
+				"end_time":   params.EndTime,
+				"max_range":  maxRange.String(),
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// splitReprocessRange splits a bounded range into calendar month windows in UTC. Windows don't
+// overlap, each ends a millisecond (the event timestamp precision) before the next one starts.
+func splitReprocessRange(start, end time.Time) []reprocessWindow {
+	windows := make([]reprocessWindow, 0)
+	windowStart := start
+	for {
+		utc := windowStart.UTC()
+		nextMonth := time.Date(utc.Year(), utc.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		if nextMonth.After(end) {
+			return append(windows, reprocessWindow{start: windowStart, end: end})
+		}
+
+		windows = append(windows, reprocessWindow{start: windowStart, end: nextMonth.Add(-time.Millisecond)})
+		windowStart = nextMonth
+	}
+}
+
+// reprocessEventsWindow publishes the unprocessed events of a window in batches, using keyset
+// pagination to avoid memory issues with large datasets
+func (s *featureUsageTrackingService) reprocessEventsWindow(
+	ctx context.Context,
+	params *events.ReprocessEventsParams,
+	window reprocessWindow,
+	batchSize int,
+	progress *reprocessProgress,
+) error {
 	findParams := &events.FindUnprocessedEventsParams{
 		ExternalCustomerID: params.ExternalCustomerID,
 		EventName:          params.EventName,
-		StartTime:          params.StartTime,
-		EndTime:            params.EndTime,
+		StartTime:          window.start,
+		EndTime:            window.end,
 		BatchSize:          batchSize,
 	}
 
-	// We'll process in batches to avoid memory issues with large datasets
-	processedBatches := 0
-	totalEventsFound := 0
-	totalEventsPublished := 0
 	var lastID string
 	var lastTimestamp time.Time
 
@@ -1919,75 +3870,844 @@ func (s *featureUsageTrackingService) ReprocessEvents(ctx context.Context, param
 				WithReportableDetails(map[string]interface{}{
 					"external_customer_id": params.ExternalCustomerID,
 					"event_name":           params.EventName,
-					"batch":                processedBatches,
+					"window_start":         window.start,
+					"window_end":           window.end,
+					"batch":                progress.batches,
 				}).
 				Mark(ierr.ErrDatabase)
 		}
 
-		eventsCount := len(unprocessedEvents)
-		totalEventsFound += eventsCount
-		s.Logger.Infow("found unprocessed events",
-			"batch", processedBatches,
-			"count", eventsCount,
-			"total_found", totalEventsFound,
-		)
+		eventsCount := len(unprocessedEvents)
+		progress.eventsFound += eventsCount
+		s.Logger.Infow("found unprocessed events",
+			"batch", progress.batches,
+			"count", eventsCount,
+			"total_found", progress.eventsFound,
+		)
+
+		// If no more events, we're done
+		if eventsCount == 0 {
+			return nil
+		}
+
+		// Publish each event to the feature usage tracking topic
+		for _, event := range unprocessedEvents {
+			// hardcoded delay to avoid rate limiting
+			// TODO: remove this to make it configurable
+			if err := s.PublishEvent(ctx, event, true); err != nil {
+				s.Logger.Errorw("failed to publish event for reprocessing for feature usage tracking",
+					"event_id", event.ID,
+					"error", err,
+				)
+				// Continue with other events instead of failing the whole batch
+				continue
+			}
+			progress.eventsPublished++
+
+			// Update the last seen ID and timestamp for next batch
+			lastID = event.ID
+			lastTimestamp = event.Timestamp
+		}
+
+		s.Logger.Infow("published events for reprocessing for feature usage tracking",
+			"batch", progress.batches,
+			"count", eventsCount,
+			"total_published", progress.eventsPublished,
+		)
+
+		// Update for next batch
+		progress.batches++
+
+		// If we didn't get a full batch, we're done
+		if eventsCount < batchSize {
+			return nil
+		}
+	}
+}
+
+// IngestUsageRecord writes pre-aggregated usage as a single feature usage row per subscription
+// it is billed to. The quantity is taken as is, only the subscription, price and billing period
+// are resolved, using the start of the usage period.
+func (s *featureUsageTrackingService) IngestUsageRecord(ctx context.Context, req *dto.IngestUsageRecordRequest) (*dto.IngestUsageRecordResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	meterID := req.MeterID
+	if req.FeatureID != "" {
+		f, err := s.FeatureRepo.Get(ctx, req.FeatureID)
+		if err != nil {
+			return nil, err
+		}
+
+		if f.MeterID == "" || (meterID != "" && meterID != f.MeterID) {
+			return nil, ierr.NewError("feature is not metered by the given meter").
+				WithHint("Usage records can only be ingested for metered features").
+				WithReportableDetails(map[string]interface{}{
+					"feature_id": req.FeatureID,
+					"meter_id":   req.MeterID,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+		meterID = f.MeterID
+	}
+
+	m, err := s.MeterRepo.GetMeter(ctx, meterID)
+	if err != nil {
+		return nil, err
+	}
+
+	source := req.Source
+	if source == "" {
+		source = "usage_record"
+	}
+
+	event := events.NewEvent(
+		m.EventName,
+		types.GetTenantID(ctx),
+		req.ExternalCustomerID,
+		map[string]interface{}{"period_end": req.PeriodEnd.UTC().Format(time.RFC3339)},
+		req.PeriodStart,
+		req.UsageRecordID,
+		"",
+		source,
+		types.GetEnvironmentID(ctx),
+	)
+
+	skips := &unbilledEventTracker{}
+	featureUsage, err := s.prepareFeatureUsage(ctx, event, &usageRecordOverride{
+		meterID:  m.ID,
+		quantity: req.Quantity,
+		periodID: req.PeriodID,
+	}, skips)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(featureUsage) == 0 && skips.reason == types.UnbilledEventReasonInvalidPeriodID {
+		return nil, ierr.NewError("period_id is not a billing period of the customer's subscription").
+			WithHint("The period id must be the start of a current or past billing period of the subscription, in epoch milliseconds").
+			WithReportableDetails(map[string]interface{}{
+				"external_customer_id": req.ExternalCustomerID,
+				"period_id":            req.PeriodID,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	if len(featureUsage) == 0 {
+		return nil, ierr.NewError("no subscription found to bill the usage record to").
+			WithHint("The customer needs an active subscription with a usage price for the meter during the usage period").
+			WithReportableDetails(map[string]interface{}{
+				"external_customer_id": req.ExternalCustomerID,
+				"meter_id":             m.ID,
+				"period_start":         req.PeriodStart,
+			}).
+			Mark(ierr.ErrNotFound)
+	}
+
+	if err := s.featureUsageRepo.BulkInsertProcessedEvents(ctx, featureUsage); err != nil {
+		return nil, err
+	}
+
+	return &dto.IngestUsageRecordResponse{
+		UsageRecordID: event.ID,
+		SubscriptionIDs: lo.Uniq(lo.Map(featureUsage, func(fu *events.FeatureUsage, _ int) string {
+			return fu.SubscriptionID
+		})),
+	}, nil
+}
+
+// projectableAggregationTypes are the aggregations whose usage accumulates over the period,
+// so usage so far can be projected to the end of the period at the current rate
+var projectableAggregationTypes = []types.AggregationType{
+	types.AggregationSum,
+	types.AggregationSumWithMultiplier,
+	types.AggregationCount,
+}
+
+// GetProjectedInvoice previews the invoice of the subscription's current period. Usage charges
+// come from the usage analytics so far and are projected to the end of the period, recurring
+// fixed charges are billed in full.
+func (s *featureUsageTrackingService) GetProjectedInvoice(ctx context.Context, subscriptionID string) (*dto.ProjectedInvoiceResponse, error) {
+	sub, lineItems, err := s.SubRepo.GetWithLineItems(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.CustomerRepo.Get(ctx, sub.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	periodStart, periodEnd := sub.CurrentPeriodStart, sub.CurrentPeriodEnd
+	asOf := time.Now().UTC()
+	if asOf.After(periodEnd) {
+		asOf = periodEnd
+	}
+
+	priceIDs := lo.Uniq(lo.Map(lineItems, func(item *subscription.SubscriptionLineItem, _ int) string {
+		return item.PriceID
+	}))
+	prices, err := s.PriceRepo.List(ctx, types.NewNoLimitPriceFilter().WithPriceIDs(priceIDs))
+	if err != nil {
+		return nil, err
+	}
+	priceMap := lo.KeyBy(prices, func(p *price.Price) string { return p.ID })
+
+	// Usage of shadow meters is projected without an amount since it is never billed
+	shadowMeterIDs := make(map[string]bool)
+	if meterIDs := lo.Uniq(lo.FilterMap(lineItems, func(item *subscription.SubscriptionLineItem, _ int) (string, bool) {
+		return item.MeterID, item.IsUsage() && item.MeterID != ""
+	})); len(meterIDs) > 0 {
+		meterFilter := types.NewNoLimitMeterFilter()
+		meterFilter.MeterIDs = meterIDs
+		meters, err := s.MeterRepo.List(ctx, meterFilter)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range meters {
+			shadowMeterIDs[m.ID] = m.IsShadow()
+		}
+	}
+
+	// Usage so far in the period per subscription line item
+	usageByLineItem := make(map[string]*dto.UsageAnalyticItem)
+	if asOf.After(periodStart) {
+		analytics, err := s.GetDetailedUsageAnalytics(ctx, &dto.GetUsageAnalyticsRequest{
+			ExternalCustomerID: customer.ExternalID,
+			StartTime:          periodStart,
+			EndTime:            asOf,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range analytics.Items {
+			item := &analytics.Items[i]
+			if item.SubscriptionID != sub.ID {
+				continue
+			}
+			if existing, ok := usageByLineItem[item.SubLineItemID]; ok {
+				existing.TotalUsage = existing.TotalUsage.Add(item.TotalUsage)
+				existing.TotalCost = existing.TotalCost.Add(item.TotalCost)
+				continue
+			}
+			usageByLineItem[item.SubLineItemID] = item
+		}
+	}
+
+	priceService := NewPriceService(s.ServiceParams)
+	precision := types.GetCurrencyPrecision(sub.Currency)
+	elapsed := asOf.Sub(periodStart)
+	periodLength := periodEnd.Sub(periodStart)
+
+	response := &dto.ProjectedInvoiceResponse{
+		SubscriptionID: sub.ID,
+		Currency:       sub.Currency,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+		AsOf:           asOf,
+		LineItems:      make([]dto.ProjectedInvoiceLineItem, 0, len(lineItems)),
+		Amount:         decimal.Zero,
+		ProjectedTotal: decimal.Zero,
+	}
+
+	for _, lineItem := range lineItems {
+		if !lineItem.IsActive(asOf) {
+			continue
+		}
+
+		p, ok := priceMap[lineItem.PriceID]
+		if !ok {
+			s.Logger.Warnw("price not found for subscription line item, skipping in projected invoice",
+				"subscription_id", sub.ID,
+				"line_item_id", lineItem.ID,
+				"price_id", lineItem.PriceID,
+			)
+			continue
+		}
+
+		item := dto.ProjectedInvoiceLineItem{
+			SubLineItemID: lineItem.ID,
+			PriceID:       p.ID,
+			PriceType:     lineItem.PriceType,
+			DisplayName:   lineItem.DisplayName,
+		}
+
+		switch {
+		case lineItem.IsUsage():
+			if usage, ok := usageByLineItem[lineItem.ID]; ok {
+				item.FeatureID = usage.FeatureID
+				item.Quantity = usage.TotalUsage
+				item.Amount = usage.TotalCost
+				item.ProjectedQuantity = usage.TotalUsage
+				item.ProjectedAmount = usage.TotalCost
+
+				if elapsed > 0 && elapsed < periodLength && lo.Contains(projectableAggregationTypes, usage.AggregationType) {
+					item.ProjectedQuantity = usage.TotalUsage.
+						Mul(decimal.NewFromInt(int64(periodLength))).
+						Div(decimal.NewFromInt(int64(elapsed)))
+					if !shadowMeterIDs[lineItem.MeterID] {
+						item.ProjectedAmount = priceService.CalculateCost(ctx, p, item.ProjectedQuantity)
+					}
+				}
+			}
+		case p.BillingCadence == types.BILLING_CADENCE_RECURRING:
+			item.Quantity = lineItem.Quantity
+			item.Amount = priceService.CalculateCost(ctx, p, lineItem.Quantity)
+			item.ProjectedQuantity = item.Quantity
+			item.ProjectedAmount = item.Amount
+		default:
+			continue
+		}
+
+		item.Amount = item.Amount.Round(precision)
+		item.ProjectedAmount = item.ProjectedAmount.Round(precision)
+
+		response.LineItems = append(response.LineItems, item)
+		response.Amount = response.Amount.Add(item.Amount)
+		response.ProjectedTotal = response.ProjectedTotal.Add(item.ProjectedAmount)
+	}
+
+	return response, nil
+}
+
+const (
+	// maxOrphanedUsageWindow is the longest window searched for orphaned usage at once
+	maxOrphanedUsageWindow = 31 * 24 * time.Hour
+	// orphanedUsageBatchSize is the number of line items looked up per query
+	orphanedUsageBatchSize = 1000
+)
+
+// FindOrphanedUsage finds feature usage in the window whose subscription line item has been
+// deleted, so it can be cleaned up or re-attributed. The line items with usage in the window are
+// looked up in batches since feature usage and line items live in different stores.
+func (s *featureUsageTrackingService) FindOrphanedUsage(ctx context.Context, params *events.FindOrphanedUsageParams) ([]*events.FeatureUsage, error) {
+	if params.StartTime.IsZero() || params.EndTime.IsZero() || !params.EndTime.After(params.StartTime) {
+		return nil, ierr.NewError("invalid time window").
+			WithHint("Start time and end time are required and end time must be after start time").
+			WithReportableDetails(map[string]interface{}{
+				"start_time": params.StartTime,
+				"end_time":   params.EndTime,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+	if params.EndTime.Sub(params.StartTime) > maxOrphanedUsageWindow {
+		return nil, ierr.NewError("time window is too long").
+			WithHintf("The time window can be at most %d days", int(maxOrphanedUsageWindow.Hours()/24)).
+			WithReportableDetails(map[string]interface{}{
+				"start_time": params.StartTime,
+				"end_time":   params.EndTime,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	subLineItemIDs, err := s.featureUsageRepo.GetUsageSubLineItemIDs(ctx, params.StartTime, params.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphanedIDs []string
+	for _, batch := range lo.Chunk(subLineItemIDs, orphanedUsageBatchSize) {
+		filter := types.NewNoLimitSubscriptionLineItemFilter()
+		filter.LineItemIDs = batch
+		lineItems, err := s.SubscriptionLineItemRepo.List(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		existing := make(map[string]bool, len(lineItems))
+		for _, lineItem := range lineItems {
+			if lineItem.Status != types.StatusDeleted {
+				existing[lineItem.ID] = true
+			}
+		}
+		for _, id := range batch {
+			if !existing[id] {
+				orphanedIDs = append(orphanedIDs, id)
+			}
+		}
+	}
+
+	orphaned := make([]*events.FeatureUsage, 0)
+	for _, batch := range lo.Chunk(orphanedIDs, orphanedUsageBatchSize) {
+		findParams := *params
+		findParams.SubLineItemIDs = batch
+		usage, err := s.featureUsageRepo.FindOrphanedUsage(ctx, &findParams)
+		if err != nil {
+			return nil, err
+		}
+		orphaned = append(orphaned, usage...)
+	}
+
+	sort.SliceStable(orphaned, func(i, j int) bool {
+		return orphaned[i].Timestamp.Before(orphaned[j].Timestamp)
+	})
+	if params.Limit > 0 && len(orphaned) > params.Limit {
+		orphaned = orphaned[:params.Limit]
+	}
+
+	if len(orphaned) > 0 {
+		s.Logger.Infow("found orphaned feature usage",
+			"count", len(orphaned),
+			"sub_line_item_ids", len(orphanedIDs),
+			"start_time", params.StartTime,
+			"end_time", params.EndTime,
+		)
+	}
+
+	return orphaned, nil
+}
+
+// GetPlatformUsage returns the usage recorded per meter in the window across all tenants and
+// environments, for capacity planning. It ignores the tenant and environment of the context, so
+// it must only be exposed to platform operators and never through tenant-scoped APIs.
+func (s *featureUsageTrackingService) GetPlatformUsage(ctx context.Context, req *dto.GetPlatformUsageRequest) (*dto.GetPlatformUsageResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	totals, err := s.featureUsageRepo.GetPlatformUsageTotals(ctx, &events.PlatformUsageParams{
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.GetPlatformUsageResponse{
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Meters:    totals,
+	}
+	for _, total := range totals {
+		response.TotalEvents += total.EventCount
+	}
+
+	return response, nil
+}
+
+// resolveExternalCustomerID sets the external customer id of an event that has none from the
+// configured property, so producers that only send it inside the properties can be billed. Events
+// ingested through the API are resolved at ingest already.
+func (s *featureUsageTrackingService) resolveExternalCustomerID(event *events.Event) {
+	property := s.Config.FeatureUsageTracking.ExternalCustomerIDProperty
+	if event.ExternalCustomerID != "" || property == "" {
+		return
+	}
+
+	externalCustomerID, ok := events.ExternalCustomerIDFromProperty(event.Properties, property)
+	if !ok {
+		s.Logger.Debugw("external customer id property is missing or not a string or number, ignoring",
+			"event_id", event.ID,
+			"property", property,
+		)
+		return
+	}
+	event.ExternalCustomerID = externalCustomerID
+}
+
+// targetSubscriptionID returns the subscription the event is directed to by the configured
+// property, empty when none is configured or the event doesn't name one
+func (s *featureUsageTrackingService) targetSubscriptionID(event *events.Event) string {
+	return s.stringPropertyValue(event, s.Config.FeatureUsageTracking.SubscriptionIDProperty)
+}
+
+// periodIDOverride returns the period id the usage record or the event's configured property
+// names, false when neither names one. A property that isn't a period id is returned as 0 so
+// the event is skipped rather than billed to the period of its timestamp.
+func (s *featureUsageTrackingService) periodIDOverride(event *events.Event, record *usageRecordOverride) (uint64, bool) {
+	if record != nil && record.periodID != 0 {
+		return record.periodID, true
+	}
+
+	property := s.Config.FeatureUsageTracking.PeriodIDProperty
+	if property == "" {
+		return 0, false
+	}
+
+	value, ok := event.GetNestedProperty(property)
+	if !ok {
+		return 0, false
+	}
+
+	var periodID decimal.Decimal
+	var err error
+	switch v := value.(type) {
+	case string:
+		periodID, err = decimal.NewFromString(strings.TrimSpace(v))
+	case float64:
+		periodID = decimal.NewFromFloat(v)
+	case int:
+		periodID = decimal.NewFromInt(int64(v))
+	case int64:
+		periodID = decimal.NewFromInt(v)
+	case json.Number:
+		periodID, err = decimal.NewFromString(v.String())
+	default:
+		err = fmt.Errorf("unsupported period id type %T", v)
+	}
+
+	if err != nil || !periodID.IsInteger() || !periodID.IsPositive() {
+		s.Logger.Debugw("event period id property is not a period id",
+			"event_id", event.ID,
+			"property", property,
+		)
+		return 0, true
+	}
+	return uint64(periodID.IntPart()), true
+}
+
+// periodIDTimestamp returns the timestamp usage placed into the period is recorded at, the last
+// millisecond of the second the period id names, which is inside the period it starts
+func periodIDTimestamp(periodID uint64) time.Time {
+	return time.UnixMilli(int64(periodID)).UTC().Add(time.Second - time.Millisecond)
+}
+
+// isSubscriptionPeriodID reports whether the period id is the start of one of the subscription's
+// billing periods up to the current one. Future periods are rejected since they aren't open yet.
+func isSubscriptionPeriodID(sub *dto.SubscriptionResponse, periodID uint64) bool {
+	if periodID == 0 || periodID > math.MaxInt64 {
+		return false
+	}
+
+	// Period ids are the period start truncated to the second
+	periodStart := time.UnixMilli(int64(periodID)).UTC()
+	if periodStart.Before(sub.StartDate.Truncate(time.Second)) || periodStart.After(sub.CurrentPeriodStart) {
+		return false
+	}
+	if sub.EndDate != nil && !periodStart.Before(*sub.EndDate) {
+		return false
+	}
+
+	// The last instant of the second the period id names is inside the period it starts
+	calculated, err := types.CalculatePeriodID(
+		periodStart.Add(time.Second-time.Nanosecond),
+		sub.StartDate,
+		sub.CurrentPeriodStart,
+		sub.CurrentPeriodEnd,
+		sub.BillingAnchor,
+		sub.BillingPeriodCount,
+		sub.BillingPeriod,
+	)
+	return err == nil && calculated == periodID
+}
+
+// correlationID returns the trace or correlation id of the event read from the configured
+// property, empty when the event has none
+func (s *featureUsageTrackingService) correlationID(event *events.Event) string {
+	return s.stringPropertyValue(event, s.Config.FeatureUsageTracking.CorrelationIDProperty)
+}
+
+// stringPropertyValue returns the trimmed string value of the property at the dot separated path,
+// empty when the path is empty or the property is missing or not a string
+func (s *featureUsageTrackingService) stringPropertyValue(event *events.Event, property string) string {
+	if property == "" {
+		return ""
+	}
+
+	value, ok := event.GetNestedProperty(property)
+	if !ok {
+		return ""
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		s.Logger.Debugw("event property is not a string, ignoring",
+			"event_id", event.ID,
+			"property", property,
+		)
+		return ""
+	}
+	return strings.TrimSpace(str)
+}
+
+// usageSubscriptionStatuses are the subscription statuses usage events are processed for.
+// Paused subscriptions are included so the paused subscription policy can decide what
+// happens to usage received during a pause; analytics reads the same set of subscriptions.
+var usageSubscriptionStatuses = []types.SubscriptionStatus{
+	types.SubscriptionStatusActive,
+	types.SubscriptionStatusTrialing,
+	types.SubscriptionStatusPaused,
+}
+
+// cancellationBoundary returns the configured cancellation boundary, falling back to inclusive
+// when unset or invalid. It is shared by the event post processing and feature usage tracking
+// so both bill the same events of cancelled subscriptions.
+func cancellationBoundary(params ServiceParams) types.CancellationBoundary {
+	if params.Config == nil || params.Config.Billing.CancellationBoundary == "" {
+		return types.CancellationBoundaryInclusive
+	}
+
+	boundary := params.Config.Billing.CancellationBoundary
+	if err := boundary.Validate(); err != nil {
+		params.Logger.Warnw("invalid cancellation boundary configured, falling back to inclusive",
+			"boundary", boundary,
+			"error", err,
+		)
+		return types.CancellationBoundaryInclusive
+	}
+
+	return boundary
+}
+
+// trialBoundaryPhase returns the configured trial boundary phase, falling back to paid when
+// unset or invalid
+func trialBoundaryPhase(params ServiceParams) types.TrialBoundaryPhase {
+	if params.Config == nil || params.Config.Billing.TrialBoundaryPhase == "" {
+		return types.TrialBoundaryPhasePaid
+	}
+
+	phase := params.Config.Billing.TrialBoundaryPhase
+	if err := phase.Validate(); err != nil {
+		params.Logger.Warnw("invalid trial boundary phase configured, falling back to paid",
+			"phase", phase,
+			"error", err,
+		)
+		return types.TrialBoundaryPhasePaid
+	}
+
+	return phase
+}
+
+// meteringSubscriptionStatus returns the status the subscription was in at the timestamp, the
+// status meters are gated by. It is reconstructed from the recorded transitions of the
+// subscription (its trial, pauses and cancellation) rather than taken from its current status,
+// so usage processed late is metered the same as usage processed right away. Statuses without a
+// recorded start, e.g. past due, are taken as current. It is shared by the event post processing
+// and feature usage tracking.
+func meteringSubscriptionStatus(ctx context.Context, params ServiceParams, sub *dto.SubscriptionResponse, timestamp time.Time) (types.SubscriptionStatus, error) {
+	switch sub.SubscriptionStatus {
+	case types.SubscriptionStatusActive, types.SubscriptionStatusTrialing, types.SubscriptionStatusPaused, types.SubscriptionStatusCancelled:
+	default:
+		return sub.SubscriptionStatus, nil
+	}
+
+	if sub.SubscriptionStatus == types.SubscriptionStatusCancelled && sub.CancelledAt != nil &&
+		!cancellationBoundary(params).Covers(timestamp, *sub.CancelledAt) {
+		return types.SubscriptionStatusCancelled, nil
+	}
+
+	pauses, err := params.SubRepo.ListPauses(ctx, sub.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, pause := range pauses {
+		if pausedAt(pause, timestamp) {
+			return types.SubscriptionStatusPaused, nil
+		}
+	}
+
+	// The trial follows the phase the timestamp falls in
+	if sub.TrialEnd == nil {
+		return lo.Ternary(sub.SubscriptionStatus == types.SubscriptionStatusTrialing, types.SubscriptionStatusTrialing, types.SubscriptionStatusActive), nil
+	}
+
+	var window time.Duration
+	if params.Config != nil {
+		window = max(params.Config.Billing.TrialBoundaryWindow, 0)
+	}
+	if trialBoundaryPhase(params).InTrial(timestamp, *sub.TrialEnd, window) {
+		return types.SubscriptionStatusTrialing, nil
+	}
+	return types.SubscriptionStatusActive, nil
+}
+
+// pausedAt reports whether the subscription was paused by the pause at the timestamp, up to
+// the end of the pause or its resumption if it was resumed earlier
+func pausedAt(pause *subscription.SubscriptionPause, timestamp time.Time) bool {
+	if pause.PauseStatus != types.PauseStatusActive && pause.PauseStatus != types.PauseStatusCompleted {
+		return false
+	}
+	if timestamp.Before(pause.PauseStart) {
+		return false
+	}
+
+	end := pause.PauseEnd
+	if pause.ResumedAt != nil && (end == nil || pause.ResumedAt.Before(*end)) {
+		end = pause.ResumedAt
+	}
+	return end == nil || timestamp.Before(*end)
+}
+
+// gatedBySubscriptionStatus reports whether any of the matched meters only records usage in
+// some subscription statuses
+func gatedBySubscriptionStatus(matches []PriceMatch) bool {
+	return lo.SomeBy(matches, func(match PriceMatch) bool {
+		return len(match.Meter.SubscriptionStatuses) > 0
+	})
+}
+
+// pausedSubscriptionPolicy returns the configured paused subscription policy,
+// falling back to skip when it is unset or invalid
+func (s *featureUsageTrackingService) pausedSubscriptionPolicy() types.PausedSubscriptionUsagePolicy {
+	policy := s.Config.FeatureUsageTracking.PausedSubscriptionPolicy
+	if policy == "" {
+		return types.PausedSubscriptionUsagePolicySkip
+	}
+
+	if err := policy.Validate(); err != nil {
+		s.Logger.Warnw("invalid paused subscription policy configured, falling back to skip",
+			"policy", policy,
+			"error", err,
+		)
+		return types.PausedSubscriptionUsagePolicySkip
+	}
+
+	return policy
+}
+
+// periodCalculationFailurePolicy returns the configured period calculation failure policy,
+// falling back to skip when it is unset or invalid
+func (s *featureUsageTrackingService) periodCalculationFailurePolicy() types.PeriodCalculationFailurePolicy {
+	policy := s.Config.FeatureUsageTracking.PeriodCalculationFailurePolicy
+	if policy == "" {
+		return types.PeriodCalculationFailurePolicySkip
+	}
+
+	if err := policy.Validate(); err != nil {
+		s.Logger.Warnw("invalid period calculation failure policy configured, falling back to skip",
+			"policy", policy,
+			"error", err,
+		)
+		return types.PeriodCalculationFailurePolicySkip
+	}
+
+	return policy
+}
+
+// maxEventValuePolicy returns the configured max event value policy
+func (s *featureUsageTrackingService) maxEventValuePolicy() types.MaxEventValuePolicy {
+	return maxEventValuePolicy(s.Config, s.Logger)
+}
+
+// unknownAggregationPolicy returns the configured policy for aggregation types without usage
+// handling, falling back to sum when it is unset or invalid
+func (s *featureUsageTrackingService) unknownAggregationPolicy() types.UnknownAggregationPolicy {
+	policy := s.Config.FeatureUsageTracking.UnknownAggregationPolicy
+	if policy == "" {
+		return types.UnknownAggregationPolicySum
+	}
+
+	if err := policy.Validate(); err != nil {
+		s.Logger.Warnw("invalid unknown aggregation policy configured, falling back to sum",
+			"policy", policy,
+			"error", err,
+		)
+		return types.UnknownAggregationPolicySum
+	}
+
+	return policy
+}
+
+// aggregationExtractor returns the registered extractor for a custom aggregation type
+func aggregationExtractor(aggregationType types.AggregationType) (meter.AggregationExtractor, bool) {
+	return meter.GetAggregationExtractor(aggregationType)
+}
+
+// checkAggregationType returns an error for a meter whose aggregation type is neither built in
+// nor has a registered extractor when the unknown aggregation policy is error, under the sum
+// policy the type is reported and treated as SUM
+func (s *featureUsageTrackingService) checkAggregationType(m *meter.Meter) error {
+	if m.Aggregation.Type.Validate() {
+		return nil
+	}
+	if _, ok := aggregationExtractor(m.Aggregation.Type); ok {
+		return nil
+	}
+
+	if s.unknownAggregationPolicy() == types.UnknownAggregationPolicyError {
+		return ierr.NewError("unsupported aggregation type").
+			WithHint("The meter's aggregation type is not supported").
+			WithReportableDetails(map[string]interface{}{
+				"meter_id":         m.ID,
+				"aggregation_type": m.Aggregation.Type,
+			}).
+			Mark(ierr.ErrInternal)
+	}
+
+	s.Logger.Warnw("unsupported aggregation type, treating it as SUM",
+		"meter_id", m.ID,
+		"aggregation_type", m.Aggregation.Type,
+	)
+	return nil
+}
 
-		// If no more events, we're done
-		if eventsCount == 0 {
-			break
-		}
+// preCustomerCreationPolicy returns the configured policy for events timestamped before the
+// customer was created, falling back to match when it is unset or invalid
+func (s *featureUsageTrackingService) preCustomerCreationPolicy() types.PreCustomerCreationEventPolicy {
+	return preCustomerCreationPolicy(s.Config, s.Logger)
+}
 
-		// Publish each event to the feature usage tracking topic
-		for _, event := range unprocessedEvents {
-			// hardcoded delay to avoid rate limiting
-			// TODO: remove this to make it configurable
-			if err := s.PublishEvent(ctx, event, true); err != nil {
-				s.Logger.Errorw("failed to publish event for reprocessing for feature usage tracking",
-					"event_id", event.ID,
-					"error", err,
-				)
-				// Continue with other events instead of failing the whole batch
-				continue
-			}
-			totalEventsPublished++
+// clampEventToCustomerStart moves an event timestamped before the customer was created to the
+// customer creation, or to the start of the customer's earliest subscription when that is later
+func (s *featureUsageTrackingService) clampEventToCustomerStart(
+	event *events.Event,
+	customer *customer.Customer,
+	subscriptions []*dto.SubscriptionResponse,
+) {
+	clamped := customer.CreatedAt
+	earliest := lo.MinBy(subscriptions, func(a, b *dto.SubscriptionResponse) bool {
+		return a.StartDate.Before(b.StartDate)
+	})
+	if earliest != nil && earliest.StartDate.After(clamped) {
+		clamped = earliest.StartDate
+	}
 
-			// Update the last seen ID and timestamp for next batch
-			lastID = event.ID
-			lastTimestamp = event.Timestamp
-		}
+	s.Logger.Debugw("event timestamp before customer creation, clamping",
+		"event_id", event.ID,
+		"customer_id", customer.ID,
+		"event_timestamp", event.Timestamp,
+		"clamped_timestamp", clamped,
+		"reason", "before_customer_creation",
+	)
+	event.Timestamp = clamped
+}
 
-		s.Logger.Infow("published events for reprocessing for feature usage tracking",
-			"batch", processedBatches,
-			"count", eventsCount,
-			"total_published", totalEventsPublished,
-		)
+// getPauseForEvent returns the active pause of the subscription if the event
+// timestamp falls within its pause window, nil otherwise
+func (s *featureUsageTrackingService) getPauseForEvent(
+	ctx context.Context,
+	sub *dto.SubscriptionResponse,
+	event *events.Event,
+) (*subscription.SubscriptionPause, error) {
+	if sub.PauseStatus != types.PauseStatusActive || sub.ActivePauseID == nil {
+		return nil, nil
+	}
 
-		// Update for next batch
-		processedBatches++
+	pause, err := s.SubRepo.GetPause(ctx, *sub.ActivePauseID)
+	if err != nil {
+		return nil, err
+	}
 
-		// If we didn't get a full batch, we're done
-		if eventsCount < batchSize {
-			break
-		}
+	if event.Timestamp.Before(pause.PauseStart) {
+		return nil, nil
 	}
 
-	s.Logger.Infow("completed event reprocessing for feature usage tracking",
-		"external_customer_id", params.ExternalCustomerID,
-		"event_name", params.EventName,
-		"batches_processed", processedBatches,
-		"total_events_found", totalEventsFound,
-		"total_events_published", totalEventsPublished,
-	)
+	if pause.PauseEnd != nil && !event.Timestamp.Before(*pause.PauseEnd) {
+		return nil, nil
+	}
 
-	return nil
+	return pause, nil
 }
 
 // isSubscriptionValidForEvent checks if a subscription is valid for processing the given event
-// It ensures the event timestamp falls within the subscription's active period
+// It ensures the subscription is metering usage and the event timestamp falls within the
+// subscription's active period
 func (s *featureUsageTrackingService) isSubscriptionValidForEvent(
 	sub *dto.SubscriptionResponse,
 	event *events.Event,
 ) bool {
+	// Frozen subscriptions stay active but don't meter usage
+	if sub.UsageFrozen {
+		s.Logger.Debugw("subscription usage is frozen, skipping event",
+			"event_id", event.ID,
+			"subscription_id", sub.ID,
+			"reason", "usage_frozen",
+		)
+		return false
+	}
+
 	// Event must be after subscription start date
 	if event.Timestamp.Before(sub.StartDate) {
 		s.Logger.Debugw("event timestamp before subscription start date",
@@ -2010,14 +4730,17 @@ func (s *featureUsageTrackingService) isSubscriptionValidForEvent(
 		return false
 	}
 
-	// Additional check: if subscription is cancelled, make sure event is before cancellation
+	// Additional check: if subscription is cancelled, make sure event is before cancellation,
+	// events exactly at the cancellation are billed depending on the configured boundary
 	if sub.SubscriptionStatus == types.SubscriptionStatusCancelled && sub.CancelledAt != nil {
-		if event.Timestamp.After(*sub.CancelledAt) {
+		boundary := cancellationBoundary(s.ServiceParams)
+		if !boundary.Covers(event.Timestamp, *sub.CancelledAt) {
 			s.Logger.Debugw("event timestamp after subscription cancellation date",
 				"event_id", event.ID,
 				"subscription_id", sub.ID,
 				"event_timestamp", event.Timestamp,
 				"subscription_cancelled_at", *sub.CancelledAt,
+				"cancellation_boundary", boundary,
 			)
 			return false
 		}
@@ -2026,11 +4749,55 @@ func (s *featureUsageTrackingService) isSubscriptionValidForEvent(
 	return true
 }
 
+// settleAnalyticsCosts reports the costs of the response in the settlement currency of the
+// environment next to the costs in their pricing currency. Costs priced in a currency without a
+// conversion rate are left out of the settled total and flagged with a warning.
+func (s *featureUsageTrackingService) settleAnalyticsCosts(ctx context.Context, response *dto.GetUsageAnalyticsResponse) {
+	config, err := getSettlementConfig(ctx, s.ServiceParams)
+	if err != nil {
+		s.Logger.Warnw("failed to get settlement config, costs are not settled",
+			"tenant_id", types.GetTenantID(ctx),
+			"environment_id", types.GetEnvironmentID(ctx),
+			"error", err,
+		)
+		return
+	}
+	if config.Currency == "" {
+		return
+	}
+
+	settledTotal := decimal.Zero
+	missingRates := make(map[string]bool)
+	for i := range response.Items {
+		item := &response.Items[i]
+		settled, rate, ok := config.Convert(item.TotalCost, item.Currency)
+		if !ok {
+			// Usage without a price has no currency and nothing to settle
+			if item.Currency != "" && !missingRates[strings.ToLower(item.Currency)] {
+				missingRates[strings.ToLower(item.Currency)] = true
+				response.Warnings = append(response.Warnings, dto.UsageAnalyticsWarning{
+					Code: types.UsageAnalyticsWarningMissingConversionRate,
+					Message: fmt.Sprintf("costs priced in %s have no conversion rate to the settlement currency %s, they are left out of the settled total cost",
+						item.Currency, config.Currency),
+				})
+			}
+			continue
+		}
+		item.SettledCost = &settled
+		item.SettlementRate = &rate
+		settledTotal = settledTotal.Add(settled)
+	}
+
+	response.SettlementCurrency = config.Currency
+	response.SettledTotalCost = &settledTotal
+}
+
 func (s *featureUsageTrackingService) ToGetUsageAnalyticsResponseDTO(ctx context.Context, data *AnalyticsData, req *dto.GetUsageAnalyticsRequest) (*dto.GetUsageAnalyticsResponse, error) {
 	response := &dto.GetUsageAnalyticsResponse{
 		TotalCost: decimal.Zero,
 		Currency:  "",
 		Items:     make([]dto.UsageAnalyticItem, 0, len(data.Analytics)),
+		Warnings:  data.Warnings,
 	}
 
 	// Check which fields should be expanded
@@ -2065,11 +4832,17 @@ func (s *featureUsageTrackingService) ToGetUsageAnalyticsResponseDTO(ctx context
 			AggregationType: analytic.AggregationType,
 			TotalUsage:      totalUsage, // Now correctly uses sum of bucket maxes for bucketed MAX
 			TotalCost:       analytic.TotalCost,
+			EffectiveRate:   effectiveRate(analytic.TotalCost, totalUsage),
 			Currency:        analytic.Currency,
 			EventCount:      analytic.EventCount,
 			Properties:      analytic.Properties,
 			Points:          make([]dto.UsageAnalyticPoint, 0, len(analytic.Points)),
 		}
+		item.CommitmentAdjustedCost = analytic.CommitmentAdjustedCost
+		if req.WeightProperty != "" {
+			weightedUsage := analytic.WeightedUsage
+			item.WeightedUsage = &weightedUsage
+		}
 		// Can expand plan and addon
 		if analytic.PriceID != "" {
 			if price, ok := data.PriceResponses[analytic.PriceID]; ok {
@@ -2095,6 +4868,12 @@ func (s *featureUsageTrackingService) ToGetUsageAnalyticsResponseDTO(ctx context
 			}
 		}
 
+		// Leave out the usage of the other entity type, usage without a plan or addon price has none
+		if (req.EntityType == types.PRICE_ENTITY_TYPE_ADDON && item.AddOnID == "") ||
+			(req.EntityType == types.PRICE_ENTITY_TYPE_PLAN && item.PlanID == "") {
+			continue
+		}
+
 		if expandMap["meter"] && analytic.MeterID != "" {
 			if meter, ok := data.Meters[analytic.MeterID]; ok {
 				item.Meter = meter
@@ -2113,6 +4892,42 @@ func (s *featureUsageTrackingService) ToGetUsageAnalyticsResponseDTO(ctx context
 			}
 		}
 
+		if expandMap["subscription"] && analytic.SubscriptionID != "" {
+			if sub, ok := data.SubscriptionsMap[analytic.SubscriptionID]; ok {
+				item.Subscription = sub
+			}
+		}
+
+		// Report contributing sources if requested
+		if expandMap["sources"] {
+			item.Sources = analytic.Sources
+		}
+
+		// Report which meter filters selected the price if requested
+		if expandMap["matched_filters"] && analytic.PriceID != "" {
+			if m, ok := data.Meters[analytic.MeterID]; ok && len(m.Filters) > 0 {
+				if filters := matchedFilters(m, data.FilterValues[analytic.PriceID]); len(filters) > 0 {
+					item.MatchedFilters = filters
+				}
+			}
+		}
+
+		// Report the property the meter aggregates and, for count unique, the values it counted
+		if expandMap["aggregation_field"] {
+			if m, ok := data.Meters[analytic.MeterID]; ok && m.Aggregation.Field != "" {
+				item.AggregationField = m.Aggregation.Field
+				if m.Aggregation.Type == types.AggregationCountUnique && analytic.PriceID != "" {
+					item.AggregationFieldValues = data.AggregationValues[analytic.PriceID][m.Aggregation.Field]
+				}
+			}
+		}
+
+		// Split the cost of tiered prices by tier if requested, bucketed max meters are billed per
+		// bucket so their usage has no single split
+		if expandMap["tiers"] {
+			item.Tiers = s.analyticsTierBreakup(ctx, data, analytic, totalUsage)
+		}
+
 		// Expand plan if requested
 		if expandMap["plan"] && item.PlanID != "" {
 			if plan, ok := data.Plans[item.PlanID]; ok {
@@ -2127,33 +4942,113 @@ func (s *featureUsageTrackingService) ToGetUsageAnalyticsResponseDTO(ctx context
 			}
 		}
 
+		// Attach raw event samples if requested
+		if samples, ok := data.EventSamples[analytic.FeatureID]; ok && req.EventSampleSize > 0 {
+			if len(samples) > req.EventSampleSize {
+				samples = samples[:req.EventSampleSize]
+			}
+			item.EventSamples = make([]dto.Event, 0, len(samples))
+			for _, event := range samples {
+				item.EventSamples = append(item.EventSamples, dto.Event{
+					ID:                 event.ID,
+					ExternalCustomerID: event.ExternalCustomerID,
+					CustomerID:         event.CustomerID,
+					EventName:          event.EventName,
+					Timestamp:          event.Timestamp,
+					Properties:         event.Properties,
+					Source:             event.Source,
+					EnvironmentID:      event.EnvironmentID,
+					CorrelationID:      s.correlationID(event),
+				})
+			}
+		}
+
 		// Map time-series points if available
 		if req.WindowSize != "" {
 			for _, point := range analytic.Points {
 				// Use the correct usage value based on aggregation type
-				correctUsage := s.getCorrectUsageValueForPoint(point, analytic.AggregationType)
+				correctUsage := s.getCorrectUsageValueForPoint(point, analytic.AggregationType, analytic.HeartbeatInterval)
 				item.Points = append(item.Points, dto.UsageAnalyticPoint{
-					Timestamp:  point.Timestamp,
-					Usage:      correctUsage,
-					Cost:       point.Cost,
-					EventCount: point.EventCount,
+					Timestamp:     point.Timestamp,
+					Usage:         correctUsage,
+					Cost:          point.Cost,
+					EffectiveRate: effectiveRate(point.Cost, correctUsage),
+					EventCount:    point.EventCount,
 				})
 			}
 		}
 
+		// Map the time-series points of each requested window size
+		if len(analytic.PointsByWindow) > 0 {
+			item.PointsByWindow = make(map[types.WindowSize][]dto.UsageAnalyticPoint, len(analytic.PointsByWindow))
+			for windowSize, points := range analytic.PointsByWindow {
+				windowPoints := make([]dto.UsageAnalyticPoint, 0, len(points))
+				for _, point := range points {
+					usage := s.getCorrectUsageValueForPoint(point, analytic.AggregationType, analytic.HeartbeatInterval)
+					windowPoints = append(windowPoints, dto.UsageAnalyticPoint{
+						Timestamp:     point.Timestamp,
+						Usage:         usage,
+						Cost:          point.Cost,
+						EffectiveRate: effectiveRate(point.Cost, usage),
+						EventCount:    point.EventCount,
+					})
+				}
+				item.PointsByWindow[windowSize] = windowPoints
+			}
+		}
+
 		response.Items = append(response.Items, item)
 		response.TotalCost = response.TotalCost.Add(analytic.TotalCost)
 		response.Currency = analytic.Currency
 	}
 
-	// sort by feature name
-	sort.Slice(response.Items, func(i, j int) bool {
-		return response.Items[i].FeatureName < response.Items[j].FeatureName
+	// Settle the exact costs before they are rounded
+	s.settleAnalyticsCosts(ctx, response)
+
+	// sort by feature name, the ties are broken so pages keep the same order across requests
+	sort.SliceStable(response.Items, func(i, j int) bool {
+		a, b := response.Items[i], response.Items[j]
+		if a.FeatureName != b.FeatureName {
+			return a.FeatureName < b.FeatureName
+		}
+		if a.FeatureID != b.FeatureID {
+			return a.FeatureID < b.FeatureID
+		}
+		if a.PriceID != b.PriceID {
+			return a.PriceID < b.PriceID
+		}
+		if a.SubLineItemID != b.SubLineItemID {
+			return a.SubLineItemID < b.SubLineItemID
+		}
+		return a.Source < b.Source
 	})
 
 	return response, nil
 }
 
+// analyticsTierBreakup returns the usage and cost of each tier of the analytic's tiered price, nil
+// when the price isn't tiered or the usage isn't billed as a whole
+func (s *featureUsageTrackingService) analyticsTierBreakup(ctx context.Context, data *AnalyticsData, analytic *events.DetailedUsageAnalytic, totalUsage decimal.Decimal) []dto.TierCostBreakup {
+	p, ok := data.Prices[analytic.PriceID]
+	if !ok || p.BillingModel != types.BILLING_MODEL_TIERED {
+		return nil
+	}
+	if m, ok := data.Meters[analytic.MeterID]; ok && (m.IsBucketedMaxMeter() || m.IsShadow()) {
+		return nil
+	}
+
+	return NewPriceService(s.ServiceParams).CalculateCostWithBreakup(ctx, p, totalUsage, false).Tiers
+}
+
+// effectiveRate returns the blended cost per unit of usage, after tiers and commitments
+// are applied. It is zero when there is no usage to spread the cost over.
+func effectiveRate(cost, usage decimal.Decimal) decimal.Decimal {
+	if usage.IsZero() {
+		return decimal.Zero
+	}
+	return cost.Div(usage)
+}
+
 func (s *featureUsageTrackingService) getTotalUsageForWeightedSumAggregation(
 	subscription *subscription.Subscription,
 	event *events.Event,
@@ -2163,33 +5058,33 @@ func (s *featureUsageTrackingService) getTotalUsageForWeightedSumAggregation(
 	// Convert periodID (epoch milliseconds) back to time for the period start
 	periodStart := time.UnixMilli(int64(periodID))
 
-	// Calculate the period end using the subscription's billing configuration
-	periodEnd, err := types.NextBillingDate(periodStart, subscription.BillingAnchor, subscription.BillingPeriodCount, subscription.BillingPeriod, nil)
-	if err != nil {
-		return decimal.Zero, ierr.WithError(err).
-			WithHint("Failed to calculate period end for weighted sum aggregation").
-			WithReportableDetails(map[string]interface{}{
-				"subscription_id": subscription.ID,
-				"period_id":       periodID,
-				"period_start":    periodStart,
-			}).
-			Mark(ierr.ErrValidation)
+	// The current period ends where the subscription says, other periods end where the
+	// subscription's billing configuration puts them
+	var periodEnd time.Time
+	if periodID == uint64(subscription.CurrentPeriodStart.Unix()*1000) {
+		periodStart, periodEnd = subscription.CurrentPeriodStart, subscription.CurrentPeriodEnd
+	} else {
+		var err error
+		periodEnd, err = types.NextBillingDate(periodStart, subscription.BillingAnchor, subscription.BillingPeriodCount, subscription.BillingPeriod, nil)
+		if err != nil {
+			return decimal.Zero, ierr.WithError(err).
+				WithHint("Failed to calculate period end for weighted sum aggregation").
+				WithReportableDetails(map[string]interface{}{
+					"subscription_id": subscription.ID,
+					"period_id":       periodID,
+					"period_start":    periodStart,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+	}
+
+	// A period without a duration has nothing to weigh the value over
+	if err := types.ValidatePeriodDuration(periodStart, periodEnd); err != nil {
+		return decimal.Zero, err
 	}
 
 	// Calculate total billing period duration in seconds
 	totalPeriodSeconds := periodEnd.Sub(periodStart).Seconds()
-	if totalPeriodSeconds <= 0 {
-		return decimal.Zero, ierr.NewError("invalid billing period duration").
-			WithHint("Billing period duration must be positive").
-			WithReportableDetails(map[string]interface{}{
-				"subscription_id": subscription.ID,
-				"period_id":       periodID,
-				"period_start":    periodStart,
-				"period_end":      periodEnd,
-				"total_seconds":   totalPeriodSeconds,
-			}).
-			Mark(ierr.ErrValidation)
-	}
 
 	// Calculate remaining seconds from event timestamp to period end
 	remainingSeconds := math.Max(0, periodEnd.Sub(event.Timestamp).Seconds())
@@ -2254,6 +5149,8 @@ func (s *featureUsageTrackingService) fetchCustomers(ctx context.Context, req *d
 			return nil, err
 		}
 		return []*customer.Customer{cust}, nil
+	} else if len(req.ExternalCustomerIDs) > 0 {
+		return s.fetchSegmentCustomers(ctx, req.ExternalCustomerIDs)
 	} else {
 		customers, err := s.CustomerRepo.List(ctx, types.NewNoLimitCustomerFilter())
 		if err != nil {
@@ -2265,6 +5162,39 @@ func (s *featureUsageTrackingService) fetchCustomers(ctx context.Context, req *d
 	}
 }
 
+// fetchSegmentCustomers fetches the customers of a segment, every external id must exist so a
+// mistyped id doesn't silently drop a customer from the combined totals
+func (s *featureUsageTrackingService) fetchSegmentCustomers(ctx context.Context, externalCustomerIDs []string) ([]*customer.Customer, error) {
+	externalCustomerIDs = lo.Uniq(externalCustomerIDs)
+
+	filter := types.NewNoLimitCustomerFilter()
+	filter.ExternalIDs = externalCustomerIDs
+	customers, err := s.CustomerRepo.List(ctx, filter)
+	if err != nil {
+		return nil, ierr.WithError(err).
+			WithHint("Failed to fetch customers").
+			Mark(ierr.ErrDatabase)
+	}
+
+	found := lo.SliceToMap(customers, func(c *customer.Customer) (string, struct{}) {
+		return c.ExternalID, struct{}{}
+	})
+	missing := lo.Filter(externalCustomerIDs, func(id string, _ int) bool {
+		_, ok := found[id]
+		return !ok
+	})
+	if len(missing) > 0 {
+		return nil, ierr.NewError("customers of segment not found").
+			WithHint("Every customer of the segment must exist").
+			WithReportableDetails(map[string]interface{}{
+				"missing_external_customer_ids": missing,
+			}).
+			Mark(ierr.ErrNotFound)
+	}
+
+	return customers, nil
+}
+
 // mergeAnalyticsData merges additional analytics data into the aggregated data structure
 func (s *featureUsageTrackingService) mergeAnalyticsData(aggregated *AnalyticsData, additional *AnalyticsData) {
 	// Merge customers (though in V2 we process multiple customers, we keep track of all)
@@ -2318,6 +5248,212 @@ func (s *featureUsageTrackingService) mergeAnalyticsData(aggregated *AnalyticsDa
 			aggregated.Addons[id] = addon
 		}
 	}
+
+	// Merge event samples, the per-feature cap is applied when building the response
+	if aggregated.EventSamples == nil {
+		aggregated.EventSamples = make(map[string][]*events.Event)
+	}
+	for featureID, samples := range additional.EventSamples {
+		aggregated.EventSamples[featureID] = append(aggregated.EventSamples[featureID], samples...)
+	}
+
+	// Merge matched filter values
+	if aggregated.FilterValues == nil {
+		aggregated.FilterValues = make(map[string]map[string][]string)
+	}
+	for priceID, byKey := range additional.FilterValues {
+		if aggregated.FilterValues[priceID] == nil {
+			aggregated.FilterValues[priceID] = make(map[string][]string)
+		}
+		for key, values := range byKey {
+			aggregated.FilterValues[priceID][key] = lo.Uniq(append(aggregated.FilterValues[priceID][key], values...))
+		}
+	}
+
+	// Merge the sampled aggregation field values, keeping the sample capped
+	if aggregated.AggregationValues == nil {
+		aggregated.AggregationValues = make(map[string]map[string][]string)
+	}
+	for priceID, byField := range additional.AggregationValues {
+		if aggregated.AggregationValues[priceID] == nil {
+			aggregated.AggregationValues[priceID] = make(map[string][]string)
+		}
+		for field, values := range byField {
+			merged := lo.Uniq(append(aggregated.AggregationValues[priceID][field], values...))
+			if len(merged) > dto.MaxAnalyticsAggregationValues {
+				merged = merged[:dto.MaxAnalyticsAggregationValues]
+			}
+			aggregated.AggregationValues[priceID][field] = merged
+		}
+	}
+}
+
+// GetEventsStatus reports for each event whether feature usage has been recorded for it. It
+// checks existence in a single batch so monitoring can poll many events cheaply. Events without
+// usage are looked up in the stored events, so events that never get usage are reported as
+// unprocessed once the status timeout passed since their ingestion instead of processing forever.
+func (s *featureUsageTrackingService) GetEventsStatus(ctx context.Context, eventIDs []string) (*dto.GetEventsStatusResponse, error) {
+	eventIDs = lo.Uniq(eventIDs)
+	response := &dto.GetEventsStatusResponse{
+		Events: make([]dto.EventStatus, 0, len(eventIDs)),
+	}
+	if len(eventIDs) == 0 {
+		return response, nil
+	}
+
+	processedIDs, err := s.featureUsageRepo.GetProcessedEventIDs(ctx, eventIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	processed := lo.SliceToMap(processedIDs, func(id string) (string, bool) {
+		return id, true
+	})
+
+	pendingIDs := lo.Filter(eventIDs, func(id string, _ int) bool { return !processed[id] })
+	storedEvents, err := s.eventRepo.GetEventsByIDs(ctx, pendingIDs)
+	if err != nil {
+		return nil, err
+	}
+	ingestedAt := lo.SliceToMap(storedEvents, func(event *events.Event) (string, time.Time) {
+		return event.ID, event.IngestedAt
+	})
+
+	timeout := s.Config.FeatureUsageTracking.EventStatusTimeout
+	now := time.Now().UTC()
+	for _, eventID := range eventIDs {
+		status := types.EventProcessingStatusProcessed
+		if !processed[eventID] {
+			at, stored := ingestedAt[eventID]
+			switch {
+			case !stored:
+				status = types.EventProcessingStatusUnknown
+			case timeout > 0 && now.Sub(at) > timeout:
+				status = types.EventProcessingStatusUnprocessed
+			default:
+				status = types.EventProcessingStatusProcessing
+			}
+		}
+		response.Events = append(response.Events, dto.EventStatus{
+			EventID: eventID,
+			Status:  status,
+		})
+	}
+
+	return response, nil
+}
+
+const (
+	// unbilledEventsDefaultLimit is the number of unbilled events returned when no limit is given
+	unbilledEventsDefaultLimit = 100
+	// unbilledEventsPageSize is the number of events of the window checked per query
+	unbilledEventsPageSize = 500
+	// unbilledEventsMaxChecked bounds the events of the window checked per request, the rest are
+	// checked by the next request
+	unbilledEventsMaxChecked = 10000
+)
+
+// GetUnbilledEvents lists the events of a customer in a window that produced no feature usage
+// and why. Events without usage are prepared again against the current subscriptions, so the
+// reason reflects today's configuration rather than the one at processing time. Events are
+// checked from the most recent, at most unbilledEventsMaxChecked per request.
+func (s *featureUsageTrackingService) GetUnbilledEvents(ctx context.Context, req *dto.GetUnbilledEventsRequest) (*dto.GetUnbilledEventsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	iterLast, err := parseEventIteratorToStruct(req.IterLastKey)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := lo.Ternary(req.Limit > 0, req.Limit, unbilledEventsDefaultLimit)
+	response := &dto.GetUnbilledEventsResponse{
+		Events: make([]dto.UnbilledEvent, 0),
+	}
+
+	checked := 0
+	for {
+		page, _, err := s.EventRepo.GetEvents(ctx, &events.GetEventsParams{
+			ExternalCustomerID: req.ExternalCustomerID,
+			StartTime:          req.StartTime,
+			EndTime:            req.EndTime,
+			IterLast:           iterLast,
+			PageSize:           unbilledEventsPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		processedIDs, err := s.featureUsageRepo.GetProcessedEventIDs(ctx, lo.Map(page, func(event *events.Event, _ int) string {
+			return event.ID
+		}))
+		if err != nil {
+			return nil, err
+		}
+
+		processed := lo.SliceToMap(processedIDs, func(id string) (string, bool) {
+			return id, true
+		})
+		for _, event := range page {
+			if len(response.Events) >= limit || checked >= unbilledEventsMaxChecked {
+				// The rest of the page is left to the next request
+				response.HasMore = true
+				break
+			}
+
+			checked++
+			iterLast = &events.EventIterator{Timestamp: event.Timestamp, ID: event.ID}
+			if processed[event.ID] {
+				continue
+			}
+
+			response.Events = append(response.Events, dto.UnbilledEvent{
+				EventID:   event.ID,
+				EventName: event.EventName,
+				Timestamp: event.Timestamp,
+				Reason:    s.unbilledEventReason(ctx, event),
+			})
+		}
+
+		if response.HasMore || len(page) < unbilledEventsPageSize {
+			break
+		}
+		if len(response.Events) >= limit || checked >= unbilledEventsMaxChecked {
+			response.HasMore = true
+			break
+		}
+	}
+
+	if response.HasMore && iterLast != nil {
+		response.IterLastKey = createEventIteratorKey(iterLast.Timestamp, iterLast.ID)
+	}
+	return response, nil
+}
+
+// unbilledEventReason prepares the event without recording usage to find why it produced none.
+// An event whose preparation fails has an unknown reason.
+func (s *featureUsageTrackingService) unbilledEventReason(ctx context.Context, event *events.Event) types.UnbilledEventReason {
+	// Preparing resolves the customer and may clamp the timestamp, keep the stored event untouched
+	eventCopy := *event
+	skips := &unbilledEventTracker{}
+
+	featureUsage, err := s.prepareFeatureUsage(ctx, &eventCopy, nil, skips)
+	if err != nil {
+		s.Logger.Warnw("failed to prepare event to find why it produced no usage",
+			"event_id", event.ID,
+			"error", err,
+		)
+		return types.UnbilledEventReasonUnknown
+	}
+
+	if len(featureUsage) > 0 {
+		return types.UnbilledEventReasonPending
+	}
+	return skips.reason
 }
 
 func (s *featureUsageTrackingService) GetHuggingFaceBillingData(ctx context.Context, params *dto.GetHuggingFaceBillingDataRequest) (*dto.GetHuggingFaceBillingDataResponse, error) {
@@ -2327,12 +5463,37 @@ func (s *featureUsageTrackingService) GetHuggingFaceBillingData(ctx context.Cont
 		}, nil
 	}
 
-	// Query feature_usage table directly by event IDs
-	featureUsageRecords, err := s.featureUsageRepo.GetFeatureUsageByEventIDs(ctx, params.EventIDs)
+	// Query feature_usage table directly by event IDs. Events matched by many line items can
+	// exceed the configured cap per event, one record past it is read to tell whether any were
+	// left out of the response.
+	limit := s.Config.FeatureUsageTracking.EventUsageLookupLimit
+	lookupLimit := 0
+	if limit > 0 {
+		lookupLimit = limit + 1
+	}
+	featureUsageRecords, err := s.featureUsageRepo.GetFeatureUsageByEventIDs(ctx, params.EventIDs, lookupLimit)
 	if err != nil {
 		return nil, err
 	}
 
+	truncated := false
+	if limit > 0 {
+		perEvent := make(map[string]int)
+		featureUsageRecords = lo.Filter(featureUsageRecords, func(record *events.FeatureUsage, _ int) bool {
+			perEvent[record.ID]++
+			return perEvent[record.ID] <= limit
+		})
+		truncatedEventIDs := lo.Keys(lo.PickBy(perEvent, func(_ string, count int) bool { return count > limit }))
+		if len(truncatedEventIDs) > 0 {
+			truncated = true
+			s.Logger.Warnw("feature usage lookup by event ids reached the limit, result truncated",
+				"event_count", len(params.EventIDs),
+				"truncated_event_ids", truncatedEventIDs,
+				"limit", limit,
+			)
+		}
+	}
+
 	if len(featureUsageRecords) == 0 {
 		return &dto.GetHuggingFaceBillingDataResponse{
 			Data: make([]dto.EventCostInfo, 0),
@@ -2375,7 +5536,17 @@ func (s *featureUsageTrackingService) GetHuggingFaceBillingData(ctx context.Cont
 
 	// Calculate cost for each request
 	priceService := NewPriceService(s.ServiceParams)
-	nanoUSDMultiplier := decimal.NewFromInt(1_000_000_000)
+	nanoMultiplier := decimal.NewFromInt(1_000_000_000)
+
+	// Costs of other currencies are converted to USD at the settlement conversion rates when the
+	// tenant settles in USD, without a rate they are flagged rather than reported as free
+	usdSettlement, err := getSettlementConfig(ctx, s.ServiceParams)
+	if err != nil {
+		return nil, err
+	}
+	if !types.IsMatchingCurrency(usdSettlement.Currency, "usd") {
+		usdSettlement = &types.SettlementConfig{Currency: "usd"}
+	}
 
 	for i := range featureUsageRecords {
 		record := featureUsageRecords[i]
@@ -2390,21 +5561,33 @@ func (s *featureUsageTrackingService) GetHuggingFaceBillingData(ctx context.Cont
 			responseData = append(responseData, dto.EventCostInfo{
 				EventID:       record.ID,
 				CostInNanoUSD: decimal.Zero,
+				CostInNano:    decimal.Zero,
 			})
 			continue
 		}
 
-		// Calculate cost in the price's currency and convert to nano-USD
-		cost := priceService.CalculateCost(ctx, p, record.QtyTotal)
-		costInNanoUSD := cost.Mul(nanoUSDMultiplier)
+		// Calculate cost in the price's currency and convert it to nano-USD
+		costInNano := priceService.CalculateCost(ctx, p, record.QtyTotal).Mul(nanoMultiplier)
+		costInNanoUSD, _, converted := usdSettlement.Convert(costInNano, p.Currency)
+		if !converted {
+			s.Logger.Warnw("no conversion rate to usd for the price currency, cost in nano usd is missing",
+				"request_id", record.ID,
+				"price_id", record.PriceID,
+				"currency", p.Currency,
+			)
+		}
 
 		responseData = append(responseData, dto.EventCostInfo{
-			EventID:       record.ID,
-			CostInNanoUSD: costInNanoUSD,
+			EventID:               record.ID,
+			CostInNanoUSD:         costInNanoUSD,
+			MissingConversionRate: !converted,
+			Currency:              p.Currency,
+			CostInNano:            costInNano,
 		})
 	}
 
 	return &dto.GetHuggingFaceBillingDataResponse{
-		Data: responseData,
+		Data:      responseData,
+		Truncated: truncated,
 	}, nil
 }
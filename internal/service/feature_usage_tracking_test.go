@@ -0,0 +1,4355 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/domain/customer"
+	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/domain/feature"
+	"github.com/flexprice/flexprice/internal/domain/meter"
+	"github.com/flexprice/flexprice/internal/domain/plan"
+	"github.com/flexprice/flexprice/internal/domain/price"
+	"github.com/flexprice/flexprice/internal/domain/settings"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/metrics"
+	"github.com/flexprice/flexprice/internal/testutil"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+type FeatureUsageTrackingServiceSuite struct {
+	testutil.BaseServiceTestSuite
+	service  *featureUsageTrackingService
+	testData struct {
+		customer     *customer.Customer
+		plan         *plan.Plan
+		meter        *meter.Meter
+		feature      *feature.Feature
+		price        *price.Price
+		subscription *subscription.Subscription
+		lineItem     *subscription.SubscriptionLineItem
+		now          time.Time
+	}
+}
+
+func TestFeatureUsageTrackingService(t *testing.T) {
+	suite.Run(t, new(FeatureUsageTrackingServiceSuite))
+}
+
+func (s *FeatureUsageTrackingServiceSuite) SetupTest() {
+	s.BaseServiceTestSuite.SetupTest()
+	s.setupService()
+	s.setupTestData()
+}
+
+func (s *FeatureUsageTrackingServiceSuite) setupService() {
+	stores := s.GetStores()
+	s.service = &featureUsageTrackingService{
+		ServiceParams: ServiceParams{
+			Logger:                   s.GetLogger(),
+			Config:                   s.GetConfig(),
+			DB:                       s.GetDB(),
+			SubRepo:                  stores.SubscriptionRepo,
+			SubscriptionLineItemRepo: stores.SubscriptionLineItemRepo,
+			PlanRepo:                 stores.PlanRepo,
+			PriceRepo:                stores.PriceRepo,
+			EventRepo:                stores.EventRepo,
+			MeterRepo:                stores.MeterRepo,
+			CustomerRepo:             stores.CustomerRepo,
+			FeatureRepo:              stores.FeatureRepo,
+			FeatureUsageRepo:         stores.FeatureUsageRepo,
+			UsageCounterRepo:         stores.UsageCounterRepo,
+			EventDedupRepo:           stores.EventDedupRepo,
+			SettingsRepo:             stores.SettingsRepo,
+			EntitlementRepo:          stores.EntitlementRepo,
+			AddonAssociationRepo:     stores.AddonAssociationRepo,
+			EventPublisher:           s.GetPublisher(),
+			WebhookPublisher:         s.GetWebhookPublisher(),
+		},
+		eventRepo:        stores.EventRepo,
+		featureUsageRepo: stores.FeatureUsageRepo,
+	}
+}
+
+func (s *FeatureUsageTrackingServiceSuite) setupTestData() {
+	ctx := s.GetContext()
+	s.testData.now = time.Now().UTC()
+
+	s.testData.customer = &customer.Customer{
+		ID:         "cust_fut_123",
+		ExternalID: "ext_cust_fut_123",
+		Name:       "Usage Customer",
+		BaseModel:  types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().CustomerRepo.Create(ctx, s.testData.customer))
+
+	s.testData.plan = &plan.Plan{
+		ID:        "plan_fut_123",
+		Name:      "Usage Plan",
+		BaseModel: types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().PlanRepo.Create(ctx, s.testData.plan))
+
+	s.testData.meter = &meter.Meter{
+		ID:        "meter_fut_tokens",
+		Name:      "Tokens",
+		EventName: "tokens_used",
+		Aggregation: meter.Aggregation{
+			Type:  types.AggregationSum,
+			Field: "tokens",
+		},
+		BaseModel: types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().MeterRepo.CreateMeter(ctx, s.testData.meter))
+
+	s.testData.feature = &feature.Feature{
+		ID:        "feat_fut_tokens",
+		Name:      "Tokens",
+		MeterID:   s.testData.meter.ID,
+		Type:      types.FeatureTypeMetered,
+		BaseModel: types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().FeatureRepo.Create(ctx, s.testData.feature))
+
+	s.testData.price = &price.Price{
+		ID:                 "price_fut_tokens",
+		Amount:             decimal.NewFromFloat(0.5),
+		Currency:           "usd",
+		EntityType:         types.PRICE_ENTITY_TYPE_PLAN,
+		EntityID:           s.testData.plan.ID,
+		Type:               types.PRICE_TYPE_USAGE,
+		BillingPeriod:      types.BILLING_PERIOD_MONTHLY,
+		BillingPeriodCount: 1,
+		BillingModel:       types.BILLING_MODEL_FLAT_FEE,
+		BillingCadence:     types.BILLING_CADENCE_RECURRING,
+		InvoiceCadence:     types.InvoiceCadenceArrear,
+		MeterID:            s.testData.meter.ID,
+		BaseModel:          types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().PriceRepo.Create(ctx, s.testData.price))
+
+	s.testData.subscription = &subscription.Subscription{
+		ID:                 "sub_fut_123",
+		PlanID:             s.testData.plan.ID,
+		CustomerID:         s.testData.customer.ID,
+		StartDate:          s.testData.now.Add(-30 * 24 * time.Hour),
+		CurrentPeriodStart: s.testData.now.Add(-5 * 24 * time.Hour),
+		CurrentPeriodEnd:   s.testData.now.Add(25 * 24 * time.Hour),
+		BillingAnchor:      s.testData.now.Add(-30 * 24 * time.Hour),
+		Currency:           "usd",
+		BillingPeriod:      types.BILLING_PERIOD_MONTHLY,
+		BillingPeriodCount: 1,
+		SubscriptionStatus: types.SubscriptionStatusActive,
+		BaseModel:          types.GetDefaultBaseModel(ctx),
+	}
+	s.testData.lineItem = &subscription.SubscriptionLineItem{
+		ID:               "subli_fut_tokens",
+		SubscriptionID:   s.testData.subscription.ID,
+		CustomerID:       s.testData.customer.ID,
+		EntityID:         s.testData.plan.ID,
+		EntityType:       types.SubscriptionLineItemEntityTypePlan,
+		PriceID:          s.testData.price.ID,
+		PriceType:        s.testData.price.Type,
+		MeterID:          s.testData.meter.ID,
+		MeterDisplayName: s.testData.meter.Name,
+		DisplayName:      "Tokens",
+		Currency:         "usd",
+		BillingPeriod:    types.BILLING_PERIOD_MONTHLY,
+		InvoiceCadence:   types.InvoiceCadenceArrear,
+		StartDate:        s.testData.subscription.StartDate,
+		BaseModel:        types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().SubscriptionRepo.CreateWithLineItems(ctx, s.testData.subscription, []*subscription.SubscriptionLineItem{s.testData.lineItem}))
+	s.testData.subscription.LineItems = []*subscription.SubscriptionLineItem{s.testData.lineItem}
+}
+
+// recordUsage stores a raw event and its feature_usage row for the test subscription
+func (s *FeatureUsageTrackingServiceSuite) recordUsage(eventID string, timestamp time.Time, tokens int64) {
+	s.recordUsageFromSource(eventID, timestamp, tokens, "api")
+}
+
+// recordUsageFromSource is recordUsage for an event sent from the given source
+func (s *FeatureUsageTrackingServiceSuite) recordUsageFromSource(eventID string, timestamp time.Time, tokens int64, source string) {
+	ctx := s.GetContext()
+	event := &events.Event{
+		ID:                 eventID,
+		TenantID:           types.GetTenantID(ctx),
+		EnvironmentID:      types.GetEnvironmentID(ctx),
+		EventName:          s.testData.meter.EventName,
+		ExternalCustomerID: s.testData.customer.ExternalID,
+		CustomerID:         s.testData.customer.ID,
+		Timestamp:          timestamp,
+		Source:             source,
+		Properties:         map[string]interface{}{"tokens": float64(tokens)},
+	}
+	s.NoError(s.GetStores().EventRepo.InsertEvent(ctx, event))
+
+	s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(ctx, &events.FeatureUsage{
+		Event:          *event,
+		SubscriptionID: s.testData.subscription.ID,
+		SubLineItemID:  s.testData.lineItem.ID,
+		PriceID:        s.testData.price.ID,
+		MeterID:        s.testData.meter.ID,
+		FeatureID:      s.testData.feature.ID,
+		UniqueHash:     eventID,
+		QtyTotal:       decimal.NewFromInt(tokens),
+		Sign:           1,
+	}))
+}
+
+func (s *FeatureUsageTrackingServiceSuite) analyticsRequest() *dto.GetUsageAnalyticsRequest {
+	return &dto.GetUsageAnalyticsRequest{
+		ExternalCustomerID: s.testData.customer.ExternalID,
+		StartTime:          s.testData.now.Add(-24 * time.Hour),
+		EndTime:            s.testData.now.Add(time.Hour),
+	}
+}
+
+// presentAnalytics builds the analytics response of the data the way it is returned, paged and
+// rounded
+func (s *FeatureUsageTrackingServiceSuite) presentAnalytics(ctx context.Context, data *AnalyticsData, req *dto.GetUsageAnalyticsRequest) (*dto.GetUsageAnalyticsResponse, error) {
+	resp, err := s.service.ToGetUsageAnalyticsResponseDTO(ctx, data, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.service.paginateAnalyticsResponse(ctx, resp, req)
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsEventSamples() {
+	s.recordUsage("evt_fut_1", s.testData.now.Add(-3*time.Hour), 10)
+	s.recordUsage("evt_fut_2", s.testData.now.Add(-2*time.Hour), 20)
+	s.recordUsage("evt_fut_3", s.testData.now.Add(-1*time.Hour), 30)
+
+	s.Run("samples_not_returned_by_default", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), s.analyticsRequest())
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+		s.Empty(resp.Items[0].EventSamples)
+	})
+
+	s.Run("returns_latest_contributing_events_up_to_sample_size", func() {
+		req := s.analyticsRequest()
+		req.EventSampleSize = 2
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+
+		item := resp.Items[0]
+		s.Equal(s.testData.feature.ID, item.FeatureID)
+		s.True(decimal.NewFromInt(60).Equal(item.TotalUsage))
+		s.Len(item.EventSamples, 2)
+		s.Equal("evt_fut_3", item.EventSamples[0].ID)
+		s.Equal("evt_fut_2", item.EventSamples[1].ID)
+		s.Equal(s.testData.meter.EventName, item.EventSamples[0].EventName)
+		s.Equal(float64(30), item.EventSamples[0].Properties["tokens"])
+	})
+
+	s.Run("rejects_sample_size_above_cap", func() {
+		req := s.analyticsRequest()
+		req.EventSampleSize = dto.MaxAnalyticsEventSampleSize + 1
+
+		_, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.Error(err)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsExpandSubscription() {
+	s.recordUsage("evt_fut_1", s.testData.now.Add(-time.Hour), 10)
+
+	s.Run("not_expanded_by_default", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), s.analyticsRequest())
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+		s.Nil(resp.Items[0].Subscription)
+	})
+
+	s.Run("attaches_subscription_when_expanded", func() {
+		req := s.analyticsRequest()
+		req.Expand = []string{"subscription"}
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+		s.NotNil(resp.Items[0].Subscription)
+		s.Equal(s.testData.subscription.ID, resp.Items[0].Subscription.ID)
+		s.Equal(s.testData.plan.ID, resp.Items[0].Subscription.PlanID)
+	})
+}
+
+// pauseSubscription pauses the test subscription from pauseStart onwards. The pause
+// interrupted the billing period that started with the subscription.
+func (s *FeatureUsageTrackingServiceSuite) pauseSubscription(pauseStart time.Time) *subscription.SubscriptionPause {
+	ctx := s.GetContext()
+	pause := &subscription.SubscriptionPause{
+		ID:                  "pause_fut_123",
+		SubscriptionID:      s.testData.subscription.ID,
+		PauseStatus:         types.PauseStatusActive,
+		PauseMode:           types.PauseModeImmediate,
+		PauseStart:          pauseStart,
+		OriginalPeriodStart: s.testData.subscription.StartDate,
+		OriginalPeriodEnd:   s.testData.subscription.CurrentPeriodStart,
+		BaseModel:           types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().SubscriptionRepo.CreatePause(ctx, pause))
+
+	s.testData.subscription.SubscriptionStatus = types.SubscriptionStatusPaused
+	s.testData.subscription.PauseStatus = types.PauseStatusActive
+	s.testData.subscription.ActivePauseID = &pause.ID
+	s.NoError(s.GetStores().SubscriptionRepo.Update(ctx, s.testData.subscription))
+
+	return pause
+}
+
+func (s *FeatureUsageTrackingServiceSuite) usageEvent(eventID string, timestamp time.Time, tokens int64) *events.Event {
+	return &events.Event{
+		ID:                 eventID,
+		TenantID:           types.GetTenantID(s.GetContext()),
+		EnvironmentID:      types.GetEnvironmentID(s.GetContext()),
+		EventName:          s.testData.meter.EventName,
+		ExternalCustomerID: s.testData.customer.ExternalID,
+		Timestamp:          timestamp,
+		Source:             "api",
+		Properties:         map[string]interface{}{"tokens": float64(tokens)},
+	}
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestPrepareProcessedEventsExternalCustomerIDProperty() {
+	propertyEvent := func(eventID string, properties map[string]interface{}) *events.Event {
+		event := s.usageEvent(eventID, s.testData.now.Add(-time.Hour), 10)
+		event.ExternalCustomerID = ""
+		for key, value := range properties {
+			event.Properties[key] = value
+		}
+		return event
+	}
+
+	tests := []struct {
+		name             string
+		property         string
+		event            *events.Event
+		expectedCustomer string
+	}{
+		{
+			name:     "nested_property_resolves_customer",
+			property: "customer.id",
+			event: propertyEvent("evt_fut_prop_nested", map[string]interface{}{
+				"customer": map[string]interface{}{"id": s.testData.customer.ExternalID},
+			}),
+			expectedCustomer: s.testData.customer.ID,
+		},
+		{
+			name:     "top_level_property_resolves_customer",
+			property: "account",
+			event: propertyEvent("evt_fut_prop_top", map[string]interface{}{
+				"account": s.testData.customer.ExternalID,
+			}),
+			expectedCustomer: s.testData.customer.ID,
+		},
+		{
+			name:     "missing_property_is_skipped",
+			property: "customer.id",
+			event: propertyEvent("evt_fut_prop_missing", map[string]interface{}{
+				"customer": map[string]interface{}{"name": "acme"},
+			}),
+		},
+		{
+			name:     "fallback_disabled_is_skipped",
+			property: "",
+			event: propertyEvent("evt_fut_prop_disabled", map[string]interface{}{
+				"customer": map[string]interface{}{"id": s.testData.customer.ExternalID},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.GetConfig().FeatureUsageTracking.ExternalCustomerIDProperty = tt.property
+
+			results, err := s.service.prepareProcessedEvents(s.GetContext(), tt.event)
+			s.NoError(err)
+			if tt.expectedCustomer == "" {
+				s.Empty(results)
+				return
+			}
+
+			s.Require().Len(results, 1)
+			s.Equal(tt.expectedCustomer, results[0].CustomerID)
+			s.Equal(s.testData.customer.ExternalID, results[0].ExternalCustomerID)
+			s.Equal(s.testData.subscription.ID, results[0].SubscriptionID)
+		})
+	}
+
+	s.Run("top_level_external_customer_id_wins", func() {
+		s.GetConfig().FeatureUsageTracking.ExternalCustomerIDProperty = "customer.id"
+		event := s.usageEvent("evt_fut_prop_top_level", s.testData.now.Add(-time.Hour), 10)
+		event.Properties["customer"] = map[string]interface{}{"id": "cust_ext_unknown"}
+
+		results, err := s.service.prepareProcessedEvents(s.GetContext(), event)
+		s.NoError(err)
+		s.Require().Len(results, 1)
+		s.Equal(s.testData.customer.ID, results[0].CustomerID)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestPrepareProcessedEventsSubscriptionIDProperty() {
+	ctx := s.GetContext()
+	s.GetConfig().FeatureUsageTracking.SubscriptionIDProperty = "billing.subscription_id"
+	defer func() { s.GetConfig().FeatureUsageTracking.SubscriptionIDProperty = "" }()
+
+	// A second subscription of the customer billing the same price
+	second := *s.testData.subscription
+	second.ID = "sub_fut_second"
+	second.LineItems = nil
+	secondLineItem := *s.testData.lineItem
+	secondLineItem.ID = "subli_fut_second"
+	secondLineItem.SubscriptionID = second.ID
+	s.NoError(s.GetStores().SubscriptionRepo.CreateWithLineItems(ctx, &second, []*subscription.SubscriptionLineItem{&secondLineItem}))
+
+	// A subscription of another customer
+	other := &customer.Customer{
+		ID:         "cust_fut_other",
+		ExternalID: "ext_cust_fut_other",
+		Name:       "Other Customer",
+		BaseModel:  types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().CustomerRepo.Create(ctx, other))
+	otherSub := *s.testData.subscription
+	otherSub.ID = "sub_fut_other"
+	otherSub.CustomerID = other.ID
+	otherSub.LineItems = nil
+	otherLineItem := *s.testData.lineItem
+	otherLineItem.ID = "subli_fut_other"
+	otherLineItem.SubscriptionID = otherSub.ID
+	otherLineItem.CustomerID = other.ID
+	s.NoError(s.GetStores().SubscriptionRepo.CreateWithLineItems(ctx, &otherSub, []*subscription.SubscriptionLineItem{&otherLineItem}))
+
+	directedEvent := func(eventID string, subscriptionID interface{}) *events.Event {
+		event := s.usageEvent(eventID, s.testData.now.Add(-time.Hour), 10)
+		if subscriptionID != nil {
+			event.Properties["billing"] = map[string]interface{}{"subscription_id": subscriptionID}
+		}
+		return event
+	}
+
+	tests := []struct {
+		name                  string
+		event                 *events.Event
+		expectedSubscriptions []string
+		expectedReason        types.UnbilledEventReason
+	}{
+		{
+			name:                  "routes_to_named_subscription",
+			event:                 directedEvent("evt_fut_route_second", second.ID),
+			expectedSubscriptions: []string{second.ID},
+		},
+		{
+			name:                  "without_property_matches_all_subscriptions",
+			event:                 directedEvent("evt_fut_route_all", nil),
+			expectedSubscriptions: []string{s.testData.subscription.ID, second.ID},
+		},
+		{
+			name:                  "non_string_property_is_ignored",
+			event:                 directedEvent("evt_fut_route_number", 42),
+			expectedSubscriptions: []string{s.testData.subscription.ID, second.ID},
+		},
+		{
+			name:           "unknown_subscription_is_skipped",
+			event:          directedEvent("evt_fut_route_unknown", "sub_fut_unknown"),
+			expectedReason: types.UnbilledEventReasonSubscriptionNotFound,
+		},
+		{
+			name:           "subscription_of_another_customer_is_skipped",
+			event:          directedEvent("evt_fut_route_other", otherSub.ID),
+			expectedReason: types.UnbilledEventReasonSubscriptionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			skips := &unbilledEventTracker{}
+			results, err := s.service.prepareFeatureUsage(ctx, tt.event, nil, skips)
+			s.NoError(err)
+			s.Equal(tt.expectedReason, skips.reason)
+
+			subscriptionIDs := lo.Map(results, func(usage *events.FeatureUsage, _ int) string {
+				return usage.SubscriptionID
+			})
+			s.ElementsMatch(tt.expectedSubscriptions, subscriptionIDs)
+		})
+	}
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestCorrelationIDPropagation() {
+	ctx := s.GetContext()
+	previous := s.GetConfig().FeatureUsageTracking.CorrelationIDProperty
+	s.GetConfig().FeatureUsageTracking.CorrelationIDProperty = "trace.correlation_id"
+	defer func() { s.GetConfig().FeatureUsageTracking.CorrelationIDProperty = previous }()
+
+	tracedEvent := func(eventID string, correlationID interface{}) *events.Event {
+		event := s.usageEvent(eventID, s.testData.now.Add(-time.Hour), 10)
+		if correlationID != nil {
+			event.Properties["trace"] = map[string]interface{}{"correlation_id": correlationID}
+		}
+		return event
+	}
+
+	s.Run("stores_correlation_id_with_feature_usage", func() {
+		s.NoError(s.service.processEvent(ctx, tracedEvent("evt_fut_trace_1", " req_123 ")))
+
+		usage, err := s.GetStores().FeatureUsageRepo.GetFeatureUsageByEventIDs(ctx, []string{"evt_fut_trace_1"}, 0)
+		s.NoError(err)
+		s.Require().Len(usage, 1)
+		s.Equal("req_123", usage[0].CorrelationID)
+	})
+
+	s.Run("missing_or_non_string_property_leaves_it_empty", func() {
+		s.NoError(s.service.processEvent(ctx, tracedEvent("evt_fut_trace_2", nil)))
+		s.NoError(s.service.processEvent(ctx, tracedEvent("evt_fut_trace_3", 42)))
+
+		usage, err := s.GetStores().FeatureUsageRepo.GetFeatureUsageByEventIDs(ctx, []string{"evt_fut_trace_2", "evt_fut_trace_3"}, 0)
+		s.NoError(err)
+		s.Require().Len(usage, 2)
+		for _, u := range usage {
+			s.Empty(u.CorrelationID)
+		}
+	})
+
+	s.Run("returns_correlation_id_on_event_samples", func() {
+		event := tracedEvent("evt_fut_trace_4", "req_456")
+		event.CustomerID = s.testData.customer.ID
+		s.NoError(s.GetStores().EventRepo.InsertEvent(ctx, event))
+		s.NoError(s.service.processEvent(ctx, event))
+
+		req := s.analyticsRequest()
+		req.EventSampleSize = dto.MaxAnalyticsEventSampleSize
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+
+		sample, ok := lo.Find(resp.Items[0].EventSamples, func(e dto.Event) bool {
+			return e.ID == "evt_fut_trace_4"
+		})
+		s.Require().True(ok)
+		s.Equal("req_456", sample.CorrelationID)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestEventNameNormalizationMatching() {
+	ctx := s.GetContext()
+	previous := s.GetConfig().Event.NameNormalization
+	defer func() { s.GetConfig().Event.NameNormalization = previous }()
+
+	// An event sent with stray whitespace and different casing than the meter
+	unnormalizedEvent := func(eventID string) *events.Event {
+		event := s.usageEvent(eventID, s.testData.now.Add(-time.Hour), 10)
+		event.EventName = " " + strings.ToUpper(s.testData.meter.EventName) + "\t"
+		return event
+	}
+
+	s.Run("none_does_not_match", func() {
+		s.GetConfig().Event.NameNormalization = types.EventNameNormalizationNone
+		usage, err := s.service.prepareFeatureUsage(ctx, unnormalizedEvent("evt_fut_normalize_1"), nil, nil)
+		s.NoError(err)
+		s.Empty(usage)
+	})
+
+	s.Run("lowercase_matches_meter", func() {
+		s.GetConfig().Event.NameNormalization = types.EventNameNormalizationLowercase
+		usage, err := s.service.prepareFeatureUsage(ctx, unnormalizedEvent("evt_fut_normalize_2"), nil, nil)
+		s.NoError(err)
+		s.Require().Len(usage, 1)
+		s.Equal(s.testData.meter.ID, usage[0].MeterID)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestEventNameMatchPrefix() {
+	ctx := s.GetContext()
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+	s.testData.meter.EventName = "tokens."
+	s.testData.meter.EventNameMatch = types.MeterEventNameMatchPrefix
+	s.NoError(s.testData.meter.Validate())
+	s.NoError(meterStore.InMemoryStore.Update(ctx, s.testData.meter.ID, s.testData.meter))
+
+	tests := []struct {
+		name      string
+		eventName string
+		matches   bool
+	}{
+		{name: "input_tokens", eventName: "tokens.input", matches: true},
+		{name: "output_tokens", eventName: "tokens.output", matches: true},
+		{name: "prefix_itself", eventName: "tokens.", matches: true},
+		{name: "other_event", eventName: "api.tokens.input", matches: false},
+	}
+
+	for i, tt := range tests {
+		s.Run(tt.name, func() {
+			event := s.usageEvent(fmt.Sprintf("evt_fut_prefix_%d", i), s.testData.now.Add(-time.Hour), 10)
+			event.EventName = tt.eventName
+			usage, err := s.service.prepareFeatureUsage(ctx, event, nil, nil)
+			s.NoError(err)
+			if !tt.matches {
+				s.Empty(usage)
+				return
+			}
+			s.Require().Len(usage, 1)
+			s.Equal(s.testData.meter.ID, usage[0].MeterID)
+		})
+	}
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestFetchSubscriptionsWindow() {
+	ctx := s.GetContext()
+	addSubscription := func(id string, status types.SubscriptionStatus, start time.Time, end *time.Time) {
+		sub := *s.testData.subscription
+		sub.ID = id
+		sub.SubscriptionStatus = status
+		sub.StartDate = start
+		sub.EndDate = end
+		sub.LineItems = nil
+		lineItem := *s.testData.lineItem
+		lineItem.ID = "subli_" + id
+		lineItem.SubscriptionID = id
+		s.NoError(s.GetStores().SubscriptionRepo.CreateWithLineItems(ctx, &sub, []*subscription.SubscriptionLineItem{&lineItem}))
+	}
+
+	windowStart := s.testData.now.Add(-24 * time.Hour)
+	windowEnd := s.testData.now.Add(time.Hour)
+	addSubscription("sub_fut_ended_before", types.SubscriptionStatusCancelled, s.testData.now.Add(-400*24*time.Hour), lo.ToPtr(s.testData.now.Add(-300*24*time.Hour)))
+	addSubscription("sub_fut_ended_at_start", types.SubscriptionStatusCancelled, s.testData.now.Add(-60*24*time.Hour), lo.ToPtr(windowStart))
+	addSubscription("sub_fut_ended_inside", types.SubscriptionStatusCancelled, s.testData.now.Add(-60*24*time.Hour), lo.ToPtr(s.testData.now.Add(-2*time.Hour)))
+	addSubscription("sub_fut_starts_after", types.SubscriptionStatusActive, windowEnd, nil)
+
+	subscriptionIDs := func(subs []*subscription.Subscription) []string {
+		return lo.Map(subs, func(sub *subscription.Subscription, _ int) string { return sub.ID })
+	}
+
+	s.Run("only_subscriptions_overlapping_the_window", func() {
+		subs, err := s.service.fetchSubscriptions(ctx, s.testData.customer.ID, windowStart, windowEnd)
+		s.NoError(err)
+		s.ElementsMatch([]string{s.testData.subscription.ID, "sub_fut_ended_inside"}, subscriptionIDs(subs))
+	})
+
+	s.Run("open_window_fetches_all", func() {
+		subs, err := s.service.fetchSubscriptions(ctx, s.testData.customer.ID, time.Time{}, time.Time{})
+		s.NoError(err)
+		s.Len(subs, 5)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestPrepareProcessedEventsPausedSubscription() {
+	pause := s.pauseSubscription(s.testData.now.Add(-48 * time.Hour))
+	currentPeriodID := uint64(s.testData.subscription.CurrentPeriodStart.Unix() * 1000)
+	interruptedPeriodID := uint64(pause.OriginalPeriodStart.Unix() * 1000)
+
+	tests := []struct {
+		name           string
+		policy         types.PausedSubscriptionUsagePolicy
+		expectUsage    bool
+		expectPeriodID uint64
+	}{
+		{
+			name:   "default_policy_skips_usage_during_pause",
+			policy: "",
+		},
+		{
+			name:   "skip_policy_drops_usage_during_pause",
+			policy: types.PausedSubscriptionUsagePolicySkip,
+		},
+		{
+			name:           "bill_policy_records_usage_in_current_period",
+			policy:         types.PausedSubscriptionUsagePolicyBill,
+			expectUsage:    true,
+			expectPeriodID: currentPeriodID,
+		},
+		{
+			name:           "accrue_policy_records_usage_in_interrupted_period",
+			policy:         types.PausedSubscriptionUsagePolicyAccrue,
+			expectUsage:    true,
+			expectPeriodID: interruptedPeriodID,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.service.Config.FeatureUsageTracking.PausedSubscriptionPolicy = tt.policy
+
+			results, err := s.service.prepareProcessedEvents(s.GetContext(), s.usageEvent("evt_fut_paused", s.testData.now.Add(-time.Hour), 10))
+			s.NoError(err)
+
+			if !tt.expectUsage {
+				s.Empty(results)
+				return
+			}
+
+			s.Len(results, 1)
+			s.Equal(s.testData.subscription.ID, results[0].SubscriptionID)
+			s.Equal(s.testData.lineItem.ID, results[0].SubLineItemID)
+			s.Equal(tt.expectPeriodID, results[0].PeriodID)
+			s.True(decimal.NewFromInt(10).Equal(results[0].QtyTotal))
+		})
+	}
+
+	s.Run("usage_before_pause_is_processed_under_skip_policy", func() {
+		s.service.Config.FeatureUsageTracking.PausedSubscriptionPolicy = types.PausedSubscriptionUsagePolicySkip
+
+		results, err := s.service.prepareProcessedEvents(s.GetContext(), s.usageEvent("evt_fut_before_pause", s.testData.now.Add(-72*time.Hour), 10))
+		s.NoError(err)
+		s.Len(results, 1)
+		s.Equal(currentPeriodID, results[0].PeriodID)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsPausedSubscription() {
+	s.pauseSubscription(s.testData.now.Add(-48 * time.Hour))
+	s.service.Config.FeatureUsageTracking.PausedSubscriptionPolicy = types.PausedSubscriptionUsagePolicyBill
+
+	results, err := s.service.prepareProcessedEvents(s.GetContext(), s.usageEvent("evt_fut_paused", s.testData.now.Add(-time.Hour), 10))
+	s.NoError(err)
+	s.Len(results, 1)
+	s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(s.GetContext(), results[0]))
+
+	resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), s.analyticsRequest())
+	s.NoError(err)
+	s.Len(resp.Items, 1)
+	s.Equal(s.testData.subscription.ID, resp.Items[0].SubscriptionID)
+	s.True(decimal.NewFromInt(10).Equal(resp.Items[0].TotalUsage))
+	s.True(decimal.NewFromInt(5).Equal(resp.TotalCost))
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestPrepareProcessedEventsCustomAggregation() {
+	customType := types.AggregationType("TEST_WEIGHTED_TOKENS")
+	s.NoError(meter.RegisterAggregationExtractor(customType, func(event *events.Event, m *meter.Meter) (decimal.Decimal, string, error) {
+		input, _ := event.Properties["input_tokens"].(float64)
+		output, _ := event.Properties["output_tokens"].(float64)
+		return decimal.NewFromFloat(input).Add(decimal.NewFromFloat(output).Mul(decimal.NewFromInt(3))), "", nil
+	}))
+	defer meter.UnregisterAggregationExtractor(customType)
+
+	s.testData.meter.Aggregation = meter.Aggregation{Type: customType}
+	s.NoError(s.testData.meter.Validate())
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+	s.NoError(meterStore.InMemoryStore.Update(s.GetContext(), s.testData.meter.ID, s.testData.meter))
+
+	event := s.usageEvent("evt_fut_custom", s.testData.now.Add(-time.Hour), 0)
+	event.Properties = map[string]interface{}{"input_tokens": float64(100), "output_tokens": float64(10)}
+
+	results, err := s.service.prepareProcessedEvents(s.GetContext(), event)
+	s.NoError(err)
+	s.Len(results, 1)
+	s.True(decimal.NewFromInt(130).Equal(results[0].QtyTotal), "qty: %s", results[0].QtyTotal)
+}
+
+// countingFeatureUsageRepo counts the analytics queries made against the feature usage store and
+// the rows inserted per event, inserts fail with insertErr when it's set
+type countingFeatureUsageRepo struct {
+	events.FeatureUsageRepository
+	analyticsQueries int
+	inserts          map[string]int
+	insertErr        error
+}
+
+func (r *countingFeatureUsageRepo) GetDetailedUsageAnalytics(ctx context.Context, params *events.UsageAnalyticsParams, maxBucketFeatures map[string]*events.MaxBucketFeatureInfo) ([]*events.DetailedUsageAnalytic, error) {
+	r.analyticsQueries++
+	return r.FeatureUsageRepository.GetDetailedUsageAnalytics(ctx, params, maxBucketFeatures)
+}
+
+func (r *countingFeatureUsageRepo) BulkInsertProcessedEvents(ctx context.Context, featureUsage []*events.FeatureUsage) error {
+	if r.insertErr != nil {
+		return r.insertErr
+	}
+	if r.inserts == nil {
+		r.inserts = make(map[string]int)
+	}
+	for _, usage := range featureUsage {
+		r.inserts[usage.ID]++
+	}
+	return r.FeatureUsageRepository.BulkInsertProcessedEvents(ctx, featureUsage)
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsMultipleWindowSizes() {
+	dayStart := s.testData.now.Truncate(24 * time.Hour).Add(-24 * time.Hour)
+	s.recordUsage("evt_fut_1", dayStart.Add(time.Hour), 10)
+	s.recordUsage("evt_fut_2", dayStart.Add(90*time.Minute), 20)
+	s.recordUsage("evt_fut_3", dayStart.Add(5*time.Hour), 30)
+
+	repo := &countingFeatureUsageRepo{FeatureUsageRepository: s.GetStores().FeatureUsageRepo}
+	s.service.featureUsageRepo = repo
+
+	analyticsRequest := func(windowSizes ...types.WindowSize) *dto.GetUsageAnalyticsRequest {
+		req := s.analyticsRequest()
+		req.StartTime = dayStart
+		req.WindowSizes = windowSizes
+		return req
+	}
+
+	s.Run("daily_and_hourly_windows_from_one_query", func() {
+		repo.analyticsQueries = 0
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), analyticsRequest(types.WindowSizeDay, types.WindowSizeHour))
+		s.NoError(err)
+		s.Equal(1, repo.analyticsQueries)
+		s.Len(resp.Items, 1)
+
+		item := resp.Items[0]
+		s.Empty(item.Points)
+		s.Len(item.PointsByWindow, 2)
+
+		hourly := item.PointsByWindow[types.WindowSizeHour]
+		s.Len(hourly, 2)
+		s.True(dayStart.Add(time.Hour).Equal(hourly[0].Timestamp))
+		s.True(decimal.NewFromInt(30).Equal(hourly[0].Usage))
+		s.True(decimal.NewFromInt(15).Equal(hourly[0].Cost))
+		s.Equal(uint64(2), hourly[0].EventCount)
+		s.True(dayStart.Add(5 * time.Hour).Equal(hourly[1].Timestamp))
+		s.True(decimal.NewFromInt(30).Equal(hourly[1].Usage))
+
+		daily := item.PointsByWindow[types.WindowSizeDay]
+		s.Len(daily, 1)
+		s.True(dayStart.Equal(daily[0].Timestamp))
+		s.True(decimal.NewFromInt(60).Equal(daily[0].Usage))
+		s.True(decimal.NewFromInt(30).Equal(daily[0].Cost))
+		s.Equal(uint64(3), daily[0].EventCount)
+		s.True(item.TotalCost.Equal(daily[0].Cost))
+	})
+
+	s.Run("keeps_points_of_window_size", func() {
+		req := analyticsRequest(types.WindowSizeDay)
+		req.WindowSize = types.WindowSizeHour
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+		s.Len(resp.Items[0].Points, 2)
+		s.Len(resp.Items[0].PointsByWindow, 1)
+		s.Len(resp.Items[0].PointsByWindow[types.WindowSizeDay], 1)
+	})
+
+	s.Run("queries_calendar_windows_separately", func() {
+		repo.analyticsQueries = 0
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), analyticsRequest(types.WindowSizeHour, types.WindowSizeMonth))
+		s.NoError(err)
+		s.Equal(2, repo.analyticsQueries)
+		s.Len(resp.Items, 1)
+		s.Len(resp.Items[0].PointsByWindow[types.WindowSizeMonth], 1)
+		s.True(decimal.NewFromInt(60).Equal(resp.Items[0].PointsByWindow[types.WindowSizeMonth][0].Usage))
+	})
+
+	s.Run("rejects_invalid_window_size", func() {
+		_, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), analyticsRequest(types.WindowSizeDay, types.WindowSize("FORTNIGHT")))
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsAccountingCalendar() {
+	today := s.testData.now.Truncate(24 * time.Hour)
+	s.recordUsage("evt_fiscal_1", today.AddDate(0, 0, -10), 10)
+	s.recordUsage("evt_fiscal_2", today.AddDate(0, 0, -9), 20)
+	s.recordUsage("evt_fiscal_3", today.AddDate(0, 0, -2).Add(3*time.Hour), 30)
+
+	repo := &countingFeatureUsageRepo{FeatureUsageRepository: s.GetStores().FeatureUsageRepo}
+	s.service.featureUsageRepo = repo
+
+	// The fiscal year starts a week ago, so the earlier events are in the 5 week period that
+	// ended the previous year
+	yearStart := today.AddDate(0, 0, -7)
+	analyticsRequest := func() *dto.GetUsageAnalyticsRequest {
+		req := s.analyticsRequest()
+		req.StartTime = today.AddDate(0, 0, -20)
+		req.Calendar = &types.AccountingCalendar{Type: types.AccountingCalendar445, YearStart: yearStart}
+		return req
+	}
+	assertFiscalPoints := func(points []dto.UsageAnalyticPoint) {
+		s.Len(points, 2)
+		s.True(yearStart.AddDate(0, 0, -35).Equal(points[0].Timestamp))
+		s.True(decimal.NewFromInt(30).Equal(points[0].Usage))
+		s.Equal(uint64(2), points[0].EventCount)
+		s.True(yearStart.Equal(points[1].Timestamp))
+		s.True(decimal.NewFromInt(30).Equal(points[1].Usage))
+		s.True(decimal.NewFromInt(15).Equal(points[1].Cost))
+	}
+
+	s.Run("month_windows_are_fiscal_periods", func() {
+		repo.analyticsQueries = 0
+		req := analyticsRequest()
+		req.WindowSize = types.WindowSizeMonth
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Equal(1, repo.analyticsQueries)
+		s.Len(resp.Items, 1)
+		assertFiscalPoints(resp.Items[0].Points)
+	})
+
+	s.Run("month_windows_rolled_up_with_other_windows", func() {
+		repo.analyticsQueries = 0
+		req := analyticsRequest()
+		req.WindowSizes = []types.WindowSize{types.WindowSizeDay, types.WindowSizeMonth}
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Equal(1, repo.analyticsQueries)
+		s.Len(resp.Items, 1)
+		s.Len(resp.Items[0].PointsByWindow, 2)
+		s.Len(resp.Items[0].PointsByWindow[types.WindowSizeDay], 3)
+		assertFiscalPoints(resp.Items[0].PointsByWindow[types.WindowSizeMonth])
+	})
+
+	s.Run("requires_month_window", func() {
+		req := analyticsRequest()
+		req.WindowSize = types.WindowSizeDay
+
+		_, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+
+	s.Run("rejects_invalid_calendar", func() {
+		req := analyticsRequest()
+		req.WindowSize = types.WindowSizeMonth
+		req.Calendar.Type = types.AccountingCalendarCustom
+
+		_, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsV2Segment() {
+	ctx := s.GetContext()
+
+	// addCustomer creates a customer subscribed to the test plan with usage of the given tokens
+	addCustomer := func(id string, tokens int64) *customer.Customer {
+		cust := &customer.Customer{
+			ID:         id,
+			ExternalID: "ext_" + id,
+			Name:       id,
+			BaseModel:  types.GetDefaultBaseModel(ctx),
+		}
+		s.Require().NoError(s.GetStores().CustomerRepo.Create(ctx, cust))
+
+		sub := *s.testData.subscription
+		sub.ID = "sub_" + id
+		sub.CustomerID = cust.ID
+		sub.LineItems = nil
+		lineItem := *s.testData.lineItem
+		lineItem.ID = "subli_" + id
+		lineItem.SubscriptionID = sub.ID
+		lineItem.CustomerID = cust.ID
+		s.Require().NoError(s.GetStores().SubscriptionRepo.CreateWithLineItems(ctx, &sub, []*subscription.SubscriptionLineItem{&lineItem}))
+
+		event := s.usageEvent("evt_"+id, s.testData.now.Add(-time.Hour), tokens)
+		event.ExternalCustomerID = cust.ExternalID
+		s.Require().NoError(s.service.processEvent(ctx, event))
+		return cust
+	}
+
+	s.recordUsage("evt_fut_segment_1", s.testData.now.Add(-time.Hour), 10)
+	second := addCustomer("cust_fut_segment_2", 20)
+	addCustomer("cust_fut_segment_3", 40)
+
+	segmentRequest := func(externalCustomerIDs ...string) *dto.GetUsageAnalyticsRequest {
+		req := s.analyticsRequest()
+		req.ExternalCustomerID = ""
+		req.ExternalCustomerIDs = externalCustomerIDs
+		return req
+	}
+
+	s.Run("combines_totals_of_segment_customers", func() {
+		resp, err := s.service.GetDetailedUsageAnalyticsV2(ctx, segmentRequest(s.testData.customer.ExternalID, second.ExternalID))
+		s.Require().NoError(err)
+
+		totalUsage := decimal.Zero
+		for _, item := range resp.Items {
+			totalUsage = totalUsage.Add(item.TotalUsage)
+		}
+		s.True(decimal.NewFromInt(30).Equal(totalUsage), "usage: %s", totalUsage)
+		s.True(decimal.NewFromInt(15).Equal(resp.TotalCost), "cost: %s", resp.TotalCost)
+		s.Equal("usd", resp.Currency)
+	})
+
+	s.Run("rejects_unknown_segment_customer", func() {
+		_, err := s.service.GetDetailedUsageAnalyticsV2(ctx, segmentRequest(s.testData.customer.ExternalID, "ext_cust_fut_unknown"))
+		s.Error(err)
+		s.True(ierr.IsNotFound(err))
+	})
+
+	s.Run("rejects_segment_with_single_customer", func() {
+		req := segmentRequest(second.ExternalID)
+		req.ExternalCustomerID = s.testData.customer.ExternalID
+		_, err := s.service.GetDetailedUsageAnalyticsV2(ctx, req)
+		s.True(ierr.IsValidation(err))
+	})
+
+	s.Run("rejects_segment_in_single_customer_analytics", func() {
+		req := s.analyticsRequest()
+		req.ExternalCustomerIDs = []string{second.ExternalID}
+		_, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsFillGaps() {
+	dayStart := s.testData.now.Truncate(24 * time.Hour).Add(-24 * time.Hour)
+	s.recordUsage("evt_fut_1", dayStart.Add(time.Hour), 10)
+	s.recordUsage("evt_fut_2", dayStart.Add(3*time.Hour), 30)
+
+	setFillGaps := func(aggregationType types.AggregationType, mode types.MeterGapFillMode) {
+		s.testData.meter.Aggregation = meter.Aggregation{Type: aggregationType, Field: "tokens", FillGaps: mode}
+		s.Require().NoError(s.testData.meter.Validate())
+		meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+		s.Require().NoError(meterStore.InMemoryStore.Update(s.GetContext(), s.testData.meter.ID, s.testData.meter))
+	}
+
+	hourlyUsage := func() []int64 {
+		req := s.analyticsRequest()
+		req.StartTime = dayStart
+		req.EndTime = dayStart.Add(5 * time.Hour)
+		req.WindowSize = types.WindowSizeHour
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.Require().NoError(err)
+		s.Require().Len(resp.Items, 1)
+
+		usage := make([]int64, 0, len(resp.Items[0].Points))
+		for _, point := range resp.Items[0].Points {
+			usage = append(usage, point.Usage.IntPart())
+		}
+		if len(usage) == 5 {
+			for i, point := range resp.Items[0].Points {
+				s.True(dayStart.Add(time.Duration(i)*time.Hour).Equal(point.Timestamp), "point %d at %s", i, point.Timestamp)
+			}
+		}
+		return usage
+	}
+
+	s.Run("gaps_without_points_by_default", func() {
+		setFillGaps(types.AggregationMax, "")
+		s.Equal([]int64{10, 30}, hourlyUsage())
+	})
+
+	s.Run("zero_fills_every_window", func() {
+		setFillGaps(types.AggregationMax, types.MeterGapFillModeZero)
+		s.Equal([]int64{0, 10, 0, 30, 0}, hourlyUsage())
+	})
+
+	s.Run("carry_over_repeats_last_reading", func() {
+		setFillGaps(types.AggregationLatest, types.MeterGapFillModeCarryOver)
+		s.Equal([]int64{0, 10, 10, 30, 30}, hourlyUsage())
+	})
+
+	s.Run("filled_points_carry_cost", func() {
+		setFillGaps(types.AggregationMax, types.MeterGapFillModeCarryOver)
+
+		req := s.analyticsRequest()
+		req.StartTime = dayStart
+		req.EndTime = dayStart.Add(5 * time.Hour)
+		req.WindowSize = types.WindowSizeHour
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.Require().NoError(err)
+		s.Require().Len(resp.Items[0].Points, 5)
+		s.True(decimal.NewFromInt(5).Equal(resp.Items[0].Points[2].Cost))
+		s.Zero(resp.Items[0].Points[2].EventCount)
+	})
+
+	s.Run("filled_points_are_not_buckets", func() {
+		s.testData.meter.Aggregation = meter.Aggregation{Type: types.AggregationMax, Field: "tokens", BucketSize: types.WindowSizeHour, FillGaps: types.MeterGapFillModeCarryOver}
+		s.Require().NoError(s.testData.meter.Validate())
+		meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+		s.Require().NoError(meterStore.InMemoryStore.Update(s.GetContext(), s.testData.meter.ID, s.testData.meter))
+
+		req := s.analyticsRequest()
+		req.StartTime = dayStart
+		req.EndTime = dayStart.Add(5 * time.Hour)
+		req.WindowSize = types.WindowSizeHour
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.Require().NoError(err)
+		s.Require().Len(resp.Items[0].Points, 5)
+		s.True(resp.Items[0].Points[2].Cost.IsZero(), "cost: %s", resp.Items[0].Points[2].Cost)
+		s.True(decimal.NewFromInt(20).Equal(resp.Items[0].TotalCost), "cost: %s", resp.Items[0].TotalCost)
+	})
+
+	s.Run("rejects_fill_gaps_for_additive_aggregation", func() {
+		m := *s.testData.meter
+		m.Aggregation = meter.Aggregation{Type: types.AggregationSum, Field: "tokens", FillGaps: types.MeterGapFillModeZero}
+		s.True(ierr.IsValidation(m.Validate()))
+
+		m.Aggregation = meter.Aggregation{Type: types.AggregationMax, Field: "tokens", FillGaps: "interpolate"}
+		s.True(ierr.IsValidation(m.Validate()))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsSecondWindow() {
+	minuteStart := s.testData.now.Truncate(time.Minute).Add(-10 * time.Minute)
+	s.recordUsage("evt_fut_1", minuteStart.Add(1200*time.Millisecond), 10)
+	s.recordUsage("evt_fut_2", minuteStart.Add(1800*time.Millisecond), 20)
+	s.recordUsage("evt_fut_3", minuteStart.Add(4*time.Second), 30)
+
+	s.Run("buckets_usage_by_second", func() {
+		req := s.analyticsRequest()
+		req.StartTime = minuteStart
+		req.EndTime = minuteStart.Add(time.Minute)
+		req.WindowSize = types.WindowSizeSecond
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+
+		points := resp.Items[0].Points
+		s.Require().Len(points, 2)
+		s.True(minuteStart.Add(time.Second).Equal(points[0].Timestamp))
+		s.True(decimal.NewFromInt(30).Equal(points[0].Usage))
+		s.Equal(uint64(2), points[0].EventCount)
+		s.True(minuteStart.Add(4 * time.Second).Equal(points[1].Timestamp))
+		s.True(decimal.NewFromInt(30).Equal(points[1].Usage))
+	})
+
+	s.Run("rejects_second_window_over_long_range", func() {
+		req := s.analyticsRequest()
+		req.WindowSize = types.WindowSizeSecond
+
+		_, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+
+	s.Run("rejects_second_window_in_window_sizes_over_long_range", func() {
+		req := s.analyticsRequest()
+		req.WindowSizes = []types.WindowSize{types.WindowSizeHour, types.WindowSizeSecond}
+
+		_, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsEffectiveRate() {
+	// First 50 tokens at 1.00, the rest at 0.50
+	s.testData.price.BillingModel = types.BILLING_MODEL_TIERED
+	s.testData.price.TierMode = types.BILLING_TIER_SLAB
+	s.testData.price.Tiers = []price.PriceTier{
+		{UpTo: lo.ToPtr(uint64(50)), UnitAmount: decimal.NewFromInt(1)},
+		{UnitAmount: decimal.NewFromFloat(0.5)},
+	}
+	s.NoError(s.GetStores().PriceRepo.Update(s.GetContext(), s.testData.price))
+
+	s.Run("zero_without_usage", func() {
+		s.True(effectiveRate(decimal.NewFromInt(5), decimal.Zero).IsZero())
+	})
+
+	s.recordUsage("evt_fut_1", s.testData.now.Add(-3*time.Hour), 40)
+	s.recordUsage("evt_fut_2", s.testData.now.Add(-1*time.Hour), 60)
+
+	s.Run("blended_rate_between_tier_prices", func() {
+		req := s.analyticsRequest()
+		req.WindowSize = types.WindowSizeHour
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+
+		// 50 * 1.00 + 50 * 0.50 = 75 for 100 tokens
+		item := resp.Items[0]
+		s.True(decimal.NewFromInt(75).Equal(item.TotalCost), "total cost: %s", item.TotalCost)
+		s.True(decimal.NewFromFloat(0.75).Equal(item.EffectiveRate), "effective rate: %s", item.EffectiveRate)
+
+		s.Len(item.Points, 2)
+		for _, point := range item.Points {
+			s.True(point.Cost.Div(point.Usage).Equal(point.EffectiveRate))
+			s.True(point.EffectiveRate.GreaterThanOrEqual(decimal.NewFromFloat(0.5)))
+			s.True(point.EffectiveRate.LessThanOrEqual(decimal.NewFromInt(1)))
+		}
+	})
+}
+
+// listBarrier releases the enrichment List calls of the wrapped repositories once all of them
+// are in flight at the same time, and gives up after a second when they run one after another
+type listBarrier struct {
+	mu      sync.Mutex
+	parties int
+	release chan struct{}
+	timeout atomic.Bool
+}
+
+func newListBarrier(parties int) *listBarrier {
+	return &listBarrier{parties: parties, release: make(chan struct{})}
+}
+
+func (b *listBarrier) await() {
+	b.mu.Lock()
+	b.parties--
+	if b.parties == 0 {
+		close(b.release)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.release:
+	case <-time.After(time.Second):
+		b.timeout.Store(true)
+	}
+}
+
+type barrierFeatureRepo struct {
+	feature.Repository
+	barrier *listBarrier
+}
+
+func (r *barrierFeatureRepo) List(ctx context.Context, filter *types.FeatureFilter) ([]*feature.Feature, error) {
+	if len(filter.FeatureIDs) > 0 {
+		r.barrier.await()
+	}
+	return r.Repository.List(ctx, filter)
+}
+
+type barrierPriceRepo struct {
+	price.Repository
+	barrier *listBarrier
+}
+
+func (r *barrierPriceRepo) List(ctx context.Context, filter *types.PriceFilter) ([]*price.Price, error) {
+	if filter.AllowExpiredPrices {
+		r.barrier.await()
+	}
+	return r.Repository.List(ctx, filter)
+}
+
+type barrierPlanRepo struct {
+	plan.Repository
+	barrier *listBarrier
+}
+
+func (r *barrierPlanRepo) List(ctx context.Context, filter *types.PlanFilter) ([]*plan.Plan, error) {
+	if len(filter.PlanIDs) == 0 {
+		r.barrier.await()
+	}
+	return r.Repository.List(ctx, filter)
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsConcurrentEnrichment() {
+	s.recordUsage("evt_fut_1", s.testData.now.Add(-3*time.Hour), 40)
+	s.recordUsage("evt_fut_2", s.testData.now.Add(-1*time.Hour), 60)
+
+	req := s.analyticsRequest()
+	req.Expand = []string{"plan"}
+
+	expected, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+	s.NoError(err)
+	s.Len(expected.Items, 1)
+
+	// Features, prices and plans are only released once all three are being fetched
+	barrier := newListBarrier(3)
+	stores := s.GetStores()
+	s.service.FeatureRepo = &barrierFeatureRepo{Repository: stores.FeatureRepo, barrier: barrier}
+	s.service.PriceRepo = &barrierPriceRepo{Repository: stores.PriceRepo, barrier: barrier}
+	s.service.PlanRepo = &barrierPlanRepo{Repository: stores.PlanRepo, barrier: barrier}
+
+	resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+	s.NoError(err)
+	s.False(barrier.timeout.Load(), "enrichment fetches ran sequentially")
+	s.Equal(expected, resp)
+
+	item := resp.Items[0]
+	s.Equal(s.testData.feature.Name, item.FeatureName)
+	s.Equal(s.testData.meter.EventName, item.EventName)
+	s.True(decimal.NewFromInt(50).Equal(item.TotalCost), "total cost: %s", item.TotalCost)
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestFindOrphanedUsage() {
+	// The in-memory subscription store keeps its line items apart from the line item store
+	s.NoError(s.GetStores().SubscriptionLineItemRepo.Create(s.GetContext(), s.testData.lineItem))
+	s.recordUsage("evt_fut_attributed", s.testData.now.Add(-2*time.Hour), 10)
+
+	// Usage attributed to a line item that gets deleted afterwards
+	deleted := *s.testData.lineItem
+	deleted.ID = "subs_line_fut_deleted"
+	s.NoError(s.GetStores().SubscriptionLineItemRepo.Create(s.GetContext(), &deleted))
+	s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(s.GetContext(), &events.FeatureUsage{
+		Event: events.Event{
+			ID:                 "evt_fut_orphaned",
+			TenantID:           types.GetTenantID(s.GetContext()),
+			EventName:          s.testData.meter.EventName,
+			ExternalCustomerID: s.testData.customer.ExternalID,
+			CustomerID:         s.testData.customer.ID,
+			Timestamp:          s.testData.now.Add(-time.Hour),
+		},
+		SubscriptionID: s.testData.subscription.ID,
+		SubLineItemID:  deleted.ID,
+		PriceID:        s.testData.price.ID,
+		MeterID:        s.testData.meter.ID,
+		FeatureID:      s.testData.feature.ID,
+		UniqueHash:     "evt_fut_orphaned",
+		QtyTotal:       decimal.NewFromInt(20),
+		Sign:           1,
+	}))
+	s.NoError(s.GetStores().SubscriptionLineItemRepo.Delete(s.GetContext(), deleted.ID))
+
+	s.Run("detects_usage_of_deleted_line_item", func() {
+		orphaned, err := s.service.FindOrphanedUsage(s.GetContext(), &events.FindOrphanedUsageParams{
+			StartTime: s.testData.now.Add(-24 * time.Hour),
+			EndTime:   s.testData.now,
+		})
+		s.NoError(err)
+		s.Len(orphaned, 1)
+		s.Equal("evt_fut_orphaned", orphaned[0].ID)
+		s.Equal(deleted.ID, orphaned[0].SubLineItemID)
+	})
+
+	s.Run("only_within_window", func() {
+		orphaned, err := s.service.FindOrphanedUsage(s.GetContext(), &events.FindOrphanedUsageParams{
+			StartTime: s.testData.now.Add(-24 * time.Hour),
+			EndTime:   s.testData.now.Add(-90 * time.Minute),
+		})
+		s.NoError(err)
+		s.Empty(orphaned)
+	})
+
+	s.Run("rejects_invalid_window", func() {
+		_, err := s.service.FindOrphanedUsage(s.GetContext(), &events.FindOrphanedUsageParams{
+			StartTime: s.testData.now,
+			EndTime:   s.testData.now.Add(-time.Hour),
+		})
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+
+	s.Run("rejects_window_longer_than_the_maximum", func() {
+		_, err := s.service.FindOrphanedUsage(s.GetContext(), &events.FindOrphanedUsageParams{
+			StartTime: s.testData.now.Add(-maxOrphanedUsageWindow - time.Hour),
+			EndTime:   s.testData.now,
+		})
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsSources() {
+	s.recordUsageFromSource("evt_fut_src_1", s.testData.now.Add(-3*time.Hour), 10, "sdk")
+	s.recordUsageFromSource("evt_fut_src_2", s.testData.now.Add(-2*time.Hour), 20, "api")
+	s.recordUsageFromSource("evt_fut_src_3", s.testData.now.Add(-1*time.Hour), 30, "sdk")
+
+	s.Run("feature_grouping_reports_all_sources", func() {
+		req := s.analyticsRequest()
+		req.GroupBy = []string{"feature_id"}
+		req.Expand = []string{"sources"}
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.True(decimal.NewFromInt(60).Equal(resp.Items[0].TotalUsage))
+		s.Empty(resp.Items[0].Source)
+		s.Equal([]string{"api", "sdk"}, resp.Items[0].Sources)
+	})
+
+	s.Run("sources_not_returned_unless_expanded", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), s.analyticsRequest())
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.Empty(resp.Items[0].Sources)
+	})
+
+	s.Run("source_grouping_reports_own_source", func() {
+		req := s.analyticsRequest()
+		req.GroupBy = []string{"source"}
+		req.Expand = []string{"sources"}
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Require().Len(resp.Items, 2)
+		for _, item := range resp.Items {
+			s.Equal([]string{item.Source}, item.Sources)
+		}
+	})
+
+	s.Run("merged_groups_combine_sources", func() {
+		analytic := func(source string, sources ...string) *events.DetailedUsageAnalytic {
+			return &events.DetailedUsageAnalytic{
+				FeatureID:  s.testData.feature.ID,
+				PriceID:    s.testData.price.ID,
+				MeterID:    s.testData.meter.ID,
+				Source:     source,
+				Sources:    sources,
+				TotalUsage: decimal.NewFromInt(1),
+				Properties: map[string]string{"org": "acme"},
+			}
+		}
+
+		result := s.service.aggregateAnalyticsByGrouping([]*events.DetailedUsageAnalytic{
+			analytic("sdk"),
+			analytic("", "api", "sdk"),
+			analytic("", "batch"),
+		}, []string{"properties.org"})
+		s.Require().Len(result, 1)
+		s.Empty(result[0].Source)
+		s.Equal([]string{"api", "batch", "sdk"}, result[0].Sources)
+		s.True(decimal.NewFromInt(3).Equal(result[0].TotalUsage))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsMatchedFilters() {
+	ctx := s.GetContext()
+	s.NoError(s.GetStores().MeterRepo.UpdateMeter(ctx, s.testData.meter.ID, []meter.Filter{
+		{Key: "region", Values: []string{"apac", "eu", "us"}},
+	}))
+
+	regionEvent := func(eventID string, region string, tokens int64) *events.Event {
+		event := s.usageEvent(eventID, s.testData.now.Add(-time.Hour), tokens)
+		event.Properties["region"] = region
+		return event
+	}
+	s.NoError(s.service.processEvent(ctx, regionEvent("evt_fut_region_1", "eu", 10)))
+	s.NoError(s.service.processEvent(ctx, regionEvent("evt_fut_region_2", "us", 20)))
+	s.NoError(s.service.processEvent(ctx, regionEvent("evt_fut_region_3", "eu", 30)))
+
+	s.Run("reports_filter_values_that_matched", func() {
+		req := s.analyticsRequest()
+		req.Expand = []string{"matched_filters"}
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.Equal(s.testData.price.ID, resp.Items[0].PriceID)
+		s.True(decimal.NewFromInt(60).Equal(resp.Items[0].TotalUsage))
+		s.Equal([]meter.Filter{{Key: "region", Values: []string{"eu", "us"}}}, resp.Items[0].MatchedFilters)
+	})
+
+	s.Run("matched_filters_not_returned_unless_expanded", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, s.analyticsRequest())
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.Empty(resp.Items[0].MatchedFilters)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsAggregationField() {
+	ctx := s.GetContext()
+
+	s.Run("reports_aggregation_field", func() {
+		s.NoError(s.service.processEvent(ctx, s.usageEvent("evt_fut_agg_field_sum", s.testData.now.Add(-time.Hour), 10)))
+
+		req := s.analyticsRequest()
+		req.Expand = []string{"aggregation_field"}
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.Equal("tokens", resp.Items[0].AggregationField)
+		// Only count unique meters report the values they counted
+		s.Empty(resp.Items[0].AggregationFieldValues)
+	})
+
+	s.Run("reports_sampled_values_of_count_unique", func() {
+		s.testData.meter.Aggregation = meter.Aggregation{Type: types.AggregationCountUnique, Field: "user_id"}
+		s.NoError(s.testData.meter.Validate())
+		meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+		s.NoError(meterStore.InMemoryStore.Update(ctx, s.testData.meter.ID, s.testData.meter))
+
+		for i := 0; i < dto.MaxAnalyticsAggregationValues+2; i++ {
+			event := s.usageEvent(fmt.Sprintf("evt_fut_agg_field_user_%d", i), s.testData.now.Add(-time.Hour), 0)
+			event.Properties["user_id"] = fmt.Sprintf("user_%02d", i%(dto.MaxAnalyticsAggregationValues+1))
+			s.NoError(s.service.processEvent(ctx, event))
+		}
+
+		req := s.analyticsRequest()
+		req.Expand = []string{"aggregation_field"}
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.Equal("user_id", resp.Items[0].AggregationField)
+		s.Len(resp.Items[0].AggregationFieldValues, dto.MaxAnalyticsAggregationValues)
+		s.Equal(len(lo.Uniq(resp.Items[0].AggregationFieldValues)), len(resp.Items[0].AggregationFieldValues))
+		for _, value := range resp.Items[0].AggregationFieldValues {
+			s.Contains(value, "user_")
+		}
+	})
+
+	s.Run("aggregation_field_not_returned_unless_expanded", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, s.analyticsRequest())
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.Empty(resp.Items[0].AggregationField)
+		s.Empty(resp.Items[0].AggregationFieldValues)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsWeightProperty() {
+	ctx := s.GetContext()
+
+	sizedEvent := func(eventID string, tokens int64, size interface{}) *events.Event {
+		event := s.usageEvent(eventID, s.testData.now.Add(-time.Hour), tokens)
+		if size != nil {
+			event.Properties["request_size"] = size
+		}
+		return event
+	}
+	s.NoError(s.service.processEvent(ctx, sizedEvent("evt_fut_weight_1", 10, float64(2))))
+	s.NoError(s.service.processEvent(ctx, sizedEvent("evt_fut_weight_2", 20, "0.5")))
+	s.NoError(s.service.processEvent(ctx, sizedEvent("evt_fut_weight_3", 30, nil)))
+
+	s.Run("weighted_total_differs_from_raw_total", func() {
+		req := s.analyticsRequest()
+		req.WeightProperty = "request_size"
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.True(decimal.NewFromInt(60).Equal(resp.Items[0].TotalUsage), "usage: %s", resp.Items[0].TotalUsage)
+		// 10*2 + 20*0.5, the event without a weight weighs 0
+		s.Require().NotNil(resp.Items[0].WeightedUsage)
+		s.True(decimal.NewFromInt(30).Equal(*resp.Items[0].WeightedUsage), "weighted usage: %s", resp.Items[0].WeightedUsage)
+	})
+
+	s.Run("weighted_usage_merged_across_groups", func() {
+		req := s.analyticsRequest()
+		req.WeightProperty = "request_size"
+		req.GroupBy = []string{"source"}
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.Require().NotNil(resp.Items[0].WeightedUsage)
+		s.True(decimal.NewFromInt(30).Equal(*resp.Items[0].WeightedUsage), "weighted usage: %s", resp.Items[0].WeightedUsage)
+	})
+
+	s.Run("unweighted_without_weight_property", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, s.analyticsRequest())
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.True(decimal.NewFromInt(60).Equal(resp.Items[0].TotalUsage))
+		s.Nil(resp.Items[0].WeightedUsage)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsPriceFilter() {
+	ctx := s.GetContext()
+	overridePrice := *s.testData.price
+	overridePrice.ID = "price_fut_tokens_override"
+	s.NoError(s.GetStores().PriceRepo.Create(ctx, &overridePrice))
+
+	s.recordUsage("evt_fut_price_1", s.testData.now.Add(-3*time.Hour), 10)
+	s.recordUsage("evt_fut_price_2", s.testData.now.Add(-2*time.Hour), 20)
+	s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(ctx, &events.FeatureUsage{
+		Event: events.Event{
+			ID:                 "evt_fut_price_override",
+			TenantID:           types.GetTenantID(ctx),
+			EnvironmentID:      types.GetEnvironmentID(ctx),
+			EventName:          s.testData.meter.EventName,
+			ExternalCustomerID: s.testData.customer.ExternalID,
+			CustomerID:         s.testData.customer.ID,
+			Timestamp:          s.testData.now.Add(-1 * time.Hour),
+			Properties:         map[string]interface{}{"tokens": float64(5)},
+		},
+		SubscriptionID: s.testData.subscription.ID,
+		SubLineItemID:  s.testData.lineItem.ID,
+		PriceID:        overridePrice.ID,
+		MeterID:        s.testData.meter.ID,
+		FeatureID:      s.testData.feature.ID,
+		UniqueHash:     "evt_fut_price_override",
+		QtyTotal:       decimal.NewFromInt(5),
+		Sign:           1,
+	}))
+
+	s.Run("only_usage_of_the_price", func() {
+		req := s.analyticsRequest()
+		req.PriceIDs = []string{overridePrice.ID}
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.Equal(overridePrice.ID, resp.Items[0].PriceID)
+		s.True(decimal.NewFromInt(5).Equal(resp.Items[0].TotalUsage))
+	})
+
+	s.Run("composes_with_feature_filter", func() {
+		req := s.analyticsRequest()
+		req.PriceIDs = []string{s.testData.price.ID}
+		req.FeatureIDs = []string{s.testData.feature.ID}
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.Equal(s.testData.price.ID, resp.Items[0].PriceID)
+		s.True(decimal.NewFromInt(30).Equal(resp.Items[0].TotalUsage))
+
+		req.FeatureIDs = []string{"feat_fut_other"}
+		resp, err = s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Empty(resp.Items)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsBillableOnly() {
+	ctx := s.GetContext()
+
+	// A second subscription with a line item of a shadow meter and a line item that ended 2 hours ago
+	shadowMeter := *s.testData.meter
+	shadowMeter.ID = "meter_fut_tokens_shadow"
+	shadowMeter.BillingMode = types.MeterBillingModeShadow
+	s.NoError(s.GetStores().MeterRepo.CreateMeter(ctx, &shadowMeter))
+	sub := *s.testData.subscription
+	sub.ID = "sub_fut_billable"
+	shadowLineItem := *s.testData.lineItem
+	shadowLineItem.ID = "subli_fut_tokens_shadow"
+	shadowLineItem.SubscriptionID = sub.ID
+	shadowLineItem.MeterID = shadowMeter.ID
+	endedLineItem := *s.testData.lineItem
+	endedLineItem.ID = "subli_fut_tokens_ended"
+	endedLineItem.SubscriptionID = sub.ID
+	endedLineItem.EndDate = s.testData.now.Add(-2 * time.Hour)
+	s.NoError(s.GetStores().SubscriptionRepo.CreateWithLineItems(ctx, &sub, []*subscription.SubscriptionLineItem{&shadowLineItem, &endedLineItem}))
+
+	insertUsage := func(eventID string, lineItem *subscription.SubscriptionLineItem, timestamp time.Time, tokens int64) {
+		s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(ctx, &events.FeatureUsage{
+			Event: events.Event{
+				ID:                 eventID,
+				TenantID:           types.GetTenantID(ctx),
+				EnvironmentID:      types.GetEnvironmentID(ctx),
+				EventName:          s.testData.meter.EventName,
+				ExternalCustomerID: s.testData.customer.ExternalID,
+				CustomerID:         s.testData.customer.ID,
+				Timestamp:          timestamp,
+				Properties:         map[string]interface{}{"tokens": float64(tokens)},
+			},
+			SubscriptionID: lineItem.SubscriptionID,
+			SubLineItemID:  lineItem.ID,
+			PriceID:        lineItem.PriceID,
+			MeterID:        lineItem.MeterID,
+			FeatureID:      s.testData.feature.ID,
+			UniqueHash:     eventID,
+			QtyTotal:       decimal.NewFromInt(tokens),
+			Sign:           1,
+		}))
+	}
+	insertUsage("evt_fut_billable", s.testData.lineItem, s.testData.now.Add(-3*time.Hour), 10)
+	insertUsage("evt_fut_shadow", &shadowLineItem, s.testData.now.Add(-3*time.Hour), 20)
+	insertUsage("evt_fut_ended_before", &endedLineItem, s.testData.now.Add(-3*time.Hour), 30)
+	insertUsage("evt_fut_ended_after", &endedLineItem, s.testData.now.Add(-1*time.Hour), 40)
+
+	totalUsage := func(resp *dto.GetUsageAnalyticsResponse) decimal.Decimal {
+		total := decimal.Zero
+		for _, item := range resp.Items {
+			total = total.Add(item.TotalUsage)
+		}
+		return total
+	}
+
+	s.Run("all_usage_by_default", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, s.analyticsRequest())
+		s.NoError(err)
+		s.True(decimal.NewFromInt(100).Equal(totalUsage(resp)), "total usage: %s", totalUsage(resp))
+	})
+
+	s.Run("excludes_shadow_and_ended_line_item_usage", func() {
+		req := s.analyticsRequest()
+		req.BillableOnly = true
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.True(decimal.NewFromInt(40).Equal(totalUsage(resp)), "total usage: %s", totalUsage(resp))
+		s.True(decimal.NewFromInt(20).Equal(resp.TotalCost), "total cost: %s", resp.TotalCost)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsPagination() {
+	s.recordUsageFromSource("evt_fut_page_1", s.testData.now.Add(-3*time.Hour), 10, "source_a")
+	s.recordUsageFromSource("evt_fut_page_2", s.testData.now.Add(-2*time.Hour), 20, "source_b")
+	s.recordUsageFromSource("evt_fut_page_3", s.testData.now.Add(-1*time.Hour), 30, "source_c")
+
+	pageRequest := func(limit int, cursor string) *dto.GetUsageAnalyticsRequest {
+		req := s.analyticsRequest()
+		req.GroupBy = []string{"source"}
+		req.Limit = limit
+		req.Cursor = cursor
+		return req
+	}
+
+	unpaged, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), pageRequest(0, ""))
+	s.Require().NoError(err)
+	s.Require().Len(unpaged.Items, 3)
+	s.Nil(unpaged.PageTotalCost)
+	s.False(unpaged.HasMore)
+
+	s.Run("pages_through_items", func() {
+		first, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), pageRequest(2, ""))
+		s.Require().NoError(err)
+		s.Equal([]string{"source_a", "source_b"}, lo.Map(first.Items, func(item dto.UsageAnalyticItem, _ int) string { return item.Source }))
+		s.True(first.HasMore)
+		s.NotEmpty(first.NextCursor)
+		s.True(unpaged.TotalCost.Equal(first.TotalCost), "total cost covers all items")
+		s.Require().NotNil(first.PageTotalCost)
+		s.True(first.Items[0].TotalCost.Add(first.Items[1].TotalCost).Equal(*first.PageTotalCost))
+
+		second, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), pageRequest(2, first.NextCursor))
+		s.Require().NoError(err)
+		s.Equal([]string{"source_c"}, lo.Map(second.Items, func(item dto.UsageAnalyticItem, _ int) string { return item.Source }))
+		s.False(second.HasMore)
+		s.Empty(second.NextCursor)
+		s.True(unpaged.TotalCost.Equal(second.TotalCost))
+		s.Require().NotNil(second.PageTotalCost)
+		s.True(first.PageTotalCost.Add(*second.PageTotalCost).Equal(unpaged.TotalCost))
+	})
+
+	s.Run("cursor_past_the_last_item_returns_empty_page", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), pageRequest(2, encodeAnalyticsCursor(5)))
+		s.Require().NoError(err)
+		s.Empty(resp.Items)
+		s.False(resp.HasMore)
+		s.True(resp.PageTotalCost.IsZero())
+	})
+
+	s.Run("rejects_invalid_pages", func() {
+		_, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), pageRequest(dto.MaxAnalyticsPageSize+1, ""))
+		s.True(ierr.IsValidation(err))
+
+		_, err = s.service.GetDetailedUsageAnalytics(s.GetContext(), pageRequest(2, "not-a-cursor"))
+		s.True(ierr.IsValidation(err))
+
+		_, err = s.service.GetDetailedUsageAnalytics(s.GetContext(), pageRequest(0, encodeAnalyticsCursor(2)))
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestAnalyticsCostPrecision() {
+	data := &AnalyticsData{
+		Analytics: []*events.DetailedUsageAnalytic{
+			{FeatureID: "feat_a", FeatureName: "a", TotalCost: decimal.RequireFromString("1.005")},
+			{FeatureID: "feat_b", FeatureName: "b", TotalCost: decimal.RequireFromString("0.004")},
+			{FeatureID: "feat_c", FeatureName: "c", TotalCost: decimal.RequireFromString("0.004")},
+		},
+	}
+	costs := func(resp *dto.GetUsageAnalyticsResponse) []string {
+		return lo.Map(resp.Items, func(item dto.UsageAnalyticItem, _ int) string { return item.TotalCost.String() })
+	}
+
+	s.Run("unrounded_by_default", func() {
+		resp, err := s.presentAnalytics(s.GetContext(), data, s.analyticsRequest())
+		s.Require().NoError(err)
+		s.Equal([]string{"1.005", "0.004", "0.004"}, costs(resp))
+		s.Equal("1.013", resp.TotalCost.String())
+	})
+
+	s.NoError(s.GetStores().SettingsRepo.Create(s.GetContext(), &settings.Setting{
+		ID:  s.GetUUID(),
+		Key: string(types.SettingKeyAnalyticsConfig),
+		Value: map[string]interface{}{
+			"cost_precision": 2,
+		},
+		EnvironmentID: types.GetEnvironmentID(s.GetContext()),
+		BaseModel:     types.GetDefaultBaseModel(s.GetContext()),
+	}))
+
+	s.Run("rounded_to_tenant_precision", func() {
+		resp, err := s.presentAnalytics(s.GetContext(), data, s.analyticsRequest())
+		s.Require().NoError(err)
+		s.Equal([]string{"1.01", "0", "0"}, costs(resp))
+		// The total is rounded from the exact costs, not summed from the rounded ones
+		s.Equal("1.01", resp.TotalCost.String())
+		s.True(data.Analytics[0].TotalCost.Equal(decimal.RequireFromString("1.005")), "computed costs stay unrounded")
+	})
+
+	s.Run("page_total_rounded_from_exact_costs", func() {
+		req := s.analyticsRequest()
+		req.Limit = 2
+		req.Cursor = encodeAnalyticsCursor(1)
+		resp, err := s.presentAnalytics(s.GetContext(), data, req)
+		s.Require().NoError(err)
+		s.Equal([]string{"0", "0"}, costs(resp))
+		s.Equal("0.01", resp.PageTotalCost.String())
+		s.Equal("1.01", resp.TotalCost.String())
+	})
+
+	s.Run("other_environments_unaffected", func() {
+		ctx := context.WithValue(s.GetContext(), types.CtxEnvironmentID, "env_other_precision")
+		resp, err := s.presentAnalytics(ctx, data, s.analyticsRequest())
+		s.Require().NoError(err)
+		s.Equal("1.013", resp.TotalCost.String())
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestAnalyticsSettlementCurrency() {
+	data := &AnalyticsData{
+		Analytics: []*events.DetailedUsageAnalytic{
+			{FeatureID: "feat_a", FeatureName: "a", Currency: "usd", TotalCost: decimal.RequireFromString("100")},
+			{FeatureID: "feat_b", FeatureName: "b", Currency: "usd", TotalCost: decimal.RequireFromString("0.505")},
+			{FeatureID: "feat_c", FeatureName: "c", Currency: "eur", TotalCost: decimal.RequireFromString("10")},
+			{FeatureID: "feat_d", FeatureName: "d", Currency: "gbp", TotalCost: decimal.RequireFromString("5")},
+			{FeatureID: "feat_e", FeatureName: "e"},
+		},
+	}
+	settled := func(resp *dto.GetUsageAnalyticsResponse) []string {
+		return lo.Map(resp.Items, func(item dto.UsageAnalyticItem, _ int) string {
+			if item.SettledCost == nil {
+				return ""
+			}
+			return item.SettledCost.String()
+		})
+	}
+
+	s.Run("not_settled_by_default", func() {
+		resp, err := s.presentAnalytics(s.GetContext(), data, s.analyticsRequest())
+		s.Require().NoError(err)
+		s.Empty(resp.SettlementCurrency)
+		s.Nil(resp.SettledTotalCost)
+		s.Equal([]string{"", "", "", "", ""}, settled(resp))
+	})
+
+	s.NoError(s.GetStores().SettingsRepo.Create(s.GetContext(), &settings.Setting{
+		ID:  s.GetUUID(),
+		Key: string(types.SettingKeySettlementConfig),
+		Value: map[string]interface{}{
+			"currency":         "eur",
+			"conversion_rates": map[string]interface{}{"usd": "0.92"},
+		},
+		EnvironmentID: types.GetEnvironmentID(s.GetContext()),
+		BaseModel:     types.GetDefaultBaseModel(s.GetContext()),
+	}))
+
+	s.Run("usd_priced_eur_settled", func() {
+		resp, err := s.presentAnalytics(s.GetContext(), data, s.analyticsRequest())
+		s.Require().NoError(err)
+		s.Equal("eur", resp.SettlementCurrency)
+		// The priced costs are preserved next to the settled ones
+		s.Equal("115.505", resp.TotalCost.String())
+		s.Equal([]string{"92", "0.4646", "10", "", ""}, settled(resp))
+		s.Equal("0.92", resp.Items[0].SettlementRate.String())
+		s.Equal("1", resp.Items[2].SettlementRate.String())
+		// GBP has no conversion rate, it is left out of the settled total and flagged
+		s.Equal("102.4646", resp.SettledTotalCost.String())
+		s.Require().Len(resp.Warnings, 1)
+		s.Equal(types.UsageAnalyticsWarningMissingConversionRate, resp.Warnings[0].Code)
+	})
+
+	s.NoError(s.GetStores().SettingsRepo.Create(s.GetContext(), &settings.Setting{
+		ID:  s.GetUUID(),
+		Key: string(types.SettingKeyAnalyticsConfig),
+		Value: map[string]interface{}{
+			"cost_precision": 2,
+		},
+		EnvironmentID: types.GetEnvironmentID(s.GetContext()),
+		BaseModel:     types.GetDefaultBaseModel(s.GetContext()),
+	}))
+
+	s.Run("settled_costs_rounded_to_tenant_precision", func() {
+		resp, err := s.presentAnalytics(s.GetContext(), data, s.analyticsRequest())
+		s.Require().NoError(err)
+		s.Equal([]string{"92", "0.46", "10", "", ""}, settled(resp))
+		s.Equal("102.46", resp.SettledTotalCost.String())
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestAnalyticsByPriceEntityType() {
+	priceResponse := func(id string, entityType types.PriceEntityType, entityID, parentPriceID string) *dto.PriceResponse {
+		return &dto.PriceResponse{Price: &price.Price{
+			ID:            id,
+			EntityType:    entityType,
+			EntityID:      entityID,
+			ParentPriceID: parentPriceID,
+		}}
+	}
+	data := &AnalyticsData{
+		Analytics: []*events.DetailedUsageAnalytic{
+			{FeatureID: "feat_plan", FeatureName: "plan", PriceID: "price_plan", Currency: "usd", TotalCost: decimal.NewFromInt(10)},
+			{FeatureID: "feat_addon", FeatureName: "addon", PriceID: "price_addon", Currency: "usd", TotalCost: decimal.NewFromInt(4)},
+			{FeatureID: "feat_override", FeatureName: "override", PriceID: "price_override", Currency: "usd", TotalCost: decimal.NewFromInt(2)},
+			{FeatureID: "feat_unpriced", FeatureName: "unpriced"},
+		},
+		PriceResponses: map[string]*dto.PriceResponse{
+			"price_plan":     priceResponse("price_plan", types.PRICE_ENTITY_TYPE_PLAN, "plan_1", ""),
+			"price_addon":    priceResponse("price_addon", types.PRICE_ENTITY_TYPE_ADDON, "addon_1", ""),
+			"price_override": priceResponse("price_override", types.PRICE_ENTITY_TYPE_SUBSCRIPTION, "sub_1", "price_plan"),
+		},
+	}
+	features := func(resp *dto.GetUsageAnalyticsResponse) []string {
+		return lo.Map(resp.Items, func(item dto.UsageAnalyticItem, _ int) string { return item.FeatureID })
+	}
+
+	s.Run("all_usage_by_default", func() {
+		resp, err := s.service.ToGetUsageAnalyticsResponseDTO(s.GetContext(), data, s.analyticsRequest())
+		s.Require().NoError(err)
+		s.Equal([]string{"feat_addon", "feat_override", "feat_plan", "feat_unpriced"}, features(resp))
+		s.Equal("16", resp.TotalCost.String())
+	})
+
+	s.Run("addon_usage_only", func() {
+		req := s.analyticsRequest()
+		req.EntityType = types.PRICE_ENTITY_TYPE_ADDON
+		resp, err := s.service.ToGetUsageAnalyticsResponseDTO(s.GetContext(), data, req)
+		s.Require().NoError(err)
+		s.Equal([]string{"feat_addon"}, features(resp))
+		s.Equal("addon_1", resp.Items[0].AddOnID)
+		s.Equal("4", resp.TotalCost.String())
+	})
+
+	s.Run("plan_usage_includes_overrides", func() {
+		req := s.analyticsRequest()
+		req.EntityType = types.PRICE_ENTITY_TYPE_PLAN
+		resp, err := s.service.ToGetUsageAnalyticsResponseDTO(s.GetContext(), data, req)
+		s.Require().NoError(err)
+		s.Equal([]string{"feat_override", "feat_plan"}, features(resp))
+		s.Equal("12", resp.TotalCost.String())
+	})
+
+	s.Run("rejects_other_entity_types", func() {
+		req := s.analyticsRequest()
+		req.EntityType = types.PRICE_ENTITY_TYPE_SUBSCRIPTION
+		_, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestAnalyticsTierBreakdown() {
+	upTo100 := uint64(100)
+	upTo1000 := uint64(1000)
+	tieredPrice := &price.Price{
+		ID:           "price_tiered",
+		Currency:     "usd",
+		BillingModel: types.BILLING_MODEL_TIERED,
+		TierMode:     types.BILLING_TIER_SLAB,
+		Tiers: []price.PriceTier{
+			{UpTo: &upTo100, UnitAmount: decimal.RequireFromString("0.1")},
+			{UpTo: &upTo1000, UnitAmount: decimal.RequireFromString("0.05")},
+			{UnitAmount: decimal.RequireFromString("0.01")},
+		},
+	}
+	data := &AnalyticsData{
+		Analytics: []*events.DetailedUsageAnalytic{
+			{FeatureID: "feat_tiered", FeatureName: "tiered", PriceID: tieredPrice.ID, MeterID: "meter_sum", AggregationType: types.AggregationSum, TotalUsage: decimal.NewFromInt(1500), Currency: "usd", TotalCost: decimal.NewFromInt(59)},
+			{FeatureID: "feat_flat", FeatureName: "unit", PriceID: s.testData.price.ID, MeterID: "meter_sum", AggregationType: types.AggregationSum, TotalUsage: decimal.NewFromInt(10)},
+		},
+		Prices: map[string]*price.Price{
+			tieredPrice.ID:      tieredPrice,
+			s.testData.price.ID: s.testData.price,
+		},
+		Meters: map[string]*meter.Meter{
+			"meter_sum": {ID: "meter_sum", Aggregation: meter.Aggregation{Type: types.AggregationSum, Field: "tokens"}},
+		},
+	}
+
+	s.Run("not_split_by_default", func() {
+		resp, err := s.service.ToGetUsageAnalyticsResponseDTO(s.GetContext(), data, s.analyticsRequest())
+		s.Require().NoError(err)
+		s.Nil(resp.Items[0].Tiers)
+	})
+
+	s.Run("usage_spanning_three_tiers", func() {
+		req := s.analyticsRequest()
+		req.Expand = []string{"tiers"}
+		resp, err := s.service.ToGetUsageAnalyticsResponseDTO(s.GetContext(), data, req)
+		s.Require().NoError(err)
+		s.Require().Len(resp.Items, 2)
+
+		tiers := resp.Items[0].Tiers
+		s.Require().Len(tiers, 3)
+		// (100 * 0.1) + (900 * 0.05) + (500 * 0.01)
+		s.Equal([]string{"100", "900", "500"}, lo.Map(tiers, func(tier dto.TierCostBreakup, _ int) string { return tier.Quantity.String() }))
+		s.Equal([]string{"10", "45", "5"}, lo.Map(tiers, func(tier dto.TierCostBreakup, _ int) string { return tier.Cost.String() }))
+		s.Equal(&upTo1000, tiers[1].UpTo)
+		s.Nil(tiers[2].UpTo)
+
+		// Prices that aren't tiered have no split
+		s.Nil(resp.Items[1].Tiers)
+	})
+
+	s.Run("bucketed_max_not_split", func() {
+		bucketed := *data
+		bucketed.Meters = map[string]*meter.Meter{
+			"meter_sum": {ID: "meter_sum", Aggregation: meter.Aggregation{Type: types.AggregationMax, Field: "tokens", BucketSize: types.WindowSizeHour}},
+		}
+		req := s.analyticsRequest()
+		req.Expand = []string{"tiers"}
+		resp, err := s.service.ToGetUsageAnalyticsResponseDTO(s.GetContext(), &bucketed, req)
+		s.Require().NoError(err)
+		s.Nil(resp.Items[0].Tiers)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestAggregateAnalyticsByGroupingDelimiterInValues() {
+	groupBy := []string{"properties.org", "properties.team"}
+	analytic := func(org, team string, usage int64) *events.DetailedUsageAnalytic {
+		return &events.DetailedUsageAnalytic{
+			FeatureID:  s.testData.feature.ID,
+			PriceID:    s.testData.price.ID,
+			MeterID:    s.testData.meter.ID,
+			TotalUsage: decimal.NewFromInt(usage),
+			Properties: map[string]string{"org": org, "team": team},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		items    []*events.DetailedUsageAnalytic
+		expected int
+	}{
+		{
+			name:     "delimiter_shifted_between_values",
+			items:    []*events.DetailedUsageAnalytic{analytic("acme|eu", "ops", 1), analytic("acme", "eu|ops", 2)},
+			expected: 2,
+		},
+		{
+			name:     "length_prefix_in_values",
+			items:    []*events.DetailedUsageAnalytic{analytic("3:a|", "b", 1), analytic("3:a", "|b", 2)},
+			expected: 2,
+		},
+		{
+			name:     "same_values_still_grouped",
+			items:    []*events.DetailedUsageAnalytic{analytic("acme|eu", "ops", 1), analytic("acme|eu", "ops", 2)},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			result := s.service.aggregateAnalyticsByGrouping(tt.items, groupBy)
+			s.Len(result, tt.expected)
+
+			total := decimal.Zero
+			for _, item := range result {
+				total = total.Add(item.TotalUsage)
+			}
+			s.True(decimal.NewFromInt(3).Equal(total))
+		})
+	}
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestAggregateAnalyticsByGroupingEventCounts() {
+	window := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	point := func(timestamp time.Time, usage int64, eventCount uint64) events.UsageAnalyticPoint {
+		return events.UsageAnalyticPoint{Timestamp: timestamp, Usage: decimal.NewFromInt(usage), EventCount: eventCount}
+	}
+	// Items of different subscriptions are grouped together, as for a customer segment
+	analytic := func(subscriptionID string, points ...events.UsageAnalyticPoint) *events.DetailedUsageAnalytic {
+		item := &events.DetailedUsageAnalytic{
+			FeatureID:      s.testData.feature.ID,
+			SubscriptionID: subscriptionID,
+			Source:         "api",
+			Properties:     map[string]string{},
+			Points:         points,
+			PointsByWindow: map[types.WindowSize][]events.UsageAnalyticPoint{types.WindowSizeHour: slices.Clone(points)},
+		}
+		for _, p := range points {
+			item.TotalUsage = item.TotalUsage.Add(p.Usage)
+			item.EventCount += p.EventCount
+		}
+		return item
+	}
+
+	// The second item reports the window in another location and the third merges into the
+	// points taken from the second
+	items := []*events.DetailedUsageAnalytic{
+		analytic("sub_a", point(window, 10, 2)),
+		analytic("sub_b", point(window.In(time.FixedZone("IST", 19800)), 20, 3), point(window.Add(time.Hour), 5, 1)),
+		analytic("sub_c", point(window.Add(time.Hour), 7, 4)),
+	}
+
+	result := s.service.aggregateAnalyticsByGrouping(items, []string{"source"})
+	s.Len(result, 1)
+	s.Equal(uint64(10), result[0].EventCount)
+	for _, points := range [][]events.UsageAnalyticPoint{result[0].Points, result[0].PointsByWindow[types.WindowSizeHour]} {
+		s.Len(points, 2)
+		s.True(window.Equal(points[0].Timestamp))
+		s.True(decimal.NewFromInt(30).Equal(points[0].Usage))
+		s.Equal(uint64(5), points[0].EventCount)
+		s.True(window.Add(time.Hour).Equal(points[1].Timestamp))
+		s.True(decimal.NewFromInt(12).Equal(points[1].Usage))
+		s.Equal(uint64(5), points[1].EventCount)
+	}
+
+	// The merged items keep their own points
+	s.Equal(uint64(1), items[1].Points[1].EventCount)
+	s.Equal(uint64(1), items[1].PointsByWindow[types.WindowSizeHour][1].EventCount)
+
+	s.Run("rolled_up_and_calendar_windows", func() {
+		hourly := result[0].PointsByWindow[types.WindowSizeHour]
+
+		daily := s.service.rollUpAnalyticPoints(hourly, types.WindowSizeDay)
+		s.Len(daily, 1)
+		s.Equal(uint64(10), daily[0].EventCount)
+
+		calendar := &types.AccountingCalendar{Type: types.AccountingCalendar445, YearStart: window.AddDate(0, 0, -3)}
+		fiscal := s.service.rollUpAnalyticPointsBy(hourly, calendar.PeriodStart)
+		s.Len(fiscal, 1)
+		s.Equal(uint64(10), fiscal[0].EventCount)
+
+		filled := fillAnalyticPointGaps(hourly, types.WindowSizeHour, nil, window, window.Add(3*time.Hour), types.MeterGapFillModeCarryOver)
+		s.Len(filled, 3)
+		s.Equal([]uint64{5, 5, 0}, lo.Map(filled, func(p events.UsageAnalyticPoint, _ int) uint64 {
+			return p.EventCount
+		}))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestPeriodIDOverride() {
+	// The subscription's first period is closed, the current one started 5 days ago
+	closedPeriodID := uint64(s.testData.subscription.StartDate.Unix() * 1000)
+	currentPeriodID := uint64(s.testData.subscription.CurrentPeriodStart.Unix() * 1000)
+	usageRecord := func(id string, periodID uint64) *dto.IngestUsageRecordRequest {
+		return &dto.IngestUsageRecordRequest{
+			UsageRecordID:      id,
+			ExternalCustomerID: s.testData.customer.ExternalID,
+			MeterID:            s.testData.meter.ID,
+			PeriodStart:        s.testData.now.Add(-24 * time.Hour),
+			PeriodEnd:          s.testData.now,
+			Quantity:           decimal.NewFromInt(500),
+			PeriodID:           periodID,
+		}
+	}
+
+	s.Run("usage_record_placed_into_closed_period", func() {
+		_, err := s.service.IngestUsageRecord(s.GetContext(), usageRecord("usage_record_correction", closedPeriodID))
+		s.NoError(err)
+
+		rows, _, err := s.GetStores().FeatureUsageRepo.GetProcessedEvents(s.GetContext(), &events.GetProcessedEventsParams{})
+		s.NoError(err)
+		s.Require().Len(rows, 1)
+		s.Equal(closedPeriodID, rows[0].PeriodID)
+		s.NotEqual(currentPeriodID, rows[0].PeriodID)
+		// The usage is timestamped in the closed period so its time bounded queries count it
+		s.True(rows[0].Timestamp.Before(s.testData.subscription.CurrentPeriodStart), rows[0].Timestamp)
+		s.False(rows[0].Timestamp.Before(s.testData.subscription.StartDate), rows[0].Timestamp)
+	})
+
+	s.Run("usage_record_with_invalid_period_rejected", func() {
+		for _, periodID := range []uint64{
+			closedPeriodID + 24*60*60*1000,                                            // inside a period, not its start
+			uint64(s.testData.subscription.CurrentPeriodEnd.Unix() * 1000),            // the next period isn't open yet
+			uint64(s.testData.subscription.StartDate.AddDate(0, -1, 0).Unix() * 1000), // before the subscription
+		} {
+			_, err := s.service.IngestUsageRecord(s.GetContext(), usageRecord("usage_record_invalid", periodID))
+			s.Error(err)
+			s.True(ierr.IsValidation(err), "period id %d", periodID)
+		}
+	})
+
+	s.Run("event_period_id_property", func() {
+		s.GetConfig().FeatureUsageTracking.PeriodIDProperty = "period_id"
+		defer func() { s.GetConfig().FeatureUsageTracking.PeriodIDProperty = "" }()
+
+		valid := s.usageEvent("evt_fut_period_override", s.testData.now.Add(-time.Hour), 10)
+		valid.Properties["period_id"] = fmt.Sprint(closedPeriodID)
+		results, err := s.service.prepareProcessedEvents(s.GetContext(), valid)
+		s.NoError(err)
+		s.Require().Len(results, 1)
+		s.Equal(closedPeriodID, results[0].PeriodID)
+		s.Equal(periodIDTimestamp(closedPeriodID), results[0].Timestamp)
+
+		// Events without the property are billed to the period of their timestamp
+		defaultEvent := s.usageEvent("evt_fut_period_default", s.testData.now.Add(-time.Hour), 10)
+		results, err = s.service.prepareProcessedEvents(s.GetContext(), defaultEvent)
+		s.NoError(err)
+		s.Require().Len(results, 1)
+		s.Equal(currentPeriodID, results[0].PeriodID)
+		s.Equal(defaultEvent.Timestamp, results[0].Timestamp)
+
+		invalid := s.usageEvent("evt_fut_period_invalid", s.testData.now.Add(-time.Hour), 10)
+		invalid.Properties["period_id"] = float64(closedPeriodID + 1000)
+		skips := &unbilledEventTracker{}
+		results, err = s.service.prepareFeatureUsage(s.GetContext(), invalid, nil, skips)
+		s.NoError(err)
+		s.Empty(results)
+		s.Equal(types.UnbilledEventReasonInvalidPeriodID, skips.reason)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestIngestUsageRecord() {
+	periodStart := s.testData.now.Add(-24 * time.Hour)
+	usageRecord := func(id string) *dto.IngestUsageRecordRequest {
+		return &dto.IngestUsageRecordRequest{
+			UsageRecordID:      id,
+			ExternalCustomerID: s.testData.customer.ExternalID,
+			MeterID:            s.testData.meter.ID,
+			PeriodStart:        periodStart,
+			PeriodEnd:          periodStart.Add(24 * time.Hour),
+			Quantity:           decimal.NewFromInt(1500),
+		}
+	}
+	processedEvents := func() []*events.FeatureUsage {
+		rows, _, err := s.GetStores().FeatureUsageRepo.GetProcessedEvents(s.GetContext(), &events.GetProcessedEventsParams{})
+		s.NoError(err)
+		return rows
+	}
+
+	s.Run("writes_one_feature_usage_row", func() {
+		resp, err := s.service.IngestUsageRecord(s.GetContext(), usageRecord("usage_record_1"))
+		s.NoError(err)
+		s.Equal("usage_record_1", resp.UsageRecordID)
+		s.Equal([]string{s.testData.subscription.ID}, resp.SubscriptionIDs)
+
+		rows := processedEvents()
+		s.Len(rows, 1)
+		s.Equal("usage_record_1", rows[0].ID)
+		s.Equal(s.testData.subscription.ID, rows[0].SubscriptionID)
+		s.Equal(s.testData.lineItem.ID, rows[0].SubLineItemID)
+		s.Equal(s.testData.price.ID, rows[0].PriceID)
+		s.Equal(s.testData.feature.ID, rows[0].FeatureID)
+		s.Equal(uint64(s.testData.subscription.CurrentPeriodStart.Unix()*1000), rows[0].PeriodID)
+		s.True(decimal.NewFromInt(1500).Equal(rows[0].QtyTotal))
+		s.True(periodStart.Equal(rows[0].Timestamp))
+	})
+
+	s.Run("resolves_meter_from_feature", func() {
+		req := usageRecord("usage_record_1")
+		req.MeterID = ""
+		req.FeatureID = s.testData.feature.ID
+		req.Quantity = decimal.NewFromInt(2000)
+
+		_, err := s.service.IngestUsageRecord(s.GetContext(), req)
+		s.NoError(err)
+
+		// Re-sending the usage record replaces it
+		rows := processedEvents()
+		s.Len(rows, 1)
+		s.True(decimal.NewFromInt(2000).Equal(rows[0].QtyTotal))
+	})
+
+	s.Run("fails_without_subscription_for_period", func() {
+		req := usageRecord("usage_record_2")
+		req.PeriodStart = s.testData.subscription.StartDate.Add(-48 * time.Hour)
+		req.PeriodEnd = req.PeriodStart.Add(24 * time.Hour)
+
+		_, err := s.service.IngestUsageRecord(s.GetContext(), req)
+		s.Error(err)
+		s.True(ierr.IsNotFound(err))
+	})
+
+	s.Run("rejects_invalid_usage_records", func() {
+		noMeter := usageRecord("usage_record_3")
+		noMeter.MeterID = ""
+
+		invalidPeriod := usageRecord("usage_record_3")
+		invalidPeriod.PeriodEnd = invalidPeriod.PeriodStart
+
+		negative := usageRecord("usage_record_3")
+		negative.Quantity = decimal.NewFromInt(-1)
+
+		for _, req := range []*dto.IngestUsageRecordRequest{noMeter, invalidPeriod, negative} {
+			_, err := s.service.IngestUsageRecord(s.GetContext(), req)
+			s.Error(err)
+			s.True(ierr.IsValidation(err))
+		}
+		s.Len(processedEvents(), 1)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetProjectedInvoice() {
+	ctx := s.GetContext()
+
+	platformFee := &price.Price{
+		ID:                 "price_fut_platform_fee",
+		Amount:             decimal.NewFromInt(20),
+		Currency:           "usd",
+		EntityType:         types.PRICE_ENTITY_TYPE_PLAN,
+		EntityID:           s.testData.plan.ID,
+		Type:               types.PRICE_TYPE_FIXED,
+		BillingPeriod:      types.BILLING_PERIOD_MONTHLY,
+		BillingPeriodCount: 1,
+		BillingModel:       types.BILLING_MODEL_FLAT_FEE,
+		BillingCadence:     types.BILLING_CADENCE_RECURRING,
+		InvoiceCadence:     types.InvoiceCadenceAdvance,
+		BaseModel:          types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().PriceRepo.Create(ctx, platformFee))
+
+	// Halfway through a 10 day billing period
+	sub := *s.testData.subscription
+	sub.ID = "sub_fut_projected"
+	sub.CurrentPeriodStart = s.testData.now.Add(-5 * 24 * time.Hour)
+	sub.CurrentPeriodEnd = s.testData.now.Add(5 * 24 * time.Hour)
+
+	usageLineItem := *s.testData.lineItem
+	usageLineItem.ID = "subli_fut_projected_tokens"
+	usageLineItem.SubscriptionID = sub.ID
+
+	feeLineItem := &subscription.SubscriptionLineItem{
+		ID:             "subli_fut_projected_fee",
+		SubscriptionID: sub.ID,
+		CustomerID:     s.testData.customer.ID,
+		EntityID:       s.testData.plan.ID,
+		EntityType:     types.SubscriptionLineItemEntityTypePlan,
+		PriceID:        platformFee.ID,
+		PriceType:      platformFee.Type,
+		DisplayName:    "Platform Fee",
+		Quantity:       decimal.NewFromInt(1),
+		Currency:       "usd",
+		BillingPeriod:  types.BILLING_PERIOD_MONTHLY,
+		InvoiceCadence: types.InvoiceCadenceAdvance,
+		StartDate:      sub.StartDate,
+		BaseModel:      types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().SubscriptionRepo.CreateWithLineItems(ctx, &sub, []*subscription.SubscriptionLineItem{&usageLineItem, feeLineItem}))
+
+	s.testData.subscription = &sub
+	s.testData.lineItem = &usageLineItem
+	s.recordUsage("evt_fut_1", s.testData.now.Add(-48*time.Hour), 40)
+	s.recordUsage("evt_fut_2", s.testData.now.Add(-24*time.Hour), 60)
+
+	preview, err := s.service.GetProjectedInvoice(ctx, sub.ID)
+	s.NoError(err)
+	s.Equal(sub.ID, preview.SubscriptionID)
+	s.Equal("usd", preview.Currency)
+	s.Len(preview.LineItems, 2)
+
+	lineItems := lo.KeyBy(preview.LineItems, func(item dto.ProjectedInvoiceLineItem) string { return item.SubLineItemID })
+
+	// 100 tokens at 0.50 so far, doubling by the end of the period
+	usage := lineItems[usageLineItem.ID]
+	s.Equal(types.PRICE_TYPE_USAGE, usage.PriceType)
+	s.Equal(s.testData.feature.ID, usage.FeatureID)
+	s.True(decimal.NewFromInt(100).Equal(usage.Quantity), "quantity: %s", usage.Quantity)
+	s.True(decimal.NewFromInt(50).Equal(usage.Amount), "amount: %s", usage.Amount)
+	s.InDelta(200, usage.ProjectedQuantity.InexactFloat64(), 0.01)
+	s.True(decimal.NewFromInt(100).Equal(usage.ProjectedAmount), "projected amount: %s", usage.ProjectedAmount)
+
+	fee := lineItems[feeLineItem.ID]
+	s.Equal(types.PRICE_TYPE_FIXED, fee.PriceType)
+	s.True(decimal.NewFromInt(1).Equal(fee.Quantity))
+	s.True(decimal.NewFromInt(20).Equal(fee.Amount))
+	s.True(decimal.NewFromInt(20).Equal(fee.ProjectedAmount))
+
+	s.True(decimal.NewFromInt(70).Equal(preview.Amount), "amount: %s", preview.Amount)
+	s.True(decimal.NewFromInt(120).Equal(preview.ProjectedTotal), "projected total: %s", preview.ProjectedTotal)
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestConvertValueToDecimalOutOfRange() {
+	event := s.usageEvent("evt_fut_range", s.testData.now.Add(-time.Hour), 0)
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected decimal.Decimal
+	}{
+		{
+			name:     "largest_storable_value",
+			value:    "9999999999.999999999999999",
+			expected: decimal.RequireFromString("9999999999.999999999999999"),
+		},
+		{
+			name:     "uint64_above_range",
+			value:    uint64(math.MaxUint64),
+			expected: decimal.Zero,
+		},
+		{
+			name:     "string_above_range",
+			value:    "10000000000",
+			expected: decimal.Zero,
+		},
+		{
+			name:     "negative_string_below_range",
+			value:    "-1e12",
+			expected: decimal.Zero,
+		},
+		{
+			name:     "float_above_range",
+			value:    float64(1e20),
+			expected: decimal.Zero,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			value, _ := s.service.convertValueToDecimal(tt.value, event, s.testData.meter)
+			s.True(tt.expected.Equal(value), "value: %s", value)
+		})
+	}
+
+	s.Run("over_range_event_records_zero_quantity", func() {
+		event := s.usageEvent("evt_fut_range_over", s.testData.now.Add(-time.Hour), 0)
+		event.Properties["tokens"] = uint64(math.MaxUint64)
+
+		results, err := s.service.prepareProcessedEvents(s.GetContext(), event)
+		s.NoError(err)
+		s.Len(results, 1)
+		s.True(results[0].QtyTotal.IsZero())
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestSumWithMultiplierRateTable() {
+	s.testData.meter.Aggregation = meter.Aggregation{
+		Type:         types.AggregationSumWithMultiplier,
+		Field:        "tokens",
+		Multiplier:   lo.ToPtr(decimal.NewFromInt(1)),
+		RateProperty: "model",
+		RateTable: map[string]decimal.Decimal{
+			"large": decimal.NewFromInt(30),
+			"small": decimal.NewFromFloat(0.5),
+		},
+	}
+	s.NoError(s.testData.meter.Validate())
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+	s.NoError(meterStore.InMemoryStore.Update(s.GetContext(), s.testData.meter.ID, s.testData.meter))
+
+	tests := []struct {
+		name     string
+		model    interface{}
+		expected decimal.Decimal
+	}{
+		{name: "large_model_rate", model: "large", expected: decimal.NewFromInt(300)},
+		{name: "small_model_rate", model: "small", expected: decimal.NewFromInt(5)},
+		{name: "unknown_model_uses_static_multiplier", model: "medium", expected: decimal.NewFromInt(10)},
+		{name: "missing_property_uses_static_multiplier", expected: decimal.NewFromInt(10)},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			event := s.usageEvent("evt_fut_rate_"+tt.name, s.testData.now.Add(-time.Hour), 10)
+			if tt.model != nil {
+				event.Properties["model"] = tt.model
+			}
+
+			results, err := s.service.prepareProcessedEvents(s.GetContext(), event)
+			s.NoError(err)
+			s.Len(results, 1)
+			s.True(tt.expected.Equal(results[0].QtyTotal), "quantity: %s", results[0].QtyTotal)
+		})
+	}
+
+	s.Run("invalid_rate_tables", func() {
+		withoutProperty := *s.testData.meter
+		withoutProperty.Aggregation.RateProperty = ""
+		s.True(ierr.IsValidation(withoutProperty.Validate()))
+
+		zeroRate := *s.testData.meter
+		zeroRate.Aggregation.RateTable = map[string]decimal.Decimal{"large": decimal.Zero}
+		s.True(ierr.IsValidation(zeroRate.Validate()))
+
+		sumMeter := *s.testData.meter
+		sumMeter.Aggregation.Type = types.AggregationSum
+		s.True(ierr.IsValidation(sumMeter.Validate()))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestMinEventValue() {
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+	setAggregation := func(aggregation meter.Aggregation) {
+		s.testData.meter.Aggregation = aggregation
+		s.Require().NoError(s.testData.meter.Validate())
+		s.Require().NoError(meterStore.InMemoryStore.Update(s.GetContext(), s.testData.meter.ID, s.testData.meter))
+	}
+
+	tests := []struct {
+		name        string
+		aggregation meter.Aggregation
+		tokens      int64
+		expected    decimal.Decimal
+	}{
+		{
+			name:        "below_floor_is_raised",
+			aggregation: meter.Aggregation{Type: types.AggregationSum, Field: "tokens", MinEventValue: lo.ToPtr(decimal.NewFromInt(60))},
+			tokens:      15,
+			expected:    decimal.NewFromInt(60),
+		},
+		{
+			name:        "zero_is_raised",
+			aggregation: meter.Aggregation{Type: types.AggregationSum, Field: "tokens", MinEventValue: lo.ToPtr(decimal.NewFromInt(60))},
+			tokens:      0,
+			expected:    decimal.NewFromInt(60),
+		},
+		{
+			name:        "above_floor_is_kept",
+			aggregation: meter.Aggregation{Type: types.AggregationSum, Field: "tokens", MinEventValue: lo.ToPtr(decimal.NewFromInt(60))},
+			tokens:      90,
+			expected:    decimal.NewFromInt(90),
+		},
+		{
+			name: "floor_applies_before_multiplier",
+			aggregation: meter.Aggregation{
+				Type:          types.AggregationSumWithMultiplier,
+				Field:         "tokens",
+				Multiplier:    lo.ToPtr(decimal.NewFromInt(2)),
+				MinEventValue: lo.ToPtr(decimal.NewFromInt(60)),
+			},
+			tokens:   15,
+			expected: decimal.NewFromInt(120),
+		},
+		{
+			name:        "without_floor_value_is_counted_as_is",
+			aggregation: meter.Aggregation{Type: types.AggregationSum, Field: "tokens"},
+			tokens:      15,
+			expected:    decimal.NewFromInt(15),
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			setAggregation(tt.aggregation)
+
+			results, err := s.service.prepareProcessedEvents(s.GetContext(), s.usageEvent("evt_fut_floor_"+tt.name, s.testData.now.Add(-time.Hour), tt.tokens))
+			s.NoError(err)
+			s.Require().Len(results, 1)
+			s.True(tt.expected.Equal(results[0].QtyTotal), "quantity: %s", results[0].QtyTotal)
+		})
+	}
+
+	s.Run("invalid_min_event_values", func() {
+		m := *s.testData.meter
+		m.Aggregation = meter.Aggregation{Type: types.AggregationSum, Field: "tokens", MinEventValue: lo.ToPtr(decimal.Zero)}
+		s.True(ierr.IsValidation(m.Validate()))
+
+		m.Aggregation = meter.Aggregation{Type: types.AggregationCount, MinEventValue: lo.ToPtr(decimal.NewFromInt(1))}
+		s.True(ierr.IsValidation(m.Validate()))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestMaxEventValue() {
+	ctx := s.GetContext()
+	defer func() { s.GetConfig().FeatureUsageTracking.MaxEventValuePolicy = "" }()
+	recorder := metrics.NewInMemoryRecorder()
+	s.service.metrics = recorder
+	tenantID := types.GetTenantID(ctx)
+
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+	s.testData.meter.Aggregation = meter.Aggregation{
+		Type:          types.AggregationSumWithMultiplier,
+		Field:         "tokens",
+		Multiplier:    lo.ToPtr(decimal.NewFromInt(2)),
+		MaxEventValue: lo.ToPtr(decimal.NewFromInt(100)),
+	}
+	s.Require().NoError(s.testData.meter.Validate())
+	s.Require().NoError(meterStore.InMemoryStore.Update(ctx, s.testData.meter.ID, s.testData.meter))
+
+	s.Run("below_ceiling_is_kept", func() {
+		results, err := s.service.prepareProcessedEvents(ctx, s.usageEvent("evt_fut_ceiling_below", s.testData.now.Add(-time.Hour), 100))
+		s.NoError(err)
+		s.Require().Len(results, 1)
+		s.True(decimal.NewFromInt(200).Equal(results[0].QtyTotal), "quantity: %s", results[0].QtyTotal)
+		s.Zero(recorder.Count(metrics.MetricEventValueAnomaly, tenantID))
+	})
+
+	s.Run("rejected_by_default", func() {
+		event := s.usageEvent("evt_fut_ceiling_reject", s.testData.now.Add(-time.Hour), 1000000)
+		results, err := s.service.prepareProcessedEvents(ctx, event)
+		s.NoError(err)
+		s.Empty(results)
+		s.Equal(int64(1), recorder.Count(metrics.MetricEventValueAnomaly, tenantID))
+
+		// Explaining why the event produced no usage doesn't count the anomaly again
+		skips := &unbilledEventTracker{}
+		results, err = s.service.prepareFeatureUsage(ctx, event, nil, skips)
+		s.NoError(err)
+		s.Empty(results)
+		s.Equal(types.UnbilledEventReasonAboveMaxEventValue, skips.reason)
+		s.Equal(int64(1), recorder.Count(metrics.MetricEventValueAnomaly, tenantID))
+	})
+
+	s.Run("clamped_before_multiplier", func() {
+		s.GetConfig().FeatureUsageTracking.MaxEventValuePolicy = types.MaxEventValuePolicyClamp
+		results, err := s.service.prepareProcessedEvents(ctx, s.usageEvent("evt_fut_ceiling_clamp", s.testData.now.Add(-time.Hour), 1000000))
+		s.NoError(err)
+		s.Require().Len(results, 1)
+		s.True(decimal.NewFromInt(200).Equal(results[0].QtyTotal), "quantity: %s", results[0].QtyTotal)
+		s.Equal(int64(2), recorder.Count(metrics.MetricEventValueAnomaly, tenantID))
+	})
+
+	s.Run("invalid_max_event_values", func() {
+		m := *s.testData.meter
+		m.Aggregation = meter.Aggregation{Type: types.AggregationSum, Field: "tokens", MaxEventValue: lo.ToPtr(decimal.Zero)}
+		s.True(ierr.IsValidation(m.Validate()))
+
+		m.Aggregation = meter.Aggregation{Type: types.AggregationCount, MaxEventValue: lo.ToPtr(decimal.NewFromInt(1))}
+		s.True(ierr.IsValidation(m.Validate()))
+
+		m.Aggregation = meter.Aggregation{
+			Type:          types.AggregationSum,
+			Field:         "tokens",
+			MinEventValue: lo.ToPtr(decimal.NewFromInt(60)),
+			MaxEventValue: lo.ToPtr(decimal.NewFromInt(30)),
+		}
+		s.True(ierr.IsValidation(m.Validate()))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestUnknownAggregationPolicy() {
+	ctx := s.GetContext()
+	previous := s.GetConfig().FeatureUsageTracking.UnknownAggregationPolicy
+	defer func() { s.GetConfig().FeatureUsageTracking.UnknownAggregationPolicy = previous }()
+
+	// A type that was added without being wired into usage handling
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+	s.testData.meter.Aggregation = meter.Aggregation{Type: types.AggregationType("UNWIRED"), Field: "tokens"}
+	s.Require().NoError(meterStore.InMemoryStore.Update(ctx, s.testData.meter.ID, s.testData.meter))
+	s.recordUsage("evt_fut_unknown_agg", s.testData.now.Add(-2*time.Hour), 30)
+
+	s.Run("sum_policy_extracts_as_sum", func() {
+		s.GetConfig().FeatureUsageTracking.UnknownAggregationPolicy = types.UnknownAggregationPolicySum
+
+		results, err := s.service.prepareProcessedEvents(ctx, s.usageEvent("evt_fut_unknown_sum", s.testData.now.Add(-time.Hour), 15))
+		s.NoError(err)
+		s.Require().Len(results, 1)
+		s.True(decimal.NewFromInt(15).Equal(results[0].QtyTotal), "quantity: %s", results[0].QtyTotal)
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, s.analyticsRequest())
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.True(decimal.NewFromInt(30).Equal(resp.Items[0].TotalUsage))
+	})
+
+	s.Run("error_policy_fails_processing_and_analytics", func() {
+		s.GetConfig().FeatureUsageTracking.UnknownAggregationPolicy = types.UnknownAggregationPolicyError
+
+		_, err := s.service.prepareProcessedEvents(ctx, s.usageEvent("evt_fut_unknown_error", s.testData.now.Add(-time.Hour), 15))
+		s.Error(err)
+		s.True(ierr.IsInternal(err))
+
+		_, err = s.service.GetDetailedUsageAnalytics(ctx, s.analyticsRequest())
+		s.Error(err)
+	})
+
+	s.Run("error_policy_keeps_known_types", func() {
+		s.GetConfig().FeatureUsageTracking.UnknownAggregationPolicy = types.UnknownAggregationPolicyError
+		s.testData.meter.Aggregation = meter.Aggregation{Type: types.AggregationSum, Field: "tokens"}
+		s.Require().NoError(meterStore.InMemoryStore.Update(ctx, s.testData.meter.ID, s.testData.meter))
+
+		results, err := s.service.prepareProcessedEvents(ctx, s.usageEvent("evt_fut_known_error", s.testData.now.Add(-time.Hour), 15))
+		s.NoError(err)
+		s.Require().Len(results, 1)
+		s.True(decimal.NewFromInt(15).Equal(results[0].QtyTotal))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestConvertPropertyWithUnit() {
+	event := s.usageEvent("evt_fut_unit", s.testData.now.Add(-time.Hour), 0)
+
+	tests := []struct {
+		name     string
+		unit     types.ValueUnit
+		value    interface{}
+		expected decimal.Decimal
+	}{
+		{
+			name:     "data_size_suffix",
+			unit:     types.ValueUnitMegabyte,
+			value:    "1.5GB",
+			expected: decimal.NewFromInt(1500),
+		},
+		{
+			name:     "duration_suffix",
+			unit:     types.ValueUnitSecond,
+			value:    "500ms",
+			expected: decimal.RequireFromString("0.5"),
+		},
+		{
+			name:     "numbers_are_in_the_meter_unit",
+			unit:     types.ValueUnitMegabyte,
+			value:    float64(12),
+			expected: decimal.NewFromInt(12),
+		},
+		{
+			name:     "mismatched_suffix_is_skipped",
+			unit:     types.ValueUnitSecond,
+			value:    "1.5GB",
+			expected: decimal.Zero,
+		},
+		{
+			name:     "suffix_without_unit_is_skipped",
+			value:    "1.5GB",
+			expected: decimal.Zero,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			m := *s.testData.meter
+			m.Aggregation.Unit = tt.unit
+
+			value, stringValue := s.service.convertPropertyToDecimal(tt.value, event, &m)
+			s.True(tt.expected.Equal(value), "value: %s", value)
+			if str, ok := tt.value.(string); ok {
+				s.Equal(str, stringValue)
+			}
+		})
+	}
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetPlatformUsage() {
+	ctx := s.GetContext()
+	s.recordUsage("evt_fut_platform_1", s.testData.now.Add(-3*time.Hour), 10)
+	s.recordUsage("evt_fut_platform_2", s.testData.now.Add(-2*time.Hour), 20)
+
+	otherUsage := func(id, tenantID, customerID, meterID string, timestamp time.Time, qty int64, sign int8) {
+		s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(ctx, &events.FeatureUsage{
+			Event: events.Event{
+				ID:            id,
+				TenantID:      tenantID,
+				EnvironmentID: "env_platform_other",
+				CustomerID:    customerID,
+				Timestamp:     timestamp,
+			},
+			MeterID:  meterID,
+			QtyTotal: decimal.NewFromInt(qty),
+			Sign:     sign,
+		}))
+	}
+	// Another tenant with two customers on the same meter
+	otherUsage("evt_fut_platform_3", "tenant_platform_other", "cust_platform_a", "meter_platform_other", s.testData.now.Add(-time.Hour), 5, 1)
+	otherUsage("evt_fut_platform_4", "tenant_platform_other", "cust_platform_b", "meter_platform_other", s.testData.now.Add(-time.Hour), 7, 1)
+	// Deleted usage and usage outside the window are left out
+	otherUsage("evt_fut_platform_5", "tenant_platform_other", "cust_platform_a", "meter_platform_other", s.testData.now.Add(-time.Hour), 100, 0)
+	otherUsage("evt_fut_platform_6", "tenant_platform_other", "cust_platform_a", "meter_platform_other", s.testData.now.Add(-48*time.Hour), 100, 1)
+
+	s.Run("totals_per_meter_across_tenants", func() {
+		resp, err := s.service.GetPlatformUsage(ctx, &dto.GetPlatformUsageRequest{
+			StartTime: s.testData.now.Add(-24 * time.Hour),
+			EndTime:   s.testData.now,
+		})
+		s.NoError(err)
+		s.Equal(uint64(4), resp.TotalEvents)
+		s.Require().Len(resp.Meters, 2)
+
+		totals := lo.KeyBy(resp.Meters, func(total *events.PlatformMeterUsage) string { return total.MeterID })
+
+		own := totals[s.testData.meter.ID]
+		s.Require().NotNil(own)
+		s.Equal(types.GetTenantID(ctx), own.TenantID)
+		s.True(decimal.NewFromInt(30).Equal(own.TotalUsage))
+		s.Equal(uint64(2), own.EventCount)
+		s.Equal(uint64(1), own.CustomerCount)
+
+		other := totals["meter_platform_other"]
+		s.Require().NotNil(other)
+		s.Equal("tenant_platform_other", other.TenantID)
+		s.True(decimal.NewFromInt(12).Equal(other.TotalUsage))
+		s.Equal(uint64(2), other.EventCount)
+		s.Equal(uint64(2), other.CustomerCount)
+	})
+
+	s.Run("rejects_invalid_window", func() {
+		_, err := s.service.GetPlatformUsage(ctx, &dto.GetPlatformUsageRequest{
+			StartTime: s.testData.now,
+			EndTime:   s.testData.now.Add(-time.Hour),
+		})
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetEventsStatus() {
+	ctx := s.GetContext()
+	s.recordUsage("evt_fut_status_1", s.testData.now.Add(-2*time.Hour), 10)
+	s.recordUsage("evt_fut_status_2", s.testData.now.Add(-time.Hour), 20)
+
+	// Events without usage, deleted usage and usage of another tenant are still processing
+	s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(ctx, &events.FeatureUsage{
+		Event: events.Event{
+			ID:            "evt_fut_status_deleted",
+			TenantID:      types.GetTenantID(ctx),
+			EnvironmentID: types.GetEnvironmentID(ctx),
+			Timestamp:     s.testData.now,
+		},
+		MeterID: s.testData.meter.ID,
+		Sign:    0,
+	}))
+	s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(ctx, &events.FeatureUsage{
+		Event: events.Event{
+			ID:            "evt_fut_status_other_tenant",
+			TenantID:      "tenant_status_other",
+			EnvironmentID: types.GetEnvironmentID(ctx),
+			Timestamp:     s.testData.now,
+		},
+		MeterID: s.testData.meter.ID,
+		Sign:    1,
+	}))
+
+	// Stored events without usage are processing until the status timeout passed
+	s.GetConfig().FeatureUsageTracking.EventStatusTimeout = time.Hour
+	for _, event := range []*events.Event{
+		{ID: "evt_fut_status_deleted", IngestedAt: time.Now().UTC()},
+		{ID: "evt_fut_status_other_tenant", IngestedAt: time.Now().UTC()},
+		{ID: "evt_fut_status_stale", IngestedAt: time.Now().UTC().Add(-2 * time.Hour)},
+	} {
+		event.TenantID = types.GetTenantID(ctx)
+		event.EnvironmentID = types.GetEnvironmentID(ctx)
+		event.Timestamp = event.IngestedAt
+		s.NoError(s.GetStores().EventRepo.InsertEvent(ctx, event))
+	}
+
+	s.Run("mix_of_processed_and_unprocessed", func() {
+		resp, err := s.service.GetEventsStatus(ctx, []string{
+			"evt_fut_status_2",
+			"evt_fut_status_unknown",
+			"evt_fut_status_1",
+			"evt_fut_status_deleted",
+			"evt_fut_status_other_tenant",
+			"evt_fut_status_stale",
+			"evt_fut_status_1",
+		})
+		s.NoError(err)
+		s.Equal([]dto.EventStatus{
+			{EventID: "evt_fut_status_2", Status: types.EventProcessingStatusProcessed},
+			{EventID: "evt_fut_status_unknown", Status: types.EventProcessingStatusUnknown},
+			{EventID: "evt_fut_status_1", Status: types.EventProcessingStatusProcessed},
+			{EventID: "evt_fut_status_deleted", Status: types.EventProcessingStatusProcessing},
+			{EventID: "evt_fut_status_other_tenant", Status: types.EventProcessingStatusProcessing},
+			{EventID: "evt_fut_status_stale", Status: types.EventProcessingStatusUnprocessed},
+		}, resp.Events)
+	})
+
+	s.Run("without_timeout_stale_events_keep_processing", func() {
+		s.GetConfig().FeatureUsageTracking.EventStatusTimeout = 0
+		defer func() { s.GetConfig().FeatureUsageTracking.EventStatusTimeout = time.Hour }()
+
+		resp, err := s.service.GetEventsStatus(ctx, []string{"evt_fut_status_stale"})
+		s.NoError(err)
+		s.Equal([]dto.EventStatus{
+			{EventID: "evt_fut_status_stale", Status: types.EventProcessingStatusProcessing},
+		}, resp.Events)
+	})
+
+	s.Run("no_event_ids", func() {
+		resp, err := s.service.GetEventsStatus(ctx, nil)
+		s.NoError(err)
+		s.Empty(resp.Events)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetUnbilledEvents() {
+	ctx := s.GetContext()
+	insertEvent := func(event *events.Event) {
+		s.NoError(s.GetStores().EventRepo.InsertEvent(ctx, event))
+	}
+
+	// Billed usage is left out, events without usage are listed most recent first
+	s.recordUsage("evt_fut_unbilled_billed", s.testData.now.Add(-4*time.Hour), 10)
+	insertEvent(s.usageEvent("evt_fut_unbilled_pending", s.testData.now.Add(-3*time.Hour), 10))
+	unknownEvent := s.usageEvent("evt_fut_unbilled_unknown_name", s.testData.now.Add(-2*time.Hour), 10)
+	unknownEvent.EventName = "unknown_event"
+	insertEvent(unknownEvent)
+	insertEvent(s.usageEvent("evt_fut_unbilled_before_sub", s.testData.now.Add(-40*24*time.Hour), 10))
+
+	unknownCustomerEvent := s.usageEvent("evt_fut_unbilled_unknown_customer", s.testData.now.Add(-time.Hour), 10)
+	unknownCustomerEvent.ExternalCustomerID = "cust_fut_unbilled_unknown"
+	insertEvent(unknownCustomerEvent)
+
+	request := func(externalCustomerID string, limit int) *dto.GetUnbilledEventsRequest {
+		return &dto.GetUnbilledEventsRequest{
+			ExternalCustomerID: externalCustomerID,
+			StartTime:          s.testData.now.Add(-60 * 24 * time.Hour),
+			EndTime:            s.testData.now,
+			Limit:              limit,
+		}
+	}
+	reasons := func(resp *dto.GetUnbilledEventsResponse) map[string]types.UnbilledEventReason {
+		return lo.SliceToMap(resp.Events, func(event dto.UnbilledEvent) (string, types.UnbilledEventReason) {
+			return event.EventID, event.Reason
+		})
+	}
+
+	s.Run("mix_of_reasons", func() {
+		resp, err := s.service.GetUnbilledEvents(ctx, request(s.testData.customer.ExternalID, 0))
+		s.NoError(err)
+		s.Equal([]string{
+			"evt_fut_unbilled_unknown_name",
+			"evt_fut_unbilled_pending",
+			"evt_fut_unbilled_before_sub",
+		}, lo.Map(resp.Events, func(event dto.UnbilledEvent, _ int) string { return event.EventID }))
+		s.Equal(map[string]types.UnbilledEventReason{
+			"evt_fut_unbilled_unknown_name": types.UnbilledEventReasonNoMatchingMeter,
+			"evt_fut_unbilled_pending":      types.UnbilledEventReasonPending,
+			"evt_fut_unbilled_before_sub":   types.UnbilledEventReasonOutsideSubscription,
+		}, reasons(resp))
+	})
+
+	s.Run("limit", func() {
+		resp, err := s.service.GetUnbilledEvents(ctx, request(s.testData.customer.ExternalID, 1))
+		s.NoError(err)
+		s.Len(resp.Events, 1)
+		s.Equal("evt_fut_unbilled_unknown_name", resp.Events[0].EventID)
+		s.True(resp.HasMore)
+		s.NotEmpty(resp.IterLastKey)
+
+		// The next request continues after the last event checked
+		var eventIDs []string
+		for resp.HasMore {
+			req := request(s.testData.customer.ExternalID, 1)
+			req.IterLastKey = resp.IterLastKey
+			resp, err = s.service.GetUnbilledEvents(ctx, req)
+			s.Require().NoError(err)
+			eventIDs = append(eventIDs, lo.Map(resp.Events, func(event dto.UnbilledEvent, _ int) string { return event.EventID })...)
+		}
+		s.Equal([]string{"evt_fut_unbilled_pending", "evt_fut_unbilled_before_sub"}, eventIDs)
+		s.Empty(resp.IterLastKey)
+	})
+
+	s.Run("invalid_iter_last_key", func() {
+		req := request(s.testData.customer.ExternalID, 0)
+		req.IterLastKey = "not_a_key"
+		_, err := s.service.GetUnbilledEvents(ctx, req)
+		s.True(ierr.IsValidation(err))
+	})
+
+	s.Run("customer_not_found", func() {
+		resp, err := s.service.GetUnbilledEvents(ctx, request("cust_fut_unbilled_unknown", 0))
+		s.NoError(err)
+		s.Equal(map[string]types.UnbilledEventReason{
+			"evt_fut_unbilled_unknown_customer": types.UnbilledEventReasonCustomerNotFound,
+		}, reasons(resp))
+	})
+
+	s.Run("usage_frozen", func() {
+		s.testData.subscription.UsageFrozen = true
+		s.NoError(s.GetStores().SubscriptionRepo.Update(ctx, s.testData.subscription))
+
+		resp, err := s.service.GetUnbilledEvents(ctx, request(s.testData.customer.ExternalID, 0))
+		s.NoError(err)
+		s.Equal(types.UnbilledEventReasonUsageFrozen, reasons(resp)["evt_fut_unbilled_pending"])
+	})
+
+	s.Run("invalid_window", func() {
+		req := request(s.testData.customer.ExternalID, 0)
+		req.EndTime = req.StartTime
+		_, err := s.service.GetUnbilledEvents(ctx, req)
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetHuggingFaceBillingDataLookupLimit() {
+	ctx := s.GetContext()
+	defer func() { s.GetConfig().FeatureUsageTracking.EventUsageLookupLimit = 0 }()
+
+	s.recordUsage("evt_fut_hf_3", s.testData.now.Add(-3*time.Hour), 30)
+	s.recordUsage("evt_fut_hf_1", s.testData.now.Add(-2*time.Hour), 10)
+	s.recordUsage("evt_fut_hf_2", s.testData.now.Add(-time.Hour), 20)
+	req := &dto.GetHuggingFaceBillingDataRequest{
+		EventIDs: []string{"evt_fut_hf_1", "evt_fut_hf_2", "evt_fut_hf_3"},
+	}
+
+	s.Run("all_rows_without_limit", func() {
+		resp, err := s.service.GetHuggingFaceBillingData(ctx, req)
+		s.NoError(err)
+		s.Len(resp.Data, 3)
+	})
+
+	s.Run("events_within_the_limit_are_not_truncated", func() {
+		s.GetConfig().FeatureUsageTracking.EventUsageLookupLimit = 1
+
+		resp, err := s.service.GetHuggingFaceBillingData(ctx, req)
+		s.NoError(err)
+		s.Len(resp.Data, 3)
+		s.False(resp.Truncated)
+		s.Equal([]string{"evt_fut_hf_1", "evt_fut_hf_2", "evt_fut_hf_3"}, lo.Map(resp.Data, func(info dto.EventCostInfo, _ int) string {
+			return info.EventID
+		}))
+
+		// 20 tokens at 0.50 in nano USD
+		s.True(decimal.NewFromInt(10_000_000_000).Equal(resp.Data[1].CostInNanoUSD), "cost: %s", resp.Data[1].CostInNanoUSD)
+	})
+
+	s.Run("capped_rows_per_event", func() {
+		// evt_fut_hf_1 is matched by two more line items
+		for _, subLineItemID := range []string{"li_fut_hf_extra_1", "li_fut_hf_extra_2"} {
+			s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(ctx, &events.FeatureUsage{
+				Event: events.Event{
+					ID:            "evt_fut_hf_1",
+					TenantID:      types.GetTenantID(ctx),
+					EnvironmentID: types.GetEnvironmentID(ctx),
+					Timestamp:     s.testData.now.Add(-2 * time.Hour),
+				},
+				SubscriptionID: s.testData.subscription.ID,
+				SubLineItemID:  subLineItemID,
+				PriceID:        s.testData.price.ID,
+				MeterID:        s.testData.meter.ID,
+				FeatureID:      s.testData.feature.ID,
+				QtyTotal:       decimal.NewFromInt(10),
+				Sign:           1,
+			}))
+		}
+		s.GetConfig().FeatureUsageTracking.EventUsageLookupLimit = 2
+
+		resp, err := s.service.GetHuggingFaceBillingData(ctx, req)
+		s.NoError(err)
+		s.True(resp.Truncated)
+		// The other events keep their records
+		s.Equal([]string{"evt_fut_hf_1", "evt_fut_hf_1", "evt_fut_hf_2", "evt_fut_hf_3"}, lo.Map(resp.Data, func(info dto.EventCostInfo, _ int) string {
+			return info.EventID
+		}))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetHuggingFaceBillingDataCurrency() {
+	ctx := s.GetContext()
+
+	eurPrice := *s.testData.price
+	eurPrice.ID = "price_fut_tokens_eur"
+	eurPrice.Currency = "eur"
+	eurPrice.Amount = decimal.NewFromFloat(0.25)
+	s.NoError(s.GetStores().PriceRepo.Create(ctx, &eurPrice))
+
+	s.recordUsage("evt_fut_hf_usd", s.testData.now.Add(-2*time.Hour), 20)
+	event := &events.Event{
+		ID:                 "evt_fut_hf_eur",
+		TenantID:           types.GetTenantID(ctx),
+		EnvironmentID:      types.GetEnvironmentID(ctx),
+		EventName:          s.testData.meter.EventName,
+		ExternalCustomerID: s.testData.customer.ExternalID,
+		CustomerID:         s.testData.customer.ID,
+		Timestamp:          s.testData.now.Add(-time.Hour),
+	}
+	s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(ctx, &events.FeatureUsage{
+		Event:          *event,
+		SubscriptionID: s.testData.subscription.ID,
+		SubLineItemID:  s.testData.lineItem.ID,
+		PriceID:        eurPrice.ID,
+		MeterID:        s.testData.meter.ID,
+		FeatureID:      s.testData.feature.ID,
+		UniqueHash:     event.ID,
+		QtyTotal:       decimal.NewFromInt(20),
+		Sign:           1,
+	}))
+
+	costs := func() map[string]dto.EventCostInfo {
+		resp, err := s.service.GetHuggingFaceBillingData(ctx, &dto.GetHuggingFaceBillingDataRequest{
+			EventIDs: []string{"evt_fut_hf_usd", "evt_fut_hf_eur"},
+		})
+		s.Require().NoError(err)
+		s.Require().Len(resp.Data, 2)
+		return lo.KeyBy(resp.Data, func(info dto.EventCostInfo) string {
+			return info.EventID
+		})
+	}
+
+	s.Run("non_usd_cost_without_rate_is_flagged", func() {
+		costs := costs()
+
+		// 20 tokens at 0.50 USD
+		usd := costs["evt_fut_hf_usd"]
+		s.Equal("usd", usd.Currency)
+		s.True(decimal.NewFromInt(10_000_000_000).Equal(usd.CostInNanoUSD), "cost: %s", usd.CostInNanoUSD)
+		s.True(usd.CostInNano.Equal(usd.CostInNanoUSD))
+		s.False(usd.MissingConversionRate)
+
+		// 20 tokens at 0.25 EUR have no rate to USD
+		eur := costs["evt_fut_hf_eur"]
+		s.Equal("eur", eur.Currency)
+		s.True(eur.CostInNanoUSD.IsZero())
+		s.True(eur.MissingConversionRate)
+		s.True(decimal.NewFromInt(5_000_000_000).Equal(eur.CostInNano), "cost: %s", eur.CostInNano)
+	})
+
+	s.NoError(s.GetStores().SettingsRepo.Create(ctx, &settings.Setting{
+		ID:  s.GetUUID(),
+		Key: string(types.SettingKeySettlementConfig),
+		Value: map[string]interface{}{
+			"currency":         "usd",
+			"conversion_rates": map[string]interface{}{"eur": "1.1"},
+		},
+		EnvironmentID: types.GetEnvironmentID(ctx),
+		BaseModel:     types.GetDefaultBaseModel(ctx),
+	}))
+
+	s.Run("non_usd_cost_converted_at_usd_settlement_rate", func() {
+		eur := costs()["evt_fut_hf_eur"]
+		s.False(eur.MissingConversionRate)
+		s.True(decimal.NewFromInt(5_500_000_000).Equal(eur.CostInNanoUSD), "cost: %s", eur.CostInNanoUSD)
+		s.True(decimal.NewFromInt(5_000_000_000).Equal(eur.CostInNano), "cost: %s", eur.CostInNano)
+	})
+}
+
+// windowRecordingEventRepo records the windows unprocessed events are looked up for
+type windowRecordingEventRepo struct {
+	events.Repository
+	windows []reprocessWindow
+}
+
+func (r *windowRecordingEventRepo) FindUnprocessedEventsFromFeatureUsage(ctx context.Context, params *events.FindUnprocessedEventsParams) ([]*events.Event, error) {
+	r.windows = append(r.windows, reprocessWindow{start: params.StartTime, end: params.EndTime})
+	return nil, nil
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestReprocessEventsLongRange() {
+	ctx := s.GetContext()
+	s.GetConfig().FeatureUsageTracking.ReprocessMaxRange = 365 * 24 * time.Hour
+	repo := &windowRecordingEventRepo{Repository: s.GetStores().EventRepo}
+	s.service.eventRepo = repo
+
+	s.Run("long_range_is_reprocessed_per_month", func() {
+		repo.windows = nil
+		err := s.service.ReprocessEvents(ctx, &events.ReprocessEventsParams{
+			StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC),
+		})
+		s.NoError(err)
+		s.Equal([]reprocessWindow{
+			{start: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), end: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC).Add(-time.Millisecond)},
+			{start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), end: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Add(-time.Millisecond)},
+			{start: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), end: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC).Add(-time.Millisecond)},
+			{start: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), end: time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)},
+		}, repo.windows)
+	})
+
+	s.Run("range_within_a_month_is_a_single_window", func() {
+		repo.windows = nil
+		err := s.service.ReprocessEvents(ctx, &events.ReprocessEventsParams{
+			StartTime: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+		})
+		s.NoError(err)
+		s.Len(repo.windows, 1)
+	})
+
+	s.Run("open_end_is_reprocessed_up_to_now_per_month", func() {
+		repo.windows = nil
+		start := time.Now().UTC().AddDate(0, -2, 0)
+		err := s.service.ReprocessEvents(ctx, &events.ReprocessEventsParams{
+			StartTime: start,
+		})
+		s.NoError(err)
+		s.GreaterOrEqual(len(repo.windows), 3)
+		s.Equal(start, repo.windows[0].start)
+		s.WithinDuration(time.Now(), repo.windows[len(repo.windows)-1].end, time.Minute)
+	})
+
+	s.Run("rejects_open_end_longer_than_max", func() {
+		repo.windows = nil
+		err := s.service.ReprocessEvents(ctx, &events.ReprocessEventsParams{
+			StartTime: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+		s.Empty(repo.windows)
+	})
+
+	s.Run("rejects_missing_start", func() {
+		repo.windows = nil
+		err := s.service.ReprocessEvents(ctx, &events.ReprocessEventsParams{
+			EndTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+		s.Empty(repo.windows)
+	})
+
+	s.Run("rejects_range_longer_than_max", func() {
+		repo.windows = nil
+		err := s.service.ReprocessEvents(ctx, &events.ReprocessEventsParams{
+			StartTime: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+		s.Empty(repo.windows)
+	})
+
+	s.Run("rejects_inverted_range", func() {
+		err := s.service.ReprocessEvents(ctx, &events.ReprocessEventsParams{
+			StartTime: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestPrepareProcessedEventsUsageFrozen() {
+	event := s.usageEvent("evt_fut_frozen", s.testData.now.Add(-time.Hour), 10)
+
+	s.Run("unfrozen_subscription_records_usage", func() {
+		results, err := s.service.prepareProcessedEvents(s.GetContext(), event)
+		s.NoError(err)
+		s.Len(results, 1)
+		s.Equal(s.testData.subscription.ID, results[0].SubscriptionID)
+	})
+
+	s.testData.subscription.UsageFrozen = true
+	s.NoError(s.GetStores().SubscriptionRepo.Update(s.GetContext(), s.testData.subscription))
+
+	s.Run("frozen_subscription_skips_usage", func() {
+		results, err := s.service.prepareProcessedEvents(s.GetContext(), event)
+		s.NoError(err)
+		s.Empty(results)
+
+		// Freezing usage doesn't cancel the subscription
+		sub, err := s.GetStores().SubscriptionRepo.Get(s.GetContext(), s.testData.subscription.ID)
+		s.NoError(err)
+		s.Equal(types.SubscriptionStatusActive, sub.SubscriptionStatus)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestPrepareProcessedEventsSubscriptionStatusGating() {
+	ctx := s.GetContext()
+	event := s.usageEvent("evt_fut_status", s.testData.now.Add(-time.Hour), 10)
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+
+	s.testData.subscription.SubscriptionStatus = types.SubscriptionStatusTrialing
+	s.NoError(s.GetStores().SubscriptionRepo.Update(ctx, s.testData.subscription))
+
+	s.Run("counts_during_trial_by_default", func() {
+		results, err := s.service.prepareProcessedEvents(ctx, event)
+		s.NoError(err)
+		s.Len(results, 1)
+		s.Equal(s.testData.meter.ID, results[0].MeterID)
+	})
+
+	s.testData.meter.SubscriptionStatuses = []types.SubscriptionStatus{types.SubscriptionStatusActive}
+	s.NoError(meterStore.Update(ctx, s.testData.meter.ID, s.testData.meter))
+
+	s.Run("excluded_during_trial", func() {
+		results, err := s.service.prepareProcessedEvents(ctx, event)
+		s.NoError(err)
+		s.Empty(results)
+	})
+
+	s.testData.subscription.SubscriptionStatus = types.SubscriptionStatusActive
+	s.NoError(s.GetStores().SubscriptionRepo.Update(ctx, s.testData.subscription))
+
+	s.Run("counts_once_active", func() {
+		results, err := s.service.prepareProcessedEvents(ctx, event)
+		s.NoError(err)
+		s.Len(results, 1)
+	})
+
+	s.Run("active_before_cancellation", func() {
+		cancelledAt := event.Timestamp.Add(time.Minute)
+		sub := *s.testData.subscription
+		sub.SubscriptionStatus = types.SubscriptionStatusCancelled
+		sub.CancelledAt = &cancelledAt
+
+		status, err := meteringSubscriptionStatus(ctx, s.service.ServiceParams, &dto.SubscriptionResponse{Subscription: &sub}, event.Timestamp)
+		s.NoError(err)
+		s.Equal(types.SubscriptionStatusActive, status)
+	})
+
+	s.Run("excluded_during_a_resumed_pause", func() {
+		pause := s.pauseSubscription(event.Timestamp.Add(-time.Minute))
+		resumedAt := event.Timestamp.Add(time.Minute)
+		pause.PauseStatus = types.PauseStatusCompleted
+		pause.ResumedAt = &resumedAt
+		s.NoError(s.GetStores().SubscriptionRepo.UpdatePause(ctx, pause))
+
+		s.testData.subscription.SubscriptionStatus = types.SubscriptionStatusActive
+		s.testData.subscription.PauseStatus = types.PauseStatusNone
+		s.testData.subscription.ActivePauseID = nil
+		s.NoError(s.GetStores().SubscriptionRepo.Update(ctx, s.testData.subscription))
+
+		results, err := s.service.prepareProcessedEvents(ctx, event)
+		s.NoError(err)
+		s.Empty(results)
+
+		sub := &dto.SubscriptionResponse{Subscription: s.testData.subscription}
+		status, err := meteringSubscriptionStatus(ctx, s.service.ServiceParams, sub, resumedAt)
+		s.NoError(err)
+		s.Equal(types.SubscriptionStatusActive, status)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestSubscriptionCancelledAtEventTime() {
+	defer func() { s.GetConfig().Billing.CancellationBoundary = "" }()
+
+	cancelledAt := s.testData.now.Add(-time.Hour)
+	sub := *s.testData.subscription
+	sub.SubscriptionStatus = types.SubscriptionStatusCancelled
+	sub.CancelledAt = &cancelledAt
+	subResp := &dto.SubscriptionResponse{Subscription: &sub}
+
+	atCancellation := s.usageEvent("evt_fut_cancel_at", cancelledAt, 10)
+	beforeCancellation := s.usageEvent("evt_fut_cancel_before", cancelledAt.Add(-time.Millisecond), 10)
+	afterCancellation := s.usageEvent("evt_fut_cancel_after", cancelledAt.Add(time.Millisecond), 10)
+	eventPostProcessing := &eventPostProcessingService{ServiceParams: s.service.ServiceParams}
+
+	tests := []struct {
+		name     string
+		boundary types.CancellationBoundary
+		wantAt   bool
+	}{
+		{name: "inclusive_by_default", wantAt: true},
+		{name: "inclusive", boundary: types.CancellationBoundaryInclusive, wantAt: true},
+		{name: "exclusive", boundary: types.CancellationBoundaryExclusive, wantAt: false},
+		{name: "invalid_falls_back_to_inclusive", boundary: "after", wantAt: true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.GetConfig().Billing.CancellationBoundary = tt.boundary
+
+			// Feature usage tracking and event post processing agree on the boundary
+			s.Equal(tt.wantAt, s.service.isSubscriptionValidForEvent(subResp, atCancellation))
+			s.Equal(tt.wantAt, eventPostProcessing.isSubscriptionValidForEvent(subResp, atCancellation))
+
+			s.True(s.service.isSubscriptionValidForEvent(subResp, beforeCancellation))
+			s.True(eventPostProcessing.isSubscriptionValidForEvent(subResp, beforeCancellation))
+			s.False(s.service.isSubscriptionValidForEvent(subResp, afterCancellation))
+			s.False(eventPostProcessing.isSubscriptionValidForEvent(subResp, afterCancellation))
+		})
+	}
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestTrialBoundaryMetering() {
+	ctx := s.GetContext()
+	defer func() {
+		s.GetConfig().Billing.TrialBoundaryPhase = ""
+		s.GetConfig().Billing.TrialBoundaryWindow = 0
+	}()
+
+	// The meter only counts paid usage and the trial ended an hour ago
+	trialEnd := s.testData.now.Add(-time.Hour)
+	s.testData.meter.SubscriptionStatuses = []types.SubscriptionStatus{types.SubscriptionStatusActive}
+	s.NoError(s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore).Update(ctx, s.testData.meter.ID, s.testData.meter))
+	s.testData.subscription.TrialEnd = &trialEnd
+
+	metered := func(offset time.Duration) bool {
+		results, err := s.service.prepareProcessedEvents(ctx, s.usageEvent("evt_fut_trial_boundary", trialEnd.Add(offset), 10))
+		s.NoError(err)
+		return len(results) > 0
+	}
+
+	tests := []struct {
+		name   string
+		phase  types.TrialBoundaryPhase
+		window time.Duration
+		// Whether events at these offsets from the trial end are metered as paid usage
+		want map[time.Duration]bool
+	}{
+		{
+			name: "boundary_is_paid_by_default",
+			want: map[time.Duration]bool{-time.Millisecond: false, 0: true, time.Millisecond: true},
+		},
+		{
+			name:  "boundary_owned_by_trial",
+			phase: types.TrialBoundaryPhaseTrial,
+			want:  map[time.Duration]bool{-time.Millisecond: false, 0: false, time.Millisecond: true},
+		},
+		{
+			name:   "window_owned_by_paid",
+			phase:  types.TrialBoundaryPhasePaid,
+			window: 10 * time.Minute,
+			want:   map[time.Duration]bool{-20 * time.Minute: false, -5 * time.Minute: true, 0: true, 5 * time.Minute: true},
+		},
+		{
+			name:   "window_owned_by_trial",
+			phase:  types.TrialBoundaryPhaseTrial,
+			window: 10 * time.Minute,
+			want:   map[time.Duration]bool{-5 * time.Minute: false, 0: false, 5 * time.Minute: false, 20 * time.Minute: true},
+		},
+		{
+			name:  "invalid_phase_falls_back_to_paid",
+			phase: "grace",
+			want:  map[time.Duration]bool{0: true},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.GetConfig().Billing.TrialBoundaryPhase = tt.phase
+			s.GetConfig().Billing.TrialBoundaryWindow = tt.window
+
+			// The phase of the event decides, whether the subscription is still trialing or
+			// already active when the event is processed
+			for _, status := range []types.SubscriptionStatus{types.SubscriptionStatusTrialing, types.SubscriptionStatusActive} {
+				s.testData.subscription.SubscriptionStatus = status
+				s.NoError(s.GetStores().SubscriptionRepo.Update(ctx, s.testData.subscription))
+
+				for offset, want := range tt.want {
+					s.Equal(want, metered(offset), "status %s, offset %s", status, offset)
+				}
+			}
+		})
+	}
+
+	s.Run("post_processing_agrees", func() {
+		s.GetConfig().Billing.TrialBoundaryPhase = types.TrialBoundaryPhaseTrial
+		s.GetConfig().Billing.TrialBoundaryWindow = 0
+		sub := &dto.SubscriptionResponse{Subscription: s.testData.subscription}
+
+		status, err := meteringSubscriptionStatus(ctx, s.service.ServiceParams, sub, trialEnd)
+		s.NoError(err)
+		s.Equal(types.SubscriptionStatusTrialing, status)
+
+		status, err = meteringSubscriptionStatus(ctx, s.service.ServiceParams, sub, trialEnd.Add(time.Millisecond))
+		s.NoError(err)
+		s.Equal(types.SubscriptionStatusActive, status)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestPrepareProcessedEventsBeforeCustomerCreation() {
+	ctx := s.GetContext()
+	defer func() { s.GetConfig().FeatureUsageTracking.PreCustomerCreationPolicy = "" }()
+
+	// The customer was created well into the subscription that started 30 days ago
+	customerCreatedAt := s.testData.now.Add(-20 * 24 * time.Hour)
+	s.testData.customer.CreatedAt = customerCreatedAt
+	s.NoError(s.GetStores().CustomerRepo.Update(ctx, s.testData.customer))
+	eventTimestamp := s.testData.now.Add(-25 * 24 * time.Hour)
+
+	s.Run("matched_by_default", func() {
+		results, err := s.service.prepareProcessedEvents(ctx, s.usageEvent("evt_fut_precreation", eventTimestamp, 10))
+		s.NoError(err)
+		s.Len(results, 1)
+		s.True(eventTimestamp.Equal(results[0].Timestamp))
+	})
+
+	s.Run("skipped", func() {
+		s.GetConfig().FeatureUsageTracking.PreCustomerCreationPolicy = types.PreCustomerCreationEventPolicySkip
+		results, err := s.service.prepareProcessedEvents(ctx, s.usageEvent("evt_fut_precreation", eventTimestamp, 10))
+		s.NoError(err)
+		s.Empty(results)
+	})
+
+	s.Run("clamped_to_customer_creation", func() {
+		s.GetConfig().FeatureUsageTracking.PreCustomerCreationPolicy = types.PreCustomerCreationEventPolicyClamp
+		results, err := s.service.prepareProcessedEvents(ctx, s.usageEvent("evt_fut_precreation", eventTimestamp, 10))
+		s.NoError(err)
+		s.Len(results, 1)
+		s.True(customerCreatedAt.Equal(results[0].Timestamp), "timestamp: %s", results[0].Timestamp)
+		s.True(decimal.NewFromInt(10).Equal(results[0].QtyTotal))
+	})
+
+	s.Run("clamped_to_subscription_start", func() {
+		s.GetConfig().FeatureUsageTracking.PreCustomerCreationPolicy = types.PreCustomerCreationEventPolicyClamp
+		s.testData.customer.CreatedAt = s.testData.now.Add(-35 * 24 * time.Hour)
+		s.NoError(s.GetStores().CustomerRepo.Update(ctx, s.testData.customer))
+
+		event := s.usageEvent("evt_fut_precreation_early", s.testData.now.Add(-40*24*time.Hour), 10)
+		results, err := s.service.prepareProcessedEvents(ctx, event)
+		s.NoError(err)
+		s.Len(results, 1)
+		s.True(s.testData.subscription.StartDate.Equal(results[0].Timestamp), "timestamp: %s", results[0].Timestamp)
+	})
+
+	s.Run("events_after_creation_unchanged", func() {
+		s.GetConfig().FeatureUsageTracking.PreCustomerCreationPolicy = types.PreCustomerCreationEventPolicySkip
+		eventTimestamp := s.testData.now.Add(-time.Hour)
+		results, err := s.service.prepareProcessedEvents(ctx, s.usageEvent("evt_fut_postcreation", eventTimestamp, 10))
+		s.NoError(err)
+		s.Len(results, 1)
+		s.True(eventTimestamp.Equal(results[0].Timestamp))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestPrepareProcessedEventsEventNameCase() {
+	event := s.usageEvent("evt_fut_case", s.testData.now.Add(-time.Hour), 10)
+	event.EventName = "Tokens_Used"
+
+	s.Run("case_sensitive_by_default", func() {
+		results, err := s.service.prepareProcessedEvents(s.GetContext(), event)
+		s.NoError(err)
+		s.Empty(results)
+
+		skips := &unbilledEventTracker{}
+		_, err = s.service.prepareFeatureUsage(s.GetContext(), event, nil, skips)
+		s.NoError(err)
+		s.Equal(types.UnbilledEventReasonNoMatchingMeter, skips.reason)
+	})
+
+	s.NoError(s.GetStores().SettingsRepo.Create(s.GetContext(), &settings.Setting{
+		ID:  s.GetUUID(),
+		Key: string(types.SettingKeyEventConfig),
+		Value: map[string]interface{}{
+			"case_insensitive_event_names": true,
+		},
+		EnvironmentID: types.GetEnvironmentID(s.GetContext()),
+		BaseModel:     types.GetDefaultBaseModel(s.GetContext()),
+	}))
+
+	s.Run("case_insensitive_when_enabled", func() {
+		results, err := s.service.prepareProcessedEvents(s.GetContext(), event)
+		s.NoError(err)
+		s.Len(results, 1)
+		s.Equal(s.testData.meter.ID, results[0].MeterID)
+		s.True(decimal.NewFromInt(10).Equal(results[0].QtyTotal))
+
+		// Unbilled events are explained with the same matching
+		skips := &unbilledEventTracker{}
+		results, err = s.service.prepareFeatureUsage(s.GetContext(), event, nil, skips)
+		s.NoError(err)
+		s.Len(results, 1)
+		s.Empty(skips.reason)
+	})
+
+	s.Run("other_event_names_still_skipped", func() {
+		other := s.usageEvent("evt_fut_case_other", s.testData.now.Add(-time.Hour), 10)
+		other.EventName = "tokens_used_v2"
+
+		results, err := s.service.prepareProcessedEvents(s.GetContext(), other)
+		s.NoError(err)
+		s.Empty(results)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestProcessingLagMetric() {
+	ctx := s.GetContext()
+	tenantID := types.GetTenantID(ctx)
+	recorder := metrics.NewInMemoryRecorder()
+	s.service.metrics = recorder
+
+	s.Run("delayed_event", func() {
+		timestamp := time.Now().UTC().Add(-10 * time.Minute)
+		event := s.usageEvent("evt_fut_lag_delayed", timestamp, 10)
+		event.IngestedAt = timestamp.Add(5 * time.Minute)
+		payload, err := json.Marshal(event)
+		s.NoError(err)
+
+		msg := message.NewMessage("msg_fut_lag_delayed", payload)
+		msg.Metadata.Set("tenant_id", tenantID)
+		msg.Metadata.Set("environment_id", types.GetEnvironmentID(ctx))
+		s.NoError(s.service.processMessage(msg))
+
+		lag, ok := recorder.Get(metrics.MetricEventProcessingLag, tenantID)
+		s.True(ok)
+		s.Equal(int64(1), lag.Count)
+		s.GreaterOrEqual(lag.Last, 10*time.Minute)
+		s.Less(lag.Last, 11*time.Minute)
+
+		ingestionLag, ok := recorder.Get(metrics.MetricEventIngestionLag, tenantID)
+		s.True(ok)
+		s.GreaterOrEqual(ingestionLag.Last, 5*time.Minute)
+		s.Less(ingestionLag.Last, 6*time.Minute)
+	})
+
+	s.Run("aggregated_per_tenant", func() {
+		recorder := metrics.NewInMemoryRecorder()
+		s.service.metrics = recorder
+		processedAt := s.testData.now
+
+		s.service.recordProcessingLag(&events.Event{TenantID: "tenant_lag_a", Timestamp: processedAt.Add(-2 * time.Second)}, processedAt)
+		s.service.recordProcessingLag(&events.Event{TenantID: "tenant_lag_a", Timestamp: processedAt.Add(-4 * time.Second)}, processedAt)
+		s.service.recordProcessingLag(&events.Event{TenantID: "tenant_lag_b", Timestamp: processedAt.Add(-time.Hour)}, processedAt)
+
+		snapshot := recorder.Snapshot(metrics.MetricEventProcessingLag)
+		s.Len(snapshot, 2)
+		s.Equal(metrics.DurationStats{Count: 2, Sum: 6 * time.Second, Max: 4 * time.Second, Last: 4 * time.Second}, snapshot["tenant_lag_a"])
+		s.Equal(3*time.Second, snapshot["tenant_lag_a"].Avg())
+		s.Equal(time.Hour, snapshot["tenant_lag_b"].Max)
+
+		// Events that were not ingested first have no ingestion lag
+		s.Empty(recorder.Snapshot(metrics.MetricEventIngestionLag))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestPrepareProcessedEventsPeriodCalculationFailure() {
+	ctx := s.GetContext()
+	tenantID := types.GetTenantID(ctx)
+	recorder := metrics.NewInMemoryRecorder()
+	s.service.metrics = recorder
+	defer func() { s.GetConfig().FeatureUsageTracking.PeriodCalculationFailurePolicy = "" }()
+
+	// Without a period count the periods before the current one can't be calculated
+	s.testData.subscription.BillingPeriodCount = 0
+	s.NoError(s.GetStores().SubscriptionRepo.Update(ctx, s.testData.subscription))
+	event := s.usageEvent("evt_fut_period_failure", s.testData.now.Add(-10*24*time.Hour), 10)
+
+	s.Run("skipped_by_default", func() {
+		results, err := s.service.prepareProcessedEvents(ctx, event)
+		s.NoError(err)
+		s.Empty(results)
+		s.Equal(int64(1), recorder.Count(metrics.MetricPeriodCalculationFailure, tenantID))
+	})
+
+	s.Run("error_for_retry", func() {
+		s.GetConfig().FeatureUsageTracking.PeriodCalculationFailurePolicy = types.PeriodCalculationFailurePolicyError
+
+		results, err := s.service.prepareProcessedEvents(ctx, event)
+		s.Error(err)
+		s.Empty(results)
+		s.Equal(int64(2), recorder.Count(metrics.MetricPeriodCalculationFailure, tenantID))
+	})
+
+	s.Run("current_period_unaffected", func() {
+		results, err := s.service.prepareProcessedEvents(ctx, s.usageEvent("evt_fut_period_current", s.testData.now.Add(-time.Hour), 10))
+		s.NoError(err)
+		s.Len(results, 1)
+		s.Equal(int64(2), recorder.Count(metrics.MetricPeriodCalculationFailure, tenantID))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestZeroDurationPeriod() {
+	ctx := s.GetContext()
+	defer func() { s.GetConfig().FeatureUsageTracking.PeriodCalculationFailurePolicy = "" }()
+
+	sub := *s.testData.subscription
+	periodID := uint64(sub.CurrentPeriodStart.Unix() * 1000)
+	event := s.usageEvent("evt_fut_zero_period", sub.CurrentPeriodStart.Add(time.Hour), 10)
+
+	s.Run("weighted_sum_over_current_period", func() {
+		weighted, err := s.service.getTotalUsageForWeightedSumAggregation(&sub, event, decimal.NewFromInt(100), periodID)
+		s.Require().NoError(err)
+		s.True(weighted.IsPositive())
+		s.True(weighted.LessThan(decimal.NewFromInt(100)))
+	})
+
+	// A misconfigured subscription whose current period ends where it starts
+	sub.CurrentPeriodEnd = sub.CurrentPeriodStart
+
+	s.Run("weighted_sum_fails_without_dividing", func() {
+		weighted, err := s.service.getTotalUsageForWeightedSumAggregation(&sub, event, decimal.NewFromInt(100), periodID)
+		s.Error(err)
+		s.True(types.IsZeroDurationPeriod(err))
+		s.True(weighted.IsZero())
+	})
+
+	s.NoError(s.GetStores().SubscriptionRepo.Update(ctx, &sub))
+
+	s.Run("usage_skipped_by_default", func() {
+		results, err := s.service.prepareProcessedEvents(ctx, event)
+		s.NoError(err)
+		s.Empty(results)
+	})
+
+	s.Run("usage_error_for_retry", func() {
+		s.GetConfig().FeatureUsageTracking.PeriodCalculationFailurePolicy = types.PeriodCalculationFailurePolicyError
+
+		results, err := s.service.prepareProcessedEvents(ctx, event)
+		s.Error(err)
+		s.True(types.IsZeroDurationPeriod(err))
+		s.Empty(results)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestCountOncePerPeriodAggregation() {
+	ctx := s.GetContext()
+	s.testData.meter.Aggregation = meter.Aggregation{Type: types.AggregationCountOncePerPeriod}
+	s.NoError(s.testData.meter.Validate())
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+	s.NoError(meterStore.InMemoryStore.Update(ctx, s.testData.meter.ID, s.testData.meter))
+
+	s.Run("multiple_events_in_one_period_count_as_one", func() {
+		var usage []*events.FeatureUsage
+		for i, eventID := range []string{"evt_fut_once_1", "evt_fut_once_2", "evt_fut_once_3"} {
+			event := s.usageEvent(eventID, s.testData.now.Add(-time.Duration(i+1)*time.Hour), 0)
+			results, err := s.service.prepareProcessedEvents(ctx, event)
+			s.NoError(err)
+			s.Len(results, 1)
+			s.True(decimal.NewFromInt(1).Equal(results[0].QtyTotal))
+			usage = append(usage, results...)
+		}
+
+		s.Equal(usage[0].UniqueHash, usage[1].UniqueHash)
+		s.Equal(usage[0].UniqueHash, usage[2].UniqueHash)
+		s.NoError(s.GetStores().FeatureUsageRepo.BulkInsertProcessedEvents(ctx, usage))
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, s.analyticsRequest())
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+		s.True(decimal.NewFromInt(1).Equal(resp.Items[0].TotalUsage), "usage: %s", resp.Items[0].TotalUsage)
+		s.Equal(uint64(3), resp.Items[0].EventCount)
+	})
+
+	s.Run("hash_differs_per_customer_and_period", func() {
+		event := s.usageEvent("evt_fut_once_hash", s.testData.now, 0)
+		hash := s.service.generateUniqueHash(event, s.testData.meter, "cust_a", 1)
+
+		s.Equal(hash, s.service.generateUniqueHash(s.usageEvent("evt_fut_once_other", s.testData.now, 0), s.testData.meter, "cust_a", 1))
+		s.NotEqual(hash, s.service.generateUniqueHash(event, s.testData.meter, "cust_b", 1))
+		s.NotEqual(hash, s.service.generateUniqueHash(event, s.testData.meter, "cust_a", 2))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestUptimeAggregation() {
+	ctx := s.GetContext()
+	s.testData.meter.Aggregation = meter.Aggregation{Type: types.AggregationUptime, HeartbeatIntervalSeconds: 60}
+	s.NoError(s.testData.meter.Validate())
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+	s.NoError(meterStore.InMemoryStore.Update(ctx, s.testData.meter.ID, s.testData.meter))
+
+	s.Run("heartbeats_produce_active_duration", func() {
+		start := s.testData.now.Add(-2 * time.Hour).Truncate(time.Minute)
+		heartbeats := map[string]time.Time{
+			"evt_fut_uptime_1": start,
+			"evt_fut_uptime_2": start.Add(30 * time.Second), // same interval as the first heartbeat
+			"evt_fut_uptime_3": start.Add(time.Minute),
+			"evt_fut_uptime_4": start.Add(5 * time.Minute),
+		}
+
+		var usage []*events.FeatureUsage
+		for eventID, timestamp := range heartbeats {
+			results, err := s.service.prepareProcessedEvents(ctx, s.usageEvent(eventID, timestamp, 0))
+			s.NoError(err)
+			s.Require().Len(results, 1)
+			s.True(decimal.NewFromInt(60).Equal(results[0].QtyTotal))
+			usage = append(usage, results...)
+		}
+		s.NoError(s.GetStores().FeatureUsageRepo.BulkInsertProcessedEvents(ctx, usage))
+
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, s.analyticsRequest())
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.True(decimal.NewFromInt(180).Equal(resp.Items[0].TotalUsage), "usage: %s", resp.Items[0].TotalUsage)
+		s.Equal(uint64(4), resp.Items[0].EventCount)
+	})
+
+	s.Run("hash_differs_per_interval", func() {
+		start := s.testData.now.Truncate(time.Minute)
+		hash := s.service.generateUniqueHash(s.usageEvent("evt_fut_uptime_hash", start, 0), s.testData.meter, "cust_a", 1)
+
+		s.Equal(hash, s.service.generateUniqueHash(s.usageEvent("evt_fut_uptime_same", start.Add(59*time.Second), 0), s.testData.meter, "cust_a", 1))
+		s.NotEqual(hash, s.service.generateUniqueHash(s.usageEvent("evt_fut_uptime_next", start.Add(time.Minute), 0), s.testData.meter, "cust_a", 1))
+		s.NotEqual(hash, s.service.generateUniqueHash(s.usageEvent("evt_fut_uptime_other", start, 0), s.testData.meter, "cust_b", 1))
+	})
+
+	s.Run("usage_capped_at_period_length", func() {
+		s.True(decimal.NewFromInt(3600).Equal(meter.UptimeUsage(90, time.Minute, time.Hour)))
+		s.True(decimal.NewFromInt(1800).Equal(meter.UptimeUsage(30, time.Minute, time.Hour)))
+	})
+
+	s.Run("heartbeat_interval_required", func() {
+		m := *s.testData.meter
+		m.Aggregation = meter.Aggregation{Type: types.AggregationUptime}
+		s.True(ierr.IsValidation(m.Validate()))
+
+		m.Aggregation = meter.Aggregation{Type: types.AggregationCount, HeartbeatIntervalSeconds: 60}
+		s.True(ierr.IsValidation(m.Validate()))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestCounterDeltaAggregation() {
+	ctx := s.GetContext()
+	s.testData.meter.Aggregation = meter.Aggregation{Type: types.AggregationCounterDelta, Field: "tokens"}
+	s.NoError(s.testData.meter.Validate())
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+	s.NoError(meterStore.InMemoryStore.Update(ctx, s.testData.meter.ID, s.testData.meter))
+
+	start := s.testData.now.Add(-6 * time.Hour)
+	record := func(eventID string, offset time.Duration, reading int64) decimal.Decimal {
+		s.NoError(s.service.processEvent(ctx, s.usageEvent(eventID, start.Add(offset), reading)))
+		usage, err := s.GetStores().FeatureUsageRepo.(*testutil.InMemoryFeatureUsageStore).Get(ctx, eventID)
+		s.Require().NoError(err)
+		return usage.QtyTotal
+	}
+
+	s.Run("increasing_readings_bill_the_increase", func() {
+		s.True(decimal.Zero.Equal(record("evt_fut_counter_1", 0, 100)), "the first reading is the baseline")
+		s.True(decimal.NewFromInt(50).Equal(record("evt_fut_counter_2", time.Hour, 150)))
+		s.True(decimal.NewFromInt(25).Equal(record("evt_fut_counter_3", 2*time.Hour, 175)))
+	})
+
+	s.Run("reset_to_zero_bills_the_reading", func() {
+		s.True(decimal.NewFromInt(10).Equal(record("evt_fut_counter_4", 3*time.Hour, 10)), "counter restarted from zero")
+		s.True(decimal.NewFromInt(30).Equal(record("evt_fut_counter_5", 4*time.Hour, 40)))
+	})
+
+	s.Run("analytics_sum_the_increases", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, s.analyticsRequest())
+		s.NoError(err)
+		s.Require().Len(resp.Items, 1)
+		s.True(decimal.NewFromInt(115).Equal(resp.Items[0].TotalUsage), "usage: %s", resp.Items[0].TotalUsage)
+	})
+
+	s.Run("readings_of_other_customers_are_separate", func() {
+		previous, err := s.GetStores().FeatureUsageRepo.GetLatestProcessedEventBefore(ctx, "cust_other", s.testData.meter.ID, s.testData.now)
+		s.NoError(err)
+		s.Nil(previous)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsCommitmentAdjustedCost() {
+	s.recordUsage("evt_fut_commit_1", s.testData.now.Add(-3*time.Hour), 40)
+	s.recordUsage("evt_fut_commit_2", s.testData.now.Add(-1*time.Hour), 60)
+
+	s.Run("no_commitment_adjusted_cost_without_commitment", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), s.analyticsRequest())
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+		s.True(decimal.NewFromInt(50).Equal(resp.Items[0].TotalCost), "total cost: %s", resp.Items[0].TotalCost)
+		s.Nil(resp.Items[0].CommitmentAdjustedCost)
+	})
+
+	s.testData.subscription.CommitmentAmount = lo.ToPtr(decimal.NewFromInt(20))
+	s.testData.subscription.OverageFactor = lo.ToPtr(decimal.NewFromInt(2))
+	s.NoError(s.GetStores().SubscriptionRepo.Update(s.GetContext(), s.testData.subscription))
+
+	s.Run("commitment_adjusted_cost_includes_overage_beyond_commitment", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), s.analyticsRequest())
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+
+		// 100 tokens at 0.50 list price, 40 tokens covered by the commitment of 20 and
+		// 60 tokens billed at twice the list price
+		item := resp.Items[0]
+		s.True(decimal.NewFromInt(50).Equal(item.TotalCost), "total cost: %s", item.TotalCost)
+		s.True(decimal.NewFromInt(50).Equal(resp.TotalCost), "response total cost: %s", resp.TotalCost)
+		s.Require().NotNil(item.CommitmentAdjustedCost)
+		s.True(decimal.NewFromInt(80).Equal(*item.CommitmentAdjustedCost), "commitment adjusted cost: %s", item.CommitmentAdjustedCost)
+	})
+
+	s.Run("no_commitment_adjusted_cost_across_billing_periods", func() {
+		req := s.analyticsRequest()
+		req.StartTime = s.testData.subscription.CurrentPeriodStart.Add(-24 * time.Hour)
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+		s.True(decimal.NewFromInt(50).Equal(resp.Items[0].TotalCost), "total cost: %s", resp.Items[0].TotalCost)
+		s.Nil(resp.Items[0].CommitmentAdjustedCost)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsShadowMeter() {
+	s.testData.meter.BillingMode = types.MeterBillingModeShadow
+	s.NoError(s.testData.meter.Validate())
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+	s.NoError(meterStore.InMemoryStore.Update(s.GetContext(), s.testData.meter.ID, s.testData.meter))
+
+	s.recordUsage("evt_fut_shadow_1", s.testData.now.Add(-3*time.Hour), 40)
+	s.recordUsage("evt_fut_shadow_2", s.testData.now.Add(-1*time.Hour), 60)
+
+	resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), s.analyticsRequest())
+	s.NoError(err)
+	s.Len(resp.Items, 1)
+
+	// Usage is reported in full while nothing is billed for it
+	item := resp.Items[0]
+	s.True(decimal.NewFromInt(100).Equal(item.TotalUsage), "total usage: %s", item.TotalUsage)
+	s.Equal("usd", item.Currency)
+	s.True(item.TotalCost.IsZero(), "total cost: %s", item.TotalCost)
+	s.True(resp.TotalCost.IsZero(), "response total cost: %s", resp.TotalCost)
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsMissingPrice() {
+	ctx := s.GetContext()
+	s.recordUsage("evt_fut_priced", s.testData.now.Add(-2*time.Hour), 10)
+
+	// Usage recorded against a price that no longer resolves
+	event := s.usageEvent("evt_fut_missing_price", s.testData.now.Add(-time.Hour), 20)
+	event.CustomerID = s.testData.customer.ID
+	s.NoError(s.GetStores().EventRepo.InsertEvent(ctx, event))
+	s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(ctx, &events.FeatureUsage{
+		Event:          *event,
+		SubscriptionID: s.testData.subscription.ID,
+		SubLineItemID:  s.testData.lineItem.ID,
+		PriceID:        "price_fut_missing",
+		MeterID:        s.testData.meter.ID,
+		FeatureID:      s.testData.feature.ID,
+		UniqueHash:     event.ID,
+		QtyTotal:       decimal.NewFromInt(20),
+		Sign:           1,
+	}))
+
+	s.Run("reports_zero_cost_with_warning", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, s.analyticsRequest())
+		s.NoError(err)
+		s.Len(resp.Items, 2)
+
+		costs := lo.SliceToMap(resp.Items, func(item dto.UsageAnalyticItem) (string, decimal.Decimal) {
+			return item.PriceID, item.TotalCost
+		})
+		s.True(decimal.NewFromInt(5).Equal(costs[s.testData.price.ID]), "priced cost: %s", costs[s.testData.price.ID])
+		s.True(costs["price_fut_missing"].IsZero(), "missing price cost: %s", costs["price_fut_missing"])
+
+		s.Equal([]dto.UsageAnalyticsWarning{{
+			Code:      types.UsageAnalyticsWarningMissingPrice,
+			Message:   "usage was recorded against a price that could not be found, its cost is reported as zero",
+			FeatureID: s.testData.feature.ID,
+			PriceID:   "price_fut_missing",
+		}}, resp.Warnings)
+	})
+
+	s.Run("fail_on_missing_price", func() {
+		req := s.analyticsRequest()
+		req.FailOnMissingPrice = true
+		_, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.True(ierr.IsNotFound(err))
+	})
+
+	s.Run("no_warnings_when_prices_resolve", func() {
+		req := s.analyticsRequest()
+		req.StartTime = s.testData.now.Add(-3 * time.Hour)
+		req.EndTime = s.testData.now.Add(-90 * time.Minute)
+		resp, err := s.service.GetDetailedUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+		s.Empty(resp.Warnings)
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestForecastFeatureUsage() {
+	base := s.testData.now.Truncate(time.Hour)
+	s.recordUsage("evt_fut_forecast_1", base.Add(-4*time.Hour).Add(10*time.Minute), 10)
+	s.recordUsage("evt_fut_forecast_2", base.Add(-3*time.Hour).Add(5*time.Minute), 5)
+	s.recordUsage("evt_fut_forecast_3", base.Add(-3*time.Hour).Add(40*time.Minute), 15)
+	// No usage in the hour starting at base-2h, it counts as zero usage
+	s.recordUsage("evt_fut_forecast_4", base.Add(-1*time.Hour).Add(20*time.Minute), 30)
+
+	req := func(model types.UsageForecastModel, periods int) *dto.ForecastFeatureUsageRequest {
+		return &dto.ForecastFeatureUsageRequest{
+			ExternalCustomerID: s.testData.customer.ExternalID,
+			FeatureID:          s.testData.feature.ID,
+			StartTime:          base.Add(-4 * time.Hour),
+			EndTime:            base,
+			WindowSize:         types.WindowSizeHour,
+			Model:              model,
+			Periods:            periods,
+		}
+	}
+
+	s.Run("linear_trend", func() {
+		resp, err := s.service.ForecastFeatureUsage(s.GetContext(), req(types.UsageForecastModelLinearTrend, 0))
+		s.NoError(err)
+		s.Equal(s.testData.feature.ID, resp.FeatureID)
+		s.Equal(types.UsageForecastModelLinearTrend, resp.Model)
+
+		s.Require().Len(resp.History, 4)
+		for i, expected := range []int64{10, 20, 0, 30} {
+			s.True(base.Add(time.Duration(i-4)*time.Hour).Equal(resp.History[i].Timestamp), "timestamp: %s", resp.History[i].Timestamp)
+			s.True(decimal.NewFromInt(expected).Equal(resp.History[i].Usage), "usage %d: %s", i, resp.History[i].Usage)
+		}
+		s.True(decimal.NewFromInt(25).Equal(resp.ForecastUsage), "forecast: %s", resp.ForecastUsage)
+	})
+
+	s.Run("moving_average_by_default", func() {
+		r := req("", 2)
+		resp, err := s.service.ForecastFeatureUsage(s.GetContext(), r)
+		s.NoError(err)
+		s.Equal(types.UsageForecastModelMovingAverage, resp.Model)
+		s.True(decimal.NewFromInt(15).Equal(resp.ForecastUsage), "forecast: %s", resp.ForecastUsage)
+	})
+
+	s.Run("calendar_windows_without_usage_at_the_bounds_count", func() {
+		r := req(types.UsageForecastModelMovingAverage, 3)
+		thisMonth := time.Date(base.Year(), base.Month(), 1, 0, 0, 0, 0, time.UTC)
+		r.StartTime = thisMonth.AddDate(0, -2, 0)
+		r.EndTime = thisMonth.AddDate(0, 1, 0)
+		r.WindowSize = types.WindowSizeMonth
+		resp, err := s.service.ForecastFeatureUsage(s.GetContext(), r)
+		s.NoError(err)
+
+		// The months before the usage are filled from the start of the request
+		s.Require().Len(resp.History, 3)
+		for i, expected := range []time.Time{r.StartTime, thisMonth.AddDate(0, -1, 0), thisMonth} {
+			s.True(expected.Equal(resp.History[i].Timestamp), "timestamp: %s", resp.History[i].Timestamp)
+		}
+		s.True(resp.History[0].Usage.IsZero())
+		s.True(decimal.NewFromInt(20).Equal(resp.ForecastUsage), "forecast: %s", resp.ForecastUsage)
+	})
+
+	s.Run("invalid_model", func() {
+		_, err := s.service.ForecastFeatureUsage(s.GetContext(), req("exponential", 0))
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestCombineLineItemUsage() {
+	a, b := decimal.NewFromInt(10), decimal.NewFromInt(4)
+	for aggregationType, expected := range map[types.AggregationType]int64{
+		types.AggregationSum:         14,
+		types.AggregationCount:       14,
+		types.AggregationMax:         10,
+		types.AggregationLatest:      10,
+		types.AggregationCountUnique: 10,
+	} {
+		combined := combineLineItemUsage(aggregationType, a, b)
+		s.True(decimal.NewFromInt(expected).Equal(combined), "%s: %s", aggregationType, combined)
+	}
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestCompareUsageAnalytics() {
+	ctx := s.GetContext()
+
+	// A second feature billed on another subscription that only has usage in the current range
+	callsMeter := &meter.Meter{
+		ID:          "meter_fut_calls",
+		Name:        "Calls",
+		EventName:   "calls_made",
+		Aggregation: meter.Aggregation{Type: types.AggregationCount},
+		BaseModel:   types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().MeterRepo.CreateMeter(ctx, callsMeter))
+	callsFeature := &feature.Feature{
+		ID:        "feat_fut_calls",
+		Name:      "Calls",
+		MeterID:   callsMeter.ID,
+		Type:      types.FeatureTypeMetered,
+		BaseModel: types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().FeatureRepo.Create(ctx, callsFeature))
+	callsPrice := *s.testData.price
+	callsPrice.ID = "price_fut_calls"
+	callsPrice.Amount = decimal.NewFromInt(2)
+	callsPrice.MeterID = callsMeter.ID
+	s.NoError(s.GetStores().PriceRepo.Create(ctx, &callsPrice))
+	callsSub := *s.testData.subscription
+	callsSub.ID = "sub_fut_calls"
+	callsLineItem := *s.testData.lineItem
+	callsLineItem.ID = "subli_fut_calls"
+	callsLineItem.SubscriptionID = callsSub.ID
+	callsLineItem.PriceID = callsPrice.ID
+	callsLineItem.MeterID = callsMeter.ID
+	s.NoError(s.GetStores().SubscriptionRepo.CreateWithLineItems(ctx, &callsSub, []*subscription.SubscriptionLineItem{&callsLineItem}))
+
+	recordCalls := func(eventID string, timestamp time.Time, calls int64) {
+		s.NoError(s.GetStores().FeatureUsageRepo.InsertProcessedEvent(ctx, &events.FeatureUsage{
+			Event: events.Event{
+				ID:                 eventID,
+				TenantID:           types.GetTenantID(ctx),
+				EnvironmentID:      types.GetEnvironmentID(ctx),
+				EventName:          callsMeter.EventName,
+				ExternalCustomerID: s.testData.customer.ExternalID,
+				CustomerID:         s.testData.customer.ID,
+				Timestamp:          timestamp,
+			},
+			SubscriptionID: callsSub.ID,
+			SubLineItemID:  callsLineItem.ID,
+			PriceID:        callsPrice.ID,
+			MeterID:        callsMeter.ID,
+			FeatureID:      callsFeature.ID,
+			UniqueHash:     eventID,
+			QtyTotal:       decimal.NewFromInt(calls),
+			Sign:           1,
+		}))
+	}
+
+	req := &dto.CompareUsageAnalyticsRequest{
+		ExternalCustomerID: s.testData.customer.ExternalID,
+		PreviousStartTime:  s.testData.now.Add(-48 * time.Hour),
+		PreviousEndTime:    s.testData.now.Add(-24 * time.Hour),
+		CurrentStartTime:   s.testData.now.Add(-24 * time.Hour),
+		CurrentEndTime:     s.testData.now.Add(time.Hour),
+	}
+
+	s.Run("increased", func() {
+		s.recordUsage("evt_fut_cmp_prev", s.testData.now.Add(-36*time.Hour), 40)
+		s.recordUsage("evt_fut_cmp_cur", s.testData.now.Add(-time.Hour), 100)
+
+		resp, err := s.service.CompareUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Equal("usd", resp.Currency)
+		s.Len(resp.Items, 1)
+
+		// 40 tokens for 20 last time, 100 tokens for 50 this time
+		delta := resp.Items[0]
+		s.Equal(s.testData.feature.ID, delta.FeatureID)
+		s.True(decimal.NewFromInt(60).Equal(delta.UsageDelta), "usage delta: %s", delta.UsageDelta)
+		s.True(decimal.NewFromInt(150).Equal(*delta.UsageDeltaPercent), "usage delta percent: %s", delta.UsageDeltaPercent)
+		s.True(decimal.NewFromInt(30).Equal(delta.CostDelta), "cost delta: %s", delta.CostDelta)
+		s.True(decimal.NewFromInt(150).Equal(*delta.CostDeltaPercent), "cost delta percent: %s", delta.CostDeltaPercent)
+		s.True(decimal.NewFromInt(30).Equal(resp.TotalCostDelta), "total cost delta: %s", resp.TotalCostDelta)
+	})
+
+	s.Run("decreased", func() {
+		s.recordUsage("evt_fut_cmp_prev_2", s.testData.now.Add(-30*time.Hour), 160)
+
+		resp, err := s.service.CompareUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+
+		// 200 tokens last time against 100 tokens this time
+		delta := resp.Items[0]
+		s.True(decimal.NewFromInt(-100).Equal(delta.UsageDelta), "usage delta: %s", delta.UsageDelta)
+		s.True(decimal.NewFromInt(-50).Equal(*delta.UsageDeltaPercent), "usage delta percent: %s", delta.UsageDeltaPercent)
+		s.True(decimal.NewFromInt(-50).Equal(delta.CostDelta), "cost delta: %s", delta.CostDelta)
+		s.True(decimal.NewFromInt(-50).Equal(*delta.CostDeltaPercent), "cost delta percent: %s", delta.CostDeltaPercent)
+	})
+
+	s.Run("new_feature", func() {
+		recordCalls("evt_fut_cmp_calls", s.testData.now.Add(-2*time.Hour), 3)
+
+		resp, err := s.service.CompareUsageAnalytics(ctx, req)
+		s.NoError(err)
+		s.Len(resp.Items, 2)
+
+		items := lo.KeyBy(resp.Items, func(item dto.UsageAnalyticsDelta) string { return item.FeatureID })
+		delta := items[callsFeature.ID]
+		s.Equal(callsFeature.Name, delta.FeatureName)
+		s.True(delta.PreviousUsage.IsZero())
+		s.True(decimal.NewFromInt(3).Equal(delta.UsageDelta), "usage delta: %s", delta.UsageDelta)
+		s.True(decimal.NewFromInt(6).Equal(delta.CostDelta), "cost delta: %s", delta.CostDelta)
+		s.Nil(delta.UsageDeltaPercent)
+		s.Nil(delta.CostDeltaPercent)
+	})
+
+	s.Run("invalid_range", func() {
+		invalid := *req
+		invalid.CurrentEndTime = invalid.CurrentStartTime
+		_, err := s.service.CompareUsageAnalytics(ctx, &invalid)
+		s.Error(err)
+		s.True(ierr.IsValidation(err))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestGetDetailedUsageAnalyticsRollingWindowMax() {
+	s.testData.meter.Aggregation = meter.Aggregation{Type: types.AggregationMax, Field: "tokens", RollingWindowHours: 1}
+	s.NoError(s.testData.meter.Validate())
+	meterStore := s.GetStores().MeterRepo.(*testutil.InMemoryMeterStore)
+	s.NoError(meterStore.InMemoryStore.Update(s.GetContext(), s.testData.meter.ID, s.testData.meter))
+
+	s.recordUsage("evt_fut_rolling_1", s.testData.now.Add(-3*time.Hour), 10)
+	s.recordUsage("evt_fut_rolling_2", s.testData.now.Add(-150*time.Minute), 20)
+	s.recordUsage("evt_fut_rolling_3", s.testData.now.Add(-time.Hour), 5)
+	s.recordUsage("evt_fut_rolling_4", s.testData.now.Add(-30*time.Minute), 15)
+
+	s.Run("max_of_usage_summed_over_the_window_ending_at_each_event", func() {
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), s.analyticsRequest())
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+
+		// The hour ending at the second event holds 10 + 20, the hour ending at the last one 5 + 15
+		s.True(decimal.NewFromInt(30).Equal(resp.Items[0].TotalUsage), "usage: %s", resp.Items[0].TotalUsage)
+		s.Equal(uint64(4), resp.Items[0].EventCount)
+	})
+
+	s.Run("events_before_the_range_fill_the_first_window", func() {
+		req := s.analyticsRequest()
+		req.StartTime = s.testData.now.Add(-165 * time.Minute)
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+		s.True(decimal.NewFromInt(30).Equal(resp.Items[0].TotalUsage), "usage: %s", resp.Items[0].TotalUsage)
+		s.Equal(uint64(3), resp.Items[0].EventCount)
+	})
+
+	s.Run("lookback_is_limited_to_one_window", func() {
+		req := s.analyticsRequest()
+		req.StartTime = s.testData.now.Add(-45 * time.Minute)
+
+		resp, err := s.service.GetDetailedUsageAnalytics(s.GetContext(), req)
+		s.NoError(err)
+		s.Len(resp.Items, 1)
+		s.True(decimal.NewFromInt(20).Equal(resp.Items[0].TotalUsage), "usage: %s", resp.Items[0].TotalUsage)
+	})
+
+	s.Run("rejects_rolling_window_with_bucket_size", func() {
+		m := *s.testData.meter
+		m.Aggregation.BucketSize = types.WindowSizeHour
+		s.True(ierr.IsValidation(m.Validate()))
+
+		m.Aggregation = meter.Aggregation{Type: types.AggregationSum, Field: "tokens", RollingWindowHours: 1}
+		s.True(ierr.IsValidation(m.Validate()))
+	})
+}
+
+// failingUsageCounterRepo fails every counter write
+type failingUsageCounterRepo struct {
+	events.UsageCounterRepository
+}
+
+func (r *failingUsageCounterRepo) Add(ctx context.Context, featureUsage []*events.FeatureUsage) error {
+	return ierr.NewError("usage counters unavailable").Mark(ierr.ErrDatabase)
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestUsageCounterSink() {
+	ctx := s.GetContext()
+	s.GetConfig().FeatureUsageTracking.UsageCounterSinkEnabled = true
+	defer func() { s.GetConfig().FeatureUsageTracking.UsageCounterSinkEnabled = false }()
+
+	counterValue := func(eventID string) decimal.Decimal {
+		usage, err := s.GetStores().FeatureUsageRepo.(*testutil.InMemoryFeatureUsageStore).Get(ctx, eventID)
+		s.Require().NoError(err)
+		counter, err := s.GetStores().UsageCounterRepo.Get(ctx, usage.CustomerID, usage.FeatureID, usage.PeriodID)
+		s.Require().NoError(err)
+		s.Require().NotNil(counter)
+		return counter.Value
+	}
+
+	s.Run("increments_per_customer_feature_period", func() {
+		s.NoError(s.service.processEvent(ctx, s.usageEvent("evt_fut_counter_1", s.testData.now.Add(-2*time.Hour), 10)))
+		s.True(decimal.NewFromInt(10).Equal(counterValue("evt_fut_counter_1")))
+
+		s.NoError(s.service.processEvent(ctx, s.usageEvent("evt_fut_counter_2", s.testData.now.Add(-time.Hour), 5)))
+		s.True(decimal.NewFromInt(15).Equal(counterValue("evt_fut_counter_2")), "value: %s", counterValue("evt_fut_counter_2"))
+	})
+
+	s.Run("disabled_sink_is_not_written", func() {
+		s.GetConfig().FeatureUsageTracking.UsageCounterSinkEnabled = false
+		defer func() { s.GetConfig().FeatureUsageTracking.UsageCounterSinkEnabled = true }()
+
+		s.NoError(s.service.processEvent(ctx, s.usageEvent("evt_fut_counter_disabled", s.testData.now.Add(-time.Hour), 7)))
+		s.True(decimal.NewFromInt(15).Equal(counterValue("evt_fut_counter_disabled")))
+	})
+
+	s.Run("sink_failure_does_not_fail_the_event", func() {
+		s.service.UsageCounterRepo = &failingUsageCounterRepo{}
+		defer func() { s.service.UsageCounterRepo = s.GetStores().UsageCounterRepo }()
+
+		s.NoError(s.service.processEvent(ctx, s.usageEvent("evt_fut_counter_failing", s.testData.now.Add(-time.Hour), 3)))
+		usage, err := s.GetStores().FeatureUsageRepo.(*testutil.InMemoryFeatureUsageStore).Get(ctx, "evt_fut_counter_failing")
+		s.NoError(err)
+		s.True(decimal.NewFromInt(3).Equal(usage.QtyTotal))
+	})
+
+	s.Run("reprocessed_event_is_counted_once", func() {
+		event := s.usageEvent("evt_fut_counter_reprocessed", s.testData.now.Add(-time.Hour), 4)
+		s.NoError(s.service.processEvent(ctx, event))
+		before := counterValue(event.ID)
+		s.NoError(s.service.processEvent(ctx, event))
+		s.True(before.Equal(counterValue(event.ID)), "value: %s", counterValue(event.ID))
+	})
+
+	s.Run("cancelled_usage_is_subtracted_once", func() {
+		s.NoError(s.service.processEvent(ctx, s.usageEvent("evt_fut_counter_cancelled", s.testData.now.Add(-time.Hour), 6)))
+		before := counterValue("evt_fut_counter_cancelled")
+
+		usage, err := s.GetStores().FeatureUsageRepo.(*testutil.InMemoryFeatureUsageStore).Get(ctx, "evt_fut_counter_cancelled")
+		s.Require().NoError(err)
+		s.NoError(s.GetStores().UsageCounterRepo.Cancel(ctx, []*events.FeatureUsage{usage}))
+		s.NoError(s.GetStores().UsageCounterRepo.Cancel(ctx, []*events.FeatureUsage{usage}))
+		s.True(before.Sub(decimal.NewFromInt(6)).Equal(counterValue("evt_fut_counter_cancelled")), "value: %s", counterValue("evt_fut_counter_cancelled"))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestEventDedupWindow() {
+	ctx := s.GetContext()
+	tenantID := types.GetTenantID(ctx)
+	recorder := metrics.NewInMemoryRecorder()
+	s.service.metrics = recorder
+	defer func() { s.GetConfig().FeatureUsageTracking.DedupWindow = 0 }()
+
+	// Counting the feature usage inserts shows duplicates being tracked
+	featureUsageRepo := &countingFeatureUsageRepo{FeatureUsageRepository: s.service.featureUsageRepo}
+	s.service.featureUsageRepo = featureUsageRepo
+	defer func() { s.service.featureUsageRepo = featureUsageRepo.FeatureUsageRepository }()
+	tracked := func(eventID string) int {
+		return featureUsageRepo.inserts[eventID]
+	}
+
+	s.Run("duplicate_within_window_is_skipped", func() {
+		s.GetConfig().FeatureUsageTracking.DedupWindow = time.Minute
+		event := s.usageEvent("evt_fut_dedup_within", s.testData.now.Add(-2*time.Hour), 10)
+
+		s.NoError(s.service.processEvent(ctx, event))
+		s.NoError(s.service.processEvent(ctx, event))
+
+		s.Equal(1, tracked(event.ID))
+		s.Equal(int64(1), recorder.Count(metrics.MetricEventDuplicate, tenantID))
+	})
+
+	s.Run("duplicate_beyond_window_is_processed", func() {
+		s.GetConfig().FeatureUsageTracking.DedupWindow = 20 * time.Millisecond
+		event := s.usageEvent("evt_fut_dedup_beyond", s.testData.now.Add(-2*time.Hour), 10)
+
+		s.NoError(s.service.processEvent(ctx, event))
+		time.Sleep(50 * time.Millisecond)
+		s.NoError(s.service.processEvent(ctx, event))
+
+		s.Equal(2, tracked(event.ID))
+		s.Equal(int64(1), recorder.Count(metrics.MetricEventDuplicate, tenantID))
+	})
+
+	s.Run("backfilled_event_bypasses_window", func() {
+		s.GetConfig().FeatureUsageTracking.DedupWindow = time.Minute
+		event := s.usageEvent("evt_fut_dedup_backfill", s.testData.now.Add(-2*time.Hour), 10)
+		s.NoError(s.service.processEvent(ctx, event))
+
+		payload, err := json.Marshal(event)
+		s.NoError(err)
+		msg := message.NewMessage("msg_fut_dedup_backfill", payload)
+		msg.Metadata.Set("tenant_id", tenantID)
+		msg.Metadata.Set("environment_id", types.GetEnvironmentID(ctx))
+		msg.Metadata.Set("backfill", "true")
+		s.NoError(s.service.processMessage(msg))
+
+		s.Equal(2, tracked(event.ID))
+		s.Equal(int64(1), recorder.Count(metrics.MetricEventDuplicate, tenantID))
+	})
+
+	s.Run("disabled_window_processes_duplicates", func() {
+		s.GetConfig().FeatureUsageTracking.DedupWindow = 0
+		event := s.usageEvent("evt_fut_dedup_disabled", s.testData.now.Add(-2*time.Hour), 10)
+
+		s.NoError(s.service.processEvent(ctx, event))
+		s.NoError(s.service.processEvent(ctx, event))
+
+		s.Equal(2, tracked(event.ID))
+	})
+
+	s.Run("failed_event_is_processed_when_retried", func() {
+		s.GetConfig().FeatureUsageTracking.DedupWindow = time.Minute
+		event := s.usageEvent("evt_fut_dedup_failed", s.testData.now.Add(-2*time.Hour), 10)
+
+		featureUsageRepo.insertErr = ierr.NewError("clickhouse unavailable").Mark(ierr.ErrDatabase)
+		s.Error(s.service.processEvent(ctx, event))
+		featureUsageRepo.insertErr = nil
+		s.NoError(s.service.processEvent(ctx, event))
+
+		s.Equal(1, tracked(event.ID))
+		s.Equal(int64(1), recorder.Count(metrics.MetricEventDuplicate, tenantID))
+	})
+
+	s.Run("expired_ids_are_deleted", func() {
+		s.GetConfig().FeatureUsageTracking.DedupWindow = 20 * time.Millisecond
+		event := s.usageEvent("evt_fut_dedup_expired", s.testData.now.Add(-2*time.Hour), 10)
+		s.NoError(s.service.processEvent(ctx, event))
+
+		time.Sleep(50 * time.Millisecond)
+		deleted, err := s.service.DeleteExpiredEventDedupKeys(ctx)
+		s.NoError(err)
+		s.Positive(deleted)
+
+		// ids within their window are kept
+		s.NoError(s.service.processEvent(ctx, s.usageEvent("evt_fut_dedup_failed", s.testData.now.Add(-2*time.Hour), 10)))
+		s.Equal(1, tracked("evt_fut_dedup_failed"))
+		s.Equal(int64(2), recorder.Count(metrics.MetricEventDuplicate, tenantID))
+	})
+}
+
+func (s *FeatureUsageTrackingServiceSuite) TestPublishEventPriorityTopic() {
+	ctx := s.GetContext()
+	pubSub := testutil.NewInMemoryPubSub()
+	backfillPubSub := testutil.NewInMemoryPubSub()
+	s.service.pubSub = pubSub
+	s.service.backfillPubSub = backfillPubSub
+	defer func() {
+		s.service.pubSub = nil
+		s.service.backfillPubSub = nil
+	}()
+
+	cfg := &s.GetConfig().FeatureUsageTracking
+	cfg.PriorityProperty = "routing.priority"
+	cfg.PriorityTopics = []config.FeatureUsagePriorityTopicConfig{
+		{Priority: "high", Topic: "events_high", ConsumerGroup: "feature_tracking_high", RateLimit: 10},
+	}
+	defer func() {
+		cfg.PriorityProperty = ""
+		cfg.PriorityTopics = nil
+	}()
+
+	taggedEvent := func(id string, priority interface{}) *events.Event {
+		event := s.usageEvent(id, s.testData.now.Add(-time.Hour), 1)
+		if priority != nil {
+			event.Properties["routing"] = map[string]interface{}{"priority": priority}
+		}
+		return event
+	}
+	published := func(ps *testutil.InMemoryPubSub, topic string) []string {
+		return lo.Map(ps.GetMessages(topic), func(msg *message.Message, _ int) string {
+			var event events.Event
+			s.Require().NoError(json.Unmarshal(msg.Payload, &event))
+			return event.ID + ":" + msg.Metadata.Get("priority")
+		})
+	}
+
+	tests := []struct {
+		name       string
+		event      *events.Event
+		isBackfill bool
+	}{
+		{name: "tagged_with_priority", event: taggedEvent("evt_prio_high", "high")},
+		{name: "priority_matched_ignoring_case", event: taggedEvent("evt_prio_upper", " HIGH ")},
+		{name: "priority_without_topic", event: taggedEvent("evt_prio_low", "low")},
+		{name: "priority_not_a_string", event: taggedEvent("evt_prio_number", 1)},
+		{name: "untagged", event: taggedEvent("evt_prio_none", nil)},
+		{name: "backfill_ignores_priority", event: taggedEvent("evt_prio_backfill", "high"), isBackfill: true},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.NoError(s.service.PublishEvent(ctx, tt.event, tt.isBackfill))
+		})
+	}
+
+	s.Equal([]string{"evt_prio_high:high", "evt_prio_upper:high"}, published(pubSub, "events_high"))
+	s.Equal([]string{"evt_prio_low:", "evt_prio_number:", "evt_prio_none:"}, published(pubSub, cfg.Topic))
+	s.Equal([]string{"evt_prio_backfill:"}, published(backfillPubSub, cfg.TopicBackfill))
+
+	s.Run("disabled_without_priority_property", func() {
+		cfg.PriorityProperty = ""
+		s.NoError(s.service.PublishEvent(ctx, taggedEvent("evt_prio_disabled", "high"), false))
+		s.Len(pubSub.GetMessages("events_high"), 2)
+		s.Len(pubSub.GetMessages(cfg.Topic), 4)
+	})
+}
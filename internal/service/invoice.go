@@ -629,6 +629,10 @@ func (s *invoiceService) performFinalizeInvoiceActions(ctx context.Context, inv
 		inv.PaymentStatus = types.PaymentStatusSucceeded
 	}
 
+	if err := s.settleInvoice(ctx, inv); err != nil {
+		return err
+	}
+
 	now := time.Now().UTC()
 	inv.InvoiceStatus = types.InvoiceStatusFinalized
 	inv.FinalizedAt = &now
@@ -641,6 +645,38 @@ func (s *invoiceService) performFinalizeInvoiceActions(ctx context.Context, inv
 	return nil
 }
 
+// settleInvoice records the settlement of the invoice in its metadata when the environment
+// settles in a currency other than the one the invoice is priced in. The conversion rate is fixed
+// when the invoice is finalized, the amounts of the invoice stay in the pricing currency.
+func (s *invoiceService) settleInvoice(ctx context.Context, inv *invoice.Invoice) error {
+	config, err := getSettlementConfig(ctx, s.ServiceParams)
+	if err != nil {
+		return err
+	}
+	if config.Currency == "" || types.IsMatchingCurrency(config.Currency, inv.Currency) {
+		return nil
+	}
+
+	settled, rate, ok := config.Convert(inv.Total, inv.Currency)
+	if !ok {
+		s.Logger.Warnw("no conversion rate to the settlement currency, invoice is settled in its pricing currency",
+			"invoice_id", inv.ID,
+			"currency", inv.Currency,
+			"settlement_currency", config.Currency,
+		)
+		return nil
+	}
+
+	if inv.Metadata == nil {
+		inv.Metadata = make(types.Metadata)
+	}
+	inv.Metadata[types.InvoiceMetadataSettlementCurrency] = config.Currency
+	inv.Metadata[types.InvoiceMetadataSettlementConversionRate] = rate.String()
+	mode, _ := costRounding(s.Config, s.Logger)
+	inv.Metadata[types.InvoiceMetadataSettledTotal] = mode.Round(settled, types.GetCurrencyPrecision(config.Currency)).String()
+	return nil
+}
+
 // updateMetadata merges the request metadata with the existing invoice metadata.
 // This function performs a selective update where:
 // - Existing metadata keys not mentioned in the request are preserved
@@ -1846,12 +1882,13 @@ func (s *invoiceService) getInvoiceDataForPDFGen(
 		invoiceNum = *inv.InvoiceNumber
 	}
 
-	// Round to currency precision before converting to float64
+	// Round to currency precision with the configured cost rounding mode before converting to float64
+	mode, _ := costRounding(s.Config, s.Logger)
 	precision := types.GetCurrencyPrecision(inv.Currency)
-	subtotal, _ := inv.Subtotal.Round(precision).Float64()
-	totalDiscount, _ := inv.TotalDiscount.Round(precision).Float64()
-	totalTax, _ := inv.TotalTax.Round(precision).Float64()
-	total, _ := inv.Total.Round(precision).Float64()
+	subtotal, _ := mode.Round(inv.Subtotal, precision).Float64()
+	totalDiscount, _ := mode.Round(inv.TotalDiscount, precision).Float64()
+	totalTax, _ := mode.Round(inv.TotalTax, precision).Float64()
+	total, _ := mode.Round(inv.Total, precision).Float64()
 
 	// Convert to InvoiceData
 	data := &pdf.InvoiceData{
@@ -1922,9 +1959,9 @@ func (s *invoiceService) getInvoiceDataForPDFGen(
 			displayName = *item.DisplayName
 		}
 
-		// Round to currency precision before converting to float64
+		// Round to currency precision with the configured cost rounding mode before converting to float64
 		precision := types.GetCurrencyPrecision(item.Currency)
-		amount, _ := item.Amount.Round(precision).Float64()
+		amount, _ := mode.Round(item.Amount, precision).Float64()
 
 		description := ""
 		if item.Metadata != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/flexprice/flexprice/internal/domain/meter"
 	"github.com/flexprice/flexprice/internal/domain/plan"
 	"github.com/flexprice/flexprice/internal/domain/price"
+	"github.com/flexprice/flexprice/internal/domain/settings"
 	"github.com/flexprice/flexprice/internal/domain/subscription"
 
 	"github.com/flexprice/flexprice/internal/testutil"
@@ -605,6 +606,81 @@ func (s *InvoiceServiceSuite) TestFinalizeInvoice() {
 	}
 }
 
+func (s *InvoiceServiceSuite) TestFinalizeInvoiceSettlement() {
+	draftInvoice := func(currency string, total decimal.Decimal) *invoice.Invoice {
+		inv := &invoice.Invoice{
+			ID:              types.GenerateUUIDWithPrefix(types.UUID_PREFIX_INVOICE),
+			CustomerID:      s.testData.customer.ID,
+			InvoiceType:     types.InvoiceTypeOneOff,
+			InvoiceStatus:   types.InvoiceStatusDraft,
+			PaymentStatus:   types.PaymentStatusPending,
+			Currency:        currency,
+			Subtotal:        total,
+			Total:           total,
+			AmountDue:       total,
+			AmountRemaining: total,
+			BaseModel:       types.GetDefaultBaseModel(s.GetContext()),
+		}
+		s.NoError(s.invoiceRepo.CreateWithLineItems(s.GetContext(), inv))
+		return inv
+	}
+	finalize := func(inv *invoice.Invoice) *invoice.Invoice {
+		s.Require().NoError(s.service.FinalizeInvoice(s.GetContext(), inv.ID))
+		finalized, err := s.invoiceRepo.Get(s.GetContext(), inv.ID)
+		s.Require().NoError(err)
+		s.Equal(types.InvoiceStatusFinalized, finalized.InvoiceStatus)
+		return finalized
+	}
+
+	s.Run("settled_in_pricing_currency_by_default", func() {
+		inv := finalize(draftInvoice("usd", decimal.NewFromFloat(123.45)))
+		s.NotContains(inv.Metadata, types.InvoiceMetadataSettlementCurrency)
+	})
+
+	s.NoError(s.GetStores().SettingsRepo.Create(s.GetContext(), &settings.Setting{
+		ID:  s.GetUUID(),
+		Key: string(types.SettingKeySettlementConfig),
+		Value: map[string]interface{}{
+			"currency":         "EUR",
+			"conversion_rates": map[string]interface{}{"usd": 0.92},
+		},
+		EnvironmentID: types.GetEnvironmentID(s.GetContext()),
+		BaseModel:     types.GetDefaultBaseModel(s.GetContext()),
+	}))
+
+	s.Run("usd_priced_eur_settled", func() {
+		inv := finalize(draftInvoice("usd", decimal.NewFromFloat(123.45)))
+		// The priced amounts are preserved, the settlement is recorded next to them
+		s.True(inv.Total.Equal(decimal.NewFromFloat(123.45)))
+		s.True(inv.AmountDue.Equal(decimal.NewFromFloat(123.45)))
+		s.Equal("eur", inv.Metadata[types.InvoiceMetadataSettlementCurrency])
+		s.Equal("0.92", inv.Metadata[types.InvoiceMetadataSettlementConversionRate])
+		// 123.45 * 0.92 = 113.574, rounded to the precision of EUR
+		s.Equal("113.57", inv.Metadata[types.InvoiceMetadataSettledTotal])
+	})
+
+	s.Run("settled_total_follows_cost_rounding_mode", func() {
+		// 0.375 * 0.92 = 0.345 sits on the half cent boundary
+		inv := finalize(draftInvoice("usd", decimal.NewFromFloat(0.375)))
+		s.Equal("0.35", inv.Metadata[types.InvoiceMetadataSettledTotal])
+
+		s.GetConfig().Billing.CostRoundingMode = types.CostRoundingModeHalfEven
+		defer func() { s.GetConfig().Billing.CostRoundingMode = "" }()
+		inv = finalize(draftInvoice("usd", decimal.NewFromFloat(0.375)))
+		s.Equal("0.34", inv.Metadata[types.InvoiceMetadataSettledTotal])
+	})
+
+	s.Run("priced_in_settlement_currency", func() {
+		inv := finalize(draftInvoice("eur", decimal.NewFromFloat(50)))
+		s.NotContains(inv.Metadata, types.InvoiceMetadataSettlementCurrency)
+	})
+
+	s.Run("no_conversion_rate", func() {
+		inv := finalize(draftInvoice("gbp", decimal.NewFromFloat(50)))
+		s.NotContains(inv.Metadata, types.InvoiceMetadataSettlementCurrency)
+	})
+}
+
 func (s *InvoiceServiceSuite) TestUpdatePaymentStatus() {
 	// Create a finalized invoice first with line items
 	finalizedInvoice := &invoice.Invoice{
@@ -4,8 +4,10 @@ import (
 	"context"
 
 	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/domain/auditlog"
 	"github.com/flexprice/flexprice/internal/domain/meter"
 	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/logger"
 	"github.com/flexprice/flexprice/internal/types"
 )
 
@@ -19,11 +21,19 @@ type MeterService interface {
 }
 
 type meterService struct {
-	meterRepo meter.Repository
+	meterRepo    meter.Repository
+	auditLogRepo auditlog.Repository
+	logger       *logger.Logger
 }
 
-func NewMeterService(meterRepo meter.Repository) MeterService {
-	return &meterService{meterRepo: meterRepo}
+// NewMeterService creates a meter service recording the changes to meters in the audit log
+// repository, when set
+func NewMeterService(meterRepo meter.Repository, auditLogRepo auditlog.Repository, logger *logger.Logger) MeterService {
+	return &meterService{
+		meterRepo:    meterRepo,
+		auditLogRepo: auditLogRepo,
+		logger:       logger,
+	}
 }
 
 func (s *meterService) CreateMeter(ctx context.Context, req *dto.CreateMeterRequest) (*meter.Meter, error) {
@@ -50,6 +60,8 @@ func (s *meterService) CreateMeter(ctx context.Context, req *dto.CreateMeterRequ
 		return nil, err
 	}
 
+	recordAuditLog(ctx, s.auditLogRepo, s.logger, types.AuditLogEntityTypeMeter, meter.ID, types.AuditLogActionCreate, nil, meter)
+
 	return meter, nil
 }
 
@@ -122,7 +134,23 @@ func (s *meterService) DisableMeter(ctx context.Context, id string) error {
 			WithHint("Id is required").
 			Mark(ierr.ErrValidation)
 	}
-	return s.meterRepo.DisableMeter(ctx, id)
+
+	// The meter as it was is only needed for the audit log
+	var before *meter.Meter
+	if s.auditLogRepo != nil {
+		existingMeter, err := s.meterRepo.GetMeter(ctx, id)
+		if err != nil {
+			return err
+		}
+		before = existingMeter
+	}
+
+	if err := s.meterRepo.DisableMeter(ctx, id); err != nil {
+		return err
+	}
+
+	recordAuditLog(ctx, s.auditLogRepo, s.logger, types.AuditLogEntityTypeMeter, id, types.AuditLogActionDelete, before, nil)
+	return nil
 }
 
 // contains checks if a slice contains a specific value
@@ -154,6 +182,7 @@ func (s *meterService) UpdateMeter(ctx context.Context, id string, filters []met
 	if err != nil {
 		return nil, err
 	}
+	before := *existingMeter
 
 	// Merge filters
 	mergedFilters := mergeFilters(existingMeter.Filters, filters)
@@ -165,6 +194,7 @@ func (s *meterService) UpdateMeter(ctx context.Context, id string, filters []met
 
 	// Return the updated meter object
 	existingMeter.Filters = mergedFilters
+	recordAuditLog(ctx, s.auditLogRepo, s.logger, types.AuditLogEntityTypeMeter, id, types.AuditLogActionUpdate, &before, existingMeter)
 	return existingMeter, nil
 }
 
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+
+	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/domain/meter"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
+)
+
+// MeterMergeService finds meters that were accidentally duplicated and consolidates them.
+// Duplicate meters match the same events, so every event is matched once per duplicate when
+// looking up prices.
+type MeterMergeService interface {
+	FindDuplicateMeters(ctx context.Context) (*dto.ListDuplicateMetersResponse, error)
+	MergeMeters(ctx context.Context, req *dto.MergeMetersRequest) (*dto.MergeMetersResponse, error)
+}
+
+type meterMergeService struct {
+	ServiceParams
+}
+
+func NewMeterMergeService(params ServiceParams) MeterMergeService {
+	return &meterMergeService{
+		ServiceParams: params,
+	}
+}
+
+// FindDuplicateMeters returns the published meters of the environment grouped by what they measure,
+// leaving out meters without duplicates
+func (s *meterMergeService) FindDuplicateMeters(ctx context.Context) (*dto.ListDuplicateMetersResponse, error) {
+	meters, err := s.MeterRepo.ListAll(ctx, types.NewNoLimitMeterFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	meters = lo.Filter(meters, func(m *meter.Meter, _ int) bool {
+		return m.Status == types.StatusPublished
+	})
+
+	response := &dto.ListDuplicateMetersResponse{
+		Groups: make([]*dto.DuplicateMeterGroup, 0),
+	}
+	for _, group := range meter.FindDuplicates(meters) {
+		response.Groups = append(response.Groups, &dto.DuplicateMeterGroup{
+			EventName:   group[0].EventName,
+			Aggregation: group[0].Aggregation,
+			Meters:      lo.Map(group, func(m *meter.Meter, _ int) *dto.MeterResponse { return dto.ToMeterResponse(m) }),
+		})
+	}
+
+	return response, nil
+}
+
+// MergeMeters re-points the features, prices and subscription line items of the source meters to
+// the target meter and archives the source meters. Every source meter must be a duplicate of the
+// target, so the merge never changes which events are billed. Usage recorded before the merge
+// keeps the source meter ID.
+func (s *meterMergeService) MergeMeters(ctx context.Context, req *dto.MergeMetersRequest) (*dto.MergeMetersResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	target, err := s.MeterRepo.GetMeter(ctx, req.TargetMeterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.Status != types.StatusPublished {
+		return nil, ierr.NewError("target meter is not published").
+			WithHint("Please merge into a published meter").
+			WithReportableDetails(map[string]interface{}{
+				"target_meter_id": target.ID,
+				"status":          target.Status,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	for _, sourceID := range req.SourceMeterIDs {
+		source, err := s.MeterRepo.GetMeter(ctx, sourceID)
+		if err != nil {
+			return nil, err
+		}
+
+		if source.Status != types.StatusPublished {
+			return nil, ierr.NewError("source meter is not published").
+				WithHint("Only published meters can be merged").
+				WithReportableDetails(map[string]interface{}{
+					"source_meter_id": source.ID,
+					"status":          source.Status,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+
+		if !source.IsDuplicateOf(target) {
+			return nil, ierr.NewError("source meter is not a duplicate of the target meter").
+				WithHint("Only meters with the same event name, aggregation, filters and reset usage can be merged").
+				WithReportableDetails(map[string]interface{}{
+					"source_meter_id": source.ID,
+					"target_meter_id": target.ID,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+	}
+
+	response := &dto.MergeMetersResponse{
+		TargetMeterID:  target.ID,
+		MergedMeterIDs: req.SourceMeterIDs,
+		FeatureIDs:     make([]string, 0),
+		PriceIDs:       make([]string, 0),
+	}
+
+	err = s.DB.WithTx(ctx, func(ctx context.Context) error {
+		featureFilter := types.NewNoLimitFeatureFilter()
+		featureFilter.MeterIDs = req.SourceMeterIDs
+		features, err := s.FeatureRepo.ListAll(ctx, featureFilter)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range features {
+			f.MeterID = target.ID
+			if err := s.FeatureRepo.Update(ctx, f); err != nil {
+				return err
+			}
+			response.FeatureIDs = append(response.FeatureIDs, f.ID)
+		}
+
+		priceFilter := types.NewNoLimitPriceFilter()
+		priceFilter.MeterIDs = req.SourceMeterIDs
+		prices, err := s.PriceRepo.ListAll(ctx, priceFilter)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range prices {
+			p.MeterID = target.ID
+			if err := s.PriceRepo.Update(ctx, p); err != nil {
+				return err
+			}
+			response.PriceIDs = append(response.PriceIDs, p.ID)
+		}
+
+		lineItemFilter := types.NewNoLimitSubscriptionLineItemFilter()
+		lineItemFilter.MeterIDs = req.SourceMeterIDs
+		lineItems, err := s.SubscriptionLineItemRepo.List(ctx, lineItemFilter)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range lineItems {
+			item.MeterID = target.ID
+			item.MeterDisplayName = target.Name
+			if err := s.SubscriptionLineItemRepo.Update(ctx, item); err != nil {
+				return err
+			}
+		}
+		response.LineItemsUpdated = len(lineItems)
+
+		for _, sourceID := range req.SourceMeterIDs {
+			if err := s.MeterRepo.DisableMeter(ctx, sourceID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Logger.Infow("merged duplicate meters",
+		"target_meter_id", target.ID,
+		"merged_meter_ids", req.SourceMeterIDs,
+		"features_updated", len(response.FeatureIDs),
+		"prices_updated", len(response.PriceIDs),
+		"line_items_updated", response.LineItemsUpdated,
+	)
+
+	return response, nil
+}
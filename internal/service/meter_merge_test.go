@@ -0,0 +1,214 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/domain/feature"
+	"github.com/flexprice/flexprice/internal/domain/meter"
+	"github.com/flexprice/flexprice/internal/domain/price"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/testutil"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+type MeterMergeServiceSuite struct {
+	testutil.BaseServiceTestSuite
+	service  MeterMergeService
+	testData struct {
+		original  *meter.Meter
+		duplicate *meter.Meter
+		distinct  *meter.Meter
+		feature   *feature.Feature
+		price     *price.Price
+		lineItem  *subscription.SubscriptionLineItem
+	}
+}
+
+func TestMeterMergeService(t *testing.T) {
+	suite.Run(t, new(MeterMergeServiceSuite))
+}
+
+func (s *MeterMergeServiceSuite) SetupTest() {
+	s.BaseServiceTestSuite.SetupTest()
+	stores := s.GetStores()
+	s.service = NewMeterMergeService(ServiceParams{
+		Logger:                   s.GetLogger(),
+		Config:                   s.GetConfig(),
+		DB:                       s.GetDB(),
+		MeterRepo:                stores.MeterRepo,
+		FeatureRepo:              stores.FeatureRepo,
+		PriceRepo:                stores.PriceRepo,
+		SubscriptionLineItemRepo: stores.SubscriptionLineItemRepo,
+	})
+	s.setupTestData()
+}
+
+func (s *MeterMergeServiceSuite) setupTestData() {
+	ctx := s.GetContext()
+	now := time.Now().UTC()
+
+	newMeter := func(id, field string, createdAt time.Time, filters []meter.Filter) *meter.Meter {
+		m := &meter.Meter{
+			ID:        id,
+			Name:      "API Calls",
+			EventName: "api_call",
+			Aggregation: meter.Aggregation{
+				Type:  types.AggregationSum,
+				Field: field,
+			},
+			Filters:    filters,
+			ResetUsage: types.ResetUsageBillingPeriod,
+			BaseModel:  types.GetDefaultBaseModel(ctx),
+		}
+		m.CreatedAt = createdAt
+		s.NoError(s.GetStores().MeterRepo.CreateMeter(ctx, m))
+		return m
+	}
+
+	s.testData.original = newMeter("meter_original", "count", now.Add(-2*time.Hour), []meter.Filter{
+		{Key: "region", Values: []string{"us", "eu"}},
+		{Key: "tier", Values: []string{"pro"}},
+	})
+	// Same filters in a different order
+	s.testData.duplicate = newMeter("meter_duplicate", "count", now.Add(-time.Hour), []meter.Filter{
+		{Key: "tier", Values: []string{"pro"}},
+		{Key: "region", Values: []string{"eu", "us"}},
+	})
+	s.testData.distinct = newMeter("meter_distinct", "duration_ms", now, []meter.Filter{
+		{Key: "region", Values: []string{"us", "eu"}},
+		{Key: "tier", Values: []string{"pro"}},
+	})
+
+	s.testData.feature = &feature.Feature{
+		ID:        "feat_api_calls",
+		Name:      "API Calls",
+		Type:      types.FeatureTypeMetered,
+		MeterID:   s.testData.duplicate.ID,
+		BaseModel: types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().FeatureRepo.Create(ctx, s.testData.feature))
+
+	s.testData.price = &price.Price{
+		ID:                 "price_api_calls",
+		Amount:             decimal.NewFromFloat(0.01),
+		Currency:           "usd",
+		EntityType:         types.PRICE_ENTITY_TYPE_PLAN,
+		EntityID:           "plan_api",
+		Type:               types.PRICE_TYPE_USAGE,
+		BillingPeriod:      types.BILLING_PERIOD_MONTHLY,
+		BillingPeriodCount: 1,
+		BillingModel:       types.BILLING_MODEL_FLAT_FEE,
+		BillingCadence:     types.BILLING_CADENCE_RECURRING,
+		MeterID:            s.testData.duplicate.ID,
+		BaseModel:          types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().PriceRepo.Create(ctx, s.testData.price))
+
+	s.testData.lineItem = &subscription.SubscriptionLineItem{
+		ID:               "subs_line_api_calls",
+		SubscriptionID:   "subs_api",
+		PriceID:          s.testData.price.ID,
+		PriceType:        types.PRICE_TYPE_USAGE,
+		MeterID:          s.testData.duplicate.ID,
+		MeterDisplayName: "API Calls (copy)",
+		Currency:         "usd",
+		BillingPeriod:    types.BILLING_PERIOD_MONTHLY,
+		BaseModel:        types.GetDefaultBaseModel(ctx),
+	}
+	s.NoError(s.GetStores().SubscriptionLineItemRepo.Create(ctx, s.testData.lineItem))
+}
+
+func (s *MeterMergeServiceSuite) TestFindDuplicateMeters() {
+	resp, err := s.service.FindDuplicateMeters(s.GetContext())
+	s.NoError(err)
+	s.Require().Len(resp.Groups, 1)
+
+	group := resp.Groups[0]
+	s.Equal("api_call", group.EventName)
+	s.Require().Len(group.Meters, 2)
+	// Oldest meter first, it is the one to keep
+	s.Equal(s.testData.original.ID, group.Meters[0].ID)
+	s.Equal(s.testData.duplicate.ID, group.Meters[1].ID)
+}
+
+func (s *MeterMergeServiceSuite) TestMergeMeters() {
+	ctx := s.GetContext()
+
+	resp, err := s.service.MergeMeters(ctx, &dto.MergeMetersRequest{
+		TargetMeterID:  s.testData.original.ID,
+		SourceMeterIDs: []string{s.testData.duplicate.ID},
+	})
+	s.NoError(err)
+	s.Equal([]string{s.testData.feature.ID}, resp.FeatureIDs)
+	s.Equal([]string{s.testData.price.ID}, resp.PriceIDs)
+	s.Equal(1, resp.LineItemsUpdated)
+
+	f, err := s.GetStores().FeatureRepo.Get(ctx, s.testData.feature.ID)
+	s.NoError(err)
+	s.Equal(s.testData.original.ID, f.MeterID)
+
+	p, err := s.GetStores().PriceRepo.Get(ctx, s.testData.price.ID)
+	s.NoError(err)
+	s.Equal(s.testData.original.ID, p.MeterID)
+
+	item, err := s.GetStores().SubscriptionLineItemRepo.Get(ctx, s.testData.lineItem.ID)
+	s.NoError(err)
+	s.Equal(s.testData.original.ID, item.MeterID)
+	s.Equal(s.testData.original.Name, item.MeterDisplayName)
+
+	merged, err := s.GetStores().MeterRepo.GetMeter(ctx, s.testData.duplicate.ID)
+	s.NoError(err)
+	s.Equal(types.StatusDeleted, merged.Status)
+
+	resp2, err := s.service.FindDuplicateMeters(ctx)
+	s.NoError(err)
+	s.Empty(resp2.Groups)
+}
+
+func (s *MeterMergeServiceSuite) TestMergeMetersRejectsNonDuplicates() {
+	ctx := s.GetContext()
+
+	testCases := []struct {
+		name string
+		req  *dto.MergeMetersRequest
+	}{
+		{
+			name: "different aggregation field",
+			req: &dto.MergeMetersRequest{
+				TargetMeterID:  s.testData.original.ID,
+				SourceMeterIDs: []string{s.testData.distinct.ID},
+			},
+		},
+		{
+			name: "target in sources",
+			req: &dto.MergeMetersRequest{
+				TargetMeterID:  s.testData.original.ID,
+				SourceMeterIDs: []string{s.testData.original.ID},
+			},
+		},
+		{
+			name: "no sources",
+			req: &dto.MergeMetersRequest{
+				TargetMeterID: s.testData.original.ID,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			_, err := s.service.MergeMeters(ctx, tc.req)
+			s.Error(err)
+			s.True(ierr.IsValidation(err))
+		})
+	}
+
+	// Nothing was re-pointed
+	f, err := s.GetStores().FeatureRepo.Get(ctx, s.testData.feature.ID)
+	s.NoError(err)
+	s.Equal(s.testData.duplicate.ID, f.MeterID)
+}
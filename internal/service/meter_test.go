@@ -6,9 +6,11 @@ import (
 	"testing"
 
 	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/domain/events"
 	"github.com/flexprice/flexprice/internal/domain/meter"
 	"github.com/flexprice/flexprice/internal/testutil"
 	"github.com/flexprice/flexprice/internal/types"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -26,7 +28,7 @@ func TestMeterService(t *testing.T) {
 func (s *MeterServiceSuite) SetupTest() {
 	s.ctx = testutil.SetupContext()
 	s.store = testutil.NewInMemoryMeterStore()
-	s.service = NewMeterService(s.store)
+	s.service = NewMeterService(s.store, nil, nil)
 }
 
 func (s *MeterServiceSuite) TestCreateMeter() {
@@ -360,3 +362,49 @@ func sortFilters(filters []meter.Filter) {
 		return filters[i].Key < filters[j].Key
 	})
 }
+
+func (s *MeterServiceSuite) TestCreateMeterWithCustomAggregation() {
+	customType := types.AggregationType("TEST_TOKEN_WEIGHTED")
+	req := &dto.CreateMeterRequest{
+		Name:      "Weighted Tokens",
+		EventName: "llm_completion",
+		Aggregation: meter.Aggregation{
+			Type: customType,
+		},
+		ResetUsage: types.ResetUsageBillingPeriod,
+	}
+
+	s.Run("rejects_custom_type_without_registered_extractor", func() {
+		_, err := s.service.CreateMeter(s.ctx, req)
+		s.Error(err)
+	})
+
+	s.Run("accepts_custom_type_with_registered_extractor", func() {
+		s.NoError(meter.RegisterAggregationExtractor(customType, func(event *events.Event, m *meter.Meter) (decimal.Decimal, string, error) {
+			return decimal.NewFromInt(1), "", nil
+		}))
+		defer meter.UnregisterAggregationExtractor(customType)
+
+		created, err := s.service.CreateMeter(s.ctx, req)
+		s.NoError(err)
+		s.Equal(customType, created.Aggregation.Type)
+	})
+}
+
+func (s *MeterServiceSuite) TestRegisterAggregationExtractor() {
+	extractor := func(event *events.Event, m *meter.Meter) (decimal.Decimal, string, error) {
+		return decimal.Zero, "", nil
+	}
+
+	s.Error(meter.RegisterAggregationExtractor(types.AggregationSum, extractor), "built-in types can't be overridden")
+	s.Error(meter.RegisterAggregationExtractor("", extractor))
+	s.Error(meter.RegisterAggregationExtractor("TEST_NIL_EXTRACTOR", nil))
+
+	customType := types.AggregationType("TEST_DUPLICATE")
+	s.NoError(meter.RegisterAggregationExtractor(customType, extractor))
+	defer meter.UnregisterAggregationExtractor(customType)
+	s.Error(meter.RegisterAggregationExtractor(customType, extractor), "duplicate registration is rejected")
+
+	_, ok := meter.GetAggregationExtractor(customType)
+	s.True(ok)
+}
@@ -531,7 +531,7 @@ func (s *onboardingService) createDefaultMeters(ctx context.Context) ([]*meter.M
 	s.Logger.Infow("creating AI-focused meters for hackathon environment")
 
 	// Create a meter service instance
-	meterService := NewMeterService(s.MeterRepo)
+	meterService := NewMeterService(s.MeterRepo, s.AuditLogRepo, s.Logger)
 
 	// Define simple LLM usage meter
 	modelFilters := []meter.Filter{
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/config"
 	"github.com/flexprice/flexprice/internal/domain/price"
 	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/logger"
 	"github.com/flexprice/flexprice/internal/types"
 	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
@@ -37,6 +39,11 @@ type PriceService interface {
 	// CalculateCostSheetPrice calculates the cost for a given price and quantity
 	// specifically for costsheet calculations
 	CalculateCostSheetPrice(ctx context.Context, price *price.Price, quantity decimal.Decimal) decimal.Decimal
+
+	// CalculateEffectiveCost calculates the cost of a price for a quantity including all
+	// modifiers (commitment, overage, true-up, minimum charge and cap) and returns an itemized
+	// breakdown. Invoices and analytics both use it so their amounts can't drift.
+	CalculateEffectiveCost(ctx context.Context, req dto.EffectiveCostRequest) dto.EffectiveCostBreakdown
 }
 
 type priceService struct {
@@ -106,6 +113,8 @@ func (s *priceService) CreatePrice(ctx context.Context, req dto.CreatePriceReque
 		return nil, err
 	}
 
+	recordAuditLog(ctx, s.AuditLogRepo, s.Logger, types.AuditLogEntityTypePrice, p.ID, types.AuditLogActionCreate, nil, p)
+
 	response := &dto.PriceResponse{Price: p}
 
 	// TODO: !REMOVE after migration
@@ -181,6 +190,7 @@ func (s *priceService) CreateBulkPrice(ctx context.Context, req dto.CreateBulkPr
 	}
 
 	var response *dto.CreateBulkPriceResponse
+	var bulkCreated []*price.Price
 
 	// Use transaction to ensure all prices are created or none
 	err := s.DB.WithTx(ctx, func(txCtx context.Context) error {
@@ -218,6 +228,7 @@ func (s *priceService) CreateBulkPrice(ctx context.Context, req dto.CreateBulkPr
 					WithHint("Failed to create prices in bulk").
 					Mark(ierr.ErrDatabase)
 			}
+			bulkCreated = regularPrices
 
 			// Add successful regular prices to response
 			for _, p := range regularPrices {
@@ -247,6 +258,11 @@ func (s *priceService) CreateBulkPrice(ctx context.Context, req dto.CreateBulkPr
 		return nil, err
 	}
 
+	// Prices with a price unit config are recorded as they are created
+	for _, p := range bulkCreated {
+		recordAuditLog(ctx, s.AuditLogRepo, s.Logger, types.AuditLogEntityTypePrice, p.ID, types.AuditLogActionCreate, nil, p)
+	}
+
 	// Sync prices to Chargebee if integration is available and prices are for a plan
 	// Use the centralized sync function for each price
 	if response != nil && len(response.Items) > 0 {
@@ -464,6 +480,8 @@ func (s *priceService) createPriceWithUnitConfig(ctx context.Context, req dto.Cr
 		return nil, err
 	}
 
+	recordAuditLog(ctx, s.AuditLogRepo, s.Logger, types.AuditLogEntityTypePrice, p.ID, types.AuditLogActionCreate, nil, p)
+
 	response := &dto.PriceResponse{Price: p}
 
 	// TODO: !REMOVE after migration
@@ -512,7 +530,7 @@ func (s *priceService) GetPrice(ctx context.Context, id string) (*dto.PriceRespo
 	}
 
 	if price.MeterID != "" {
-		meterService := NewMeterService(s.MeterRepo)
+		meterService := NewMeterService(s.MeterRepo, s.AuditLogRepo, s.Logger)
 		meter, err := meterService.GetMeter(ctx, price.MeterID)
 		if err != nil {
 			s.Logger.Warnw("failed to fetch meter", "meter_id", price.MeterID, "error", err)
@@ -631,7 +649,7 @@ func (s *priceService) GetPricesByCostsheetID(ctx context.Context, costsheetID s
 }
 
 func (s *priceService) GetPrices(ctx context.Context, filter *types.PriceFilter) (*dto.ListPricesResponse, error) {
-	meterService := NewMeterService(s.MeterRepo)
+	meterService := NewMeterService(s.MeterRepo, s.AuditLogRepo, s.Logger)
 
 	// Validate expand fields
 	if err := filter.GetExpand().Validate(types.PriceExpandConfig); err != nil {
@@ -847,6 +865,7 @@ func (s *priceService) UpdatePrice(ctx context.Context, id string, req dto.Updat
 	if err != nil {
 		return nil, err
 	}
+	before := *existingPrice
 
 	// Check if the request has critical fields
 	if req.ShouldCreateNewPrice() {
@@ -882,6 +901,7 @@ func (s *priceService) UpdatePrice(ctx context.Context, id string, req dto.Updat
 			if err := s.PriceRepo.Update(ctx, existingPrice); err != nil {
 				return err
 			}
+			recordAuditLog(ctx, s.AuditLogRepo, s.Logger, types.AuditLogEntityTypePrice, existingPrice.ID, types.AuditLogActionUpdate, &before, existingPrice)
 
 			// Convert update request to create request - this handles all the field mapping
 			createReq := req.ToCreatePriceRequest(existingPrice)
@@ -939,6 +959,7 @@ func (s *priceService) UpdatePrice(ctx context.Context, id string, req dto.Updat
 		if err := s.PriceRepo.Update(ctx, existingPrice); err != nil {
 			return nil, err
 		}
+		recordAuditLog(ctx, s.AuditLogRepo, s.Logger, types.AuditLogEntityTypePrice, existingPrice.ID, types.AuditLogActionUpdate, &before, existingPrice)
 
 		response := &dto.PriceResponse{Price: existingPrice}
 
@@ -987,12 +1008,16 @@ func (s *priceService) DeletePrice(ctx context.Context, id string, req dto.Delet
 			Mark(ierr.ErrValidation)
 	}
 
+	before := *price
 	price.EndDate = &endDate
 
 	if err := s.PriceRepo.Update(ctx, price); err != nil {
 		return err
 	}
 
+	// Prices are terminated rather than removed, the log keeps the price with its end date
+	recordAuditLog(ctx, s.AuditLogRepo, s.Logger, types.AuditLogEntityTypePrice, id, types.AuditLogActionDelete, &before, price)
+
 	return nil
 }
 
@@ -1016,7 +1041,9 @@ func (s *priceService) calculateBucketedMaxCost(ctx context.Context, price *pric
 		}
 	}
 
-	return totalCost.Round(types.GetCurrencyPrecision(price.Currency))
+	// bucketed costs are always rounded to the currency precision, using the configured mode
+	mode, _ := s.costRounding()
+	return mode.Round(totalCost, types.GetCurrencyPrecision(price.Currency))
 }
 
 // calculateSingletonCost calculates cost for a single value
@@ -1060,7 +1087,147 @@ func (s *priceService) calculateSingletonCost(ctx context.Context, price *price.
 // CalculateCost calculates the cost for a given price and quantity
 // returns the cost in main currency units (e.g., 1.00 = $1.00)
 func (s *priceService) CalculateCost(ctx context.Context, price *price.Price, quantity decimal.Decimal) decimal.Decimal {
-	return s.calculateSingletonCost(ctx, price, quantity)
+	return s.roundLineItemCost(s.calculateSingletonCost(ctx, price, quantity), price.Currency)
+}
+
+// roundLineItemCost rounds a line item cost to the currency precision when costs are configured
+// to be rounded per line item, otherwise the cost keeps its full precision for the invoice total
+func (s *priceService) roundLineItemCost(cost decimal.Decimal, currency string) decimal.Decimal {
+	mode, granularity := s.costRounding()
+	if granularity != types.CostRoundingGranularityLineItem {
+		return cost
+	}
+	return mode.Round(cost, types.GetCurrencyPrecision(currency))
+}
+
+// costRounding returns the configured cost rounding mode and granularity
+func (s *priceService) costRounding() (types.CostRoundingMode, types.CostRoundingGranularity) {
+	return costRounding(s.Config, s.Logger)
+}
+
+// costRounding returns the configured cost rounding mode and granularity, falling back to half
+// up at invoice level when unset or invalid
+func costRounding(cfg *config.Configuration, log *logger.Logger) (types.CostRoundingMode, types.CostRoundingGranularity) {
+	mode := types.CostRoundingModeHalfUp
+	granularity := types.CostRoundingGranularityInvoice
+	if cfg == nil {
+		return mode, granularity
+	}
+
+	if configured := cfg.Billing.CostRoundingMode; configured != "" {
+		if err := configured.Validate(); err != nil {
+			log.Warnw("invalid cost rounding mode configured, falling back to half_up",
+				"mode", configured,
+				"error", err,
+			)
+		} else {
+			mode = configured
+		}
+	}
+
+	if configured := cfg.Billing.CostRoundingGranularity; configured != "" {
+		if err := configured.Validate(); err != nil {
+			log.Warnw("invalid cost rounding granularity configured, falling back to invoice",
+				"granularity", configured,
+				"error", err,
+			)
+		} else {
+			granularity = configured
+		}
+	}
+
+	return mode, granularity
+}
+
+func (s *priceService) CalculateEffectiveCost(ctx context.Context, req dto.EffectiveCostRequest) dto.EffectiveCostBreakdown {
+	quantity := req.Quantity
+	if quantity.IsZero() && req.LineItem != nil && req.Price.Type == types.PRICE_TYPE_FIXED {
+		quantity = req.LineItem.Quantity
+	}
+
+	baseCost := lo.FromPtrOr(req.BaseCost, decimal.Zero)
+	if req.BaseCost == nil {
+		baseCost = s.CalculateCost(ctx, req.Price, quantity)
+	}
+
+	breakdown := dto.EffectiveCostBreakdown{
+		Quantity:     quantity,
+		BaseCost:     baseCost,
+		StandardCost: baseCost,
+	}
+
+	// Flat fee prices split across the commitment at their unit amount, every other
+	// model at the average unit cost of the charge
+	if req.Price.BillingModel == types.BILLING_MODEL_FLAT_FEE {
+		breakdown.UnitCost = req.Price.Amount
+	} else if quantity.IsPositive() {
+		breakdown.UnitCost = baseCost.Div(quantity)
+	}
+
+	// Commitment only applies to usage charges, fixed charges are always billed at list price
+	if req.Commitment != nil && req.Price.Type == types.PRICE_TYPE_USAGE {
+		s.applyCommitment(&breakdown, req.Commitment)
+	}
+
+	total := breakdown.StandardCost.
+		Add(breakdown.CommitmentUtilized).
+		Add(breakdown.OverageCost).
+		Add(breakdown.TrueUp)
+
+	if req.MinimumCharge != nil && total.LessThan(*req.MinimumCharge) {
+		breakdown.MinimumChargeAdjustment = req.MinimumCharge.Sub(total)
+		total = *req.MinimumCharge
+	}
+
+	if req.MaximumCharge != nil && total.GreaterThan(*req.MaximumCharge) {
+		breakdown.CapAdjustment = req.MaximumCharge.Sub(total)
+		total = *req.MaximumCharge
+	}
+
+	breakdown.Total = total
+	return breakdown
+}
+
+// applyCommitment splits the base cost of the breakdown between the remaining commitment
+// and overage. The committed quantity is rounded down so the commitment is never exceeded.
+func (s *priceService) applyCommitment(breakdown *dto.EffectiveCostBreakdown, commitment *dto.EffectiveCostCommitment) {
+	remaining := commitment.Remaining
+	breakdown.StandardCost = decimal.Zero
+
+	switch {
+	case remaining.GreaterThanOrEqual(breakdown.BaseCost):
+		// Commitment covers the whole charge
+		breakdown.CommittedQuantity = breakdown.Quantity
+		breakdown.CommitmentUtilized = breakdown.BaseCost
+	case remaining.IsPositive():
+		// Charge is split between commitment and overage
+		if !breakdown.UnitCost.IsZero() {
+			breakdown.CommittedQuantity = remaining.Div(breakdown.UnitCost).Floor()
+		}
+		breakdown.CommitmentUtilized = breakdown.CommittedQuantity.Mul(breakdown.UnitCost)
+		breakdown.OverageQuantity = breakdown.Quantity.Sub(breakdown.CommittedQuantity)
+		if breakdown.OverageQuantity.IsPositive() {
+			breakdown.OverageCost = breakdown.OverageQuantity.Mul(breakdown.UnitCost).Mul(commitment.OverageFactor)
+			breakdown.HasOverage = true
+		} else {
+			breakdown.OverageQuantity = decimal.Zero
+		}
+	default:
+		// Commitment is exhausted, the whole charge is overage
+		breakdown.OverageQuantity = breakdown.Quantity
+		breakdown.OverageCost = breakdown.BaseCost.Mul(commitment.OverageFactor)
+		breakdown.HasOverage = true
+	}
+
+	breakdown.RemainingCommitment = remaining.Sub(breakdown.CommitmentUtilized)
+	if !remaining.IsPositive() {
+		breakdown.RemainingCommitment = remaining
+	}
+
+	if commitment.ApplyTrueUp && !breakdown.HasOverage && breakdown.RemainingCommitment.IsPositive() {
+		breakdown.TrueUp = breakdown.RemainingCommitment
+		breakdown.RemainingCommitment = decimal.Zero
+	}
 }
 
 // CalculateBucketedCost calculates cost for bucketed max values where each value represents max in its time bucket
@@ -1158,7 +1325,8 @@ func (s *priceService) calculateTieredCost(ctx context.Context, price *price.Pri
 		return decimal.Zero
 	}
 
-	return cost
+	// Subscription overrides with a discount inherit the tiers and charge less than their output
+	return price.ApplyDiscountPercent(cost)
 }
 
 // CalculateCostWithBreakup calculates the cost with detailed breakdown information
@@ -1218,7 +1386,8 @@ func (s *priceService) CalculateCostWithBreakup(ctx context.Context, price *pric
 	}
 
 	if round {
-		result.FinalCost = result.FinalCost.Round(types.GetCurrencyPrecision(price.Currency))
+		mode, _ := s.costRounding()
+		result.FinalCost = mode.Round(result.FinalCost, types.GetCurrencyPrecision(price.Currency))
 	}
 
 	return result
@@ -1268,6 +1437,7 @@ func (s *priceService) calculateTieredCostWithBreakup(ctx context.Context, price
 
 		// Calculate tier cost with full precision and handling of flat amount
 		result.FinalCost = selectedTier.CalculateTierAmount(quantity, price.Currency)
+		result.Tiers = append(result.Tiers, tierCostBreakup(selectedTierIndex, selectedTier, quantity, result.FinalCost))
 
 		// Calculate effective unit cost (handle zero quantity case)
 		if !quantity.IsZero() {
@@ -1305,6 +1475,7 @@ func (s *priceService) calculateTieredCostWithBreakup(ctx context.Context, price
 			// Calculate tier cost with full precision and handling of flat amount
 			tierCost := tier.CalculateTierAmount(tierQuantity, price.Currency)
 			result.FinalCost = result.FinalCost.Add(tierCost)
+			result.Tiers = append(result.Tiers, tierCostBreakup(i, tier, tierQuantity, tierCost))
 
 			// Record the last tier used (will be the highest tier the quantity applies to)
 			if tierQuantity.GreaterThan(decimal.Zero) {
@@ -1337,9 +1508,33 @@ func (s *priceService) calculateTieredCostWithBreakup(ctx context.Context, price
 		s.Logger.WithContext(ctx).Errorf("invalid tier mode: %s", price.TierMode)
 	}
 
+	// Subscription overrides with a discount inherit the tiers and charge less than their output
+	result.FinalCost = price.ApplyDiscountPercent(result.FinalCost)
+	result.EffectiveUnitCost = price.ApplyDiscountPercent(result.EffectiveUnitCost)
+	result.TierUnitAmount = price.ApplyDiscountPercent(result.TierUnitAmount)
+	for i := range result.Tiers {
+		result.Tiers[i].UnitAmount = price.ApplyDiscountPercent(result.Tiers[i].UnitAmount)
+		result.Tiers[i].Cost = price.ApplyDiscountPercent(result.Tiers[i].Cost)
+		if result.Tiers[i].FlatAmount != nil {
+			result.Tiers[i].FlatAmount = lo.ToPtr(price.ApplyDiscountPercent(*result.Tiers[i].FlatAmount))
+		}
+	}
+
 	return result
 }
 
+// tierCostBreakup returns the quantity billed in the tier and its cost
+func tierCostBreakup(index int, tier price.PriceTier, quantity, cost decimal.Decimal) dto.TierCostBreakup {
+	return dto.TierCostBreakup{
+		TierIndex:  index,
+		UpTo:       tier.UpTo,
+		Quantity:   quantity,
+		UnitAmount: tier.UnitAmount,
+		FlatAmount: tier.FlatAmount,
+		Cost:       cost,
+	}
+}
+
 // CalculateCostSheetPrice calculates the cost for a given price and quantity
 // specifically for costsheet calculations. This is similar to CalculateCost
 // but may have specific rules for costsheet pricing.
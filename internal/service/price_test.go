@@ -8,9 +8,11 @@ import (
 	"time"
 
 	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/config"
 	"github.com/flexprice/flexprice/internal/domain/plan"
 	"github.com/flexprice/flexprice/internal/domain/price"
 	"github.com/flexprice/flexprice/internal/domain/priceunit"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
 	"github.com/flexprice/flexprice/internal/logger"
 	"github.com/flexprice/flexprice/internal/testutil"
 	"github.com/flexprice/flexprice/internal/types"
@@ -26,6 +28,7 @@ type PriceServiceSuite struct {
 	priceRepo     *testutil.InMemoryPriceStore
 	meterRepo     *testutil.InMemoryMeterStore
 	priceUnitRepo *testutil.InMemoryPriceUnitStore
+	auditLogRepo  *testutil.InMemoryAuditLogStore
 	logger        *logger.Logger
 }
 
@@ -38,6 +41,7 @@ func (s *PriceServiceSuite) SetupTest() {
 	s.priceRepo = testutil.NewInMemoryPriceStore()
 	s.meterRepo = testutil.NewInMemoryMeterStore()
 	s.priceUnitRepo = testutil.NewInMemoryPriceUnitStore()
+	s.auditLogRepo = testutil.NewInMemoryAuditLogStore()
 	s.logger = logger.GetLogger()
 
 	serviceParams := ServiceParams{
@@ -45,6 +49,7 @@ func (s *PriceServiceSuite) SetupTest() {
 		MeterRepo:     s.meterRepo,
 		PriceUnitRepo: s.priceUnitRepo,
 		PlanRepo:      testutil.NewInMemoryPlanStore(),
+		AuditLogRepo:  s.auditLogRepo,
 		Logger:        s.logger,
 	}
 	s.priceService = NewPriceService(serviceParams)
@@ -194,6 +199,17 @@ func (s *PriceServiceSuite) TestUpdatePrice() {
 	s.NotNil(resp)
 	s.Equal(req.Description, resp.Price.Description)
 	s.Equal(req.Metadata, map[string]string(resp.Price.Metadata)) // Convert Metadata for comparison
+
+	// The update is audited with the before and after of the changed fields
+	logs, err := s.auditLogRepo.ListByEntity(s.ctx, types.AuditLogEntityTypePrice, "price-1", 0)
+	s.NoError(err)
+	s.Require().Len(logs, 1)
+	s.Equal(types.AuditLogActionUpdate, logs[0].Action)
+	s.Equal(types.DefaultUserID, logs[0].Actor)
+	s.Equal(map[string]types.AuditLogChange{
+		"description": {Before: "", After: "Updated Description"},
+		"metadata":    {Before: nil, After: map[string]interface{}{"updated": "true"}},
+	}, logs[0].Changes)
 }
 
 func (s *PriceServiceSuite) TestDeletePrice() {
@@ -377,6 +393,157 @@ func (s *PriceServiceSuite) TestCalculateCostWithBreakup_TieredSlab() {
 	s.Equal(2, result.SelectedTierIndex)                               // Index 2 (third tier)
 }
 
+func (s *PriceServiceSuite) TestCalculateCostWithBreakup_TierBreakdown() {
+	upTo10 := uint64(10)
+	upTo20 := uint64(20)
+	flatAmount := decimal.NewFromInt(25)
+	tieredPrice := func(mode types.BillingTier) *price.Price {
+		return &price.Price{
+			ID:           "price-tiers",
+			Currency:     "usd",
+			BillingModel: types.BILLING_MODEL_TIERED,
+			TierMode:     mode,
+			Tiers: []price.PriceTier{
+				{UpTo: &upTo10, UnitAmount: decimal.NewFromInt(50)},
+				{UpTo: &upTo20, UnitAmount: decimal.NewFromInt(40), FlatAmount: &flatAmount},
+				{UnitAmount: decimal.NewFromInt(30)},
+			},
+		}
+	}
+	split := func(tiers []dto.TierCostBreakup) [][2]string {
+		return lo.Map(tiers, func(tier dto.TierCostBreakup, _ int) [2]string {
+			return [2]string{tier.Quantity.String(), tier.Cost.String()}
+		})
+	}
+
+	s.Run("slab_usage_spanning_three_tiers", func() {
+		result := s.priceService.CalculateCostWithBreakup(s.ctx, tieredPrice(types.BILLING_TIER_SLAB), decimal.NewFromInt(25), false)
+		// (10 * 50) + (10 * 40 + 25) + (5 * 30)
+		s.Equal([][2]string{{"10", "500"}, {"10", "425"}, {"5", "150"}}, split(result.Tiers))
+		s.Equal([]int{0, 1, 2}, lo.Map(result.Tiers, func(tier dto.TierCostBreakup, _ int) int { return tier.TierIndex }))
+		s.Equal(&upTo20, result.Tiers[1].UpTo)
+		s.Nil(result.Tiers[2].UpTo)
+		s.Equal("25", result.Tiers[1].FlatAmount.String())
+		s.Equal("1075", result.FinalCost.String())
+	})
+
+	s.Run("slab_usage_within_first_tier", func() {
+		result := s.priceService.CalculateCostWithBreakup(s.ctx, tieredPrice(types.BILLING_TIER_SLAB), decimal.NewFromInt(4), false)
+		s.Equal([][2]string{{"4", "200"}}, split(result.Tiers))
+	})
+
+	s.Run("volume_usage_billed_in_one_tier", func() {
+		result := s.priceService.CalculateCostWithBreakup(s.ctx, tieredPrice(types.BILLING_TIER_VOLUME), decimal.NewFromInt(25), false)
+		s.Equal([][2]string{{"25", "750"}}, split(result.Tiers))
+		s.Equal(2, result.Tiers[0].TierIndex)
+	})
+
+	s.Run("discounted_tiers", func() {
+		p := tieredPrice(types.BILLING_TIER_SLAB)
+		p.DiscountPercent = lo.ToPtr(decimal.NewFromInt(10))
+		result := s.priceService.CalculateCostWithBreakup(s.ctx, p, decimal.NewFromInt(25), false)
+		s.Equal([][2]string{{"10", "450"}, {"10", "382.5"}, {"5", "135"}}, split(result.Tiers))
+		s.Equal("36", result.Tiers[1].UnitAmount.String())
+		s.Equal("22.5", result.Tiers[1].FlatAmount.String())
+		s.Equal("967.5", result.FinalCost.String())
+		// The flat amount of the price itself is untouched
+		s.Equal("25", flatAmount.String())
+	})
+
+	s.Run("no_tiers_for_other_models", func() {
+		result := s.priceService.CalculateCostWithBreakup(s.ctx, &price.Price{
+			Amount:       decimal.NewFromInt(2),
+			Currency:     "usd",
+			BillingModel: types.BILLING_MODEL_FLAT_FEE,
+		}, decimal.NewFromInt(25), false)
+		s.Empty(result.Tiers)
+	})
+}
+
+func (s *PriceServiceSuite) TestCalculateCost_TieredDiscountOverride() {
+	upTo10 := uint64(10)
+	upTo20 := uint64(20)
+	tiers := []price.PriceTier{
+		{UpTo: &upTo10, UnitAmount: decimal.NewFromInt(50)},
+		{UpTo: &upTo20, UnitAmount: decimal.NewFromInt(40), FlatAmount: lo.ToPtr(decimal.NewFromInt(100))},
+		{UnitAmount: decimal.NewFromInt(30)},
+	}
+
+	tests := []struct {
+		name         string
+		tierMode     types.BillingTier
+		quantity     decimal.Decimal
+		expectedCost decimal.Decimal
+	}{
+		{
+			name:         "volume first tier",
+			tierMode:     types.BILLING_TIER_VOLUME,
+			quantity:     decimal.NewFromInt(5),
+			expectedCost: decimal.NewFromInt(225), // 90% of 5 * 50
+		},
+		{
+			name:         "volume second tier",
+			tierMode:     types.BILLING_TIER_VOLUME,
+			quantity:     decimal.NewFromInt(15),
+			expectedCost: decimal.NewFromInt(630), // 90% of 15 * 40 + 100
+		},
+		{
+			name:         "volume last tier",
+			tierMode:     types.BILLING_TIER_VOLUME,
+			quantity:     decimal.NewFromInt(25),
+			expectedCost: decimal.NewFromInt(675), // 90% of 25 * 30
+		},
+		{
+			name:         "slab first tier",
+			tierMode:     types.BILLING_TIER_SLAB,
+			quantity:     decimal.NewFromInt(5),
+			expectedCost: decimal.NewFromInt(225), // 90% of 5 * 50
+		},
+		{
+			name:         "slab spanning two tiers",
+			tierMode:     types.BILLING_TIER_SLAB,
+			quantity:     decimal.NewFromInt(15),
+			expectedCost: decimal.NewFromInt(720), // 90% of 10 * 50 + 5 * 40 + 100
+		},
+		{
+			name:         "slab spanning all tiers",
+			tierMode:     types.BILLING_TIER_SLAB,
+			quantity:     decimal.NewFromInt(25),
+			expectedCost: decimal.NewFromInt(1035), // 90% of 10 * 50 + 10 * 40 + 100 + 5 * 30
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			parent := &price.Price{
+				ID:           "price-parent",
+				Currency:     "usd",
+				BillingModel: types.BILLING_MODEL_TIERED,
+				TierMode:     tt.tierMode,
+				Tiers:        tiers,
+			}
+			override := &price.Price{
+				ID:              "price-override",
+				Currency:        "usd",
+				BillingModel:    types.BILLING_MODEL_TIERED,
+				TierMode:        tt.tierMode,
+				Tiers:           tiers,
+				ParentPriceID:   parent.ID,
+				DiscountPercent: lo.ToPtr(decimal.NewFromInt(10)),
+			}
+
+			parentCost := s.priceService.CalculateCost(s.ctx, parent, tt.quantity)
+			overrideCost := s.priceService.CalculateCost(s.ctx, override, tt.quantity)
+			s.True(tt.expectedCost.Equal(overrideCost), "expected %s, got %s", tt.expectedCost, overrideCost)
+			s.True(parentCost.Mul(decimal.NewFromFloat(0.9)).Equal(overrideCost))
+
+			breakup := s.priceService.CalculateCostWithBreakup(s.ctx, override, tt.quantity, false)
+			s.True(tt.expectedCost.Equal(breakup.FinalCost), "expected %s, got %s", tt.expectedCost, breakup.FinalCost)
+			s.True(tt.expectedCost.Div(tt.quantity).Equal(breakup.EffectiveUnitCost))
+		})
+	}
+}
+
 func (s *PriceServiceSuite) TestCalculateCostWithBreakup_ZeroQuantity() {
 	price := &price.Price{
 		ID:           "price-5",
@@ -743,6 +910,76 @@ func (s *PriceServiceSuite) TestCalculateBucketedCost_TieredVolume() {
 		expected.String(), result.String(), bucketedValues)
 }
 
+func (s *PriceServiceSuite) TestCalculateCostRounding() {
+	// $0.001 per unit puts 1025 units exactly on the half cent boundary at $1.025
+	price := &price.Price{
+		ID:           "price-rounding",
+		Amount:       decimal.NewFromFloat(0.001),
+		Currency:     "usd",
+		BillingModel: types.BILLING_MODEL_FLAT_FEE,
+	}
+
+	testCases := []struct {
+		name             string
+		mode             types.CostRoundingMode
+		granularity      types.CostRoundingGranularity
+		expectedCost     decimal.Decimal
+		expectedBucketed decimal.Decimal
+	}{
+		{
+			name:             "line_item_half_up",
+			mode:             types.CostRoundingModeHalfUp,
+			granularity:      types.CostRoundingGranularityLineItem,
+			expectedCost:     decimal.NewFromFloat(1.03),
+			expectedBucketed: decimal.NewFromFloat(2.03),
+		},
+		{
+			name:             "line_item_half_even",
+			mode:             types.CostRoundingModeHalfEven,
+			granularity:      types.CostRoundingGranularityLineItem,
+			expectedCost:     decimal.NewFromFloat(1.02),
+			expectedBucketed: decimal.NewFromFloat(2.02),
+		},
+		{
+			name:             "invoice_keeps_cost_precision_and_rounds_buckets_with_mode",
+			mode:             types.CostRoundingModeHalfEven,
+			granularity:      types.CostRoundingGranularityInvoice,
+			expectedCost:     decimal.NewFromFloat(1.025),
+			expectedBucketed: decimal.NewFromFloat(2.02),
+		},
+		{
+			name:             "invalid_settings_fall_back_to_half_up_at_invoice",
+			mode:             "half_down",
+			granularity:      "subscription",
+			expectedCost:     decimal.NewFromFloat(1.025),
+			expectedBucketed: decimal.NewFromFloat(2.03),
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			priceService := NewPriceService(ServiceParams{
+				Logger: s.logger,
+				Config: &config.Configuration{
+					Billing: config.BillingConfig{
+						CostRoundingMode:        tc.mode,
+						CostRoundingGranularity: tc.granularity,
+					},
+				},
+			})
+
+			cost := priceService.CalculateCost(s.ctx, price, decimal.NewFromInt(1025))
+			s.True(tc.expectedCost.Equal(cost), "expected cost %s but got %s", tc.expectedCost, cost)
+
+			bucketed := priceService.CalculateBucketedCost(s.ctx, price, []decimal.Decimal{
+				decimal.NewFromInt(1025),
+				decimal.NewFromInt(1000),
+			})
+			s.True(tc.expectedBucketed.Equal(bucketed), "expected bucketed cost %s but got %s", tc.expectedBucketed, bucketed)
+		})
+	}
+}
+
 func (s *PriceServiceSuite) TestCalculateBucketedCost_EmptyBuckets() {
 	price := &price.Price{
 		ID:           "price-bucketed-empty",
@@ -1386,3 +1623,177 @@ func (s *PriceServiceSuite) TestDeletePrice_EdgeCases() {
 		s.Equal(types.ROUND_UP, updatedPrice.TransformQuantity.Round)
 	})
 }
+
+func (s *PriceServiceSuite) TestCalculateEffectiveCost() {
+	upTo10 := uint64(10)
+	usagePrice := &price.Price{
+		ID:           "price-effective-usage",
+		Currency:     "usd",
+		Type:         types.PRICE_TYPE_USAGE,
+		BillingModel: types.BILLING_MODEL_TIERED,
+		TierMode:     types.BILLING_TIER_SLAB,
+		Tiers: []price.PriceTier{
+			{UpTo: &upTo10, UnitAmount: decimal.NewFromInt(2)},
+			{UnitAmount: decimal.NewFromInt(1)},
+		},
+	}
+	fixedPrice := &price.Price{
+		ID:           "price-effective-fixed",
+		Amount:       decimal.NewFromInt(10),
+		Currency:     "usd",
+		Type:         types.PRICE_TYPE_FIXED,
+		BillingModel: types.BILLING_MODEL_FLAT_FEE,
+	}
+
+	tests := []struct {
+		name     string
+		req      dto.EffectiveCostRequest
+		expected dto.EffectiveCostBreakdown
+	}{
+		{
+			name: "no modifiers bills base tiered cost",
+			req:  dto.EffectiveCostRequest{Price: usagePrice, Quantity: decimal.NewFromInt(20)},
+			expected: dto.EffectiveCostBreakdown{
+				BaseCost:     decimal.NewFromInt(30),
+				StandardCost: decimal.NewFromInt(30),
+				Total:        decimal.NewFromInt(30),
+			},
+		},
+		{
+			name: "commitment covers the whole charge",
+			req: dto.EffectiveCostRequest{
+				Price:      usagePrice,
+				Quantity:   decimal.NewFromInt(20),
+				Commitment: &dto.EffectiveCostCommitment{Remaining: decimal.NewFromInt(50), OverageFactor: decimal.NewFromInt(2)},
+			},
+			expected: dto.EffectiveCostBreakdown{
+				BaseCost:            decimal.NewFromInt(30),
+				CommittedQuantity:   decimal.NewFromInt(20),
+				CommitmentUtilized:  decimal.NewFromInt(30),
+				RemainingCommitment: decimal.NewFromInt(20),
+				Total:               decimal.NewFromInt(30),
+			},
+		},
+		{
+			name: "true-up charges unused commitment",
+			req: dto.EffectiveCostRequest{
+				Price:      usagePrice,
+				Quantity:   decimal.NewFromInt(20),
+				Commitment: &dto.EffectiveCostCommitment{Remaining: decimal.NewFromInt(50), OverageFactor: decimal.NewFromInt(2), ApplyTrueUp: true},
+			},
+			expected: dto.EffectiveCostBreakdown{
+				BaseCost:           decimal.NewFromInt(30),
+				CommittedQuantity:  decimal.NewFromInt(20),
+				CommitmentUtilized: decimal.NewFromInt(30),
+				TrueUp:             decimal.NewFromInt(20),
+				Total:              decimal.NewFromInt(50),
+			},
+		},
+		{
+			name: "charge split between commitment and overage",
+			req: dto.EffectiveCostRequest{
+				Price:      usagePrice,
+				Quantity:   decimal.NewFromInt(20),
+				Commitment: &dto.EffectiveCostCommitment{Remaining: decimal.NewFromInt(15), OverageFactor: decimal.NewFromInt(2), ApplyTrueUp: true},
+			},
+			expected: dto.EffectiveCostBreakdown{
+				BaseCost:           decimal.NewFromInt(30),
+				CommittedQuantity:  decimal.NewFromInt(10),
+				CommitmentUtilized: decimal.NewFromInt(15),
+				OverageQuantity:    decimal.NewFromInt(10),
+				OverageCost:        decimal.NewFromInt(30),
+				Total:              decimal.NewFromInt(45),
+				HasOverage:         true,
+			},
+		},
+		{
+			name: "exhausted commitment bills everything as overage",
+			req: dto.EffectiveCostRequest{
+				Price:      usagePrice,
+				Quantity:   decimal.NewFromInt(20),
+				Commitment: &dto.EffectiveCostCommitment{Remaining: decimal.Zero, OverageFactor: decimal.NewFromFloat(1.5)},
+			},
+			expected: dto.EffectiveCostBreakdown{
+				BaseCost:        decimal.NewFromInt(30),
+				OverageQuantity: decimal.NewFromInt(20),
+				OverageCost:     decimal.NewFromInt(45),
+				Total:           decimal.NewFromInt(45),
+				HasOverage:      true,
+			},
+		},
+		{
+			name: "minimum charge floors the total",
+			req: dto.EffectiveCostRequest{
+				Price:         usagePrice,
+				Quantity:      decimal.NewFromInt(20),
+				MinimumCharge: lo.ToPtr(decimal.NewFromInt(40)),
+			},
+			expected: dto.EffectiveCostBreakdown{
+				BaseCost:                decimal.NewFromInt(30),
+				StandardCost:            decimal.NewFromInt(30),
+				MinimumChargeAdjustment: decimal.NewFromInt(10),
+				Total:                   decimal.NewFromInt(40),
+			},
+		},
+		{
+			name: "maximum charge caps overage",
+			req: dto.EffectiveCostRequest{
+				Price:         usagePrice,
+				Quantity:      decimal.NewFromInt(20),
+				Commitment:    &dto.EffectiveCostCommitment{Remaining: decimal.NewFromInt(15), OverageFactor: decimal.NewFromInt(2)},
+				MaximumCharge: lo.ToPtr(decimal.NewFromInt(40)),
+			},
+			expected: dto.EffectiveCostBreakdown{
+				BaseCost:           decimal.NewFromInt(30),
+				CommittedQuantity:  decimal.NewFromInt(10),
+				CommitmentUtilized: decimal.NewFromInt(15),
+				OverageQuantity:    decimal.NewFromInt(10),
+				OverageCost:        decimal.NewFromInt(30),
+				CapAdjustment:      decimal.NewFromInt(-5),
+				Total:              decimal.NewFromInt(40),
+				HasOverage:         true,
+			},
+		},
+		{
+			name: "fixed price uses line item quantity and ignores commitment",
+			req: dto.EffectiveCostRequest{
+				Price:      fixedPrice,
+				LineItem:   &subscription.SubscriptionLineItem{Quantity: decimal.NewFromInt(3)},
+				Commitment: &dto.EffectiveCostCommitment{Remaining: decimal.NewFromInt(100), OverageFactor: decimal.NewFromInt(2)},
+			},
+			expected: dto.EffectiveCostBreakdown{
+				BaseCost:     decimal.NewFromInt(30),
+				StandardCost: decimal.NewFromInt(30),
+				Total:        decimal.NewFromInt(30),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			result := s.priceService.CalculateEffectiveCost(s.ctx, tt.req)
+
+			s.True(tt.expected.BaseCost.Equal(result.BaseCost), "base cost: %s", result.BaseCost)
+			s.True(tt.expected.StandardCost.Equal(result.StandardCost), "standard cost: %s", result.StandardCost)
+			s.True(tt.expected.CommittedQuantity.Equal(result.CommittedQuantity), "committed quantity: %s", result.CommittedQuantity)
+			s.True(tt.expected.CommitmentUtilized.Equal(result.CommitmentUtilized), "commitment utilized: %s", result.CommitmentUtilized)
+			s.True(tt.expected.OverageQuantity.Equal(result.OverageQuantity), "overage quantity: %s", result.OverageQuantity)
+			s.True(tt.expected.OverageCost.Equal(result.OverageCost), "overage cost: %s", result.OverageCost)
+			s.True(tt.expected.TrueUp.Equal(result.TrueUp), "true-up: %s", result.TrueUp)
+			s.True(tt.expected.MinimumChargeAdjustment.Equal(result.MinimumChargeAdjustment), "minimum charge adjustment: %s", result.MinimumChargeAdjustment)
+			s.True(tt.expected.CapAdjustment.Equal(result.CapAdjustment), "cap adjustment: %s", result.CapAdjustment)
+			s.True(tt.expected.RemainingCommitment.Equal(result.RemainingCommitment), "remaining commitment: %s", result.RemainingCommitment)
+			s.True(tt.expected.Total.Equal(result.Total), "total: %s", result.Total)
+			s.Equal(tt.expected.HasOverage, result.HasOverage)
+
+			// Components must always add up to the total
+			sum := result.StandardCost.
+				Add(result.CommitmentUtilized).
+				Add(result.OverageCost).
+				Add(result.TrueUp).
+				Add(result.MinimumChargeAdjustment).
+				Add(result.CapAdjustment)
+			s.True(sum.Equal(result.Total), "components %s do not sum to total %s", sum, result.Total)
+		})
+	}
+}
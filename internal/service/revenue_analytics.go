@@ -78,7 +78,7 @@ func (s *revenueAnalyticsService) getCostAnalytics(
 	}
 
 	// 5. Use existing BulkGetUsageByMeter (same as subscription billing)
-	eventService := NewEventService(s.EventRepo, s.MeterRepo, s.EventPublisher, s.Logger, s.Config)
+	eventService := newEventServiceFromParams(s.ServiceParams)
 	usageMap, err := eventService.BulkGetUsageByMeter(ctx, meterUsageRequests)
 	if err != nil {
 		return nil, ierr.WithError(err).
@@ -215,6 +215,46 @@ func (s *settingsService) GetSettingWithDefaults(ctx context.Context, key types.
 	return dto.SettingFromDomain(settingModel), nil
 }
 
+// getEventConfig returns the event config of the current environment. Event processing
+// shouldn't fail on settings, so the defaults are used when the setting can't be read.
+func getEventConfig(ctx context.Context, params ServiceParams) *types.EventConfig {
+	setting, err := NewSettingsService(params).GetSettingWithDefaults(ctx, types.SettingKeyEventConfig)
+	if err != nil {
+		params.Logger.Warnw("failed to get event config, using defaults",
+			"tenant_id", types.GetTenantID(ctx),
+			"environment_id", types.GetEnvironmentID(ctx),
+			"error", err,
+		)
+		return types.EventConfigFromValue(nil)
+	}
+	return types.EventConfigFromValue(setting.Value)
+}
+
+// getAnalyticsConfig returns the analytics config of the current environment. The config only
+// changes how the costs are presented, so analytics are served at the default cost precision
+// rather than failed when the setting can't be read.
+func getAnalyticsConfig(ctx context.Context, params ServiceParams) *types.AnalyticsConfig {
+	setting, err := NewSettingsService(params).GetSettingWithDefaults(ctx, types.SettingKeyAnalyticsConfig)
+	if err != nil {
+		params.Logger.Warnw("failed to get analytics config, using defaults",
+			"tenant_id", types.GetTenantID(ctx),
+			"environment_id", types.GetEnvironmentID(ctx),
+			"error", err,
+		)
+		return types.AnalyticsConfigFromValue(nil)
+	}
+	return types.AnalyticsConfigFromValue(setting.Value)
+}
+
+// getSettlementConfig returns the settlement config of the current environment
+func getSettlementConfig(ctx context.Context, params ServiceParams) (*types.SettlementConfig, error) {
+	setting, err := NewSettingsService(params).GetSettingWithDefaults(ctx, types.SettingKeySettlementConfig)
+	if err != nil {
+		return nil, err
+	}
+	return types.SettlementConfigFromValue(setting.Value), nil
+}
+
 // normalizeSettingTypes normalizes types for known setting keys to ensure consistent typing
 func (s *settingsService) normalizeSettingTypes(key types.SettingKey, values map[string]interface{}) error {
 	switch key {
@@ -12,6 +12,7 @@ import (
 	"github.com/flexprice/flexprice/internal/domain/customer"
 	"github.com/flexprice/flexprice/internal/domain/entitlement"
 	"github.com/flexprice/flexprice/internal/domain/invoice"
+	meterDomain "github.com/flexprice/flexprice/internal/domain/meter"
 	"github.com/flexprice/flexprice/internal/domain/plan"
 	"github.com/flexprice/flexprice/internal/interfaces"
 
@@ -1126,6 +1127,11 @@ func (s *subscriptionService) ProcessSubscriptionPriceOverrides(
 			createPriceReq.TransformQuantity = override.TransformQuantity
 		}
 
+		// Discount override - keeps the original tiers, the discount is applied to their output at cost time
+		if override.DiscountPercent != nil {
+			createPriceReq.DiscountPercent = override.DiscountPercent
+		}
+
 		// Create the subscription-scoped price using price service
 		overriddenPriceResp, err := priceService.CreatePrice(ctx, createPriceReq)
 		if err != nil {
@@ -1401,6 +1407,10 @@ func (s *subscriptionService) UpdateSubscription(ctx context.Context, subscripti
 
 	subscription.CancelAtPeriodEnd = req.CancelAtPeriodEnd
 
+	if req.UsageFrozen != nil {
+		subscription.UsageFrozen = *req.UsageFrozen
+	}
+
 	// Update the subscription in the database
 	err = s.SubRepo.Update(ctx, subscription)
 	if err != nil {
@@ -1743,7 +1753,7 @@ func (s *subscriptionService) ListSubscriptions(ctx context.Context, filter *typ
 func (s *subscriptionService) GetUsageBySubscription(ctx context.Context, req *dto.GetUsageBySubscriptionRequest) (*dto.GetUsageBySubscriptionResponse, error) {
 	response := &dto.GetUsageBySubscriptionResponse{}
 
-	eventService := NewEventService(s.EventRepo, s.MeterRepo, s.EventPublisher, s.Logger, s.Config)
+	eventService := newEventServiceFromParams(s.ServiceParams)
 	priceService := NewPriceService(s.ServiceParams)
 
 	// Get subscription with line items
@@ -1829,10 +1839,13 @@ func (s *subscriptionService) GetUsageBySubscription(ctx context.Context, req *d
 		distinctEventNames = nil // Fallback: process all meters if optimization fails
 	}
 
-	// Create a map for fast event name lookup
-	eventNameExists := make(map[string]bool, len(distinctEventNames))
-	for _, eventName := range distinctEventNames {
-		eventNameExists[eventName] = true
+	// Meters match the event names as event processing does, by prefix or regardless of case
+	eventConfig := getEventConfig(ctx, s.ServiceParams)
+	normalization := eventNameNormalization(s.Config, s.Logger)
+	hasEvents := func(m *meterDomain.Meter) bool {
+		return lo.ContainsBy(distinctEventNames, func(eventName string) bool {
+			return m.MatchesEventName(eventName, eventConfig.CaseInsensitiveEventNames, normalization)
+		})
 	}
 
 	s.Logger.Debugw("distinct event names optimization",
@@ -1873,7 +1886,7 @@ func (s *subscriptionService) GetUsageBySubscription(ctx context.Context, req *d
 
 		// Performance optimization: Skip meters that don't have any events for this customer
 		// Only skip if we successfully got distinct event names (not nil) and the event doesn't exist
-		if distinctEventNames != nil && !eventNameExists[meter.EventName] {
+		if distinctEventNames != nil && !hasEvents(meter.ToMeter()) {
 			s.Logger.Debugw("skipping meter with no events",
 				"meter_id", lineItem.MeterID,
 				"event_name", meter.EventName,
@@ -1885,13 +1898,16 @@ func (s *subscriptionService) GetUsageBySubscription(ctx context.Context, req *d
 
 		meterID := lineItem.MeterID
 		usageRequest := &dto.GetUsageByMeterRequest{
-			MeterID:            meterID,
-			PriceID:            lineItem.PriceID,
-			Meter:              meter.ToMeter(),
-			ExternalCustomerID: customer.ExternalID,
-			StartTime:          lineItem.GetPeriodStart(usageStartTime),
-			EndTime:            lineItem.GetPeriodEnd(usageEndTime),
-			Filters:            make(map[string][]string),
+			MeterID:               meterID,
+			PriceID:               lineItem.PriceID,
+			Meter:                 meter.ToMeter(),
+			ExternalCustomerID:    customer.ExternalID,
+			SubscriptionID:        req.SubscriptionID,
+			CustomerCreatedAt:     customer.CreatedAt,
+			SubscriptionStartDate: subscription.StartDate,
+			StartTime:             lineItem.GetPeriodStart(usageStartTime),
+			EndTime:               lineItem.GetPeriodEnd(usageEndTime),
+			Filters:               make(map[string][]string),
 		}
 
 		for _, filter := range meter.Filters {
@@ -1980,6 +1996,11 @@ func (s *subscriptionService) GetUsageBySubscription(ctx context.Context, req *d
 			cost = priceService.CalculateCost(ctx, priceObj, quantity)
 		}
 
+		// Usage of shadow meters is reported without being billed
+		if meterInfo != nil && meterInfo.ToMeter().IsShadow() {
+			cost = decimal.Zero
+		}
+
 		s.Logger.Debugw("calculated usage for meter",
 			"meter_id", meterID,
 			"quantity", quantity,
@@ -2052,81 +2073,46 @@ func (s *subscriptionService) GetUsageBySubscription(ctx context.Context, req *d
 		totalOverageAmount := decimal.Zero
 
 		for _, charge := range usageOnlyCharges {
-			// Get charge amount as decimal for precise calculations
-			chargeAmount := decimal.NewFromFloat(charge.Amount)
-			pricePerUnit := decimal.Zero
-			if charge.Price != nil && charge.Price.BillingModel == types.BILLING_MODEL_FLAT_FEE {
-				pricePerUnit = charge.Price.Amount
-			} else if charge.Quantity > 0 {
-				pricePerUnit = chargeAmount.Div(decimal.NewFromFloat(charge.Quantity))
-			}
+			breakdown := priceService.CalculateEffectiveCost(ctx, dto.EffectiveCostRequest{
+				Price:    charge.Price,
+				Quantity: decimal.NewFromFloat(charge.Quantity),
+				BaseCost: lo.ToPtr(decimal.NewFromFloat(charge.Amount)),
+				Commitment: &dto.EffectiveCostCommitment{
+					Remaining:     remainingCommitment,
+					OverageFactor: overageFactor,
+				},
+			})
+			remainingCommitment = breakdown.RemainingCommitment
+			totalOverageAmount = totalOverageAmount.Add(breakdown.OverageCost)
 
 			// Normal price covers all of this charge
-			if remainingCommitment.GreaterThanOrEqual(chargeAmount) {
+			if breakdown.CommittedQuantity.Equal(breakdown.Quantity) && !breakdown.HasOverage {
 				charge.IsOverage = false
-				remainingCommitment = remainingCommitment.Sub(chargeAmount)
 				response.Charges = append(response.Charges, charge)
 				continue
 			}
 
-			// Charge needs to be split between normal and overage
-			if remainingCommitment.GreaterThan(decimal.Zero) {
-				// Calculate exact quantity that can be covered by remaining commitment
-				var normalQuantityDecimal decimal.Decimal
-
-				if !pricePerUnit.IsZero() {
-					normalQuantityDecimal = remainingCommitment.Div(pricePerUnit)
-
-					// Round down to ensure we don't exceed commitment
-					normalQuantityDecimal = normalQuantityDecimal.Floor()
-				}
-
-				// Calculate the normal amount based on the normal quantity
-				normalAmountDecimal := normalQuantityDecimal.Mul(pricePerUnit)
-
-				// Create the normal charge
-				if normalQuantityDecimal.GreaterThan(decimal.Zero) {
-					normalCharge := *charge // Create a copy
-					normalCharge.Quantity = normalQuantityDecimal.InexactFloat64()
-					normalCharge.Amount = price.FormatAmountToFloat64WithPrecision(normalAmountDecimal, subscription.Currency)
-					normalCharge.DisplayAmount = price.FormatAmountToStringWithPrecision(normalAmountDecimal, subscription.Currency)
-					normalCharge.IsOverage = false
-					response.Charges = append(response.Charges, &normalCharge)
-				}
-
-				// Calculate overage quantity and amount
-				overageQuantityDecimal := decimal.NewFromFloat(charge.Quantity).Sub(normalQuantityDecimal)
-
-				// Create the overage charge only if there's actual overage
-				if overageQuantityDecimal.GreaterThan(decimal.Zero) {
-					overageAmountDecimal := overageQuantityDecimal.Mul(pricePerUnit).Mul(overageFactor)
-					totalOverageAmount = totalOverageAmount.Add(overageAmountDecimal)
-
-					overageCharge := *charge // Create a copy
-					overageCharge.Quantity = overageQuantityDecimal.InexactFloat64()
-					overageCharge.Amount = price.FormatAmountToFloat64WithPrecision(overageAmountDecimal, subscription.Currency)
-					overageCharge.DisplayAmount = price.FormatAmountToStringWithPrecision(overageAmountDecimal, subscription.Currency)
-					overageCharge.IsOverage = true
-					overageCharge.OverageFactor = overageFactorFloat
-					response.Charges = append(response.Charges, &overageCharge)
-					response.HasOverage = true
-				}
-
-				// Update remaining commitment (should be zero or very close to it due to rounding)
-				remainingCommitment = remainingCommitment.Sub(normalAmountDecimal)
-				continue
+			// Create the normal charge for the quantity covered by the commitment
+			if breakdown.CommittedQuantity.GreaterThan(decimal.Zero) {
+				normalCharge := *charge // Create a copy
+				normalCharge.Quantity = breakdown.CommittedQuantity.InexactFloat64()
+				normalCharge.Amount = price.FormatAmountToFloat64WithPrecision(breakdown.CommitmentUtilized, subscription.Currency)
+				normalCharge.DisplayAmount = price.FormatAmountToStringWithPrecision(breakdown.CommitmentUtilized, subscription.Currency)
+				normalCharge.IsOverage = false
+				response.Charges = append(response.Charges, &normalCharge)
 			}
 
-			// Charge is entirely in overage
-			overageAmountDecimal := chargeAmount.Mul(overageFactor)
-			totalOverageAmount = totalOverageAmount.Add(overageAmountDecimal)
-
-			charge.Amount = price.FormatAmountToFloat64WithPrecision(overageAmountDecimal, subscription.Currency)
-			charge.DisplayAmount = overageAmountDecimal.StringFixed(6)
-			charge.IsOverage = true
-			charge.OverageFactor = overageFactorFloat
-			response.Charges = append(response.Charges, charge)
-			response.HasOverage = true
+			// Create the overage charge only if there's actual overage
+			if breakdown.HasOverage {
+				overageCharge := *charge // Create a copy
+				overageCharge.Quantity = breakdown.OverageQuantity.InexactFloat64()
+				overageCharge.Amount = price.FormatAmountToFloat64WithPrecision(breakdown.OverageCost, subscription.Currency)
+				overageCharge.DisplayAmount = price.FormatAmountToStringWithPrecision(breakdown.OverageCost, subscription.Currency)
+				overageCharge.IsOverage = true
+				overageCharge.OverageFactor = overageFactorFloat
+				response.Charges = append(response.Charges, &overageCharge)
+				response.HasOverage = true
+			}
 		}
 
 		// Calculate final amounts for response
@@ -4380,22 +4366,29 @@ func (s *subscriptionService) GetFeatureUsageBySubscription(ctx context.Context,
 		// Calculate quantity based on meter aggregation type
 		var quantity decimal.Decimal
 		switch meter.Aggregation.Type {
-		case types.AggregationSum, types.AggregationSumWithMultiplier, types.AggregationWeightedSum:
+		case types.AggregationSum, types.AggregationSumWithMultiplier, types.AggregationWeightedSum, types.AggregationCounterDelta:
+			// Usage of counter delta meters is already recorded as the increase of each reading
 			quantity = usageResult.SumTotal
 		case types.AggregationMax:
 			quantity = usageResult.MaxTotal
 		case types.AggregationCount:
 			quantity = decimal.NewFromInt(int64(usageResult.CountDistinctIDs))
-		case types.AggregationCountUnique:
+		case types.AggregationCountUnique, types.AggregationCountOncePerPeriod:
 			quantity = decimal.NewFromInt(int64(usageResult.CountUniqueQty))
+		case types.AggregationUptime:
+			// Active duration can't exceed the period it is billed for
+			quantity = meterDomain.UptimeUsage(usageResult.CountUniqueQty, meter.Aggregation.HeartbeatInterval(), usageEndTime.Sub(usageStartTime))
 		case types.AggregationLatest:
 			quantity = usageResult.LatestQty
 		default:
 			quantity = usageResult.SumTotal // Default to sum
 		}
 
-		// Calculate cost using the price service
+		// Calculate cost using the price service, usage of shadow meters is reported without being billed
 		cost := priceService.CalculateCost(ctx, priceObj, quantity)
+		if meter.ToMeter().IsShadow() {
+			cost = decimal.Zero
+		}
 		totalCost = totalCost.Add(cost)
 
 		// Create charge response
@@ -4512,80 +4505,46 @@ func (s *subscriptionService) GetFeatureUsageBySubscription(ctx context.Context,
 		totalOverageAmount := decimal.Zero
 
 		for _, charge := range usageOnlyCharges {
-			// Get charge amount as decimal for precise calculations
-			chargeAmount := decimal.NewFromFloat(charge.Amount)
-			pricePerUnit := decimal.Zero
-			if charge.Price != nil && charge.Price.BillingModel == types.BILLING_MODEL_FLAT_FEE {
-				pricePerUnit = charge.Price.Amount
-			} else if charge.Quantity > 0 {
-				pricePerUnit = chargeAmount.Div(decimal.NewFromFloat(charge.Quantity))
-			}
+			breakdown := priceService.CalculateEffectiveCost(ctx, dto.EffectiveCostRequest{
+				Price:    charge.Price,
+				Quantity: decimal.NewFromFloat(charge.Quantity),
+				BaseCost: lo.ToPtr(decimal.NewFromFloat(charge.Amount)),
+				Commitment: &dto.EffectiveCostCommitment{
+					Remaining:     remainingCommitment,
+					OverageFactor: overageFactor,
+				},
+			})
+			remainingCommitment = breakdown.RemainingCommitment
+			totalOverageAmount = totalOverageAmount.Add(breakdown.OverageCost)
 
 			// Normal price covers all of this charge
-			if remainingCommitment.GreaterThanOrEqual(chargeAmount) {
+			if breakdown.CommittedQuantity.Equal(breakdown.Quantity) && !breakdown.HasOverage {
 				charge.IsOverage = false
-				remainingCommitment = remainingCommitment.Sub(chargeAmount)
 				finalCharges = append(finalCharges, charge)
 				continue
 			}
 
-			// Charge needs to be split between normal and overage
-			if remainingCommitment.GreaterThan(decimal.Zero) {
-				// Calculate exact quantity that can be covered by remaining commitment
-				var normalQuantityDecimal decimal.Decimal
-
-				if !pricePerUnit.IsZero() {
-					normalQuantityDecimal = remainingCommitment.Div(pricePerUnit)
-					// Round down to ensure we don't exceed commitment
-					normalQuantityDecimal = normalQuantityDecimal.Floor()
-				}
-
-				// Calculate the normal amount based on the normal quantity
-				normalAmountDecimal := normalQuantityDecimal.Mul(pricePerUnit)
-
-				// Create the normal charge
-				if normalQuantityDecimal.GreaterThan(decimal.Zero) {
-					normalCharge := *charge // Create a copy
-					normalCharge.Quantity = normalQuantityDecimal.InexactFloat64()
-					normalCharge.Amount = price.FormatAmountToFloat64WithPrecision(normalAmountDecimal, subscription.Currency)
-					normalCharge.DisplayAmount = price.FormatAmountToStringWithPrecision(normalAmountDecimal, subscription.Currency)
-					normalCharge.IsOverage = false
-					finalCharges = append(finalCharges, &normalCharge)
-				}
-
-				// Calculate overage quantity and amount
-				overageQuantityDecimal := decimal.NewFromFloat(charge.Quantity).Sub(normalQuantityDecimal)
-
-				// Create the overage charge only if there's actual overage
-				if overageQuantityDecimal.GreaterThan(decimal.Zero) {
-					overageAmountDecimal := overageQuantityDecimal.Mul(pricePerUnit).Mul(overageFactor)
-					totalOverageAmount = totalOverageAmount.Add(overageAmountDecimal)
-
-					overageCharge := *charge // Create a copy
-					overageCharge.Quantity = overageQuantityDecimal.InexactFloat64()
-					overageCharge.Amount = price.FormatAmountToFloat64WithPrecision(overageAmountDecimal, subscription.Currency)
-					overageCharge.DisplayAmount = price.GetDisplayAmountWithPrecision(overageAmountDecimal, subscription.Currency)
-					overageCharge.IsOverage = true
-					overageCharge.OverageFactor = overageFactorFloat
-					finalCharges = append(finalCharges, &overageCharge)
-					response.HasOverage = true
-				}
-
-				// Update remaining commitment (should be zero or very close to it due to rounding)
-				remainingCommitment = remainingCommitment.Sub(normalAmountDecimal)
-				continue
+			// Create the normal charge for the quantity covered by the commitment
+			if breakdown.CommittedQuantity.GreaterThan(decimal.Zero) {
+				normalCharge := *charge // Create a copy
+				normalCharge.Quantity = breakdown.CommittedQuantity.InexactFloat64()
+				normalCharge.Amount = price.FormatAmountToFloat64WithPrecision(breakdown.CommitmentUtilized, subscription.Currency)
+				normalCharge.DisplayAmount = price.FormatAmountToStringWithPrecision(breakdown.CommitmentUtilized, subscription.Currency)
+				normalCharge.IsOverage = false
+				finalCharges = append(finalCharges, &normalCharge)
 			}
 
-			// Charge is entirely in overage
-			overageAmountDecimal := chargeAmount.Mul(overageFactor)
-			totalOverageAmount = totalOverageAmount.Add(overageAmountDecimal)
-
-			charge.Amount = price.FormatAmountToFloat64WithPrecision(overageAmountDecimal, subscription.Currency)
-			charge.DisplayAmount = overageAmountDecimal.StringFixed(6)
-			charge.IsOverage = true
-			charge.OverageFactor = overageFactorFloat
-			finalCharges = append(finalCharges, charge)
-			response.HasOverage = true
+			// Create the overage charge only if there's actual overage
+			if breakdown.HasOverage {
+				overageCharge := *charge // Create a copy
+				overageCharge.Quantity = breakdown.OverageQuantity.InexactFloat64()
+				overageCharge.Amount = price.FormatAmountToFloat64WithPrecision(breakdown.OverageCost, subscription.Currency)
+				overageCharge.DisplayAmount = price.GetDisplayAmountWithPrecision(breakdown.OverageCost, subscription.Currency)
+				overageCharge.IsOverage = true
+				overageCharge.OverageFactor = overageFactorFloat
+				finalCharges = append(finalCharges, &overageCharge)
+				response.HasOverage = true
+			}
 		}
 
 		// Calculate final amounts for response
@@ -84,6 +84,36 @@ func TestCreateSubscriptionWithPriceOverrides(t *testing.T) {
 		assert.Contains(t, err.Error(), "at least one override field")
 	})
 
+	t.Run("should validate discount percent overrides", func(t *testing.T) {
+		discount := decimal.NewFromInt(10)
+		override := dto.OverrideLineItemRequest{
+			PriceID:         "test_price",
+			DiscountPercent: &discount,
+		}
+		require.NoError(t, override.Validate(nil, nil, ""))
+
+		// Discount must be within (0, 100]
+		for _, invalid := range []decimal.Decimal{decimal.Zero, decimal.NewFromInt(-5), decimal.NewFromInt(101)} {
+			override = dto.OverrideLineItemRequest{
+				PriceID:         "test_price",
+				DiscountPercent: &invalid,
+			}
+			err := override.Validate(nil, nil, "")
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "discount_percent must be greater than 0")
+		}
+
+		// Discount inherits the parent's tiers, so it can't redefine them
+		override = dto.OverrideLineItemRequest{
+			PriceID:         "test_price",
+			DiscountPercent: &discount,
+			TierMode:        types.BILLING_TIER_SLAB,
+		}
+		err := override.Validate(nil, nil, "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be combined")
+	})
+
 	t.Run("should reject duplicate price IDs in overrides", func(t *testing.T) {
 		amount1 := decimal.NewFromFloat(10.00)
 		amount2 := decimal.NewFromFloat(20.00)
@@ -4561,6 +4561,59 @@ func (s *SubscriptionServiceSuite) TestPriceOverrideIntegration() {
 		s.T().Logf("✅ Integration test passed: Subscription created with overrides and subscription-scoped prices verified")
 	})
 
+	s.Run("create_subscription_with_discount_override_inherits_parent_tiers", func() {
+		parent := s.testData.prices.apiCalls
+		req := dto.CreateSubscriptionRequest{
+			CustomerID:         s.testData.customer.ID,
+			PlanID:             s.testData.plan.ID,
+			Currency:           "usd",
+			BillingCadence:     types.BILLING_CADENCE_RECURRING,
+			BillingPeriod:      types.BILLING_PERIOD_MONTHLY,
+			BillingPeriodCount: 1,
+			BillingCycle:       types.BillingCycleAnniversary,
+			OverrideLineItems: []dto.OverrideLineItemRequest{
+				{
+					PriceID:         parent.ID,
+					DiscountPercent: lo.ToPtr(decimal.NewFromInt(10)),
+				},
+			},
+		}
+
+		resp, err := s.service.CreateSubscription(s.GetContext(), req)
+		s.NoError(err)
+		s.NotNil(resp)
+
+		prices, err := s.GetStores().PriceRepo.List(s.GetContext(), types.NewNoLimitPriceFilter().
+			WithEntityIDs([]string{resp.ID}).
+			WithEntityType(types.PRICE_ENTITY_TYPE_SUBSCRIPTION))
+		s.NoError(err)
+		s.Len(prices, 1)
+
+		override := prices[0]
+		s.Equal(parent.ID, override.ParentPriceID)
+		s.Equal(parent.TierMode, override.TierMode)
+		s.Len(override.Tiers, len(parent.Tiers))
+		for i, tier := range parent.Tiers {
+			s.Equal(tier.UpTo, override.Tiers[i].UpTo)
+			s.True(tier.UnitAmount.Equal(override.Tiers[i].UnitAmount))
+		}
+		s.NotNil(override.DiscountPercent)
+		s.True(decimal.NewFromInt(10).Equal(*override.DiscountPercent))
+
+		// The discount applies to the output of every tier of the parent
+		priceService := NewPriceService(ServiceParams{
+			PriceRepo: s.GetStores().PriceRepo,
+			MeterRepo: s.GetStores().MeterRepo,
+			Logger:    s.GetLogger(),
+		})
+		for _, quantity := range []int64{500, 3000, 8000} {
+			parentCost := priceService.CalculateCost(s.GetContext(), parent, decimal.NewFromInt(quantity))
+			overrideCost := priceService.CalculateCost(s.GetContext(), override, decimal.NewFromInt(quantity))
+			s.True(parentCost.Mul(decimal.NewFromFloat(0.9)).Equal(overrideCost),
+				"quantity %d: expected 90%% of %s, got %s", quantity, parentCost, overrideCost)
+		}
+	})
+
 	s.Run("create_multiple_subscriptions_with_different_overrides", func() {
 		// Test creating multiple subscriptions with different overrides on the same plan
 		overrideScenarios := []dto.OverrideLineItemRequest{
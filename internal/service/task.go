@@ -472,6 +472,10 @@ func (p *EventsChunkProcessor) batchCreateEvents(ctx context.Context, events []*
 	return totalSuccessCount, nil
 }
 
+// eventsRateLimitRetries is the number of times a batch of events rejected by the ingestion rate
+// limit is retried
+const eventsRateLimitRetries = 5
+
 // processBatch processes a single batch of events using the bulk API
 func (p *EventsChunkProcessor) processBatch(ctx context.Context, batch []*dto.IngestEventRequest) (int, int) {
 	// Create bulk request
@@ -479,8 +483,17 @@ func (p *EventsChunkProcessor) processBatch(ctx context.Context, batch []*dto.In
 		Events: batch,
 	}
 
-	// Use bulk API for better performance
-	if err := p.eventService.BulkCreateEvents(ctx, bulkRequest); err != nil {
+	// Use bulk API for better performance, backing off while the customers are rate limited
+	err := p.eventService.BulkCreateEvents(ctx, bulkRequest)
+	for attempt := 1; ierr.IsTooManyRequests(err) && attempt <= eventsRateLimitRetries; attempt++ {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(time.Duration(attempt) * time.Second):
+			err = p.eventService.BulkCreateEvents(ctx, bulkRequest)
+		}
+	}
+	if err != nil {
 		p.logger.Errorw("bulk event creation failed",
 			"batch_size", len(batch),
 			"error", err)
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/shopspring/decimal"
+)
+
+// defaultMovingAveragePeriods is the number of latest periods averaged when none is requested
+const defaultMovingAveragePeriods = 3
+
+// UsageForecaster forecasts the usage of the next period from the usage of the previous
+// periods, oldest first
+type UsageForecaster interface {
+	Forecast(history []decimal.Decimal) decimal.Decimal
+}
+
+// usageForecasters creates the forecaster of each model, periods is the number of latest periods
+// the model looks at, 0 for its default. New models only need to be registered here.
+var usageForecasters = map[types.UsageForecastModel]func(periods int) UsageForecaster{
+	types.UsageForecastModelMovingAverage: func(periods int) UsageForecaster {
+		if periods <= 0 {
+			periods = defaultMovingAveragePeriods
+		}
+		return movingAverageForecaster{periods: periods}
+	},
+	types.UsageForecastModelLinearTrend: func(periods int) UsageForecaster {
+		return linearTrendForecaster{periods: periods}
+	},
+}
+
+// NewUsageForecaster returns the forecaster of the model
+func NewUsageForecaster(model types.UsageForecastModel, periods int) (UsageForecaster, error) {
+	if err := model.Validate(); err != nil {
+		return nil, err
+	}
+	return usageForecasters[model](periods), nil
+}
+
+// latestPeriods returns the latest periods of the history, all of them when periods is 0
+func latestPeriods(history []decimal.Decimal, periods int) []decimal.Decimal {
+	if periods <= 0 || periods >= len(history) {
+		return history
+	}
+	return history[len(history)-periods:]
+}
+
+// movingAverageForecaster forecasts the average usage of the latest periods
+type movingAverageForecaster struct {
+	periods int
+}
+
+func (f movingAverageForecaster) Forecast(history []decimal.Decimal) decimal.Decimal {
+	history = latestPeriods(history, f.periods)
+	if len(history) == 0 {
+		return decimal.Zero
+	}
+	return decimal.Sum(decimal.Zero, history...).Div(decimal.NewFromInt(int64(len(history))))
+}
+
+// linearTrendForecaster fits a least squares line through the usage of the latest periods and
+// extends it to the next period. Usage can't be negative so a falling trend stops at zero.
+type linearTrendForecaster struct {
+	periods int
+}
+
+func (f linearTrendForecaster) Forecast(history []decimal.Decimal) decimal.Decimal {
+	history = latestPeriods(history, f.periods)
+	n := int64(len(history))
+	switch n {
+	case 0:
+		return decimal.Zero
+	case 1:
+		return history[0]
+	}
+
+	// The periods are numbered 0 to n-1, the next period is n
+	var sumX, sumXX int64
+	sumY, sumXY := decimal.Zero, decimal.Zero
+	for i, usage := range history {
+		x := int64(i)
+		sumX += x
+		sumXX += x * x
+		sumY = sumY.Add(usage)
+		sumXY = sumXY.Add(usage.Mul(decimal.NewFromInt(x)))
+	}
+
+	count := decimal.NewFromInt(n)
+	slope := count.Mul(sumXY).Sub(decimal.NewFromInt(sumX).Mul(sumY)).
+		Div(decimal.NewFromInt(n*sumXX - sumX*sumX))
+	intercept := sumY.Sub(slope.Mul(decimal.NewFromInt(sumX))).Div(count)
+
+	forecast := intercept.Add(slope.Mul(count))
+	if forecast.IsNegative() {
+		return decimal.Zero
+	}
+	return forecast
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func usageHistory(values ...int64) []decimal.Decimal {
+	return lo.Map(values, func(v int64, _ int) decimal.Decimal { return decimal.NewFromInt(v) })
+}
+
+func TestUsageForecaster(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    types.UsageForecastModel
+		periods  int
+		history  []decimal.Decimal
+		expected decimal.Decimal
+	}{
+		{
+			name:     "moving average of the latest three periods by default",
+			model:    types.UsageForecastModelMovingAverage,
+			history:  usageHistory(100, 10, 20, 30),
+			expected: decimal.NewFromInt(20),
+		},
+		{
+			name:     "moving average of the requested periods",
+			model:    types.UsageForecastModelMovingAverage,
+			periods:  2,
+			history:  usageHistory(10, 20, 30, 50),
+			expected: decimal.NewFromInt(40),
+		},
+		{
+			name:     "moving average of a short history",
+			model:    types.UsageForecastModelMovingAverage,
+			periods:  5,
+			history:  usageHistory(10, 20),
+			expected: decimal.NewFromInt(15),
+		},
+		{
+			name:     "linear trend extends a steady increase",
+			model:    types.UsageForecastModelLinearTrend,
+			history:  usageHistory(10, 20, 30, 40),
+			expected: decimal.NewFromInt(50),
+		},
+		{
+			name:     "linear trend fits the least squares line",
+			model:    types.UsageForecastModelLinearTrend,
+			history:  usageHistory(10, 30, 20, 40),
+			expected: decimal.NewFromInt(45),
+		},
+		{
+			name:     "linear trend of the latest periods",
+			model:    types.UsageForecastModelLinearTrend,
+			periods:  3,
+			history:  usageHistory(500, 10, 20, 30),
+			expected: decimal.NewFromInt(40),
+		},
+		{
+			name:     "linear trend stops at zero",
+			model:    types.UsageForecastModelLinearTrend,
+			history:  usageHistory(30, 20, 10, 0),
+			expected: decimal.Zero,
+		},
+		{
+			name:     "linear trend of a single period",
+			model:    types.UsageForecastModelLinearTrend,
+			history:  usageHistory(25),
+			expected: decimal.NewFromInt(25),
+		},
+		{
+			name:     "no history",
+			model:    types.UsageForecastModelMovingAverage,
+			expected: decimal.Zero,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecaster, err := NewUsageForecaster(tt.model, tt.periods)
+			require.NoError(t, err)
+
+			forecast := forecaster.Forecast(tt.history)
+			assert.True(t, tt.expected.Equal(forecast), "expected %s, got %s", tt.expected, forecast)
+		})
+	}
+
+	t.Run("unknown model", func(t *testing.T) {
+		_, err := NewUsageForecaster("exponential", 0)
+		assert.Error(t, err)
+	})
+}
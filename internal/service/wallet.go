@@ -1518,8 +1518,23 @@ func (s *walletService) PublishEvent(ctx context.Context, eventName string, w *w
 		Payload:       eventJSON,
 	}
 
+	// Repeated balance alerts for the same wallet and state within the window share the key
+	if internalEvent.Alert != nil {
+		webhookEvent.IdempotencyKey = types.GenerateWebhookIdempotencyKey(
+			s.Config.Webhook.AlertDedupWindow,
+			webhookEvent.Timestamp,
+			w.TenantID,
+			w.EnvironmentID,
+			eventName,
+			w.CustomerID,
+			w.ID,
+			w.AlertState,
+		)
+	}
+
 	s.Logger.Infow("publishing webhook event",
 		"event_id", webhookEvent.ID,
+		"idempotency_key", webhookEvent.IdempotencyKey,
 		"event_name", eventName,
 		"wallet_id", w.ID,
 		"alert_state", w.AlertState,
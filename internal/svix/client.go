@@ -86,8 +86,9 @@ func (c *Client) GetDashboardURL(ctx context.Context, applicationID string) (str
 	return dashboard.Url, nil
 }
 
-// SendMessage sends a webhook message to the given application
-func (c *Client) SendMessage(ctx context.Context, applicationID string, eventType string, payload interface{}) error {
+// SendMessage sends a webhook message to the given application. Messages sent with the same
+// non-empty idempotency key are only delivered once.
+func (c *Client) SendMessage(ctx context.Context, applicationID string, eventType string, payload interface{}, idempotencyKey string) error {
 	if !c.enabled || c.client == nil {
 		return nil // Return nil if Svix is not enabled
 	}
@@ -121,10 +122,14 @@ func (c *Client) SendMessage(ctx context.Context, applicationID string, eventTyp
 	}
 
 	payloadMap["event_type"] = eventType
+	opts := &svix.MessageCreateOptions{}
+	if idempotencyKey != "" {
+		opts.IdempotencyKey = &idempotencyKey
+	}
 	_, err := c.client.Message.Create(ctx, applicationID, models.MessageIn{
 		EventType: eventType,
 		Payload:   payloadMap,
-	}, &svix.MessageCreateOptions{})
+	}, opts)
 	if err != nil {
 		// Check if application not found error
 		if err.Error() == "application not found" {
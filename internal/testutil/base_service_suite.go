@@ -8,6 +8,7 @@ import (
 	"github.com/flexprice/flexprice/internal/config"
 	"github.com/flexprice/flexprice/internal/domain/addonassociation"
 	"github.com/flexprice/flexprice/internal/domain/alertlogs"
+	"github.com/flexprice/flexprice/internal/domain/auditlog"
 	"github.com/flexprice/flexprice/internal/domain/auth"
 	"github.com/flexprice/flexprice/internal/domain/connection"
 	"github.com/flexprice/flexprice/internal/domain/coupon"
@@ -87,6 +88,9 @@ type Stores struct {
 	SettingsRepo                 settings.Repository
 	AlertLogsRepo                alertlogs.Repository
 	FeatureUsageRepo             events.FeatureUsageRepository
+	UsageCounterRepo             events.UsageCounterRepository
+	AuditLogRepo                 auditlog.Repository
+	EventDedupRepo               events.EventDedupRepository
 }
 
 // BaseServiceTestSuite provides common functionality for all service test suites
@@ -222,6 +226,9 @@ func (s *BaseServiceTestSuite) setupStores() {
 		SettingsRepo:                 NewInMemorySettingsStore(),
 		AlertLogsRepo:                NewInMemoryAlertLogsStore(),
 		FeatureUsageRepo:             NewInMemoryFeatureUsageStore(),
+		UsageCounterRepo:             NewInMemoryUsageCounterStore(),
+		AuditLogRepo:                 NewInMemoryAuditLogStore(),
+		EventDedupRepo:               NewInMemoryEventDedupStore(),
 	}
 
 	s.db = NewMockPostgresClient(s.logger)
@@ -271,6 +278,9 @@ func (s *BaseServiceTestSuite) clearStores() {
 	s.stores.SubscriptionLineItemRepo.(*InMemorySubscriptionLineItemStore).Clear()
 	s.stores.SubscriptionPhaseRepo.(*InMemorySubscriptionPhaseStore).Clear()
 	s.stores.AlertLogsRepo.(*InMemoryAlertLogsStore).Clear()
+	s.stores.UsageCounterRepo.(*InMemoryUsageCounterStore).Clear()
+	s.stores.AuditLogRepo.(*InMemoryAuditLogStore).Clear()
+	s.stores.EventDedupRepo.(*InMemoryEventDedupStore).Clear()
 }
 
 func (s *BaseServiceTestSuite) ClearStores() {
@@ -0,0 +1,60 @@
+package testutil
+
+import (
+	"context"
+	"sync"
+
+	"github.com/flexprice/flexprice/internal/domain/auditlog"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// InMemoryAuditLogStore implements an in-memory audit log repository for testing
+type InMemoryAuditLogStore struct {
+	mu   sync.RWMutex
+	logs []*auditlog.AuditLog
+}
+
+// NewInMemoryAuditLogStore creates a new in-memory audit log store
+func NewInMemoryAuditLogStore() *InMemoryAuditLogStore {
+	return &InMemoryAuditLogStore{
+		logs: make([]*auditlog.AuditLog, 0),
+	}
+}
+
+// Create stores the audit log
+func (s *InMemoryAuditLogStore) Create(ctx context.Context, auditLog *auditlog.AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logs = append(s.logs, auditLog)
+	return nil
+}
+
+// ListByEntity returns the audit logs of an entity, the latest first
+func (s *InMemoryAuditLogStore) ListByEntity(ctx context.Context, entityType types.AuditLogEntityType, entityID string, limit int) ([]*auditlog.AuditLog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*auditlog.AuditLog, 0)
+	for i := len(s.logs) - 1; i >= 0; i-- {
+		log := s.logs[i]
+		if log.TenantID != types.GetTenantID(ctx) || log.EnvironmentID != types.GetEnvironmentID(ctx) {
+			continue
+		}
+		if log.EntityType != entityType || log.EntityID != entityID {
+			continue
+		}
+		result = append(result, log)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Clear removes all audit logs
+func (s *InMemoryAuditLogStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs = make([]*auditlog.AuditLog, 0)
+}
@@ -13,6 +13,8 @@ import (
 // InMemoryCustomerStore implements customer.Repository
 type InMemoryCustomerStore struct {
 	*InMemoryStore[*customer.Customer]
+	// LookupKeyConflictPolicy resolves lookups by external id matching several customers
+	LookupKeyConflictPolicy types.CustomerLookupKeyConflictPolicy
 }
 
 // NewInMemoryCustomerStore creates a new in-memory customer store
@@ -87,16 +89,12 @@ func (s *InMemoryCustomerStore) GetByLookupKey(ctx context.Context, lookupKey st
 			Mark(ierr.ErrDatabase)
 	}
 
-	if len(customers) == 0 {
-		return nil, ierr.NewError("customer not found").
-			WithHintf("Customer with lookup key %s was not found", lookupKey).
-			WithReportableDetails(map[string]any{
-				"lookup_key": lookupKey,
-			}).
-			Mark(ierr.ErrNotFound)
+	c, err := customer.ResolveLookupKeyMatches(lookupKey, customers, s.LookupKeyConflictPolicy)
+	if err != nil {
+		return nil, err
 	}
 
-	return copyCustomer(customers[0]), nil
+	return copyCustomer(c), nil
 }
 
 func (s *InMemoryCustomerStore) List(ctx context.Context, filter *types.CustomerFilter) ([]*customer.Customer, error) {
@@ -151,6 +149,10 @@ func customerFilterFn(ctx context.Context, c *customer.Customer, filter interfac
 		return false
 	}
 
+	if len(f.ExternalIDs) > 0 && !lo.Contains(f.ExternalIDs, c.ExternalID) {
+		return false
+	}
+
 	// Apply parent customer ID filter
 	if len(f.ParentCustomerIDs) > 0 {
 		if c.ParentCustomerID == nil {
@@ -0,0 +1,71 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// InMemoryEventDedupStore implements an in-memory event dedup repository for testing
+type InMemoryEventDedupStore struct {
+	mu        sync.Mutex
+	expiresAt map[string]time.Time
+}
+
+// NewInMemoryEventDedupStore creates a new in-memory event dedup store
+func NewInMemoryEventDedupStore() *InMemoryEventDedupStore {
+	return &InMemoryEventDedupStore{
+		expiresAt: make(map[string]time.Time),
+	}
+}
+
+func eventDedupKey(ctx context.Context, eventID string) string {
+	return fmt.Sprintf("%s:%s:%s", types.GetTenantID(ctx), types.GetEnvironmentID(ctx), eventID)
+}
+
+// Claim records the event id until expiresAt unless it's recorded and hasn't expired
+func (s *InMemoryEventDedupStore) Claim(ctx context.Context, eventID string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := eventDedupKey(ctx, eventID)
+	if existing, ok := s.expiresAt[key]; ok && existing.After(time.Now()) {
+		return false, nil
+	}
+	s.expiresAt[key] = expiresAt
+	return true, nil
+}
+
+// Release removes the record of the event id
+func (s *InMemoryEventDedupStore) Release(ctx context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.expiresAt, eventDedupKey(ctx, eventID))
+	return nil
+}
+
+// DeleteExpired removes the records that expired before the time
+func (s *InMemoryEventDedupStore) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for key, expiresAt := range s.expiresAt {
+		if expiresAt.Before(before) {
+			delete(s.expiresAt, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// Clear removes all records
+func (s *InMemoryEventDedupStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expiresAt = make(map[string]time.Time)
+}
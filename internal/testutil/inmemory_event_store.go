@@ -6,6 +6,7 @@ import (
 	"log"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -53,48 +54,57 @@ func (s *InMemoryEventStore) GetUsage(ctx context.Context, params *events.UsageP
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var filteredEvents []*events.Event
-
-	// Filter events based on basic criteria
-	for _, event := range s.events {
-		if event.EventName != params.EventName {
-			continue
+	// matches reports whether the event belongs to the usage regardless of its timestamp
+	matches := func(event *events.Event) bool {
+		if !params.MatchesEventName(event.EventName) {
+			return false
 		}
 
 		if params.ExternalCustomerID != "" && event.ExternalCustomerID != params.ExternalCustomerID {
-			continue
+			return false
 		}
 
-		if event.Timestamp.Before(params.StartTime) || event.Timestamp.After(params.EndTime) {
-			continue
+		// Skip the events routed to another subscription
+		if params.SubscriptionID != "" && params.SubscriptionIDProperty != "" {
+			if value, ok := event.GetNestedProperty(params.SubscriptionIDProperty); ok {
+				if subscriptionID, ok := value.(string); ok {
+					if subscriptionID = strings.TrimSpace(subscriptionID); subscriptionID != "" && subscriptionID != params.SubscriptionID {
+						return false
+					}
+				}
+			}
+		}
+
+		// Skip the events timestamped before the customer was created unless they're billed in the period
+		if params.ExcludesPreCustomerCreationEvents() && event.Timestamp.Before(*params.CustomerCreatedAt) {
+			return false
 		}
 
 		// Apply property filters
-		matchesFilters := true
 		for key, expectedValues := range params.Filters {
-			if propertyValue, exists := event.Properties[key]; exists {
-				valueStr := fmt.Sprintf("%v", propertyValue)
-				valueMatches := false
-				for _, expectedValue := range expectedValues {
-					if valueStr == expectedValue {
-						valueMatches = true
-						break
-					}
-				}
-				if !valueMatches {
-					matchesFilters = false
-					break
-				}
-			} else {
-				matchesFilters = false
-				break
+			propertyValue, exists := event.Properties[key]
+			if !exists || !lo.Contains(expectedValues, fmt.Sprintf("%v", propertyValue)) {
+				return false
 			}
 		}
+		return true
+	}
 
-		if matchesFilters {
+	// clamped reports whether the event is timestamped before the customer was created and clamped into the period
+	clamped := func(event *events.Event) bool {
+		return params.IncludesPreCustomerCreationEvents() && event.Timestamp.Before(*params.CustomerCreatedAt)
+	}
+
+	var filteredEvents []*events.Event
+	for _, event := range s.events {
+		if (event.Timestamp.Before(params.StartTime) && !clamped(event)) || event.Timestamp.After(params.EndTime) {
+			continue
+		}
+		if matches(event) {
 			filteredEvents = append(filteredEvents, event)
 		}
 	}
+	filteredEvents = boundEventValues(filteredEvents, params)
 
 	// Calculate aggregation
 	result := &events.AggregationResult{
@@ -276,6 +286,59 @@ func (s *InMemoryEventStore) GetUsage(ctx context.Context, params *events.UsageP
 	switch params.AggregationType {
 	case types.AggregationCount:
 		result.Value = decimal.NewFromInt(int64(len(filteredEvents)))
+	case types.AggregationCountOncePerPeriod:
+		customers := make(map[string]struct{})
+		for _, event := range filteredEvents {
+			customers[event.ExternalCustomerID] = struct{}{}
+		}
+		result.Value = decimal.NewFromInt(int64(len(customers)))
+	case types.AggregationCounterDelta:
+		// Readings before the start time are the previous readings of the first readings in range
+		var readings []*events.Event
+		for _, event := range s.events {
+			if event.Timestamp.Before(params.EndTime) && matches(event) {
+				readings = append(readings, event)
+			}
+		}
+		sort.Slice(readings, func(i, j int) bool {
+			if !readings[i].Timestamp.Equal(readings[j].Timestamp) {
+				return readings[i].Timestamp.Before(readings[j].Timestamp)
+			}
+			return readings[i].ID < readings[j].ID
+		})
+
+		var total decimal.Decimal
+		previous := make(map[string]decimal.Decimal)
+		for _, event := range readings {
+			reading := decimal.Zero
+			if val, ok := event.Properties[params.PropertyName]; ok {
+				if f, err := strconv.ParseFloat(fmt.Sprintf("%v", val), 64); err == nil {
+					reading = decimal.NewFromFloat(f)
+				}
+			}
+			if previousReading, ok := previous[event.ExternalCustomerID]; ok && !event.Timestamp.Before(params.StartTime) {
+				if reading.LessThan(previousReading) {
+					total = total.Add(reading)
+				} else {
+					total = total.Add(reading.Sub(previousReading))
+				}
+			}
+			previous[event.ExternalCustomerID] = reading
+		}
+		result.Value = total
+	case types.AggregationUptime:
+		if params.HeartbeatIntervalSeconds <= 0 {
+			break
+		}
+		intervals := make(map[string]struct{})
+		for _, event := range filteredEvents {
+			intervals[fmt.Sprintf("%s:%d", event.ExternalCustomerID, event.Timestamp.Unix()/params.HeartbeatIntervalSeconds)] = struct{}{}
+		}
+		active := int64(len(intervals)) * params.HeartbeatIntervalSeconds
+		if period := int64(params.EndTime.Sub(params.StartTime).Seconds()); active > period {
+			active = period
+		}
+		result.Value = decimal.NewFromInt(active)
 	case types.AggregationSum:
 		var sum decimal.Decimal
 		for _, event := range filteredEvents {
@@ -295,6 +358,31 @@ func (s *InMemoryEventStore) GetUsage(ctx context.Context, params *events.UsageP
 			}
 		}
 		result.Value = sum
+	case types.AggregationSumWithMultiplier:
+		// Each event is scaled by its rate in the rate table, or the static multiplier
+		var sum decimal.Decimal
+		for _, event := range filteredEvents {
+			val, ok := event.Properties[params.PropertyName]
+			if !ok {
+				continue
+			}
+			value, err := decimal.NewFromString(fmt.Sprintf("%v", val))
+			if err != nil {
+				continue
+			}
+
+			multiplier := decimal.NewFromInt(1)
+			if params.Multiplier != nil {
+				multiplier = *params.Multiplier
+			}
+			if rateValue, ok := event.Properties[params.RateProperty]; ok && params.RateProperty != "" {
+				if rate, ok := params.RateTable[fmt.Sprint(rateValue)]; ok {
+					multiplier = rate
+				}
+			}
+			sum = sum.Add(value.Mul(multiplier))
+		}
+		result.Value = sum
 	case types.AggregationMax:
 		// Simple max across all filtered events
 		var maxVal decimal.Decimal
@@ -332,6 +420,12 @@ func (s *InMemoryEventStore) GetUsage(ctx context.Context, params *events.UsageP
 func truncateToBucket(t time.Time, size types.WindowSize) time.Time {
 	t = t.UTC()
 	switch size {
+	case types.WindowSizeSecond:
+		return t.Truncate(time.Second)
+	case types.WindowSize10Sec:
+		return t.Truncate(10 * time.Second)
+	case types.WindowSize30Sec:
+		return t.Truncate(30 * time.Second)
 	case types.WindowSizeMinute:
 		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
 	case types.WindowSize15Min:
@@ -584,6 +678,24 @@ func (s *InMemoryEventStore) GetDistinctEventNames(ctx context.Context, external
 	return eventNames, nil
 }
 
+func (s *InMemoryEventStore) GetEventsByIDs(ctx context.Context, eventIDs []string) ([]*events.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*events.Event, 0, len(eventIDs))
+	for _, id := range lo.Uniq(eventIDs) {
+		if event, ok := s.events[id]; ok {
+			result = append(result, event)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.After(result[j].Timestamp)
+	})
+
+	return result, nil
+}
+
 func (s *InMemoryEventStore) matchesBaseFilters(ctx context.Context, event *events.Event, params *events.UsageParams) bool {
 	// check tenant ID
 	tenantID := types.GetTenantID(ctx)
@@ -597,7 +709,7 @@ func (s *InMemoryEventStore) matchesBaseFilters(ctx context.Context, event *even
 	}
 
 	// Check event name
-	if event.EventName != params.EventName {
+	if !params.MatchesEventName(event.EventName) {
 		return false
 	}
 
@@ -735,9 +847,135 @@ func (s *InMemoryEventStore) GetTotalEventCount(ctx context.Context, startTime,
 	return result, nil
 }
 
+// boundEventValues applies the minimum and maximum event value of the usage to the events that have
+// the field, the values below the minimum are raised to it and the ones above the maximum are
+// dropped or lowered to it according to the policy
+func boundEventValues(evts []*events.Event, params *events.UsageParams) []*events.Event {
+	if (params.MinEventValue == nil && params.MaxEventValue == nil) || params.PropertyName == "" {
+		return evts
+	}
+
+	bounded := make([]*events.Event, 0, len(evts))
+	for _, event := range evts {
+		val, ok := event.Properties[params.PropertyName]
+		if !ok {
+			bounded = append(bounded, event)
+			continue
+		}
+		value, err := decimal.NewFromString(fmt.Sprintf("%v", val))
+		if err != nil {
+			value = decimal.Zero
+		}
+
+		boundedValue := value
+		if params.MinEventValue != nil {
+			boundedValue = decimal.Max(boundedValue, *params.MinEventValue)
+		}
+		if params.MaxEventValue != nil && boundedValue.GreaterThan(*params.MaxEventValue) {
+			if params.MaxEventValuePolicy == types.MaxEventValuePolicyReject {
+				continue
+			}
+			if params.MaxEventValuePolicy == types.MaxEventValuePolicyClamp {
+				boundedValue = *params.MaxEventValue
+			}
+		}
+
+		if !boundedValue.Equal(value) {
+			copied := *event
+			copied.Properties = lo.Assign(event.Properties, map[string]interface{}{
+				params.PropertyName: boundedValue.InexactFloat64(),
+			})
+			event = &copied
+		}
+		bounded = append(bounded, event)
+	}
+	return bounded
+}
+
+// GetClockSkewStats summarizes the delay between the timestamp and the ingestion of the events
+// ingested in the time range per customer and source, the most skewed producers first
+func (s *InMemoryEventStore) GetClockSkewStats(ctx context.Context, params *events.ClockSkewParams) ([]*events.ClockSkewStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type producer struct {
+		externalCustomerID string
+		source             string
+	}
+	skews := make(map[producer][]time.Duration)
+	for _, event := range s.events {
+		if event.TenantID != types.GetTenantID(ctx) || event.EnvironmentID != types.GetEnvironmentID(ctx) {
+			continue
+		}
+		if event.IngestedAt.Before(params.StartTime) || !event.IngestedAt.Before(params.EndTime) {
+			continue
+		}
+		if params.MaxSkew > 0 && (event.Timestamp.Before(params.StartTime.Add(-params.MaxSkew)) ||
+			!event.Timestamp.Before(params.EndTime.Add(params.MaxSkew))) {
+			continue
+		}
+		if params.ExternalCustomerID != "" && event.ExternalCustomerID != params.ExternalCustomerID {
+			continue
+		}
+		if params.Source != "" && event.Source != params.Source {
+			continue
+		}
+
+		key := producer{externalCustomerID: event.ExternalCustomerID, source: event.Source}
+		skews[key] = append(skews[key], event.IngestedAt.Sub(event.Timestamp))
+	}
+
+	stats := make([]*events.ClockSkewStats, 0, len(skews))
+	for key, values := range skews {
+		if len(values) < params.MinEvents {
+			continue
+		}
+
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		quantile := func(q float64) time.Duration {
+			return values[int(q*float64(len(values)-1))]
+		}
+		stats = append(stats, &events.ClockSkewStats{
+			ExternalCustomerID: key.externalCustomerID,
+			Source:             key.source,
+			EventCount:         uint64(len(values)),
+			Avg:                lo.Sum(values) / time.Duration(len(values)),
+			Min:                values[0],
+			P10:                quantile(0.1),
+			P50:                quantile(0.5),
+			P90:                quantile(0.9),
+			Max:                values[len(values)-1],
+		})
+	}
+
+	abs := func(d time.Duration) time.Duration { return max(d, -d) }
+	sort.Slice(stats, func(i, j int) bool {
+		if abs(stats[i].P50) != abs(stats[j].P50) {
+			return abs(stats[i].P50) > abs(stats[j].P50)
+		}
+		if stats[i].ExternalCustomerID != stats[j].ExternalCustomerID {
+			return stats[i].ExternalCustomerID < stats[j].ExternalCustomerID
+		}
+		return stats[i].Source < stats[j].Source
+	})
+
+	if params.Limit > 0 && len(stats) > params.Limit {
+		stats = stats[:params.Limit]
+	}
+	return stats, nil
+}
+
 // getWindowStart returns the start of the time window for a given timestamp
 func (s *InMemoryEventStore) getWindowStart(t time.Time, windowSize types.WindowSize) time.Time {
 	switch windowSize {
+	case types.WindowSizeSecond:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+	case types.WindowSize10Sec:
+		second := (t.Second() / 10) * 10
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), second, 0, t.Location())
+	case types.WindowSize30Sec:
+		second := (t.Second() / 30) * 30
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), second, 0, t.Location())
 	case types.WindowSizeMinute:
 		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
 	case types.WindowSize15Min:
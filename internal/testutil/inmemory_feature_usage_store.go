@@ -3,18 +3,30 @@ package testutil
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
 )
 
 type InMemoryFeatureUsageStore struct {
-	mu    sync.RWMutex
+	mu sync.RWMutex
+	// usage is keyed like the rows the feature_usage table replaces, so a row inserted again with
+	// another customer, line item or period is kept next to the row it was copied from
 	usage map[string]*events.FeatureUsage
 }
 
+// featureUsageKey is the key of the feature usage row among the columns of the table's sorting key
+func featureUsageKey(usage *events.FeatureUsage) string {
+	return fmt.Sprintf("%s|%s|%s|%d", usage.ID, usage.CustomerID, usage.SubLineItemID, usage.PeriodID)
+}
+
 func NewInMemoryFeatureUsageStore() *InMemoryFeatureUsageStore {
 	return &InMemoryFeatureUsageStore{
 		usage: make(map[string]*events.FeatureUsage),
@@ -24,18 +36,48 @@ func NewInMemoryFeatureUsageStore() *InMemoryFeatureUsageStore {
 func (s *InMemoryFeatureUsageStore) Create(ctx context.Context, featureUsage *events.FeatureUsage) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.usage[featureUsage.ID] = featureUsage
+	s.usage[featureUsageKey(featureUsage)] = featureUsage
 	return nil
 }
 
+// Get returns a live row of the event, else a cancelled one
 func (s *InMemoryFeatureUsageStore) Get(ctx context.Context, id string) (*events.FeatureUsage, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	usage, exists := s.usage[id]
-	if !exists {
+
+	var found *events.FeatureUsage
+	for _, usage := range s.usage {
+		if usage.ID != id {
+			continue
+		}
+		if found == nil || (found.Sign == 0 && usage.Sign != 0) {
+			found = usage
+		}
+	}
+	if found == nil {
 		return nil, errors.New("feature usage not found")
 	}
-	return usage, nil
+	return found, nil
+}
+
+// Rows returns every stored row of the event, live and cancelled, ordered by customer
+func (s *InMemoryFeatureUsageStore) Rows(id string) []*events.FeatureUsage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := make([]*events.FeatureUsage, 0)
+	for _, usage := range s.usage {
+		if usage.ID == id {
+			rows = append(rows, usage)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].CustomerID != rows[j].CustomerID {
+			return rows[i].CustomerID < rows[j].CustomerID
+		}
+		return featureUsageKey(rows[i]) < featureUsageKey(rows[j])
+	})
+	return rows
 }
 
 func (s *InMemoryFeatureUsageStore) Clear() {
@@ -48,7 +90,7 @@ func (s *InMemoryFeatureUsageStore) Clear() {
 func (s *InMemoryFeatureUsageStore) InsertProcessedEvent(ctx context.Context, event *events.FeatureUsage) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.usage[event.ID] = event
+	s.usage[featureUsageKey(event)] = event
 	return nil
 }
 
@@ -57,7 +99,47 @@ func (s *InMemoryFeatureUsageStore) BulkInsertProcessedEvents(ctx context.Contex
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	for _, event := range events {
-		s.usage[event.ID] = event
+		s.usage[featureUsageKey(event)] = event
+	}
+	return nil
+}
+
+// GetLatestProcessedEventBefore returns the latest usage of the customer's meter before the given time
+func (s *InMemoryFeatureUsageStore) GetLatestProcessedEventBefore(ctx context.Context, customerID, meterID string, before time.Time) (*events.FeatureUsage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *events.FeatureUsage
+	for _, usage := range s.usage {
+		if usage.Sign == 0 || usage.CustomerID != customerID || usage.MeterID != meterID || !usage.Timestamp.Before(before) {
+			continue
+		}
+		if latest == nil || usage.Timestamp.After(latest.Timestamp) ||
+			(usage.Timestamp.Equal(latest.Timestamp) && usage.ID > latest.ID) {
+			latest = usage
+		}
+	}
+	return latest, nil
+}
+
+// ReplaceProcessedEvents stores copies of the cancelled rows with sign 0 and the replacements
+func (s *InMemoryFeatureUsageStore) ReplaceProcessedEvents(ctx context.Context, cancelled, replacements []*events.FeatureUsage) error {
+	if len(cancelled) != len(replacements) {
+		return errors.New("every cancelled feature usage row needs a replacement")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range cancelled {
+		cancelledRow := *cancelled[i]
+		cancelledRow.Sign = 0
+		s.usage[featureUsageKey(&cancelledRow)] = &cancelledRow
+
+		replacement := *replacements[i]
+		if replacement.Sign == 0 {
+			replacement.Sign = 1
+		}
+		s.usage[featureUsageKey(&replacement)] = &replacement
 	}
 	return nil
 }
@@ -69,9 +151,44 @@ func (s *InMemoryFeatureUsageStore) GetProcessedEvents(ctx context.Context, para
 
 	result := make([]*events.FeatureUsage, 0)
 	for _, usage := range s.usage {
+		if usage.Sign == 0 {
+			continue
+		}
+		if !params.StartTime.IsZero() && usage.Timestamp.Before(params.StartTime) {
+			continue
+		}
+		if !params.EndTime.IsZero() && usage.Timestamp.After(params.EndTime) {
+			continue
+		}
+		if (params.CustomerID != "" && usage.CustomerID != params.CustomerID) ||
+			(params.SubscriptionID != "" && usage.SubscriptionID != params.SubscriptionID) ||
+			(params.MeterID != "" && usage.MeterID != params.MeterID) ||
+			(params.FeatureID != "" && usage.FeatureID != params.FeatureID) ||
+			(params.PriceID != "" && usage.PriceID != params.PriceID) {
+			continue
+		}
 		result = append(result, usage)
 	}
-	return result, uint64(len(result)), nil
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Timestamp.Equal(result[j].Timestamp) {
+			return result[i].Timestamp.Before(result[j].Timestamp)
+		}
+		if result[i].ID != result[j].ID {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].SubLineItemID < result[j].SubLineItemID
+	})
+
+	total := uint64(len(result))
+	if params.Offset >= len(result) {
+		return []*events.FeatureUsage{}, total, nil
+	}
+	result = result[params.Offset:]
+	if params.Limit > 0 && len(result) > params.Limit {
+		result = result[:params.Limit]
+	}
+	return result, total, nil
 }
 
 // IsDuplicate checks for duplicate events
@@ -87,9 +204,218 @@ func (s *InMemoryFeatureUsageStore) IsDuplicate(ctx context.Context, subscriptio
 	return false, nil
 }
 
-// GetDetailedUsageAnalytics provides usage analytics
+// GetDetailedUsageAnalytics provides usage analytics grouped by feature, price, meter and
+// line item plus any requested source/property dimensions
 func (s *InMemoryFeatureUsageStore) GetDetailedUsageAnalytics(ctx context.Context, params *events.UsageAnalyticsParams, maxBucketFeatures map[string]*events.MaxBucketFeatureInfo) ([]*events.DetailedUsageAnalytic, error) {
-	return []*events.DetailedUsageAnalytic{}, nil
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type group struct {
+		analytic     *events.DetailedUsageAnalytic
+		latestAt     time.Time
+		uniqueHashes map[string]struct{}
+		eventIDs     map[string]struct{}
+		sources      map[string]struct{}
+		points       map[time.Time]*events.UsageAnalyticPoint
+	}
+
+	groups := make(map[string]*group)
+	order := make([]string, 0)
+
+	// Rows of rolling window features per group, including the window before the start time
+	rollingRows := make(map[string][]*events.FeatureUsage)
+	rollingWindows := make(map[string]time.Duration)
+
+	for _, usage := range s.usage {
+		info, ok := maxBucketFeatures[usage.FeatureID]
+		isRolling := ok && info.RollingWindowHours > 0
+		window := time.Duration(0)
+		if isRolling {
+			window = time.Duration(info.RollingWindowHours) * time.Hour
+		}
+
+		lookbackParams := *params
+		lookbackParams.StartTime = params.StartTime.Add(-window)
+		if !s.matchesAnalyticsParams(usage, &lookbackParams) {
+			continue
+		}
+
+		keyParts := []string{usage.FeatureID, usage.PriceID, usage.MeterID, usage.SubLineItemID}
+		properties := make(map[string]string)
+		source := ""
+		for _, groupBy := range params.GroupBy {
+			switch {
+			case groupBy == "source":
+				source = usage.Source
+				keyParts = append(keyParts, usage.Source)
+			case strings.HasPrefix(groupBy, "properties."):
+				name := strings.TrimPrefix(groupBy, "properties.")
+				value := ""
+				if v, ok := usage.Properties[name]; ok {
+					value = fmt.Sprintf("%v", v)
+				}
+				properties[name] = value
+				keyParts = append(keyParts, value)
+			}
+		}
+		key := strings.Join(keyParts, "|")
+
+		if isRolling {
+			rollingRows[key] = append(rollingRows[key], usage)
+			rollingWindows[key] = window
+		}
+		if usage.Timestamp.Before(params.StartTime) {
+			continue
+		}
+
+		g, ok := groups[key]
+		if !ok {
+			g = &group{
+				analytic: &events.DetailedUsageAnalytic{
+					FeatureID:      usage.FeatureID,
+					PriceID:        usage.PriceID,
+					MeterID:        usage.MeterID,
+					SubLineItemID:  usage.SubLineItemID,
+					SubscriptionID: usage.SubscriptionID,
+					Source:         source,
+					Properties:     properties,
+					MaxUsage:       usage.QtyTotal,
+				},
+				uniqueHashes: make(map[string]struct{}),
+				eventIDs:     make(map[string]struct{}),
+				sources:      make(map[string]struct{}),
+				points:       make(map[time.Time]*events.UsageAnalyticPoint),
+			}
+			groups[key] = g
+			order = append(order, key)
+		}
+
+		qty := usage.QtyTotal.Mul(decimal.NewFromInt(int64(usage.Sign)))
+		a := g.analytic
+		a.TotalUsage = a.TotalUsage.Add(qty)
+		if params.WeightProperty != "" {
+			a.WeightedUsage = a.WeightedUsage.Add(qty.Mul(propertyWeight(usage.Properties[params.WeightProperty])))
+		}
+		if qty.GreaterThan(a.MaxUsage) {
+			a.MaxUsage = qty
+		}
+		if !usage.Timestamp.Before(g.latestAt) {
+			g.latestAt = usage.Timestamp
+			a.LatestUsage = usage.QtyTotal
+		}
+		g.uniqueHashes[usage.UniqueHash] = struct{}{}
+		g.eventIDs[usage.ID] = struct{}{}
+		if usage.Source != "" {
+			g.sources[usage.Source] = struct{}{}
+		}
+		a.CountUniqueUsage = uint64(len(g.uniqueHashes))
+		a.EventCount = uint64(len(g.eventIDs))
+
+		if params.WindowSize != "" {
+			bucket := truncateToBucket(usage.Timestamp, params.WindowSize)
+			point, ok := g.points[bucket]
+			if !ok {
+				point = &events.UsageAnalyticPoint{Timestamp: bucket, MaxUsage: qty}
+				g.points[bucket] = point
+			}
+			point.Usage = point.Usage.Add(qty)
+			if qty.GreaterThan(point.MaxUsage) {
+				point.MaxUsage = qty
+			}
+			point.LatestUsage = usage.QtyTotal
+			point.EventCount++
+			point.CountUniqueUsage++
+		}
+	}
+
+	// Rolling window features report the highest usage summed over the window ending at each
+	// event in the range
+	for key, rows := range rollingRows {
+		g, ok := groups[key]
+		if !ok {
+			continue
+		}
+
+		g.analytic.AggregationType = types.AggregationMax
+		g.analytic.TotalUsage = decimal.Zero
+		g.analytic.MaxUsage = decimal.Zero
+		g.points = nil
+		for _, row := range rows {
+			if row.Timestamp.Before(params.StartTime) {
+				continue
+			}
+			windowUsage := decimal.Zero
+			for _, other := range rows {
+				if other.Timestamp.After(row.Timestamp) || !other.Timestamp.After(row.Timestamp.Add(-rollingWindows[key])) {
+					continue
+				}
+				windowUsage = windowUsage.Add(other.QtyTotal.Mul(decimal.NewFromInt(int64(other.Sign))))
+			}
+			if windowUsage.GreaterThan(g.analytic.MaxUsage) {
+				g.analytic.MaxUsage = windowUsage
+				g.analytic.TotalUsage = windowUsage
+			}
+			if row.Timestamp.Equal(g.latestAt) {
+				g.analytic.LatestUsage = windowUsage
+			}
+		}
+	}
+
+	results := make([]*events.DetailedUsageAnalytic, 0, len(groups))
+	for _, key := range order {
+		g := groups[key]
+		points := make([]events.UsageAnalyticPoint, 0, len(g.points))
+		for _, point := range g.points {
+			points = append(points, *point)
+		}
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Timestamp.Before(points[j].Timestamp)
+		})
+		g.analytic.Points = points
+		g.analytic.Sources = lo.Keys(g.sources)
+		sort.Strings(g.analytic.Sources)
+		results = append(results, g.analytic)
+	}
+
+	return results, nil
+}
+
+// matchesAnalyticsParams checks whether a feature usage record falls within the analytics filters
+func (s *InMemoryFeatureUsageStore) matchesAnalyticsParams(usage *events.FeatureUsage, params *events.UsageAnalyticsParams) bool {
+	if usage.Sign == 0 {
+		return false
+	}
+	if params.CustomerID != "" && usage.CustomerID != params.CustomerID {
+		return false
+	}
+	if !params.StartTime.IsZero() && usage.Timestamp.Before(params.StartTime) {
+		return false
+	}
+	if !params.EndTime.IsZero() && !usage.Timestamp.Before(params.EndTime) {
+		return false
+	}
+	if len(params.FeatureIDs) > 0 && !lo.Contains(params.FeatureIDs, usage.FeatureID) {
+		return false
+	}
+	if len(params.Sources) > 0 && !lo.Contains(params.Sources, usage.Source) {
+		return false
+	}
+	if len(params.PriceIDs) > 0 && !lo.Contains(params.PriceIDs, usage.PriceID) {
+		return false
+	}
+	if !params.IsBillable(usage.SubLineItemID, usage.Timestamp) {
+		return false
+	}
+	for property, values := range params.PropertyFilters {
+		if len(values) == 0 {
+			continue
+		}
+		value, ok := usage.Properties[property]
+		if !ok || !lo.Contains(values, fmt.Sprintf("%v", value)) {
+			return false
+		}
+	}
+	return true
 }
 
 // GetFeatureUsageBySubscription gets feature usage by subscription
@@ -99,15 +425,25 @@ func (s *InMemoryFeatureUsageStore) GetFeatureUsageBySubscription(ctx context.Co
 
 	result := make(map[string]*events.UsageByFeatureResult)
 	for _, usage := range s.usage {
-		if usage.SubscriptionID == subscriptionID {
-			result[usage.SubLineItemID] = &events.UsageByFeatureResult{
+		if usage.Sign == 0 || usage.SubscriptionID != subscriptionID || usage.ExternalCustomerID != externalCustomerID {
+			continue
+		}
+		if usage.Timestamp.Before(startTime) || !usage.Timestamp.Before(endTime) {
+			continue
+		}
+
+		item, ok := result[usage.SubLineItemID]
+		if !ok {
+			item = &events.UsageByFeatureResult{
 				SubLineItemID: usage.SubLineItemID,
 				FeatureID:     usage.FeatureID,
 				MeterID:       usage.MeterID,
 				PriceID:       usage.PriceID,
-				SumTotal:      usage.QtyTotal,
+				SumTotal:      decimal.Zero,
 			}
+			result[usage.SubLineItemID] = item
 		}
+		item.SumTotal = item.SumTotal.Add(usage.QtyTotal)
 	}
 	return result, nil
 }
@@ -135,11 +471,285 @@ func (s *InMemoryFeatureUsageStore) GetUsageForMaxMetersWithBuckets(ctx context.
 	}, nil
 }
 
-func (s *InMemoryFeatureUsageStore) GetFeatureUsageByEventIDs(ctx context.Context, eventIDs []string) ([]*events.FeatureUsage, error) {
+func (s *InMemoryFeatureUsageStore) GetFeatureUsageByEventIDs(ctx context.Context, eventIDs []string, limit int) ([]*events.FeatureUsage, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	tenantID := types.GetTenantID(ctx)
+	environmentID := types.GetEnvironmentID(ctx)
+
 	var result []*events.FeatureUsage
+	for _, usage := range s.usage {
+		if usage.TenantID != tenantID || usage.EnvironmentID != environmentID {
+			continue
+		}
+		if lo.Contains(eventIDs, usage.ID) {
+			result = append(result, usage)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ID != result[j].ID {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].SubLineItemID < result[j].SubLineItemID
+	})
+
+	if limit > 0 {
+		perEvent := make(map[string]int)
+		result = lo.Filter(result, func(usage *events.FeatureUsage, _ int) bool {
+			perEvent[usage.ID]++
+			return perEvent[usage.ID] <= limit
+		})
+	}
 
 	return result, nil
 }
+
+// GetProcessedEventIDs returns the given event IDs that have feature usage recorded
+func (s *InMemoryFeatureUsageStore) GetProcessedEventIDs(ctx context.Context, eventIDs []string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tenantID := types.GetTenantID(ctx)
+	environmentID := types.GetEnvironmentID(ctx)
+
+	processed := make([]string, 0)
+	for _, usage := range s.usage {
+		if usage.Sign == 0 || usage.TenantID != tenantID || usage.EnvironmentID != environmentID {
+			continue
+		}
+		if lo.Contains(eventIDs, usage.ID) && !lo.Contains(processed, usage.ID) {
+			processed = append(processed, usage.ID)
+		}
+	}
+
+	return processed, nil
+}
+
+func (s *InMemoryFeatureUsageStore) GetEventSampleIDs(ctx context.Context, params *events.UsageAnalyticsParams, limit int) (map[string][]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	samples := make(map[string][]string)
+	if limit <= 0 {
+		return samples, nil
+	}
+
+	matched := make([]*events.FeatureUsage, 0)
+	for _, usage := range s.usage {
+		if s.matchesAnalyticsParams(usage, params) {
+			matched = append(matched, usage)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Timestamp.After(matched[j].Timestamp)
+	})
+
+	for _, usage := range matched {
+		ids := samples[usage.FeatureID]
+		if len(ids) >= limit || lo.Contains(ids, usage.ID) {
+			continue
+		}
+		samples[usage.FeatureID] = append(ids, usage.ID)
+	}
+
+	return samples, nil
+}
+
+func (s *InMemoryFeatureUsageStore) GetPropertyValuesByPrice(ctx context.Context, params *events.UsageAnalyticsParams, properties []string, limit int) (map[string]map[string][]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	values := make(map[string]map[string][]string)
+	if len(properties) == 0 {
+		return values, nil
+	}
+
+	for _, usage := range s.usage {
+		if !s.matchesAnalyticsParams(usage, params) {
+			continue
+		}
+		for _, property := range properties {
+			value, ok := usage.Properties[property]
+			if !ok {
+				continue
+			}
+			if values[usage.PriceID] == nil {
+				values[usage.PriceID] = make(map[string][]string)
+			}
+			str := fmt.Sprintf("%v", value)
+			if limit > 0 && len(values[usage.PriceID][property]) >= limit {
+				continue
+			}
+			if !lo.Contains(values[usage.PriceID][property], str) {
+				values[usage.PriceID][property] = append(values[usage.PriceID][property], str)
+			}
+		}
+	}
+
+	for _, byProperty := range values {
+		for _, propertyValues := range byProperty {
+			sort.Strings(propertyValues)
+		}
+	}
+
+	return values, nil
+}
+
+// GetUsageSubLineItemIDs returns the distinct subscription line items with usage in the window
+func (s *InMemoryFeatureUsageStore) GetUsageSubLineItemIDs(ctx context.Context, startTime, endTime time.Time) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subLineItemIDs := make([]string, 0)
+	for _, usage := range s.usage {
+		if usage.Sign == 0 || usage.SubLineItemID == "" {
+			continue
+		}
+		if usage.Timestamp.Before(startTime) || !usage.Timestamp.Before(endTime) {
+			continue
+		}
+		subLineItemIDs = append(subLineItemIDs, usage.SubLineItemID)
+	}
+	return lo.Uniq(subLineItemIDs), nil
+}
+
+// FindOrphanedUsage returns feature usage in the window attributed to the given line items
+func (s *InMemoryFeatureUsageStore) FindOrphanedUsage(ctx context.Context, params *events.FindOrphanedUsageParams) ([]*events.FeatureUsage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	orphaned := make([]*events.FeatureUsage, 0)
+	for _, usage := range s.usage {
+		if usage.Sign == 0 || usage.SubLineItemID == "" {
+			continue
+		}
+		if usage.Timestamp.Before(params.StartTime) || !usage.Timestamp.Before(params.EndTime) {
+			continue
+		}
+		if !lo.Contains(params.SubLineItemIDs, usage.SubLineItemID) {
+			continue
+		}
+		orphaned = append(orphaned, usage)
+	}
+
+	sort.Slice(orphaned, func(i, j int) bool {
+		return orphaned[i].Timestamp.Before(orphaned[j].Timestamp)
+	})
+
+	if params.Limit > 0 && len(orphaned) > params.Limit {
+		orphaned = orphaned[:params.Limit]
+	}
+
+	return orphaned, nil
+}
+
+// FindUsageWithoutCustomer returns feature usage in the window that has no customer id
+func (s *InMemoryFeatureUsageStore) FindUsageWithoutCustomer(ctx context.Context, params *events.FindUsageWithoutCustomerParams) ([]*events.FeatureUsage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	missing := make([]*events.FeatureUsage, 0)
+	for _, usage := range s.usage {
+		if usage.Sign == 0 || usage.CustomerID != "" {
+			continue
+		}
+		if usage.Timestamp.Before(params.StartTime) || !usage.Timestamp.Before(params.EndTime) {
+			continue
+		}
+		missing = append(missing, usage)
+	}
+
+	sort.Slice(missing, func(i, j int) bool {
+		if !missing[i].Timestamp.Equal(missing[j].Timestamp) {
+			return missing[i].Timestamp.Before(missing[j].Timestamp)
+		}
+		return missing[i].ID < missing[j].ID
+	})
+
+	if params.Offset >= len(missing) {
+		return []*events.FeatureUsage{}, nil
+	}
+	missing = missing[params.Offset:]
+	if params.Limit > 0 && len(missing) > params.Limit {
+		missing = missing[:params.Limit]
+	}
+
+	return missing, nil
+}
+
+// GetPlatformUsageTotals returns usage totals per meter in the window across all tenants and environments
+func (s *InMemoryFeatureUsageStore) GetPlatformUsageTotals(ctx context.Context, params *events.PlatformUsageParams) ([]*events.PlatformMeterUsage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	totals := make(map[string]*events.PlatformMeterUsage)
+	eventIDs := make(map[string]map[string]bool)
+	customerIDs := make(map[string]map[string]bool)
+	for _, usage := range s.usage {
+		if usage.Sign == 0 {
+			continue
+		}
+		if usage.Timestamp.Before(params.StartTime) || !usage.Timestamp.Before(params.EndTime) {
+			continue
+		}
+
+		key := strings.Join([]string{usage.TenantID, usage.EnvironmentID, usage.MeterID}, "|")
+		total, ok := totals[key]
+		if !ok {
+			total = &events.PlatformMeterUsage{
+				TenantID:      usage.TenantID,
+				EnvironmentID: usage.EnvironmentID,
+				MeterID:       usage.MeterID,
+				TotalUsage:    decimal.Zero,
+			}
+			totals[key] = total
+			eventIDs[key] = make(map[string]bool)
+			customerIDs[key] = make(map[string]bool)
+		}
+
+		total.TotalUsage = total.TotalUsage.Add(usage.QtyTotal.Mul(decimal.NewFromInt(int64(usage.Sign))))
+		eventIDs[key][usage.ID] = true
+		customerIDs[key][usage.CustomerID] = true
+	}
+
+	result := make([]*events.PlatformMeterUsage, 0, len(totals))
+	for key, total := range totals {
+		total.EventCount = uint64(len(eventIDs[key]))
+		total.CustomerCount = uint64(len(customerIDs[key]))
+		result = append(result, total)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TenantID != result[j].TenantID {
+			return result[i].TenantID < result[j].TenantID
+		}
+		if result[i].EnvironmentID != result[j].EnvironmentID {
+			return result[i].EnvironmentID < result[j].EnvironmentID
+		}
+		return result[i].MeterID < result[j].MeterID
+	})
+
+	return result, nil
+}
+
+// propertyWeight is the numeric value of a weight property, 0 when it isn't numeric
+func propertyWeight(value interface{}) decimal.Decimal {
+	switch v := value.(type) {
+	case float64:
+		return decimal.NewFromFloat(v)
+	case int:
+		return decimal.NewFromInt(int64(v))
+	case int64:
+		return decimal.NewFromInt(v)
+	case string:
+		weight, err := decimal.NewFromString(v)
+		if err != nil {
+			return decimal.Zero
+		}
+		return weight
+	}
+	return decimal.Zero
+}
@@ -57,6 +57,13 @@ func priceFilterFn(ctx context.Context, p *price.Price, filter interface{}) bool
 		}
 	}
 
+	// Filter by meter IDs
+	if len(f.MeterIDs) > 0 {
+		if !lo.Contains(f.MeterIDs, p.MeterID) {
+			return false
+		}
+	}
+
 	// Filter by status
 	if f.Status != nil && p.Status != *f.Status {
 		return false
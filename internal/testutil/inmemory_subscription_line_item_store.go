@@ -44,6 +44,11 @@ func lineItemFilterFn(ctx context.Context, item *subscription.SubscriptionLineIt
 		return false
 	}
 
+	// Filter by line item IDs
+	if len(f.LineItemIDs) > 0 && !lo.Contains(f.LineItemIDs, item.ID) {
+		return false
+	}
+
 	// Filter by subscription IDs
 	if len(f.SubscriptionIDs) > 0 && !lo.Contains(f.SubscriptionIDs, item.SubscriptionID) {
 		return false
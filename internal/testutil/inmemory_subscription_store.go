@@ -98,6 +98,16 @@ func subscriptionFilterFn(ctx context.Context, sub *subscription.Subscription, f
 		}
 	}
 
+	// Filter by active during
+	if f.ActiveDuring != nil {
+		if f.ActiveDuring.EndTime != nil && !sub.StartDate.Before(*f.ActiveDuring.EndTime) {
+			return false
+		}
+		if f.ActiveDuring.StartTime != nil && sub.EndDate != nil && !sub.EndDate.After(*f.ActiveDuring.StartTime) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -284,6 +294,7 @@ func (s *InMemorySubscriptionStore) ListAll(ctx context.Context, filter *types.S
 		BillingPeriod:           filter.BillingPeriod,
 		SubscriptionStatusNotIn: filter.SubscriptionStatusNotIn,
 		ActiveAt:                filter.ActiveAt,
+		ActiveDuring:            filter.ActiveDuring,
 	}
 
 	return s.List(ctx, unlimitedFilter)
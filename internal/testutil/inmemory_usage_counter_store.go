@@ -0,0 +1,129 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// InMemoryUsageCounterStore implements an in-memory usage counter repository for testing
+type InMemoryUsageCounterStore struct {
+	mu       sync.RWMutex
+	counters map[string]*events.UsageCounter
+	// entries are the feature usage rows added to the counters, by event id and line item
+	entries map[string]*events.FeatureUsage
+}
+
+// NewInMemoryUsageCounterStore creates a new in-memory usage counter store
+func NewInMemoryUsageCounterStore() *InMemoryUsageCounterStore {
+	return &InMemoryUsageCounterStore{
+		counters: make(map[string]*events.UsageCounter),
+		entries:  make(map[string]*events.FeatureUsage),
+	}
+}
+
+func usageCounterKey(tenantID, environmentID, customerID, featureID string, periodID uint64) string {
+	return fmt.Sprintf("%s:%s:%s:%s:%d", tenantID, environmentID, customerID, featureID, periodID)
+}
+
+func usageCounterEntryKey(tenantID, environmentID string, usage *events.FeatureUsage) string {
+	return fmt.Sprintf("%s:%s:%s:%s", tenantID, environmentID, usage.ID, usage.SubLineItemID)
+}
+
+// usageScope returns the tenant and environment of the row, falling back to the context
+func usageScope(ctx context.Context, usage *events.FeatureUsage) (string, string) {
+	tenantID := usage.TenantID
+	if tenantID == "" {
+		tenantID = types.GetTenantID(ctx)
+	}
+	environmentID := usage.EnvironmentID
+	if environmentID == "" {
+		environmentID = types.GetEnvironmentID(ctx)
+	}
+	return tenantID, environmentID
+}
+
+// Add adds each feature usage row to its counter, rows already added are skipped
+func (s *InMemoryUsageCounterStore) Add(ctx context.Context, featureUsage []*events.FeatureUsage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, usage := range featureUsage {
+		if !usage.IsCountable() {
+			continue
+		}
+
+		tenantID, environmentID := usageScope(ctx, usage)
+		entryKey := usageCounterEntryKey(tenantID, environmentID, usage)
+		if _, ok := s.entries[entryKey]; ok {
+			continue
+		}
+		entry := *usage
+		entry.TenantID = tenantID
+		entry.EnvironmentID = environmentID
+		s.entries[entryKey] = &entry
+
+		key := usageCounterKey(tenantID, environmentID, usage.CustomerID, usage.FeatureID, usage.PeriodID)
+		stored, ok := s.counters[key]
+		if !ok {
+			stored = &events.UsageCounter{
+				TenantID:      tenantID,
+				EnvironmentID: environmentID,
+				CustomerID:    usage.CustomerID,
+				FeatureID:     usage.FeatureID,
+				PeriodID:      usage.PeriodID,
+			}
+			s.counters[key] = stored
+		}
+		stored.Value = stored.Value.Add(usage.QtyTotal)
+		stored.UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
+
+// Cancel subtracts each feature usage row from the counter it was added to
+func (s *InMemoryUsageCounterStore) Cancel(ctx context.Context, featureUsage []*events.FeatureUsage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, usage := range featureUsage {
+		tenantID, environmentID := usageScope(ctx, usage)
+		entryKey := usageCounterEntryKey(tenantID, environmentID, usage)
+		entry, ok := s.entries[entryKey]
+		if !ok {
+			continue
+		}
+		delete(s.entries, entryKey)
+
+		if stored, ok := s.counters[usageCounterKey(tenantID, environmentID, entry.CustomerID, entry.FeatureID, entry.PeriodID)]; ok {
+			stored.Value = stored.Value.Sub(entry.QtyTotal)
+			stored.UpdatedAt = time.Now().UTC()
+		}
+	}
+	return nil
+}
+
+// Get returns the counter of a customer feature period, nil when nothing was counted
+func (s *InMemoryUsageCounterStore) Get(ctx context.Context, customerID, featureID string, periodID uint64) (*events.UsageCounter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counter, ok := s.counters[usageCounterKey(types.GetTenantID(ctx), types.GetEnvironmentID(ctx), customerID, featureID, periodID)]
+	if !ok {
+		return nil, nil
+	}
+	copied := *counter
+	return &copied, nil
+}
+
+// Clear removes all counters and entries
+func (s *InMemoryUsageCounterStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters = make(map[string]*events.UsageCounter)
+	s.entries = make(map[string]*events.FeatureUsage)
+}
@@ -0,0 +1,167 @@
+package types
+
+import (
+	"time"
+
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/samber/lo"
+)
+
+// Calendar splits time into the reporting periods of usage analytics
+type Calendar interface {
+	// PeriodStart returns the start of the period containing t
+	PeriodStart(t time.Time) time.Time
+	// NextPeriodStart returns the start of the period following the one containing t
+	NextPeriodStart(t time.Time) time.Time
+}
+
+// AccountingCalendarType is the layout of the periods of an accounting calendar
+type AccountingCalendarType string
+
+const (
+	// AccountingCalendar445 splits every quarter into periods of 4, 4 and 5 weeks
+	AccountingCalendar445 AccountingCalendarType = "4-4-5"
+	// AccountingCalendar454 splits every quarter into periods of 4, 5 and 4 weeks
+	AccountingCalendar454 AccountingCalendarType = "4-5-4"
+	// AccountingCalendar544 splits every quarter into periods of 5, 4 and 4 weeks
+	AccountingCalendar544 AccountingCalendarType = "5-4-4"
+	// AccountingCalendarCustom uses the periods given in period_weeks
+	AccountingCalendarCustom AccountingCalendarType = "custom"
+)
+
+// MaxAccountingCalendarWeeks caps the weeks of a fiscal year of a custom accounting calendar
+const MaxAccountingCalendarWeeks = 53
+
+// AccountingCalendar is a fiscal calendar of periods of whole weeks. The periods of a fiscal year
+// follow each other from YearStart and every fiscal year has the same periods, so the quarter
+// layouts have years of 52 weeks. Periods start at midnight UTC. The calendar must be valid
+// before its periods are computed.
+type AccountingCalendar struct {
+	Type AccountingCalendarType `json:"type"`
+	// YearStart is the first day of a fiscal year, earlier and later years repeat from it
+	YearStart time.Time `json:"year_start"`
+	// PeriodWeeks are the weeks of each period of the fiscal year, only set for custom calendars
+	PeriodWeeks []int `json:"period_weeks,omitempty"`
+}
+
+func (c AccountingCalendarType) String() string {
+	return string(c)
+}
+
+func (c AccountingCalendarType) Validate() error {
+	allowed := []AccountingCalendarType{
+		AccountingCalendar445,
+		AccountingCalendar454,
+		AccountingCalendar544,
+		AccountingCalendarCustom,
+	}
+	if !lo.Contains(allowed, c) {
+		return ierr.NewError("invalid accounting calendar type").
+			WithHint("Accounting calendar type must be one of 4-4-5, 4-5-4, 5-4-4 or custom").
+			WithReportableDetails(map[string]interface{}{
+				"type":           c,
+				"allowed_values": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+	return nil
+}
+
+func (c *AccountingCalendar) Validate() error {
+	if err := c.Type.Validate(); err != nil {
+		return err
+	}
+
+	if c.YearStart.IsZero() {
+		return ierr.NewError("year_start is required").
+			WithHint("Please provide the first day of a fiscal year of the accounting calendar").
+			Mark(ierr.ErrValidation)
+	}
+
+	if c.Type != AccountingCalendarCustom {
+		if len(c.PeriodWeeks) > 0 {
+			return ierr.NewError("period_weeks is only supported for custom calendars").
+				WithHintf("The periods of a %s calendar are fixed", c.Type).
+				Mark(ierr.ErrValidation)
+		}
+		return nil
+	}
+
+	if len(c.PeriodWeeks) == 0 {
+		return ierr.NewError("period_weeks is required for custom calendars").
+			WithHint("Please provide the weeks of each period of the fiscal year").
+			Mark(ierr.ErrValidation)
+	}
+	for _, weeks := range c.PeriodWeeks {
+		if weeks <= 0 {
+			return ierr.NewError("invalid period_weeks").
+				WithHint("Every period must have at least one week").
+				WithReportableDetails(map[string]interface{}{
+					"period_weeks": c.PeriodWeeks,
+				}).
+				Mark(ierr.ErrValidation)
+		}
+	}
+	if lo.Sum(c.PeriodWeeks) > MaxAccountingCalendarWeeks {
+		return ierr.NewError("invalid period_weeks").
+			WithHintf("A fiscal year can have at most %d weeks", MaxAccountingCalendarWeeks).
+			WithReportableDetails(map[string]interface{}{
+				"period_weeks": c.PeriodWeeks,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+	return nil
+}
+
+// PeriodStart returns the start of the fiscal period containing t
+func (c *AccountingCalendar) PeriodStart(t time.Time) time.Time {
+	start, _ := c.period(t)
+	return start
+}
+
+// NextPeriodStart returns the start of the fiscal period following the one containing t
+func (c *AccountingCalendar) NextPeriodStart(t time.Time) time.Time {
+	start, weeks := c.period(t)
+	return start.AddDate(0, 0, 7*weeks)
+}
+
+// period returns the start and the weeks of the fiscal period containing t
+func (c *AccountingCalendar) period(t time.Time) (time.Time, int) {
+	periodWeeks := c.periodWeeks()
+	yearLength := time.Duration(7*lo.Sum(periodWeeks)) * 24 * time.Hour
+
+	// Move to the start of the fiscal year containing t, which may be before YearStart
+	year, month, day := c.YearStart.UTC().Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	offset := t.Sub(start)
+	years := offset / yearLength
+	if offset%yearLength < 0 {
+		years--
+	}
+	start = start.Add(years * yearLength)
+
+	for i, weeks := range periodWeeks {
+		next := start.AddDate(0, 0, 7*weeks)
+		if t.Before(next) || i == len(periodWeeks)-1 {
+			return start, weeks
+		}
+		start = next
+	}
+	return start, 0
+}
+
+// periodWeeks returns the weeks of each period of a fiscal year
+func (c *AccountingCalendar) periodWeeks() []int {
+	var quarter []int
+	switch c.Type {
+	case AccountingCalendar445:
+		quarter = []int{4, 4, 5}
+	case AccountingCalendar454:
+		quarter = []int{4, 5, 4}
+	case AccountingCalendar544:
+		quarter = []int{5, 4, 4}
+	default:
+		return c.PeriodWeeks
+	}
+	return lo.Flatten([][]int{quarter, quarter, quarter, quarter})
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountingCalendarPeriods(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	// Fiscal year 2024 starts on Sunday, December 31st 2023
+	calendar := &AccountingCalendar{Type: AccountingCalendar445, YearStart: date(2023, time.December, 31)}
+	assert.NoError(t, calendar.Validate())
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		wantStart time.Time
+		wantNext  time.Time
+	}{
+		{name: "first day of the year", at: date(2023, time.December, 31), wantStart: date(2023, time.December, 31), wantNext: date(2024, time.January, 28)},
+		{name: "first 4 week period", at: date(2024, time.January, 15).Add(13 * time.Hour), wantStart: date(2023, time.December, 31), wantNext: date(2024, time.January, 28)},
+		{name: "second 4 week period", at: date(2024, time.February, 24), wantStart: date(2024, time.January, 28), wantNext: date(2024, time.February, 25)},
+		{name: "5 week period", at: date(2024, time.March, 30), wantStart: date(2024, time.February, 25), wantNext: date(2024, time.March, 31)},
+		{name: "second quarter", at: date(2024, time.April, 1), wantStart: date(2024, time.March, 31), wantNext: date(2024, time.April, 28)},
+		{name: "last period of the year", at: date(2024, time.December, 28), wantStart: date(2024, time.November, 24), wantNext: date(2024, time.December, 29)},
+		{name: "next year", at: date(2024, time.December, 29), wantStart: date(2024, time.December, 29), wantNext: date(2025, time.January, 26)},
+		{name: "previous year", at: date(2023, time.December, 30), wantStart: date(2023, time.November, 26), wantNext: date(2023, time.December, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantStart, calendar.PeriodStart(tt.at))
+			assert.Equal(t, tt.wantNext, calendar.NextPeriodStart(tt.at))
+		})
+	}
+
+	t.Run("custom periods", func(t *testing.T) {
+		custom := &AccountingCalendar{Type: AccountingCalendarCustom, YearStart: date(2024, time.July, 1), PeriodWeeks: []int{2, 50}}
+		assert.NoError(t, custom.Validate())
+		assert.Equal(t, date(2024, time.July, 15), custom.PeriodStart(date(2025, time.June, 1)))
+		assert.Equal(t, date(2025, time.June, 30), custom.NextPeriodStart(date(2025, time.June, 1)))
+	})
+}
+
+func TestAccountingCalendarValidate(t *testing.T) {
+	yearStart := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		calendar AccountingCalendar
+		wantErr  bool
+	}{
+		{name: "4-5-4 calendar", calendar: AccountingCalendar{Type: AccountingCalendar454, YearStart: yearStart}},
+		{name: "custom calendar", calendar: AccountingCalendar{Type: AccountingCalendarCustom, YearStart: yearStart, PeriodWeeks: []int{13, 13, 13, 14}}},
+		{name: "unknown type", calendar: AccountingCalendar{Type: "4-4-4", YearStart: yearStart}, wantErr: true},
+		{name: "missing year start", calendar: AccountingCalendar{Type: AccountingCalendar445}, wantErr: true},
+		{name: "period weeks of a quarter layout", calendar: AccountingCalendar{Type: AccountingCalendar445, YearStart: yearStart, PeriodWeeks: []int{4}}, wantErr: true},
+		{name: "custom calendar without periods", calendar: AccountingCalendar{Type: AccountingCalendarCustom, YearStart: yearStart}, wantErr: true},
+		{name: "empty period", calendar: AccountingCalendar{Type: AccountingCalendarCustom, YearStart: yearStart, PeriodWeeks: []int{4, 0}}, wantErr: true},
+		{name: "year too long", calendar: AccountingCalendar{Type: AccountingCalendarCustom, YearStart: yearStart, PeriodWeeks: []int{27, 27}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.calendar.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,18 +1,26 @@
 package types
 
+import (
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/samber/lo"
+)
+
 // AggregationType is a type for the type of aggregation to be performed on a meter
 // This is used to determine which aggregator to use when querying the database
 type AggregationType string
 
 const (
-	AggregationCount             AggregationType = "COUNT"
-	AggregationSum               AggregationType = "SUM"
-	AggregationAvg               AggregationType = "AVG"
-	AggregationCountUnique       AggregationType = "COUNT_UNIQUE"
-	AggregationLatest            AggregationType = "LATEST"
-	AggregationSumWithMultiplier AggregationType = "SUM_WITH_MULTIPLIER" // Sum with a multiplier - [sum(value) * multiplier]
-	AggregationMax               AggregationType = "MAX"
-	AggregationWeightedSum       AggregationType = "WEIGHTED_SUM"
+	AggregationCount              AggregationType = "COUNT"
+	AggregationSum                AggregationType = "SUM"
+	AggregationAvg                AggregationType = "AVG"
+	AggregationCountUnique        AggregationType = "COUNT_UNIQUE"
+	AggregationLatest             AggregationType = "LATEST"
+	AggregationSumWithMultiplier  AggregationType = "SUM_WITH_MULTIPLIER" // Sum with a multiplier - [sum(value) * multiplier]
+	AggregationMax                AggregationType = "MAX"
+	AggregationWeightedSum        AggregationType = "WEIGHTED_SUM"
+	AggregationCountOncePerPeriod AggregationType = "COUNT_ONCE_PER_PERIOD" // At most one event per customer per billing period
+	AggregationUptime             AggregationType = "UPTIME"                // Active seconds from heartbeat events, one heartbeat interval per interval with a heartbeat
+	AggregationCounterDelta       AggregationType = "COUNTER_DELTA"         // Increase of a cumulative counter between consecutive readings of a customer
 )
 
 func (t AggregationType) Validate() bool {
@@ -24,17 +32,55 @@ func (t AggregationType) Validate() bool {
 		AggregationLatest,
 		AggregationSumWithMultiplier,
 		AggregationMax,
-		AggregationWeightedSum:
+		AggregationWeightedSum,
+		AggregationCountOncePerPeriod,
+		AggregationUptime,
+		AggregationCounterDelta:
 		return true
 	default:
 		return false
 	}
 }
 
+// UnknownAggregationPolicy determines how an aggregation type without usage handling is treated,
+// e.g. a newly added type that isn't wired into quantity extraction yet
+type UnknownAggregationPolicy string
+
+const (
+	// UnknownAggregationPolicySum treats the type as SUM
+	UnknownAggregationPolicySum UnknownAggregationPolicy = "sum"
+
+	// UnknownAggregationPolicyError fails event processing and usage analytics for the meter
+	UnknownAggregationPolicyError UnknownAggregationPolicy = "error"
+)
+
+func (p UnknownAggregationPolicy) String() string {
+	return string(p)
+}
+
+func (p UnknownAggregationPolicy) Validate() error {
+	allowed := []UnknownAggregationPolicy{
+		UnknownAggregationPolicySum,
+		UnknownAggregationPolicyError,
+	}
+
+	if !lo.Contains(allowed, p) {
+		return ierr.NewError("invalid unknown aggregation policy").
+			WithHint("Unknown aggregation policy must be one of sum or error").
+			WithReportableDetails(map[string]any{
+				"policy":         p,
+				"allowed_policy": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
 // RequiresField returns true if the aggregation type requires a field
 func (t AggregationType) RequiresField() bool {
 	switch t {
-	case AggregationCount:
+	case AggregationCount, AggregationCountOncePerPeriod, AggregationUptime:
 		return false
 	default:
 		return true
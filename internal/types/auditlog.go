@@ -0,0 +1,25 @@
+package types
+
+// AuditLogEntityType is the type of the billing configuration entity an audit log records a change of
+type AuditLogEntityType string
+
+const (
+	AuditLogEntityTypeMeter   AuditLogEntityType = "meter"
+	AuditLogEntityTypePrice   AuditLogEntityType = "price"
+	AuditLogEntityTypeFeature AuditLogEntityType = "feature"
+)
+
+// AuditLogAction is the change an audit log records
+type AuditLogAction string
+
+const (
+	AuditLogActionCreate AuditLogAction = "create"
+	AuditLogActionUpdate AuditLogAction = "update"
+	AuditLogActionDelete AuditLogAction = "delete"
+)
+
+// AuditLogChange is the value of a field before and after a change
+type AuditLogChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
@@ -5,8 +5,48 @@ import (
 	"strings"
 
 	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/samber/lo"
 )
 
+// CustomerLookupKeyConflictPolicy determines how a lookup by external id that matches several
+// customers is resolved
+type CustomerLookupKeyConflictPolicy string
+
+const (
+	// CustomerLookupKeyConflictPolicyError fails the lookup
+	CustomerLookupKeyConflictPolicyError CustomerLookupKeyConflictPolicy = "error"
+
+	// CustomerLookupKeyConflictPolicyOldest picks the customer created first
+	CustomerLookupKeyConflictPolicyOldest CustomerLookupKeyConflictPolicy = "oldest"
+
+	// CustomerLookupKeyConflictPolicyNewest picks the customer created last
+	CustomerLookupKeyConflictPolicyNewest CustomerLookupKeyConflictPolicy = "newest"
+)
+
+func (p CustomerLookupKeyConflictPolicy) String() string {
+	return string(p)
+}
+
+func (p CustomerLookupKeyConflictPolicy) Validate() error {
+	allowed := []CustomerLookupKeyConflictPolicy{
+		CustomerLookupKeyConflictPolicyError,
+		CustomerLookupKeyConflictPolicyOldest,
+		CustomerLookupKeyConflictPolicyNewest,
+	}
+
+	if !lo.Contains(allowed, p) {
+		return ierr.NewError("invalid customer lookup key conflict policy").
+			WithHint("Customer lookup key conflict policy must be one of error, oldest or newest").
+			WithReportableDetails(map[string]any{
+				"policy":         p,
+				"allowed_policy": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
 // CustomerFilter represents filters for customer queries
 type CustomerFilter struct {
 	*QueryFilter
@@ -1,11 +1,41 @@
 package types
 
 import (
+	"errors"
 	"time"
 
 	ierr "github.com/flexprice/flexprice/internal/errors"
 )
 
+// ErrZeroDurationPeriod marks the errors of billing periods that don't end after they start,
+// e.g. of a misconfigured subscription, so they can be told apart from other period errors
+var ErrZeroDurationPeriod = errors.New("zero duration billing period")
+
+// IsZeroDurationPeriod checks if an error is the error of a billing period that doesn't end
+// after it starts
+func IsZeroDurationPeriod(err error) bool {
+	return ierr.Is(err, ErrZeroDurationPeriod)
+}
+
+// ValidatePeriodDuration returns a validation error marked with ErrZeroDurationPeriod when the
+// billing period has a zero or negative duration
+func ValidatePeriodDuration(periodStart, periodEnd time.Time) error {
+	if periodEnd.After(periodStart) {
+		return nil
+	}
+
+	err := ierr.NewError("billing period has a zero or negative duration").
+		WithHint("Billing period end must be after its start").
+		WithReportableDetails(
+			map[string]any{
+				"period_start": periodStart,
+				"period_end":   periodEnd,
+			},
+		).
+		Mark(ierr.ErrValidation)
+	return ierr.WithError(err).Mark(ErrZeroDurationPeriod)
+}
+
 // NextBillingDate calculates the next billing date based on the current period start,
 // billing anchor, billing period, and billing period unit.
 // The billing anchor determines the reference point for billing cycles:
@@ -236,6 +266,8 @@ func isLeapYear(year int) bool {
 // 1. Event timestamp falls within current billing period -> return current period start
 // 2. Event timestamp is before current period start -> calculate periods from subscription start to find the appropriate period
 // 3. Event timestamp is after current period end -> find appropriate future period
+// A current period, or a period after it, that doesn't advance fails with ErrZeroDurationPeriod
+// instead of being iterated over.
 func CalculatePeriodID(
 	eventTimestamp time.Time,
 	subStart time.Time,
@@ -277,6 +309,11 @@ func CalculatePeriodID(
 		)
 	}
 
+	// The periods after a current period without a duration can't be found from it
+	if err := ValidatePeriodDuration(currentPeriodStart, currentPeriodEnd); err != nil {
+		return 0, err
+	}
+
 	// Case 3: Event timestamp is after current period end
 	// Iterate forward from current period until we find the period containing the event
 	periodStart := currentPeriodStart
@@ -294,6 +331,9 @@ func CalculatePeriodID(
 		if err != nil {
 			return 0, err
 		}
+		if err := ValidatePeriodDuration(nextPeriodStart, nextPeriodEnd); err != nil {
+			return 0, err
+		}
 
 		// Check if event falls within this period
 		if isBetween(eventTimestamp, nextPeriodStart, nextPeriodEnd) {
@@ -339,6 +379,9 @@ func findPeriodFromSubscriptionStart(
 	if err != nil {
 		return 0, err
 	}
+	if err := ValidatePeriodDuration(periodStart, periodEnd); err != nil {
+		return 0, err
+	}
 
 	// Iterate through periods from subscription start until we find the period containing the event
 	// or reach the current period (optimization to avoid infinite loops)
@@ -360,6 +403,9 @@ func findPeriodFromSubscriptionStart(
 		if err != nil {
 			return 0, err
 		}
+		if err := ValidatePeriodDuration(nextPeriodStart, nextPeriodEnd); err != nil {
+			return 0, err
+		}
 
 		periodStart = nextPeriodStart
 		periodEnd = nextPeriodEnd
@@ -3,6 +3,8 @@ package types
 import (
 	"testing"
 	"time"
+
+	ierr "github.com/flexprice/flexprice/internal/errors"
 )
 
 var (
@@ -1359,6 +1361,77 @@ func TestCalculatePeriodID_Simple(t *testing.T) {
 	}
 }
 
+func TestValidatePeriodDuration(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		end     time.Time
+		wantErr bool
+	}{
+		{name: "positive duration", end: start.Add(time.Second), wantErr: false},
+		{name: "zero duration", end: start, wantErr: true},
+		{name: "negative duration", end: start.AddDate(0, -1, 0), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePeriodDuration(start, tt.end)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidatePeriodDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if !IsZeroDurationPeriod(err) {
+				t.Errorf("ValidatePeriodDuration() error = %v, want a zero duration period error", err)
+			}
+			if !ierr.IsValidation(err) {
+				t.Errorf("ValidatePeriodDuration() error = %v, want a validation error", err)
+			}
+		})
+	}
+
+	if IsZeroDurationPeriod(ierr.NewError("other").Mark(ierr.ErrValidation)) {
+		t.Error("IsZeroDurationPeriod() = true for another validation error")
+	}
+}
+
+// Events before a current period without a duration are still placed in their periods, later
+// events fail with a zero duration period error instead of being iterated over
+func TestCalculatePeriodID_ZeroDurationCurrentPeriod(t *testing.T) {
+	subStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	currentPeriodStart := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		event   time.Time
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "event before the current period", event: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "event at the current period start", event: currentPeriodStart, wantErr: true},
+		{name: "event after the current period", event: time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalculatePeriodID(tt.event, subStart, currentPeriodStart, currentPeriodStart, subStart, 1, BILLING_PERIOD_MONTHLY)
+			if tt.wantErr {
+				if !IsZeroDurationPeriod(err) {
+					t.Fatalf("CalculatePeriodID() error = %v, want a zero duration period error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CalculatePeriodID() error = %v", err)
+			}
+			if got != calculatePeriodID(tt.want) {
+				t.Errorf("CalculatePeriodID() = %v, want %v", got, calculatePeriodID(tt.want))
+			}
+		})
+	}
+}
+
 func TestGetNextUsageResetAt_Never(t *testing.T) {
 	currentTime := time.Date(2024, time.March, 15, 12, 30, 0, 0, time.UTC)
 	subscriptionStart := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
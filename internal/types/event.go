@@ -1 +1,286 @@
 package types
+
+import (
+	"strings"
+
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/samber/lo"
+)
+
+// EventProcessingStatus is how far an ingested event has made it through usage processing
+type EventProcessingStatus string
+
+const (
+	// EventProcessingStatusProcessed means feature usage has been recorded for the event
+	EventProcessingStatusProcessed EventProcessingStatus = "processed"
+	// EventProcessingStatusProcessing means no feature usage has been recorded for the stored event
+	// yet, it was ingested recently enough to still be queued
+	EventProcessingStatusProcessing EventProcessingStatus = "processing"
+	// EventProcessingStatusUnprocessed means no feature usage was recorded for the stored event
+	// within the status timeout, e.g. because it didn't match any subscription. It is terminal
+	// unless the event is reprocessed.
+	EventProcessingStatusUnprocessed EventProcessingStatus = "unprocessed"
+	// EventProcessingStatusUnknown means the event isn't stored, either because it was never
+	// ingested or because it is still on its way to storage
+	EventProcessingStatusUnknown EventProcessingStatus = "unknown"
+)
+
+// UnbilledEventReason is why an event produced no feature usage
+type UnbilledEventReason string
+
+const (
+	// UnbilledEventReasonCustomerNotFound means no customer has the event's external customer id
+	UnbilledEventReasonCustomerNotFound UnbilledEventReason = "customer_not_found"
+	// UnbilledEventReasonAmbiguousCustomer means several customers have the event's external customer id
+	UnbilledEventReasonAmbiguousCustomer UnbilledEventReason = "ambiguous_customer"
+	// UnbilledEventReasonBeforeCustomerCreation means the event predates the customer and is skipped by policy
+	UnbilledEventReasonBeforeCustomerCreation UnbilledEventReason = "before_customer_creation"
+	// UnbilledEventReasonNoActiveSubscription means the customer has no subscription metering usage
+	UnbilledEventReasonNoActiveSubscription UnbilledEventReason = "no_active_subscription"
+	// UnbilledEventReasonSubscriptionNotFound means the subscription named by the event isn't one of the customer's
+	UnbilledEventReasonSubscriptionNotFound UnbilledEventReason = "subscription_not_found"
+	// UnbilledEventReasonOutsideSubscription means the event falls outside the subscriptions' active period
+	UnbilledEventReasonOutsideSubscription UnbilledEventReason = "outside_subscription"
+	// UnbilledEventReasonUsageFrozen means the subscription's usage is frozen
+	UnbilledEventReasonUsageFrozen UnbilledEventReason = "usage_frozen"
+	// UnbilledEventReasonSubscriptionPaused means the event was received during a subscription pause
+	UnbilledEventReasonSubscriptionPaused UnbilledEventReason = "subscription_paused"
+	// UnbilledEventReasonPeriodCalculationFailed means the billing period of the event couldn't be calculated
+	UnbilledEventReasonPeriodCalculationFailed UnbilledEventReason = "period_calculation_failed"
+	// UnbilledEventReasonNoLineItem means the subscription has no active usage line item
+	UnbilledEventReasonNoLineItem UnbilledEventReason = "no_line_item"
+	// UnbilledEventReasonNoMatchingMeter means no meter matches the event name or the event is filtered out
+	UnbilledEventReasonNoMatchingMeter UnbilledEventReason = "no_matching_meter"
+	// UnbilledEventReasonMeterStatusExcluded means the meter doesn't count usage in the subscription status
+	UnbilledEventReasonMeterStatusExcluded UnbilledEventReason = "meter_status_excluded"
+	// UnbilledEventReasonFeatureNotFound means the matched meter has no feature
+	UnbilledEventReasonFeatureNotFound UnbilledEventReason = "feature_not_found"
+	// UnbilledEventReasonAboveMaxEventValue means the event's value is above the meter's maximum
+	// event value and was rejected as an anomaly
+	UnbilledEventReasonAboveMaxEventValue UnbilledEventReason = "above_max_event_value"
+	// UnbilledEventReasonInvalidPeriodID means the period id the event names isn't a period of the subscription
+	UnbilledEventReasonInvalidPeriodID UnbilledEventReason = "invalid_period_id"
+	// UnbilledEventReasonPending means the event would produce feature usage but hasn't been processed yet
+	UnbilledEventReasonPending UnbilledEventReason = "pending"
+	// UnbilledEventReasonUnknown means preparing the event failed, e.g. a lookup it needs failed
+	UnbilledEventReasonUnknown UnbilledEventReason = "unknown"
+)
+
+// UsageAnalyticsWarningCode identifies why part of a usage analytics response is incomplete
+type UsageAnalyticsWarningCode string
+
+const (
+	// UsageAnalyticsWarningMissingPrice means usage was recorded against a price that couldn't be
+	// fetched, its cost is reported as zero
+	UsageAnalyticsWarningMissingPrice UsageAnalyticsWarningCode = "missing_price"
+	// UsageAnalyticsWarningMissingConversionRate means costs are priced in a currency without a
+	// conversion rate to the settlement currency, they are left out of the settled total cost
+	UsageAnalyticsWarningMissingConversionRate UsageAnalyticsWarningCode = "missing_conversion_rate"
+)
+
+// PreCustomerCreationEventPolicy determines how events timestamped before the
+// customer was created are processed
+type PreCustomerCreationEventPolicy string
+
+const (
+	// PreCustomerCreationEventPolicyMatch matches the event to subscriptions by its own timestamp
+	PreCustomerCreationEventPolicyMatch PreCustomerCreationEventPolicy = "match"
+
+	// PreCustomerCreationEventPolicyClamp moves the event to the customer creation, or to the start of
+	// the customer's earliest subscription when that is later, so it is billed in the first period
+	PreCustomerCreationEventPolicyClamp PreCustomerCreationEventPolicy = "clamp"
+
+	// PreCustomerCreationEventPolicySkip drops the event
+	PreCustomerCreationEventPolicySkip PreCustomerCreationEventPolicy = "skip"
+)
+
+func (p PreCustomerCreationEventPolicy) String() string {
+	return string(p)
+}
+
+func (p PreCustomerCreationEventPolicy) Validate() error {
+	allowed := []PreCustomerCreationEventPolicy{
+		PreCustomerCreationEventPolicyMatch,
+		PreCustomerCreationEventPolicyClamp,
+		PreCustomerCreationEventPolicySkip,
+	}
+
+	if !lo.Contains(allowed, p) {
+		return ierr.NewError("invalid pre customer creation event policy").
+			WithHint("Pre customer creation event policy must be one of match, clamp or skip").
+			WithReportableDetails(map[string]any{
+				"policy":         p,
+				"allowed_policy": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// MaxEventValuePolicy determines what happens to an event whose value is above the maximum event
+// value of a meter
+type MaxEventValuePolicy string
+
+const (
+	// MaxEventValuePolicyReject drops the usage of the event for the meter
+	MaxEventValuePolicyReject MaxEventValuePolicy = "reject"
+
+	// MaxEventValuePolicyClamp lowers the value of the event to the maximum event value
+	MaxEventValuePolicyClamp MaxEventValuePolicy = "clamp"
+)
+
+func (p MaxEventValuePolicy) String() string {
+	return string(p)
+}
+
+func (p MaxEventValuePolicy) Validate() error {
+	allowed := []MaxEventValuePolicy{
+		MaxEventValuePolicyReject,
+		MaxEventValuePolicyClamp,
+	}
+
+	if !lo.Contains(allowed, p) {
+		return ierr.NewError("invalid max event value policy").
+			WithHint("Max event value policy must be one of reject or clamp").
+			WithReportableDetails(map[string]any{
+				"policy":         p,
+				"allowed_policy": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// PeriodCalculationFailurePolicy determines what happens to an event when the billing period
+// of a subscription it matches can't be calculated
+type PeriodCalculationFailurePolicy string
+
+const (
+	// PeriodCalculationFailurePolicySkip drops the usage of the subscription and processes the rest of the event
+	PeriodCalculationFailurePolicySkip PeriodCalculationFailurePolicy = "skip"
+
+	// PeriodCalculationFailurePolicyError fails the processing of the event so it is retried
+	PeriodCalculationFailurePolicyError PeriodCalculationFailurePolicy = "error"
+)
+
+func (p PeriodCalculationFailurePolicy) String() string {
+	return string(p)
+}
+
+func (p PeriodCalculationFailurePolicy) Validate() error {
+	allowed := []PeriodCalculationFailurePolicy{
+		PeriodCalculationFailurePolicySkip,
+		PeriodCalculationFailurePolicyError,
+	}
+
+	if !lo.Contains(allowed, p) {
+		return ierr.NewError("invalid period calculation failure policy").
+			WithHint("Period calculation failure policy must be one of skip or error").
+			WithReportableDetails(map[string]any{
+				"policy":         p,
+				"allowed_policy": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// EventNameNormalization determines how event names are normalized when events are ingested
+// and when they are matched to meters
+type EventNameNormalization string
+
+const (
+	// EventNameNormalizationNone keeps event names as they are sent
+	EventNameNormalizationNone EventNameNormalization = "none"
+
+	// EventNameNormalizationTrim removes leading and trailing whitespace from event names
+	EventNameNormalizationTrim EventNameNormalization = "trim"
+
+	// EventNameNormalizationLowercase removes leading and trailing whitespace from event names and lowercases them
+	EventNameNormalizationLowercase EventNameNormalization = "lowercase"
+)
+
+func (n EventNameNormalization) String() string {
+	return string(n)
+}
+
+func (n EventNameNormalization) Validate() error {
+	allowed := []EventNameNormalization{
+		EventNameNormalizationNone,
+		EventNameNormalizationTrim,
+		EventNameNormalizationLowercase,
+	}
+
+	if !lo.Contains(allowed, n) {
+		return ierr.NewError("invalid event name normalization").
+			WithHint("Event name normalization must be one of none, trim or lowercase").
+			WithReportableDetails(map[string]any{
+				"normalization":         n,
+				"allowed_normalization": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// Normalize returns the event name normalized, names are kept as is for none or an invalid normalization
+func (n EventNameNormalization) Normalize(eventName string) string {
+	switch n {
+	case EventNameNormalizationTrim:
+		return strings.TrimSpace(eventName)
+	case EventNameNormalizationLowercase:
+		return strings.ToLower(strings.TrimSpace(eventName))
+	default:
+		return eventName
+	}
+}
+
+// UsageForecastModel is the model forecasting the usage of the next period from the usage of
+// the previous periods
+type UsageForecastModel string
+
+const (
+	// UsageForecastModelMovingAverage forecasts the average usage of the latest periods
+	UsageForecastModelMovingAverage UsageForecastModel = "moving_average"
+
+	// UsageForecastModelLinearTrend extends the least squares line through the usage of the periods
+	UsageForecastModelLinearTrend UsageForecastModel = "linear_trend"
+)
+
+func (m UsageForecastModel) String() string {
+	return string(m)
+}
+
+func (m UsageForecastModel) Validate() error {
+	allowed := []UsageForecastModel{
+		UsageForecastModelMovingAverage,
+		UsageForecastModelLinearTrend,
+	}
+
+	if !lo.Contains(allowed, m) {
+		return ierr.NewError("invalid usage forecast model").
+			WithHint("Usage forecast model must be one of moving_average or linear_trend").
+			WithReportableDetails(map[string]any{
+				"model":         m,
+				"allowed_model": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// ClockSkewDirection is the direction in which the clock of an event producer is skewed
+type ClockSkewDirection string
+
+const (
+	// ClockSkewDirectionAhead is a producer timestamping its events in the future
+	ClockSkewDirectionAhead ClockSkewDirection = "ahead"
+
+	// ClockSkewDirectionBehind is a producer timestamping its events in the past
+	ClockSkewDirectionBehind ClockSkewDirection = "behind"
+)
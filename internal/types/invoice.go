@@ -15,6 +15,14 @@ const (
 	InvoiceLineItemEntityTypeAddon InvoiceLineItemEntityType = "addon"
 )
 
+// Metadata keys recording the settlement of an invoice priced in a currency other than the
+// settlement currency. The amounts of the invoice stay in the pricing currency.
+const (
+	InvoiceMetadataSettlementCurrency       = "settlement_currency"
+	InvoiceMetadataSettlementConversionRate = "settlement_conversion_rate"
+	InvoiceMetadataSettledTotal             = "settled_total"
+)
+
 // InvoiceCadence defines when an invoice is generated relative to the billing period
 // ARREAR: Invoice generated at the end of the billing period (after service delivery)
 // ADVANCE: Invoice generated at the beginning of the billing period (before service delivery)
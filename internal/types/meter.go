@@ -1,5 +1,12 @@
 package types
 
+import (
+	"strings"
+
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/samber/lo"
+)
+
 // MeterFilter represents the filter options for meter queries
 type MeterFilter struct {
 	*QueryFilter
@@ -106,3 +113,120 @@ func (f *MeterFilter) IsUnlimited() bool {
 	}
 	return f.QueryFilter.IsUnlimited()
 }
+
+// MeterBillingMode determines whether the usage of a meter is billed
+type MeterBillingMode string
+
+const (
+	// MeterBillingModeBill bills the usage of the meter
+	MeterBillingModeBill MeterBillingMode = "bill"
+
+	// MeterBillingModeShadow records and reports the usage of the meter without ever billing it,
+	// for ex while onboarding a customer before their usage is charged
+	MeterBillingModeShadow MeterBillingMode = "shadow"
+)
+
+// Validate ensures the MeterBillingMode value is valid, empty defaults to bill
+func (m MeterBillingMode) Validate() error {
+	if m == "" {
+		return nil
+	}
+
+	allowed := []MeterBillingMode{
+		MeterBillingModeBill,
+		MeterBillingModeShadow,
+	}
+
+	if !lo.Contains(allowed, m) {
+		return ierr.NewError("invalid meter billing mode").
+			WithHint("Meter billing mode must be one of bill or shadow").
+			WithReportableDetails(map[string]any{
+				"billing_mode":  m,
+				"allowed_modes": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// MeterEventNameMatch determines how the event name of a meter is matched against the name of
+// the events it tracks
+type MeterEventNameMatch string
+
+const (
+	// MeterEventNameMatchExact matches the events named exactly as the meter's event name
+	MeterEventNameMatchExact MeterEventNameMatch = "exact"
+
+	// MeterEventNameMatchPrefix matches the events whose name starts with the meter's event name,
+	// for ex "api.users." matches "api.users.create" and "api.users.delete"
+	MeterEventNameMatchPrefix MeterEventNameMatch = "prefix"
+)
+
+// Validate ensures the MeterEventNameMatch value is valid, empty defaults to exact
+func (m MeterEventNameMatch) Validate() error {
+	if m == "" {
+		return nil
+	}
+
+	allowed := []MeterEventNameMatch{
+		MeterEventNameMatchExact,
+		MeterEventNameMatchPrefix,
+	}
+
+	if !lo.Contains(allowed, m) {
+		return ierr.NewError("invalid meter event name match").
+			WithHint("Meter event name match must be one of exact or prefix").
+			WithReportableDetails(map[string]any{
+				"event_name_match": m,
+				"allowed_modes":    allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// Matches reports whether the event name is matched by the meter's event name
+func (m MeterEventNameMatch) Matches(meterEventName, eventName string) bool {
+	if m == MeterEventNameMatchPrefix {
+		return strings.HasPrefix(eventName, meterEventName)
+	}
+	return eventName == meterEventName
+}
+
+// MeterGapFillMode determines the analytics points reported for windows without events
+type MeterGapFillMode string
+
+const (
+	// MeterGapFillModeZero reports a zero usage point for windows without events
+	MeterGapFillModeZero MeterGapFillMode = "zero"
+
+	// MeterGapFillModeCarryOver repeats the usage of the last window with events, for gauges
+	// whose value holds until the next reading
+	MeterGapFillModeCarryOver MeterGapFillMode = "carry_over"
+)
+
+// Validate ensures the MeterGapFillMode value is valid, empty leaves gaps without points
+func (m MeterGapFillMode) Validate() error {
+	if m == "" {
+		return nil
+	}
+
+	allowed := []MeterGapFillMode{
+		MeterGapFillModeZero,
+		MeterGapFillModeCarryOver,
+	}
+
+	if !lo.Contains(allowed, m) {
+		return ierr.NewError("invalid meter gap fill mode").
+			WithHint("Meter gap fill mode must be one of zero or carry_over").
+			WithReportableDetails(map[string]any{
+				"fill_gaps":     m,
+				"allowed_modes": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
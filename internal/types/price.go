@@ -480,3 +480,73 @@ func (f *PriceFilter) IsUnlimited() bool {
 	}
 	return f.QueryFilter.IsUnlimited()
 }
+
+// CostRoundingMode is how costs are rounded to the currency precision
+type CostRoundingMode string
+
+const (
+	// CostRoundingModeHalfUp rounds halves away from zero ex 1.005 -> 1.01
+	CostRoundingModeHalfUp CostRoundingMode = "half_up"
+	// CostRoundingModeHalfEven rounds halves to the nearest even digit ex 1.005 -> 1.00
+	CostRoundingModeHalfEven CostRoundingMode = "half_even"
+)
+
+func (m CostRoundingMode) String() string {
+	return string(m)
+}
+
+func (m CostRoundingMode) Validate() error {
+	allowed := []CostRoundingMode{
+		CostRoundingModeHalfUp,
+		CostRoundingModeHalfEven,
+	}
+	if !lo.Contains(allowed, m) {
+		return ierr.NewError("invalid cost rounding mode").
+			WithHint("Cost rounding mode must be one of half_up or half_even").
+			WithReportableDetails(map[string]interface{}{
+				"mode":    m,
+				"allowed": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+	return nil
+}
+
+// Round rounds the amount to the given number of decimal places using the mode
+func (m CostRoundingMode) Round(amount decimal.Decimal, places int32) decimal.Decimal {
+	if m == CostRoundingModeHalfEven {
+		return amount.RoundBank(places)
+	}
+	return amount.Round(places)
+}
+
+// CostRoundingGranularity is the level costs are rounded to the currency precision at
+type CostRoundingGranularity string
+
+const (
+	// CostRoundingGranularityLineItem rounds the cost of every line item
+	CostRoundingGranularityLineItem CostRoundingGranularity = "line_item"
+	// CostRoundingGranularityInvoice keeps line item costs at full precision so only invoice totals are rounded
+	CostRoundingGranularityInvoice CostRoundingGranularity = "invoice"
+)
+
+func (g CostRoundingGranularity) String() string {
+	return string(g)
+}
+
+func (g CostRoundingGranularity) Validate() error {
+	allowed := []CostRoundingGranularity{
+		CostRoundingGranularityLineItem,
+		CostRoundingGranularityInvoice,
+	}
+	if !lo.Contains(allowed, g) {
+		return ierr.NewError("invalid cost rounding granularity").
+			WithHint("Cost rounding granularity must be one of line_item or invoice").
+			WithReportableDetails(map[string]interface{}{
+				"granularity": g,
+				"allowed":     allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+	return nil
+}
@@ -1,5 +1,10 @@
 package types
 
+import (
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/samber/lo"
+)
+
 // PubSubType defines the type of pubsub implementation
 type PubSubType string
 
@@ -10,3 +15,50 @@ const (
 	// KafkaPubSub uses Kafka implementation
 	KafkaPubSub PubSubType = "kafka"
 )
+
+// ConsumerGroupKind is the role of a kafka consumer group, used to report the lag of the
+// consumer groups
+type ConsumerGroupKind string
+
+const (
+	// ConsumerGroupKindNormal consumes the events as they are ingested
+	ConsumerGroupKindNormal ConsumerGroupKind = "normal"
+
+	// ConsumerGroupKindBackfill consumes the events of backfills
+	ConsumerGroupKindBackfill ConsumerGroupKind = "backfill"
+
+	// ConsumerGroupKindLazy consumes the events of the tenants routed on lazy mode
+	ConsumerGroupKindLazy ConsumerGroupKind = "lazy"
+
+	// ConsumerGroupKindReplay consumes the events replayed from a point in time
+	ConsumerGroupKindReplay ConsumerGroupKind = "replay"
+
+	// ConsumerGroupKindPriority consumes the events tagged with a processing priority
+	ConsumerGroupKindPriority ConsumerGroupKind = "priority"
+)
+
+func (k ConsumerGroupKind) String() string {
+	return string(k)
+}
+
+func (k ConsumerGroupKind) Validate() error {
+	allowed := []ConsumerGroupKind{
+		ConsumerGroupKindNormal,
+		ConsumerGroupKindBackfill,
+		ConsumerGroupKindLazy,
+		ConsumerGroupKindReplay,
+		ConsumerGroupKindPriority,
+	}
+
+	if !lo.Contains(allowed, k) {
+		return ierr.NewError("invalid consumer group kind").
+			WithHint("Consumer group kind must be one of normal, backfill, lazy, replay or priority").
+			WithReportableDetails(map[string]any{
+				"kind":         k,
+				"allowed_kind": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/shopspring/decimal"
 )
 
 type SettingKey string
@@ -15,6 +16,9 @@ const (
 	SettingKeySubscriptionConfig SettingKey = "subscription_config"
 	SettingKeyInvoicePDFConfig   SettingKey = "invoice_pdf_config"
 	SettingKeyEnvConfig          SettingKey = "env_config"
+	SettingKeyEventConfig        SettingKey = "event_config"
+	SettingKeyAnalyticsConfig    SettingKey = "analytics_config"
+	SettingKeySettlementConfig   SettingKey = "settlement_config"
 )
 
 func (s SettingKey) String() string {
@@ -35,6 +39,131 @@ type SubscriptionConfig struct {
 	AutoCancellationEnabled bool `json:"auto_cancellation_enabled"`
 }
 
+// EventConfig represents the configuration for matching events to meters
+type EventConfig struct {
+	// CaseInsensitiveEventNames matches event names to meters regardless of case (e.g. API_Call and api_call)
+	CaseInsensitiveEventNames bool `json:"case_insensitive_event_names"`
+}
+
+// EventConfigFromValue extracts the event config from a setting value, using defaults for missing fields
+func EventConfigFromValue(value map[string]interface{}) *EventConfig {
+	defaultConfig := GetDefaultSettings()[SettingKeyEventConfig].DefaultValue
+
+	config := &EventConfig{
+		CaseInsensitiveEventNames: defaultConfig["case_insensitive_event_names"].(bool),
+	}
+
+	if caseInsensitiveRaw, exists := value["case_insensitive_event_names"]; exists {
+		if caseInsensitive, ok := caseInsensitiveRaw.(bool); ok {
+			config.CaseInsensitiveEventNames = caseInsensitive
+		}
+	}
+
+	return config
+}
+
+// MaxAnalyticsCostPrecision is the most decimal places analytics costs can be rounded to
+const MaxAnalyticsCostPrecision = 10
+
+// AnalyticsConfig represents the configuration for presenting usage analytics
+type AnalyticsConfig struct {
+	// CostPrecision is the number of decimal places the costs of the usage analytics are rounded to,
+	// nil returns the costs at the precision they are computed at
+	CostPrecision *int `json:"cost_precision"`
+}
+
+// AnalyticsConfigFromValue extracts the analytics config from a setting value, using defaults for missing fields
+func AnalyticsConfigFromValue(value map[string]interface{}) *AnalyticsConfig {
+	defaultConfig := GetDefaultSettings()[SettingKeyAnalyticsConfig].DefaultValue
+
+	config := &AnalyticsConfig{
+		CostPrecision: costPrecisionFromValue(defaultConfig["cost_precision"]),
+	}
+
+	if costPrecisionRaw, exists := value["cost_precision"]; exists {
+		config.CostPrecision = costPrecisionFromValue(costPrecisionRaw)
+	}
+
+	return config
+}
+
+func costPrecisionFromValue(value interface{}) *int {
+	switch v := value.(type) {
+	case int:
+		return &v
+	case float64:
+		precision := int(v)
+		return &precision
+	default:
+		return nil
+	}
+}
+
+// SettlementConfig represents the configuration for settling amounts in a currency other than
+// the currency they are priced in, e.g. priced in USD but settled in EUR
+type SettlementConfig struct {
+	// Currency is the currency amounts are settled in, empty settles in the pricing currency
+	Currency string `json:"currency"`
+	// ConversionRates maps a pricing currency to the amount in the settlement currency of one
+	// unit of it, e.g. {"usd": 0.92} settles 1 USD as 0.92 EUR
+	ConversionRates map[string]decimal.Decimal `json:"conversion_rates"`
+}
+
+// SettlementConfigFromValue extracts the settlement config from a setting value, rates that
+// aren't positive numbers are dropped
+func SettlementConfigFromValue(value map[string]interface{}) *SettlementConfig {
+	config := &SettlementConfig{
+		ConversionRates: make(map[string]decimal.Decimal),
+	}
+
+	if currency, ok := value["currency"].(string); ok {
+		config.Currency = strings.ToLower(strings.TrimSpace(currency))
+	}
+
+	if ratesRaw, ok := value["conversion_rates"].(map[string]interface{}); ok {
+		for currency, rateRaw := range ratesRaw {
+			if rate, err := conversionRateFromValue(rateRaw); err == nil && rate.IsPositive() {
+				config.ConversionRates[strings.ToLower(currency)] = rate
+			}
+		}
+	}
+
+	return config
+}
+
+// Convert returns the amount priced in the currency in the settlement currency and the rate it
+// was converted at. It reports false when there is no settlement currency or no rate for the
+// currency. Amounts priced in the settlement currency settle at a rate of 1.
+func (c *SettlementConfig) Convert(amount decimal.Decimal, currency string) (decimal.Decimal, decimal.Decimal, bool) {
+	if c == nil || c.Currency == "" || currency == "" {
+		return decimal.Zero, decimal.Zero, false
+	}
+	if IsMatchingCurrency(c.Currency, currency) {
+		return amount, decimal.NewFromInt(1), true
+	}
+	rate, ok := c.ConversionRates[strings.ToLower(currency)]
+	if !ok {
+		return decimal.Zero, decimal.Zero, false
+	}
+	return amount.Mul(rate), rate, true
+}
+
+func conversionRateFromValue(value interface{}) (decimal.Decimal, error) {
+	switch v := value.(type) {
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	case int:
+		return decimal.NewFromInt(int64(v)), nil
+	case string:
+		return decimal.NewFromString(v)
+	case decimal.Decimal:
+		return v, nil
+	default:
+		return decimal.Zero, ierr.NewErrorf("unsupported conversion rate type %T", value).
+			Mark(ierr.ErrValidation)
+	}
+}
+
 // TenantEnvConfig represents a generic configuration for a specific tenant and environment
 type TenantEnvConfig struct {
 	TenantID      string                 `json:"tenant_id"`
@@ -145,6 +274,31 @@ func GetDefaultSettings() map[SettingKey]DefaultSettingValue {
 			Description: "Default configuration for environment creation limits (production and sandbox)",
 			Required:    true,
 		},
+		SettingKeyEventConfig: {
+			Key: SettingKeyEventConfig,
+			DefaultValue: map[string]interface{}{
+				"case_insensitive_event_names": false,
+			},
+			Description: "Default configuration for matching events to meters (event names are case sensitive)",
+			Required:    true,
+		},
+		SettingKeyAnalyticsConfig: {
+			Key: SettingKeyAnalyticsConfig,
+			DefaultValue: map[string]interface{}{
+				"cost_precision": nil,
+			},
+			Description: "Default configuration for usage analytics (costs are not rounded)",
+			Required:    true,
+		},
+		SettingKeySettlementConfig: {
+			Key: SettingKeySettlementConfig,
+			DefaultValue: map[string]interface{}{
+				"currency":         "",
+				"conversion_rates": map[string]interface{}{},
+			},
+			Description: "Default configuration for settlement currency (amounts are settled in the pricing currency)",
+			Required:    true,
+		},
 	}
 }
 
@@ -169,6 +323,12 @@ func ValidateSettingValue(key string, value map[string]interface{}) error {
 		return ValidateInvoicePDFConfig(value)
 	case SettingKeyEnvConfig:
 		return ValidateEnvConfig(value)
+	case SettingKeyEventConfig:
+		return ValidateEventConfig(value)
+	case SettingKeyAnalyticsConfig:
+		return ValidateAnalyticsConfig(value)
+	case SettingKeySettlementConfig:
+		return ValidateSettlementConfig(value)
 	default:
 		return ierr.NewErrorf("unknown setting key: %s", key).
 			WithHintf("Unknown setting key: %s", key).
@@ -597,3 +757,104 @@ func validateEnvLimit(envTypeKey string, limitRaw interface{}) error {
 
 	return nil
 }
+
+// ValidateEventConfig validates event configuration settings
+func ValidateEventConfig(value map[string]interface{}) error {
+	if value == nil {
+		return errors.New("event_config value cannot be nil")
+	}
+
+	if caseInsensitiveRaw, exists := value["case_insensitive_event_names"]; exists {
+		if _, ok := caseInsensitiveRaw.(bool); !ok {
+			return ierr.NewErrorf("event_config: 'case_insensitive_event_names' must be a boolean, got %T", caseInsensitiveRaw).
+				WithHintf("Event config case insensitive event names must be a boolean, got %T", caseInsensitiveRaw).
+				Mark(ierr.ErrValidation)
+		}
+	}
+
+	return nil
+}
+
+// ValidateAnalyticsConfig validates analytics configuration settings
+func ValidateAnalyticsConfig(value map[string]interface{}) error {
+	if value == nil {
+		return errors.New("analytics_config value cannot be nil")
+	}
+
+	costPrecisionRaw, exists := value["cost_precision"]
+	if !exists || costPrecisionRaw == nil {
+		return nil
+	}
+
+	var costPrecision int
+	switch v := costPrecisionRaw.(type) {
+	case int:
+		costPrecision = v
+	case float64:
+		if v != float64(int(v)) {
+			return ierr.NewErrorf("analytics_config: 'cost_precision' must be a whole number").
+				WithHintf("Analytics config cost precision must be a whole number").
+				Mark(ierr.ErrValidation)
+		}
+		costPrecision = int(v)
+	default:
+		return ierr.NewErrorf("analytics_config: 'cost_precision' must be an integer, got %T", costPrecisionRaw).
+			WithHintf("Analytics config cost precision must be an integer, got %T", costPrecisionRaw).
+			Mark(ierr.ErrValidation)
+	}
+
+	if costPrecision < 0 || costPrecision > MaxAnalyticsCostPrecision {
+		return ierr.NewErrorf("analytics_config: 'cost_precision' must be between 0 and %d", MaxAnalyticsCostPrecision).
+			WithHintf("Analytics config cost precision must be between 0 and %d", MaxAnalyticsCostPrecision).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// ValidateSettlementConfig validates settlement configuration settings
+func ValidateSettlementConfig(value map[string]interface{}) error {
+	if value == nil {
+		return errors.New("settlement_config value cannot be nil")
+	}
+
+	if currencyRaw, exists := value["currency"]; exists && currencyRaw != nil {
+		currency, ok := currencyRaw.(string)
+		if !ok {
+			return ierr.NewErrorf("settlement_config: 'currency' must be a string, got %T", currencyRaw).
+				WithHintf("Settlement config currency must be a string, got %T", currencyRaw).
+				Mark(ierr.ErrValidation)
+		}
+		if currency != "" {
+			if err := ValidateCurrencyCode(currency); err != nil {
+				return err
+			}
+		}
+	}
+
+	ratesRaw, exists := value["conversion_rates"]
+	if !exists || ratesRaw == nil {
+		return nil
+	}
+
+	rates, ok := ratesRaw.(map[string]interface{})
+	if !ok {
+		return ierr.NewErrorf("settlement_config: 'conversion_rates' must be an object, got %T", ratesRaw).
+			WithHintf("Settlement config conversion rates must map a currency to its rate, got %T", ratesRaw).
+			Mark(ierr.ErrValidation)
+	}
+
+	for currency, rateRaw := range rates {
+		if err := ValidateCurrencyCode(currency); err != nil {
+			return err
+		}
+		rate, err := conversionRateFromValue(rateRaw)
+		if err != nil || !rate.IsPositive() {
+			return ierr.NewErrorf("settlement_config: conversion rate of '%s' must be a positive number", currency).
+				WithHintf("Settlement config conversion rate of %s must be a positive number", currency).
+				Mark(ierr.ErrValidation)
+		}
+	}
+
+	return nil
+}
@@ -211,6 +211,137 @@ func (s PauseStatus) Validate() error {
 	return nil
 }
 
+// PausedSubscriptionUsagePolicy determines how usage events that fall inside
+// an active pause window of a subscription are processed
+type PausedSubscriptionUsagePolicy string
+
+const (
+	// PausedSubscriptionUsagePolicySkip drops usage received while the subscription is paused
+	PausedSubscriptionUsagePolicySkip PausedSubscriptionUsagePolicy = "skip"
+
+	// PausedSubscriptionUsagePolicyBill records usage received while paused exactly like usage of an active subscription
+	PausedSubscriptionUsagePolicyBill PausedSubscriptionUsagePolicy = "bill"
+
+	// PausedSubscriptionUsagePolicyAccrue records usage received while paused against the billing
+	// period that was interrupted by the pause, so it is billed once the subscription resumes
+	PausedSubscriptionUsagePolicyAccrue PausedSubscriptionUsagePolicy = "accrue"
+)
+
+func (p PausedSubscriptionUsagePolicy) String() string {
+	return string(p)
+}
+
+func (p PausedSubscriptionUsagePolicy) Validate() error {
+	allowed := []PausedSubscriptionUsagePolicy{
+		PausedSubscriptionUsagePolicySkip,
+		PausedSubscriptionUsagePolicyBill,
+		PausedSubscriptionUsagePolicyAccrue,
+	}
+
+	if !lo.Contains(allowed, p) {
+		return ierr.NewError("invalid paused subscription usage policy").
+			WithHint("Paused subscription usage policy must be one of skip, bill or accrue").
+			WithReportableDetails(map[string]any{
+				"policy":         p,
+				"allowed_policy": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// CancellationBoundary determines whether an event timestamped exactly at the cancellation of a
+// subscription is billed to it
+type CancellationBoundary string
+
+const (
+	// CancellationBoundaryInclusive bills events at the cancellation instant
+	CancellationBoundaryInclusive CancellationBoundary = "inclusive"
+
+	// CancellationBoundaryExclusive bills only the events before the cancellation instant
+	CancellationBoundaryExclusive CancellationBoundary = "exclusive"
+)
+
+func (b CancellationBoundary) String() string {
+	return string(b)
+}
+
+func (b CancellationBoundary) Validate() error {
+	allowed := []CancellationBoundary{
+		CancellationBoundaryInclusive,
+		CancellationBoundaryExclusive,
+	}
+
+	if !lo.Contains(allowed, b) {
+		return ierr.NewError("invalid cancellation boundary").
+			WithHint("Cancellation boundary must be one of inclusive or exclusive").
+			WithReportableDetails(map[string]any{
+				"boundary":         b,
+				"allowed_boundary": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// Covers returns true if an event at the timestamp is billed to a subscription cancelled at
+// cancelledAt
+func (b CancellationBoundary) Covers(timestamp, cancelledAt time.Time) bool {
+	if b == CancellationBoundaryExclusive {
+		return timestamp.Before(cancelledAt)
+	}
+	return !timestamp.After(cancelledAt)
+}
+
+// TrialBoundaryPhase determines which phase of a subscription owns the usage of events at, or
+// within the boundary window of, the end of its trial
+type TrialBoundaryPhase string
+
+const (
+	// TrialBoundaryPhaseTrial meters boundary events as trial usage
+	TrialBoundaryPhaseTrial TrialBoundaryPhase = "trial"
+
+	// TrialBoundaryPhasePaid meters boundary events as paid usage
+	TrialBoundaryPhasePaid TrialBoundaryPhase = "paid"
+)
+
+func (p TrialBoundaryPhase) String() string {
+	return string(p)
+}
+
+func (p TrialBoundaryPhase) Validate() error {
+	allowed := []TrialBoundaryPhase{
+		TrialBoundaryPhaseTrial,
+		TrialBoundaryPhasePaid,
+	}
+
+	if !lo.Contains(allowed, p) {
+		return ierr.NewError("invalid trial boundary phase").
+			WithHint("Trial boundary phase must be one of trial or paid").
+			WithReportableDetails(map[string]any{
+				"phase":         p,
+				"allowed_phase": allowed,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return nil
+}
+
+// InTrial returns true if an event at the timestamp is trial usage of a subscription whose trial
+// ends at trialEnd. Events within the window either side of the trial end belong to the phase.
+func (p TrialBoundaryPhase) InTrial(timestamp, trialEnd time.Time, window time.Duration) bool {
+	if timestamp.Before(trialEnd.Add(-window)) {
+		return true
+	}
+	if timestamp.After(trialEnd.Add(window)) {
+		return false
+	}
+	return p == TrialBoundaryPhaseTrial
+}
+
 // SubscriptionFilter represents filters for subscription queries
 type SubscriptionFilter struct {
 	*QueryFilter
@@ -236,6 +367,9 @@ type SubscriptionFilter struct {
 	SubscriptionStatusNotIn []SubscriptionStatus `json:"-"`
 	// ActiveAt filters subscriptions that are active at the given time
 	ActiveAt *time.Time `json:"active_at,omitempty" form:"active_at"`
+	// ActiveDuring filters subscriptions active at any time in the range, the start is inclusive
+	// and the end exclusive, either may be left open
+	ActiveDuring *TimeRangeFilter `json:"-"`
 
 	// WithLineItems includes line items in the response
 	WithLineItems bool `json:"with_line_items,omitempty" form:"with_line_items"`
@@ -105,6 +105,7 @@ const (
 	UUID_PREFIX_WEBHOOK_EVENT              = "webhook"
 	UUID_PREFIX_SETTING                    = "setting"
 	UUID_PREFIX_ALERT_LOG                  = "alert"
+	UUID_PREFIX_AUDIT_LOG                  = "audit"
 	UUID_PREFIX_GROUP                      = "group"
 	UUID_PREFIX_SUBSCRIPTION_ENTITLEMENT   = "sub_ent"
 	UUID_PREFIX_SUBSCRIPTION_PHASE         = "phase"
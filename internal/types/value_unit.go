@@ -0,0 +1,158 @@
+package types
+
+import (
+	"strings"
+
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/shopspring/decimal"
+)
+
+// ValueUnit is the unit a meter aggregates property values in. Producers can send values with a
+// unit suffix, e.g. "1.5GB" or "500ms", which are converted to the unit of the meter before
+// aggregation. Values without a suffix are taken as already being in the unit of the meter.
+type ValueUnit string
+
+const (
+	// Data units, decimal multiples
+	ValueUnitByte     ValueUnit = "B"
+	ValueUnitKilobyte ValueUnit = "KB"
+	ValueUnitMegabyte ValueUnit = "MB"
+	ValueUnitGigabyte ValueUnit = "GB"
+	ValueUnitTerabyte ValueUnit = "TB"
+	ValueUnitPetabyte ValueUnit = "PB"
+
+	// Data units, binary multiples
+	ValueUnitKibibyte ValueUnit = "KiB"
+	ValueUnitMebibyte ValueUnit = "MiB"
+	ValueUnitGibibyte ValueUnit = "GiB"
+	ValueUnitTebibyte ValueUnit = "TiB"
+
+	// Time units
+	ValueUnitNanosecond  ValueUnit = "ns"
+	ValueUnitMicrosecond ValueUnit = "us"
+	ValueUnitMillisecond ValueUnit = "ms"
+	ValueUnitSecond      ValueUnit = "s"
+	ValueUnitMinute      ValueUnit = "min"
+	ValueUnitHour        ValueUnit = "h"
+	ValueUnitDay         ValueUnit = "d"
+)
+
+type valueUnitDimension string
+
+const (
+	valueUnitDimensionData valueUnitDimension = "data"
+	valueUnitDimensionTime valueUnitDimension = "time"
+)
+
+// valueUnitScale is the size of a unit in the base unit of its dimension, bytes or nanoseconds
+type valueUnitScale struct {
+	dimension valueUnitDimension
+	factor    decimal.Decimal
+}
+
+var valueUnitScales = map[ValueUnit]valueUnitScale{
+	ValueUnitByte:     {valueUnitDimensionData, decimal.NewFromInt(1)},
+	ValueUnitKilobyte: {valueUnitDimensionData, decimal.New(1, 3)},
+	ValueUnitMegabyte: {valueUnitDimensionData, decimal.New(1, 6)},
+	ValueUnitGigabyte: {valueUnitDimensionData, decimal.New(1, 9)},
+	ValueUnitTerabyte: {valueUnitDimensionData, decimal.New(1, 12)},
+	ValueUnitPetabyte: {valueUnitDimensionData, decimal.New(1, 15)},
+
+	ValueUnitKibibyte: {valueUnitDimensionData, decimal.NewFromInt(1 << 10)},
+	ValueUnitMebibyte: {valueUnitDimensionData, decimal.NewFromInt(1 << 20)},
+	ValueUnitGibibyte: {valueUnitDimensionData, decimal.NewFromInt(1 << 30)},
+	ValueUnitTebibyte: {valueUnitDimensionData, decimal.NewFromInt(1 << 40)},
+
+	ValueUnitNanosecond:  {valueUnitDimensionTime, decimal.NewFromInt(1)},
+	ValueUnitMicrosecond: {valueUnitDimensionTime, decimal.New(1, 3)},
+	ValueUnitMillisecond: {valueUnitDimensionTime, decimal.New(1, 6)},
+	ValueUnitSecond:      {valueUnitDimensionTime, decimal.New(1, 9)},
+	ValueUnitMinute:      {valueUnitDimensionTime, decimal.New(60, 9)},
+	ValueUnitHour:        {valueUnitDimensionTime, decimal.New(3600, 9)},
+	ValueUnitDay:         {valueUnitDimensionTime, decimal.New(86400, 9)},
+}
+
+// valueUnitSuffixes maps the lower cased suffixes producers send to their unit
+var valueUnitSuffixes = map[string]ValueUnit{
+	"b":     ValueUnitByte,
+	"kb":    ValueUnitKilobyte,
+	"mb":    ValueUnitMegabyte,
+	"gb":    ValueUnitGigabyte,
+	"tb":    ValueUnitTerabyte,
+	"pb":    ValueUnitPetabyte,
+	"kib":   ValueUnitKibibyte,
+	"mib":   ValueUnitMebibyte,
+	"gib":   ValueUnitGibibyte,
+	"tib":   ValueUnitTebibyte,
+	"ns":    ValueUnitNanosecond,
+	"us":    ValueUnitMicrosecond,
+	"µs":    ValueUnitMicrosecond,
+	"ms":    ValueUnitMillisecond,
+	"s":     ValueUnitSecond,
+	"sec":   ValueUnitSecond,
+	"min":   ValueUnitMinute,
+	"h":     ValueUnitHour,
+	"hr":    ValueUnitHour,
+	"d":     ValueUnitDay,
+	"day":   ValueUnitDay,
+	"days":  ValueUnitDay,
+	"bytes": ValueUnitByte,
+}
+
+func (u ValueUnit) Validate() error {
+	if _, ok := valueUnitScales[u]; !ok {
+		return ierr.NewError("invalid unit").
+			WithHint("Please provide a supported data or time unit").
+			WithReportableDetails(map[string]interface{}{
+				"unit": u,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+	return nil
+}
+
+// Parse converts a value with an optional unit suffix, e.g. "1.5GB" or "500 ms", to this unit.
+// The suffix must be of the same kind as this unit, a data size can't be converted to a duration.
+func (u ValueUnit) Parse(value string) (decimal.Decimal, error) {
+	to, ok := valueUnitScales[u]
+	if !ok {
+		return decimal.Zero, u.Validate()
+	}
+
+	value = strings.TrimSpace(value)
+	split := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '-' && r != '+'
+	})
+	if split == -1 {
+		split = len(value)
+	}
+
+	number, err := decimal.NewFromString(value[:split])
+	if err != nil {
+		return decimal.Zero, ierr.WithError(err).
+			WithHint("Value must be a number optionally followed by a unit").
+			WithReportableDetails(map[string]interface{}{
+				"value": value,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	suffix := strings.TrimSpace(value[split:])
+	if suffix == "" {
+		return number, nil
+	}
+
+	from, ok := valueUnitSuffixes[strings.ToLower(suffix)]
+	if !ok || valueUnitScales[from].dimension != to.dimension {
+		return decimal.Zero, ierr.NewError("unsupported unit suffix").
+			WithHint("Value unit must be convertible to the unit of the meter").
+			WithReportableDetails(map[string]interface{}{
+				"value":  value,
+				"suffix": suffix,
+				"unit":   u,
+			}).
+			Mark(ierr.ErrValidation)
+	}
+
+	return number.Mul(valueUnitScales[from].factor).Div(to.factor), nil
+}
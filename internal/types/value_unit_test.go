@@ -0,0 +1,44 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueUnitParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		unit     ValueUnit
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "gigabytes to megabytes", unit: ValueUnitMegabyte, value: "1.5GB", expected: "1500"},
+		{name: "gigabytes to bytes", unit: ValueUnitByte, value: "1.5GB", expected: "1500000000"},
+		{name: "binary multiples", unit: ValueUnitMebibyte, value: "2 GiB", expected: "2048"},
+		{name: "lower case suffix", unit: ValueUnitKilobyte, value: "3mb", expected: "3000"},
+		{name: "milliseconds to seconds", unit: ValueUnitSecond, value: "500ms", expected: "0.5"},
+		{name: "milliseconds to milliseconds", unit: ValueUnitMillisecond, value: "500ms", expected: "500"},
+		{name: "minutes to milliseconds", unit: ValueUnitMillisecond, value: "1.5min", expected: "90000"},
+		{name: "no suffix is already in the unit", unit: ValueUnitMegabyte, value: "42", expected: "42"},
+		{name: "data to time", unit: ValueUnitMillisecond, value: "1.5GB", wantErr: true},
+		{name: "unknown suffix", unit: ValueUnitMegabyte, value: "10xyz", wantErr: true},
+		{name: "not a number", unit: ValueUnitMegabyte, value: "GB", wantErr: true},
+		{name: "invalid unit", unit: ValueUnit("parsecs"), value: "1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.unit.Parse(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, decimal.RequireFromString(tt.expected).Equal(value), "value: %s", value)
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"strconv"
 	"time"
 )
 
@@ -14,6 +17,28 @@ type WebhookEvent struct {
 	UserID        string          `json:"user_id"`
 	Timestamp     time.Time       `json:"timestamp"`
 	Payload       json.RawMessage `json:"payload"`
+	// IdempotencyKey is shared by events that describe the same occurrence, so receivers can
+	// drop the repeats. Empty when the event can't be deduplicated.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// GenerateWebhookIdempotencyKey returns a key that is the same for all events with the same parts
+// published within the same window, e.g. the same alert for a customer raised several times in a
+// few seconds. Returns an empty key when window is not positive.
+func GenerateWebhookIdempotencyKey(window time.Duration, at time.Time, parts ...string) string {
+	if window <= 0 {
+		return ""
+	}
+
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(strconv.Itoa(len(part))))
+		h.Write([]byte{':'})
+		h.Write([]byte(part))
+	}
+	h.Write([]byte(strconv.FormatInt(at.UTC().Truncate(window).Unix(), 10)))
+
+	return "idem_" + hex.EncodeToString(h.Sum(nil))[:32]
 }
 
 // invoice event names
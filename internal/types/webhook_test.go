@@ -0,0 +1,70 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateWebhookIdempotencyKey(t *testing.T) {
+	window := 5 * time.Minute
+	at := time.Date(2024, 3, 20, 15, 1, 0, 0, time.UTC)
+	key := GenerateWebhookIdempotencyKey(window, at, "tenant_1", "env_1", "cust_1", "in_alarm")
+	assert.NotEmpty(t, key)
+
+	tests := []struct {
+		name     string
+		window   time.Duration
+		at       time.Time
+		parts    []string
+		collapse bool
+	}{
+		{
+			name:     "same customer later in the window",
+			window:   window,
+			at:       at.Add(3 * time.Minute),
+			parts:    []string{"tenant_1", "env_1", "cust_1", "in_alarm"},
+			collapse: true,
+		},
+		{
+			name:     "same customer in the next window",
+			window:   window,
+			at:       at.Add(4 * time.Minute),
+			parts:    []string{"tenant_1", "env_1", "cust_1", "in_alarm"},
+			collapse: false,
+		},
+		{
+			name:     "other customer in the window",
+			window:   window,
+			at:       at,
+			parts:    []string{"tenant_1", "env_1", "cust_2", "in_alarm"},
+			collapse: false,
+		},
+		{
+			name:     "other alert status in the window",
+			window:   window,
+			at:       at,
+			parts:    []string{"tenant_1", "env_1", "cust_1", "ok"},
+			collapse: false,
+		},
+		{
+			name:     "parts are not concatenated",
+			window:   window,
+			at:       at,
+			parts:    []string{"tenant_1", "env_1cust_1", "", "in_alarm"},
+			collapse: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := GenerateWebhookIdempotencyKey(tt.window, tt.at, tt.parts...)
+			assert.Equal(t, tt.collapse, other == key)
+		})
+	}
+
+	t.Run("disabled without window", func(t *testing.T) {
+		assert.Empty(t, GenerateWebhookIdempotencyKey(0, at, "tenant_1", "env_1", "cust_1", "in_alarm"))
+	})
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	ierr "github.com/flexprice/flexprice/internal/errors"
 	"github.com/samber/lo"
 )
@@ -8,6 +10,9 @@ import (
 // WindowSize defines the time window for aggregating usage data.
 //
 // Supported window sizes and their behavior:
+// - SECOND: 1-second windows (00:00:00.000 to 00:00:00.999)
+// - 10SEC: 10-second windows (00:00:00 to 00:00:09, 00:00:10 to 00:00:19, etc.)
+// - 30SEC: 30-second windows (00:00:00 to 00:00:29, 00:00:30 to 00:00:59)
 // - MINUTE: 1-minute windows (00:00:00 to 00:00:59)
 // - 15MIN: 15-minute windows (00:00:00 to 00:14:59, 00:15:00 to 00:29:59, etc.)
 // - 30MIN: 30-minute windows (00:00:00 to 00:29:59, 00:30:00 to 00:59:59, etc.)
@@ -23,10 +28,15 @@ import (
 // - When used with BillingAnchor: Creates custom monthly periods (e.g., 5th to 5th of each month)
 // - When used without BillingAnchor: Uses standard calendar months (1st to 1st of each month)
 // - All other window sizes ignore BillingAnchor and use standard calendar-based windows
+//
+// Sub-minute window sizes are meant for high-frequency metering over short ranges, see ValidateRange.
 type WindowSize string
 
 // Note: keep values up to date in the meter package
 const (
+	WindowSizeSecond WindowSize = "SECOND"
+	WindowSize10Sec  WindowSize = "10SEC"
+	WindowSize30Sec  WindowSize = "30SEC"
 	WindowSizeMinute WindowSize = "MINUTE"
 	WindowSize15Min  WindowSize = "15MIN"
 	WindowSize30Min  WindowSize = "30MIN"
@@ -39,23 +49,29 @@ const (
 	WindowSizeMonth  WindowSize = "MONTH"
 )
 
+// windowSizes lists the supported window sizes from the shortest to the longest window
+var windowSizes = []WindowSize{
+	WindowSizeSecond,
+	WindowSize10Sec,
+	WindowSize30Sec,
+	WindowSizeMinute,
+	WindowSize15Min,
+	WindowSize30Min,
+	WindowSizeHour,
+	WindowSize3Hour,
+	WindowSize6Hour,
+	WindowSize12Hour,
+	WindowSizeDay,
+	WindowSizeWeek,
+	WindowSizeMonth,
+}
+
 func (w WindowSize) Validate() error {
 	if w == "" {
 		return nil
 	}
 
-	if !lo.Contains([]WindowSize{
-		WindowSizeMinute,
-		WindowSize15Min,
-		WindowSize30Min,
-		WindowSizeHour,
-		WindowSize3Hour,
-		WindowSize6Hour,
-		WindowSize12Hour,
-		WindowSizeDay,
-		WindowSizeWeek,
-		WindowSizeMonth,
-	}, w) {
+	if !lo.Contains(windowSizes, w) {
 		return ierr.NewError("invalid window size").
 			WithHint("Invalid window size").
 			WithReportableDetails(
@@ -68,3 +84,91 @@ func (w WindowSize) Validate() error {
 
 	return nil
 }
+
+// MaxSubMinuteWindows caps the number of windows a sub-minute window size can split a range into.
+// It allows six hours of one second windows, the default analytics range.
+const MaxSubMinuteWindows = 6 * 60 * 60
+
+// IsSubMinute reports whether the window is shorter than a minute
+func (w WindowSize) IsSubMinute() bool {
+	d, ok := w.Duration()
+	return ok && d < time.Minute
+}
+
+// ValidateRange guards against sub-minute windows over long ranges, which would return
+// more than MaxSubMinuteWindows points. Windows of a minute or longer and ranges without a
+// start time, which default to a short range when querying, are not limited.
+func (w WindowSize) ValidateRange(start, end time.Time) error {
+	if !w.IsSubMinute() || start.IsZero() || !end.After(start) {
+		return nil
+	}
+
+	d, _ := w.Duration()
+	windows := int64(end.Sub(start) / d)
+	if windows <= MaxSubMinuteWindows {
+		return nil
+	}
+
+	return ierr.NewError("time range too long for window size").
+		WithHintf("Window size %s supports ranges of up to %s, please use a shorter range or a longer window size", w, time.Duration(MaxSubMinuteWindows)*d).
+		WithReportableDetails(
+			map[string]any{
+				"window_size": w,
+				"start_time":  start,
+				"end_time":    end,
+				"windows":     windows,
+				"max_windows": MaxSubMinuteWindows,
+			},
+		).
+		Mark(ierr.ErrValidation)
+}
+
+// Compare orders window sizes from the shortest to the longest window
+func (w WindowSize) Compare(other WindowSize) int {
+	return lo.IndexOf(windowSizes, w) - lo.IndexOf(windowSizes, other)
+}
+
+// Duration returns the fixed length of the window. WEEK and MONTH windows follow the
+// calendar (and the billing anchor for MONTH) and have no fixed length.
+func (w WindowSize) Duration() (time.Duration, bool) {
+	switch w {
+	case WindowSizeSecond:
+		return time.Second, true
+	case WindowSize10Sec:
+		return 10 * time.Second, true
+	case WindowSize30Sec:
+		return 30 * time.Second, true
+	case WindowSizeMinute:
+		return time.Minute, true
+	case WindowSize15Min:
+		return 15 * time.Minute, true
+	case WindowSize30Min:
+		return 30 * time.Minute, true
+	case WindowSizeHour:
+		return time.Hour, true
+	case WindowSize3Hour:
+		return 3 * time.Hour, true
+	case WindowSize6Hour:
+		return 6 * time.Hour, true
+	case WindowSize12Hour:
+		return 12 * time.Hour, true
+	case WindowSizeDay:
+		return 24 * time.Hour, true
+	default:
+		return 0, false
+	}
+}
+
+// CanRollUpTo reports whether every target window is made up of whole windows of size w,
+// so points bucketed by w can be combined into target windows without querying again
+func (w WindowSize) CanRollUpTo(target WindowSize) bool {
+	from, ok := w.Duration()
+	if !ok {
+		return false
+	}
+	to, ok := target.Duration()
+	if !ok {
+		return false
+	}
+	return to > from && to%from == 0
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowSizeCanRollUpTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     WindowSize
+		to       WindowSize
+		expected bool
+	}{
+		{name: "hour to day", from: WindowSizeHour, to: WindowSizeDay, expected: true},
+		{name: "second to minute", from: WindowSizeSecond, to: WindowSizeMinute, expected: true},
+		{name: "10 seconds to 30 seconds", from: WindowSize10Sec, to: WindowSize30Sec, expected: true},
+		{name: "15 minutes to 3 hours", from: WindowSize15Min, to: WindowSize3Hour, expected: true},
+		{name: "3 hours to 12 hours", from: WindowSize3Hour, to: WindowSize12Hour, expected: true},
+		{name: "same window", from: WindowSizeHour, to: WindowSizeHour, expected: false},
+		{name: "coarser to finer", from: WindowSizeDay, to: WindowSizeHour, expected: false},
+		{name: "day to week", from: WindowSizeDay, to: WindowSizeWeek, expected: false},
+		{name: "hour to month", from: WindowSizeHour, to: WindowSizeMonth, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanRollUpTo(tt.to))
+		})
+	}
+}
+
+func TestWindowSizeCompare(t *testing.T) {
+	assert.Negative(t, WindowSizeHour.Compare(WindowSizeDay))
+	assert.Positive(t, WindowSizeMonth.Compare(WindowSizeWeek))
+	assert.Zero(t, WindowSize15Min.Compare(WindowSize15Min))
+}
+
+func TestWindowSizeValidateRange(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		windowSize WindowSize
+		start      time.Time
+		end        time.Time
+		wantErr    bool
+	}{
+		{name: "second window over an hour", windowSize: WindowSizeSecond, start: start, end: start.Add(time.Hour)},
+		{name: "second window over six hours", windowSize: WindowSizeSecond, start: start, end: start.Add(6 * time.Hour)},
+		{name: "second window over a day", windowSize: WindowSizeSecond, start: start, end: start.Add(24 * time.Hour), wantErr: true},
+		{name: "30 second window over a day", windowSize: WindowSize30Sec, start: start, end: start.Add(24 * time.Hour)},
+		{name: "30 second window over a month", windowSize: WindowSize30Sec, start: start, end: start.AddDate(0, 1, 0), wantErr: true},
+		{name: "second window without start time", windowSize: WindowSizeSecond, end: start},
+		{name: "minute window over a year", windowSize: WindowSizeMinute, start: start, end: start.AddDate(1, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, tt.windowSize.Validate())
+
+			err := tt.windowSize.ValidateRange(tt.start, tt.end)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
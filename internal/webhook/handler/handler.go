@@ -126,7 +126,7 @@ func (h *handler) processMessageSvix(ctx context.Context, event *types.WebhookEv
 	}
 
 	// Send to Svix
-	if err := h.svixClient.SendMessage(ctx, appID, event.EventName, json.RawMessage(webHookPayload)); err != nil {
+	if err := h.svixClient.SendMessage(ctx, appID, event.EventName, json.RawMessage(webHookPayload), event.IdempotencyKey); err != nil {
 		h.logger.Errorw("failed to send webhook via Svix",
 			"error", err,
 			"message_uuid", messageUUID,
@@ -199,11 +199,20 @@ func (h *handler) processMessageNative(ctx context.Context, event *types.Webhook
 		"payload", string(webHookPayload),
 	)
 
-	// Send webhook
+	// Send webhook, receivers can drop repeated events carrying the same idempotency key
+	headers := tenantCfg.Headers
+	if event.IdempotencyKey != "" {
+		headers = make(map[string]string, len(tenantCfg.Headers)+1)
+		for k, v := range tenantCfg.Headers {
+			headers[k] = v
+		}
+		headers[types.HeaderIdempotencyKey] = event.IdempotencyKey
+	}
+
 	req := &httpclient.Request{
 		Method:  "POST",
 		URL:     tenantCfg.Endpoint,
-		Headers: tenantCfg.Headers,
+		Headers: headers,
 		Body:    webHookPayload,
 	}
 
@@ -0,0 +1,5 @@
+ALTER TABLE flexprice.feature_usage
+ADD COLUMN IF NOT EXISTS correlation_id String DEFAULT '' AFTER sign;
+
+ALTER TABLE flexprice.feature_usage
+ADD INDEX IF NOT EXISTS bf_correlation_id correlation_id TYPE bloom_filter(0.01) GRANULARITY 128;
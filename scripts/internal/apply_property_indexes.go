@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/flexprice/flexprice/internal/cache"
+	"github.com/flexprice/flexprice/internal/clickhouse"
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/domain/meter"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/postgres"
+	chRepo "github.com/flexprice/flexprice/internal/repository/clickhouse"
+	entRepo "github.com/flexprice/flexprice/internal/repository/ent"
+	"github.com/flexprice/flexprice/internal/sentry"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
+)
+
+// indexedProperties returns the properties indexed by the published meters
+func indexedProperties(meters []*meter.Meter) []string {
+	properties := make([]string, 0)
+	for _, m := range meters {
+		if m.Status != types.StatusPublished {
+			continue
+		}
+		properties = append(properties, m.IndexedProperties...)
+	}
+	return properties
+}
+
+// ApplyPropertyIndexes brings the property skip indexes of the feature usage tables in line with
+// the indexed properties of the meters of the tenants operators enabled property indexes for.
+// The properties of a tenant are indexed on the table its usage is written to, the shared table
+// or its dedicated one. An index is added while any published meter of an enabled tenant of the
+// table indexes its property and dropped afterwards, up to the configured number of indexes.
+//
+// Environment variables:
+//   - DRY_RUN: when true, only reports the statements
+func ApplyPropertyIndexes() error {
+	dryRun := os.Getenv("DRY_RUN") == "true"
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log, err := logger.NewLogger(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	entClient, err := postgres.NewEntClients(cfg, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	sentryService := sentry.NewSentryService(cfg, log)
+	client := postgres.NewClient(entClient, log, sentryService)
+	cacheClient := cache.NewInMemoryCache()
+
+	chStore, err := clickhouse.NewClickHouseStore(cfg, sentryService)
+	if err != nil {
+		return fmt.Errorf("failed to connect to clickhouse: %w", err)
+	}
+
+	tenantRepo := entRepo.NewTenantRepository(client, log, cacheClient)
+	meterRepo := entRepo.NewMeterRepository(client, log, cacheClient)
+	propertyIndexRepo := chRepo.NewPropertyIndexRepository(chStore, log)
+
+	ctx := context.Background()
+	tenants, err := tenantRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	enabled := lo.SliceToMap(cfg.ClickHouse.PropertyIndexes.TenantIDs, func(tenantID string) (string, bool) {
+		return tenantID, true
+	})
+
+	// The usage of a tenant is indexed on the table it's written to
+	propertiesByTable := make(map[string][]string)
+	for _, t := range tenants {
+		if !enabled[t.ID] {
+			continue
+		}
+		tenantCtx := context.WithValue(ctx, types.CtxTenantID, t.ID)
+		meters, err := meterRepo.ListAll(tenantCtx, types.NewNoLimitMeterFilter())
+		if err != nil {
+			return fmt.Errorf("failed to list meters of tenant %s: %w", t.ID, err)
+		}
+		table := propertyIndexRepo.FeatureUsageTable(t.ID)
+		propertiesByTable[table] = append(propertiesByTable[table], indexedProperties(meters)...)
+	}
+
+	// Every table is planned so the indexes of the tables without indexed properties are dropped
+	for _, table := range propertyIndexRepo.FeatureUsageTables() {
+		existing, err := propertyIndexRepo.ListIndexes(ctx, table)
+		if err != nil {
+			return fmt.Errorf("failed to list property indexes of %s: %w", table, err)
+		}
+
+		plan := chRepo.PlanPropertyIndexes(table, propertiesByTable[table], existing, cfg.ClickHouse.PropertyIndexes.MaxIndexes)
+		for _, statement := range plan.Statements {
+			log.Infow("property index statement", "table", table, "statement", statement)
+		}
+		if len(plan.Skipped) > 0 {
+			log.Warnw("indexed properties over the max indexes are not indexed",
+				"table", table,
+				"max_indexes", cfg.ClickHouse.PropertyIndexes.MaxIndexes,
+				"skipped", plan.Skipped,
+			)
+		}
+
+		if !dryRun && !plan.IsEmpty() {
+			if err := propertyIndexRepo.Apply(ctx, plan); err != nil {
+				return fmt.Errorf("failed to apply property indexes of %s: %w", table, err)
+			}
+		}
+
+		log.Infow("planned property indexes",
+			"dry_run", dryRun,
+			"table", table,
+			"existing", len(existing),
+			"added", len(plan.Add),
+			"dropped", len(plan.Drop),
+		)
+	}
+
+	log.Infow("applied property indexes",
+		"dry_run", dryRun,
+		"tenants", len(tenants),
+		"enabled_tenants", len(enabled),
+	)
+	return nil
+}
@@ -189,7 +189,7 @@ func newAssignPlanScript() (*assignPlanScript, error) {
 	cacheClient := cache.NewInMemoryCache()
 
 	// Create repositories
-	customerRepo := entRepo.NewCustomerRepository(client, log, cacheClient)
+	customerRepo := entRepo.NewCustomerRepository(client, log, cacheClient, cfg.Customer.LookupKeyConflictPolicy)
 	planRepo := entRepo.NewPlanRepository(client, log, cacheClient)
 	subscriptionRepo := entRepo.NewSubscriptionRepository(client, log, cacheClient)
 	priceRepo := entRepo.NewPriceRepository(client, log, cacheClient)
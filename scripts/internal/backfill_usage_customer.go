@@ -0,0 +1,228 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/cache"
+	"github.com/flexprice/flexprice/internal/clickhouse"
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/domain/customer"
+	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
+	ierr "github.com/flexprice/flexprice/internal/errors"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/postgres"
+	chRepo "github.com/flexprice/flexprice/internal/repository/clickhouse"
+	entRepo "github.com/flexprice/flexprice/internal/repository/ent"
+	"github.com/flexprice/flexprice/internal/sentry"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// UsageCustomerBackfillResult summarizes a backfill of the customer id of feature usage rows
+type UsageCustomerBackfillResult struct {
+	Resolved int
+	// Unresolved are the event IDs of the rows whose customer couldn't be found
+	Unresolved []string
+}
+
+// usageCustomerResolver resolves the customer of feature usage rows by external customer id and
+// falls back to the customer of their subscription
+type usageCustomerResolver struct {
+	customerRepo   customer.Repository
+	subRepo        subscription.Repository
+	byExternalID   map[string]string
+	bySubscription map[string]string
+}
+
+func newUsageCustomerResolver(customerRepo customer.Repository, subRepo subscription.Repository) *usageCustomerResolver {
+	return &usageCustomerResolver{
+		customerRepo:   customerRepo,
+		subRepo:        subRepo,
+		byExternalID:   make(map[string]string),
+		bySubscription: make(map[string]string),
+	}
+}
+
+// resolve returns the customer id of the row, empty when it can't be resolved
+func (r *usageCustomerResolver) resolve(ctx context.Context, usage *events.FeatureUsage) (string, error) {
+	if usage.ExternalCustomerID != "" {
+		customerID, ok := r.byExternalID[usage.ExternalCustomerID]
+		if !ok {
+			c, err := r.customerRepo.GetByLookupKey(ctx, usage.ExternalCustomerID)
+			if err != nil && !ierr.IsNotFound(err) {
+				return "", err
+			}
+			if c != nil {
+				customerID = c.ID
+			}
+			r.byExternalID[usage.ExternalCustomerID] = customerID
+		}
+		if customerID != "" {
+			return customerID, nil
+		}
+	}
+
+	if usage.SubscriptionID != "" {
+		customerID, ok := r.bySubscription[usage.SubscriptionID]
+		if !ok {
+			sub, err := r.subRepo.Get(ctx, usage.SubscriptionID)
+			if err != nil && !ierr.IsNotFound(err) {
+				return "", err
+			}
+			if sub != nil {
+				customerID = sub.CustomerID
+			}
+			r.bySubscription[usage.SubscriptionID] = customerID
+		}
+		return customerID, nil
+	}
+
+	return "", nil
+}
+
+// backfillUsageCustomers sets the customer id of the feature usage rows in the window that have
+// none. Feature usage is keyed by customer, so each row is cancelled and inserted again with its
+// customer, and added to the usage counters when they are kept. Unresolved rows are left as is and skipped when paging through the next batches.
+func backfillUsageCustomers(ctx context.Context, featureUsageRepo events.FeatureUsageRepository, usageCounterRepo events.UsageCounterRepository, resolver *usageCustomerResolver, startTime, endTime time.Time, batchSize int) (*UsageCustomerBackfillResult, error) {
+	result := &UsageCustomerBackfillResult{Unresolved: make([]string, 0)}
+
+	for {
+		rows, err := featureUsageRepo.FindUsageWithoutCustomer(ctx, &events.FindUsageWithoutCustomerParams{
+			StartTime: startTime,
+			EndTime:   endTime,
+			Limit:     batchSize,
+			Offset:    len(result.Unresolved),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		cancelled := make([]*events.FeatureUsage, 0, len(rows))
+		updated := make([]*events.FeatureUsage, 0, len(rows))
+		for _, row := range rows {
+			customerID, err := resolver.resolve(ctx, row)
+			if err != nil {
+				return nil, err
+			}
+			if customerID == "" {
+				result.Unresolved = append(result.Unresolved, row.ID)
+				continue
+			}
+
+			usage := *row
+			usage.CustomerID = customerID
+			cancelled = append(cancelled, row)
+			updated = append(updated, &usage)
+			result.Resolved++
+		}
+
+		if len(updated) > 0 {
+			if err := replaceFeatureUsage(ctx, featureUsageRepo, usageCounterRepo, cancelled, updated); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(rows) < batchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// BackfillUsageCustomers re-derives the customer id of feature usage rows recorded without one,
+// by external customer id or else by subscription, and persists it.
+//
+// Environment variables:
+//   - TENANT_ID: tenant to backfill
+//   - ENVIRONMENT_ID: environment to backfill
+//   - START_TIME, END_TIME: window of the usage in RFC3339, e.g. 2006-01-02T15:04:05Z
+//   - BATCH_SIZE: rows read per batch, defaults to 500
+func BackfillUsageCustomers() error {
+	tenantID := os.Getenv("TENANT_ID")
+	environmentID := os.Getenv("ENVIRONMENT_ID")
+	if tenantID == "" || environmentID == "" {
+		return fmt.Errorf("TENANT_ID and ENVIRONMENT_ID are required")
+	}
+
+	startTime, err := time.Parse(time.RFC3339, os.Getenv("START_TIME"))
+	if err != nil {
+		return fmt.Errorf("invalid START_TIME, use RFC3339 (2006-01-02T15:04:05Z): %w", err)
+	}
+	endTime, err := time.Parse(time.RFC3339, os.Getenv("END_TIME"))
+	if err != nil {
+		return fmt.Errorf("invalid END_TIME, use RFC3339 (2006-01-02T15:04:05Z): %w", err)
+	}
+	if !endTime.After(startTime) {
+		return fmt.Errorf("END_TIME must be after START_TIME")
+	}
+
+	batchSize := 500
+	if batchSizeStr := os.Getenv("BATCH_SIZE"); batchSizeStr != "" {
+		batchSize, err = strconv.Atoi(batchSizeStr)
+		if err != nil || batchSize <= 0 {
+			return fmt.Errorf("invalid BATCH_SIZE, must be a positive integer")
+		}
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log, err := logger.NewLogger(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	entClient, err := postgres.NewEntClients(cfg, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	sentryService := sentry.NewSentryService(cfg, log)
+	client := postgres.NewClient(entClient, log, sentryService)
+	cacheClient := cache.NewInMemoryCache()
+
+	chStore, err := clickhouse.NewClickHouseStore(cfg, sentryService)
+	if err != nil {
+		return fmt.Errorf("failed to connect to clickhouse: %w", err)
+	}
+
+	featureUsageRepo := chRepo.NewFeatureUsageRepository(chStore, log)
+	var usageCounterRepo events.UsageCounterRepository
+	if cfg.FeatureUsageTracking.UsageCounterSinkEnabled {
+		usageCounterRepo = entRepo.NewUsageCounterRepository(client, log)
+	}
+	resolver := newUsageCustomerResolver(
+		entRepo.NewCustomerRepository(client, log, cacheClient, cfg.Customer.LookupKeyConflictPolicy),
+		entRepo.NewSubscriptionRepository(client, log, cacheClient),
+	)
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, types.CtxTenantID, tenantID)
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, environmentID)
+
+	result, err := backfillUsageCustomers(ctx, featureUsageRepo, usageCounterRepo, resolver, startTime, endTime, batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to backfill feature usage customers: %w", err)
+	}
+
+	for _, eventID := range result.Unresolved {
+		log.Warnw("could not resolve customer of feature usage", "event_id", eventID)
+	}
+
+	log.Infow("backfilled feature usage customers",
+		"tenant_id", tenantID,
+		"environment_id", environmentID,
+		"resolved", result.Resolved,
+		"unresolved", len(result.Unresolved),
+	)
+	return nil
+}
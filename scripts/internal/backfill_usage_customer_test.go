@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/domain/customer"
+	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
+	"github.com/flexprice/flexprice/internal/testutil"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillUsageCustomers(t *testing.T) {
+	ctx := context.WithValue(context.Background(), types.CtxTenantID, types.DefaultTenantID)
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, "env_backfill")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	customerRepo := testutil.NewInMemoryCustomerStore()
+	require.NoError(t, customerRepo.Create(ctx, &customer.Customer{
+		ID:            "cust_by_external_id",
+		ExternalID:    "ext_known",
+		EnvironmentID: "env_backfill",
+		BaseModel:     types.GetDefaultBaseModel(ctx),
+	}))
+
+	subRepo := testutil.NewInMemorySubscriptionStore()
+	require.NoError(t, subRepo.Create(ctx, &subscription.Subscription{
+		ID:            "sub_backfill",
+		CustomerID:    "cust_by_subscription",
+		EnvironmentID: "env_backfill",
+		BaseModel:     types.GetDefaultBaseModel(ctx),
+	}))
+
+	featureUsageRepo := testutil.NewInMemoryFeatureUsageStore()
+	newUsage := func(id, externalCustomerID, customerID, subscriptionID string, timestamp time.Time) *events.FeatureUsage {
+		return &events.FeatureUsage{
+			Event: events.Event{
+				ID:                 id,
+				TenantID:           types.DefaultTenantID,
+				EnvironmentID:      "env_backfill",
+				ExternalCustomerID: externalCustomerID,
+				CustomerID:         customerID,
+				Timestamp:          timestamp,
+			},
+			SubscriptionID: subscriptionID,
+			FeatureID:      "feat_backfill",
+			QtyTotal:       decimal.NewFromInt(1),
+			Sign:           1,
+		}
+	}
+	require.NoError(t, featureUsageRepo.BulkInsertProcessedEvents(ctx, []*events.FeatureUsage{
+		newUsage("evt_by_external_id", "ext_known", "", "", now.Add(-4*time.Hour)),
+		newUsage("evt_unresolvable", "ext_unknown", "", "sub_missing", now.Add(-3*time.Hour)),
+		newUsage("evt_by_subscription", "ext_unknown", "", "sub_backfill", now.Add(-2*time.Hour)),
+		newUsage("evt_already_set", "ext_known", "cust_existing", "", now.Add(-time.Hour)),
+		newUsage("evt_outside_window", "ext_known", "", "", now.Add(-48*time.Hour)),
+	}))
+
+	usageCounterRepo := testutil.NewInMemoryUsageCounterStore()
+	resolver := newUsageCustomerResolver(customerRepo, subRepo)
+	// A batch size of one pages past the unresolvable row
+	result, err := backfillUsageCustomers(ctx, featureUsageRepo, usageCounterRepo, resolver, now.Add(-24*time.Hour), now, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Resolved)
+	assert.Equal(t, []string{"evt_unresolvable"}, result.Unresolved)
+
+	customerIDs := make(map[string]string)
+	for _, id := range []string{"evt_by_external_id", "evt_unresolvable", "evt_by_subscription", "evt_already_set", "evt_outside_window"} {
+		usage, err := featureUsageRepo.Get(ctx, id)
+		require.NoError(t, err)
+		customerIDs[id] = usage.CustomerID
+	}
+	assert.Equal(t, map[string]string{
+		"evt_by_external_id":  "cust_by_external_id",
+		"evt_unresolvable":    "",
+		"evt_by_subscription": "cust_by_subscription",
+		"evt_already_set":     "cust_existing",
+		"evt_outside_window":  "",
+	}, customerIDs)
+
+	// the row is replaced, its usage is counted once
+	rows := featureUsageRepo.Rows("evt_by_external_id")
+	require.Len(t, rows, 2)
+	assert.Equal(t, "", rows[0].CustomerID)
+	assert.Equal(t, int8(0), rows[0].Sign)
+	assert.Equal(t, "cust_by_external_id", rows[1].CustomerID)
+	assert.Equal(t, int8(1), rows[1].Sign)
+	assert.True(t, rows[1].QtyTotal.Equal(decimal.NewFromInt(1)))
+
+	live, _, err := featureUsageRepo.GetProcessedEvents(ctx, &events.GetProcessedEventsParams{})
+	require.NoError(t, err)
+	liveQty := decimal.Zero
+	for _, usage := range live {
+		liveQty = liveQty.Add(usage.QtyTotal.Mul(decimal.NewFromInt(int64(usage.Sign))))
+	}
+	assert.True(t, liveQty.Equal(decimal.NewFromInt(5)), "live usage is %s", liveQty)
+
+	// the usage billed to the subscription counts the replaced row once
+	billed, err := featureUsageRepo.GetFeatureUsageBySubscription(ctx, "sub_backfill", "ext_unknown", now.Add(-24*time.Hour), now)
+	require.NoError(t, err)
+	require.Len(t, billed, 1)
+	for _, usage := range billed {
+		assert.True(t, usage.SumTotal.Equal(decimal.NewFromInt(1)), "billed usage is %s", usage.SumTotal)
+	}
+
+	// usage without a customer isn't counted until its customer is resolved
+	counterValue := func(customerID string) int64 {
+		counter, err := usageCounterRepo.Get(ctx, customerID, "feat_backfill", 0)
+		require.NoError(t, err)
+		require.NotNil(t, counter)
+		return counter.Value.IntPart()
+	}
+	assert.Equal(t, int64(1), counterValue("cust_by_external_id"))
+	assert.Equal(t, int64(1), counterValue("cust_by_subscription"))
+
+	t.Run("nothing left to resolve", func(t *testing.T) {
+		result, err := backfillUsageCustomers(ctx, featureUsageRepo, usageCounterRepo, resolver, now.Add(-24*time.Hour), now, 10)
+		require.NoError(t, err)
+		assert.Zero(t, result.Resolved)
+		assert.Equal(t, []string{"evt_unresolvable"}, result.Unresolved)
+		assert.Equal(t, int64(1), counterValue("cust_by_external_id"))
+	})
+}
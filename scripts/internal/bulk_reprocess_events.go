@@ -233,7 +233,7 @@ func newBulkReprocessEventsScript() (*BulkReprocessEventsScript, error) {
 	// Initialize repositories
 	eventRepo := chRepo.NewEventRepository(chStore, log)
 	processedEventRepo := chRepo.NewProcessedEventRepository(chStore, log)
-	customerRepo := entRepo.NewCustomerRepository(pgClient, log, cacheClient)
+	customerRepo := entRepo.NewCustomerRepository(pgClient, log, cacheClient, cfg.Customer.LookupKeyConflictPolicy)
 	subscriptionRepo := entRepo.NewSubscriptionRepository(pgClient, log, cacheClient)
 	meterRepo := entRepo.NewMeterRepository(pgClient, log, cacheClient)
 	priceRepo := entRepo.NewPriceRepository(pgClient, log, cacheClient)
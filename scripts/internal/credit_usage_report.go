@@ -225,7 +225,7 @@ func newCreditUsageReportScript() (*creditUsageReportScript, error) {
 	cacheClient := cache.NewInMemoryCache()
 
 	// Create repositories
-	customerRepo := entRepo.NewCustomerRepository(client, log, cacheClient)
+	customerRepo := entRepo.NewCustomerRepository(client, log, cacheClient, cfg.Customer.LookupKeyConflictPolicy)
 	walletRepo := entRepo.NewWalletRepository(client, log, cacheClient)
 	subscriptionRepo := entRepo.NewSubscriptionRepository(client, log, cacheClient)
 	subscriptionLineItemRepo := entRepo.NewSubscriptionLineItemRepository(client, log, cacheClient)
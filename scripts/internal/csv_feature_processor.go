@@ -150,7 +150,7 @@ func newCSVFeatureProcessor(tenantID, environmentID, userID string) (*CSVFeature
 	}
 
 	// Create service instances once for reuse
-	meterService := service.NewMeterService(meterRepo)
+	meterService := service.NewMeterService(meterRepo, nil, log)
 	featureService := service.NewFeatureService(serviceParams)
 	priceService := service.NewPriceService(serviceParams)
 	addonService := service.NewAddonService(serviceParams)
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/pubsub/kafka"
+)
+
+// SeekKafkaOffsets moves the committed offsets of a consumer group on a topic to a timestamp so
+// the group replays the messages published since then. Stop every consumer of the group before
+// running it, running consumers overwrite the offsets on their next commit.
+//
+// Environment variables:
+//   - TOPIC: topic to seek, defaults to the configured kafka topic
+//   - CONSUMER_GROUP: consumer group to seek, defaults to the configured consumer group
+//   - TIMESTAMP: time to replay from, format: 2006-01-02T15:04:05Z
+//   - DRY_RUN: set to true to only print the resolved offsets
+func SeekKafkaOffsets() error {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log, err := logger.NewLogger(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	topic := os.Getenv("TOPIC")
+	if topic == "" {
+		topic = cfg.Kafka.Topic
+	}
+
+	group := os.Getenv("CONSUMER_GROUP")
+	if group == "" {
+		group = cfg.Kafka.ConsumerGroup
+	}
+
+	timestampStr := os.Getenv("TIMESTAMP")
+	if timestampStr == "" {
+		return fmt.Errorf("TIMESTAMP environment variable is required")
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, timestampStr)
+	if err != nil {
+		return fmt.Errorf("invalid TIMESTAMP format, use ISO-8601 (2006-01-02T15:04:05Z): %w", err)
+	}
+
+	dryRun := strings.EqualFold(os.Getenv("DRY_RUN"), "true")
+
+	seeker, err := kafka.NewOffsetSeeker(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to kafka: %w", err)
+	}
+	defer seeker.Close()
+
+	log.Infow("Seeking consumer group offsets",
+		"topic", topic,
+		"consumer_group", group,
+		"timestamp", timestamp,
+		"dry_run", dryRun,
+	)
+
+	offsets, err := kafka.SeekConsumerGroupToTime(seeker, group, topic, timestamp, dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to seek consumer group offsets: %w", err)
+	}
+
+	for _, offset := range offsets {
+		log.Infow("Partition offset", "partition", offset.Partition, "offset", offset.Offset)
+	}
+
+	if dryRun {
+		log.Infow("Dry run, offsets were not committed", "partitions", len(offsets))
+		return nil
+	}
+
+	log.Infow("Committed consumer group offsets", "partitions", len(offsets))
+	return nil
+}
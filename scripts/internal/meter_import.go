@@ -0,0 +1,339 @@
+package internal
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/cache"
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/domain/meter"
+	"github.com/flexprice/flexprice/internal/domain/price"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/postgres"
+	entRepo "github.com/flexprice/flexprice/internal/repository/ent"
+	"github.com/flexprice/flexprice/internal/sentry"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
+)
+
+// MeterSpec is a meter of the meter import spec. Meters are matched to the existing meters of
+// the environment by name.
+type MeterSpec struct {
+	Name             string                `json:"name"`
+	EventName        string                `json:"event_name"`
+	AggregationType  types.AggregationType `json:"aggregation_type"`
+	AggregationField string                `json:"aggregation_field,omitempty"`
+	Filters          []meter.Filter        `json:"filters,omitempty"`
+	BucketSize       types.WindowSize      `json:"bucket_size,omitempty"`
+}
+
+// MeterImportSummary contains statistics about the meter import
+type MeterImportSummary struct {
+	TotalSpecs     int
+	MetersCreated  int
+	MetersUpdated  int
+	MetersReplaced int
+	MetersSkipped  int
+	Errors         []string
+}
+
+// parseMeterSpecs parses a meter spec, a JSON array of meters or a CSV file with a header row of
+// name, event_name, aggregation_type, aggregation_field, filters and bucket_size. The filters of
+// a CSV row are a JSON array, e.g. [{"key":"region","values":["us","eu"]}].
+func parseMeterSpecs(r io.Reader, format string) ([]MeterSpec, error) {
+	switch format {
+	case "json":
+		var specs []MeterSpec
+		if err := json.NewDecoder(r).Decode(&specs); err != nil {
+			return nil, fmt.Errorf("failed to decode meter spec: %w", err)
+		}
+		return specs, nil
+	case "csv":
+		return parseMeterSpecCSV(r)
+	default:
+		return nil, fmt.Errorf("unsupported meter spec format %q, expected json or csv", format)
+	}
+}
+
+func parseMeterSpecCSV(r io.Reader) ([]MeterSpec, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.TrimSpace(strings.ToLower(column))] = i
+	}
+	for _, required := range []string{"name", "event_name", "aggregation_type"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("meter spec CSV is missing the %s column", required)
+		}
+	}
+	value := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var specs []MeterSpec
+	for line := 2; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV line %d: %w", line, err)
+		}
+
+		spec := MeterSpec{
+			Name:             value(record, "name"),
+			EventName:        value(record, "event_name"),
+			AggregationType:  types.AggregationType(strings.ToUpper(value(record, "aggregation_type"))),
+			AggregationField: value(record, "aggregation_field"),
+			BucketSize:       types.WindowSize(strings.ToUpper(value(record, "bucket_size"))),
+		}
+		if filters := value(record, "filters"); filters != "" {
+			if err := json.Unmarshal([]byte(filters), &spec.Filters); err != nil {
+				return nil, fmt.Errorf("failed to parse the filters of CSV line %d: %w", line, err)
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// normalizeMeterFilters sorts the filters by key and their values, so filters listed in a
+// different order compare equal
+func normalizeMeterFilters(filters []meter.Filter) []meter.Filter {
+	normalized := make([]meter.Filter, 0, len(filters))
+	for _, filter := range filters {
+		values := append([]string(nil), filter.Values...)
+		sort.Strings(values)
+		normalized = append(normalized, meter.Filter{Key: filter.Key, Values: values})
+	}
+	sort.SliceStable(normalized, func(i, j int) bool {
+		return normalized[i].Key < normalized[j].Key
+	})
+	return normalized
+}
+
+// importMeters creates the meters of the specs that don't exist yet and updates the existing
+// meters whose definition differs from their spec. Meters that already match are skipped, so
+// importing the same spec twice changes nothing. Specs that fail are reported in the summary
+// and don't stop the import.
+//
+// The event name and aggregation of a meter are immutable. When they change, the meter is
+// archived and replaced by a new meter of the spec, unless active prices use it, in which case
+// the spec is reported as an error. Only the filters of a meter are updated in place.
+func importMeters(ctx context.Context, log *logger.Logger, meterRepo meter.Repository, priceRepo price.Repository, specs []MeterSpec) (*MeterImportSummary, error) {
+	summary := &MeterImportSummary{TotalSpecs: len(specs)}
+
+	meters, err := meterRepo.ListAll(ctx, types.NewNoLimitMeterFilter())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list meters: %w", err)
+	}
+	existing := make(map[string]*meter.Meter, len(meters))
+	for _, m := range meters {
+		if m.Status == types.StatusPublished {
+			existing[m.Name] = m
+		}
+	}
+
+	// createMeter creates the meter of the spec and returns it, or nil when it fails
+	createMeter := func(i int, spec MeterSpec, req *dto.CreateMeterRequest) *meter.Meter {
+		if err := req.Validate(); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("spec %d: meter %q is invalid: %v", i, spec.Name, err))
+			return nil
+		}
+		m := req.ToMeter(types.GetTenantID(ctx), types.GetUserID(ctx))
+		m.EnvironmentID = types.GetEnvironmentID(ctx)
+		if err := m.Validate(); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("spec %d: meter %q is invalid: %v", i, spec.Name, err))
+			return nil
+		}
+		if err := meterRepo.CreateMeter(ctx, m); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("spec %d: failed to create meter %q: %v", i, spec.Name, err))
+			return nil
+		}
+		return m
+	}
+
+	seen := make(map[string]bool, len(specs))
+	for i, spec := range specs {
+		if seen[spec.Name] {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("spec %d: meter %q is listed more than once", i, spec.Name))
+			continue
+		}
+		seen[spec.Name] = true
+
+		req := &dto.CreateMeterRequest{
+			Name:      spec.Name,
+			EventName: spec.EventName,
+			Aggregation: meter.Aggregation{
+				Type:       spec.AggregationType,
+				Field:      spec.AggregationField,
+				BucketSize: spec.BucketSize,
+			},
+			Filters:    normalizeMeterFilters(spec.Filters),
+			ResetUsage: types.ResetUsageBillingPeriod,
+		}
+
+		current, ok := existing[spec.Name]
+		if !ok {
+			m := createMeter(i, spec, req)
+			if m == nil {
+				continue
+			}
+			summary.MetersCreated++
+			log.Infow("created meter", "meter_id", m.ID, "name", m.Name, "event_name", m.EventName)
+			continue
+		}
+
+		definitionChanged := spec.EventName != current.EventName ||
+			spec.AggregationType != current.Aggregation.Type ||
+			spec.AggregationField != current.Aggregation.Field ||
+			spec.BucketSize != current.Aggregation.BucketSize
+		filtersChanged := !reflect.DeepEqual(req.Filters, normalizeMeterFilters(current.Filters))
+		if !definitionChanged && !filtersChanged {
+			summary.MetersSkipped++
+			continue
+		}
+
+		if !definitionChanged {
+			if err := meterRepo.UpdateMeter(ctx, current.ID, req.Filters); err != nil {
+				summary.Errors = append(summary.Errors, fmt.Sprintf("spec %d: failed to update meter %q: %v", i, spec.Name, err))
+				continue
+			}
+			summary.MetersUpdated++
+			log.Infow("updated meter filters", "meter_id", current.ID, "name", current.Name)
+			continue
+		}
+
+		priceFilter := types.NewNoLimitPriceFilter()
+		priceFilter.MeterIDs = []string{current.ID}
+		priceFilter.QueryFilter.Status = lo.ToPtr(types.StatusPublished)
+		activePrices, err := priceRepo.Count(ctx, priceFilter)
+		if err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("spec %d: failed to list the prices of meter %q: %v", i, spec.Name, err))
+			continue
+		}
+		if activePrices > 0 {
+			summary.Errors = append(summary.Errors, fmt.Sprintf(
+				"spec %d: meter %q has %d active prices, its event name and aggregation can't change, import it under a new name",
+				i, spec.Name, activePrices))
+			continue
+		}
+
+		// the replacement is created before the meter is archived, so a failure keeps the meter
+		replacement := createMeter(i, spec, req)
+		if replacement == nil {
+			continue
+		}
+		if err := meterRepo.DisableMeter(ctx, current.ID); err != nil {
+			summary.Errors = append(summary.Errors, fmt.Sprintf("spec %d: failed to archive meter %q replaced by %s: %v", i, spec.Name, replacement.ID, err))
+			continue
+		}
+		summary.MetersReplaced++
+		log.Infow("replaced meter",
+			"meter_id", current.ID,
+			"replacement_meter_id", replacement.ID,
+			"name", current.Name,
+			"event_name", replacement.EventName,
+		)
+	}
+
+	return summary, nil
+}
+
+// ImportMeters creates and updates the meters of a tenant environment from a spec file, see
+// parseMeterSpecs for its format. Existing meters are matched by name, meters already matching
+// their spec are skipped. Running servers cache meters, so they see updated filters once their
+// cached meter expires.
+//
+// Environment variables:
+//   - FILE_PATH: the JSON or CSV meter spec
+//   - TENANT_ID: tenant to import the meters into
+//   - ENVIRONMENT_ID: environment to import the meters into
+func ImportMeters() error {
+	filePath := os.Getenv("FILE_PATH")
+	tenantID := os.Getenv("TENANT_ID")
+	environmentID := os.Getenv("ENVIRONMENT_ID")
+	if filePath == "" {
+		return fmt.Errorf("file path is required")
+	}
+	if tenantID == "" || environmentID == "" {
+		return fmt.Errorf("TENANT_ID and ENVIRONMENT_ID are required")
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	specs, err := parseMeterSpecs(file, strings.TrimPrefix(strings.ToLower(filepath.Ext(filePath)), "."))
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log, err := logger.NewLogger(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	entClients, err := postgres.NewEntClients(cfg, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	client := postgres.NewClient(entClients, log, sentry.NewSentryService(cfg, log))
+	cacheClient := cache.NewInMemoryCache()
+	meterRepo := entRepo.NewMeterRepository(client, log, cacheClient)
+	priceRepo := entRepo.NewPriceRepository(client, log, cacheClient)
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, types.CtxTenantID, tenantID)
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, environmentID)
+
+	log.Infow("starting meter import",
+		"file", filePath,
+		"spec_count", len(specs),
+		"tenant_id", tenantID,
+		"environment_id", environmentID,
+	)
+
+	summary, err := importMeters(ctx, log, meterRepo, priceRepo, specs)
+	if err != nil {
+		return err
+	}
+
+	log.Infow("meter import summary",
+		"total_specs", summary.TotalSpecs,
+		"meters_created", summary.MetersCreated,
+		"meters_updated", summary.MetersUpdated,
+		"meters_replaced", summary.MetersReplaced,
+		"meters_skipped", summary.MetersSkipped,
+		"errors", len(summary.Errors),
+	)
+	if len(summary.Errors) > 0 {
+		log.Infow("errors encountered during meter import", "errors", summary.Errors)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/flexprice/flexprice/internal/domain/meter"
+	"github.com/flexprice/flexprice/internal/domain/price"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/testutil"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportMeters(t *testing.T) {
+	ctx := context.WithValue(context.Background(), types.CtxTenantID, types.DefaultTenantID)
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, "env_import")
+
+	meterRepo := testutil.NewInMemoryMeterStore()
+	existing := &meter.Meter{
+		ID:          "meter_api_calls",
+		Name:        "API Calls",
+		EventName:   "api_call",
+		Aggregation: meter.Aggregation{Type: types.AggregationCount},
+		Filters:     []meter.Filter{{Key: "region", Values: []string{"us"}}},
+		ResetUsage:  types.ResetUsageBillingPeriod,
+		BaseModel:   types.GetDefaultBaseModel(ctx),
+	}
+	require.NoError(t, meterRepo.CreateMeter(ctx, existing))
+
+	priceRepo := testutil.NewInMemoryPriceStore()
+
+	spec := `name,event_name,aggregation_type,aggregation_field,filters,bucket_size
+API Calls,api_call,count,,"[{""key"":""region"",""values"":[""us"",""eu""]}]",
+Tokens,llm_call,sum,tokens,,
+Peak Connections,connection,max,connections,,hour
+`
+	specs, err := parseMeterSpecs(strings.NewReader(spec), "csv")
+	require.NoError(t, err)
+	require.Len(t, specs, 3)
+	assert.Equal(t, types.AggregationMax, specs[2].AggregationType)
+	assert.Equal(t, types.WindowSizeHour, specs[2].BucketSize)
+
+	summary, err := importMeters(ctx, logger.GetLogger(), meterRepo, priceRepo, specs)
+	require.NoError(t, err)
+	assert.Empty(t, summary.Errors)
+	assert.Equal(t, 3, summary.TotalSpecs)
+	assert.Equal(t, 2, summary.MetersCreated)
+	assert.Equal(t, 1, summary.MetersUpdated)
+	assert.Equal(t, 0, summary.MetersSkipped)
+
+	// The pre-existing meter is updated in place with the filters of its spec
+	updated, err := meterRepo.GetMeter(ctx, existing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []meter.Filter{{Key: "region", Values: []string{"eu", "us"}}}, updated.Filters)
+
+	meters, err := meterRepo.ListAll(ctx, types.NewNoLimitMeterFilter())
+	require.NoError(t, err)
+	byName := make(map[string]*meter.Meter, len(meters))
+	for _, m := range meters {
+		byName[m.Name] = m
+	}
+	require.Len(t, byName, 3)
+	assert.Equal(t, "llm_call", byName["Tokens"].EventName)
+	assert.Equal(t, meter.Aggregation{Type: types.AggregationSum, Field: "tokens"}, byName["Tokens"].Aggregation)
+	assert.Equal(t, types.WindowSizeHour, byName["Peak Connections"].Aggregation.BucketSize)
+
+	t.Run("importing again changes nothing", func(t *testing.T) {
+		summary, err := importMeters(ctx, logger.GetLogger(), meterRepo, priceRepo, specs)
+		require.NoError(t, err)
+		assert.Equal(t, 0, summary.MetersCreated)
+		assert.Equal(t, 0, summary.MetersUpdated)
+		assert.Equal(t, 3, summary.MetersSkipped)
+	})
+
+	t.Run("json spec with an invalid meter", func(t *testing.T) {
+		specs, err := parseMeterSpecs(strings.NewReader(`[
+			{"name": "API Calls", "event_name": "api_call", "aggregation_type": "SUM", "aggregation_field": "calls"},
+			{"name": "Broken", "event_name": "broken", "aggregation_type": "SUM"}
+		]`), "json")
+		require.NoError(t, err)
+
+		summary, err := importMeters(ctx, logger.GetLogger(), meterRepo, priceRepo, specs)
+		require.NoError(t, err)
+		assert.Equal(t, 0, summary.MetersUpdated)
+		assert.Equal(t, 1, summary.MetersReplaced)
+		assert.Equal(t, 0, summary.MetersCreated)
+		// A sum without a field is rejected and doesn't stop the import
+		assert.Len(t, summary.Errors, 1)
+
+		// The aggregation of a meter can't change, so the meter is archived and replaced
+		archived, err := meterRepo.GetMeter(ctx, existing.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, types.StatusPublished, archived.Status)
+		assert.Equal(t, meter.Aggregation{Type: types.AggregationCount}, archived.Aggregation)
+
+		replacement := publishedMeter(t, ctx, meterRepo, "API Calls")
+		assert.NotEqual(t, existing.ID, replacement.ID)
+		assert.Equal(t, meter.Aggregation{Type: types.AggregationSum, Field: "calls"}, replacement.Aggregation)
+		assert.Empty(t, replacement.Filters)
+	})
+
+	t.Run("meter with active prices keeps its definition", func(t *testing.T) {
+		tokens := publishedMeter(t, ctx, meterRepo, "Tokens")
+		require.NoError(t, priceRepo.Create(ctx, &price.Price{
+			ID:            "price_tokens",
+			Amount:        decimal.NewFromFloat(0.01),
+			Currency:      "usd",
+			Type:          types.PRICE_TYPE_USAGE,
+			MeterID:       tokens.ID,
+			EnvironmentID: "env_import",
+			BaseModel:     types.GetDefaultBaseModel(ctx),
+		}))
+
+		specs, err := parseMeterSpecs(strings.NewReader(`[
+			{"name": "Tokens", "event_name": "llm_request", "aggregation_type": "SUM", "aggregation_field": "tokens"}
+		]`), "json")
+		require.NoError(t, err)
+
+		summary, err := importMeters(ctx, logger.GetLogger(), meterRepo, priceRepo, specs)
+		require.NoError(t, err)
+		assert.Equal(t, 0, summary.MetersReplaced)
+		require.Len(t, summary.Errors, 1)
+		assert.Contains(t, summary.Errors[0], "active prices")
+
+		unchanged := publishedMeter(t, ctx, meterRepo, "Tokens")
+		assert.Equal(t, tokens.ID, unchanged.ID)
+		assert.Equal(t, "llm_call", unchanged.EventName)
+	})
+}
+
+// publishedMeter returns the published meter of the name
+func publishedMeter(t *testing.T, ctx context.Context, meterRepo meter.Repository, name string) *meter.Meter {
+	meters, err := meterRepo.ListAll(ctx, types.NewNoLimitMeterFilter())
+	require.NoError(t, err)
+	for _, m := range meters {
+		if m.Name == name && m.Status == types.StatusPublished {
+			return m
+		}
+	}
+	require.Failf(t, "meter not found", "no published meter named %q", name)
+	return nil
+}
@@ -52,7 +52,7 @@ func SyncBillingCustomers() error {
 
 	// Initialize repositories
 	tenantRepo := ent.NewTenantRepository(client, logger, cache)
-	customerRepo := ent.NewCustomerRepository(client, logger, cache)
+	customerRepo := ent.NewCustomerRepository(client, logger, cache, cfg.Customer.LookupKeyConflictPolicy)
 	subscriptionRepo := ent.NewSubscriptionRepository(client, logger, cache)
 	invoiceRepo := ent.NewInvoiceRepository(client, logger, cache)
 	walletRepo := ent.NewWalletRepository(client, logger, cache)
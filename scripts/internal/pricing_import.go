@@ -523,12 +523,16 @@ func (s *pricingImportScript) printSummary() {
 	}
 }
 
-// ImportPricing is the main function to import pricing data from a CSV file
+// ImportPricing is the main function to import pricing data from a CSV file.
+// With DRY_RUN=true nothing is applied; instead the cost change of a sample of
+// SAMPLE_SIZE active subscriptions under the new prices is reported.
 func ImportPricing() error {
 	var filePath, tenantID, environmentID string
 	filePath = os.Getenv("FILE_PATH")
 	tenantID = os.Getenv("TENANT_ID")
 	environmentID = os.Getenv("ENVIRONMENT_ID")
+	dryRunStr := os.Getenv("DRY_RUN")
+	dryRun := dryRunStr == "true" || dryRunStr == "1"
 
 	if filePath == "" {
 		return fmt.Errorf("file path is required")
@@ -558,6 +562,24 @@ func ImportPricing() error {
 		return fmt.Errorf("failed to parse pricing CSV: %w", err)
 	}
 
+	if dryRun {
+		sampleSize := pricingDryRunSampleSize()
+		script.log.Infow("Starting pricing import dry run",
+			"file", filePath,
+			"row_count", len(pricingRows),
+			"sample_size", sampleSize,
+			"tenant_id", tenantID,
+			"environment_id", environmentID)
+
+		summary, err := script.dryRunCostDiff(ctx, pricingRows, sampleSize)
+		if err != nil {
+			return fmt.Errorf("failed to compute pricing import cost diff: %w", err)
+		}
+
+		script.printDryRunSummary(summary)
+		return nil
+	}
+
 	script.log.Infow("Starting pricing import",
 		"file", filePath,
 		"row_count", len(pricingRows),
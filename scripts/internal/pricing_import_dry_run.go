@@ -0,0 +1,271 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/cache"
+	"github.com/flexprice/flexprice/internal/clickhouse"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
+	chRepo "github.com/flexprice/flexprice/internal/repository/clickhouse"
+	entRepo "github.com/flexprice/flexprice/internal/repository/ent"
+	"github.com/flexprice/flexprice/internal/sentry"
+	"github.com/flexprice/flexprice/internal/service"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// defaultPricingDryRunSampleSize is the number of active subscriptions sampled when SAMPLE_SIZE is not set
+	defaultPricingDryRunSampleSize = 20
+
+	// pricingDryRunAlertRatio flags subscriptions whose cost changes by this factor or more in either direction
+	pricingDryRunAlertRatio = 10
+)
+
+// SubscriptionCostDiff is the cost change of a subscription's most recent period under the imported prices
+type SubscriptionCostDiff struct {
+	SubscriptionID string
+	CustomerID     string
+	Currency       string
+	CurrentCost    decimal.Decimal
+	NewCost        decimal.Decimal
+	Delta          decimal.Decimal
+	// Flagged is set when the cost changes by pricingDryRunAlertRatio or more, which
+	// usually points at a mistyped price
+	Flagged bool
+}
+
+// PricingDryRunSummary summarizes the cost impact of a pricing import over the sampled subscriptions
+type PricingDryRunSummary struct {
+	SubscriptionsSampled int
+	SubscriptionsChanged int
+	TotalCurrentCost     decimal.Decimal
+	TotalNewCost         decimal.Decimal
+	TotalDelta           decimal.Decimal
+	Diffs                []SubscriptionCostDiff
+	Errors               []string
+}
+
+// newPriceAmounts returns the per unit amount each existing price would have after the import.
+// Prices marked for deletion no longer charge anything. New prices only affect future
+// subscriptions and are not part of the diff.
+func newPriceAmounts(rows []PricingRow) map[string]decimal.Decimal {
+	amounts := make(map[string]decimal.Decimal)
+	for _, row := range rows {
+		if row.PriceID == "" {
+			continue
+		}
+
+		if strings.ToUpper(row.Delete) == "Y" {
+			amounts[row.PriceID] = decimal.Zero
+			continue
+		}
+
+		if row.PlanID == "" || row.FeatureID == "" {
+			continue
+		}
+
+		amounts[row.PriceID] = decimal.NewFromFloat(row.PerUnitPrice)
+	}
+	return amounts
+}
+
+// calculateSubscriptionCostDiff reprices the usage charges of a subscription with the new
+// price amounts and returns the resulting cost change
+func calculateSubscriptionCostDiff(
+	ctx context.Context,
+	priceService service.PriceService,
+	sub *subscription.Subscription,
+	usage *dto.GetUsageBySubscriptionResponse,
+	newAmounts map[string]decimal.Decimal,
+) SubscriptionCostDiff {
+	diff := SubscriptionCostDiff{
+		SubscriptionID: sub.ID,
+		CustomerID:     sub.CustomerID,
+		Currency:       sub.Currency,
+	}
+
+	for _, charge := range usage.Charges {
+		currentCost := decimal.NewFromFloat(charge.Amount)
+		diff.CurrentCost = diff.CurrentCost.Add(currentCost)
+
+		if charge.Price == nil {
+			diff.NewCost = diff.NewCost.Add(currentCost)
+			continue
+		}
+
+		newAmount, ok := newAmounts[charge.Price.ID]
+		if !ok || newAmount.Equal(charge.Price.Amount) {
+			diff.NewCost = diff.NewCost.Add(currentCost)
+			continue
+		}
+
+		// Reprice a copy so the loaded price is left untouched
+		newPrice := *charge.Price
+		newPrice.Amount = newAmount
+		newCost := priceService.CalculateCost(ctx, &newPrice, decimal.NewFromFloat(charge.Quantity))
+		if charge.IsOverage && charge.OverageFactor > 0 {
+			newCost = newCost.Mul(decimal.NewFromFloat(charge.OverageFactor))
+		}
+		diff.NewCost = diff.NewCost.Add(newCost.Round(types.GetCurrencyPrecision(sub.Currency)))
+	}
+
+	diff.Delta = diff.NewCost.Sub(diff.CurrentCost)
+	diff.Flagged = isCostChangeSuspicious(diff.CurrentCost, diff.NewCost)
+	return diff
+}
+
+// isCostChangeSuspicious returns true when the new cost differs from the current cost by
+// pricingDryRunAlertRatio or more in either direction
+func isCostChangeSuspicious(currentCost, newCost decimal.Decimal) bool {
+	ratio := decimal.NewFromInt(pricingDryRunAlertRatio)
+	if currentCost.IsZero() || newCost.IsZero() {
+		return false
+	}
+	return newCost.GreaterThanOrEqual(currentCost.Mul(ratio)) || currentCost.GreaterThanOrEqual(newCost.Mul(ratio))
+}
+
+// summarizeCostDiffs aggregates per subscription diffs into a dry run summary
+func summarizeCostDiffs(diffs []SubscriptionCostDiff) PricingDryRunSummary {
+	summary := PricingDryRunSummary{
+		SubscriptionsSampled: len(diffs),
+		Diffs:                diffs,
+	}
+
+	for _, diff := range diffs {
+		summary.TotalCurrentCost = summary.TotalCurrentCost.Add(diff.CurrentCost)
+		summary.TotalNewCost = summary.TotalNewCost.Add(diff.NewCost)
+		if !diff.Delta.IsZero() {
+			summary.SubscriptionsChanged++
+		}
+	}
+
+	summary.TotalDelta = summary.TotalNewCost.Sub(summary.TotalCurrentCost)
+	return summary
+}
+
+// dryRunCostDiff computes, for a sample of active subscriptions, how the cost of their most
+// recent (current) billing period would change if the pricing rows were imported
+func (s *pricingImportScript) dryRunCostDiff(ctx context.Context, rows []PricingRow, sampleSize int) (*PricingDryRunSummary, error) {
+	serviceParams, err := s.newDryRunServiceParams()
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptionService := service.NewSubscriptionService(serviceParams)
+	priceService := service.NewPriceService(serviceParams)
+	newAmounts := newPriceAmounts(rows)
+
+	filter := types.NewSubscriptionFilter()
+	filter.Limit = lo.ToPtr(sampleSize)
+	filter.SubscriptionStatus = []types.SubscriptionStatus{types.SubscriptionStatusActive}
+
+	subs, err := serviceParams.SubRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active subscriptions: %w", err)
+	}
+
+	diffs := make([]SubscriptionCostDiff, 0, len(subs))
+	var errs []string
+	for _, sub := range subs {
+		usage, err := subscriptionService.GetUsageBySubscription(ctx, &dto.GetUsageBySubscriptionRequest{
+			SubscriptionID: sub.ID,
+			StartTime:      sub.CurrentPeriodStart,
+			EndTime:        sub.CurrentPeriodEnd,
+		})
+		if err != nil {
+			s.log.Errorw("Failed to get usage for subscription", "subscription_id", sub.ID, "error", err)
+			errs = append(errs, fmt.Sprintf("Error getting usage for subscription %s: %v", sub.ID, err))
+			continue
+		}
+
+		diffs = append(diffs, calculateSubscriptionCostDiff(ctx, priceService, sub, usage, newAmounts))
+	}
+
+	summary := summarizeCostDiffs(diffs)
+	summary.Errors = errs
+	return &summary, nil
+}
+
+// newDryRunServiceParams wires the repositories needed to compute subscription usage.
+// They are only created for dry runs since a regular import only touches postgres.
+func (s *pricingImportScript) newDryRunServiceParams() (service.ServiceParams, error) {
+	sentryService := sentry.NewSentryService(s.cfg, s.log)
+	chStore, err := clickhouse.NewClickHouseStore(s.cfg, sentryService)
+	if err != nil {
+		return service.ServiceParams{}, fmt.Errorf("failed to connect to clickhouse: %w", err)
+	}
+
+	cacheClient := cache.NewInMemoryCache()
+	return service.ServiceParams{
+		Logger:                   s.log,
+		Config:                   s.cfg,
+		DB:                       s.pgClient,
+		CustomerRepo:             entRepo.NewCustomerRepository(s.pgClient, s.log, cacheClient, s.cfg.Customer.LookupKeyConflictPolicy),
+		SubRepo:                  entRepo.NewSubscriptionRepository(s.pgClient, s.log, cacheClient),
+		SubscriptionLineItemRepo: entRepo.NewSubscriptionLineItemRepository(s.pgClient, s.log, cacheClient),
+		PlanRepo:                 s.planRepo,
+		PriceRepo:                s.priceRepo,
+		MeterRepo:                s.meterRepo,
+		FeatureRepo:              s.featureRepo,
+		EntitlementRepo:          entRepo.NewEntitlementRepository(s.pgClient, s.log, cacheClient),
+		AddonRepo:                entRepo.NewAddonRepository(s.pgClient, s.log, cacheClient),
+		AddonAssociationRepo:     entRepo.NewAddonAssociationRepository(s.pgClient, s.log, cacheClient),
+		SettingsRepo:             entRepo.NewSettingsRepository(s.pgClient, s.log, cacheClient),
+		EventRepo:                chRepo.NewEventRepository(chStore, s.log),
+		ProcessedEventRepo:       chRepo.NewProcessedEventRepository(chStore, s.log),
+		FeatureUsageRepo:         chRepo.NewFeatureUsageRepository(chStore, s.log),
+	}, nil
+}
+
+// printDryRunSummary logs the cost diff of a dry run, calling out suspicious changes
+func (s *pricingImportScript) printDryRunSummary(summary *PricingDryRunSummary) {
+	for _, diff := range summary.Diffs {
+		if diff.Delta.IsZero() {
+			continue
+		}
+
+		fields := []interface{}{
+			"subscription_id", diff.SubscriptionID,
+			"customer_id", diff.CustomerID,
+			"currency", diff.Currency,
+			"current_cost", diff.CurrentCost.String(),
+			"new_cost", diff.NewCost.String(),
+			"delta", diff.Delta.String(),
+		}
+		if diff.Flagged {
+			s.log.Warnw("Subscription cost changes by 10x or more, check the imported prices", fields...)
+		} else {
+			s.log.Infow("Subscription cost change", fields...)
+		}
+	}
+
+	s.log.Infow("Pricing import dry run summary",
+		"subscriptions_sampled", summary.SubscriptionsSampled,
+		"subscriptions_changed", summary.SubscriptionsChanged,
+		"total_current_cost", summary.TotalCurrentCost.String(),
+		"total_new_cost", summary.TotalNewCost.String(),
+		"total_delta", summary.TotalDelta.String(),
+		"flagged", len(lo.Filter(summary.Diffs, func(d SubscriptionCostDiff, _ int) bool { return d.Flagged })),
+		"errors", len(summary.Errors),
+	)
+
+	if len(summary.Errors) > 0 {
+		s.log.Infow("Errors encountered during dry run", "errors", summary.Errors)
+	}
+}
+
+// pricingDryRunSampleSize reads SAMPLE_SIZE, falling back to the default for empty or invalid values
+func pricingDryRunSampleSize() int {
+	sampleSize, err := strconv.Atoi(os.Getenv("SAMPLE_SIZE"))
+	if err != nil || sampleSize <= 0 {
+		return defaultPricingDryRunSampleSize
+	}
+	return sampleSize
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/domain/price"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/service"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPricingImportDryRunCostDiff(t *testing.T) {
+	ctx := context.Background()
+	priceService := service.NewPriceService(service.ServiceParams{Logger: logger.GetLogger()})
+
+	apiCalls := &price.Price{
+		ID:           "price_api_calls",
+		Amount:       decimal.NewFromFloat(0.01),
+		Currency:     "usd",
+		Type:         types.PRICE_TYPE_USAGE,
+		BillingModel: types.BILLING_MODEL_FLAT_FEE,
+	}
+	storage := &price.Price{
+		ID:           "price_storage",
+		Amount:       decimal.NewFromInt(2),
+		Currency:     "usd",
+		Type:         types.PRICE_TYPE_USAGE,
+		BillingModel: types.BILLING_MODEL_FLAT_FEE,
+	}
+
+	rows := []PricingRow{
+		// 0.01 -> 0.015 per call
+		{FeatureID: "feat_api_calls", PlanID: "plan_1", PriceID: apiCalls.ID, PerUnitPrice: 0.015},
+		// unchanged
+		{FeatureID: "feat_storage", PlanID: "plan_1", PriceID: storage.ID, PerUnitPrice: 2},
+		// new prices don't affect existing subscriptions
+		{FeatureID: "feat_new", PlanID: "plan_1", PerUnitPrice: 5},
+	}
+	newAmounts := newPriceAmounts(rows)
+	assert.Len(t, newAmounts, 2)
+
+	usage := &dto.GetUsageBySubscriptionResponse{
+		Charges: []*dto.SubscriptionUsageByMetersResponse{
+			{Price: apiCalls, Quantity: 1000, Amount: 10},
+			{Price: storage, Quantity: 5, Amount: 10},
+		},
+	}
+	sub := &subscription.Subscription{ID: "sub_1", CustomerID: "cust_1", Currency: "usd"}
+
+	diff := calculateSubscriptionCostDiff(ctx, priceService, sub, usage, newAmounts)
+	assert.True(t, decimal.NewFromInt(20).Equal(diff.CurrentCost), "current cost: %s", diff.CurrentCost)
+	assert.True(t, decimal.NewFromInt(25).Equal(diff.NewCost), "new cost: %s", diff.NewCost)
+	assert.True(t, decimal.NewFromInt(5).Equal(diff.Delta), "delta: %s", diff.Delta)
+	assert.False(t, diff.Flagged)
+
+	t.Run("flags 10x price errors", func(t *testing.T) {
+		// 0.01 typed as 0.1
+		mistyped := newPriceAmounts([]PricingRow{
+			{FeatureID: "feat_api_calls", PlanID: "plan_1", PriceID: apiCalls.ID, PerUnitPrice: 0.1},
+		})
+		usage := &dto.GetUsageBySubscriptionResponse{
+			Charges: []*dto.SubscriptionUsageByMetersResponse{
+				{Price: apiCalls, Quantity: 1000, Amount: 10},
+			},
+		}
+
+		diff := calculateSubscriptionCostDiff(ctx, priceService, sub, usage, mistyped)
+		assert.True(t, decimal.NewFromInt(90).Equal(diff.Delta), "delta: %s", diff.Delta)
+		assert.True(t, diff.Flagged)
+	})
+
+	t.Run("deleted prices stop charging", func(t *testing.T) {
+		deleted := newPriceAmounts([]PricingRow{
+			{PriceID: storage.ID, Delete: "Y"},
+		})
+
+		diff := calculateSubscriptionCostDiff(ctx, priceService, sub, usage, deleted)
+		assert.True(t, decimal.NewFromInt(-10).Equal(diff.Delta), "delta: %s", diff.Delta)
+	})
+
+	t.Run("summary totals the sampled subscriptions", func(t *testing.T) {
+		unchanged := SubscriptionCostDiff{
+			SubscriptionID: "sub_2",
+			CurrentCost:    decimal.NewFromInt(7),
+			NewCost:        decimal.NewFromInt(7),
+		}
+
+		summary := summarizeCostDiffs([]SubscriptionCostDiff{diff, unchanged})
+		assert.Equal(t, 2, summary.SubscriptionsSampled)
+		assert.Equal(t, 1, summary.SubscriptionsChanged)
+		assert.True(t, decimal.NewFromInt(27).Equal(summary.TotalCurrentCost))
+		assert.True(t, decimal.NewFromInt(32).Equal(summary.TotalNewCost))
+		assert.True(t, decimal.NewFromInt(5).Equal(summary.TotalDelta))
+	})
+}
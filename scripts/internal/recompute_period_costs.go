@@ -0,0 +1,314 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/cache"
+	"github.com/flexprice/flexprice/internal/clickhouse"
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/domain/invoice"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/postgres"
+	chRepo "github.com/flexprice/flexprice/internal/repository/clickhouse"
+	entRepo "github.com/flexprice/flexprice/internal/repository/ent"
+	"github.com/flexprice/flexprice/internal/sentry"
+	"github.com/flexprice/flexprice/internal/service"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+)
+
+// CostCorrection is the difference between the amount billed for the usage of a price on an
+// invoice and the cost of that usage under the current prices
+type CostCorrection struct {
+	InvoiceID string
+	PriceID   string
+	Currency  string
+	// LineItems are the usage line items of the invoice billed under the price
+	LineItems  []*invoice.InvoiceLineItem
+	Invoiced   decimal.Decimal
+	Recomputed decimal.Decimal
+	// Delta is Recomputed - Invoiced, negative when the customer was overcharged
+	Delta decimal.Decimal
+}
+
+// computeCostCorrections compares the usage line items of an invoice with the usage charges the
+// billing service computes for the invoice period under the current prices. Charges of prices the
+// invoice didn't bill are left out, only prices whose cost changed are returned, ordered by price.
+func computeCostCorrections(inv *invoice.Invoice, usageCharges []dto.CreateInvoiceLineItemRequest) []CostCorrection {
+	recomputed := make(map[string]decimal.Decimal)
+	for _, charge := range usageCharges {
+		if charge.PriceID == nil {
+			continue
+		}
+		recomputed[*charge.PriceID] = recomputed[*charge.PriceID].Add(charge.Amount)
+	}
+
+	lineItems := make(map[string][]*invoice.InvoiceLineItem)
+	for _, li := range inv.LineItems {
+		if li.PriceID == nil || lo.FromPtr(li.PriceType) != string(types.PRICE_TYPE_USAGE) {
+			continue
+		}
+		lineItems[*li.PriceID] = append(lineItems[*li.PriceID], li)
+	}
+
+	corrections := make([]CostCorrection, 0)
+	for priceID, items := range lineItems {
+		invoiced := decimal.Zero
+		for _, li := range items {
+			invoiced = invoiced.Add(li.Amount)
+		}
+		cost := recomputed[priceID].Round(types.GetCurrencyPrecision(inv.Currency))
+		if cost.Equal(invoiced) {
+			continue
+		}
+
+		corrections = append(corrections, CostCorrection{
+			InvoiceID:  inv.ID,
+			PriceID:    priceID,
+			Currency:   inv.Currency,
+			LineItems:  items,
+			Invoiced:   invoiced,
+			Recomputed: cost,
+			Delta:      cost.Sub(invoiced),
+		})
+	}
+
+	sort.SliceStable(corrections, func(i, j int) bool {
+		return corrections[i].PriceID < corrections[j].PriceID
+	})
+	return corrections
+}
+
+// adjustmentCreditNote returns the credit note refunding the overcharges of the invoice's
+// corrections, nil when the customer wasn't overcharged. The overcharge of a price is credited
+// on its line items in order, never more than a line item's amount. Undercharges can't be
+// corrected by a credit note and are only reported.
+func adjustmentCreditNote(inv *invoice.Invoice, corrections []CostCorrection) *dto.CreateCreditNoteRequest {
+	lineItems := make([]dto.CreateCreditNoteLineItemRequest, 0)
+	for _, correction := range corrections {
+		overcharge := correction.Delta.Neg()
+		for _, li := range correction.LineItems {
+			if !overcharge.IsPositive() {
+				break
+			}
+			amount := decimal.Min(overcharge, li.Amount)
+			if !amount.IsPositive() {
+				continue
+			}
+			lineItems = append(lineItems, dto.CreateCreditNoteLineItemRequest{
+				InvoiceLineItemID: li.ID,
+				DisplayName:       fmt.Sprintf("Price correction: %s", lo.FromPtr(li.DisplayName)),
+				Amount:            amount,
+				Metadata: types.Metadata{
+					"price_id":   correction.PriceID,
+					"invoiced":   correction.Invoiced.String(),
+					"recomputed": correction.Recomputed.String(),
+				},
+			})
+			overcharge = overcharge.Sub(amount)
+		}
+	}
+	if len(lineItems) == 0 {
+		return nil
+	}
+
+	return &dto.CreateCreditNoteRequest{
+		InvoiceID:         inv.ID,
+		Reason:            types.CreditNoteReasonBillingError,
+		Memo:              "Adjustment for a retroactive price correction",
+		LineItems:         lineItems,
+		IdempotencyKey:    lo.ToPtr(fmt.Sprintf("price_correction_%s", inv.ID)),
+		ProcessCreditNote: true,
+	}
+}
+
+// recomputeUsageCharges returns the usage charges of the invoice period under the current prices,
+// calculated by the billing service the way the invoice's usage was charged
+func recomputeUsageCharges(ctx context.Context, billingService service.BillingService, subRepo subscription.Repository, inv *invoice.Invoice) ([]dto.CreateInvoiceLineItemRequest, error) {
+	if inv.SubscriptionID == nil {
+		return nil, nil
+	}
+
+	sub, lineItems, err := subRepo.GetWithLineItems(ctx, *inv.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	usageLineItems := lo.Filter(lineItems, func(li *subscription.SubscriptionLineItem, _ int) bool {
+		return li.IsUsage()
+	})
+	if len(usageLineItems) == 0 {
+		return nil, nil
+	}
+
+	result, err := billingService.CalculateCharges(ctx, sub, usageLineItems, *inv.PeriodStart, *inv.PeriodEnd, true)
+	if err != nil {
+		return nil, err
+	}
+	return result.UsageCharges, nil
+}
+
+// RecomputePeriodCosts recomputes the cost of the usage billed on a customer's finalized invoices
+// under the current prices, e.g. after a price was corrected retroactively, and reports the
+// difference with the invoiced amounts. The usage charges are recalculated by the billing service
+// for each invoice period, so commitments, entitlements and bucketed meters apply as on the invoice.
+//
+// Environment variables:
+//   - TENANT_ID: tenant of the customer
+//   - ENVIRONMENT_ID: environment of the customer
+//   - CUSTOMER_ID: customer whose invoices are recomputed
+//   - START_TIME, END_TIME: the closed period in RFC3339, invoices whose period lies within it
+//     are recomputed
+//   - ISSUE_CREDIT_NOTES: when true, issues a credit note refunding the overcharge of each invoice
+func RecomputePeriodCosts() error {
+	tenantID := os.Getenv("TENANT_ID")
+	environmentID := os.Getenv("ENVIRONMENT_ID")
+	customerID := os.Getenv("CUSTOMER_ID")
+	if tenantID == "" || environmentID == "" || customerID == "" {
+		return fmt.Errorf("TENANT_ID, ENVIRONMENT_ID and CUSTOMER_ID are required")
+	}
+
+	startTime, err := time.Parse(time.RFC3339, os.Getenv("START_TIME"))
+	if err != nil {
+		return fmt.Errorf("invalid START_TIME, use RFC3339 (2006-01-02T15:04:05Z): %w", err)
+	}
+	endTime, err := time.Parse(time.RFC3339, os.Getenv("END_TIME"))
+	if err != nil {
+		return fmt.Errorf("invalid END_TIME, use RFC3339 (2006-01-02T15:04:05Z): %w", err)
+	}
+	if !endTime.After(startTime) {
+		return fmt.Errorf("END_TIME must be after START_TIME")
+	}
+	issueCreditNotes := os.Getenv("ISSUE_CREDIT_NOTES") == "true"
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log, err := logger.NewLogger(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	entClient, err := postgres.NewEntClients(cfg, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	sentryService := sentry.NewSentryService(cfg, log)
+	client := postgres.NewClient(entClient, log, sentryService)
+	cacheClient := cache.NewInMemoryCache()
+
+	chStore, err := clickhouse.NewClickHouseStore(cfg, sentryService)
+	if err != nil {
+		return fmt.Errorf("failed to connect to clickhouse: %w", err)
+	}
+
+	eventRepo := chRepo.NewEventRepository(chStore, log)
+	featureUsageRepo := chRepo.NewFeatureUsageRepository(chStore, log)
+	serviceParams := service.ServiceParams{
+		Logger:                       log,
+		Config:                       cfg,
+		DB:                           client,
+		EventRepo:                    eventRepo,
+		ProcessedEventRepo:           chRepo.NewProcessedEventRepository(chStore, log),
+		FeatureUsageRepo:             featureUsageRepo,
+		CustomerRepo:                 entRepo.NewCustomerRepository(client, log, cacheClient, cfg.Customer.LookupKeyConflictPolicy),
+		SubRepo:                      entRepo.NewSubscriptionRepository(client, log, cacheClient),
+		SubscriptionLineItemRepo:     entRepo.NewSubscriptionLineItemRepository(client, log, cacheClient),
+		SubscriptionPhaseRepo:        entRepo.NewSubscriptionPhaseRepository(client, log, cacheClient),
+		PlanRepo:                     entRepo.NewPlanRepository(client, log, cacheClient),
+		PriceRepo:                    entRepo.NewPriceRepository(client, log, cacheClient),
+		MeterRepo:                    entRepo.NewMeterRepository(client, log, cacheClient),
+		FeatureRepo:                  entRepo.NewFeatureRepository(client, log, cacheClient),
+		EntitlementRepo:              entRepo.NewEntitlementRepository(client, log, cacheClient),
+		AddonRepo:                    entRepo.NewAddonRepository(client, log, cacheClient),
+		AddonAssociationRepo:         entRepo.NewAddonAssociationRepository(client, log, cacheClient),
+		WalletRepo:                   entRepo.NewWalletRepository(client, log, cacheClient),
+		InvoiceRepo:                  entRepo.NewInvoiceRepository(client, log, cacheClient),
+		PaymentRepo:                  entRepo.NewPaymentRepository(client, log, cacheClient),
+		CreditNoteRepo:               entRepo.NewCreditNoteRepository(client, log, cacheClient),
+		CreditNoteLineItemRepo:       entRepo.NewCreditNoteLineItemRepository(client, log, cacheClient),
+		TaxRateRepo:                  entRepo.NewTaxRateRepository(client, log, cacheClient),
+		TaxAssociationRepo:           entRepo.NewTaxAssociationRepository(client, log, cacheClient),
+		TaxAppliedRepo:               entRepo.NewTaxAppliedRepository(client, log, cacheClient),
+		CouponApplicationRepo:        entRepo.NewCouponApplicationRepository(client, log, cacheClient),
+		ConnectionRepo:               entRepo.NewConnectionRepository(client, log, cacheClient),
+		EntityIntegrationMappingRepo: entRepo.NewEntityIntegrationMappingRepository(client, log, cacheClient),
+		SettingsRepo:                 entRepo.NewSettingsRepository(client, log, cacheClient),
+	}
+	billingService := service.NewBillingService(serviceParams)
+	creditNoteService := service.NewCreditNoteService(serviceParams)
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, types.CtxTenantID, tenantID)
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, environmentID)
+
+	invoices, err := serviceParams.InvoiceRepo.List(ctx, &types.InvoiceFilter{
+		QueryFilter:   types.NewNoLimitQueryFilter(),
+		CustomerID:    customerID,
+		InvoiceType:   types.InvoiceTypeSubscription,
+		InvoiceStatus: []types.InvoiceStatus{types.InvoiceStatusFinalized},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	recomputed := 0
+	totalDelta := decimal.Zero
+	for _, inv := range invoices {
+		if inv.PeriodStart == nil || inv.PeriodEnd == nil ||
+			inv.PeriodStart.Before(startTime) || inv.PeriodEnd.After(endTime) {
+			continue
+		}
+		recomputed++
+
+		usageCharges, err := recomputeUsageCharges(ctx, billingService, serviceParams.SubRepo, inv)
+		if err != nil {
+			return fmt.Errorf("failed to recompute usage charges of invoice %s: %w", inv.ID, err)
+		}
+
+		corrections := computeCostCorrections(inv, usageCharges)
+		for _, correction := range corrections {
+			totalDelta = totalDelta.Add(correction.Delta)
+			log.Infow("invoiced usage cost differs from the current prices",
+				"invoice_id", correction.InvoiceID,
+				"price_id", correction.PriceID,
+				"currency", correction.Currency,
+				"invoiced", correction.Invoiced,
+				"recomputed", correction.Recomputed,
+				"delta", correction.Delta,
+			)
+		}
+
+		req := adjustmentCreditNote(inv, corrections)
+		if req == nil || !issueCreditNotes {
+			continue
+		}
+		creditNote, err := creditNoteService.CreateCreditNote(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to issue credit note for invoice %s: %w", inv.ID, err)
+		}
+		log.Infow("issued adjustment credit note",
+			"invoice_id", inv.ID,
+			"credit_note_id", creditNote.ID,
+			"amount", creditNote.TotalAmount,
+		)
+	}
+
+	log.Infow("recomputed usage costs",
+		"customer_id", customerID,
+		"start_time", startTime,
+		"end_time", endTime,
+		"invoices", recomputed,
+		"delta", totalDelta,
+		"issue_credit_notes", issueCreditNotes,
+	)
+	return nil
+}
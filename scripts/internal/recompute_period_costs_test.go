@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/flexprice/flexprice/internal/api/dto"
+	"github.com/flexprice/flexprice/internal/domain/invoice"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecomputePeriodCostsPriceCorrection(t *testing.T) {
+	usageLineItem := func(id, priceID string, quantity, amount int64) *invoice.InvoiceLineItem {
+		return &invoice.InvoiceLineItem{
+			ID:          id,
+			InvoiceID:   "inv_correction",
+			PriceID:     lo.ToPtr(priceID),
+			PriceType:   lo.ToPtr(string(types.PRICE_TYPE_USAGE)),
+			DisplayName: lo.ToPtr("API Calls"),
+			Quantity:    decimal.NewFromInt(quantity),
+			Amount:      decimal.NewFromInt(amount),
+			Currency:    "usd",
+		}
+	}
+
+	// API calls were billed at 0.10 but the price was corrected to 0.08 afterwards, storage was
+	// billed at 1.00 and corrected to 1.50, the fixed fee is not usage
+	inv := &invoice.Invoice{
+		ID:       "inv_correction",
+		Currency: "usd",
+		LineItems: []*invoice.InvoiceLineItem{
+			usageLineItem("li_api_calls", "price_api_calls", 1000, 100),
+			usageLineItem("li_storage", "price_storage", 10, 10),
+			usageLineItem("li_unchanged", "price_unchanged", 5, 5),
+			{
+				ID:        "li_fixed",
+				InvoiceID: "inv_correction",
+				PriceID:   lo.ToPtr("price_fixed"),
+				PriceType: lo.ToPtr(string(types.PRICE_TYPE_FIXED)),
+				Amount:    decimal.NewFromInt(50),
+				Currency:  "usd",
+			},
+		},
+	}
+	charge := func(priceID string, amount int64) dto.CreateInvoiceLineItemRequest {
+		return dto.CreateInvoiceLineItemRequest{
+			PriceID:   lo.ToPtr(priceID),
+			PriceType: lo.ToPtr(string(types.PRICE_TYPE_USAGE)),
+			Amount:    decimal.NewFromInt(amount),
+		}
+	}
+	usageCharges := []dto.CreateInvoiceLineItemRequest{
+		charge("price_api_calls", 48),
+		charge("price_api_calls", 32),
+		charge("price_storage", 15),
+		charge("price_unchanged", 5),
+		charge("price_not_invoiced", 3),
+	}
+
+	corrections := computeCostCorrections(inv, usageCharges)
+	require.Len(t, corrections, 2)
+
+	assert.Equal(t, "price_api_calls", corrections[0].PriceID)
+	assert.True(t, decimal.NewFromInt(100).Equal(corrections[0].Invoiced))
+	assert.True(t, decimal.NewFromInt(80).Equal(corrections[0].Recomputed))
+	assert.True(t, decimal.NewFromInt(-20).Equal(corrections[0].Delta), "delta: %s", corrections[0].Delta)
+
+	assert.Equal(t, "price_storage", corrections[1].PriceID)
+	assert.True(t, decimal.NewFromInt(5).Equal(corrections[1].Delta), "delta: %s", corrections[1].Delta)
+
+	// Only the overcharge is credited, the undercharge of storage is reported only
+	req := adjustmentCreditNote(inv, corrections)
+	require.NotNil(t, req)
+	assert.Equal(t, inv.ID, req.InvoiceID)
+	assert.Equal(t, types.CreditNoteReasonBillingError, req.Reason)
+	require.Len(t, req.LineItems, 1)
+	assert.Equal(t, "li_api_calls", req.LineItems[0].InvoiceLineItemID)
+	assert.True(t, decimal.NewFromInt(20).Equal(req.LineItems[0].Amount), "amount: %s", req.LineItems[0].Amount)
+	assert.NoError(t, req.Validate())
+
+	t.Run("undercharges only", func(t *testing.T) {
+		assert.Nil(t, adjustmentCreditNote(inv, corrections[1:]))
+	})
+
+	t.Run("overcharge spread over line items", func(t *testing.T) {
+		split := &invoice.Invoice{
+			ID:       "inv_split",
+			Currency: "usd",
+			LineItems: []*invoice.InvoiceLineItem{
+				usageLineItem("li_first", "price_api_calls", 100, 10),
+				usageLineItem("li_second", "price_api_calls", 900, 90),
+			},
+		}
+		corrections := computeCostCorrections(split, []dto.CreateInvoiceLineItemRequest{charge("price_api_calls", 75)})
+		require.Len(t, corrections, 1)
+		assert.True(t, decimal.NewFromInt(-25).Equal(corrections[0].Delta))
+
+		req := adjustmentCreditNote(split, corrections)
+		require.NotNil(t, req)
+		require.Len(t, req.LineItems, 2)
+		assert.True(t, decimal.NewFromInt(10).Equal(req.LineItems[0].Amount))
+		assert.True(t, decimal.NewFromInt(15).Equal(req.LineItems[1].Amount))
+	})
+}
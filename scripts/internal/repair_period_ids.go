@@ -0,0 +1,270 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/cache"
+	"github.com/flexprice/flexprice/internal/clickhouse"
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/postgres"
+	chRepo "github.com/flexprice/flexprice/internal/repository/clickhouse"
+	entRepo "github.com/flexprice/flexprice/internal/repository/ent"
+	"github.com/flexprice/flexprice/internal/sentry"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// PeriodIDMismatch is a feature usage row whose period id doesn't match the period of the
+// subscription its timestamp falls in
+type PeriodIDMismatch struct {
+	EventID       string
+	SubLineItemID string
+	Timestamp     time.Time
+	PeriodID      uint64
+	Expected      uint64
+}
+
+// PeriodIDRepairResult summarizes a validation of the period ids of a subscription's usage
+type PeriodIDRepairResult struct {
+	Checked    int
+	Mismatched []PeriodIDMismatch
+	Repaired   int
+	// Unresolved are the event IDs of the rows whose period couldn't be calculated
+	Unresolved []string
+}
+
+// expectedPeriodID returns the period id the row is recorded under when processed against the
+// subscription as it is now. Usage accrued during a pause keeps the period the pause interrupted.
+func expectedPeriodID(sub *subscription.Subscription, pauses []*subscription.SubscriptionPause, row *events.FeatureUsage) (uint64, error) {
+	for _, pause := range pauses {
+		if row.Timestamp.Before(pause.PauseStart) || (pause.PauseEnd != nil && !row.Timestamp.Before(*pause.PauseEnd)) {
+			continue
+		}
+		if accrued := uint64(pause.OriginalPeriodStart.Unix() * 1000); row.PeriodID == accrued {
+			return accrued, nil
+		}
+	}
+
+	return types.CalculatePeriodID(
+		row.Timestamp,
+		sub.StartDate,
+		sub.CurrentPeriodStart,
+		sub.CurrentPeriodEnd,
+		sub.BillingAnchor,
+		sub.BillingPeriodCount,
+		sub.BillingPeriod,
+	)
+}
+
+// replaceFeatureUsage replaces the cancelled feature usage rows with their replacements. When the
+// usage counters are kept, nil otherwise, the cancelled rows are moved to the counters of their
+// replacements first. A replacement is counted under the key of the row it replaces, so a rerun
+// after a failed replace moves it again instead of counting it twice.
+func replaceFeatureUsage(ctx context.Context, featureUsageRepo events.FeatureUsageRepository, usageCounterRepo events.UsageCounterRepository, cancelled, replacements []*events.FeatureUsage) error {
+	if usageCounterRepo != nil {
+		if err := usageCounterRepo.Cancel(ctx, cancelled); err != nil {
+			return err
+		}
+		if err := usageCounterRepo.Add(ctx, replacements); err != nil {
+			return err
+		}
+	}
+
+	return featureUsageRepo.ReplaceProcessedEvents(ctx, cancelled, replacements)
+}
+
+// repairPeriodIDs recomputes the period id of the subscription's feature usage rows in the window
+// and, unless dryRun, persists the ones that changed. Feature usage is keyed by period, so each
+// mismatched row is cancelled and inserted again under its period, and moved between the usage
+// counters when they are kept. The repaired rows keep their
+// place in the order the rows are paged in, so the pages stay consistent.
+func repairPeriodIDs(ctx context.Context, featureUsageRepo events.FeatureUsageRepository, usageCounterRepo events.UsageCounterRepository, sub *subscription.Subscription, pauses []*subscription.SubscriptionPause, startTime, endTime time.Time, batchSize int, dryRun bool) (*PeriodIDRepairResult, error) {
+	result := &PeriodIDRepairResult{
+		Mismatched: make([]PeriodIDMismatch, 0),
+		Unresolved: make([]string, 0),
+	}
+
+	for {
+		rows, _, err := featureUsageRepo.GetProcessedEvents(ctx, &events.GetProcessedEventsParams{
+			StartTime:      startTime,
+			EndTime:        endTime,
+			SubscriptionID: sub.ID,
+			Limit:          batchSize,
+			Offset:         result.Checked,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		result.Checked += len(rows)
+
+		cancelled := make([]*events.FeatureUsage, 0)
+		updated := make([]*events.FeatureUsage, 0)
+		for _, row := range rows {
+			periodID, err := expectedPeriodID(sub, pauses, row)
+			if err != nil {
+				result.Unresolved = append(result.Unresolved, row.ID)
+				continue
+			}
+			if periodID == row.PeriodID {
+				continue
+			}
+
+			result.Mismatched = append(result.Mismatched, PeriodIDMismatch{
+				EventID:       row.ID,
+				SubLineItemID: row.SubLineItemID,
+				Timestamp:     row.Timestamp,
+				PeriodID:      row.PeriodID,
+				Expected:      periodID,
+			})
+
+			usage := *row
+			usage.PeriodID = periodID
+			cancelled = append(cancelled, row)
+			updated = append(updated, &usage)
+		}
+
+		if !dryRun && len(updated) > 0 {
+			if err := replaceFeatureUsage(ctx, featureUsageRepo, usageCounterRepo, cancelled, updated); err != nil {
+				return nil, err
+			}
+			result.Repaired += len(updated)
+		}
+
+		if len(rows) < batchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// RepairPeriodIDs validates the period id of a subscription's feature usage against its current
+// billing periods, e.g. after its current period start or end was edited, and repairs the rows
+// recorded under a stale period.
+//
+// Environment variables:
+//   - TENANT_ID: tenant of the subscription
+//   - ENVIRONMENT_ID: environment of the subscription
+//   - SUBSCRIPTION_ID: subscription to validate
+//   - START_TIME, END_TIME: optional window of the usage in RFC3339, defaults to the subscription
+//     start until now
+//   - BATCH_SIZE: rows read per batch, defaults to 500
+//   - DRY_RUN: when true, only reports the mismatched rows
+func RepairPeriodIDs() error {
+	tenantID := os.Getenv("TENANT_ID")
+	environmentID := os.Getenv("ENVIRONMENT_ID")
+	subscriptionID := os.Getenv("SUBSCRIPTION_ID")
+	if tenantID == "" || environmentID == "" || subscriptionID == "" {
+		return fmt.Errorf("TENANT_ID, ENVIRONMENT_ID and SUBSCRIPTION_ID are required")
+	}
+
+	var startTime, endTime time.Time
+	var err error
+	if startTimeStr := os.Getenv("START_TIME"); startTimeStr != "" {
+		startTime, err = time.Parse(time.RFC3339, startTimeStr)
+		if err != nil {
+			return fmt.Errorf("invalid START_TIME, use RFC3339 (2006-01-02T15:04:05Z): %w", err)
+		}
+	}
+	if endTimeStr := os.Getenv("END_TIME"); endTimeStr != "" {
+		endTime, err = time.Parse(time.RFC3339, endTimeStr)
+		if err != nil {
+			return fmt.Errorf("invalid END_TIME, use RFC3339 (2006-01-02T15:04:05Z): %w", err)
+		}
+	}
+
+	batchSize := 500
+	if batchSizeStr := os.Getenv("BATCH_SIZE"); batchSizeStr != "" {
+		batchSize, err = strconv.Atoi(batchSizeStr)
+		if err != nil || batchSize <= 0 {
+			return fmt.Errorf("invalid BATCH_SIZE, must be a positive integer")
+		}
+	}
+	dryRun := os.Getenv("DRY_RUN") == "true"
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log, err := logger.NewLogger(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	entClient, err := postgres.NewEntClients(cfg, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	sentryService := sentry.NewSentryService(cfg, log)
+	client := postgres.NewClient(entClient, log, sentryService)
+	cacheClient := cache.NewInMemoryCache()
+
+	chStore, err := clickhouse.NewClickHouseStore(cfg, sentryService)
+	if err != nil {
+		return fmt.Errorf("failed to connect to clickhouse: %w", err)
+	}
+
+	featureUsageRepo := chRepo.NewFeatureUsageRepository(chStore, log)
+	subRepo := entRepo.NewSubscriptionRepository(client, log, cacheClient)
+	var usageCounterRepo events.UsageCounterRepository
+	if cfg.FeatureUsageTracking.UsageCounterSinkEnabled {
+		usageCounterRepo = entRepo.NewUsageCounterRepository(client, log)
+	}
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, types.CtxTenantID, tenantID)
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, environmentID)
+
+	sub, pauses, err := subRepo.GetWithPauses(ctx, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	if startTime.IsZero() {
+		startTime = sub.StartDate
+	}
+	if endTime.IsZero() {
+		endTime = time.Now().UTC()
+	}
+	if !endTime.After(startTime) {
+		return fmt.Errorf("END_TIME must be after START_TIME")
+	}
+
+	result, err := repairPeriodIDs(ctx, featureUsageRepo, usageCounterRepo, sub, pauses, startTime, endTime, batchSize, dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to repair feature usage period ids: %w", err)
+	}
+
+	for _, mismatch := range result.Mismatched {
+		log.Infow("feature usage recorded under a stale period",
+			"event_id", mismatch.EventID,
+			"sub_line_item_id", mismatch.SubLineItemID,
+			"timestamp", mismatch.Timestamp,
+			"period_id", mismatch.PeriodID,
+			"expected_period_id", mismatch.Expected,
+		)
+	}
+	for _, eventID := range result.Unresolved {
+		log.Warnw("could not calculate period of feature usage", "event_id", eventID)
+	}
+
+	log.Infow("validated feature usage period ids",
+		"subscription_id", subscriptionID,
+		"dry_run", dryRun,
+		"checked", result.Checked,
+		"mismatched", len(result.Mismatched),
+		"repaired", result.Repaired,
+		"unresolved", len(result.Unresolved),
+	)
+	return nil
+}
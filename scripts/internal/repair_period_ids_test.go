@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/domain/events"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
+	"github.com/flexprice/flexprice/internal/testutil"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairPeriodIDs(t *testing.T) {
+	ctx := context.WithValue(context.Background(), types.CtxTenantID, types.DefaultTenantID)
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, "env_repair")
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	periodID := func(t time.Time) uint64 {
+		return uint64(t.UnixMilli())
+	}
+
+	// The current period was moved from March 1st - April 1st to March 15th - April 15th
+	sub := &subscription.Subscription{
+		ID:                 "sub_repair",
+		StartDate:          date(time.January, 1),
+		BillingAnchor:      date(time.January, 1),
+		CurrentPeriodStart: date(time.March, 15),
+		CurrentPeriodEnd:   date(time.April, 15),
+		BillingPeriod:      types.BILLING_PERIOD_MONTHLY,
+		BillingPeriodCount: 1,
+	}
+	pauses := []*subscription.SubscriptionPause{{
+		ID:                  "pause_repair",
+		SubscriptionID:      sub.ID,
+		PauseStart:          date(time.March, 25),
+		PauseEnd:            lo.ToPtr(date(time.March, 28)),
+		OriginalPeriodStart: date(time.March, 1),
+		OriginalPeriodEnd:   date(time.April, 1),
+	}}
+
+	featureUsageRepo := testutil.NewInMemoryFeatureUsageStore()
+	newUsage := func(id string, timestamp time.Time, periodStart time.Time) *events.FeatureUsage {
+		return &events.FeatureUsage{
+			Event: events.Event{
+				ID:                 id,
+				TenantID:           types.DefaultTenantID,
+				EnvironmentID:      "env_repair",
+				CustomerID:         "cust_repair",
+				ExternalCustomerID: "ext_cust_repair",
+				Timestamp:          timestamp,
+			},
+			SubscriptionID: sub.ID,
+			FeatureID:      "feat_repair",
+			SubLineItemID:  "subli_repair",
+			PeriodID:       periodID(periodStart),
+			QtyTotal:       decimal.NewFromInt(1),
+			Sign:           1,
+		}
+	}
+	subscriptionUsage := []*events.FeatureUsage{
+		newUsage("evt_february", date(time.February, 10), date(time.February, 1)),
+		newUsage("evt_stale", date(time.March, 20), date(time.March, 1)),
+		newUsage("evt_current", date(time.March, 21), date(time.March, 15)),
+		newUsage("evt_accrued", date(time.March, 26), date(time.March, 1)),
+		newUsage("evt_stale_after_pause", date(time.April, 2), date(time.April, 1)),
+	}
+	require.NoError(t, featureUsageRepo.BulkInsertProcessedEvents(ctx, subscriptionUsage))
+	usageCounterRepo := testutil.NewInMemoryUsageCounterStore()
+	require.NoError(t, usageCounterRepo.Add(ctx, subscriptionUsage))
+	counterValue := func(periodStart time.Time) int64 {
+		counter, err := usageCounterRepo.Get(ctx, "cust_repair", "feat_repair", periodID(periodStart))
+		require.NoError(t, err)
+		require.NotNil(t, counter)
+		return counter.Value.IntPart()
+	}
+	other := newUsage("evt_other_subscription", date(time.March, 20), date(time.March, 1))
+	other.SubscriptionID = "sub_other"
+	require.NoError(t, featureUsageRepo.BulkInsertProcessedEvents(ctx, []*events.FeatureUsage{other}))
+
+	storedPeriodIDs := func() map[string]uint64 {
+		periodIDs := make(map[string]uint64)
+		for _, id := range []string{"evt_february", "evt_stale", "evt_current", "evt_accrued", "evt_stale_after_pause", "evt_other_subscription"} {
+			usage, err := featureUsageRepo.Get(ctx, id)
+			require.NoError(t, err)
+			periodIDs[id] = usage.PeriodID
+		}
+		return periodIDs
+	}
+	before := storedPeriodIDs()
+	expectedMismatches := []PeriodIDMismatch{
+		{
+			EventID:       "evt_stale",
+			SubLineItemID: "subli_repair",
+			Timestamp:     date(time.March, 20),
+			PeriodID:      periodID(date(time.March, 1)),
+			Expected:      periodID(date(time.March, 15)),
+		},
+		{
+			EventID:       "evt_stale_after_pause",
+			SubLineItemID: "subli_repair",
+			Timestamp:     date(time.April, 2),
+			PeriodID:      periodID(date(time.April, 1)),
+			Expected:      periodID(date(time.March, 15)),
+		},
+	}
+
+	t.Run("dry run reports the mismatches", func(t *testing.T) {
+		// A batch size of two pages through the rows
+		result, err := repairPeriodIDs(ctx, featureUsageRepo, usageCounterRepo, sub, pauses, date(time.January, 1), date(time.May, 1), 2, true)
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Checked)
+		assert.Equal(t, expectedMismatches, result.Mismatched)
+		assert.Zero(t, result.Repaired)
+		assert.Empty(t, result.Unresolved)
+		assert.Equal(t, before, storedPeriodIDs())
+		assert.Equal(t, int64(2), counterValue(date(time.March, 1)))
+	})
+
+	t.Run("repairs the mismatched rows", func(t *testing.T) {
+		result, err := repairPeriodIDs(ctx, featureUsageRepo, usageCounterRepo, sub, pauses, date(time.January, 1), date(time.May, 1), 2, false)
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Checked)
+		assert.Equal(t, expectedMismatches, result.Mismatched)
+		assert.Equal(t, 2, result.Repaired)
+
+		after := storedPeriodIDs()
+		assert.Equal(t, periodID(date(time.March, 15)), after["evt_stale"])
+		assert.Equal(t, periodID(date(time.March, 15)), after["evt_stale_after_pause"])
+		for _, id := range []string{"evt_february", "evt_current", "evt_accrued", "evt_other_subscription"} {
+			assert.Equal(t, before[id], after[id], id)
+		}
+
+		// the repaired rows replace the stored ones instead of adding to the billed usage
+		usage, err := featureUsageRepo.GetFeatureUsageBySubscription(ctx, sub.ID, "ext_cust_repair", date(time.January, 1), date(time.May, 1))
+		require.NoError(t, err)
+		require.Contains(t, usage, "subli_repair")
+		assert.True(t, decimal.NewFromInt(5).Equal(usage["subli_repair"].SumTotal), usage["subli_repair"].SumTotal.String())
+
+		// the repaired usage is moved to the counter of its period
+		assert.Equal(t, int64(1), counterValue(date(time.March, 1)))
+		assert.Equal(t, int64(3), counterValue(date(time.March, 15)))
+		assert.Equal(t, int64(0), counterValue(date(time.April, 1)))
+	})
+
+	t.Run("nothing left to repair", func(t *testing.T) {
+		result, err := repairPeriodIDs(ctx, featureUsageRepo, usageCounterRepo, sub, pauses, date(time.January, 1), date(time.May, 1), 10, false)
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Checked)
+		assert.Empty(t, result.Mismatched)
+		assert.Zero(t, result.Repaired)
+		assert.Equal(t, int64(3), counterValue(date(time.March, 15)))
+	})
+}
@@ -177,7 +177,7 @@ func newReprocessEventsScript() (*ReprocessEventsScript, error) {
 	// Initialize repositories
 	eventRepo := chRepo.NewEventRepository(chStore, log)
 	processedEventRepo := chRepo.NewProcessedEventRepository(chStore, log)
-	customerRepo := entRepo.NewCustomerRepository(pgClient, log, cacheClient)
+	customerRepo := entRepo.NewCustomerRepository(pgClient, log, cacheClient, cfg.Customer.LookupKeyConflictPolicy)
 	meterRepo := entRepo.NewMeterRepository(pgClient, log, cacheClient)
 	priceRepo := entRepo.NewPriceRepository(pgClient, log, cacheClient)
 	featureRepo := entRepo.NewFeatureRepository(pgClient, log, cacheClient)
@@ -54,27 +54,29 @@ func GenerateNewAPIKey() error {
 	return nil
 }
 
-// AssignTenantToUser assigns a tenant to a user
+// AssignTenantToUser assigns a tenant to a user, it does nothing when the user already belongs to the tenant
 func AssignTenantToUser() error {
 	userID := os.Getenv("USER_ID")
 	tenantID := os.Getenv("TENANT_ID")
-	// Load configuration
-	cfg, err := config.NewConfig()
+
+	script, err := newOnboardingScript()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		log.Fatalf("Failed to initialize script: %v", err)
 		return err
 	}
 
-	// Create auth provider
-	authProvider := auth.NewProvider(cfg)
-
 	// Assign tenant to user
-	err = authProvider.AssignUserToTenant(context.Background(), userID, tenantID)
+	assigned, err := script.assignTenant(context.Background(), userID, tenantID)
 	if err != nil {
 		log.Fatalf("Failed to assign tenant to user: %v", err)
 		return err
 	}
 
+	if !assigned {
+		fmt.Printf("User %s is already assigned to tenant %s, nothing to do\n", userID, tenantID)
+		return nil
+	}
+
 	fmt.Printf("Successfully assigned tenant %s to user %s\n", tenantID, userID)
 	return nil
 }
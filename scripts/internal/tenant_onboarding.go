@@ -21,6 +21,24 @@ import (
 	"github.com/samber/lo"
 )
 
+// defaultEnvironments are the environments every onboarded tenant gets
+var defaultEnvironments = []struct {
+	envType types.EnvironmentType
+	name    string
+}{
+	{types.EnvironmentDevelopment, "Sandbox"},
+	{types.EnvironmentProduction, "Production"},
+}
+
+// onboardingResult is what an onboarding run did, records that already existed are reused
+type onboardingResult struct {
+	Tenant              *tenant.Tenant
+	User                *user.User
+	TenantCreated       bool
+	UserCreated         bool
+	CreatedEnvironments []*environment.Environment
+}
+
 type onboardingScript struct {
 	cfg             *config.Configuration
 	log             *logger.Logger
@@ -96,15 +114,42 @@ func (s *onboardingScript) createTenant(ctx context.Context, name string) (*tena
 	return t, nil
 }
 
-func (s *onboardingScript) createUser(ctx context.Context, email, tenantID string) (*user.User, error) {
+// getOrCreateTenant returns the tenant with the given name, creating it when there is none.
+// The returned flag tells whether the tenant was created.
+func (s *onboardingScript) getOrCreateTenant(ctx context.Context, name string) (*tenant.Tenant, bool, error) {
+	tenants, err := s.tenantRepo.List(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	for _, t := range tenants {
+		if t.Name == name {
+			s.log.Infow("tenant already exists", "id", t.ID, "name", t.Name)
+			return t, false, nil
+		}
+	}
+
+	t, err := s.createTenant(ctx, name)
+	if err != nil {
+		return nil, false, err
+	}
+	return t, true, nil
+}
+
+// createUser creates the user of the tenant, an existing user with the same email is reused
+// as long as it belongs to the tenant. The returned flag tells whether the user was created.
+func (s *onboardingScript) createUser(ctx context.Context, email, tenantID string) (*user.User, bool, error) {
 	password := os.Getenv("USER_PASSWORD")
 	u := user.NewUser(email, tenantID)
 
-	// Check if user already exists in MongoDB
+	// Check if user already exists in the DB
 	existingUser, err := s.userRepo.GetByEmail(ctx, u.Email)
 	if err == nil && existingUser != nil {
+		if existingUser.TenantID != tenantID {
+			return nil, false, fmt.Errorf("user %s already belongs to tenant %s", existingUser.Email, existingUser.TenantID)
+		}
 		s.log.Infow("user already exists", "id", existingUser.ID, "email", existingUser.Email, "tenant_id", existingUser.TenantID)
-		return existingUser, nil
+		return existingUser, false, nil
 	}
 
 	// Register the user with Supabase only if UserID is empty
@@ -118,18 +163,18 @@ func (s *onboardingScript) createUser(ctx context.Context, email, tenantID strin
 		},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user with admin API: %w", err)
+		return nil, false, fmt.Errorf("failed to create user with admin API: %w", err)
 	}
 	s.log.Infof("Supabase registration response : %+v", supabaseUser)
 
 	u.ID = supabaseUser.ID // Set the UserID from the Supabase response
 
 	if err := s.userRepo.Create(ctx, u); err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+		return nil, false, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	s.log.Infow("created user", "id", u.ID, "email", u.Email, "tenant_id", u.TenantID)
-	return u, nil
+	return u, true, nil
 }
 
 func (s *onboardingScript) createEnvironment(ctx context.Context, name string, envType types.EnvironmentType, tenantID string) (*environment.Environment, error) {
@@ -154,6 +199,86 @@ func (s *onboardingScript) createEnvironment(ctx context.Context, name string, e
 	return e, nil
 }
 
+// createMissingEnvironments creates the default environments the tenant doesn't have yet
+func (s *onboardingScript) createMissingEnvironments(ctx context.Context, tenantID string) ([]*environment.Environment, error) {
+	tenantCtx := context.WithValue(ctx, types.CtxTenantID, tenantID)
+	existing, err := s.environmentRepo.List(tenantCtx, types.Filter{Limit: 100})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	existingTypes := make(map[types.EnvironmentType]bool, len(existing))
+	for _, e := range existing {
+		if e.TenantID == tenantID {
+			existingTypes[e.Type] = true
+		}
+	}
+
+	var created []*environment.Environment
+	for _, env := range defaultEnvironments {
+		if existingTypes[env.envType] {
+			s.log.Infow("environment already exists", "type", env.envType, "tenant_id", tenantID)
+			continue
+		}
+
+		e, err := s.createEnvironment(ctx, env.name, env.envType, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create environment %s: %w", env.envType, err)
+		}
+		created = append(created, e)
+	}
+
+	return created, nil
+}
+
+// onboard sets up the tenant, its user and its default environments. It can be re-run safely,
+// whatever already exists is reused instead of being created again.
+func (s *onboardingScript) onboard(ctx context.Context, email, tenantName string) (*onboardingResult, error) {
+	t, tenantCreated, err := s.getOrCreateTenant(ctx, tenantName)
+	if err != nil {
+		return nil, err
+	}
+
+	u, userCreated, err := s.createUser(ctx, email, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	envs, err := s.createMissingEnvironments(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &onboardingResult{
+		Tenant:              t,
+		User:                u,
+		TenantCreated:       tenantCreated,
+		UserCreated:         userCreated,
+		CreatedEnvironments: envs,
+	}, nil
+}
+
+// assignTenant assigns the tenant to the user unless the user already belongs to it.
+// The returned flag tells whether the assignment was made.
+func (s *onboardingScript) assignTenant(ctx context.Context, userID, tenantID string) (bool, error) {
+	if _, err := s.tenantRepo.GetByID(ctx, tenantID); err != nil {
+		return false, fmt.Errorf("failed to get tenant %s: %w", tenantID, err)
+	}
+
+	tenantCtx := context.WithValue(ctx, types.CtxTenantID, tenantID)
+	if u, err := s.userRepo.GetByID(tenantCtx, userID); err == nil && u.TenantID == tenantID {
+		s.log.Infow("user already assigned to tenant", "user_id", userID, "tenant_id", tenantID)
+		return false, nil
+	}
+
+	if err := s.authProvider.AssignUserToTenant(ctx, userID, tenantID); err != nil {
+		return false, fmt.Errorf("failed to assign tenant to user: %w", err)
+	}
+
+	s.log.Infow("assigned tenant to user", "user_id", userID, "tenant_id", tenantID)
+	return true, nil
+}
+
 func OnboardNewTenant() error {
 	email := os.Getenv("USER_EMAIL")
 	tenantName := os.Getenv("TENANT_NAME")
@@ -172,42 +297,27 @@ func OnboardNewTenant() error {
 		log.Fatalf("Failed to initialize script: %v", err)
 	}
 
-	ctx := context.Background()
-
-	// Create tenant
-	t, err := script.createTenant(ctx, tenantName)
+	result, err := script.onboard(context.Background(), email, tenantName)
 	if err != nil {
-		log.Fatalf("Failed to create tenant: %v", err)
+		log.Fatalf("Failed to onboard tenant: %v", err)
 	}
 
-	// Create user
-	u, err := script.createUser(ctx, email, t.ID)
-	if err != nil {
-		log.Fatalf("Failed to create user: %v", err)
+	if !result.TenantCreated && !result.UserCreated && len(result.CreatedEnvironments) == 0 {
+		fmt.Printf("Tenant %s with user %s is already onboarded, nothing to do\n", tenantName, email)
+	} else {
+		fmt.Printf("Successfully onboarded tenant %s with user %s\n", tenantName, email)
 	}
-
-	// Create default environments (development, staging, production)
-	envTypes := []types.EnvironmentType{
-		types.EnvironmentDevelopment,
-		types.EnvironmentProduction,
+	if !result.TenantCreated {
+		fmt.Printf("Tenant already existed\n")
 	}
-
-	envNameMap := map[types.EnvironmentType]string{
-		types.EnvironmentDevelopment: "Sandbox",
-		types.EnvironmentProduction:  "Production",
+	if !result.UserCreated {
+		fmt.Printf("User already existed\n")
 	}
-
-	for _, envType := range envTypes {
-		env, err := script.createEnvironment(ctx, envNameMap[envType], envType, t.ID)
-		if err != nil {
-			log.Fatalf("Failed to create environment %s: %v", envType, err)
-		}
-		log.Debugf("Created environment %s", env.ID)
+	for _, env := range result.CreatedEnvironments {
+		fmt.Printf("Created environment %s (%s)\n", env.Name, env.ID)
 	}
-
-	fmt.Printf("Successfully onboarded tenant %s with user %s\n", tenantName, email)
-	fmt.Printf("Tenant ID: %s\n", t.ID)
-	fmt.Printf("User ID: %s\n", u.ID)
+	fmt.Printf("Tenant ID: %s\n", result.Tenant.ID)
+	fmt.Printf("User ID: %s\n", result.User.ID)
 
 	return nil
 }
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/auth"
+	"github.com/flexprice/flexprice/internal/domain/environment"
+	"github.com/flexprice/flexprice/internal/domain/tenant"
+	"github.com/flexprice/flexprice/internal/domain/user"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/testutil"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAuthProvider counts the tenant assignments made through it
+type recordingAuthProvider struct {
+	auth.Provider
+	assignments []string
+}
+
+func (p *recordingAuthProvider) AssignUserToTenant(ctx context.Context, userID string, tenantID string) error {
+	p.assignments = append(p.assignments, userID+":"+tenantID)
+	return nil
+}
+
+func newTestOnboardingScript() (*onboardingScript, *recordingAuthProvider) {
+	provider := &recordingAuthProvider{}
+	return &onboardingScript{
+		log:             logger.GetLogger(),
+		tenantRepo:      testutil.NewInMemoryTenantStore(),
+		userRepo:        testutil.NewInMemoryUserStore(),
+		environmentRepo: testutil.NewInMemoryEnvironmentStore(),
+		authProvider:    provider,
+	}, provider
+}
+
+func TestOnboardExistingTenant(t *testing.T) {
+	ctx := context.Background()
+	script, _ := newTestOnboardingScript()
+
+	existingTenant := &tenant.Tenant{ID: "tenant_acme", Name: "Acme", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, script.tenantRepo.Create(ctx, existingTenant))
+	require.NoError(t, script.userRepo.Create(ctx, user.NewUser("owner@acme.com", existingTenant.ID)))
+	require.NoError(t, script.environmentRepo.Create(ctx, &environment.Environment{
+		ID:        "env_sandbox",
+		Name:      "Sandbox",
+		Type:      types.EnvironmentDevelopment,
+		BaseModel: types.BaseModel{TenantID: existingTenant.ID},
+	}))
+
+	// The first run only adds the missing production environment
+	result, err := script.onboard(ctx, "owner@acme.com", "Acme")
+	require.NoError(t, err)
+	assert.Equal(t, existingTenant.ID, result.Tenant.ID)
+	assert.False(t, result.TenantCreated)
+	assert.False(t, result.UserCreated)
+	require.Len(t, result.CreatedEnvironments, 1)
+	assert.Equal(t, types.EnvironmentProduction, result.CreatedEnvironments[0].Type)
+
+	// Re-running creates nothing
+	result, err = script.onboard(ctx, "owner@acme.com", "Acme")
+	require.NoError(t, err)
+	assert.False(t, result.TenantCreated)
+	assert.False(t, result.UserCreated)
+	assert.Empty(t, result.CreatedEnvironments)
+
+	tenants, err := script.tenantRepo.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tenants, 1)
+
+	envs, err := script.environmentRepo.List(ctx, types.Filter{Limit: 100})
+	require.NoError(t, err)
+	assert.Len(t, envs, 2)
+}
+
+func TestOnboardUserOfAnotherTenant(t *testing.T) {
+	ctx := context.Background()
+	script, _ := newTestOnboardingScript()
+
+	require.NoError(t, script.tenantRepo.Create(ctx, &tenant.Tenant{ID: "tenant_acme", Name: "Acme"}))
+	require.NoError(t, script.userRepo.Create(ctx, user.NewUser("owner@other.com", "tenant_other")))
+
+	_, err := script.onboard(ctx, "owner@other.com", "Acme")
+	assert.Error(t, err)
+}
+
+func TestAssignTenant(t *testing.T) {
+	ctx := context.Background()
+	script, provider := newTestOnboardingScript()
+
+	require.NoError(t, script.tenantRepo.Create(ctx, &tenant.Tenant{ID: "tenant_acme", Name: "Acme"}))
+	require.NoError(t, script.tenantRepo.Create(ctx, &tenant.Tenant{ID: "tenant_other", Name: "Other"}))
+	u := user.NewUser("owner@acme.com", "tenant_acme")
+	require.NoError(t, script.userRepo.Create(ctx, u))
+
+	t.Run("already assigned", func(t *testing.T) {
+		assigned, err := script.assignTenant(ctx, u.ID, "tenant_acme")
+		require.NoError(t, err)
+		assert.False(t, assigned)
+		assert.Empty(t, provider.assignments)
+	})
+
+	t.Run("other tenant", func(t *testing.T) {
+		assigned, err := script.assignTenant(ctx, u.ID, "tenant_other")
+		require.NoError(t, err)
+		assert.True(t, assigned)
+		assert.Equal(t, []string{u.ID + ":tenant_other"}, provider.assignments)
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		_, err := script.assignTenant(ctx, u.ID, "tenant_missing")
+		assert.Error(t, err)
+	})
+}
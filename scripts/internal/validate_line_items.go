@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/cache"
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/domain/subscription"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/postgres"
+	entRepo "github.com/flexprice/flexprice/internal/repository/ent"
+	"github.com/flexprice/flexprice/internal/sentry"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// LineItemOverlap is a pair of published usage line items of a subscription that meter the same
+// meter over overlapping dates. Events in the overlap match both line items and are billed twice.
+type LineItemOverlap struct {
+	SubscriptionID  string
+	CustomerID      string
+	MeterID         string
+	LineItemID      string
+	OtherLineItemID string
+	// OverlapStart and OverlapEnd bound the overlap, OverlapEnd is zero when both line items are open ended
+	OverlapStart time.Time
+	OverlapEnd   time.Time
+}
+
+// findOverlappingLineItems returns the overlapping pairs of published usage line items of the same
+// subscription and meter, ordered by subscription, meter and line item. Line items are active from
+// their start date until their end date, one starting when the other ends doesn't overlap it.
+func findOverlappingLineItems(lineItems []*subscription.SubscriptionLineItem) []LineItemOverlap {
+	type key struct{ subscriptionID, meterID string }
+	grouped := make(map[key][]*subscription.SubscriptionLineItem)
+	for _, li := range lineItems {
+		if li.Status != types.StatusPublished || !li.IsUsage() || li.StartDate.IsZero() {
+			continue
+		}
+		k := key{li.SubscriptionID, li.MeterID}
+		grouped[k] = append(grouped[k], li)
+	}
+
+	overlaps := make([]LineItemOverlap, 0)
+	for _, items := range grouped {
+		sort.SliceStable(items, func(i, j int) bool {
+			if !items[i].StartDate.Equal(items[j].StartDate) {
+				return items[i].StartDate.Before(items[j].StartDate)
+			}
+			return items[i].ID < items[j].ID
+		})
+
+		for i, a := range items {
+			for _, b := range items[i+1:] {
+				// b starts no earlier than a, so they overlap when b starts before a ends
+				if !a.EndDate.IsZero() && !b.StartDate.Before(a.EndDate) {
+					break
+				}
+
+				overlapEnd := a.EndDate
+				if overlapEnd.IsZero() || (!b.EndDate.IsZero() && b.EndDate.Before(overlapEnd)) {
+					overlapEnd = b.EndDate
+				}
+				if !overlapEnd.IsZero() && !b.StartDate.Before(overlapEnd) {
+					continue
+				}
+
+				overlaps = append(overlaps, LineItemOverlap{
+					SubscriptionID:  a.SubscriptionID,
+					CustomerID:      a.CustomerID,
+					MeterID:         a.MeterID,
+					LineItemID:      a.ID,
+					OtherLineItemID: b.ID,
+					OverlapStart:    b.StartDate,
+					OverlapEnd:      overlapEnd,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(overlaps, func(i, j int) bool {
+		if overlaps[i].SubscriptionID != overlaps[j].SubscriptionID {
+			return overlaps[i].SubscriptionID < overlaps[j].SubscriptionID
+		}
+		if overlaps[i].MeterID != overlaps[j].MeterID {
+			return overlaps[i].MeterID < overlaps[j].MeterID
+		}
+		if overlaps[i].LineItemID != overlaps[j].LineItemID {
+			return overlaps[i].LineItemID < overlaps[j].LineItemID
+		}
+		return overlaps[i].OtherLineItemID < overlaps[j].OtherLineItemID
+	})
+	return overlaps
+}
+
+// FindOverlappingLineItems reports the subscriptions of a tenant environment with two published
+// usage line items metering the same meter over overlapping dates, so the line items can be fixed
+// before their usage is billed twice.
+//
+// Environment variables:
+//   - TENANT_ID: tenant to check
+//   - ENVIRONMENT_ID: environment to check
+func FindOverlappingLineItems() error {
+	tenantID := os.Getenv("TENANT_ID")
+	environmentID := os.Getenv("ENVIRONMENT_ID")
+	if tenantID == "" || environmentID == "" {
+		return fmt.Errorf("TENANT_ID and ENVIRONMENT_ID are required")
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log, err := logger.NewLogger(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	entClient, err := postgres.NewEntClients(cfg, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	client := postgres.NewClient(entClient, log, sentry.NewSentryService(cfg, log))
+
+	lineItemRepo := entRepo.NewSubscriptionLineItemRepository(client, log, cache.NewInMemoryCache())
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, types.CtxTenantID, tenantID)
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, environmentID)
+
+	lineItems, err := lineItemRepo.List(ctx, types.NewNoLimitSubscriptionLineItemFilter())
+	if err != nil {
+		return fmt.Errorf("failed to list subscription line items: %w", err)
+	}
+
+	overlaps := findOverlappingLineItems(lineItems)
+	for _, overlap := range overlaps {
+		log.Warnw("subscription line items overlap",
+			"subscription_id", overlap.SubscriptionID,
+			"customer_id", overlap.CustomerID,
+			"meter_id", overlap.MeterID,
+			"line_item_id", overlap.LineItemID,
+			"other_line_item_id", overlap.OtherLineItemID,
+			"overlap_start", overlap.OverlapStart,
+			"overlap_end", overlap.OverlapEnd,
+		)
+	}
+
+	log.Infow("checked subscription line items",
+		"tenant_id", tenantID,
+		"environment_id", environmentID,
+		"line_items", len(lineItems),
+		"overlaps", len(overlaps),
+	)
+	return nil
+}
@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flexprice/flexprice/internal/domain/subscription"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindOverlappingLineItems(t *testing.T) {
+	published := types.BaseModel{Status: types.StatusPublished}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newLineItem := func(id, subscriptionID, meterID string, startDate, endDate time.Time) *subscription.SubscriptionLineItem {
+		return &subscription.SubscriptionLineItem{
+			ID:             id,
+			SubscriptionID: subscriptionID,
+			CustomerID:     "cust_" + subscriptionID,
+			MeterID:        meterID,
+			PriceType:      types.PRICE_TYPE_USAGE,
+			StartDate:      startDate,
+			EndDate:        endDate,
+			BaseModel:      published,
+		}
+	}
+
+	lineItems := []*subscription.SubscriptionLineItem{
+		// Overlapping pair, the second starts before the first ends
+		newLineItem("li_api_old", "sub_overlap", "meter_api", start, start.AddDate(0, 2, 0)),
+		newLineItem("li_api_new", "sub_overlap", "meter_api", start.AddDate(0, 1, 0), time.Time{}),
+		// Replaced line item, the second starts when the first ends
+		newLineItem("li_storage_old", "sub_overlap", "meter_storage", start, start.AddDate(0, 1, 0)),
+		newLineItem("li_storage_new", "sub_overlap", "meter_storage", start.AddDate(0, 1, 0), time.Time{}),
+		// Same meter on different subscriptions
+		newLineItem("li_other_sub", "sub_other", "meter_api", start, time.Time{}),
+		// Archived line items don't count
+		{ID: "li_api_archived", SubscriptionID: "sub_other", MeterID: "meter_api", PriceType: types.PRICE_TYPE_USAGE,
+			StartDate: start, BaseModel: types.BaseModel{Status: types.StatusArchived}},
+		// Fixed line items don't meter usage
+		{ID: "li_fixed", SubscriptionID: "sub_other", MeterID: "meter_api", PriceType: types.PRICE_TYPE_FIXED,
+			StartDate: start, BaseModel: published},
+	}
+
+	overlaps := findOverlappingLineItems(lineItems)
+	assert.Equal(t, []LineItemOverlap{
+		{
+			SubscriptionID:  "sub_overlap",
+			CustomerID:      "cust_sub_overlap",
+			MeterID:         "meter_api",
+			LineItemID:      "li_api_old",
+			OtherLineItemID: "li_api_new",
+			OverlapStart:    start.AddDate(0, 1, 0),
+			OverlapEnd:      start.AddDate(0, 2, 0),
+		},
+	}, overlaps)
+
+	t.Run("open ended line items", func(t *testing.T) {
+		overlaps := findOverlappingLineItems([]*subscription.SubscriptionLineItem{
+			newLineItem("li_a", "sub_open", "meter_api", start, time.Time{}),
+			newLineItem("li_b", "sub_open", "meter_api", start.AddDate(0, 0, 7), time.Time{}),
+		})
+		assert.Len(t, overlaps, 1)
+		assert.Equal(t, start.AddDate(0, 0, 7), overlaps[0].OverlapStart)
+		assert.True(t, overlaps[0].OverlapEnd.IsZero())
+	})
+}
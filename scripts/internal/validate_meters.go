@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/flexprice/flexprice/internal/cache"
+	"github.com/flexprice/flexprice/internal/config"
+	"github.com/flexprice/flexprice/internal/domain/feature"
+	"github.com/flexprice/flexprice/internal/domain/meter"
+	"github.com/flexprice/flexprice/internal/domain/price"
+	"github.com/flexprice/flexprice/internal/logger"
+	"github.com/flexprice/flexprice/internal/postgres"
+	entRepo "github.com/flexprice/flexprice/internal/repository/ent"
+	"github.com/flexprice/flexprice/internal/sentry"
+	"github.com/flexprice/flexprice/internal/types"
+)
+
+// MeterConfigIssue is a published meter whose events can't be billed. Usage of a meter without a
+// feature is dropped when tracking feature usage, and usage of a meter without a usage price is
+// never charged.
+type MeterConfigIssue struct {
+	MeterID        string
+	MeterName      string
+	EventName      string
+	MissingFeature bool
+	MissingPrice   bool
+}
+
+// findMeterConfigIssues returns the published meters lacking a published metered feature or any
+// published usage price, ordered by meter name
+func findMeterConfigIssues(meters []*meter.Meter, features []*feature.Feature, prices []*price.Price) []MeterConfigIssue {
+	featureMeters := make(map[string]bool)
+	for _, f := range features {
+		if f.Status == types.StatusPublished && f.Type == types.FeatureTypeMetered && f.MeterID != "" {
+			featureMeters[f.MeterID] = true
+		}
+	}
+
+	priceMeters := make(map[string]bool)
+	for _, p := range prices {
+		if p.Status == types.StatusPublished && p.Type == types.PRICE_TYPE_USAGE && p.MeterID != "" {
+			priceMeters[p.MeterID] = true
+		}
+	}
+
+	issues := make([]MeterConfigIssue, 0)
+	for _, m := range meters {
+		if m.Status != types.StatusPublished {
+			continue
+		}
+
+		issue := MeterConfigIssue{
+			MeterID:        m.ID,
+			MeterName:      m.Name,
+			EventName:      m.EventName,
+			MissingFeature: !featureMeters[m.ID],
+			MissingPrice:   !priceMeters[m.ID],
+		}
+		if issue.MissingFeature || issue.MissingPrice {
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].MeterName != issues[j].MeterName {
+			return issues[i].MeterName < issues[j].MeterName
+		}
+		return issues[i].MeterID < issues[j].MeterID
+	})
+	return issues
+}
+
+// ValidateMeters reports the meters of a tenant environment that lack a feature or a usage price,
+// so the configuration can be fixed before usage is silently dropped.
+//
+// Environment variables:
+//   - TENANT_ID: tenant to validate
+//   - ENVIRONMENT_ID: environment to validate
+func ValidateMeters() error {
+	tenantID := os.Getenv("TENANT_ID")
+	environmentID := os.Getenv("ENVIRONMENT_ID")
+	if tenantID == "" || environmentID == "" {
+		return fmt.Errorf("TENANT_ID and ENVIRONMENT_ID are required")
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log, err := logger.NewLogger(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	entClient, err := postgres.NewEntClients(cfg, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	client := postgres.NewClient(entClient, log, sentry.NewSentryService(cfg, log))
+	cacheClient := cache.NewInMemoryCache()
+
+	meterRepo := entRepo.NewMeterRepository(client, log, cacheClient)
+	featureRepo := entRepo.NewFeatureRepository(client, log, cacheClient)
+	priceRepo := entRepo.NewPriceRepository(client, log, cacheClient)
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, types.CtxTenantID, tenantID)
+	ctx = context.WithValue(ctx, types.CtxEnvironmentID, environmentID)
+
+	meters, err := meterRepo.ListAll(ctx, types.NewNoLimitMeterFilter())
+	if err != nil {
+		return fmt.Errorf("failed to list meters: %w", err)
+	}
+
+	features, err := featureRepo.ListAll(ctx, types.NewNoLimitFeatureFilter())
+	if err != nil {
+		return fmt.Errorf("failed to list features: %w", err)
+	}
+
+	prices, err := priceRepo.ListAll(ctx, types.NewNoLimitPriceFilter())
+	if err != nil {
+		return fmt.Errorf("failed to list prices: %w", err)
+	}
+
+	issues := findMeterConfigIssues(meters, features, prices)
+	for _, issue := range issues {
+		log.Warnw("meter is not fully configured",
+			"meter_id", issue.MeterID,
+			"meter_name", issue.MeterName,
+			"event_name", issue.EventName,
+			"missing_feature", issue.MissingFeature,
+			"missing_price", issue.MissingPrice,
+		)
+	}
+
+	log.Infow("validated meters",
+		"tenant_id", tenantID,
+		"environment_id", environmentID,
+		"meters", len(meters),
+		"meters_with_issues", len(issues),
+	)
+	return nil
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/flexprice/flexprice/internal/domain/feature"
+	"github.com/flexprice/flexprice/internal/domain/meter"
+	"github.com/flexprice/flexprice/internal/domain/price"
+	"github.com/flexprice/flexprice/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindMeterConfigIssues(t *testing.T) {
+	published := types.BaseModel{Status: types.StatusPublished}
+	newMeter := func(id, name string) *meter.Meter {
+		return &meter.Meter{ID: id, Name: name, EventName: id, BaseModel: published}
+	}
+
+	meters := []*meter.Meter{
+		newMeter("meter_ok", "API Calls"),
+		newMeter("meter_no_feature", "Storage"),
+		newMeter("meter_no_price", "Bandwidth"),
+		newMeter("meter_unused", "Tokens"),
+		{ID: "meter_deleted", Name: "Deleted", BaseModel: types.BaseModel{Status: types.StatusDeleted}},
+	}
+	features := []*feature.Feature{
+		{ID: "feat_api_calls", Type: types.FeatureTypeMetered, MeterID: "meter_ok", BaseModel: published},
+		{ID: "feat_bandwidth", Type: types.FeatureTypeMetered, MeterID: "meter_no_price", BaseModel: published},
+		// Archived features don't count
+		{ID: "feat_storage", Type: types.FeatureTypeMetered, MeterID: "meter_no_feature", BaseModel: types.BaseModel{Status: types.StatusArchived}},
+	}
+	prices := []*price.Price{
+		{ID: "price_api_calls", Type: types.PRICE_TYPE_USAGE, MeterID: "meter_ok", BaseModel: published},
+		{ID: "price_storage", Type: types.PRICE_TYPE_USAGE, MeterID: "meter_no_feature", BaseModel: published},
+		// Fixed prices don't bill usage
+		{ID: "price_bandwidth", Type: types.PRICE_TYPE_FIXED, MeterID: "meter_no_price", BaseModel: published},
+	}
+
+	issues := findMeterConfigIssues(meters, features, prices)
+	assert.Equal(t, []MeterConfigIssue{
+		{MeterID: "meter_no_price", MeterName: "Bandwidth", EventName: "meter_no_price", MissingPrice: true},
+		{MeterID: "meter_no_feature", MeterName: "Storage", EventName: "meter_no_feature", MissingFeature: true},
+		{MeterID: "meter_unused", MeterName: "Tokens", EventName: "meter_unused", MissingFeature: true, MissingPrice: true},
+	}, issues)
+
+	t.Run("no issues when fully configured", func(t *testing.T) {
+		assert.Empty(t, findMeterConfigIssues(meters[:1], features, prices))
+	})
+}
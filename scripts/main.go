@@ -63,6 +63,11 @@ var commands = []Command{
 		Description: "Import pricing",
 		Run:         internal.ImportPricing,
 	},
+	{
+		Name:        "import-meters",
+		Description: "Create and update meters from a JSON or CSV spec",
+		Run:         internal.ImportMeters,
+	},
 	{
 		Name:        "sync-plan-prices",
 		Description: "Synchronize plan prices to all active subscriptions",
@@ -113,6 +118,41 @@ var commands = []Command{
 		Description: "Generate credit usage report for customers in a tenant/environment",
 		Run:         internal.GenerateCreditUsageReport,
 	},
+	{
+		Name:        "kafka-seek-offsets",
+		Description: "Seek a consumer group's offsets on a topic to a timestamp to replay messages",
+		Run:         internal.SeekKafkaOffsets,
+	},
+	{
+		Name:        "validate-meters",
+		Description: "Report meters that lack a feature or a usage price",
+		Run:         internal.ValidateMeters,
+	},
+	{
+		Name:        "find-overlapping-line-items",
+		Description: "Report subscriptions with usage line items of the same meter over overlapping dates",
+		Run:         internal.FindOverlappingLineItems,
+	},
+	{
+		Name:        "backfill-usage-customers",
+		Description: "Resolve and persist the customer of feature usage recorded without one",
+		Run:         internal.BackfillUsageCustomers,
+	},
+	{
+		Name:        "repair-period-ids",
+		Description: "Validate and repair the period id of a subscription's feature usage after its periods were edited",
+		Run:         internal.RepairPeriodIDs,
+	},
+	{
+		Name:        "recompute-period-costs",
+		Description: "Recompute a customer's invoiced usage costs under the current prices after a retroactive price fix",
+		Run:         internal.RecomputePeriodCosts,
+	},
+	{
+		Name:        "apply-property-indexes",
+		Description: "Add and drop the ClickHouse skip indexes of the properties meters index",
+		Run:         internal.ApplyPropertyIndexes,
+	},
 }
 
 // runBulkReprocessEventsCommand wraps the bulk reprocess events with command line parameters